@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,18 +17,37 @@ import (
 const (
 	configFile = "config.yaml"
 	envKey     = "GOMAIL_ENV" // env variable name to get deployment environment
+
+	// defaultQueueWorkers is used when queue.workers is left out. An
+	// explicit 0 disables the workers.
+	defaultQueueWorkers = 4
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Env      string         `yaml:"env" json:"env"`
-	Server   ServerConfig   `yaml:"server" json:"server"`
-	MongoDB  MongoDBConfig  `yaml:"mongodb" json:"mongodb"`
-	SMTP     SMTPConfig     `yaml:"smtp" json:"smtp"`
-	JWT      JWTConfig      `yaml:"jwt" json:"jwt"`
-	LogLevel string         `yaml:"logLevel" json:"logLevel"`
-	Cors     CorsConfig     `yaml:"cors" json:"cors"`
-	Services ServiceConfigs `yaml:"services" json:"services"`
+	Env         string            `yaml:"env" json:"env"`
+	Server      ServerConfig      `yaml:"server" json:"server"`
+	MongoDB     MongoDBConfig     `yaml:"mongodb" json:"mongodb"`
+	SMTP        SMTPConfig        `yaml:"smtp" json:"smtp"`
+	JWT         JWTConfig         `yaml:"jwt" json:"jwt"`
+	LogLevel    string            `yaml:"logLevel" json:"logLevel"`
+	Cors        CorsConfig        `yaml:"cors" json:"cors"`
+	Services    ServiceConfigs    `yaml:"services" json:"services"`
+	Queue       QueueConfig       `yaml:"queue" json:"queue"`
+	Markdown    MarkdownConfig    `yaml:"markdown" json:"markdown"`
+	HTML        HTMLConfig        `yaml:"html" json:"html"`
+	Fetch       FetchConfig       `yaml:"fetch" json:"fetch"`
+	Attachments AttachmentConfig  `yaml:"attachments" json:"attachments"`
+	Scan        ScanConfig        `yaml:"scan" json:"scan"`
+	Idempotency IdempotencyConfig `yaml:"idempotency" json:"idempotency"`
+	Dedup       DedupConfig       `yaml:"dedup" json:"dedup"`
+	Suppression SuppressionConfig `yaml:"suppression" json:"suppression"`
+	Bounce      BounceConfig      `yaml:"bounce" json:"bounce"`
+	Inbound     InboundConfig     `yaml:"inbound" json:"inbound"`
+	Submission  SubmissionConfig  `yaml:"submission" json:"submission"`
+	Tracking    TrackingConfig    `yaml:"tracking" json:"tracking"`
+	Webhooks    WebhooksConfig    `yaml:"webhooks" json:"webhooks"`
+	Merge       MergeConfig       `yaml:"merge" json:"merge"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -36,6 +56,14 @@ type ServerConfig struct {
 	PortGRPC     string        `yaml:"portGRPC" json:"portGRPC"`
 	ReadTimeout  time.Duration `yaml:"readTimeout" json:"readTimeout"`
 	WriteTimeout time.Duration `yaml:"writeTimeout" json:"writeTimeout"`
+
+	// PublicURL is the address recipients reach the server at, used for
+	// links in emails. It defaults to http://localhost:<port>.
+	PublicURL string `yaml:"publicURL" json:"publicURL"`
+
+	// SigningSecret signs the tokens of links in emails. It defaults to
+	// the JWT secret.
+	SigningSecret string `yaml:"signingSecret" json:"signingSecret"`
 }
 
 // MongoDBConfig holds MongoDB connection details
@@ -69,6 +97,230 @@ type JWTConfig struct {
 	RevokedTokensTTL    time.Duration `yaml:"revokedTokensTTL" json:"revokedTokensTTL"`
 }
 
+// QueueConfig holds configuration for the persistent outbound queue
+type QueueConfig struct {
+	Workers           int           `yaml:"workers" json:"workers"`
+	PollInterval      time.Duration `yaml:"pollInterval" json:"pollInterval"`
+	LeaseDuration     time.Duration `yaml:"leaseDuration" json:"leaseDuration"`
+	MaxAttempts       int           `yaml:"maxAttempts" json:"maxAttempts"`
+	RetryBackoff      time.Duration `yaml:"retryBackoff" json:"retryBackoff"`
+	MaxBackoff        time.Duration `yaml:"maxBackoff" json:"maxBackoff"`
+	SchedulerInterval time.Duration `yaml:"schedulerInterval" json:"schedulerInterval"`
+}
+
+// MarkdownConfig holds configuration for rendering Markdown email bodies
+type MarkdownConfig struct {
+	// LayoutFile is an html/template file the rendered HTML is embedded in.
+	// The built-in layout is used when empty.
+	LayoutFile string `yaml:"layoutFile" json:"layoutFile"`
+}
+
+// HTMLConfig holds the defaults of the HTML email pipeline. Requests can
+// switch each step on or off.
+type HTMLConfig struct {
+	InlineCSS    bool `yaml:"inlineCSS" json:"inlineCSS"`
+	GenerateText bool `yaml:"generateText" json:"generateText"`
+}
+
+// FetchConfig holds the limits for fetching attachments referenced by URL.
+// Zero values use the defaults of the fetcher.
+type FetchConfig struct {
+	Timeout      time.Duration `yaml:"timeout" json:"timeout"`
+	MaxSize      int64         `yaml:"maxSize" json:"maxSize"`
+	MaxRedirects int           `yaml:"maxRedirects" json:"maxRedirects"`
+	ContentTypes []string      `yaml:"contentTypes" json:"contentTypes"`
+	AllowPrivate bool          `yaml:"allowPrivate" json:"allowPrivate"` // development only
+}
+
+// AttachmentConfig holds the size limits of attachments in bytes, counted
+// before base64 encoding. Zero disables a limit.
+type AttachmentConfig struct {
+	MaxFileSize    int64 `yaml:"maxFileSize" json:"maxFileSize"`
+	MaxMessageSize int64 `yaml:"maxMessageSize" json:"maxMessageSize"`
+
+	// StoreTTL is how long uploads to the attachment store are kept unless
+	// the upload asks otherwise. Zero keeps them until deleted.
+	StoreTTL time.Duration `yaml:"storeTTL" json:"storeTTL"`
+
+	// LinkThreshold is the total size above which attachments are stored
+	// and replaced with download links in the body. Zero disables links.
+	LinkThreshold int64         `yaml:"linkThreshold" json:"linkThreshold"`
+	LinkTTL       time.Duration `yaml:"linkTTL" json:"linkTTL"`
+}
+
+// ScanConfig holds the clamd daemon attachments are scanned with before
+// they are sent. Zero values use the defaults of the client.
+type ScanConfig struct {
+	Enabled   bool          `yaml:"enabled" json:"enabled"`
+	Network   string        `yaml:"network" json:"network"` // tcp or unix
+	Address   string        `yaml:"address" json:"address"`
+	Timeout   time.Duration `yaml:"timeout" json:"timeout"`
+	ChunkSize int           `yaml:"chunkSize" json:"chunkSize"`
+
+	// FailOpen sends emails whose attachments couldn't be scanned, for
+	// example while the daemon is down. By default they are rejected.
+	FailOpen bool `yaml:"failOpen" json:"failOpen"`
+}
+
+// IdempotencyConfig holds how requests sent with an Idempotency-Key header
+// are remembered
+type IdempotencyConfig struct {
+	// TTL is how long a key and its response are kept
+	TTL time.Duration `yaml:"ttl" json:"ttl"`
+
+	// LockTimeout is how long a request may stay in progress before a
+	// retry with the same key is allowed to take over
+	LockTimeout time.Duration `yaml:"lockTimeout" json:"lockTimeout"`
+
+	// MaxBodySize bounds the requests sent with a key in bytes, since
+	// they are buffered to be hashed before they are handled
+	MaxBodySize int64 `yaml:"maxBodySize" json:"maxBodySize"`
+}
+
+// DedupConfig holds the policy suppressing identical sends, with the same
+// recipient, subject, body and template, by a user within a window
+type DedupConfig struct {
+	Enabled bool          `yaml:"enabled" json:"enabled"`
+	Window  time.Duration `yaml:"window" json:"window"`
+}
+
+// SuppressionConfig holds who manages the suppression list
+type SuppressionConfig struct {
+	// Admins are the emails of the users allowed to manage global
+	// suppressions, which apply to the emails of all users
+	Admins []string `yaml:"admins" json:"admins"`
+}
+
+// BounceConfig holds how bounce and complaint reports are received
+type BounceConfig struct {
+	// IngestToken authorises posting reports to the ingest endpoint, which
+	// is disabled without one
+	IngestToken string `yaml:"ingestToken" json:"ingestToken"`
+
+	// MailboxDir is a directory polled for reports, one message per file,
+	// such as the new folder of a Maildir. Polling is disabled without one.
+	MailboxDir   string        `yaml:"mailboxDir" json:"mailboxDir"`
+	PollInterval time.Duration `yaml:"pollInterval" json:"pollInterval"`
+
+	// MaxReportSize bounds a report in bytes, including the original
+	// message it may carry
+	MaxReportSize int64 `yaml:"maxReportSize" json:"maxReportSize"`
+}
+
+// InboundConfig holds the SMTP listener that receives emails, such as
+// replies, and posts them to webhooks
+type InboundConfig struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	Port     string `yaml:"port" json:"port"`
+	Hostname string `yaml:"hostname" json:"hostname"`
+
+	// Domains are the recipient domains accepted. Recipients of other
+	// domains or that no webhook routes are rejected.
+	Domains []string `yaml:"domains" json:"domains"`
+
+	MaxMessageSize int64         `yaml:"maxMessageSize" json:"maxMessageSize"`
+	MaxRecipients  int           `yaml:"maxRecipients" json:"maxRecipients"`
+	Timeout        time.Duration `yaml:"timeout" json:"timeout"`
+
+	// TLSCertFile and TLSKeyFile enable STARTTLS. RequireTLS rejects
+	// emails sent without it.
+	TLSCertFile string `yaml:"tlsCertFile" json:"tlsCertFile"`
+	TLSKeyFile  string `yaml:"tlsKeyFile" json:"tlsKeyFile"`
+	RequireTLS  bool   `yaml:"requireTLS" json:"requireTLS"`
+
+	// Webhooks receive the emails of the recipients matching their pattern
+	Webhooks []InboundWebhook `yaml:"webhooks" json:"webhooks"`
+
+	WebhookTimeout time.Duration `yaml:"webhookTimeout" json:"webhookTimeout"`
+	PollInterval   time.Duration `yaml:"pollInterval" json:"pollInterval"`
+	LeaseDuration  time.Duration `yaml:"leaseDuration" json:"leaseDuration"`
+	MaxAttempts    int           `yaml:"maxAttempts" json:"maxAttempts"`
+	RetryBackoff   time.Duration `yaml:"retryBackoff" json:"retryBackoff"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff" json:"maxBackoff"`
+}
+
+// InboundWebhook routes the received emails of the recipients matching a
+// pattern, such as "support+*@reply.example.com", to a URL
+type InboundWebhook struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	URL     string `yaml:"url" json:"url"`
+}
+
+// SubmissionConfig holds the SMTP listener that applications submit
+// emails to with their GoMail credentials instead of the API
+type SubmissionConfig struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	Port     string `yaml:"port" json:"port"`
+	Hostname string `yaml:"hostname" json:"hostname"`
+
+	MaxMessageSize int64         `yaml:"maxMessageSize" json:"maxMessageSize"`
+	MaxRecipients  int           `yaml:"maxRecipients" json:"maxRecipients"`
+	Timeout        time.Duration `yaml:"timeout" json:"timeout"`
+
+	// TLSCertFile and TLSKeyFile enable STARTTLS. AUTH is only offered
+	// after it, unless AllowInsecureAuth is set for trusted networks.
+	TLSCertFile       string `yaml:"tlsCertFile" json:"tlsCertFile"`
+	TLSKeyFile        string `yaml:"tlsKeyFile" json:"tlsKeyFile"`
+	AllowInsecureAuth bool   `yaml:"allowInsecureAuth" json:"allowInsecureAuth"`
+}
+
+// TrackingConfig holds the tracking of opens and clicks of sent HTML emails
+type TrackingConfig struct {
+	// Opens adds a tracking pixel to HTML emails whose request doesn't
+	// switch it on or off
+	Opens bool `yaml:"opens" json:"opens"`
+
+	// Clicks rewrites the links of HTML emails whose request doesn't
+	// switch it on or off to the redirect endpoint
+	Clicks bool `yaml:"clicks" json:"clicks"`
+
+	// AnonymizeIP keeps only the network of the addresses opens and clicks
+	// come from: /24 for IPv4 and /48 for IPv6
+	AnonymizeIP bool `yaml:"anonymizeIP" json:"anonymizeIP"`
+
+	// UTM parameters are added to the links of HTML emails that don't
+	// already carry them. Empty parameters are left out.
+	UTM UTMConfig `yaml:"utm" json:"utm"`
+}
+
+// UTMConfig holds the campaign parameters added to links
+type UTMConfig struct {
+	Source   string `yaml:"source" json:"source"`
+	Medium   string `yaml:"medium" json:"medium"`
+	Campaign string `yaml:"campaign" json:"campaign"`
+}
+
+// WebhooksConfig holds the delivery of email events to the webhooks users
+// subscribe
+type WebhooksConfig struct {
+	Timeout       time.Duration `yaml:"timeout" json:"timeout"`
+	PollInterval  time.Duration `yaml:"pollInterval" json:"pollInterval"`
+	LeaseDuration time.Duration `yaml:"leaseDuration" json:"leaseDuration"`
+	MaxAttempts   int           `yaml:"maxAttempts" json:"maxAttempts"`
+	RetryBackoff  time.Duration `yaml:"retryBackoff" json:"retryBackoff"`
+	MaxBackoff    time.Duration `yaml:"maxBackoff" json:"maxBackoff"`
+
+	// DisableAfter disables a webhook after this many failed attempts in a
+	// row, across all of its deliveries
+	DisableAfter int `yaml:"disableAfter" json:"disableAfter"`
+
+	// AllowPrivate permits webhooks on loopback and private networks,
+	// which are refused by default so users can't reach internal services
+	AllowPrivate bool `yaml:"allowPrivate" json:"allowPrivate"`
+}
+
+// MergeConfig holds the limits of mail merge jobs and how they are run
+type MergeConfig struct {
+	MaxRows       int   `yaml:"maxRows" json:"maxRows"`             // Recipients of a single job
+	MaxUploadSize int64 `yaml:"maxUploadSize" json:"maxUploadSize"` // Bytes of a recipient list
+
+	// BatchSize is how many recipients are queued at a time. Progress is
+	// saved after each batch, so a job whose runner stopped resumes there.
+	BatchSize     int           `yaml:"batchSize" json:"batchSize"`
+	PollInterval  time.Duration `yaml:"pollInterval" json:"pollInterval"`
+	LeaseDuration time.Duration `yaml:"leaseDuration" json:"leaseDuration"`
+}
+
 // CorsConfig holds CORS configuration
 type CorsConfig struct {
 	AllowedOrigins []string `yaml:"allowedOrigins" json:"allowedOrigins"`
@@ -122,6 +374,112 @@ func Init() (*Config, error) {
 		config.JWT.RevokedTokensTTL = 24 * time.Hour
 	}
 	
+	// Set default values for the outbound queue if not set
+	setQueueDefaults(&config.Queue)
+
+	// Set default attachment limits if not set
+	if config.Attachments.MaxFileSize == 0 {
+		config.Attachments.MaxFileSize = 20 << 20
+	}
+	if config.Attachments.MaxMessageSize == 0 {
+		config.Attachments.MaxMessageSize = 25 << 20
+	}
+	if config.Attachments.LinkTTL == 0 {
+		config.Attachments.LinkTTL = 7 * 24 * time.Hour
+	}
+
+	// Set default idempotency key limits if not set
+	if config.Idempotency.TTL == 0 {
+		config.Idempotency.TTL = 24 * time.Hour
+	}
+	if config.Idempotency.LockTimeout == 0 {
+		config.Idempotency.LockTimeout = 5 * time.Minute
+	}
+	if config.Idempotency.MaxBodySize == 0 {
+		config.Idempotency.MaxBodySize = 64 << 20
+	}
+
+	// Set the default dedup window if not set
+	if config.Dedup.Window == 0 {
+		config.Dedup.Window = 10 * time.Minute
+	}
+
+	// Set default bounce report limits if not set
+	if config.Bounce.PollInterval == 0 {
+		config.Bounce.PollInterval = time.Minute
+	}
+	if config.Bounce.MaxReportSize == 0 {
+		config.Bounce.MaxReportSize = 10 << 20
+	}
+
+	// Set default inbound listener limits if not set
+	setInboundDefaults(&config.Inbound)
+
+	// Set default submission listener limits if not set
+	if config.Submission.Port == "" {
+		config.Submission.Port = "587"
+	}
+	if config.Submission.Hostname == "" {
+		config.Submission.Hostname = "localhost"
+	}
+	if config.Submission.MaxMessageSize == 0 {
+		config.Submission.MaxMessageSize = 25 << 20
+	}
+	if config.Submission.MaxRecipients == 0 {
+		config.Submission.MaxRecipients = 50
+	}
+	if config.Submission.Timeout == 0 {
+		config.Submission.Timeout = 5 * time.Minute
+	}
+
+	// Set default webhook delivery limits if not set
+	if config.Webhooks.Timeout == 0 {
+		config.Webhooks.Timeout = 10 * time.Second
+	}
+	if config.Webhooks.PollInterval == 0 {
+		config.Webhooks.PollInterval = time.Second
+	}
+	if config.Webhooks.LeaseDuration == 0 {
+		config.Webhooks.LeaseDuration = time.Minute
+	}
+	if config.Webhooks.MaxAttempts == 0 {
+		config.Webhooks.MaxAttempts = 8
+	}
+	if config.Webhooks.RetryBackoff == 0 {
+		config.Webhooks.RetryBackoff = 30 * time.Second
+	}
+	if config.Webhooks.MaxBackoff == 0 {
+		config.Webhooks.MaxBackoff = time.Hour
+	}
+	if config.Webhooks.DisableAfter == 0 {
+		config.Webhooks.DisableAfter = 20
+	}
+
+	// Set default mail merge limits if not set
+	if config.Merge.MaxRows == 0 {
+		config.Merge.MaxRows = 10000
+	}
+	if config.Merge.MaxUploadSize == 0 {
+		config.Merge.MaxUploadSize = 10 << 20
+	}
+	if config.Merge.BatchSize == 0 {
+		config.Merge.BatchSize = 100
+	}
+	if config.Merge.PollInterval == 0 {
+		config.Merge.PollInterval = time.Second
+	}
+	if config.Merge.LeaseDuration == 0 {
+		config.Merge.LeaseDuration = 2 * time.Minute
+	}
+
+	// Set defaults for links in emails if not set
+	if config.Server.PublicURL == "" {
+		config.Server.PublicURL = "http://localhost:" + config.Server.Port
+	}
+	if config.Server.SigningSecret == "" {
+		config.Server.SigningSecret = config.JWT.Secret
+	}
+	
 	return config, nil
 }
 
@@ -137,7 +495,9 @@ func loadConfig() error {
 		return err
 	}
 	
-	config = &Config{}
+	// Defaults that can be set to zero are filled in before the file is
+	// read, so that an explicit zero is kept
+	config = &Config{Queue: QueueConfig{Workers: defaultQueueWorkers}}
 	if err := yaml.Unmarshal(yamlFile, config); err != nil {
 		return err
 	}
@@ -154,6 +514,12 @@ func overwriteConfigFromEnv() error {
 	if portGRPC := os.Getenv("PORT_GRPC"); portGRPC != "" {
 		config.Server.PortGRPC = portGRPC
 	}
+	if publicURL := os.Getenv("PUBLIC_URL"); publicURL != "" {
+		config.Server.PublicURL = publicURL
+	}
+	if signingSecret := os.Getenv("SIGNING_SECRET"); signingSecret != "" {
+		config.Server.SigningSecret = signingSecret
+	}
 	
 	// MongoDB config
 	if uri := os.Getenv("MONGODB_URI"); uri != "" {
@@ -205,6 +571,76 @@ func overwriteConfigFromEnv() error {
 		config.JWT.EnableTokenRevoking = enableTokenRevokingStr == "true" || enableTokenRevokingStr == "1" || enableTokenRevokingStr == "yes"
 	}
 
+	// Queue config
+	if workersStr := os.Getenv("QUEUE_WORKERS"); workersStr != "" {
+		if workers, err := strconv.Atoi(workersStr); err == nil {
+			config.Queue.Workers = workers
+		}
+	}
+
+	// Scan config
+	if address := os.Getenv("CLAMD_ADDRESS"); address != "" {
+		config.Scan.Address = address
+	}
+
+	// Bounce config
+	if token := os.Getenv("BOUNCE_INGEST_TOKEN"); token != "" {
+		config.Bounce.IngestToken = token
+	}
+	if dir := os.Getenv("BOUNCE_MAILBOX_DIR"); dir != "" {
+		config.Bounce.MailboxDir = dir
+	}
+
+	// Inbound config
+	if enabledStr := os.Getenv("INBOUND_ENABLED"); enabledStr != "" {
+		config.Inbound.Enabled = enabledStr == "true" || enabledStr == "1" || enabledStr == "yes"
+	}
+	if port := os.Getenv("INBOUND_PORT"); port != "" {
+		config.Inbound.Port = port
+	}
+	if domains := os.Getenv("INBOUND_DOMAINS"); domains != "" {
+		config.Inbound.Domains = strings.Split(domains, ",")
+	}
+	if certFile := os.Getenv("INBOUND_TLS_CERT_FILE"); certFile != "" {
+		config.Inbound.TLSCertFile = certFile
+	}
+	if keyFile := os.Getenv("INBOUND_TLS_KEY_FILE"); keyFile != "" {
+		config.Inbound.TLSKeyFile = keyFile
+	}
+
+	// Submission config
+	if enabledStr := os.Getenv("SUBMISSION_ENABLED"); enabledStr != "" {
+		config.Submission.Enabled = enabledStr == "true" || enabledStr == "1" || enabledStr == "yes"
+	}
+	if port := os.Getenv("SUBMISSION_PORT"); port != "" {
+		config.Submission.Port = port
+	}
+	if certFile := os.Getenv("SUBMISSION_TLS_CERT_FILE"); certFile != "" {
+		config.Submission.TLSCertFile = certFile
+	}
+	if keyFile := os.Getenv("SUBMISSION_TLS_KEY_FILE"); keyFile != "" {
+		config.Submission.TLSKeyFile = keyFile
+	}
+	if insecureStr := os.Getenv("SUBMISSION_ALLOW_INSECURE_AUTH"); insecureStr != "" {
+		config.Submission.AllowInsecureAuth = insecureStr == "true" || insecureStr == "1" || insecureStr == "yes"
+	}
+
+	// Tracking config
+	if opensStr := os.Getenv("TRACKING_OPENS"); opensStr != "" {
+		config.Tracking.Opens = opensStr == "true" || opensStr == "1" || opensStr == "yes"
+	}
+	if clicksStr := os.Getenv("TRACKING_CLICKS"); clicksStr != "" {
+		config.Tracking.Clicks = clicksStr == "true" || clicksStr == "1" || clicksStr == "yes"
+	}
+	if anonymizeStr := os.Getenv("TRACKING_ANONYMIZE_IP"); anonymizeStr != "" {
+		config.Tracking.AnonymizeIP = anonymizeStr == "true" || anonymizeStr == "1" || anonymizeStr == "yes"
+	}
+
+	// Webhooks config
+	if privateStr := os.Getenv("WEBHOOKS_ALLOW_PRIVATE"); privateStr != "" {
+		config.Webhooks.AllowPrivate = privateStr == "true" || privateStr == "1" || privateStr == "yes"
+	}
+
 	// Load JSON configuration from GOMAIL_CONFIG env var if it exists
 	// This allows passing complex configuration as a single JSON string
 	if configJSON := os.Getenv("GOMAIL_CONFIG"); configJSON != "" {
@@ -251,6 +687,65 @@ func initMongoDB() error {
 	return nil
 }
 
+// setQueueDefaults fills in unset outbound queue settings
+func setQueueDefaults(q *QueueConfig) {
+	if q.PollInterval == 0 {
+		q.PollInterval = time.Second
+	}
+	if q.LeaseDuration == 0 {
+		q.LeaseDuration = 2 * time.Minute
+	}
+	if q.MaxAttempts == 0 {
+		q.MaxAttempts = 5
+	}
+	if q.RetryBackoff == 0 {
+		q.RetryBackoff = 30 * time.Second
+	}
+	if q.MaxBackoff == 0 {
+		q.MaxBackoff = time.Hour
+	}
+	if q.SchedulerInterval == 0 {
+		q.SchedulerInterval = time.Second
+	}
+}
+
+// setInboundDefaults fills in unset inbound listener and webhook settings
+func setInboundDefaults(in *InboundConfig) {
+	if in.Port == "" {
+		in.Port = "2525"
+	}
+	if in.Hostname == "" {
+		in.Hostname = "localhost"
+	}
+	if in.MaxMessageSize == 0 {
+		in.MaxMessageSize = 10 << 20
+	}
+	if in.MaxRecipients == 0 {
+		in.MaxRecipients = 50
+	}
+	if in.Timeout == 0 {
+		in.Timeout = 5 * time.Minute
+	}
+	if in.WebhookTimeout == 0 {
+		in.WebhookTimeout = 10 * time.Second
+	}
+	if in.PollInterval == 0 {
+		in.PollInterval = time.Second
+	}
+	if in.LeaseDuration == 0 {
+		in.LeaseDuration = 2 * time.Minute
+	}
+	if in.MaxAttempts == 0 {
+		in.MaxAttempts = 8
+	}
+	if in.RetryBackoff == 0 {
+		in.RetryBackoff = 30 * time.Second
+	}
+	if in.MaxBackoff == 0 {
+		in.MaxBackoff = time.Hour
+	}
+}
+
 // Get returns the current configuration
 func Get() *Config {
 	if config == nil {
@@ -119,4 +119,34 @@ services:
 	if configFromGet != cfg {
 		t.Error("Get() did not return the expected config instance")
 	}
-} 
\ No newline at end of file
+}
+
+func TestLoadConfig_QueueWorkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "unset", content: "queue:\n  maxAttempts: 3\n", want: 4},
+		{name: "explicit zero", content: "queue:\n  workers: 0\n", want: 0},
+		{name: "set", content: "queue:\n  workers: 2\n", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempConfigFile := t.TempDir() + "/config.yaml"
+			if err := os.WriteFile(tempConfigFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create temp config file: %v", err)
+			}
+			t.Setenv("CONFIG_PATH", tempConfigFile)
+
+			if err := loadConfig(); err != nil {
+				t.Fatalf("Failed to load configuration: %v", err)
+			}
+
+			if config.Queue.Workers != tt.want {
+				t.Errorf("Expected Workers to be %d, got %d", tt.want, config.Queue.Workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package attachment
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/attachment"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// uploadMemory is how much of an upload is kept in memory, the rest is
+	// spooled to a temporary file
+	uploadMemory = 1 << 20
+
+	// uploadFieldsSize allows for the text fields and multipart framing of
+	// an upload on top of the file size limit
+	uploadFieldsSize = 1 << 20
+)
+
+// Handler handles attachment store HTTP requests
+type Handler struct {
+	attachmentService attachment.Service
+	maxFileSize       int64
+}
+
+// NewHandler creates a new attachment handler
+func NewHandler(attachmentService attachment.Service, cfg *config.Config) *Handler {
+	return &Handler{
+		attachmentService: attachmentService,
+		maxFileSize:       cfg.Attachments.MaxFileSize,
+	}
+}
+
+// upload handles storing a file sent as the "file" field of a
+// multipart/form-data request. An optional "ttl" field overrides the
+// configured expiry, e.g. "720h".
+func (h *Handler) upload(c *gin.Context) {
+	if h.maxFileSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxFileSize+uploadFieldsSize)
+	}
+	if err := c.Request.ParseMultipartForm(uploadMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("%v: the upload exceeds %d bytes", attachment.ErrAttachmentTooLarge, tooLarge.Limit)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing file"})
+		return
+	}
+
+	req := attachment.UploadRequest{
+		Filename: file.Filename,
+		MimeType: file.Header.Get("Content-Type"),
+		Size:     file.Size,
+		Open: func() (io.ReadCloser, error) {
+			return file.Open()
+		},
+	}
+	if req.MimeType == "application/octet-stream" {
+		req.MimeType = ""
+	}
+	if value := c.PostForm("ttl"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid ttl %q", value)})
+			return
+		}
+		req.TTL = ttl
+	}
+
+	resp, err := h.attachmentService.Upload(c.Request.Context(), c.GetString("userID"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	// A duplicate upload returns the attachment stored before
+	status := http.StatusCreated
+	if resp.Deduplicated {
+		status = http.StatusOK
+	}
+	c.JSON(status, resp)
+}
+
+// list handles listing the stored attachments
+func (h *Handler) list(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	resp, err := h.attachmentService.List(c.Request.Context(), c.GetString("userID"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// get handles fetching the metadata of a stored attachment
+func (h *Handler) get(c *gin.Context) {
+	resp, err := h.attachmentService.Get(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// delete handles removing a stored attachment
+func (h *Handler) delete(c *gin.Context) {
+	if err := h.attachmentService.Delete(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, attachment.ErrInvalidAttachment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrAttachmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,180 @@
+package attachment
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/logic/attachment"
+	"GoMail/app/logic/attachment/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// uploadBody builds a multipart body with a file field and an optional ttl
+func uploadBody(t *testing.T, content, ttl string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if content != "" {
+		part, err := writer.CreateFormFile("file", "terms.pdf")
+		assert.NoError(t, err)
+		_, _ = part.Write([]byte(content))
+	}
+	if ttl != "" {
+		_ = writer.WriteField("ttl", ttl)
+	}
+	assert.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func Test_handler_upload(t *testing.T) {
+	tests := []struct {
+		name               string
+		content            string
+		ttl                string
+		maxFileSize        int64
+		callLogic          bool
+		response           *attachment.AttachmentResponse
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			content:            "%PDF-1.7",
+			ttl:                "24h",
+			callLogic:          true,
+			response:           &attachment.AttachmentResponse{ID: "att-1"},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "deduplicated",
+			content:            "%PDF-1.7",
+			callLogic:          true,
+			response:           &attachment.AttachmentResponse{ID: "att-1", Deduplicated: true},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "missing file",
+			ttl:                "24h",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid ttl",
+			content:            "%PDF-1.7",
+			ttl:                "soon",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "body too large",
+			content:            strings.Repeat("x", 2<<20),
+			maxFileSize:        1,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:               "too large for logic",
+			content:            "%PDF-1.7",
+			callLogic:          true,
+			err:                attachment.ErrAttachmentTooLarge,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:               "error in logic",
+			content:            "%PDF-1.7",
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			body, contentType := uploadBody(t, tt.content, tt.ttl)
+			r, _ := http.NewRequest("POST", "/attachments", body)
+			r.Header.Set("Content-Type", contentType)
+			c.Request = r
+			c.Set("userID", "user-1")
+
+			attachmentService := &mocks.Service{}
+			if tt.callLogic {
+				attachmentService.On("Upload", mock.Anything, "user-1", mock.MatchedBy(func(req attachment.UploadRequest) bool {
+					wantTTL, _ := time.ParseDuration(tt.ttl)
+					return req.Filename == "terms.pdf" && req.Size == int64(len(tt.content)) && req.TTL == wantTTL
+				})).Return(tt.response, tt.err)
+			}
+
+			h := &Handler{
+				attachmentService: attachmentService,
+				maxFileSize:       tt.maxFileSize,
+			}
+
+			// Act
+			h.upload(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			attachmentService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_delete(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not found",
+			err:                attachment.ErrAttachmentNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("DELETE", "/attachments/att-1", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "att-1"}}
+			c.Set("userID", "user-1")
+
+			attachmentService := &mocks.Service{}
+			attachmentService.On("Delete", mock.Anything, "user-1", "att-1").Return(tt.err)
+
+			h := &Handler{
+				attachmentService: attachmentService,
+			}
+
+			// Act
+			h.delete(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			attachmentService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package attachment
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddProtectedRoute adds attachment store routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	attachmentGroup := router.Group(path)
+	{
+		attachmentGroup.POST("", handler.upload)
+		attachmentGroup.GET("", handler.list)
+		attachmentGroup.GET("/:id", handler.get)
+		attachmentGroup.DELETE("/:id", handler.delete)
+	}
+}
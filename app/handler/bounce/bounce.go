@@ -0,0 +1,60 @@
+package bounce
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/bounce"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles bounce and complaint report HTTP requests
+type Handler struct {
+	bounceService bounce.Service
+	config        *config.Config
+}
+
+// NewHandler creates a new bounce handler
+func NewHandler(bounceService bounce.Service, cfg *config.Config) *Handler {
+	return &Handler{
+		bounceService: bounceService,
+		config:        cfg,
+	}
+}
+
+// ingest handles a DSN or ARF report posted as a raw message, such as
+// from a mail server's pipe transport
+func (h *Handler) ingest(c *gin.Context) {
+	token := h.config.Bounce.IngestToken
+	if token == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bounce ingestion is disabled"})
+		return
+	}
+	given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid ingest token"})
+		return
+	}
+
+	resp, err := h.bounceService.Process(c.Request.Context(), c.Request.Body)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, bounce.ErrInvalidReport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,103 @@
+package bounce
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/bounce"
+	"GoMail/app/logic/bounce/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_ingest(t *testing.T) {
+	tests := []struct {
+		name               string
+		ingestToken        string
+		authorization      string
+		callLogic          bool
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			ingestToken:        "secret",
+			authorization:      "Bearer secret",
+			callLogic:          true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "ingestion disabled",
+			authorization:      "Bearer ",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "missing token",
+			ingestToken:        "secret",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "wrong token",
+			ingestToken:        "secret",
+			authorization:      "Bearer guess",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "not a report",
+			ingestToken:        "secret",
+			authorization:      "Bearer secret",
+			callLogic:          true,
+			err:                bounce.ErrInvalidReport,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error in logic",
+			ingestToken:        "secret",
+			authorization:      "Bearer secret",
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/bounces", bytes.NewBufferString("From: MAILER-DAEMON@example.net\r\n\r\n"))
+			r.Header.Set("Content-Type", "message/rfc822")
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			c.Request = r
+
+			bounceService := &mocks.Service{}
+			if tt.callLogic {
+				var resp *bounce.ProcessResponse
+				if tt.err == nil {
+					resp = &bounce.ProcessResponse{Matched: true}
+				}
+				bounceService.On("Process", mock.Anything, mock.Anything).Return(resp, tt.err)
+			}
+
+			h := &Handler{
+				bounceService: bounceService,
+				config:        &config.Config{Bounce: config.BounceConfig{IngestToken: tt.ingestToken}},
+			}
+
+			// Act
+			h.ingest(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			bounceService.AssertExpectations(t)
+		})
+	}
+}
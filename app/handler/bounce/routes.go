@@ -0,0 +1,14 @@
+package bounce
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddPublicRoute adds the route mail servers post bounces and complaints
+// to. It authenticates with the ingest token instead of a user.
+func AddPublicRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	bounceGroup := router.Group(path)
+	{
+		bounceGroup.POST("", handler.ingest)
+	}
+}
@@ -0,0 +1,90 @@
+package download
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"GoMail/app/logic/download"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles download link HTTP requests
+type Handler struct {
+	downloadService download.Service
+}
+
+// NewHandler creates a new download link handler
+func NewHandler(downloadService download.Service) *Handler {
+	return &Handler{
+		downloadService: downloadService,
+	}
+}
+
+// download handles streaming the file of a download link to its recipient
+func (h *Handler) download(c *gin.Context) {
+	file, err := h.downloadService.Open(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	defer file.Content.Close()
+
+	mimeType := file.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	// Links are personal, so shared caches must not keep the file
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Filename}))
+	c.Header("Content-Length", strconv.FormatInt(file.Size, 10))
+	c.Header("Content-Type", mimeType)
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, file.Content); err != nil {
+		log.Printf("Failed to stream download %s: %v", file.Filename, err)
+	}
+}
+
+// list handles listing the download links
+func (h *Handler) list(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	resp, err := h.downloadService.List(c.Request.Context(), c.GetString("userID"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// revoke handles disabling a download link
+func (h *Handler) revoke(c *gin.Context) {
+	if err := h.downloadService.Revoke(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, download.ErrInvalidLink):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, download.ErrLinkNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, download.ErrLinkGone):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
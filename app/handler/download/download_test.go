@@ -0,0 +1,127 @@
+package download
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"GoMail/app/logic/download"
+	"GoMail/app/logic/download/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_download(t *testing.T) {
+	tests := []struct {
+		name               string
+		file               *download.File
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			file:               &download.File{Filename: "q3 report.pdf", MimeType: "application/pdf", Size: 6, Content: io.NopCloser(strings.NewReader("report"))},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "unknown token",
+			err:                download.ErrLinkNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "expired or revoked",
+			err:                download.ErrLinkGone,
+			expectedStatusCode: http.StatusGone,
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("GET", "/files/token-1", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "token", Value: "token-1"}}
+
+			downloadService := &mocks.Service{}
+			downloadService.On("Open", mock.Anything, "token-1").Return(tt.file, tt.err)
+
+			h := &Handler{
+				downloadService: downloadService,
+			}
+
+			// Act
+			h.download(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.file != nil {
+				assert.Equal(t, "report", w.Body.String())
+				assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="q3 report.pdf"`, w.Header().Get("Content-Disposition"))
+				assert.Equal(t, "6", w.Header().Get("Content-Length"))
+			}
+			downloadService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_revoke(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not found",
+			err:                download.ErrLinkNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("DELETE", "/files/link-1", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "link-1"}}
+			c.Set("userID", "user-1")
+
+			downloadService := &mocks.Service{}
+			downloadService.On("Revoke", mock.Anything, "user-1", "link-1").Return(tt.err)
+
+			h := &Handler{
+				downloadService: downloadService,
+			}
+
+			// Act
+			h.revoke(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			downloadService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package download
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddPublicRoute adds the route recipients download files from
+func AddPublicRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	downloadGroup := router.Group(path)
+	{
+		downloadGroup.GET("/:token", handler.download)
+	}
+}
+
+// AddProtectedRoute adds download link routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	downloadGroup := router.Group(path)
+	{
+		downloadGroup.GET("", handler.list)
+		downloadGroup.DELETE("/:id", handler.revoke)
+	}
+}
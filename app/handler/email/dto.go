@@ -1,9 +1,16 @@
 package email
 
+import "time"
+
 // This file would contain any handler-specific DTOs
 // Most of the DTOs are already defined in the logic/email package
 
 // StatusResponse represents a response for checking email service status
 type StatusResponse struct {
 	Status string `json:"status"`
-} 
\ No newline at end of file
+}
+
+// RescheduleRequest represents a request to change the send time of a scheduled email
+type RescheduleRequest struct {
+	SendAt time.Time `json:"sendAt" binding:"required"`
+}
@@ -1,22 +1,28 @@
 package email
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"GoMail/app/config"
 	"GoMail/app/logic/email"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // Handler handles email-related HTTP requests
 type Handler struct {
 	emailService email.Email
+	attachments  config.AttachmentConfig
 }
 
 // NewHandler creates a new email handler
-func NewHandler(emailService email.Email) *Handler {
+func NewHandler(emailService email.Email, cfg *config.Config) *Handler {
 	return &Handler{
 		emailService: emailService,
+		attachments:  cfg.Attachments,
 	}
 }
 
@@ -28,8 +34,14 @@ func (h *Handler) sendEmail(c *gin.Context) {
 		return
 	}
 
+	req.UserID = c.GetString("userID")
+
 	resp, err := h.emailService.Send(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, email.ErrInvalidTemplate) || errors.Is(err, email.ErrInvalidFormat) || errors.Is(err, email.ErrInvalidTopic) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -45,8 +57,14 @@ func (h *Handler) sendHTMLEmail(c *gin.Context) {
 		return
 	}
 
+	req.UserID = c.GetString("userID")
+
 	resp, err := h.emailService.SendHTML(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, email.ErrInvalidTemplate) || errors.Is(err, email.ErrInvalidFormat) || errors.Is(err, email.ErrInvalidTopic) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -54,16 +72,45 @@ func (h *Handler) sendHTMLEmail(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-// sendEmailWithAttachments handles sending an email with attachments
+// sendEmailWithAttachments handles sending an email with attachments, sent
+// as JSON or as a multipart/form-data upload
 func (h *Handler) sendEmailWithAttachments(c *gin.Context) {
 	var req email.SendWithAttachmentsRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var err error
+	if c.ContentType() == binding.MIMEMultipartPOSTForm {
+		err = h.bindAttachmentsForm(c, &req)
+	} else {
+		err = c.ShouldBindJSON(&req)
+	}
+	if err != nil {
+		if errors.Is(err, email.ErrAttachmentTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	req.UserID = c.GetString("userID")
+
 	resp, err := h.emailService.SendWithAttachments(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, email.ErrInvalidFormat) || errors.Is(err, email.ErrInvalidAttachment) || errors.Is(err, email.ErrInvalidTopic) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, email.ErrAttachmentTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, email.ErrAttachmentInfected) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, email.ErrScanFailed) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -79,6 +126,8 @@ func (h *Handler) sendBulkEmails(c *gin.Context) {
 		return
 	}
 
+	req.UserID = c.GetString("userID")
+
 	resp, err := h.emailService.SendBulk(c.Request.Context(), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -88,6 +137,97 @@ func (h *Handler) sendBulkEmails(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// sendInvite handles sending a calendar invitation
+func (h *Handler) sendInvite(c *gin.Context) {
+	var req email.SendInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.UserID = c.GetString("userID")
+
+	resp, err := h.emailService.SendInvite(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, email.ErrInvalidInvite) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// getMessageStatus returns the delivery status of a queued email
+func (h *Handler) getMessageStatus(c *gin.Context) {
+	resp, err := h.emailService.GetStatus(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, email.ErrEmailNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// listScheduled returns the scheduled emails of the current user
+func (h *Handler) listScheduled(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	resp, err := h.emailService.ListScheduled(c.Request.Context(), c.GetString("userID"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// cancelScheduled cancels a scheduled email before it is sent
+func (h *Handler) cancelScheduled(c *gin.Context) {
+	err := h.emailService.CancelScheduled(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, email.ErrEmailNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// rescheduleEmail changes the send time of a scheduled email
+func (h *Handler) rescheduleEmail(c *gin.Context) {
+	var req RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.emailService.Reschedule(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.SendAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, email.ErrInvalidSchedule):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, email.ErrEmailNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // getEmailStatus returns the status of the email service
 func (h *Handler) getEmailStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
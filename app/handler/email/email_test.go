@@ -19,7 +19,7 @@ import (
 // Test request bodies
 var (
 	validSendEmailRequestBody   = []byte(`{"from":"sender@example.com","to":"recipient@example.com","subject":"Test Subject","body":"Test Body"}`)
-	invalidSendEmailRequestBody = []byte(`{"from":"","to":"recipient@example.com","subject":"Test Subject","body":"Test Body"}`)
+	invalidSendEmailRequestBody = []byte(`{"from":"sender@example.com","to":["recipient@example.com"],"subject":"Test Subject","body":"Test Body"}`)
 )
 
 func Test_handler_sendEmail(t *testing.T) {
@@ -196,6 +196,260 @@ func buildSendHTMLEmailMock(enableFlag bool, res *email.SendEmailResponse, err e
 	return client
 }
 
+func Test_handler_sendInvite(t *testing.T) {
+	validInviteRequestBody := []byte(`{"from":"sender@example.com","subject":"Sync","event":{"organizer":{"email":"sender@example.com"},"attendees":[{"email":"recipient@example.com"}],"start":"2026-03-02T09:00:00Z","end":"2026-03-02T10:00:00Z"}}`)
+	malformedInviteRequestBody := []byte(`{"event":`)
+
+	tests := []struct {
+		name               string
+		email              *mocks.Email
+		request            []byte
+		expectedStatusCode int
+		expectedResponse   *email.SendInviteResponse
+	}{
+		{
+			name:               "happy path",
+			email:              buildSendInviteMock(true, &email.SendInviteResponse{Success: true, UID: "uid-1"}, nil),
+			request:            validInviteRequestBody,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   &email.SendInviteResponse{Success: true, UID: "uid-1"},
+		},
+		{
+			name:               "malformed request",
+			email:              buildSendInviteMock(false, nil, nil),
+			request:            malformedInviteRequestBody,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid invite",
+			email:              buildSendInviteMock(true, nil, email.ErrInvalidInvite),
+			request:            validInviteRequestBody,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error in logic",
+			email:              buildSendInviteMock(true, nil, errors.New("failed to send invite")),
+			request:            validInviteRequestBody,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/email/send-invite", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+
+			h := &Handler{
+				emailService: tt.email,
+			}
+
+			h.sendInvite(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedResponse != nil {
+				var response email.SendInviteResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, tt.expectedResponse, &response)
+			}
+
+			tt.email.AssertExpectations(t)
+		})
+	}
+}
+
+func buildSendInviteMock(enableFlag bool, res *email.SendInviteResponse, err error) *mocks.Email {
+	client := &mocks.Email{}
+	if enableFlag {
+		client.On("SendInvite", mock.Anything, mock.AnythingOfType("email.SendInviteRequest")).Return(res, err)
+	}
+	return client
+}
+
+func Test_handler_getMessageStatus(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		response           *email.EmailStatusResponse
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			response:           &email.EmailStatusResponse{ID: "abc", Status: "pending", Attempts: 1},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not found",
+			err:                email.ErrEmailNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("GET", "/email/messages/abc", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "abc"}}
+			c.Set("userID", "user-1")
+
+			emailService := &mocks.Email{}
+			emailService.On("GetStatus", mock.Anything, "user-1", "abc").Return(tt.response, tt.err)
+
+			h := &Handler{
+				emailService: emailService,
+			}
+
+			h.getMessageStatus(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.response != nil {
+				var response email.EmailStatusResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, tt.response, &response)
+			}
+
+			emailService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_cancelScheduled(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not found",
+			err:                email.ErrEmailNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("DELETE", "/email/scheduled/abc", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "abc"}}
+			c.Set("userID", "user-1")
+
+			emailService := &mocks.Email{}
+			emailService.On("CancelScheduled", mock.Anything, "user-1", "abc").Return(tt.err)
+
+			h := &Handler{
+				emailService: emailService,
+			}
+
+			h.cancelScheduled(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			emailService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_rescheduleEmail(t *testing.T) {
+	validRescheduleRequestBody := []byte(`{"sendAt":"2030-01-02T09:00:00Z"}`)
+
+	tests := []struct {
+		name               string
+		request            []byte
+		callLogic          bool
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			request:            validRescheduleRequestBody,
+			callLogic:          true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "missing sendAt",
+			request:            []byte(`{}`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid schedule",
+			request:            validRescheduleRequestBody,
+			callLogic:          true,
+			err:                email.ErrInvalidSchedule,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "not found",
+			request:            validRescheduleRequestBody,
+			callLogic:          true,
+			err:                email.ErrEmailNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("PUT", "/email/scheduled/abc", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "abc"}}
+			c.Set("userID", "user-1")
+
+			emailService := &mocks.Email{}
+			if tt.callLogic {
+				var resp *email.EmailStatusResponse
+				if tt.err == nil {
+					resp = &email.EmailStatusResponse{ID: "abc", Status: "scheduled"}
+				}
+				emailService.On("Reschedule", mock.Anything, "user-1", "abc", mock.AnythingOfType("time.Time")).Return(resp, tt.err)
+			}
+
+			h := &Handler{
+				emailService: emailService,
+			}
+
+			h.rescheduleEmail(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			emailService.AssertExpectations(t)
+		})
+	}
+}
+
 func Test_handler_getEmailStatus(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -20,5 +20,10 @@ func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
 		emailGroup.POST("/send-html", handler.sendHTMLEmail)
 		emailGroup.POST("/send-with-attachments", handler.sendEmailWithAttachments)
 		emailGroup.POST("/send-bulk", handler.sendBulkEmails)
+		emailGroup.POST("/send-invite", handler.sendInvite)
+		emailGroup.GET("/messages/:id", handler.getMessageStatus)
+		emailGroup.GET("/scheduled", handler.listScheduled)
+		emailGroup.PUT("/scheduled/:id", handler.rescheduleEmail)
+		emailGroup.DELETE("/scheduled/:id", handler.cancelScheduled)
 	}
 } 
\ No newline at end of file
@@ -0,0 +1,93 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/logic/email"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// uploadMemory is how much of an upload is kept in memory, the rest of
+	// the files is spooled to temporary files
+	uploadMemory = 1 << 20
+
+	// uploadFieldsSize allows for the text fields and multipart framing of
+	// an upload on top of the attachment limit
+	uploadFieldsSize = 1 << 20
+)
+
+// bindAttachmentsForm binds a multipart/form-data upload. The text fields
+// match the JSON request; every file of the "attachments" field becomes an
+// attachment streamed from the upload when the email is sent.
+func (h *Handler) bindAttachmentsForm(c *gin.Context, req *email.SendWithAttachmentsRequest) error {
+	if limit := h.attachments.MaxMessageSize; limit > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+uploadFieldsSize)
+	}
+	if err := c.Request.ParseMultipartForm(uploadMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return fmt.Errorf("%w: the upload exceeds %d bytes", email.ErrAttachmentTooLarge, tooLarge.Limit)
+		}
+		return err
+	}
+
+	req.From = c.PostForm("from")
+	req.To = c.PostForm("to")
+	req.Subject = c.PostForm("subject")
+	req.Body = c.PostForm("body")
+	req.Format = c.PostForm("format")
+
+	if value := c.PostForm("async"); value != "" {
+		async, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid async value %q", value)
+		}
+		req.Async = async
+	}
+	if value := c.PostForm("sendAt"); value != "" {
+		sendAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid sendAt value %q", value)
+		}
+		req.SendAt = &sendAt
+	}
+	if value := c.PostForm("allowDuplicate"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid allowDuplicate value %q", value)
+		}
+		req.AllowDuplicate = allow
+	}
+
+	for _, file := range c.Request.MultipartForm.File["attachments"] {
+		req.Attachments = append(req.Attachments, uploadedAttachment(file))
+	}
+	return nil
+}
+
+// uploadedAttachment streams an uploaded file. A missing or generic content
+// type is left empty, so the email service sniffs it from the content.
+func uploadedAttachment(file *multipart.FileHeader) libSmtp.Attachment {
+	mimeType := file.Header.Get("Content-Type")
+	if mimeType == "application/octet-stream" {
+		mimeType = ""
+	}
+
+	return libSmtp.Attachment{
+		Filename: file.Filename,
+		MimeType: mimeType,
+		Size:     file.Size,
+		Open: func() (io.ReadCloser, error) {
+			return file.Open()
+		},
+	}
+}
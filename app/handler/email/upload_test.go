@@ -0,0 +1,177 @@
+package email
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/email"
+	"GoMail/app/logic/email/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// multipartUpload builds a form with the given fields and files, keyed by
+// filename, under the "attachments" field
+func multipartUpload(t *testing.T, fields map[string]string, files map[string]string, contentType string) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(t, form.WriteField(name, value))
+	}
+	for filename, content := range files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="attachments"; filename="`+filename+`"`)
+		header.Set("Content-Type", contentType)
+		part, err := form.CreatePart(header)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, form.Close())
+	return &body, form.FormDataContentType()
+}
+
+func Test_handler_sendEmailWithAttachments_Multipart(t *testing.T) {
+	report := strings.Repeat("%PDF-1.7 ", 200000)
+
+	service := &mocks.Email{}
+	service.On("SendWithAttachments", mock.Anything, mock.MatchedBy(func(req email.SendWithAttachmentsRequest) bool {
+		if req.To != "recipient@example.com" || req.Subject != "Report" || !req.Async || req.UserID != "user-1" || len(req.Attachments) != 1 {
+			return false
+		}
+		att := req.Attachments[0]
+		if att.Filename != "report.pdf" || att.MimeType != "" || att.Size != int64(len(report)) || att.Content != nil {
+			return false
+		}
+		content, err := att.Open()
+		if err != nil {
+			return false
+		}
+		defer content.Close()
+		data, err := io.ReadAll(content)
+		return err == nil && string(data) == report
+	})).Return(&email.SendEmailResponse{Success: true}, nil)
+
+	body, contentType := multipartUpload(t,
+		map[string]string{"from": "sender@example.com", "to": "recipient@example.com", "subject": "Report", "body": "Attached", "async": "true"},
+		map[string]string{"report.pdf": report}, "application/octet-stream")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/email/send-with-attachments", body)
+	c.Request.Header.Set("Content-Type", contentType)
+	c.Set("userID", "user-1")
+
+	h := NewHandler(service, &config.Config{Attachments: config.AttachmentConfig{MaxMessageSize: 4 << 20}})
+	h.sendEmailWithAttachments(c)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	service.AssertExpectations(t)
+}
+
+func Test_handler_sendEmailWithAttachments_MultipartErrors(t *testing.T) {
+	tests := []struct {
+		name               string
+		fields             map[string]string
+		files              map[string]string
+		expectedStatusCode int
+	}{
+		{
+			name:               "upload too large",
+			fields:             map[string]string{"to": "recipient@example.com"},
+			files:              map[string]string{"a.bin": strings.Repeat("a", 3<<20)},
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:               "invalid async",
+			fields:             map[string]string{"to": "recipient@example.com", "async": "sometimes"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid sendAt",
+			fields:             map[string]string{"to": "recipient@example.com", "sendAt": "tomorrow"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.Email{}
+			body, contentType := multipartUpload(t, tt.fields, tt.files, "text/plain")
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/email/send-with-attachments", body)
+			c.Request.Header.Set("Content-Type", contentType)
+
+			h := NewHandler(service, &config.Config{Attachments: config.AttachmentConfig{MaxMessageSize: 1 << 20}})
+			h.sendEmailWithAttachments(c)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code, w.Body.String())
+			service.AssertNotCalled(t, "SendWithAttachments", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_handler_sendEmailWithAttachments_TooLarge(t *testing.T) {
+	service := &mocks.Email{}
+	service.On("SendWithAttachments", mock.Anything, mock.AnythingOfType("email.SendWithAttachmentsRequest")).
+		Return(&email.SendEmailResponse{Success: false, Error: "attachment too large"}, email.ErrAttachmentTooLarge)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/email/send-with-attachments", strings.NewReader(`{"to":"recipient@example.com","attachments":[{"Filename":"a.txt","Content":"YQ=="}]}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	h := &Handler{emailService: service}
+	h.sendEmailWithAttachments(c)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func Test_handler_sendEmailWithAttachments_ScanErrors(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "infected",
+			err:                email.ErrAttachmentInfected,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:               "scanner unavailable",
+			err:                email.ErrScanFailed,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.Email{}
+			service.On("SendWithAttachments", mock.Anything, mock.AnythingOfType("email.SendWithAttachmentsRequest")).
+				Return(&email.SendEmailResponse{Success: false, Error: tt.err.Error()}, tt.err)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/email/send-with-attachments", strings.NewReader(`{"to":"recipient@example.com","attachments":[{"Filename":"a.txt","Content":"YQ=="}]}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h := &Handler{emailService: service}
+			h.sendEmailWithAttachments(c)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
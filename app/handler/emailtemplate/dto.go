@@ -0,0 +1,6 @@
+package emailtemplate
+
+// PublishRequest represents a request to publish a template version
+type PublishRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
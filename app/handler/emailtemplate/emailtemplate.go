@@ -0,0 +1,201 @@
+package emailtemplate
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"GoMail/app/logic/emailtemplate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles stored template HTTP requests
+type Handler struct {
+	templateService emailtemplate.Service
+}
+
+// NewHandler creates a new template handler
+func NewHandler(templateService emailtemplate.Service) *Handler {
+	return &Handler{
+		templateService: templateService,
+	}
+}
+
+// create handles creating a template with its first version
+func (h *Handler) create(c *gin.Context) {
+	var req emailtemplate.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.UserID = c.GetString("userID")
+	resp, err := h.templateService.Create(c.Request.Context(), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// list handles listing the templates of the current user
+func (h *Handler) list(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	resp, err := h.templateService.List(c.Request.Context(), c.GetString("userID"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// get handles fetching a single template
+func (h *Handler) get(c *gin.Context) {
+	resp, err := h.templateService.Get(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// update handles changing the name and description of a template
+func (h *Handler) update(c *gin.Context) {
+	var req emailtemplate.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.templateService.Update(c.Request.Context(), c.GetString("userID"), c.Param("id"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// delete handles removing a template and its versions
+func (h *Handler) delete(c *gin.Context) {
+	if err := h.templateService.Delete(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// createVersion handles adding a new version to a template
+func (h *Handler) createVersion(c *gin.Context) {
+	var req emailtemplate.VersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.templateService.CreateVersion(c.Request.Context(), c.GetString("userID"), c.Param("id"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// listVersions handles listing every version of a template
+func (h *Handler) listVersions(c *gin.Context) {
+	resp, err := h.templateService.ListVersions(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": resp})
+}
+
+// getVersion handles fetching a single template version
+func (h *Handler) getVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	resp, err := h.templateService.GetVersion(c.Request.Context(), c.GetString("userID"), c.Param("id"), version)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// publish handles selecting the version used for sending
+func (h *Handler) publish(c *gin.Context) {
+	var req PublishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.templateService.Publish(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.Version)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// render handles previewing a template rendered with sample data
+func (h *Handler) render(c *gin.Context) {
+	var req emailtemplate.RenderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.templateService.Render(c.Request.Context(), c.GetString("userID"), c.Param("id"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// missingTranslations handles reporting the locales a version does not translate
+func (h *Handler) missingTranslations(c *gin.Context) {
+	version, err := strconv.Atoi(c.DefaultQuery("version", "0"))
+	if err != nil || version < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	resp, err := h.templateService.MissingTranslations(c.Request.Context(), c.GetString("userID"), c.Param("id"), version)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, emailtemplate.ErrInvalidTemplate), errors.Is(err, emailtemplate.ErrMissingVariable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, emailtemplate.ErrTemplateNotFound), errors.Is(err, emailtemplate.ErrVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,291 @@
+package emailtemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/logic/emailtemplate"
+	"GoMail/app/logic/emailtemplate/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_create(t *testing.T) {
+	validRequestBody := []byte(`{"name":"welcome","subject":"Hi {{.name}}","text":"Welcome {{.name}}"}`)
+
+	tests := []struct {
+		name               string
+		request            []byte
+		callLogic          bool
+		response           *emailtemplate.TemplateResponse
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			request:            validRequestBody,
+			callLogic:          true,
+			response:           &emailtemplate.TemplateResponse{ID: "abc", Name: "welcome", LatestVersion: 1, PublishedVersion: 1},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "malformed request",
+			request:            []byte(`{"name":`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid template",
+			request:            validRequestBody,
+			callLogic:          true,
+			err:                emailtemplate.ErrInvalidTemplate,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error in logic",
+			request:            validRequestBody,
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/templates", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Set("userID", "user-1")
+
+			templateService := &mocks.Service{}
+			if tt.callLogic {
+				templateService.On("Create", mock.Anything, mock.MatchedBy(func(req emailtemplate.CreateTemplateRequest) bool {
+					return req.UserID == "user-1" && req.Name == "welcome"
+				})).Return(tt.response, tt.err)
+			}
+
+			h := &Handler{
+				templateService: templateService,
+			}
+
+			// Act
+			h.create(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			templateService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_render(t *testing.T) {
+	tests := []struct {
+		name               string
+		response           *emailtemplate.Rendered
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			response:           &emailtemplate.Rendered{Subject: "Hi Ada", Text: "Welcome Ada", Version: 2},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "missing variable",
+			err:                fmt.Errorf("%w %q in text", emailtemplate.ErrMissingVariable, "name"),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "template not found",
+			err:                emailtemplate.ErrTemplateNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "version not found",
+			err:                emailtemplate.ErrVersionNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/templates/abc/render", bytes.NewBufferString(`{"version":2,"locale":"pt-BR","data":{"name":"Ada"}}`))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "abc"}}
+			c.Set("userID", "user-1")
+
+			templateService := &mocks.Service{}
+			templateService.On("Render", mock.Anything, "user-1", "abc", emailtemplate.RenderRequest{
+				Version: 2,
+				Locale:  "pt-BR",
+				Data:    map[string]interface{}{"name": "Ada"},
+			}).Return(tt.response, tt.err)
+
+			h := &Handler{
+				templateService: templateService,
+			}
+
+			// Act
+			h.render(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			templateService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_publish(t *testing.T) {
+	tests := []struct {
+		name               string
+		request            string
+		callLogic          bool
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			request:            `{"version":3}`,
+			callLogic:          true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "missing version",
+			request:            `{}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "unknown version",
+			request:            `{"version":3}`,
+			callLogic:          true,
+			err:                emailtemplate.ErrVersionNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/templates/abc/publish", bytes.NewBufferString(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "abc"}}
+			c.Set("userID", "user-1")
+
+			templateService := &mocks.Service{}
+			if tt.callLogic {
+				var resp *emailtemplate.TemplateResponse
+				if tt.err == nil {
+					resp = &emailtemplate.TemplateResponse{ID: "abc", PublishedVersion: 3}
+				}
+				templateService.On("Publish", mock.Anything, "user-1", "abc", 3).Return(resp, tt.err)
+			}
+
+			h := &Handler{
+				templateService: templateService,
+			}
+
+			// Act
+			h.publish(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			templateService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_getVersion_invalid(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/templates/abc/versions/latest", nil)
+	c.Params = gin.Params{{Key: "id", Value: "abc"}, {Key: "version", Value: "latest"}}
+
+	h := &Handler{templateService: &mocks.Service{}}
+	h.getVersion(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func Test_handler_missingTranslations(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		callLogic          bool
+		version            int
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "published version",
+			callLogic:          true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "explicit version",
+			query:              "?version=2",
+			callLogic:          true,
+			version:            2,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "invalid version",
+			query:              "?version=two",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "template not found",
+			callLogic:          true,
+			err:                emailtemplate.ErrTemplateNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request, _ = http.NewRequest("GET", "/templates/abc/missing-translations"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: "abc"}}
+			c.Set("userID", "user-1")
+
+			templateService := &mocks.Service{}
+			if tt.callLogic {
+				var resp *emailtemplate.MissingTranslationsResponse
+				if tt.err == nil {
+					resp = &emailtemplate.MissingTranslationsResponse{
+						Version: 2,
+						Missing: []emailtemplate.MissingTranslation{{Locale: "pt-BR", Fallback: "pt"}},
+					}
+				}
+				templateService.On("MissingTranslations", mock.Anything, "user-1", "abc", tt.version).Return(resp, tt.err)
+			}
+
+			h := &Handler{
+				templateService: templateService,
+			}
+
+			// Act
+			h.missingTranslations(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			templateService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package emailtemplate
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddProtectedRoute adds template routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	templateGroup := router.Group(path)
+	{
+		templateGroup.POST("", handler.create)
+		templateGroup.GET("", handler.list)
+		templateGroup.GET("/:id", handler.get)
+		templateGroup.PUT("/:id", handler.update)
+		templateGroup.DELETE("/:id", handler.delete)
+		templateGroup.POST("/:id/versions", handler.createVersion)
+		templateGroup.GET("/:id/versions", handler.listVersions)
+		templateGroup.GET("/:id/versions/:version", handler.getVersion)
+		templateGroup.POST("/:id/publish", handler.publish)
+		templateGroup.POST("/:id/render", handler.render)
+		templateGroup.GET("/:id/missing-translations", handler.missingTranslations)
+	}
+}
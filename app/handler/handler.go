@@ -1,8 +1,22 @@
 package handler
 
 import (
+	"GoMail/app/config"
+	"GoMail/app/handler/attachment"
 	"GoMail/app/handler/auth"
+	"GoMail/app/handler/bounce"
+	"GoMail/app/handler/download"
 	"GoMail/app/handler/email"
+	"GoMail/app/handler/emailtemplate"
+	"GoMail/app/handler/merge"
+	"GoMail/app/handler/recipient"
+	"GoMail/app/handler/schedule"
+	"GoMail/app/handler/suppression"
+	"GoMail/app/handler/topic"
+	"GoMail/app/handler/tracking"
+	"GoMail/app/handler/unsubscribe"
+	"GoMail/app/handler/webhook"
+	"GoMail/app/logic/idempotency"
 	"GoMail/app/middleware"
 	"GoMail/app/repository"
 
@@ -10,19 +24,45 @@ import (
 )
 
 // InitPublicRoutes initializes routes that don't require authentication
-func InitPublicRoutes(router *gin.Engine, emailHandler *email.Handler, repo repository.Repository) {
+func InitPublicRoutes(router *gin.Engine, emailHandler *email.Handler, downloadHandler *download.Handler, bounceHandler *bounce.Handler, trackingHandler *tracking.Handler, unsubscribeHandler *unsubscribe.Handler, recipientHandler *recipient.Handler, repo repository.Repository) {
 	api := router.Group("/api/v1")
 	
 	// Register public routes
 	auth.AddRoute(api, "/auth", repo)
 	email.AddPublicRoute(api, "/email", emailHandler)
+
+	// Mail servers post bounces and complaints with the ingest token
+	bounce.AddPublicRoute(api, "/bounces", bounceHandler)
+	
+	// Download links sent to recipients live outside the API
+	download.AddPublicRoute(&router.RouterGroup, "/files", downloadHandler)
+
+	// Tracking pixels and tracked links are loaded by email clients
+	tracking.AddPublicRoute(&router.RouterGroup, "/t", trackingHandler)
+
+	// Unsubscribe links are followed by recipients and mail providers
+	unsubscribe.AddPublicRoute(&router.RouterGroup, "/u", unsubscribeHandler)
+
+	// Recipients choose the topics they receive on the preference page
+	recipient.AddPublicRoute(&router.RouterGroup, "/p", recipientHandler)
 }
 
 // InitProtectedRoutes initializes routes that require authentication
-func InitProtectedRoutes(router *gin.Engine, emailHandler *email.Handler) {
+func InitProtectedRoutes(router *gin.Engine, emailHandler *email.Handler, scheduleHandler *schedule.Handler, templateHandler *emailtemplate.Handler, recipientHandler *recipient.Handler, attachmentHandler *attachment.Handler, downloadHandler *download.Handler, suppressionHandler *suppression.Handler, trackingHandler *tracking.Handler, webhookHandler *webhook.Handler, topicHandler *topic.Handler, mergeHandler *merge.Handler, idempotencyService idempotency.Service, cfg *config.Config) {
 	api := router.Group("/api/v1")
 	api.Use(middleware.VerifyAuthToken())
 	
 	// Register protected routes
-	email.AddProtectedRoute(api, "/email", emailHandler)
+	// Sends may be retried with an Idempotency-Key header
+	email.AddProtectedRoute(api.Group("", middleware.Idempotency(idempotencyService, cfg)), "/email", emailHandler)
+	schedule.AddProtectedRoute(api, "/schedules", scheduleHandler)
+	emailtemplate.AddProtectedRoute(api, "/templates", templateHandler)
+	recipient.AddProtectedRoute(api, "/recipients", recipientHandler)
+	attachment.AddProtectedRoute(api, "/attachments", attachmentHandler)
+	download.AddProtectedRoute(api, "/files", downloadHandler)
+	suppression.AddProtectedRoute(api, "/suppressions", suppressionHandler)
+	tracking.AddProtectedRoute(api, "/tracking", trackingHandler)
+	webhook.AddProtectedRoute(api, "/webhooks", webhookHandler)
+	topic.AddProtectedRoute(api, "/topics", topicHandler)
+	merge.AddProtectedRoute(api, "/merges", mergeHandler)
 }
\ No newline at end of file
@@ -0,0 +1,182 @@
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/merge"
+	"GoMail/app/repository/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	// uploadMemory is how much of an upload is kept in memory, the rest of
+	// the recipient list is spooled to a temporary file
+	uploadMemory = 1 << 20
+
+	// uploadFieldsSize allows for the message field and multipart framing
+	// of an upload on top of the recipient list limit
+	uploadFieldsSize = 1 << 20
+)
+
+// Handler handles mail merge HTTP requests
+type Handler struct {
+	mergeService  merge.Service
+	maxUploadSize int64
+}
+
+// NewHandler creates a new mail merge handler
+func NewHandler(mergeService merge.Service, cfg *config.Config) *Handler {
+	return &Handler{
+		mergeService:  mergeService,
+		maxUploadSize: cfg.Merge.MaxUploadSize,
+	}
+}
+
+// create handles starting a mail merge. The multipart/form-data upload
+// carries the message as JSON in the "message" field and the recipient
+// list in the "recipients" file, as CSV or JSON lines.
+func (h *Handler) create(c *gin.Context) {
+	if h.maxUploadSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize+uploadFieldsSize)
+	}
+	if err := c.Request.ParseMultipartForm(uploadMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("the upload exceeds %d bytes", tooLarge.Limit)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req merge.CreateRequest
+	if err := binding.JSON.BindBody([]byte(c.PostForm("message")), &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid message: " + err.Error()})
+		return
+	}
+	req.UserID = c.GetString("userID")
+
+	file, header, err := c.Request.FormFile("recipients")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a recipients file is required"})
+		return
+	}
+	defer file.Close()
+	req.RecipientsFormat = recipientsFormat(header.Filename, header.Header.Get("Content-Type"))
+
+	resp, err := h.mergeService.Create(c.Request.Context(), req, file)
+	if err != nil {
+		if resp != nil && errors.Is(err, merge.ErrInvalidMerge) {
+			// Report the rows that failed validation with the error
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   err.Error(),
+				"total":   resp.Total,
+				"valid":   resp.Valid,
+				"invalid": resp.Invalid,
+				"errors":  resp.Errors,
+			})
+			return
+		}
+		writeError(c, err)
+		return
+	}
+
+	if resp.Job == nil {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// recipientsFormat tells the format of an uploaded recipient list from its
+// file extension or content type. It is left to the service to detect
+// when neither is known.
+func recipientsFormat(filename, contentType string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return merge.FormatCSV
+	case ".jsonl", ".ndjson":
+		return merge.FormatJSONLines
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return merge.FormatCSV
+	case strings.HasPrefix(contentType, "application/jsonl"), strings.HasPrefix(contentType, "application/x-ndjson"):
+		return merge.FormatJSONLines
+	}
+	return ""
+}
+
+// list handles listing the mail merge jobs of the user
+func (h *Handler) list(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	resp, err := h.mergeService.List(c.Request.Context(), c.GetString("userID"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// get handles fetching a mail merge job and its progress
+func (h *Handler) get(c *gin.Context) {
+	resp, err := h.mergeService.Get(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// rows handles listing the recipients of a mail merge job
+func (h *Handler) rows(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	status := models.MergeRowStatus(c.Query("status"))
+
+	resp, err := h.mergeService.Rows(c.Request.Context(), c.GetString("userID"), c.Param("id"), status, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// cancel handles stopping a mail merge job
+func (h *Handler) cancel(c *gin.Context) {
+	resp, err := h.mergeService.Cancel(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, merge.ErrInvalidMerge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, merge.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, merge.ErrJobFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,230 @@
+package merge
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/logic/merge"
+	"GoMail/app/logic/merge/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mergeUpload builds a form with a message field and a recipients file,
+// leaving out the parts that are empty
+func mergeUpload(t *testing.T, message, filename, recipients string) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if message != "" {
+		require.NoError(t, form.WriteField("message", message))
+	}
+	if filename != "" {
+		part, err := form.CreateFormFile("recipients", filename)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(recipients))
+		require.NoError(t, err)
+	}
+	require.NoError(t, form.Close())
+	return &body, form.FormDataContentType()
+}
+
+func Test_handler_create(t *testing.T) {
+	const message = `{"from":"app@example.com","subject":"Hi {{.name}}","body":"Hello"}`
+	const recipients = "email,name\nada@example.org,Ada\n"
+
+	tests := []struct {
+		name               string
+		message            string
+		filename           string
+		callLogic          bool
+		resp               *merge.CreateResponse
+		err                error
+		expectedStatusCode int
+		expectedFormat     string
+	}{
+		{
+			name:               "happy path",
+			message:            message,
+			filename:           "list.csv",
+			callLogic:          true,
+			resp:               &merge.CreateResponse{Job: &merge.JobResponse{ID: "job-1"}, Total: 1, Valid: 1},
+			expectedStatusCode: http.StatusAccepted,
+			expectedFormat:     merge.FormatCSV,
+		},
+		{
+			name:               "dry run",
+			message:            message,
+			filename:           "list.ndjson",
+			callLogic:          true,
+			resp:               &merge.CreateResponse{Total: 1, Valid: 1},
+			expectedStatusCode: http.StatusOK,
+			expectedFormat:     merge.FormatJSONLines,
+		},
+		{
+			name:               "invalid rows",
+			message:            message,
+			filename:           "list",
+			callLogic:          true,
+			resp:               &merge.CreateResponse{Total: 1, Invalid: 1, Errors: []merge.RowError{{Row: 1, Error: "invalid"}}},
+			err:                fmt.Errorf("%w: 1 of 1 rows are invalid", merge.ErrInvalidMerge),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "missing from",
+			message:            `{"subject":"Hi"}`,
+			filename:           "list.csv",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "missing recipients",
+			message:            message,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error in logic",
+			message:            message,
+			filename:           "list.csv",
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedFormat:     merge.FormatCSV,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			body, contentType := mergeUpload(t, tt.message, tt.filename, recipients)
+			c.Request, _ = http.NewRequest("POST", "/merges", body)
+			c.Request.Header.Set("Content-Type", contentType)
+			c.Set("userID", "user-1")
+
+			mergeService := &mocks.Service{}
+			if tt.callLogic {
+				mergeService.On("Create", mock.Anything, mock.MatchedBy(func(req merge.CreateRequest) bool {
+					return req.UserID == "user-1" && req.From == "app@example.com" && req.RecipientsFormat == tt.expectedFormat
+				}), mock.Anything).Return(tt.resp, tt.err)
+			}
+
+			h := &Handler{
+				mergeService:  mergeService,
+				maxUploadSize: 1 << 20,
+			}
+
+			// Act
+			h.create(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code, w.Body.String())
+			mergeService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_create_TooLarge(t *testing.T) {
+	// Assemble
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	body, contentType := mergeUpload(t, `{"from":"app@example.com"}`, "list.csv", string(bytes.Repeat([]byte("a"), 3<<20)))
+	c.Request, _ = http.NewRequest("POST", "/merges", body)
+	c.Request.Header.Set("Content-Type", contentType)
+
+	h := &Handler{
+		mergeService:  &mocks.Service{},
+		maxUploadSize: 1 << 20,
+	}
+
+	// Act
+	h.create(c)
+
+	// Assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func Test_handler_cancel(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not found",
+			err:                merge.ErrJobNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "already finished",
+			err:                merge.ErrJobFinished,
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/merges/job-1/cancel", nil)
+			c.Params = gin.Params{{Key: "id", Value: "job-1"}}
+			c.Set("userID", "user-1")
+
+			var resp *merge.JobResponse
+			if tt.err == nil {
+				resp = &merge.JobResponse{ID: "job-1"}
+			}
+			mergeService := &mocks.Service{}
+			mergeService.On("Cancel", mock.Anything, "user-1", "job-1").Return(resp, tt.err)
+
+			h := &Handler{
+				mergeService: mergeService,
+			}
+
+			// Act
+			h.cancel(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mergeService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_rows(t *testing.T) {
+	// Assemble
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/merges/job-1/rows?status=failed&page=2", nil)
+	c.Params = gin.Params{{Key: "id", Value: "job-1"}}
+	c.Set("userID", "user-1")
+
+	mergeService := &mocks.Service{}
+	mergeService.On("Rows", mock.Anything, "user-1", "job-1", models.MergeRowFailed, 2, 20).
+		Return(&merge.ListRowsResponse{Rows: []merge.RowResponse{}}, nil)
+
+	h := &Handler{
+		mergeService: mergeService,
+	}
+
+	// Act
+	h.rows(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mergeService.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package merge
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddProtectedRoute adds mail merge routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	mergeGroup := router.Group(path)
+	{
+		mergeGroup.GET("", handler.list)
+		mergeGroup.POST("", handler.create)
+		mergeGroup.GET("/:id", handler.get)
+		mergeGroup.GET("/:id/rows", handler.rows)
+		mergeGroup.POST("/:id/cancel", handler.cancel)
+	}
+}
@@ -0,0 +1,58 @@
+package recipient
+
+import (
+	"html/template"
+
+	"GoMail/app/logic/recipient"
+)
+
+// page is the preference page recipients see. Transactional topics are
+// listed but can't be unchecked.
+var page = template.Must(template.New("preferences").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Email preferences</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;background:#f4f4f5;color:#18181b;margin:0;padding:48px 16px}
+main{max-width:480px;margin:0 auto;background:#fff;border-radius:8px;padding:32px}
+h1{font-size:22px;margin:0 0 16px}
+label{display:block;margin:0 0 16px}
+small{display:block;color:#71717a;margin-left:24px}
+button{background:#18181b;color:#fff;border:0;border-radius:6px;padding:10px 20px;font-size:15px;cursor:pointer}
+</style>
+</head>
+<body>
+<main>
+{{- if .Error}}
+<h1>Link not valid</h1>
+<p>{{.Error}}</p>
+{{- else}}
+<h1>Email preferences</h1>
+<p>Choose the emails {{.Email}} receives.</p>
+{{- if .Saved}}
+<p><strong>Your preferences are saved.</strong></p>
+{{- end}}
+<form method="post">
+{{- range .Topics}}
+<label><input type="checkbox" name="topic" value="{{.Name}}"{{if .Subscribed}} checked{{end}}{{if .Transactional}} disabled{{end}}> {{.Label}}
+{{- if .Transactional}}<small>Always sent, since it concerns your account.</small>{{end}}
+{{- if .Description}}<small>{{.Description}}</small>{{end}}</label>
+{{- end}}
+<button type="submit">Save</button>
+</form>
+{{- end}}
+</main>
+</body>
+</html>
+`))
+
+// pageData is what the page shows
+type pageData struct {
+	Email  string
+	Topics []recipient.TopicPreference
+	Saved  bool
+	Error  string
+}
@@ -0,0 +1,63 @@
+package recipient
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"GoMail/app/logic/recipient"
+
+	"github.com/gin-gonic/gin"
+)
+
+// preferencesURL handles fetching the preference page address of a
+// recipient, to link to from emails
+func (h *Handler) preferencesURL(c *gin.Context) {
+	c.JSON(http.StatusOK, recipient.PreferencesURLResponse{
+		URL: h.recipientService.PreferencesURL(c.GetString("userID"), c.Param("email")),
+	})
+}
+
+// preferences handles a followed preference page link
+func (h *Handler) preferences(c *gin.Context) {
+	resp, err := h.recipientService.GetPreferences(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writePageError(c, err)
+		return
+	}
+
+	render(c, http.StatusOK, pageData{Email: resp.Email, Topics: resp.Topics})
+}
+
+// updatePreferences handles the form of the preference page, which posts
+// the checked topics
+func (h *Handler) updatePreferences(c *gin.Context) {
+	resp, err := h.recipientService.UpdatePreferences(c.Request.Context(), c.Param("token"), c.PostFormArray("topic"))
+	if err != nil {
+		writePageError(c, err)
+		return
+	}
+
+	render(c, http.StatusOK, pageData{Email: resp.Email, Topics: resp.Topics, Saved: true})
+}
+
+// render writes the page, which nothing may cache
+func render(c *gin.Context, status int, data pageData) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := page.Execute(c.Writer, data); err != nil {
+		log.Printf("Failed to render preference page: %v", err)
+	}
+}
+
+// writePageError maps service errors to pages
+func writePageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, recipient.ErrInvalidToken):
+		render(c, http.StatusNotFound, pageData{Error: "This preference link is invalid."})
+	default:
+		log.Printf("Failed to manage preferences: %v", err)
+		render(c, http.StatusInternalServerError, pageData{Error: "Something went wrong, please try again later."})
+	}
+}
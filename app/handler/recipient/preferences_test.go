@@ -0,0 +1,100 @@
+package recipient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"GoMail/app/logic/recipient"
+	"GoMail/app/logic/recipient/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_preferences(t *testing.T) {
+	tests := []struct {
+		name               string
+		resp               *recipient.PreferencesResponse
+		err                error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "happy path",
+			resp: &recipient.PreferencesResponse{Email: "ada@example.org", Topics: []recipient.TopicPreference{
+				{Name: "billing", Label: "Billing", Transactional: true, Subscribed: true},
+				{Name: "news", Label: "News"},
+			}},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `<input type="checkbox" name="topic" value="billing" checked disabled>`,
+		},
+		{
+			name:               "invalid token",
+			err:                recipient.ErrInvalidToken,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "invalid",
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       "try again later",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("GET", "/p/token", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "token", Value: "token"}}
+
+			recipientService := &mocks.Service{}
+			recipientService.On("GetPreferences", mock.Anything, "token").Return(tt.resp, tt.err)
+
+			h := &Handler{recipientService: recipientService}
+
+			// Act
+			h.preferences(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			recipientService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_updatePreferences(t *testing.T) {
+	// Assemble
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	form := url.Values{"topic": {"news", "product-updates"}}
+	r, _ := http.NewRequest("POST", "/p/token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	c.Request = r
+	c.Params = gin.Params{{Key: "token", Value: "token"}}
+
+	recipientService := &mocks.Service{}
+	recipientService.On("UpdatePreferences", mock.Anything, "token", []string{"news", "product-updates"}).
+		Return(&recipient.PreferencesResponse{Email: "ada@example.org"}, nil)
+
+	h := &Handler{recipientService: recipientService}
+
+	// Act
+	h.updatePreferences(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Your preferences are saved.")
+	recipientService.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+package recipient
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"GoMail/app/logic/recipient"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles recipient preference HTTP requests
+type Handler struct {
+	recipientService recipient.Service
+}
+
+// NewHandler creates a new recipient handler
+func NewHandler(recipientService recipient.Service) *Handler {
+	return &Handler{
+		recipientService: recipientService,
+	}
+}
+
+// list handles listing the recipients with stored preferences
+func (h *Handler) list(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	resp, err := h.recipientService.List(c.Request.Context(), c.GetString("userID"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// get handles fetching the preferences of a recipient
+func (h *Handler) get(c *gin.Context) {
+	resp, err := h.recipientService.Get(c.Request.Context(), c.GetString("userID"), c.Param("email"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// update handles storing the preferences of a recipient
+func (h *Handler) update(c *gin.Context) {
+	var req recipient.PreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.recipientService.Update(c.Request.Context(), c.GetString("userID"), c.Param("email"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// delete handles removing the preferences of a recipient
+func (h *Handler) delete(c *gin.Context) {
+	if err := h.recipientService.Delete(c.Request.Context(), c.GetString("userID"), c.Param("email")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, recipient.ErrInvalidRecipient):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, recipient.ErrRecipientNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
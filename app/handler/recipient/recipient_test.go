@@ -0,0 +1,130 @@
+package recipient
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/logic/recipient"
+	"GoMail/app/logic/recipient/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_update(t *testing.T) {
+	tests := []struct {
+		name               string
+		request            []byte
+		callLogic          bool
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			request:            []byte(`{"locale":"pt-BR"}`),
+			callLogic:          true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "malformed request",
+			request:            []byte(`{"locale":`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid locale",
+			request:            []byte(`{"locale":"pt-BR"}`),
+			callLogic:          true,
+			err:                recipient.ErrInvalidRecipient,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error in logic",
+			request:            []byte(`{"locale":"pt-BR"}`),
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("PUT", "/recipients/ana@example.com", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Params = gin.Params{{Key: "email", Value: "ana@example.com"}}
+			c.Set("userID", "user-1")
+
+			recipientService := &mocks.Service{}
+			if tt.callLogic {
+				var resp *recipient.RecipientResponse
+				if tt.err == nil {
+					resp = &recipient.RecipientResponse{Email: "ana@example.com", Locale: "pt-BR"}
+				}
+				recipientService.On("Update", mock.Anything, "user-1", "ana@example.com", recipient.PreferenceRequest{Locale: "pt-BR"}).Return(resp, tt.err)
+			}
+
+			h := &Handler{
+				recipientService: recipientService,
+			}
+
+			// Act
+			h.update(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			recipientService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_get(t *testing.T) {
+	tests := []struct {
+		name               string
+		response           *recipient.RecipientResponse
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			response:           &recipient.RecipientResponse{Email: "ana@example.com", Locale: "pt-BR"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not found",
+			err:                recipient.ErrRecipientNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request, _ = http.NewRequest("GET", "/recipients/ana@example.com", nil)
+			c.Params = gin.Params{{Key: "email", Value: "ana@example.com"}}
+			c.Set("userID", "user-1")
+
+			recipientService := &mocks.Service{}
+			recipientService.On("Get", mock.Anything, "user-1", "ana@example.com").Return(tt.response, tt.err)
+
+			h := &Handler{
+				recipientService: recipientService,
+			}
+
+			// Act
+			h.get(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			recipientService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package recipient
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddProtectedRoute adds recipient preference routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	recipientGroup := router.Group(path)
+	{
+		recipientGroup.GET("", handler.list)
+		recipientGroup.GET("/:email", handler.get)
+		recipientGroup.PUT("/:email", handler.update)
+		recipientGroup.DELETE("/:email", handler.delete)
+		recipientGroup.GET("/:email/preferences-url", handler.preferencesURL)
+	}
+}
+
+// AddPublicRoute adds the preference page routes recipients follow from
+// their emails
+func AddPublicRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	preferenceGroup := router.Group(path)
+	{
+		preferenceGroup.GET("/:token", handler.preferences)
+		preferenceGroup.POST("/:token", handler.updatePreferences)
+	}
+}
@@ -0,0 +1,22 @@
+package schedule
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddProtectedRoute adds schedule routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	scheduleGroup := router.Group(path)
+	{
+		scheduleGroup.POST("", handler.create)
+		scheduleGroup.GET("", handler.list)
+		scheduleGroup.POST("/preview", handler.preview)
+		scheduleGroup.GET("/:id", handler.get)
+		scheduleGroup.PUT("/:id", handler.update)
+		scheduleGroup.DELETE("/:id", handler.delete)
+		scheduleGroup.POST("/:id/pause", handler.pause)
+		scheduleGroup.POST("/:id/resume", handler.resume)
+		scheduleGroup.GET("/:id/next-runs", handler.nextRuns)
+		scheduleGroup.GET("/:id/runs", handler.runs)
+	}
+}
@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"GoMail/app/logic/schedule"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles recurring schedule HTTP requests
+type Handler struct {
+	scheduleService schedule.Service
+}
+
+// NewHandler creates a new schedule handler
+func NewHandler(scheduleService schedule.Service) *Handler {
+	return &Handler{
+		scheduleService: scheduleService,
+	}
+}
+
+// create handles creating a recurring schedule
+func (h *Handler) create(c *gin.Context) {
+	var req schedule.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.UserID = c.GetString("userID")
+	resp, err := h.scheduleService.Create(c.Request.Context(), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// list handles listing the schedules of the current user
+func (h *Handler) list(c *gin.Context) {
+	page, limit := pagination(c)
+
+	resp, err := h.scheduleService.List(c.Request.Context(), c.GetString("userID"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// get handles fetching a single schedule
+func (h *Handler) get(c *gin.Context) {
+	resp, err := h.scheduleService.Get(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// update handles replacing a schedule definition
+func (h *Handler) update(c *gin.Context) {
+	var req schedule.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.UserID = c.GetString("userID")
+	resp, err := h.scheduleService.Update(c.Request.Context(), req.UserID, c.Param("id"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// delete handles removing a schedule
+func (h *Handler) delete(c *gin.Context) {
+	if err := h.scheduleService.Delete(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// pause handles suspending a schedule
+func (h *Handler) pause(c *gin.Context) {
+	resp, err := h.scheduleService.Pause(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// resume handles reactivating a paused schedule
+func (h *Handler) resume(c *gin.Context) {
+	resp, err := h.scheduleService.Resume(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// nextRuns handles previewing the upcoming runs of a schedule
+func (h *Handler) nextRuns(c *gin.Context) {
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "5"))
+
+	resp, err := h.scheduleService.NextRuns(c.Request.Context(), c.GetString("userID"), c.Param("id"), count)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// preview handles previewing the runs of a cron expression before saving it
+func (h *Handler) preview(c *gin.Context) {
+	var req schedule.PreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.scheduleService.Preview(c.Request.Context(), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// runs handles listing the run history of a schedule
+func (h *Handler) runs(c *gin.Context) {
+	page, limit := pagination(c)
+
+	resp, err := h.scheduleService.Runs(c.Request.Context(), c.GetString("userID"), c.Param("id"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, schedule.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, schedule.ErrScheduleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// pagination reads the page and limit query parameters
+func pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	return page, limit
+}
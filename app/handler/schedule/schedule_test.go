@@ -0,0 +1,166 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/logic/schedule"
+	"GoMail/app/logic/schedule/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_create(t *testing.T) {
+	validRequestBody := []byte(`{"name":"Weekly report","cronExpression":"0 9 * * MON","timeZone":"UTC","message":{"from":"a@example.com","subject":"s","body":"b"},"recipients":["ops@example.com"]}`)
+
+	tests := []struct {
+		name               string
+		request            []byte
+		callLogic          bool
+		response           *schedule.ScheduleResponse
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			request:            validRequestBody,
+			callLogic:          true,
+			response:           &schedule.ScheduleResponse{ID: "abc", Name: "Weekly report", Status: "active"},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "malformed request",
+			request:            []byte(`{"name":`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid schedule",
+			request:            validRequestBody,
+			callLogic:          true,
+			err:                schedule.ErrInvalidSchedule,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error in logic",
+			request:            validRequestBody,
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/schedules", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Set("userID", "user-1")
+
+			scheduleService := &mocks.Service{}
+			if tt.callLogic {
+				scheduleService.On("Create", mock.Anything, mock.MatchedBy(func(req schedule.ScheduleRequest) bool {
+					return req.UserID == "user-1" && req.CronExpression == "0 9 * * MON"
+				})).Return(tt.response, tt.err)
+			}
+
+			h := &Handler{
+				scheduleService: scheduleService,
+			}
+
+			h.create(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.response != nil {
+				var response schedule.ScheduleResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, tt.response, &response)
+			}
+
+			scheduleService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_pause(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not found",
+			err:                schedule.ErrScheduleNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/schedules/abc/pause", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "abc"}}
+			c.Set("userID", "user-1")
+
+			var resp *schedule.ScheduleResponse
+			if tt.err == nil {
+				resp = &schedule.ScheduleResponse{ID: "abc", Status: "paused"}
+			}
+			scheduleService := &mocks.Service{}
+			scheduleService.On("Pause", mock.Anything, "user-1", "abc").Return(resp, tt.err)
+
+			h := &Handler{
+				scheduleService: scheduleService,
+			}
+
+			h.pause(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			scheduleService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_nextRuns(t *testing.T) {
+	// Assemble
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	r, _ := http.NewRequest("GET", "/schedules/abc/next-runs?count=3", nil)
+	c.Request = r
+	c.Params = gin.Params{{Key: "id", Value: "abc"}}
+	c.Set("userID", "user-1")
+
+	scheduleService := &mocks.Service{}
+	scheduleService.On("NextRuns", mock.Anything, "user-1", "abc", 3).Return(&schedule.NextRunsResponse{}, nil)
+
+	h := &Handler{
+		scheduleService: scheduleService,
+	}
+
+	h.nextRuns(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	scheduleService.AssertExpectations(t)
+}
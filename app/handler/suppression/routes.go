@@ -0,0 +1,18 @@
+package suppression
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddProtectedRoute adds suppression list routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	suppressionGroup := router.Group(path)
+	{
+		suppressionGroup.GET("", handler.list)
+		suppressionGroup.POST("", handler.create)
+		suppressionGroup.POST("/import", handler.importSuppressions)
+		suppressionGroup.GET("/:id", handler.get)
+		suppressionGroup.PUT("/:id", handler.update)
+		suppressionGroup.DELETE("/:id", handler.delete)
+	}
+}
@@ -0,0 +1,138 @@
+package suppression
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"GoMail/app/logic/suppression"
+	"GoMail/app/repository/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles suppression list HTTP requests
+type Handler struct {
+	suppressionService suppression.Service
+}
+
+// NewHandler creates a new suppression handler
+func NewHandler(suppressionService suppression.Service) *Handler {
+	return &Handler{
+		suppressionService: suppressionService,
+	}
+}
+
+// list handles listing the suppressions of a scope
+func (h *Handler) list(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	filter := suppression.ListFilter{
+		Scope:  models.SuppressionScope(c.Query("scope")),
+		Reason: models.SuppressionReason(c.Query("reason")),
+		Value:  c.Query("value"),
+	}
+
+	resp, err := h.suppressionService.List(c.Request.Context(), c.GetString("userID"), filter, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// create handles suppressing an address or domain
+func (h *Handler) create(c *gin.Context) {
+	var req suppression.SuppressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.suppressionService.Create(c.Request.Context(), c.GetString("userID"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// get handles fetching a suppression
+func (h *Handler) get(c *gin.Context) {
+	resp, err := h.suppressionService.Get(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// update handles changing a suppression
+func (h *Handler) update(c *gin.Context) {
+	var req suppression.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.suppressionService.Update(c.Request.Context(), c.GetString("userID"), c.Param("id"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// delete handles lifting a suppression
+func (h *Handler) delete(c *gin.Context) {
+	if err := h.suppressionService.Delete(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// importSuppressions handles bulk imports, either a JSON list of entries or
+// a text/csv body with a header row
+func (h *Handler) importSuppressions(c *gin.Context) {
+	var resp *suppression.ImportResponse
+	var err error
+	if strings.HasPrefix(c.ContentType(), "text/csv") {
+		resp, err = h.suppressionService.ImportCSV(c.Request.Context(), c.GetString("userID"), c.Request.Body)
+	} else {
+		var req suppression.ImportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		resp, err = h.suppressionService.Import(c.Request.Context(), c.GetString("userID"), req.Entries)
+	}
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, suppression.ErrInvalidSuppression):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, suppression.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, suppression.ErrSuppressionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, suppression.ErrSuppressionExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,223 @@
+package suppression
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/logic/suppression"
+	"GoMail/app/logic/suppression/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_create(t *testing.T) {
+	tests := []struct {
+		name               string
+		request            []byte
+		callLogic          bool
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			request:            []byte(`{"address":"ana@example.com","reason":"complaint"}`),
+			callLogic:          true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "malformed request",
+			request:            []byte(`{"address":`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid address",
+			request:            []byte(`{"address":"ana@example.com","reason":"complaint"}`),
+			callLogic:          true,
+			err:                suppression.ErrInvalidSuppression,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "global without admin",
+			request:            []byte(`{"address":"ana@example.com","reason":"complaint"}`),
+			callLogic:          true,
+			err:                suppression.ErrForbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "already suppressed",
+			request:            []byte(`{"address":"ana@example.com","reason":"complaint"}`),
+			callLogic:          true,
+			err:                suppression.ErrSuppressionExists,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "error in logic",
+			request:            []byte(`{"address":"ana@example.com","reason":"complaint"}`),
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/suppressions", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Set("userID", "user-1")
+
+			suppressionService := &mocks.Service{}
+			if tt.callLogic {
+				var resp *suppression.SuppressionResponse
+				if tt.err == nil {
+					resp = &suppression.SuppressionResponse{Value: "ana@example.com", Reason: models.SuppressionReasonComplaint}
+				}
+				suppressionService.On("Create", mock.Anything, "user-1", suppression.SuppressionRequest{Address: "ana@example.com", Reason: models.SuppressionReasonComplaint}).Return(resp, tt.err)
+			}
+
+			h := &Handler{
+				suppressionService: suppressionService,
+			}
+
+			// Act
+			h.create(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			suppressionService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_importSuppressions(t *testing.T) {
+	tests := []struct {
+		name               string
+		contentType        string
+		request            []byte
+		method             string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "json entries",
+			contentType:        "application/json",
+			request:            []byte(`{"entries":[{"domain":"example.com"}]}`),
+			method:             "Import",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "csv body",
+			contentType:        "text/csv; charset=utf-8",
+			request:            []byte("address\nana@example.com\n"),
+			method:             "ImportCSV",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "malformed json",
+			contentType:        "application/json",
+			request:            []byte(`{"entries":`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "too many entries",
+			contentType:        "text/csv",
+			request:            []byte("address\n"),
+			method:             "ImportCSV",
+			err:                suppression.ErrInvalidSuppression,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/suppressions/import", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", tt.contentType)
+			c.Request = r
+			c.Set("userID", "user-1")
+
+			var resp *suppression.ImportResponse
+			if tt.err == nil {
+				resp = &suppression.ImportResponse{Created: 1, Errors: []suppression.ImportError{}}
+			}
+			suppressionService := &mocks.Service{}
+			switch tt.method {
+			case "Import":
+				suppressionService.On("Import", mock.Anything, "user-1", []suppression.SuppressionRequest{{Domain: "example.com"}}).Return(resp, tt.err)
+			case "ImportCSV":
+				suppressionService.On("ImportCSV", mock.Anything, "user-1", mock.Anything).Return(resp, tt.err)
+			}
+
+			h := &Handler{
+				suppressionService: suppressionService,
+			}
+
+			// Act
+			h.importSuppressions(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			suppressionService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_delete(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not found",
+			err:                suppression.ErrSuppressionNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("DELETE", "/suppressions/sup-1", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "sup-1"}}
+			c.Set("userID", "user-1")
+
+			suppressionService := &mocks.Service{}
+			suppressionService.On("Delete", mock.Anything, "user-1", "sup-1").Return(tt.err)
+
+			h := &Handler{
+				suppressionService: suppressionService,
+			}
+
+			// Act
+			h.delete(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			suppressionService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package topic
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddProtectedRoute adds topic routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	topicGroup := router.Group(path)
+	{
+		topicGroup.GET("", handler.list)
+		topicGroup.POST("", handler.create)
+		topicGroup.GET("/:name", handler.get)
+		topicGroup.PUT("/:name", handler.update)
+		topicGroup.DELETE("/:name", handler.delete)
+	}
+}
@@ -0,0 +1,102 @@
+package topic
+
+import (
+	"errors"
+	"net/http"
+
+	"GoMail/app/logic/topic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles topic HTTP requests
+type Handler struct {
+	topicService topic.Service
+}
+
+// NewHandler creates a new topic handler
+func NewHandler(topicService topic.Service) *Handler {
+	return &Handler{
+		topicService: topicService,
+	}
+}
+
+// list handles listing the topics of the user
+func (h *Handler) list(c *gin.Context) {
+	resp, err := h.topicService.List(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// create handles adding a topic
+func (h *Handler) create(c *gin.Context) {
+	var req topic.TopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.topicService.Create(c.Request.Context(), c.GetString("userID"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// get handles fetching a topic
+func (h *Handler) get(c *gin.Context) {
+	resp, err := h.topicService.Get(c.Request.Context(), c.GetString("userID"), c.Param("name"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// update handles changing a topic
+func (h *Handler) update(c *gin.Context) {
+	var req topic.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.topicService.Update(c.Request.Context(), c.GetString("userID"), c.Param("name"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// delete handles removing a topic
+func (h *Handler) delete(c *gin.Context) {
+	if err := h.topicService.Delete(c.Request.Context(), c.GetString("userID"), c.Param("name")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, topic.ErrInvalidTopic):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, topic.ErrTopicNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, topic.ErrTopicExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
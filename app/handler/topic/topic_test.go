@@ -0,0 +1,128 @@
+package topic
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/logic/topic"
+	"GoMail/app/logic/topic/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_create(t *testing.T) {
+	tests := []struct {
+		name               string
+		request            []byte
+		callLogic          bool
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			request:            []byte(`{"name":"billing","label":"Billing","transactional":true}`),
+			callLogic:          true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "missing name",
+			request:            []byte(`{"label":"Billing"}`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid topic",
+			request:            []byte(`{"name":"billing","label":"Billing","transactional":true}`),
+			callLogic:          true,
+			err:                topic.ErrInvalidTopic,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "topic exists",
+			request:            []byte(`{"name":"billing","label":"Billing","transactional":true}`),
+			callLogic:          true,
+			err:                topic.ErrTopicExists,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "error in logic",
+			request:            []byte(`{"name":"billing","label":"Billing","transactional":true}`),
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/topics", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Set("userID", "user-1")
+
+			topicService := &mocks.Service{}
+			if tt.callLogic {
+				var resp *topic.TopicResponse
+				if tt.err == nil {
+					resp = &topic.TopicResponse{Name: "billing", Label: "Billing", Transactional: true}
+				}
+				topicService.On("Create", mock.Anything, "user-1", topic.TopicRequest{
+					Name:          "billing",
+					Label:         "Billing",
+					Transactional: true,
+				}).Return(resp, tt.err)
+			}
+
+			h := &Handler{topicService: topicService}
+
+			// Act
+			h.create(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			topicService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_delete(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{name: "happy path", expectedStatusCode: http.StatusOK},
+		{name: "not found", err: topic.ErrTopicNotFound, expectedStatusCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("DELETE", "/topics/news", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "name", Value: "news"}}
+			c.Set("userID", "user-1")
+
+			topicService := &mocks.Service{}
+			topicService.On("Delete", mock.Anything, "user-1", "news").Return(tt.err)
+
+			h := &Handler{topicService: topicService}
+
+			// Act
+			h.delete(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			topicService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package tracking
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddPublicRoute adds the routes email clients load tracking pixels and
+// follow tracked links from
+func AddPublicRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	trackingGroup := router.Group(path)
+	{
+		trackingGroup.GET("/o/:token", handler.open)
+		trackingGroup.GET("/c/:token", handler.click)
+	}
+}
+
+// AddProtectedRoute adds tracking report routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	trackingGroup := router.Group(path)
+	{
+		trackingGroup.GET("/events", handler.listEvents)
+		trackingGroup.GET("/messages/:messageId", handler.report)
+	}
+}
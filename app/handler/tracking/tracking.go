@@ -0,0 +1,108 @@
+package tracking
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"GoMail/app/logic/tracking"
+	"GoMail/app/repository/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pixel is a transparent 1x1 GIF
+var pixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// Handler handles tracking HTTP requests
+type Handler struct {
+	trackingService tracking.Service
+}
+
+// NewHandler creates a new tracking handler
+func NewHandler(trackingService tracking.Service) *Handler {
+	return &Handler{
+		trackingService: trackingService,
+	}
+}
+
+// open handles a load of a tracking pixel. The pixel is served even when
+// the open can't be recorded, so the email never shows a broken image.
+func (h *Handler) open(c *gin.Context) {
+	err := h.trackingService.RecordOpen(c.Request.Context(), c.Param("token"), tracking.EventRequest{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil && !errors.Is(err, tracking.ErrInvalidToken) {
+		log.Printf("Failed to record open: %v", err)
+	}
+
+	// Every open must reach the server, so nothing may cache the pixel
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+	c.Header("Pragma", "no-cache")
+	c.Data(http.StatusOK, "image/gif", pixel)
+}
+
+// click handles a followed tracked link by redirecting to its target.
+// Only signed targets are redirected to, so the endpoint isn't an open
+// redirect.
+func (h *Handler) click(c *gin.Context) {
+	target, err := h.trackingService.RecordClick(c.Request.Context(), c.Param("token"), tracking.EventRequest{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if errors.Is(err, tracking.ErrInvalidToken) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "link not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to record click: %v", err)
+	}
+
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+	c.Redirect(http.StatusFound, target)
+}
+
+// report handles summarising the opens and clicks of a message
+func (h *Handler) report(c *gin.Context) {
+	resp, err := h.trackingService.Report(c.Request.Context(), c.GetString("userID"), c.Param("messageId"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// listEvents handles listing the events of the user's messages
+func (h *Handler) listEvents(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	filter := tracking.ListFilter{
+		MessageID: c.Query("messageId"),
+		Type:      models.EmailEventType(c.Query("type")),
+	}
+
+	resp, err := h.trackingService.ListEvents(c.Request.Context(), c.GetString("userID"), filter, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, tracking.ErrInvalidEvent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,159 @@
+package tracking
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/logic/tracking"
+	"GoMail/app/logic/tracking/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_open(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "happy path"},
+		{name: "invalid token", err: tracking.ErrInvalidToken},
+		{name: "error in logic", err: errors.New("db error")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("GET", "/t/o/token", nil)
+			r.Header.Set("User-Agent", "Mozilla/5.0")
+			r.RemoteAddr = "192.0.2.10:40000"
+			c.Request = r
+			c.Params = gin.Params{{Key: "token", Value: "token"}}
+
+			trackingService := &mocks.Service{}
+			trackingService.On("RecordOpen", mock.Anything, "token", tracking.EventRequest{IP: "192.0.2.10", UserAgent: "Mozilla/5.0"}).Return(tt.err)
+
+			h := &Handler{trackingService: trackingService}
+
+			// Act
+			h.open(c)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "image/gif", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Header().Get("Cache-Control"), "no-store")
+			assert.Equal(t, pixel, w.Body.Bytes())
+			trackingService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_click(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		err                error
+		expectedStatusCode int
+		expectedLocation   string
+	}{
+		{
+			name:               "happy path",
+			target:             "https://shop.example.com/order?id=7",
+			expectedStatusCode: http.StatusFound,
+			expectedLocation:   "https://shop.example.com/order?id=7",
+		},
+		{
+			name:               "click not saved",
+			target:             "https://shop.example.com/order?id=7",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusFound,
+			expectedLocation:   "https://shop.example.com/order?id=7",
+		},
+		{
+			name:               "invalid token",
+			err:                tracking.ErrInvalidToken,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("GET", "/t/c/token", nil)
+			r.Header.Set("User-Agent", "Mozilla/5.0 (iPhone)")
+			r.RemoteAddr = "192.0.2.10:40000"
+			c.Request = r
+			c.Params = gin.Params{{Key: "token", Value: "token"}}
+
+			trackingService := &mocks.Service{}
+			trackingService.On("RecordClick", mock.Anything, "token", tracking.EventRequest{IP: "192.0.2.10", UserAgent: "Mozilla/5.0 (iPhone)"}).Return(tt.target, tt.err)
+
+			h := &Handler{trackingService: trackingService}
+
+			// Act
+			h.click(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			trackingService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_listEvents(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "unknown type",
+			err:                tracking.ErrInvalidEvent,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("GET", "/tracking/events?messageId=abc&type=open&page=2&limit=10", nil)
+			c.Request = r
+			c.Set("userID", "user-1")
+
+			trackingService := &mocks.Service{}
+			var resp *tracking.ListEventsResponse
+			if tt.err == nil {
+				resp = &tracking.ListEventsResponse{}
+			}
+			trackingService.On("ListEvents", mock.Anything, "user-1", tracking.ListFilter{MessageID: "abc", Type: "open"}, 2, 10).Return(resp, tt.err)
+
+			h := &Handler{trackingService: trackingService}
+
+			// Act
+			h.listEvents(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			trackingService.AssertExpectations(t)
+		})
+	}
+}
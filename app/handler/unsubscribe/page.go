@@ -0,0 +1,54 @@
+package unsubscribe
+
+import (
+	"html/template"
+)
+
+// page is the HTML page recipients see. Without Done it asks them to
+// confirm; the form posts to the link itself, like a one-click request.
+var page = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Unsubscribe</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;background:#f4f4f5;color:#18181b;margin:0;padding:48px 16px}
+main{max-width:480px;margin:0 auto;background:#fff;border-radius:8px;padding:32px}
+h1{font-size:22px;margin:0 0 16px}
+button{background:#18181b;color:#fff;border:0;border-radius:6px;padding:10px 20px;font-size:15px;cursor:pointer}
+</style>
+</head>
+<body>
+<main>
+{{- if .Error}}
+<h1>Link not valid</h1>
+<p>{{.Error}}</p>
+{{- else if .Done}}
+<h1>You are unsubscribed</h1>
+<p>{{.Recipient}} will no longer receive {{if .List}}emails of {{.List}}{{else}}these emails{{end}}.</p>
+{{- else}}
+<h1>Unsubscribe</h1>
+<p>Stop sending {{if .List}}emails of {{.List}}{{else}}these emails{{end}} to {{.Recipient}}?</p>
+<form method="post">
+<button type="submit">Unsubscribe</button>
+</form>
+{{- end}}
+{{- if .PreferencesURL}}
+<p><a href="{{.PreferencesURL}}">Manage your email preferences</a></p>
+{{- end}}
+</main>
+</body>
+</html>
+`))
+
+// pageData is what the page shows
+type pageData struct {
+	Recipient string
+	List      string
+	Done      bool
+	Error     string
+
+	PreferencesURL string
+}
@@ -0,0 +1,15 @@
+package unsubscribe
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddPublicRoute adds the routes recipients and mailbox providers follow
+// unsubscribe links on
+func AddPublicRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	unsubscribeGroup := router.Group(path)
+	{
+		unsubscribeGroup.GET("/:token", handler.confirm)
+		unsubscribeGroup.POST("/:token", handler.unsubscribe)
+	}
+}
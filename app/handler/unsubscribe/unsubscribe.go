@@ -0,0 +1,69 @@
+package unsubscribe
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"GoMail/app/logic/unsubscribe"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles unsubscribe HTTP requests
+type Handler struct {
+	unsubscribeService unsubscribe.Service
+}
+
+// NewHandler creates a new unsubscribe handler
+func NewHandler(unsubscribeService unsubscribe.Service) *Handler {
+	return &Handler{
+		unsubscribeService: unsubscribeService,
+	}
+}
+
+// confirm handles a followed unsubscribe link by asking the recipient to
+// confirm. Opening the link doesn't unsubscribe, since link scanners of
+// mail providers open them too.
+func (h *Handler) confirm(c *gin.Context) {
+	resp, err := h.unsubscribeService.Get(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	render(c, http.StatusOK, pageData{Recipient: resp.Recipient, List: resp.List, Done: resp.Unsubscribed, PreferencesURL: resp.PreferencesURL})
+}
+
+// unsubscribe handles both the one-click POST of mail providers (RFC 8058)
+// and the form of the confirmation page
+func (h *Handler) unsubscribe(c *gin.Context) {
+	resp, err := h.unsubscribeService.Unsubscribe(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	render(c, http.StatusOK, pageData{Recipient: resp.Recipient, List: resp.List, Done: true, PreferencesURL: resp.PreferencesURL})
+}
+
+// render writes the page, which nothing may cache
+func render(c *gin.Context, status int, data pageData) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := page.Execute(c.Writer, data); err != nil {
+		log.Printf("Failed to render unsubscribe page: %v", err)
+	}
+}
+
+// writeError maps service errors to pages
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, unsubscribe.ErrInvalidToken):
+		render(c, http.StatusNotFound, pageData{Error: "This unsubscribe link is invalid."})
+	default:
+		log.Printf("Failed to unsubscribe: %v", err)
+		render(c, http.StatusInternalServerError, pageData{Error: "Something went wrong, please try again later."})
+	}
+}
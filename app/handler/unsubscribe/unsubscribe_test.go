@@ -0,0 +1,102 @@
+package unsubscribe
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"GoMail/app/logic/unsubscribe"
+	"GoMail/app/logic/unsubscribe/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_confirm(t *testing.T) {
+	tests := []struct {
+		name               string
+		resp               *unsubscribe.UnsubscribeResponse
+		err                error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "happy path",
+			resp:               &unsubscribe.UnsubscribeResponse{Recipient: "ada@example.org", List: "news"},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `<form method="post">`,
+		},
+		{
+			name:               "already unsubscribed",
+			resp:               &unsubscribe.UnsubscribeResponse{Recipient: "ada@example.org", Unsubscribed: true},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "You are unsubscribed",
+		},
+		{
+			name:               "invalid token",
+			err:                unsubscribe.ErrInvalidToken,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "invalid",
+		},
+		{
+			name:               "error in logic",
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       "try again later",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("GET", "/u/token", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "token", Value: "token"}}
+
+			unsubscribeService := &mocks.Service{}
+			unsubscribeService.On("Get", mock.Anything, "token").Return(tt.resp, tt.err)
+
+			h := &Handler{unsubscribeService: unsubscribeService}
+
+			// Act
+			h.confirm(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			unsubscribeService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_unsubscribe(t *testing.T) {
+	// Assemble
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	// Mailbox providers post the one-click form of RFC 8058
+	r, _ := http.NewRequest("POST", "/u/token", strings.NewReader("List-Unsubscribe=One-Click"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	c.Request = r
+	c.Params = gin.Params{{Key: "token", Value: "token"}}
+
+	unsubscribeService := &mocks.Service{}
+	unsubscribeService.On("Unsubscribe", mock.Anything, "token").
+		Return(&unsubscribe.UnsubscribeResponse{Recipient: "ada@example.org", List: "news", Unsubscribed: true}, nil)
+
+	h := &Handler{unsubscribeService: unsubscribeService}
+
+	// Act
+	h.unsubscribe(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "ada@example.org will no longer receive emails of news")
+	unsubscribeService.AssertExpectations(t)
+}
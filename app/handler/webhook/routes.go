@@ -0,0 +1,19 @@
+package webhook
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// AddProtectedRoute adds webhook subscription routes that require authentication
+func AddProtectedRoute(router *gin.RouterGroup, path string, handler *Handler) {
+	webhookGroup := router.Group(path)
+	{
+		webhookGroup.GET("", handler.list)
+		webhookGroup.POST("", handler.create)
+		webhookGroup.GET("/:id", handler.get)
+		webhookGroup.PUT("/:id", handler.update)
+		webhookGroup.DELETE("/:id", handler.delete)
+		webhookGroup.GET("/:id/deliveries", handler.listDeliveries)
+		webhookGroup.POST("/deliveries/:deliveryId/redeliver", handler.redeliver)
+	}
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles webhook subscription HTTP requests
+type Handler struct {
+	webhookService webhook.Service
+}
+
+// NewHandler creates a new webhook handler
+func NewHandler(webhookService webhook.Service) *Handler {
+	return &Handler{
+		webhookService: webhookService,
+	}
+}
+
+// list handles listing the webhooks of the user
+func (h *Handler) list(c *gin.Context) {
+	resp, err := h.webhookService.List(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// create handles subscribing a webhook
+func (h *Handler) create(c *gin.Context) {
+	var req webhook.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.webhookService.Create(c.Request.Context(), c.GetString("userID"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// get handles fetching a webhook
+func (h *Handler) get(c *gin.Context) {
+	resp, err := h.webhookService.Get(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// update handles changing a webhook
+func (h *Handler) update(c *gin.Context) {
+	var req webhook.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.webhookService.Update(c.Request.Context(), c.GetString("userID"), c.Param("id"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// delete handles removing a webhook
+func (h *Handler) delete(c *gin.Context) {
+	if err := h.webhookService.Delete(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// listDeliveries handles listing the delivery log of a webhook
+func (h *Handler) listDeliveries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	status := models.WebhookDeliveryStatus(c.Query("status"))
+
+	resp, err := h.webhookService.ListDeliveries(c.Request.Context(), c.GetString("userID"), c.Param("id"), status, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// redeliver handles queueing a delivery again
+func (h *Handler) redeliver(c *gin.Context) {
+	resp, err := h.webhookService.Redeliver(c.Request.Context(), c.GetString("userID"), c.Param("deliveryId"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// writeError maps service errors to HTTP responses
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, webhook.ErrWebhookNotFound), errors.Is(err, webhook.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, webhook.ErrWebhookDisabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"GoMail/app/logic/webhook"
+	"GoMail/app/logic/webhook/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handler_create(t *testing.T) {
+	tests := []struct {
+		name               string
+		request            []byte
+		callLogic          bool
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			request:            []byte(`{"url":"https://hooks.example.com","events":["sent","bounced"]}`),
+			callLogic:          true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "malformed request",
+			request:            []byte(`{"url":`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "missing events",
+			request:            []byte(`{"url":"https://hooks.example.com"}`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "invalid webhook",
+			request:            []byte(`{"url":"https://hooks.example.com","events":["sent","bounced"]}`),
+			callLogic:          true,
+			err:                webhook.ErrInvalidWebhook,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error in logic",
+			request:            []byte(`{"url":"https://hooks.example.com","events":["sent","bounced"]}`),
+			callLogic:          true,
+			err:                errors.New("db error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/webhooks", bytes.NewBuffer(tt.request))
+			r.Header.Set("Content-Type", "application/json")
+			c.Request = r
+			c.Set("userID", "user-1")
+
+			webhookService := &mocks.Service{}
+			if tt.callLogic {
+				var resp *webhook.WebhookResponse
+				if tt.err == nil {
+					resp = &webhook.WebhookResponse{ID: "hook-1", Secret: "whsec_1"}
+				}
+				webhookService.On("Create", mock.Anything, "user-1", webhook.WebhookRequest{
+					URL:    "https://hooks.example.com",
+					Events: []models.WebhookEventType{models.WebhookEventSent, models.WebhookEventBounced},
+				}).Return(resp, tt.err)
+			}
+
+			h := &Handler{
+				webhookService: webhookService,
+			}
+
+			// Act
+			h.create(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			webhookService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_listDeliveries(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		status             models.WebhookDeliveryStatus
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "by status",
+			query:              "?status=failed",
+			status:             models.WebhookDeliveryFailed,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "webhook of another user",
+			err:                webhook.ErrWebhookNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("GET", "/webhooks/hook-1/deliveries"+tt.query, nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "id", Value: "hook-1"}}
+			c.Set("userID", "user-1")
+
+			var resp *webhook.ListDeliveriesResponse
+			if tt.err == nil {
+				resp = &webhook.ListDeliveriesResponse{Deliveries: []webhook.DeliveryResponse{}, Page: 1, Limit: 20}
+			}
+			webhookService := &mocks.Service{}
+			webhookService.On("ListDeliveries", mock.Anything, "user-1", "hook-1", tt.status, 1, 20).Return(resp, tt.err)
+
+			h := &Handler{
+				webhookService: webhookService,
+			}
+
+			// Act
+			h.listDeliveries(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			webhookService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_handler_redeliver(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "not found",
+			err:                webhook.ErrDeliveryNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "webhook disabled",
+			err:                webhook.ErrWebhookDisabled,
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			r, _ := http.NewRequest("POST", "/webhooks/deliveries/delivery-1/redeliver", nil)
+			c.Request = r
+			c.Params = gin.Params{{Key: "deliveryId", Value: "delivery-1"}}
+			c.Set("userID", "user-1")
+
+			var resp *webhook.DeliveryResponse
+			if tt.err == nil {
+				resp = &webhook.DeliveryResponse{ID: "delivery-2", RedeliveryOf: "delivery-1"}
+			}
+			webhookService := &mocks.Service{}
+			webhookService.On("Redeliver", mock.Anything, "user-1", "delivery-1").Return(resp, tt.err)
+
+			h := &Handler{
+				webhookService: webhookService,
+			}
+
+			// Act
+			h.redeliver(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			webhookService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+// Package bounce parses delivery status notifications (RFC 3464) and abuse
+// feedback reports (RFC 5965) sent back about delivered emails.
+//
+// Both are multipart/report messages. A DSN carries a message/delivery-status
+// part with the envelope ID and a block of fields per recipient, an ARF
+// report a message/feedback-report part. Either usually ends with the
+// original message or its headers, which give the Message-ID.
+package bounce
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrNotReport     = errors.New("not a delivery status notification or feedback report")
+	ErrInvalidReport = errors.New("invalid report")
+)
+
+// ReportType is the kind of a report
+type ReportType string
+
+const (
+	// ReportTypeDSN is a delivery status notification
+	ReportTypeDSN ReportType = "dsn"
+	// ReportTypeARF is an abuse feedback report
+	ReportTypeARF ReportType = "arf"
+)
+
+const (
+	// maxDepth is how deep reports are looked for in forwarded messages
+	maxDepth = 3
+
+	// maxFieldsSize bounds the report and original header parts
+	maxFieldsSize = 1 << 20
+)
+
+// Report is a parsed DSN or ARF report
+type Report struct {
+	Type ReportType
+
+	// MessageID and EnvelopeID identify the original message, without
+	// angle brackets. Either may be missing.
+	MessageID  string
+	EnvelopeID string
+
+	// ReportingMTA is the server that created a DSN
+	ReportingMTA string
+
+	// FeedbackType is the kind of an ARF report, such as abuse or fraud
+	FeedbackType string
+
+	Recipients []Recipient
+}
+
+// Recipient is the outcome of the original message for one recipient
+type Recipient struct {
+	Address string
+
+	// OriginalAddress is the recipient the message was sent to, when a DSN
+	// reports it and forwarding changed the address
+	OriginalAddress string
+
+	// Action is the DSN action: failed, delayed, delivered, relayed or
+	// expanded. It is empty for ARF reports.
+	Action string
+
+	// Status is the enhanced status code, such as 5.1.1
+	Status string
+
+	// DiagnosticCode is the reply of the remote server, such as
+	// "550 5.1.1 User unknown"
+	DiagnosticCode string
+
+	RemoteMTA string
+}
+
+// Failed reports whether the message could not be delivered
+func (r Recipient) Failed() bool {
+	return r.Action == "failed"
+}
+
+// Hard reports whether delivery failed permanently, so retrying won't help
+func (r Recipient) Hard() bool {
+	return r.Failed() && strings.HasPrefix(r.Status, "5.")
+}
+
+// Parse parses a report from a whole message. Reports forwarded as an
+// attachment are found too.
+func Parse(r io.Reader) (*Report, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+	return parseEntity(textproto.MIMEHeader(msg.Header), msg.Body, 0)
+}
+
+// parseEntity looks for a report in a MIME entity
+func parseEntity(header textproto.MIMEHeader, body io.Reader, depth int) (*Report, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || depth > maxDepth {
+		return nil, ErrNotReport
+	}
+	body = decode(header, body)
+
+	switch {
+	case mediaType == "multipart/report":
+		return parseReport(multipart.NewReader(body, params["boundary"]))
+	case strings.HasPrefix(mediaType, "multipart/"):
+		// Look into the parts of a forwarded report
+		parts := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err != nil {
+				return nil, ErrNotReport
+			}
+			if report, err := parseEntity(part.Header, part, depth+1); err == nil {
+				return report, nil
+			}
+		}
+	case mediaType == "message/rfc822":
+		msg, err := mail.ReadMessage(body)
+		if err != nil {
+			return nil, ErrNotReport
+		}
+		return parseEntity(textproto.MIMEHeader(msg.Header), msg.Body, depth+1)
+	default:
+		return nil, ErrNotReport
+	}
+}
+
+// parseReport parses the parts of a multipart/report
+func parseReport(parts *multipart.Reader) (*Report, error) {
+	var report *Report
+	var original textproto.MIMEHeader
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidReport, err)
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		content := io.LimitReader(decode(part.Header, part), maxFieldsSize)
+		switch mediaType {
+		case "message/delivery-status", "message/global-delivery-status":
+			if report, err = parseDeliveryStatus(content); err != nil {
+				return nil, err
+			}
+		case "message/feedback-report":
+			if report, err = parseFeedbackReport(content); err != nil {
+				return nil, err
+			}
+		case "message/rfc822", "message/global", "text/rfc822-headers", "message/global-headers":
+			if msg, err := mail.ReadMessage(content); err == nil {
+				original = textproto.MIMEHeader(msg.Header)
+			}
+		}
+	}
+	if report == nil {
+		return nil, ErrNotReport
+	}
+
+	if original != nil {
+		report.MessageID = unbracket(original.Get("Message-Id"))
+
+		// Feedback reports may leave out the recipient
+		if report.Type == ReportTypeARF && len(report.Recipients) == 0 {
+			if addresses, err := mail.ParseAddressList(original.Get("To")); err == nil {
+				for _, addr := range addresses {
+					report.Recipients = append(report.Recipients, Recipient{Address: addr.Address})
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+// parseDeliveryStatus parses the per-message fields of a delivery status
+// and the blocks of per-recipient fields that follow them
+func parseDeliveryStatus(r io.Reader) (*Report, error) {
+	blocks, err := readBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) < 2 {
+		return nil, fmt.Errorf("%w: delivery status without recipients", ErrInvalidReport)
+	}
+
+	report := &Report{
+		Type:         ReportTypeDSN,
+		EnvelopeID:   unbracket(blocks[0].Get("Original-Envelope-Id")),
+		ReportingMTA: typedValue(blocks[0].Get("Reporting-Mta")),
+	}
+	for _, fields := range blocks[1:] {
+		address := unbracket(typedValue(fields.Get("Final-Recipient")))
+		original := unbracket(typedValue(fields.Get("Original-Recipient")))
+		if address == "" {
+			address, original = original, ""
+		}
+		if strings.EqualFold(address, original) {
+			original = ""
+		}
+		recipient := Recipient{
+			Address:         address,
+			OriginalAddress: original,
+			Action:          strings.ToLower(firstWord(fields.Get("Action"))),
+			Status:          firstWord(fields.Get("Status")),
+			DiagnosticCode:  typedValue(fields.Get("Diagnostic-Code")),
+			RemoteMTA:       typedValue(fields.Get("Remote-Mta")),
+		}
+		if recipient.Status == "" {
+			recipient.Status = statusFromDiagnostic(recipient.DiagnosticCode)
+		}
+		report.Recipients = append(report.Recipients, recipient)
+	}
+	return report, nil
+}
+
+// parseFeedbackReport parses the fields of an abuse feedback report
+func parseFeedbackReport(r io.Reader) (*Report, error) {
+	blocks, err := readBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("%w: empty feedback report", ErrInvalidReport)
+	}
+
+	report := &Report{
+		Type:         ReportTypeARF,
+		FeedbackType: strings.ToLower(strings.TrimSpace(blocks[0].Get("Feedback-Type"))),
+	}
+	for _, address := range blocks[0].Values("Original-Rcpt-To") {
+		if address = unbracket(address); address != "" {
+			report.Recipients = append(report.Recipients, Recipient{Address: address})
+		}
+	}
+	return report, nil
+}
+
+// readBlocks reads the blocks of header fields of a report part, which
+// are separated by blank lines
+func readBlocks(r io.Reader) ([]textproto.MIMEHeader, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+
+	var blocks []textproto.MIMEHeader
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			blocks = append(blocks, fields)
+		}
+		if err == io.EOF {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidReport, err)
+		}
+	}
+}
+
+// decode undoes the base64 transfer encoding of a part. Quoted-printable
+// parts are decoded by the multipart reader.
+func decode(header textproto.MIMEHeader, body io.Reader) io.Reader {
+	if strings.EqualFold(strings.TrimSpace(header.Get("Content-Transfer-Encoding")), "base64") {
+		return base64.NewDecoder(base64.StdEncoding, body)
+	}
+	return body
+}
+
+// typedValue returns the value of a field of the form "type; value", such
+// as "rfc822; ada@example.com"
+func typedValue(field string) string {
+	if i := strings.Index(field, ";"); i >= 0 {
+		field = field[i+1:]
+	}
+	return strings.TrimSpace(field)
+}
+
+// firstWord returns a field without its trailing comment
+func firstWord(field string) string {
+	if fields := strings.Fields(field); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// unbracket removes the angle brackets around a Message-ID or address
+func unbracket(value string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "<"), ">")
+}
+
+var (
+	enhancedStatus = regexp.MustCompile(`\b([245]\.\d{1,3}\.\d{1,3})\b`)
+	basicStatus    = regexp.MustCompile(`^([245])\d\d\b`)
+)
+
+// statusFromDiagnostic derives a status code from the SMTP reply of a
+// recipient whose DSN has none
+func statusFromDiagnostic(diagnostic string) string {
+	if match := enhancedStatus.FindStringSubmatch(diagnostic); match != nil {
+		return match[1]
+	}
+	if match := basicStatus.FindStringSubmatch(diagnostic); match != nil {
+		return match[1] + ".0.0"
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package bounce
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crlf converts a message written with \n line endings
+func crlf(message string) string {
+	return strings.ReplaceAll(message, "\n", "\r\n")
+}
+
+const dsn = `From: MAILER-DAEMON@mx.example.net
+To: sender@example.com
+Subject: Undelivered Mail Returned to Sender
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: text/plain
+
+Your message could not be delivered.
+
+--BOUNDARY
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.example.net
+Original-Envelope-Id: 0123abcd@example.com
+Arrival-Date: Mon, 12 Oct 2026 10:00:00 +0000
+
+Final-Recipient: rfc822; ada@example.org
+Original-Recipient: rfc822;Ada@example.org
+Action: failed
+Status: 5.1.1 (mailbox unknown)
+Remote-MTA: dns; mail.example.org
+Diagnostic-Code: smtp; 550 5.1.1 <ada@example.org>: User unknown
+
+Final-Recipient: rfc822; bob@lists.example.org
+Original-Recipient: rfc822; bob@example.org
+Action: delayed
+Diagnostic-Code: smtp; 451 4.2.2 Mailbox full
+
+Final-Recipient: rfc822; cy@example.org
+Action: failed
+Diagnostic-Code: smtp; 554 Rejected
+
+--BOUNDARY
+Content-Type: text/rfc822-headers
+
+From: sender@example.com
+To: ada@example.org, bob@example.org, cy@example.org
+Subject: Hello
+Message-ID: <0123abcd@example.com>
+
+--BOUNDARY--
+`
+
+func TestParse_DSN(t *testing.T) {
+	report, err := Parse(strings.NewReader(crlf(dsn)))
+
+	require.NoError(t, err)
+	assert.Equal(t, ReportTypeDSN, report.Type)
+	assert.Equal(t, "0123abcd@example.com", report.MessageID)
+	assert.Equal(t, "0123abcd@example.com", report.EnvelopeID)
+	assert.Equal(t, "mx.example.net", report.ReportingMTA)
+	require.Len(t, report.Recipients, 3)
+
+	assert.Equal(t, Recipient{
+		Address:        "ada@example.org",
+		Action:         "failed",
+		Status:         "5.1.1",
+		DiagnosticCode: "550 5.1.1 <ada@example.org>: User unknown",
+		RemoteMTA:      "mail.example.org",
+	}, report.Recipients[0])
+	assert.True(t, report.Recipients[0].Hard())
+
+	assert.Equal(t, "bob@lists.example.org", report.Recipients[1].Address)
+	assert.Equal(t, "bob@example.org", report.Recipients[1].OriginalAddress)
+	assert.Equal(t, "4.2.2", report.Recipients[1].Status)
+	assert.False(t, report.Recipients[1].Failed())
+
+	assert.Equal(t, "5.0.0", report.Recipients[2].Status)
+	assert.True(t, report.Recipients[2].Hard())
+}
+
+const arf = `From: feedback@isp.example.net
+To: abuse@example.com
+Subject: FW: Hello
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=feedback-report; boundary="part"
+
+--part
+Content-Type: text/plain; charset="US-ASCII"
+
+This is an email abuse report.
+
+--part
+Content-Type: message/feedback-report
+
+Feedback-Type: abuse
+User-Agent: SomeGenerator/1.0
+Version: 1
+Original-Mail-From: <sender@example.com>
+Original-Rcpt-To: <ada@example.org>
+
+--part
+Content-Type: message/rfc822
+Content-Disposition: inline
+
+From: sender@example.com
+To: ada@example.org
+Subject: Hello
+Message-ID: <feed@example.com>
+
+Hello
+--part--
+`
+
+func TestParse_ARF(t *testing.T) {
+	report, err := Parse(strings.NewReader(crlf(arf)))
+
+	require.NoError(t, err)
+	assert.Equal(t, ReportTypeARF, report.Type)
+	assert.Equal(t, "abuse", report.FeedbackType)
+	assert.Equal(t, "feed@example.com", report.MessageID)
+	assert.Equal(t, []Recipient{{Address: "ada@example.org"}}, report.Recipients)
+}
+
+func TestParse_ARFRedactedRecipient(t *testing.T) {
+	redacted := strings.Replace(arf, "Original-Rcpt-To: <ada@example.org>\n", "", 1)
+
+	report, err := Parse(strings.NewReader(crlf(redacted)))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Recipient{{Address: "ada@example.org"}}, report.Recipients)
+}
+
+func TestParse_Forwarded(t *testing.T) {
+	forwarded := "From: postmaster@example.com\nTo: bounces@example.com\nSubject: Fwd\nMIME-Version: 1.0\n" +
+		"Content-Type: multipart/mixed; boundary=outer\n\n--outer\nContent-Type: text/plain\n\nSee attached\n" +
+		"--outer\nContent-Type: message/rfc822\n\n" + dsn + "\n--outer--\n"
+
+	report, err := Parse(strings.NewReader(crlf(forwarded)))
+
+	require.NoError(t, err)
+	assert.Equal(t, ReportTypeDSN, report.Type)
+	assert.Len(t, report.Recipients, 3)
+}
+
+func TestParse_Base64Status(t *testing.T) {
+	encoded := `From: MAILER-DAEMON@mx.example.net
+To: sender@example.com
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary=b
+
+--b
+Content-Type: message/delivery-status
+Content-Transfer-Encoding: base64
+
+UmVwb3J0aW5nLU1UQTogZG5zOyBteC5leGFtcGxlLm5ldA0KDQpGaW5hbC1SZWNpcGllbnQ6IHJm
+YzgyMjsgZGFuQGV4YW1wbGUub3JnDQpBY3Rpb246IGZhaWxlZA0KU3RhdHVzOiA1LjIuMQ0K
+--b--
+`
+
+	report, err := Parse(strings.NewReader(crlf(encoded)))
+
+	require.NoError(t, err)
+	assert.Empty(t, report.MessageID)
+	assert.Equal(t, []Recipient{{Address: "dan@example.org", Action: "failed", Status: "5.2.1"}}, report.Recipients)
+}
+
+func TestParse_Errors(t *testing.T) {
+	plain := "From: ada@example.org\nTo: sender@example.com\nSubject: Re: Hello\n\nThanks!\n"
+	noReport := strings.Replace(dsn, "message/delivery-status", "text/plain", 1)
+	noRecipients := strings.Replace(dsn, "\nFinal-Recipient", "\nX-Final-Recipient", -1)
+	noRecipients = noRecipients[:strings.Index(noRecipients, "\nX-Final-Recipient")] + "\n--BOUNDARY--\n"
+
+	_, err := Parse(strings.NewReader(crlf(plain)))
+	assert.ErrorIs(t, err, ErrNotReport)
+
+	_, err = Parse(strings.NewReader(crlf(noReport)))
+	assert.ErrorIs(t, err, ErrNotReport)
+
+	_, err = Parse(strings.NewReader(crlf(noRecipients)))
+	assert.ErrorIs(t, err, ErrInvalidReport)
+
+	_, err = Parse(strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrInvalidReport)
+}
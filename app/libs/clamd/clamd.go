@@ -0,0 +1,159 @@
+// Package clamd scans content for malware with a ClamAV daemon.
+//
+// Content is sent with the INSTREAM command: the command is followed by
+// chunks prefixed with their length as a 4-byte big-endian integer and a
+// zero-length chunk ends the stream. The daemon then replies with
+// "stream: OK" or "stream: <signature> FOUND". Commands are prefixed with
+// "z", so replies are terminated by a NUL byte.
+package clamd
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+var (
+	ErrScanFailed = errors.New("scan failed")
+
+	// errContent reports a failure to read the scanned content
+	errContent = errors.New("reading content")
+)
+
+// Defaults used for zero config values
+const (
+	DefaultNetwork   = "tcp"
+	DefaultTimeout   = 30 * time.Second
+	DefaultChunkSize = 64 << 10
+)
+
+// Config holds the address of a clamd daemon
+type Config struct {
+	// Network is "tcp" or "unix"
+	Network string
+
+	// Address is a host:port for tcp or a socket path for unix
+	Address string
+
+	// Timeout bounds a whole scan, including the upload of the content
+	Timeout time.Duration
+
+	// ChunkSize is the size of the INSTREAM chunks. It must stay below the
+	// StreamMaxLength of the daemon.
+	ChunkSize int
+}
+
+// Client scans content with a clamd daemon. Each scan uses its own
+// connection, so a client is safe for concurrent use.
+type Client struct {
+	config Config
+	dialer net.Dialer
+}
+
+// New creates a clamd client
+func New(cfg Config) *Client {
+	if cfg.Network == "" {
+		cfg.Network = DefaultNetwork
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = DefaultChunkSize
+	}
+
+	return &Client{config: cfg}
+}
+
+// Scan streams r to the daemon and returns the name of the signature it
+// matched, or an empty string when the content is clean
+func (c *Client) Scan(ctx context.Context, r io.Reader) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(ctx, c.config.Network, c.config.Address)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrScanFailed, err)
+	}
+	defer conn.Close()
+
+	// Abort blocked reads and writes when the context ends
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrScanFailed, err)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	// The daemon closes the connection once the stream exceeds its size
+	// limit and explains why in its reply, so read the reply even when the
+	// upload fails
+	sendErr := c.send(conn, r)
+	if errors.Is(sendErr, errContent) {
+		return "", fmt.Errorf("%w: %v", ErrScanFailed, sendErr)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		if sendErr != nil {
+			err = sendErr
+		}
+		return "", fmt.Errorf("%w: %v", ErrScanFailed, err)
+	}
+
+	return parseReply(reply)
+}
+
+// send writes the INSTREAM command and the content of r
+func (c *Client) send(w io.Writer, r io.Reader) error {
+	if _, err := io.WriteString(w, "zINSTREAM\x00"); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4+c.config.ChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, werr := w.Write(buf[:4+n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", errContent, err)
+		}
+	}
+
+	// A zero-length chunk ends the stream
+	_, err := w.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// parseReply returns the signature of a reply to INSTREAM
+func parseReply(reply string) (string, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(reply, " FOUND")
+		if i := strings.LastIndex(signature, ": "); i >= 0 {
+			signature = signature[i+2:]
+		}
+		return signature, nil
+	case strings.HasSuffix(reply, ": OK"):
+		return "", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrScanFailed, reply)
+	}
+}
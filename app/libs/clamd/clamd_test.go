@@ -0,0 +1,204 @@
+package clamd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd serves the INSTREAM command like clamd. Streams containing the
+// EICAR test string are reported as infected and streams longer than
+// maxLength are refused.
+type fakeClamd struct {
+	listener  net.Listener
+	maxLength int
+	command   string
+	chunks    []int
+}
+
+func newFakeClamd(t *testing.T, network, address string) *fakeClamd {
+	t.Helper()
+
+	listener, err := net.Listen(network, address)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	f := &fakeClamd{listener: listener, maxLength: 1 << 20}
+	go f.serve()
+	return f
+}
+
+func (f *fakeClamd) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		f.handle(conn)
+	}
+}
+
+func (f *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	command, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+	f.command = command
+	if command != "zINSTREAM\x00" {
+		io.WriteString(conn, "UNKNOWN COMMAND\x00")
+		return
+	}
+
+	var content bytes.Buffer
+	f.chunks = nil
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return
+		}
+		if size == 0 {
+			break
+		}
+		f.chunks = append(f.chunks, int(size))
+		if content.Len()+int(size) > f.maxLength {
+			io.WriteString(conn, "INSTREAM size limit exceeded. ERROR\x00")
+			return
+		}
+		if _, err := io.CopyN(&content, r, int64(size)); err != nil {
+			return
+		}
+	}
+
+	if strings.Contains(content.String(), eicar) {
+		io.WriteString(conn, "stream: Win.Test.EICAR_HDB-1 FOUND\x00")
+		return
+	}
+	io.WriteString(conn, "stream: OK\x00")
+}
+
+func TestClient_Scan(t *testing.T) {
+	networks := []struct {
+		network string
+		address string
+	}{
+		{network: "tcp", address: "127.0.0.1:0"},
+		{network: "unix", address: filepath.Join(t.TempDir(), "clamd.sock")},
+	}
+
+	for _, n := range networks {
+		t.Run(n.network, func(t *testing.T) {
+			server := newFakeClamd(t, n.network, n.address)
+			client := New(Config{Network: n.network, Address: server.listener.Addr().String(), ChunkSize: 16})
+
+			signature, err := client.Scan(context.Background(), strings.NewReader("quarterly report"))
+			require.NoError(t, err)
+			assert.Empty(t, signature)
+			assert.Equal(t, "zINSTREAM\x00", server.command)
+			assert.Equal(t, []int{16}, server.chunks)
+
+			signature, err = client.Scan(context.Background(), strings.NewReader("prefix "+eicar))
+			require.NoError(t, err)
+			assert.Equal(t, "Win.Test.EICAR_HDB-1", signature)
+			assert.Equal(t, []int{16, 16, 16, 16, 11}, server.chunks)
+		})
+	}
+}
+
+func TestClient_Scan_SizeLimit(t *testing.T) {
+	server := newFakeClamd(t, "tcp", "127.0.0.1:0")
+	server.maxLength = 32
+	client := New(Config{Address: server.listener.Addr().String(), ChunkSize: 16})
+
+	_, err := client.Scan(context.Background(), strings.NewReader(strings.Repeat("x", 64)))
+
+	assert.ErrorIs(t, err, ErrScanFailed)
+	assert.Contains(t, err.Error(), "size limit exceeded")
+}
+
+func TestClient_Scan_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	_, err = New(Config{Address: address}).Scan(context.Background(), strings.NewReader("x"))
+
+	assert.ErrorIs(t, err, ErrScanFailed)
+}
+
+func TestClient_Scan_Timeout(t *testing.T) {
+	// A daemon that accepts connections but never replies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go io.Copy(io.Discard, conn)
+		}
+	}()
+
+	start := time.Now()
+	_, err = New(Config{Address: listener.Addr().String(), Timeout: 100 * time.Millisecond}).Scan(context.Background(), strings.NewReader("x"))
+
+	assert.ErrorIs(t, err, ErrScanFailed)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestClient_Scan_ContentError(t *testing.T) {
+	server := newFakeClamd(t, "tcp", "127.0.0.1:0")
+
+	_, err := New(Config{Address: server.listener.Addr().String()}).Scan(context.Background(), io.MultiReader(strings.NewReader("x"), errReader{}))
+
+	assert.ErrorIs(t, err, ErrScanFailed)
+	assert.Contains(t, err.Error(), "disk gone")
+}
+
+// errReader fails every read
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk gone")
+}
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		reply     string
+		signature string
+		wantErr   bool
+	}{
+		{reply: "stream: OK\x00"},
+		{reply: "stream: Eicar-Signature FOUND\x00", signature: "Eicar-Signature"},
+		{reply: "1: stream: Eicar-Signature FOUND\n", signature: "Eicar-Signature"},
+		{reply: "INSTREAM size limit exceeded. ERROR\x00", wantErr: true},
+		{reply: "stream: Can't allocate memory ERROR\x00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			signature, err := parseReply(tt.reply)
+
+			assert.Equal(t, tt.signature, signature)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
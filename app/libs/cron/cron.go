@@ -0,0 +1,262 @@
+// Package cron parses standard five-field cron expressions and computes
+// their activation times in a given time zone.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidExpression = errors.New("invalid cron expression")
+)
+
+// searchYears bounds the search for the next activation so expressions that
+// never fire (e.g. 30 February) terminate
+const searchYears = 5
+
+// bounds describes the valid range and names of a single cron field
+type bounds struct {
+	name     string
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minutes = bounds{name: "minute", min: 0, max: 59}
+	hours   = bounds{name: "hour", min: 0, max: 23}
+	dom     = bounds{name: "day of month", min: 1, max: 31}
+	months  = bounds{name: "month", min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week accepts 7 as an alias for Sunday
+	dow = bounds{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// macros maps the supported shorthands to their five-field equivalents
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule is a parsed cron expression. Each field is a bit set of the
+// values it matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// Standard cron semantics: when both day fields are restricted a day
+	// matches if either of them does
+	domStar, dowStar bool
+}
+
+// Parse parses a five-field cron expression (minute hour day-of-month month
+// day-of-week) or one of the @yearly, @monthly, @weekly, @daily and @hourly
+// macros
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidExpression, len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], dom); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dow); err != nil {
+		return nil, err
+	}
+
+	// Fold 7 into Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+// parseField parses a comma separated list of values, ranges and steps
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		partBits, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+// parsePart parses a single *, value, range or step expression
+func parsePart(part string, b bounds) (uint64, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+
+	var start, end uint
+	switch {
+	case rangeExpr == "*" || rangeExpr == "?":
+		start, end = b.min, b.max
+	default:
+		lo, hi, isRange := strings.Cut(rangeExpr, "-")
+
+		var err error
+		if start, err = parseValue(lo, b); err != nil {
+			return 0, err
+		}
+		end = start
+		if isRange {
+			if end, err = parseValue(hi, b); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			// "5/15" means every 15 starting at 5
+			end = b.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("%w: %s range %q is reversed", ErrInvalidExpression, b.name, part)
+	}
+
+	step := uint(1)
+	if hasStep {
+		n, err := strconv.ParseUint(stepExpr, 10, 8)
+		if err != nil || n == 0 {
+			return 0, fmt.Errorf("%w: invalid %s step %q", ErrInvalidExpression, b.name, part)
+		}
+		step = uint(n)
+	}
+
+	var bits uint64
+	for v := start; v <= end; v += step {
+		bits |= 1 << v
+	}
+	return bits, nil
+}
+
+// parseValue parses a number or name within the bounds of a field
+func parseValue(value string, b bounds) (uint, error) {
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s %q", ErrInvalidExpression, b.name, value)
+	}
+	if uint(n) < b.min || uint(n) > b.max {
+		return 0, fmt.Errorf("%w: %s %d out of range %d-%d", ErrInvalidExpression, b.name, n, b.min, b.max)
+	}
+	return uint(n), nil
+}
+
+// Next returns the first activation strictly after t, in t's location.
+// Wall-clock times skipped by a daylight saving transition never fire and
+// repeated ones fire once. It returns the zero time if the schedule never
+// fires within the search window.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+
+	// Start at the next whole minute
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+
+	// Once a field is advanced the lower fields are reset to their minimum
+	added := false
+	yearLimit := t.Year() + searchYears
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+
+		// Midnight may not exist on a DST transition day
+		if t.Hour() != 0 {
+			if t.Hour() > 12 {
+				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+			} else {
+				t = t.Add(time.Duration(-t.Hour()) * time.Hour)
+			}
+		}
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		added = true
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+// dayMatches reports whether the day of month and day of week fields match t
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
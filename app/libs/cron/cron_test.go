@@ -0,0 +1,122 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "too few fields", expr: "0 9 * *"},
+		{name: "too many fields", expr: "0 0 9 * * 1"},
+		{name: "minute out of range", expr: "60 * * * *"},
+		{name: "day of month zero", expr: "0 0 0 * *"},
+		{name: "unknown name", expr: "0 0 * foo *"},
+		{name: "reversed range", expr: "0 17-9 * * *"},
+		{name: "zero step", expr: "*/0 * * * *"},
+		{name: "unknown macro", expr: "@sometimes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			assert.ErrorIs(t, err, ErrInvalidExpression)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	utc := time.UTC
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			from: time.Date(2026, 1, 1, 10, 30, 15, 0, utc),
+			want: time.Date(2026, 1, 1, 10, 31, 0, 0, utc),
+		},
+		{
+			name: "strictly after an exact match",
+			expr: "30 10 * * *",
+			from: time.Date(2026, 1, 1, 10, 30, 0, 0, utc),
+			want: time.Date(2026, 1, 2, 10, 30, 0, 0, utc),
+		},
+		{
+			name: "weekly report on monday with names",
+			expr: "0 9 * * MON",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, utc), // Thursday
+			want: time.Date(2026, 1, 5, 9, 0, 0, 0, utc),
+		},
+		{
+			name: "step and range",
+			expr: "*/15 9-17 * * 1-5",
+			from: time.Date(2026, 1, 2, 17, 50, 0, 0, utc), // Friday
+			want: time.Date(2026, 1, 5, 9, 0, 0, 0, utc),
+		},
+		{
+			name: "sunday as seven",
+			expr: "0 8 * * 7",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, utc),
+			want: time.Date(2026, 1, 4, 8, 0, 0, 0, utc),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 15 * FRI",
+			from: time.Date(2026, 1, 10, 0, 0, 0, 0, utc), // Saturday
+			want: time.Date(2026, 1, 15, 0, 0, 0, 0, utc),
+		},
+		{
+			name: "month wrap to next year",
+			expr: "@yearly",
+			from: time.Date(2026, 6, 1, 0, 0, 0, 0, utc),
+			want: time.Date(2027, 1, 1, 0, 0, 0, 0, utc),
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			from: time.Date(2026, 3, 1, 0, 0, 0, 0, utc),
+			want: time.Date(2028, 2, 29, 0, 0, 0, 0, utc),
+		},
+		{
+			name: "evaluated in the schedule time zone",
+			expr: "0 9 * * *",
+			from: time.Date(2026, 1, 1, 12, 0, 0, 0, ny),
+			want: time.Date(2026, 1, 2, 9, 0, 0, 0, ny),
+		},
+		{
+			name: "time skipped by spring forward does not fire",
+			expr: "30 2 * * *",
+			from: time.Date(2026, 3, 7, 12, 0, 0, 0, ny),
+			want: time.Date(2026, 3, 9, 2, 30, 0, 0, ny),
+		},
+		{
+			name: "never fires",
+			expr: "0 0 30 2 *",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, utc),
+			want: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+
+			got := s.Next(tt.from)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
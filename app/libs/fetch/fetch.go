@@ -0,0 +1,248 @@
+// Package fetch downloads remote attachments and images over https with an
+// egress safety layer against server-side request forgery.
+//
+// Every connection, including those made for redirects, is checked after
+// DNS resolution: the dialer refuses loopback, private, link-local and
+// other non-public addresses, so a hostname that resolves to an internal
+// address can't be used to reach internal services. Responses are limited
+// in size and time and must have an allowed content type.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL       = errors.New("invalid url")
+	ErrBlockedAddress   = errors.New("address not allowed")
+	ErrTooManyRedirects = errors.New("too many redirects")
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+	ErrTooLarge         = errors.New("content too large")
+	ErrContentType      = errors.New("content type not allowed")
+	ErrFetchFailed      = errors.New("fetch failed")
+)
+
+// Default limits used for zero config values
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxSize      = 10 << 20
+	DefaultMaxRedirects = 3
+)
+
+// DefaultContentTypes are the media types allowed when none are configured
+var DefaultContentTypes = []string{
+	"image/*",
+	"application/pdf",
+	"text/plain",
+	"text/csv",
+	"text/calendar",
+}
+
+// Config holds the limits of a fetcher
+type Config struct {
+	Timeout      time.Duration
+	MaxSize      int64
+	MaxRedirects int
+
+	// ContentTypes lists the allowed media types. A type ending in /*
+	// allows all subtypes.
+	ContentTypes []string
+
+	// AllowPrivate disables the address check, for local development only
+	AllowPrivate bool
+}
+
+// Resource is fetched content
+type Resource struct {
+	Content  []byte
+	MimeType string
+	Filename string
+}
+
+// Fetcher downloads remote content
+type Fetcher struct {
+	client *http.Client
+	config Config
+
+	// allowed decides whether an address may be connected to
+	allowed func(ip net.IP) bool
+}
+
+// New creates a fetcher, using defaults for zero config values
+func New(cfg Config) *Fetcher {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxSize
+	}
+	if cfg.MaxRedirects <= 0 {
+		cfg.MaxRedirects = DefaultMaxRedirects
+	}
+	if len(cfg.ContentTypes) == 0 {
+		cfg.ContentTypes = DefaultContentTypes
+	}
+
+	f := &Fetcher{config: cfg, allowed: IsPublic}
+	if cfg.AllowPrivate {
+		f.allowed = func(net.IP) bool { return true }
+	}
+
+	dialer := &net.Dialer{
+		Timeout: cfg.Timeout,
+		// Control runs after DNS resolution for every address dialed, so
+		// redirects and DNS rebinding can't bypass the check
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+			}
+			if ip := net.ParseIP(host); ip == nil || !f.allowed(ip) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+
+	f.client = &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			// A proxy would connect on our behalf and skip the address check
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   cfg.Timeout,
+			ResponseHeaderTimeout: cfg.Timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > cfg.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			return checkURL(req.URL)
+		},
+	}
+
+	return f
+}
+
+// Fetch downloads the content of an https URL
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Resource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if err := checkURL(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		// Surface the sentinel errors of the dialer and redirect policy
+		for _, sentinel := range []error{ErrBlockedAddress, ErrTooManyRedirects, ErrInvalidURL} {
+			if errors.Is(err, sentinel) {
+				return nil, fmt.Errorf("%w: %s", sentinel, rawURL)
+			}
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %d", ErrUnexpectedStatus, rawURL, resp.StatusCode)
+	}
+	if resp.ContentLength > f.config.MaxSize {
+		return nil, fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrTooLarge, rawURL, resp.ContentLength, f.config.MaxSize)
+	}
+
+	// Read one byte more than allowed to detect oversized bodies without a
+	// Content-Length
+	content, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	if int64(len(content)) > f.config.MaxSize {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrTooLarge, rawURL, f.config.MaxSize)
+	}
+
+	mimeType := mediaType(resp.Header.Get("Content-Type"))
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = mediaType(http.DetectContentType(content))
+	}
+	if !f.allowedType(mimeType) {
+		return nil, fmt.Errorf("%w: %s", ErrContentType, mimeType)
+	}
+
+	return &Resource{
+		Content:  content,
+		MimeType: mimeType,
+		Filename: filename(resp),
+	}, nil
+}
+
+// allowedType reports whether a media type is in the allowlist
+func (f *Fetcher) allowedType(mimeType string) bool {
+	for _, allowed := range f.config.ContentTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mimeType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mimeType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkURL only allows https URLs with a host and without credentials
+func checkURL(u *url.URL) error {
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: only https urls are allowed", ErrInvalidURL)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: credentials aren't allowed", ErrInvalidURL)
+	}
+	return nil
+}
+
+// mediaType returns the lowercase media type of a Content-Type value
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(mediaType)
+}
+
+// filename takes the name from Content-Disposition, falling back to the
+// last segment of the final URL
+func filename(resp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := path.Base(params["filename"]); params["filename"] != "" && name != "." && name != "/" {
+			return name
+		}
+	}
+	if name := path.Base(resp.Request.URL.Path); name != "." && name != "/" {
+		return name
+	}
+	return "attachment"
+}
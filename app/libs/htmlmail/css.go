@@ -0,0 +1,472 @@
+package htmlmail
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// declaration is a CSS property assignment
+type declaration struct {
+	property  string
+	value     string
+	important bool
+}
+
+// rule is a style rule with a single selector. Rules with a selector list
+// are split into one rule per selector.
+type rule struct {
+	selector     selector
+	declarations []declaration
+	order        int // position in the style sheets, later rules win ties
+}
+
+// parseStylesheet splits CSS into rules that can be inlined and the text of
+// rules that have to stay in a style block. order numbers the rules across
+// several style blocks.
+func parseStylesheet(css string, order *int) ([]rule, []string) {
+	css = stripComments(css)
+
+	var rules []rule
+	var retained []string
+	for i := 0; i < len(css); {
+		i = skipSpace(css, i)
+		if i >= len(css) {
+			break
+		}
+
+		// At-rules like @media or @font-face can't be expressed inline
+		if css[i] == '@' {
+			end := atRuleEnd(css, i)
+			retained = append(retained, strings.TrimSpace(css[i:end]))
+			i = end
+			continue
+		}
+
+		open := indexOutside(css, i, '{')
+		if open < 0 {
+			break
+		}
+		end := matchingBrace(css, open)
+		prelude := strings.TrimSpace(css[i:open])
+		body := css[open+1 : end]
+		declarations := parseDeclarations(body)
+
+		var kept []string
+		for _, text := range splitOutside(prelude, ',') {
+			text = strings.TrimSpace(text)
+			sel, ok := parseSelector(text)
+			if !ok {
+				kept = append(kept, text)
+				continue
+			}
+			rules = append(rules, rule{selector: sel, declarations: declarations, order: *order})
+			*order++
+		}
+		if len(kept) > 0 {
+			retained = append(retained, strings.Join(kept, ", ")+" { "+strings.TrimSpace(body)+" }")
+		}
+
+		i = end + 1
+	}
+
+	return rules, retained
+}
+
+// parseDeclarations parses the declarations of a rule or style attribute
+func parseDeclarations(text string) []declaration {
+	var declarations []declaration
+	for _, part := range splitOutside(text, ';') {
+		colon := strings.IndexByte(part, ':')
+		if colon < 0 {
+			continue
+		}
+
+		property := strings.ToLower(strings.TrimSpace(part[:colon]))
+		value := strings.TrimSpace(part[colon+1:])
+		important := false
+		if bang := strings.LastIndexByte(value, '!'); bang >= 0 && strings.EqualFold(strings.TrimSpace(value[bang+1:]), "important") {
+			value = strings.TrimSpace(value[:bang])
+			important = true
+		}
+		if property == "" || value == "" {
+			continue
+		}
+
+		declarations = append(declarations, declaration{property: property, value: value, important: important})
+	}
+	return declarations
+}
+
+// stripComments removes /* */ comments
+func stripComments(css string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			b.WriteString(css)
+			return b.String()
+		}
+		b.WriteString(css[:start])
+		end := strings.Index(css[start+2:], "*/")
+		if end < 0 {
+			return b.String()
+		}
+		css = css[start+2+end+2:]
+	}
+}
+
+// atRuleEnd returns the end of the at-rule starting at i, either after its
+// terminating semicolon or its block
+func atRuleEnd(css string, i int) int {
+	for j := i; j < len(css); j++ {
+		switch css[j] {
+		case ';':
+			return j + 1
+		case '{':
+			end := matchingBrace(css, j)
+			if end < len(css) {
+				end++
+			}
+			return end
+		case '"', '\'':
+			j = closingQuote(css, j)
+		}
+	}
+	return len(css)
+}
+
+// matchingBrace returns the index of the } closing the { at open, or the
+// end of the text for an unclosed block
+func matchingBrace(css string, open int) int {
+	depth := 0
+	for j := open; j < len(css); j++ {
+		switch css[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		case '"', '\'':
+			j = closingQuote(css, j)
+		}
+	}
+	return len(css)
+}
+
+// indexOutside returns the index of the first c at or after i that isn't
+// quoted
+func indexOutside(s string, i int, c byte) int {
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case c:
+			return j
+		case '"', '\'':
+			j = closingQuote(s, j)
+		}
+	}
+	return -1
+}
+
+// splitOutside splits s at separators that aren't quoted or inside
+// parentheses or brackets, like the ; in url(data:image/png;base64,...)
+func splitOutside(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for j := 0; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '"' || c == '\'':
+			j = closingQuote(s, j)
+		case c == '(' || c == '[':
+			depth++
+		case (c == ')' || c == ']') && depth > 0:
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:j])
+			start = j + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// closingQuote returns the index of the quote closing the one at i
+func closingQuote(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case s[i]:
+			return j
+		}
+	}
+	return len(s)
+}
+
+// skipSpace skips whitespace starting at i
+func skipSpace(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+// isSpace reports whether c is CSS or HTML whitespace
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// selector is a chain of compound selectors joined by combinators
+type selector struct {
+	compounds   []compound
+	combinators []byte // combinators[i] joins compounds[i] and compounds[i+1]: ' ' or '>'
+	specificity [3]int
+}
+
+// compound is a sequence of simple selectors matching a single element
+type compound struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []attrSelector
+}
+
+// attrSelector matches an attribute. An empty operator tests presence.
+type attrSelector struct {
+	name     string
+	operator string
+	value    string
+}
+
+// parseSelector parses a selector. Selectors with pseudo-classes,
+// pseudo-elements, sibling combinators or escapes aren't supported.
+func parseSelector(text string) (selector, bool) {
+	var sel selector
+	var current *compound
+	combinator := byte(0)
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case isSpace(c):
+			if current != nil && combinator == 0 {
+				combinator = ' '
+			}
+			i++
+			continue
+		case c == '>':
+			if current == nil {
+				return selector{}, false
+			}
+			combinator = '>'
+			i++
+			continue
+		}
+
+		// Start a new compound after a combinator
+		if current == nil || combinator != 0 {
+			if current != nil {
+				sel.combinators = append(sel.combinators, combinator)
+			}
+			sel.compounds = append(sel.compounds, compound{})
+			current = &sel.compounds[len(sel.compounds)-1]
+			combinator = 0
+
+			if c == '*' {
+				i++
+				continue
+			}
+			if isIdentStart(c) {
+				end := identEnd(text, i)
+				current.tag = strings.ToLower(text[i:end])
+				sel.specificity[2]++
+				i = end
+				continue
+			}
+		}
+
+		switch c {
+		case '.', '#':
+			end := identEnd(text, i+1)
+			if end == i+1 {
+				return selector{}, false
+			}
+			if c == '.' {
+				current.classes = append(current.classes, text[i+1:end])
+				sel.specificity[1]++
+			} else {
+				if current.id != "" {
+					return selector{}, false
+				}
+				current.id = text[i+1 : end]
+				sel.specificity[0]++
+			}
+			i = end
+		case '[':
+			end := indexOutside(text, i, ']')
+			if end < 0 {
+				return selector{}, false
+			}
+			attr, ok := parseAttrSelector(text[i+1 : end])
+			if !ok {
+				return selector{}, false
+			}
+			current.attrs = append(current.attrs, attr)
+			sel.specificity[1]++
+			i = end + 1
+		default:
+			return selector{}, false
+		}
+	}
+
+	if len(sel.compounds) == 0 || combinator == '>' {
+		return selector{}, false
+	}
+	return sel, true
+}
+
+// parseAttrSelector parses the content of an attribute selector
+func parseAttrSelector(text string) (attrSelector, bool) {
+	text = strings.TrimSpace(text)
+	end := identEnd(text, 0)
+	if end == 0 {
+		return attrSelector{}, false
+	}
+	attr := attrSelector{name: strings.ToLower(text[:end])}
+
+	rest := strings.TrimSpace(text[end:])
+	if rest == "" {
+		return attr, true
+	}
+
+	for _, operator := range []string{"~=", "|=", "^=", "$=", "*=", "="} {
+		if strings.HasPrefix(rest, operator) {
+			attr.operator = operator
+			rest = strings.TrimSpace(rest[len(operator):])
+			break
+		}
+	}
+	if attr.operator == "" || rest == "" {
+		return attrSelector{}, false
+	}
+
+	if rest[0] == '"' || rest[0] == '\'' {
+		if len(rest) < 2 || rest[len(rest)-1] != rest[0] {
+			return attrSelector{}, false
+		}
+		attr.value = rest[1 : len(rest)-1]
+	} else {
+		if identEnd(rest, 0) != len(rest) {
+			return attrSelector{}, false
+		}
+		attr.value = rest
+	}
+	return attr, true
+}
+
+// isIdentStart reports whether c can start a CSS identifier
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '-' || c >= 0x80
+}
+
+// identEnd returns the end of the identifier starting at i
+func identEnd(s string, i int) int {
+	for i < len(s) && (isIdentStart(s[i]) || s[i] >= '0' && s[i] <= '9') {
+		i++
+	}
+	return i
+}
+
+// matches reports whether the selector matches an element
+func (s selector) matches(n *html.Node) bool {
+	return s.matchesAt(n, len(s.compounds)-1)
+}
+
+// matchesAt matches the compounds up to i, with compound i matching n
+func (s selector) matchesAt(n *html.Node, i int) bool {
+	if !s.compounds[i].matches(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	if s.combinators[i-1] == '>' {
+		parent := parentElement(n)
+		return parent != nil && s.matchesAt(parent, i-1)
+	}
+	for parent := parentElement(n); parent != nil; parent = parentElement(parent) {
+		if s.matchesAt(parent, i-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether all simple selectors of the compound match n
+func (c compound) matches(n *html.Node) bool {
+	if c.tag != "" && n.Data != c.tag {
+		return false
+	}
+	if c.id != "" {
+		if id, _ := attr(n, "id"); id != c.id {
+			return false
+		}
+	}
+	if len(c.classes) > 0 {
+		class, _ := attr(n, "class")
+		classes := strings.Fields(class)
+		for _, want := range c.classes {
+			if !contains(classes, want) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		value, ok := attr(n, a.name)
+		if !ok || !a.matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches tests an attribute value against the selector
+func (a attrSelector) matches(value string) bool {
+	switch a.operator {
+	case "":
+		return true
+	case "=":
+		return value == a.value
+	case "~=":
+		return contains(strings.Fields(value), a.value)
+	case "|=":
+		return value == a.value || strings.HasPrefix(value, a.value+"-")
+	case "^=":
+		return a.value != "" && strings.HasPrefix(value, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(value, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(value, a.value)
+	}
+	return false
+}
+
+// parentElement returns the parent of n if it is an element
+func parentElement(n *html.Node) *html.Node {
+	if n.Parent == nil || n.Parent.Type != html.ElementNode {
+		return nil
+	}
+	return n.Parent
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+// Package htmlmail prepares HTML email bodies for sending: it inlines the
+// CSS of <style> blocks into style attributes, derives a plain text
+// alternative, inserts a hidden preheader shown in inbox previews and
+// rewrites links, such as for click tracking.
+//
+// Inlining supports type, class, id, universal and attribute selectors,
+// compound selectors and the descendant and child combinators. Rules the
+// inliner can't apply to elements, such as @media blocks or selectors with
+// pseudo-classes, are kept in a <style> block in the head.
+package htmlmail
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	ErrInvalidHTML = errors.New("invalid html")
+)
+
+// Document is a parsed HTML email body
+type Document struct {
+	root *html.Node
+}
+
+// Parse parses an HTML document or fragment. Fragments are wrapped in
+// html, head and body elements.
+func Parse(source string) (*Document, error) {
+	root, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHTML, err)
+	}
+	return &Document{root: root}, nil
+}
+
+// HTML renders the document
+func (d *Document) HTML() (string, error) {
+	var b strings.Builder
+	if err := html.Render(&b, d.root); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidHTML, err)
+	}
+	return b.String(), nil
+}
+
+// findElement returns the first element of the given type in document order
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// attr returns the value of an attribute of an element
+func attr(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// setAttr sets an attribute of an element, replacing an existing value
+func setAttr(n *html.Node, name, value string) {
+	for i, a := range n.Attr {
+		if a.Namespace == "" && a.Key == name {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: name, Val: value})
+}
+
+// textContent concatenates the text nodes below n
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
@@ -0,0 +1,230 @@
+package htmlmail
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inlined parses source, inlines its CSS and returns the rendered body
+func inlined(t *testing.T, source string) string {
+	t.Helper()
+	doc, err := Parse(source)
+	require.NoError(t, err)
+	doc.InlineCSS()
+	out, err := doc.HTML()
+	require.NoError(t, err)
+	return out
+}
+
+func TestInlineCSS(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "type, class and id selectors",
+			source: `<style>p { color: red } .lead { font-size: 18px } #intro { margin: 0 }</style><p class="lead" id="intro">Hi</p>`,
+			want:   []string{`<p class="lead" id="intro" style="color: red; font-size: 18px; margin: 0">Hi</p>`},
+		},
+		{
+			name:   "specificity beats order",
+			source: `<style>p.note { color: red } p { color: blue }</style><p class="note">x</p>`,
+			want:   []string{`style="color: red"`},
+		},
+		{
+			name:   "later rule wins ties",
+			source: `<style>.a { color: red } .b { color: blue }</style><p class="b a">x</p>`,
+			want:   []string{`style="color: blue"`},
+		},
+		{
+			name:   "style attribute wins over normal rules",
+			source: `<style>p { color: red; margin: 0 }</style><p style="color: green">x</p>`,
+			want:   []string{`style="color: green; margin: 0"`},
+		},
+		{
+			name:   "important rule wins over style attribute",
+			source: `<style>p { color: red !important }</style><p style="color: green">x</p>`,
+			want:   []string{`style="color: red"`},
+		},
+		{
+			name:   "descendant and child combinators",
+			source: `<style>div a { color: red } div > span { color: blue }</style><div><p><a href="/">a</a><span>s</span></p><span>t</span></div>`,
+			want:   []string{`<a href="/" style="color: red">a</a><span>s</span>`, `<span style="color: blue">t</span>`},
+		},
+		{
+			name:   "attribute selectors",
+			source: `<style>a[href^="https"] { color: green } [data-x] { margin: 0 }</style><a href="https://a">s</a><a href="http://b" data-x="">p</a>`,
+			want:   []string{`<a href="https://a" style="color: green">s</a>`, `<a href="http://b" data-x="" style="margin: 0">p</a>`},
+		},
+		{
+			name:   "semicolons inside urls",
+			source: `<style>td { background: url(data:image/png;base64,AA==); color: red }</style><table><tr><td>x</td></tr></table>`,
+			want:   []string{`<td style="background: url(data:image/png;base64,AA==); color: red">x</td>`},
+		},
+		{
+			name:   "uninlinable rules stay in the head",
+			source: "<html><head><style>/* c */ a:hover { color: red } @media (max-width: 600px) { .w { width: 100% !important } } .w { width: 600px }</style></head><body><div class=\"w\">x</div></body></html>",
+			want: []string{
+				"<head><style>\na:hover { color: red }\n@media (max-width: 600px) { .w { width: 100% !important } }\n</style></head>",
+				`<div class="w" style="width: 600px">x</div>`,
+			},
+		},
+		{
+			name:   "print styles are left alone",
+			source: `<style media="print">p { color: black }</style><p>x</p>`,
+			want:   []string{`<style media="print">p { color: black }</style>`, `<p>x</p>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := inlined(t, tt.source)
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+		})
+	}
+}
+
+func TestInlineCSS_RemovesStyleBlocks(t *testing.T) {
+	got := inlined(t, `<style>p { color: red }</style><p>x</p>`)
+	assert.NotContains(t, got, "<style")
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		text        string
+		ok          bool
+		specificity [3]int
+	}{
+		{text: "p", ok: true, specificity: [3]int{0, 0, 1}},
+		{text: "*", ok: true},
+		{text: "div#main p.note > a[href]", ok: true, specificity: [3]int{1, 2, 3}},
+		{text: "a:hover"},
+		{text: "p::first-line"},
+		{text: "h1 + p"},
+		{text: "h1 ~ p"},
+		{text: "p >"},
+		{text: "#a#b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			sel, ok := parseSelector(tt.text)
+			assert.Equal(t, tt.ok, ok)
+			if ok {
+				assert.Equal(t, tt.specificity, sel.specificity)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "paragraphs and whitespace",
+			source: "<h1>Hello</h1>\n<p>  Some\n   text\u00a0here. </p><p>Next</p>",
+			want:   "Hello\n\nSome text here.\n\nNext",
+		},
+		{
+			name:   "links as footnotes",
+			source: `<p>Read <a href="https://a.example/docs">the docs</a>, <a href="https://b.example">this</a> and <a href="https://a.example/docs">again</a>.</p>`,
+			want:   "Read the docs [1], this [2] and again [1].\n\n[1] https://a.example/docs\n[2] https://b.example",
+		},
+		{
+			name:   "links without footnotes",
+			source: `<a href="mailto:a@example.com">a@example.com</a> <a href="#top">top</a> <a href="https://x.example">https://x.example</a>`,
+			want:   "a@example.com top https://x.example",
+		},
+		{
+			name:   "tables are flattened",
+			source: `<table><tr><th>Item</th><th>Price</th></tr><tr><td>Book</td><td>10</td></tr><tr><td></td><td>2</td></tr></table>`,
+			want:   "Item | Price\nBook | 10\n2",
+		},
+		{
+			name:   "layout tables",
+			source: `<table><tr><td><table><tr><td><p>Header</p></td></tr></table></td></tr><tr><td><p>Body</p></td></tr></table>`,
+			want:   "Header\n\nBody",
+		},
+		{
+			name:   "lists",
+			source: `<ul><li>One</li><li>Two<ul><li>Nested</li></ul></li></ul><ol start="3"><li>Three</li><li>Four</li></ol>`,
+			want:   "- One\n- Two\n  - Nested\n\n3. Three\n4. Four",
+		},
+		{
+			name:   "block quotes",
+			source: `<p>Intro</p><blockquote><p>One</p><p>Two</p></blockquote><p>After</p>`,
+			want:   "Intro\n\n> One\n>\n> Two\n\nAfter",
+		},
+		{
+			name:   "line breaks and rules",
+			source: `<p>a<br>b<br><br>c</p><hr><p>d</p>`,
+			want:   "a\nb\n\nc\n\n----------\n\nd",
+		},
+		{
+			name:   "preformatted text",
+			source: "<pre>line 1\n  line 2\n</pre>",
+			want:   "line 1\n  line 2",
+		},
+		{
+			name:   "hidden and invisible content is skipped",
+			source: "<head><title>T</title></head><body><div style=\"display:none\">pre</div><div hidden>h</div><script>x()</script><p>Body\u200c</p><img alt=\"Logo\" src=\"l.png\"></body>",
+			want:   "Body\n\nLogo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, doc.Text())
+		})
+	}
+}
+
+func TestAddPreheader(t *testing.T) {
+	doc, err := Parse(`<html><body><p>Body</p></body></html>`)
+	require.NoError(t, err)
+
+	doc.AddPreheader("  Your order shipped <today>  ")
+	got, err := doc.HTML()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(got, `<html><head></head><body><div style="display: none;`), got)
+	assert.Contains(t, got, "Your order shipped &lt;today&gt;\u200c\u00a0")
+	assert.Equal(t, "Body", doc.Text())
+}
+
+func TestAddPreheader_Empty(t *testing.T) {
+	doc, err := Parse(`<p>Body</p>`)
+	require.NoError(t, err)
+
+	doc.AddPreheader("  ")
+	got, err := doc.HTML()
+	require.NoError(t, err)
+	assert.Equal(t, `<html><head></head><body><p>Body</p></body></html>`, got)
+}
+
+func TestRewriteLinks(t *testing.T) {
+	doc, err := Parse(`<p><a href="https://example.com/a?x=1&amp;y=2">A</a> <a href="https://example.com/b" data-notrack>B</a> <a name="top">C</a> <a href="">D</a></p>`)
+	require.NoError(t, err)
+
+	var seen []string
+	doc.RewriteLinks(func(href string) string {
+		seen = append(seen, href)
+		return "https://mail.example.com/t/c/1"
+	})
+	got, err := doc.HTML()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://example.com/a?x=1&y=2"}, seen)
+	assert.Equal(t, `<html><head></head><body><p><a href="https://mail.example.com/t/c/1">A</a> <a href="https://example.com/b">B</a> <a name="top">C</a> <a href="">D</a></p></body></html>`, got)
+}
@@ -0,0 +1,138 @@
+package htmlmail
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// weightedDeclaration is a declaration with its position in the cascade
+type weightedDeclaration struct {
+	declaration
+	inline      bool // from the element's own style attribute
+	specificity [3]int
+	order       int
+}
+
+// InlineCSS applies the rules of the document's <style> blocks to the style
+// attributes of the elements they match. The cascade follows CSS: important
+// declarations win over normal ones, a style attribute wins over style
+// sheets and otherwise the more specific, later rule wins. Style blocks with
+// a media attribute other than all or screen are left untouched. Rules that
+// can't be inlined are kept in a single style block in the head.
+func (d *Document) InlineCSS() {
+	var styles []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Style {
+			media, _ := attr(n, "media")
+			switch strings.ToLower(strings.TrimSpace(media)) {
+			case "", "all", "screen":
+				styles = append(styles, n)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(d.root)
+	if len(styles) == 0 {
+		return
+	}
+
+	order := 0
+	var rules []rule
+	var retained []string
+	for _, style := range styles {
+		r, kept := parseStylesheet(textContent(style), &order)
+		rules = append(rules, r...)
+		retained = append(retained, kept...)
+		style.Parent.RemoveChild(style)
+	}
+
+	if body := findElement(d.root, atom.Body); body != nil {
+		applyRules(body, rules)
+	}
+
+	if len(retained) > 0 {
+		if head := findElement(d.root, atom.Head); head != nil {
+			style := &html.Node{Type: html.ElementNode, Data: "style", DataAtom: atom.Style}
+			style.AppendChild(&html.Node{Type: html.TextNode, Data: "\n" + strings.Join(retained, "\n") + "\n"})
+			head.AppendChild(style)
+		}
+	}
+}
+
+// applyRules sets the style attribute of n and its descendants to the
+// result of the cascade
+func applyRules(n *html.Node, rules []rule) {
+	if n.Type == html.ElementNode {
+		switch n.DataAtom {
+		case atom.Script, atom.Style, atom.Template:
+			return
+		}
+
+		var matched []weightedDeclaration
+		for _, r := range rules {
+			if !r.selector.matches(n) {
+				continue
+			}
+			for _, decl := range r.declarations {
+				matched = append(matched, weightedDeclaration{declaration: decl, specificity: r.selector.specificity, order: r.order})
+			}
+		}
+
+		// Elements no rule matches keep their style attribute as written
+		if len(matched) > 0 {
+			style, _ := attr(n, "style")
+			for i, decl := range parseDeclarations(style) {
+				matched = append(matched, weightedDeclaration{declaration: decl, inline: true, order: i})
+			}
+			setAttr(n, "style", cascade(matched))
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		applyRules(c, rules)
+	}
+}
+
+// cascade resolves the declarations matching an element into the value of
+// its style attribute. Properties keep the position they first appear at.
+func cascade(matched []weightedDeclaration) string {
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.important != b.important {
+			return !a.important
+		}
+		if a.inline != b.inline {
+			return !a.inline
+		}
+		if a.specificity != b.specificity {
+			for k := range a.specificity {
+				if a.specificity[k] != b.specificity[k] {
+					return a.specificity[k] < b.specificity[k]
+				}
+			}
+		}
+		return a.order < b.order
+	})
+
+	var properties []string
+	values := make(map[string]string)
+	for _, decl := range matched {
+		if _, ok := values[decl.property]; !ok {
+			properties = append(properties, decl.property)
+		}
+		values[decl.property] = decl.value
+	}
+
+	parts := make([]string, 0, len(properties))
+	for _, property := range properties {
+		parts = append(parts, property+": "+values[property])
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,40 @@
+package htmlmail
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// notrackAttr marks links that must not be rewritten
+const notrackAttr = "data-notrack"
+
+// RewriteLinks replaces the href of every link with what rewrite returns
+// for it. Links with a data-notrack attribute keep their href, and the
+// attribute is removed so it doesn't reach recipients.
+func (d *Document) RewriteLinks(rewrite func(href string) string) {
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			if _, notrack := attr(n, notrackAttr); notrack {
+				removeAttr(n, notrackAttr)
+			} else if href, ok := attr(n, "href"); ok && href != "" {
+				setAttr(n, "href", rewrite(href))
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(d.root)
+}
+
+// removeAttr removes an attribute of an element
+func removeAttr(n *html.Node, name string) {
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		if a.Namespace != "" || a.Key != name {
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attr = attrs
+}
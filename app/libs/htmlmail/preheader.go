@@ -0,0 +1,34 @@
+package htmlmail
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// preheaderStyle hides the preheader in the message while inbox previews
+// still show it
+const preheaderStyle = "display: none; font-size: 1px; line-height: 1px; max-height: 0; max-width: 0; opacity: 0; overflow: hidden; mso-hide: all"
+
+// preheaderPadding follows the preheader so previews don't continue with
+// the start of the body
+var preheaderPadding = strings.Repeat("\u200c\u00a0", 80)
+
+// AddPreheader inserts text as the first, hidden element of the body
+func (d *Document) AddPreheader(text string) {
+	text = strings.TrimSpace(text)
+	body := findElement(d.root, atom.Body)
+	if text == "" || body == nil {
+		return
+	}
+
+	div := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+		Attr:     []html.Attribute{{Key: "style", Val: preheaderStyle}},
+	}
+	div.AppendChild(&html.Node{Type: html.TextNode, Data: text + preheaderPadding})
+	body.InsertBefore(div, body.FirstChild)
+}
@@ -0,0 +1,349 @@
+package htmlmail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// invisibleReplacer drops zero-width characters email layouts use as
+// spacers and turns no-break spaces into spaces
+var invisibleReplacer = strings.NewReplacer("\u200b", "", "\u200c", "", "\u200d", "", "\u034f", "", "\u00ad", "", "\ufeff", "", "\u00a0", " ")
+
+// Text renders the document as plain text. Links are numbered and listed
+// as footnotes, tables are flattened to one line per row with cells
+// separated by " | ", and hidden elements are skipped.
+func (d *Document) Text() string {
+	w := &textWriter{atLineStart: true, footnotes: make(map[string]int)}
+	w.walk(d.root)
+
+	text := strings.TrimRight(w.out.String(), " \n")
+	if len(w.links) > 0 {
+		var b strings.Builder
+		b.WriteString(text)
+		b.WriteString("\n\n")
+		for i, link := range w.links {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "[%d] %s", i+1, link)
+		}
+		text = b.String()
+	}
+	return text
+}
+
+// linePrefix is written at the start of the lines of a block quote or list item
+type linePrefix struct {
+	first string // prefix of the first line, e.g. a list bullet
+	rest  string // prefix of the following lines
+	used  bool
+}
+
+// textWriter collects plain text while collapsing whitespace and blank lines
+type textWriter struct {
+	out         strings.Builder
+	started     bool   // any text was written
+	atLineStart bool   // the next word starts a line
+	space       bool   // a space is pending before the next word
+	separator   string // a cell separator is pending before the next word
+	breaks      int    // line breaks pending before the next word
+	blankPrefix string // prefix of pending blank lines
+	prefixes    []*linePrefix
+	listDepth   int
+	links       []string
+	footnotes   map[string]int
+}
+
+// walk writes a node and its children
+func (w *textWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.writeText(n.Data)
+		return
+	case html.DocumentNode:
+		w.walkChildren(n)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if isHidden(n) {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Head, atom.Script, atom.Style, atom.Template, atom.Title:
+		return
+
+	case atom.Br:
+		w.lineBreak()
+
+	case atom.Hr:
+		w.block(2)
+		w.writeWord("----------")
+		w.block(2)
+
+	case atom.Img:
+		if alt, _ := attr(n, "alt"); strings.TrimSpace(alt) != "" {
+			w.writeText(alt)
+		}
+
+	case atom.A:
+		start := w.out.Len()
+		w.walkChildren(n)
+		text := strings.TrimSpace(w.out.String()[start:])
+		href, _ := attr(n, "href")
+		if number := w.footnote(strings.TrimSpace(href), text); number > 0 {
+			w.space = true
+			w.writeWord("[" + strconv.Itoa(number) + "]")
+		}
+
+	case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Table, atom.Dl:
+		w.block(2)
+		w.walkChildren(n)
+		w.block(2)
+
+	case atom.Pre:
+		w.block(2)
+		w.writePre(strings.TrimRight(textContent(n), "\n"))
+		w.block(2)
+
+	case atom.Blockquote:
+		w.block(2)
+		w.prefixes = append(w.prefixes, &linePrefix{first: "> ", rest: "> "})
+		w.walkChildren(n)
+		w.prefixes = w.prefixes[:len(w.prefixes)-1]
+		w.block(2)
+
+	case atom.Ul, atom.Ol:
+		breaks := 2
+		if w.listDepth > 0 {
+			breaks = 1
+		}
+		w.block(breaks)
+		w.listDepth++
+		w.walkList(n)
+		w.listDepth--
+		w.block(breaks)
+
+	case atom.Tr:
+		w.block(1)
+		cells := 0
+		rowStart := w.out.Len()
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.DataAtom == atom.Td || c.DataAtom == atom.Th) && !isHidden(c) {
+				if cells > 0 && w.out.Len() > rowStart {
+					w.separator = " | "
+				}
+				cells++
+			}
+			w.walk(c)
+		}
+		w.separator = ""
+		w.block(1)
+
+	case atom.Div, atom.Li, atom.Dt, atom.Dd, atom.Section, atom.Article, atom.Header, atom.Footer,
+		atom.Main, atom.Nav, atom.Aside, atom.Center, atom.Address, atom.Figure, atom.Figcaption, atom.Caption:
+		w.block(1)
+		w.walkChildren(n)
+		w.block(1)
+
+	default:
+		w.walkChildren(n)
+	}
+}
+
+// walkChildren writes the children of a node
+func (w *textWriter) walkChildren(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c)
+	}
+}
+
+// walkList writes the items of a list with bullets or numbers
+func (w *textWriter) walkList(n *html.Node) {
+	number := 1
+	if start, ok := attr(n, "start"); ok {
+		if parsed, err := strconv.Atoi(strings.TrimSpace(start)); err == nil {
+			number = parsed
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			w.walk(c)
+			continue
+		}
+		if isHidden(c) {
+			continue
+		}
+
+		bullet := "- "
+		if n.DataAtom == atom.Ol {
+			bullet = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		w.block(1)
+		w.prefixes = append(w.prefixes, &linePrefix{first: bullet, rest: strings.Repeat(" ", len(bullet))})
+		w.walkChildren(c)
+		w.prefixes = w.prefixes[:len(w.prefixes)-1]
+		w.block(1)
+	}
+}
+
+// footnote returns the number of the footnote for a link, or 0 when the
+// link isn't worth a footnote
+func (w *textWriter) footnote(href, text string) int {
+	if href == "" || strings.HasPrefix(href, "#") || href == text || href == "mailto:"+text || href == "tel:"+text {
+		return 0
+	}
+	if colon := strings.IndexByte(href, ':'); colon >= 0 && strings.EqualFold(href[:colon], "javascript") {
+		return 0
+	}
+
+	if number, ok := w.footnotes[href]; ok {
+		return number
+	}
+	w.links = append(w.links, href)
+	w.footnotes[href] = len(w.links)
+	return len(w.links)
+}
+
+// writeText writes text with whitespace collapsed
+func (w *textWriter) writeText(text string) {
+	text = invisibleReplacer.Replace(text)
+	if text == "" {
+		return
+	}
+
+	if isSpace(text[0]) {
+		w.space = true
+	}
+	for i, word := range strings.Fields(text) {
+		if i > 0 {
+			w.space = true
+		}
+		w.writeWord(word)
+	}
+	if isSpace(text[len(text)-1]) {
+		w.space = true
+	}
+}
+
+// writeWord writes a word, preceded by pending line breaks, prefixes,
+// separators or spaces
+func (w *textWriter) writeWord(word string) {
+	w.flushBreaks()
+
+	switch {
+	case w.atLineStart:
+		for _, prefix := range w.prefixes {
+			if prefix.used {
+				w.out.WriteString(prefix.rest)
+			} else {
+				w.out.WriteString(prefix.first)
+				prefix.used = true
+			}
+		}
+		w.atLineStart = false
+	case w.separator != "":
+		w.out.WriteString(w.separator)
+	case w.space:
+		w.out.WriteByte(' ')
+	}
+
+	w.out.WriteString(word)
+	w.space, w.separator = false, ""
+	w.started = true
+}
+
+// writePre writes preformatted text line by line
+func (w *textWriter) writePre(text string) {
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			w.flushBreaks()
+			w.out.WriteByte('\n')
+			w.atLineStart = true
+		}
+		if line != "" {
+			w.writeWord(line)
+		}
+	}
+}
+
+// block requests line breaks before the next word: 1 starts a new line, 2
+// leaves a blank line. Requests don't add up.
+func (w *textWriter) block(breaks int) {
+	w.requestBreaks(breaks)
+	w.separator = ""
+}
+
+// lineBreak handles <br>: each one ends the current line, a second one
+// leaves a blank line
+func (w *textWriter) lineBreak() {
+	breaks := 1
+	if w.breaks > 0 {
+		breaks = 2
+	}
+	w.requestBreaks(breaks)
+	w.separator = ""
+}
+
+// requestBreaks raises the number of pending line breaks. Blank lines use
+// the shortest prefix requested, so the blank line after a block quote
+// isn't quoted.
+func (w *textWriter) requestBreaks(breaks int) {
+	if prefix := w.currentBlankPrefix(); w.breaks == 0 || len(prefix) < len(w.blankPrefix) {
+		w.blankPrefix = prefix
+	}
+	if breaks > w.breaks {
+		w.breaks = breaks
+	}
+}
+
+// flushBreaks writes the pending line breaks
+func (w *textWriter) flushBreaks() {
+	if w.breaks == 0 {
+		return
+	}
+	if w.started {
+		w.out.WriteByte('\n')
+		for i := 1; i < w.breaks; i++ {
+			w.out.WriteString(w.blankPrefix)
+			w.out.WriteByte('\n')
+		}
+		w.atLineStart = true
+	}
+	w.breaks, w.blankPrefix = 0, ""
+}
+
+// currentBlankPrefix returns the prefix of a blank line at the current
+// nesting, e.g. ">" inside a block quote
+func (w *textWriter) currentBlankPrefix() string {
+	var b strings.Builder
+	for _, prefix := range w.prefixes {
+		b.WriteString(prefix.rest)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// isHidden reports whether an element is hidden with the hidden attribute
+// or a display: none style
+func isHidden(n *html.Node) bool {
+	if _, ok := attr(n, "hidden"); ok {
+		return true
+	}
+	style, _ := attr(n, "style")
+	for _, decl := range parseDeclarations(style) {
+		if decl.property == "display" && strings.EqualFold(decl.value, "none") {
+			return true
+		}
+	}
+	return false
+}
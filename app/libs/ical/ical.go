@@ -0,0 +1,275 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Methods supported by the iTIP (RFC 5546) scheduling messages we build
+const (
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+const (
+	prodID          = "-//GoMail//GoMail Calendar//EN"
+	dateTimeFormat  = "20060102T150405"
+	utcFormat       = "20060102T150405Z"
+	maxLineOctets   = 75
+	defaultAttendee = "REQ-PARTICIPANT"
+)
+
+var (
+	ErrInvalidMethod    = errors.New("invalid calendar method")
+	ErrMissingUID       = errors.New("event UID is required")
+	ErrMissingOrganizer = errors.New("event organizer email is required")
+	ErrMissingAttendees = errors.New("event requires at least one attendee")
+	ErrInvalidTimeRange = errors.New("event end must be after start")
+	ErrUnknownTimeZone  = errors.New("unknown time zone")
+	ErrInvalidRRule     = errors.New("invalid recurrence rule")
+)
+
+// Person identifies an organizer by name and email address
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Attendee represents an invited participant of an event
+type Attendee struct {
+	Name  string
+	Email string
+	Role  string
+	RSVP  bool
+}
+
+// Event represents a single calendar event to be sent as an iTIP message
+type Event struct {
+	Method         string
+	UID            string
+	Sequence       int
+	Organizer      Person
+	Attendees      []Attendee
+	Summary        string
+	Description    string
+	Location       string
+	Start          time.Time
+	End            time.Time
+	TimeZone       string
+	RecurrenceRule string
+	Timestamp      time.Time
+}
+
+// Build renders the event as an iCalendar object (RFC 5545)
+func Build(event Event) ([]byte, error) {
+	if err := validate(event); err != nil {
+		return nil, err
+	}
+
+	loc, err := loadLocation(event.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	stamp := event.Timestamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("PRODID:" + prodID)
+	w.line("VERSION:2.0")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:" + event.Method)
+
+	if loc != time.UTC {
+		writeTimeZone(w, loc, event.Start.In(loc).Year())
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + escapeText(event.UID))
+	w.line(fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+	w.line("DTSTAMP:" + stamp.UTC().Format(utcFormat))
+	w.line(formatDateTime("DTSTART", event.Start, loc))
+	w.line(formatDateTime("DTEND", event.End, loc))
+	if event.RecurrenceRule != "" {
+		w.line("RRULE:" + normalizeRRule(event.RecurrenceRule))
+	}
+	if event.Summary != "" {
+		w.line("SUMMARY:" + escapeText(event.Summary))
+	}
+	if event.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(event.Description))
+	}
+	if event.Location != "" {
+		w.line("LOCATION:" + escapeText(event.Location))
+	}
+	w.line("ORGANIZER" + commonName(event.Organizer.Name) + ":mailto:" + event.Organizer.Email)
+	for _, attendee := range event.Attendees {
+		w.line(formatAttendee(attendee, event.Method))
+	}
+	if event.Method == MethodCancel {
+		w.line("STATUS:CANCELLED")
+	} else {
+		w.line("STATUS:CONFIRMED")
+	}
+	w.line("TRANSP:OPAQUE")
+	w.line("END:VEVENT")
+	w.line("END:VCALENDAR")
+
+	return []byte(w.String()), nil
+}
+
+// validate checks the event for the fields required by iTIP
+func validate(event Event) error {
+	if event.Method != MethodRequest && event.Method != MethodCancel {
+		return fmt.Errorf("%w: %q", ErrInvalidMethod, event.Method)
+	}
+	if strings.TrimSpace(event.UID) == "" {
+		return ErrMissingUID
+	}
+	if strings.TrimSpace(event.Organizer.Email) == "" {
+		return ErrMissingOrganizer
+	}
+	if len(event.Attendees) == 0 {
+		return ErrMissingAttendees
+	}
+	if !event.End.After(event.Start) {
+		return ErrInvalidTimeRange
+	}
+	if event.RecurrenceRule != "" {
+		if err := validateRRule(event.RecurrenceRule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRRule performs a structural check of a recurrence rule
+func validateRRule(rule string) error {
+	allowed := map[string]bool{
+		"FREQ": true, "UNTIL": true, "COUNT": true, "INTERVAL": true,
+		"BYSECOND": true, "BYMINUTE": true, "BYHOUR": true, "BYDAY": true,
+		"BYMONTHDAY": true, "BYYEARDAY": true, "BYWEEKNO": true, "BYMONTH": true,
+		"BYSETPOS": true, "WKST": true,
+	}
+	frequencies := map[string]bool{
+		"SECONDLY": true, "MINUTELY": true, "HOURLY": true, "DAILY": true,
+		"WEEKLY": true, "MONTHLY": true, "YEARLY": true,
+	}
+
+	hasFreq := false
+	for _, part := range strings.Split(normalizeRRule(rule), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" || !allowed[key] {
+			return fmt.Errorf("%w: %q", ErrInvalidRRule, part)
+		}
+		if key == "FREQ" {
+			if !frequencies[value] {
+				return fmt.Errorf("%w: unknown frequency %q", ErrInvalidRRule, value)
+			}
+			hasFreq = true
+		}
+	}
+	if !hasFreq {
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidRRule)
+	}
+	return nil
+}
+
+// normalizeRRule upper-cases a rule and strips an optional RRULE: prefix
+func normalizeRRule(rule string) string {
+	return strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+}
+
+// loadLocation resolves an IANA time zone name, defaulting to UTC
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" || strings.EqualFold(name, "UTC") {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTimeZone, name)
+	}
+	return loc, nil
+}
+
+// formatDateTime renders a DATE-TIME property in UTC or with a TZID parameter
+func formatDateTime(name string, t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return name + ":" + t.UTC().Format(utcFormat)
+	}
+	return name + ";TZID=" + loc.String() + ":" + t.In(loc).Format(dateTimeFormat)
+}
+
+// formatAttendee renders an ATTENDEE property
+func formatAttendee(attendee Attendee, method string) string {
+	role := attendee.Role
+	if role == "" {
+		role = defaultAttendee
+	}
+
+	var b strings.Builder
+	b.WriteString("ATTENDEE")
+	b.WriteString(commonName(attendee.Name))
+	b.WriteString(";ROLE=" + strings.ToUpper(role))
+	if method == MethodRequest {
+		b.WriteString(";PARTSTAT=NEEDS-ACTION")
+		if attendee.RSVP {
+			b.WriteString(";RSVP=TRUE")
+		}
+	}
+	b.WriteString(":mailto:" + attendee.Email)
+	return b.String()
+}
+
+// commonName renders a CN parameter, quoting it when required
+func commonName(name string) string {
+	name = strings.TrimSpace(strings.ReplaceAll(name, `"`, ""))
+	if name == "" {
+		return ""
+	}
+	if strings.ContainsAny(name, ":;,") {
+		return `;CN="` + name + `"`
+	}
+	return ";CN=" + name
+}
+
+// escapeText escapes a TEXT value as described in RFC 5545 section 3.3.11
+func escapeText(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(s)
+}
+
+// writer accumulates content lines, folding them at 75 octets
+type writer struct {
+	strings.Builder
+}
+
+// line writes a single content line terminated by CRLF
+func (w *writer) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		// Never split a multi-byte UTF-8 sequence
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,124 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var validEvent = Event{
+	Method:   MethodRequest,
+	UID:      "meeting-1@example.com",
+	Sequence: 0,
+	Organizer: Person{
+		Name:  "Jane Organizer",
+		Email: "jane@example.com",
+	},
+	Attendees: []Attendee{
+		{Name: "John Doe", Email: "john@example.com", RSVP: true},
+	},
+	Summary:        "Weekly sync",
+	Description:    "Agenda:\n1. Status; 2. Blockers, 3. AOB",
+	Location:       "Room 1",
+	Start:          time.Date(2026, time.July, 6, 7, 0, 0, 0, time.UTC),
+	End:            time.Date(2026, time.July, 6, 8, 0, 0, 0, time.UTC),
+	TimeZone:       "Europe/Berlin",
+	RecurrenceRule: "freq=weekly;byday=MO",
+	Timestamp:      time.Date(2026, time.July, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestBuild(t *testing.T) {
+	got, err := Build(validEvent)
+	assert.NoError(t, err)
+
+	out := unfold(string(got))
+	assert.Contains(t, out, "METHOD:REQUEST\r\n")
+	assert.Contains(t, out, "UID:meeting-1@example.com\r\n")
+	assert.Contains(t, out, "DTSTAMP:20260701T120000Z\r\n")
+	assert.Contains(t, out, "DTSTART;TZID=Europe/Berlin:20260706T090000\r\n")
+	assert.Contains(t, out, "DTEND;TZID=Europe/Berlin:20260706T100000\r\n")
+	assert.Contains(t, out, "RRULE:FREQ=WEEKLY;BYDAY=MO\r\n")
+	assert.Contains(t, out, `DESCRIPTION:Agenda:\n1. Status\; 2. Blockers\, 3. AOB`)
+	assert.Contains(t, out, "ORGANIZER;CN=Jane Organizer:mailto:jane@example.com\r\n")
+	assert.Contains(t, out, "ATTENDEE;CN=John Doe;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:john@example.com\r\n")
+	assert.Contains(t, out, "STATUS:CONFIRMED\r\n")
+
+	// Central European time switches on the last Sundays of March and October
+	assert.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20260329T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\n")
+	assert.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20261025T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\n")
+}
+
+func TestBuild_UTC(t *testing.T) {
+	event := validEvent
+	event.TimeZone = ""
+	event.RecurrenceRule = ""
+
+	got, err := Build(event)
+	assert.NoError(t, err)
+
+	out := string(got)
+	assert.NotContains(t, out, "VTIMEZONE")
+	assert.NotContains(t, out, "RRULE")
+	assert.Contains(t, out, "DTSTART:20260706T070000Z\r\n")
+}
+
+func TestBuild_Cancel(t *testing.T) {
+	event := validEvent
+	event.Method = MethodCancel
+	event.Sequence = 2
+
+	got, err := Build(event)
+	assert.NoError(t, err)
+
+	out := string(got)
+	assert.Contains(t, out, "METHOD:CANCEL\r\n")
+	assert.Contains(t, out, "SEQUENCE:2\r\n")
+	assert.Contains(t, out, "STATUS:CANCELLED\r\n")
+	assert.Contains(t, out, "ATTENDEE;CN=John Doe;ROLE=REQ-PARTICIPANT:mailto:john@example.com\r\n")
+}
+
+func TestBuild_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(e *Event)
+		wantErr error
+	}{
+		{name: "invalid method", modify: func(e *Event) { e.Method = "PUBLISH" }, wantErr: ErrInvalidMethod},
+		{name: "missing uid", modify: func(e *Event) { e.UID = " " }, wantErr: ErrMissingUID},
+		{name: "missing organizer", modify: func(e *Event) { e.Organizer.Email = "" }, wantErr: ErrMissingOrganizer},
+		{name: "missing attendees", modify: func(e *Event) { e.Attendees = nil }, wantErr: ErrMissingAttendees},
+		{name: "end before start", modify: func(e *Event) { e.End = e.Start }, wantErr: ErrInvalidTimeRange},
+		{name: "unknown time zone", modify: func(e *Event) { e.TimeZone = "Mars/Olympus" }, wantErr: ErrUnknownTimeZone},
+		{name: "rrule without freq", modify: func(e *Event) { e.RecurrenceRule = "COUNT=3" }, wantErr: ErrInvalidRRule},
+		{name: "rrule unknown part", modify: func(e *Event) { e.RecurrenceRule = "FREQ=DAILY;FOO=1" }, wantErr: ErrInvalidRRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := validEvent
+			event.Attendees = append([]Attendee(nil), validEvent.Attendees...)
+			tt.modify(&event)
+
+			_, err := Build(event)
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v, want %v", err, tt.wantErr)
+		})
+	}
+}
+
+func TestWriter_Folding(t *testing.T) {
+	w := &writer{}
+	w.line("DESCRIPTION:" + strings.Repeat("ä", 60))
+
+	for _, line := range strings.Split(strings.TrimSuffix(w.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+	}
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("ä", 60)+"\r\n", unfold(w.String()))
+}
+
+// unfold joins folded content lines back together
+func unfold(s string) string {
+	return strings.ReplaceAll(s, "\r\n ", "")
+}
@@ -0,0 +1,115 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+// transition describes a change of UTC offset within a time zone
+type transition struct {
+	at         time.Time
+	fromOffset int
+	toOffset   int
+	toName     string
+}
+
+// writeTimeZone writes a VTIMEZONE component for loc derived from the
+// offset transitions observed in the given year
+func writeTimeZone(w *writer, loc *time.Location, year int) {
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + loc.String())
+
+	transitions := findTransitions(loc, year)
+	if len(transitions) == 0 {
+		// The zone has a fixed offset, describe it with a single STANDARD block
+		name, offset := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+		w.line("BEGIN:STANDARD")
+		w.line("DTSTART:19700101T000000")
+		w.line("TZOFFSETFROM:" + formatOffset(offset))
+		w.line("TZOFFSETTO:" + formatOffset(offset))
+		w.line("TZNAME:" + name)
+		w.line("END:STANDARD")
+	}
+
+	for _, tr := range transitions {
+		component := "STANDARD"
+		if tr.toOffset > tr.fromOffset {
+			component = "DAYLIGHT"
+		}
+
+		// DTSTART is expressed in the local time that was in effect before the change
+		local := tr.at.In(time.FixedZone("", tr.fromOffset))
+
+		w.line("BEGIN:" + component)
+		w.line("DTSTART:" + local.Format(dateTimeFormat))
+		w.line("TZOFFSETFROM:" + formatOffset(tr.fromOffset))
+		w.line("TZOFFSETTO:" + formatOffset(tr.toOffset))
+		w.line("TZNAME:" + tr.toName)
+		w.line(fmt.Sprintf("RRULE:FREQ=YEARLY;BYMONTH=%d;BYDAY=%s", int(local.Month()), weekdayOrdinal(local)))
+		w.line("END:" + component)
+	}
+
+	w.line("END:VTIMEZONE")
+}
+
+// findTransitions scans a year for UTC offset changes in loc
+func findTransitions(loc *time.Location, year int) []transition {
+	var transitions []transition
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+	_, prevOffset := start.Zone()
+
+	for day := start; day.Before(end); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		_, offset := next.Zone()
+		if offset == prevOffset {
+			continue
+		}
+
+		// Narrow the change down to the exact instant with a binary search
+		lo, hi := day, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, o := mid.Zone(); o == prevOffset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+
+		name, _ := hi.Zone()
+		transitions = append(transitions, transition{
+			at:         hi.Truncate(time.Second),
+			fromOffset: prevOffset,
+			toOffset:   offset,
+			toName:     name,
+		})
+		prevOffset = offset
+	}
+
+	return transitions
+}
+
+// weekdayOrdinal returns a BYDAY value such as 2SU or -1SU for the date
+func weekdayOrdinal(t time.Time) string {
+	days := [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+	day := days[t.Weekday()]
+
+	// Prefer "last <weekday>" when the date falls in the final week of the month
+	lastDay := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if t.Day()+7 > lastDay {
+		return "-1" + day
+	}
+	return fmt.Sprintf("%d%s", (t.Day()-1)/7+1, day)
+}
+
+// formatOffset renders a UTC offset in seconds as +HHMM
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, (offset%3600)/60)
+}
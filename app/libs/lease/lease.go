@@ -0,0 +1,123 @@
+// Package lease runs the loops of background processes that claim work from
+// a shared collection with a lease, such as queued emails or webhook
+// deliveries, so that processes on several GoMail instances can share it.
+package lease
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ProcessFunc claims and processes a single item. It reports whether an
+// item was claimed.
+type ProcessFunc func(ctx context.Context) (bool, error)
+
+// Poller calls a ProcessFunc in loops until it is stopped. A loop sleeps
+// for the poll interval only when nothing was claimed or processing failed,
+// so a backlog is worked off without delay.
+type Poller struct {
+	name     string
+	interval time.Duration
+	process  ProcessFunc
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewPoller creates a poller whose errors are logged with the given name
+func NewPoller(name string, interval time.Duration, process ProcessFunc) *Poller {
+	return &Poller{
+		name:     name,
+		interval: interval,
+		process:  process,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start launches the given number of loops
+func (p *Poller) Start(loops int) {
+	for i := 0; i < loops; i++ {
+		p.wg.Add(1)
+		go p.run()
+	}
+}
+
+// Stop signals the loops to exit and waits for the items they are
+// processing. An item left unfinished when ctx is done keeps its lease and
+// is recovered once the lease expires.
+func (p *Poller) Stop(ctx context.Context) error {
+	close(p.stop)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run processes items until the poller is stopped
+func (p *Poller) run() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		processed, err := p.process(context.Background())
+		if err != nil {
+			log.Printf("%s error: %v", p.name, err)
+		}
+
+		// Sleep only when nothing is due or the database is failing
+		if !processed || err != nil {
+			select {
+			case <-p.stop:
+				return
+			case <-time.After(p.interval):
+			}
+		}
+	}
+}
+
+// Backoff returns the exponential retry delay after the given attempt: base
+// after the first one, doubling with each further attempt up to max
+func Backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
+
+// NewOwnerID returns an identifier unique to this process, which it claims
+// and releases leases with
+func NewOwnerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "gomail"
+	}
+
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
@@ -0,0 +1,79 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(time.Second, 3*time.Second, 1))
+	assert.Equal(t, 2*time.Second, Backoff(time.Second, 3*time.Second, 2))
+	assert.Equal(t, 3*time.Second, Backoff(time.Second, 3*time.Second, 3))
+	assert.Equal(t, 3*time.Second, Backoff(time.Second, 3*time.Second, 10))
+}
+
+func TestNewOwnerID(t *testing.T) {
+	a, b := NewOwnerID(), NewOwnerID()
+
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, strings.Count(a, "-"), strings.Count(b, "-"))
+}
+
+func TestPoller(t *testing.T) {
+	// Claims three items, then fails once and finds nothing afterwards
+	var calls atomic.Int32
+	polled := make(chan struct{}, 1)
+	p := NewPoller("Test poller", 10*time.Millisecond, func(ctx context.Context) (bool, error) {
+		switch n := calls.Add(1); {
+		case n <= 3:
+			return true, nil
+		case n == 4:
+			return false, errors.New("db error")
+		default:
+			select {
+			case polled <- struct{}{}:
+			default:
+			}
+			return false, nil
+		}
+	})
+
+	p.Start(1)
+	select {
+	case <-polled:
+	case <-time.After(time.Second):
+		t.Fatal("poller didn't poll after the error")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, p.Stop(ctx))
+	assert.GreaterOrEqual(t, calls.Load(), int32(5))
+}
+
+func TestPoller_StopTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{}, 1)
+	p := NewPoller("Test poller", time.Millisecond, func(ctx context.Context) (bool, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return true, nil
+	})
+
+	p.Start(2)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Stop(ctx), context.DeadlineExceeded)
+}
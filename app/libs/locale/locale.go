@@ -0,0 +1,180 @@
+// Package locale normalises BCP 47 language tags, resolves fallback chains
+// and formats dates, numbers and currency amounts for a locale.
+package locale
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTag = errors.New("invalid locale")
+)
+
+// tagPattern accepts a language subtag followed by optional script, region
+// or variant subtags
+var tagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// Format describes how a locale writes numbers, dates and currency amounts
+type Format struct {
+	Decimal string
+	Group   string
+	// DateLayout is a Go reference layout for short dates
+	DateLayout string
+	// CurrencyPattern places the amount (#) relative to the symbol (¤)
+	CurrencyPattern string
+}
+
+// formats holds the conventions of the supported locales. Tags not listed
+// here resolve through their fallback chain and finally to English.
+var formats = map[string]Format{
+	"en":    {Decimal: ".", Group: ",", DateLayout: "01/02/2006", CurrencyPattern: "¤#"},
+	"en-GB": {Decimal: ".", Group: ",", DateLayout: "02/01/2006", CurrencyPattern: "¤#"},
+	"de":    {Decimal: ",", Group: ".", DateLayout: "02.01.2006", CurrencyPattern: "#\u00a0¤"},
+	"fr":    {Decimal: ",", Group: "\u202f", DateLayout: "02/01/2006", CurrencyPattern: "#\u00a0¤"},
+	"es":    {Decimal: ",", Group: ".", DateLayout: "2/1/2006", CurrencyPattern: "#\u00a0¤"},
+	"it":    {Decimal: ",", Group: ".", DateLayout: "02/01/2006", CurrencyPattern: "#\u00a0¤"},
+	"pt":    {Decimal: ",", Group: "\u00a0", DateLayout: "02/01/2006", CurrencyPattern: "#\u00a0¤"},
+	"pt-BR": {Decimal: ",", Group: ".", DateLayout: "02/01/2006", CurrencyPattern: "¤\u00a0#"},
+	"nl":    {Decimal: ",", Group: ".", DateLayout: "02-01-2006", CurrencyPattern: "¤\u00a0#"},
+	"pl":    {Decimal: ",", Group: "\u00a0", DateLayout: "02.01.2006", CurrencyPattern: "#\u00a0¤"},
+	"sv":    {Decimal: ",", Group: "\u00a0", DateLayout: "2006-01-02", CurrencyPattern: "#\u00a0¤"},
+	"ru":    {Decimal: ",", Group: "\u00a0", DateLayout: "02.01.2006", CurrencyPattern: "#\u00a0¤"},
+	"tr":    {Decimal: ",", Group: ".", DateLayout: "02.01.2006", CurrencyPattern: "¤#"},
+	"ja":    {Decimal: ".", Group: ",", DateLayout: "2006/01/02", CurrencyPattern: "¤#"},
+	"zh":    {Decimal: ".", Group: ",", DateLayout: "2006/1/2", CurrencyPattern: "¤#"},
+}
+
+// symbols maps ISO 4217 codes to their display symbol
+var symbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "CNY": "¥",
+	"BRL": "R$", "INR": "₹", "RUB": "₽", "TRY": "₺", "PLN": "zł",
+	"SEK": "kr", "CAD": "CA$", "AUD": "A$", "MXN": "MX$", "CHF": "CHF",
+}
+
+// zeroDecimals lists the currencies without minor units
+var zeroDecimals = map[string]bool{"JPY": true, "KRW": true, "CLP": true, "ISK": true}
+
+// Normalize canonicalises the case and separators of a language tag, e.g.
+// "pt_br" becomes "pt-BR" and "zh-hant-tw" becomes "zh-Hant-TW"
+func Normalize(tag string) (string, error) {
+	parts := strings.FieldsFunc(strings.TrimSpace(tag), func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return "", ErrInvalidTag
+	}
+
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch {
+		case len(parts[i]) == 4:
+			// Script subtags are title case
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		case len(parts[i]) == 2 || (len(parts[i]) == 3 && isDigits(parts[i])):
+			// Region subtags are upper case
+			parts[i] = strings.ToUpper(parts[i])
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+
+	normalized := strings.Join(parts, "-")
+	if !tagPattern.MatchString(normalized) {
+		return "", ErrInvalidTag
+	}
+	return normalized, nil
+}
+
+// Fallbacks returns the chain of tags to try for a locale, most specific
+// first: "zh-Hant-TW" yields zh-Hant-TW, zh-Hant and zh. Invalid tags yield
+// an empty chain.
+func Fallbacks(tag string) []string {
+	normalized, err := Normalize(tag)
+	if err != nil {
+		return nil
+	}
+
+	chain := []string{normalized}
+	for i := strings.LastIndex(normalized, "-"); i > 0; i = strings.LastIndex(normalized, "-") {
+		normalized = normalized[:i]
+		chain = append(chain, normalized)
+	}
+	return chain
+}
+
+// For returns the formatting conventions of a locale
+func For(tag string) Format {
+	for _, candidate := range Fallbacks(tag) {
+		if format, ok := formats[candidate]; ok {
+			return format
+		}
+	}
+	return formats["en"]
+}
+
+// Number formats a value with grouping. A negative number of decimals keeps
+// the shortest representation of the value.
+func (f Format) Number(value float64, decimals int) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	digits := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	whole, fraction, _ := strings.Cut(digits, ".")
+
+	var b strings.Builder
+	if value < 0 && strings.Trim(digits, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(f.Group)
+		}
+		b.WriteRune(r)
+	}
+	if fraction != "" {
+		b.WriteString(f.Decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// Currency formats an amount of an ISO 4217 currency
+func (f Format) Currency(amount float64, code string) string {
+	code = strings.ToUpper(code)
+
+	decimals := 2
+	if zeroDecimals[code] {
+		decimals = 0
+	}
+
+	symbol, ok := symbols[code]
+	if !ok {
+		symbol = code
+	}
+
+	number := f.Number(math.Abs(amount), decimals)
+	formatted := strings.Replace(strings.Replace(f.CurrencyPattern, "#", number, 1), "¤", symbol, 1)
+	if amount < 0 && strings.Trim(number, "0.,") != "" {
+		formatted = "-" + formatted
+	}
+	return formatted
+}
+
+// Date formats the calendar date of a time
+func (f Format) Date(t time.Time) string {
+	return t.Format(f.DateLayout)
+}
+
+// isDigits reports whether s only contains ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
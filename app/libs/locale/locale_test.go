@@ -0,0 +1,89 @@
+package locale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{tag: "pt_br", want: "pt-BR"},
+		{tag: "EN", want: "en"},
+		{tag: "zh-hant-tw", want: "zh-Hant-TW"},
+		{tag: "es-419", want: "es-419"},
+		{tag: "", wantErr: true},
+		{tag: "english", wantErr: true},
+		{tag: "e", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := Normalize(tt.tag)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTag)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	assert.Equal(t, []string{"pt-BR", "pt"}, Fallbacks("pt-br"))
+	assert.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh"}, Fallbacks("zh_Hant_TW"))
+	assert.Equal(t, []string{"de"}, Fallbacks("de"))
+	assert.Nil(t, Fallbacks("not a tag"))
+}
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2026, time.March, 7, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		tag      string
+		code     string
+		number   string
+		currency string
+		date     string
+	}{
+		{tag: "en", code: "USD", number: "1,234,567.5", currency: "$1,234,567.50", date: "03/07/2026"},
+		{tag: "de-AT", code: "EUR", number: "1.234.567,5", currency: "1.234.567,50\u00a0€", date: "07.03.2026"},
+		{tag: "fr", code: "EUR", number: "1\u202f234\u202f567,5", currency: "1\u202f234\u202f567,50\u00a0€", date: "07/03/2026"},
+		{tag: "pt-BR", code: "BRL", number: "1.234.567,5", currency: "R$\u00a01.234.567,50", date: "07/03/2026"},
+		{tag: "ja", code: "JPY", number: "1,234,567.5", currency: "¥1,234,568", date: "2026/03/07"},
+		{tag: "xx", code: "usd", number: "1,234,567.5", currency: "$1,234,567.50", date: "03/07/2026"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			f := For(tt.tag)
+			assert.Equal(t, tt.number, f.Number(1234567.5, -1))
+			assert.Equal(t, tt.currency, f.Currency(1234567.5, tt.code))
+			assert.Equal(t, tt.date, f.Date(date))
+		})
+	}
+}
+
+func TestFormat_Number(t *testing.T) {
+	f := For("en")
+
+	assert.Equal(t, "0", f.Number(0, -1))
+	assert.Equal(t, "999", f.Number(999, -1))
+	assert.Equal(t, "1,000", f.Number(1000, 0))
+	assert.Equal(t, "-12,345.68", f.Number(-12345.678, 2))
+	assert.Equal(t, "0.00", f.Number(-0.001, 2))
+}
+
+func TestFormat_Currency(t *testing.T) {
+	f := For("en")
+
+	assert.Equal(t, "¥1,235", f.Currency(1234.6, "JPY"))
+	assert.Equal(t, "-€12.50", f.Currency(-12.5, "EUR"))
+	assert.Equal(t, "NOK99.00", f.Currency(99, "NOK"))
+}
@@ -0,0 +1,279 @@
+// Package mailparse parses MIME email messages (RFC 2045-2049) into their
+// headers, text and HTML bodies and attachments.
+//
+// Encoded words in headers and the transfer encodings and charsets of
+// parts are decoded, so all text is returned as UTF-8.
+package mailparse
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	ErrInvalidMessage = errors.New("invalid message")
+)
+
+// maxDepth is how deeply multipart entities are followed
+const maxDepth = 10
+
+// Address is a mailbox of an address header
+type Address struct {
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address"`
+}
+
+// Attachment is a part of a message that isn't one of its bodies
+type Attachment struct {
+	Filename    string
+	ContentType string
+
+	// ContentID references inline parts from the HTML body as cid:<ContentID>
+	ContentID string
+	Inline    bool
+
+	Content []byte
+}
+
+// Message is a parsed message
+type Message struct {
+	// Header holds all header fields with encoded words decoded
+	Header map[string][]string
+
+	Subject    string
+	From       []Address
+	To         []Address
+	Cc         []Address
+	ReplyTo    []Address
+	MessageID  string
+	InReplyTo  string
+	References []string
+
+	// Date is zero when the message has no valid Date header
+	Date time.Time
+
+	// Text and HTML are the bodies. Several bodies of the same type, such
+	// as of a message with attachments between its parts, are joined.
+	Text string
+	HTML string
+
+	Attachments []Attachment
+}
+
+// Parse parses a whole message
+func Parse(r io.Reader) (*Message, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	header := textproto.MIMEHeader(msg.Header)
+	parsed := &Message{
+		Header:     decodeHeader(header),
+		MessageID:  unbracket(header.Get("Message-Id")),
+		InReplyTo:  unbracket(header.Get("In-Reply-To")),
+		References: messageIDs(header.Get("References")),
+	}
+	parsed.Subject = first(parsed.Header["Subject"])
+	parsed.From = addresses(msg.Header, "From")
+	parsed.To = addresses(msg.Header, "To")
+	parsed.Cc = addresses(msg.Header, "Cc")
+	parsed.ReplyTo = addresses(msg.Header, "Reply-To")
+	if date, err := msg.Header.Date(); err == nil {
+		parsed.Date = date
+	}
+
+	// A message without a content type is plain text (RFC 2045)
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "text/plain")
+	}
+	if err := parsed.addEntity(header, msg.Body, 0); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// addEntity adds the bodies and attachments of a MIME entity
+func (m *Message) addEntity(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// Unparsable types are treated as binary data (RFC 2045)
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+
+	if strings.HasPrefix(mediaType, "multipart/") && depth < maxDepth {
+		parts := multipart.NewReader(decodeTransfer(header, body), params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+			}
+			if err := m.addEntity(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransfer(header, body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if disposition != "attachment" && filename == "" {
+		switch mediaType {
+		case "text/plain":
+			m.Text = join(m.Text, decodeText(content, params["charset"]))
+			return nil
+		case "text/html":
+			m.HTML = join(m.HTML, decodeText(content, params["charset"]))
+			return nil
+		}
+	}
+
+	if filename == "" && (mediaType == "message/rfc822" || mediaType == "message/global") {
+		filename = "message.eml"
+	}
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    decodeWords(filename),
+		ContentType: mediaType,
+		ContentID:   unbracket(header.Get("Content-Id")),
+		Inline:      disposition == "inline",
+		Content:     content,
+	})
+	return nil
+}
+
+// decodeTransfer undoes the transfer encoding of an entity. The multipart
+// reader already decodes quoted-printable parts and drops the header.
+func decodeTransfer(header textproto.MIMEHeader, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &base64Cleaner{r: body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// decodeText converts a body to UTF-8 with \n line endings. Unknown
+// charsets are kept as is.
+func decodeText(content []byte, label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label != "" && label != "utf-8" && label != "us-ascii" {
+		if reader, err := charset.NewReaderLabel(label, bytes.NewReader(content)); err == nil {
+			if decoded, err := io.ReadAll(reader); err == nil {
+				content = decoded
+			}
+		}
+	}
+	return strings.ReplaceAll(string(content), "\r\n", "\n")
+}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+
+// decodeWords decodes the encoded words of a header value (RFC 2047)
+func decodeWords(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// decodeHeader decodes the encoded words of all header fields
+func decodeHeader(header textproto.MIMEHeader) map[string][]string {
+	decoded := make(map[string][]string, len(header))
+	for key, values := range header {
+		for _, value := range values {
+			decoded[key] = append(decoded[key], decodeWords(value))
+		}
+	}
+	return decoded
+}
+
+// addresses parses an address header, skipping it when it is invalid
+func addresses(header mail.Header, key string) []Address {
+	list, err := (&mail.AddressParser{WordDecoder: wordDecoder}).ParseList(header.Get(key))
+	if err != nil {
+		return nil
+	}
+	result := make([]Address, 0, len(list))
+	for _, addr := range list {
+		result = append(result, Address{Name: addr.Name, Address: addr.Address})
+	}
+	return result
+}
+
+// messageIDs returns the message IDs of a References header
+func messageIDs(value string) []string {
+	var ids []string
+	for _, field := range strings.Fields(value) {
+		if id := unbracket(field); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// unbracket removes the angle brackets around a message or content ID
+func unbracket(value string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "<"), ">")
+}
+
+// join appends a body to the ones found before it
+func join(body, part string) string {
+	if body == "" {
+		return part
+	}
+	return body + "\n" + part
+}
+
+func first(values []string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// base64Cleaner drops the characters the base64 decoder doesn't skip,
+// like spaces and tabs some senders wrap lines with
+type base64Cleaner struct {
+	r io.Reader
+}
+
+func (c *base64Cleaner) Read(p []byte) (int, error) {
+	for {
+		n, err := c.r.Read(p)
+		kept := 0
+		for _, b := range p[:n] {
+			if b != ' ' && b != '\t' {
+				p[kept] = b
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
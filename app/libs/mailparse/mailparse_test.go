@@ -0,0 +1,142 @@
+package mailparse
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crlf converts a message written with \n line endings
+func crlf(message string) string {
+	return strings.ReplaceAll(message, "\n", "\r\n")
+}
+
+const reply = `From: =?UTF-8?Q?Ad=C3=A0_Lovelace?= <ada@example.org>
+To: "Support" <support+1234@reply.example.com>, sales@example.com
+Cc: bob@example.org
+Subject: =?ISO-8859-1?Q?Re:_Caf=E9?=
+Date: Mon, 12 Oct 2026 10:00:00 +0200
+Message-ID: <reply-1@example.org>
+In-Reply-To: <0123abcd@example.com>
+References: <first@example.com>
+ <0123abcd@example.com>
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="MIXED"
+
+--MIXED
+Content-Type: multipart/alternative; boundary="ALT"
+
+--ALT
+Content-Type: text/plain; charset=ISO-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+Merci pour le caf=E9 !
+
+--ALT
+Content-Type: multipart/related; boundary="REL"
+
+--REL
+Content-Type: text/html; charset=UTF-8
+
+<p>Merci pour le caf=C3=A9 !</p><img src="cid:logo@example.org">
+--REL
+Content-Type: image/png
+Content-ID: <logo@example.org>
+Content-Disposition: inline
+Content-Transfer-Encoding: base64
+
+iVBO Rw0K
+--REL--
+
+--ALT--
+
+--MIXED
+Content-Type: application/pdf; name="=?UTF-8?Q?re=C3=A7u.pdf?="
+Content-Disposition: attachment
+Content-Transfer-Encoding: base64
+
+JVBERi0x
+LjQK
+
+--MIXED--
+`
+
+func TestParse(t *testing.T) {
+	msg, err := Parse(strings.NewReader(crlf(reply)))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Re: Café", msg.Subject)
+	assert.Equal(t, []Address{{Name: "Adà Lovelace", Address: "ada@example.org"}}, msg.From)
+	assert.Equal(t, []Address{{Name: "Support", Address: "support+1234@reply.example.com"}, {Address: "sales@example.com"}}, msg.To)
+	assert.Equal(t, []Address{{Address: "bob@example.org"}}, msg.Cc)
+	assert.Equal(t, "reply-1@example.org", msg.MessageID)
+	assert.Equal(t, "0123abcd@example.com", msg.InReplyTo)
+	assert.Equal(t, []string{"first@example.com", "0123abcd@example.com"}, msg.References)
+	assert.True(t, msg.Date.Equal(time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"Re: Café"}, msg.Header["Subject"])
+
+	assert.Equal(t, "Merci pour le café !\n", msg.Text)
+	assert.Contains(t, msg.HTML, "<p>Merci pour le caf=C3=A9 !</p>", "HTML without a transfer encoding is kept as is")
+
+	require.Len(t, msg.Attachments, 2)
+	assert.Equal(t, Attachment{
+		ContentType: "image/png",
+		ContentID:   "logo@example.org",
+		Inline:      true,
+		Content:     []byte("\x89PNG\r\n"),
+	}, msg.Attachments[0])
+	assert.Equal(t, "reçu.pdf", msg.Attachments[1].Filename)
+	assert.Equal(t, "application/pdf", msg.Attachments[1].ContentType)
+	assert.Equal(t, []byte("%PDF-1.4\n"), msg.Attachments[1].Content)
+}
+
+func TestParse_SinglePart(t *testing.T) {
+	msg, err := Parse(strings.NewReader(crlf(`From: ada@example.org
+To: support@example.com
+Subject: Hello
+Content-Transfer-Encoding: quoted-printable
+
+A long line that was wrapped=
+ by the sender.
+`)))
+
+	require.NoError(t, err)
+	assert.Equal(t, "A long line that was wrapped by the sender.\n", msg.Text)
+	assert.Empty(t, msg.HTML)
+	assert.Empty(t, msg.Attachments)
+	assert.True(t, msg.Date.IsZero())
+}
+
+func TestParse_ForwardedMessage(t *testing.T) {
+	msg, err := Parse(strings.NewReader(crlf(`From: ada@example.org
+Content-Type: multipart/mixed; boundary="B"
+
+--B
+Content-Type: text/plain
+
+See below.
+--B
+Content-Type: message/rfc822
+
+From: eve@example.net
+Subject: Original
+
+Hi
+--B--
+`)))
+
+	require.NoError(t, err)
+	assert.Equal(t, "See below.", msg.Text)
+	require.Len(t, msg.Attachments, 1)
+	assert.Equal(t, "message.eml", msg.Attachments[0].Filename)
+	assert.Equal(t, "message/rfc822", msg.Attachments[0].ContentType)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("not a message"))
+
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
@@ -0,0 +1,459 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// inlineKind identifies the type of an inline element
+type inlineKind int
+
+const (
+	inlineText inlineKind = iota
+	inlineCode
+	inlineEmphasis
+	inlineStrong
+	inlineStrike
+	inlineLink
+	inlineImage
+	inlineHardBreak
+	inlineSoftBreak
+)
+
+// inline is a node of the inline content of a paragraph or heading
+type inline struct {
+	kind     inlineKind
+	text     string // literal text, code or image alt text
+	url      string
+	title    string
+	children []inline
+}
+
+var (
+	// entityPattern matches named and numeric character references
+	entityPattern = regexp.MustCompile(`^&(#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+	// autolinkPattern matches <scheme:...> autolinks
+	autolinkPattern = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+	// emailPattern matches <user@example.com> autolinks
+	emailPattern = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*)>`)
+)
+
+// parseInline parses the inline content of a paragraph or heading
+func parseInline(s string) []inline {
+	var nodes []inline
+	var text strings.Builder
+	emphasis := &emphasisParser{s: s, results: make(map[int]*emphasisResult)}
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, inline{kind: inlineText, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			flush()
+			nodes = append(nodes, inline{kind: inlineHardBreak})
+			i += 2
+
+		case c == '\\' && i+1 < len(s) && isPunctuation(s[i+1]):
+			text.WriteByte(s[i+1])
+			i += 2
+
+		case c == '\n':
+			// Two or more trailing spaces make a hard break
+			current := text.String()
+			trimmed := strings.TrimRight(current, " ")
+			hard := len(current)-len(trimmed) >= 2
+			text.Reset()
+			text.WriteString(trimmed)
+			flush()
+			if hard {
+				nodes = append(nodes, inline{kind: inlineHardBreak})
+			} else {
+				nodes = append(nodes, inline{kind: inlineSoftBreak})
+			}
+			i++
+			for i < len(s) && s[i] == ' ' {
+				i++
+			}
+
+		case c == '`':
+			if code, next, ok := parseCodeSpan(s, i); ok {
+				flush()
+				nodes = append(nodes, inline{kind: inlineCode, text: code})
+				i = next
+				break
+			}
+			n := runLength(s, i, '`')
+			text.WriteString(s[i : i+n])
+			i += n
+
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if node, next, ok := parseLink(s, i+1); ok {
+				flush()
+				node.kind = inlineImage
+				node.text = plainText(node.children)
+				node.children = nil
+				nodes = append(nodes, node)
+				i = next
+				break
+			}
+			text.WriteByte(c)
+			i++
+
+		case c == '[':
+			if node, next, ok := parseLink(s, i); ok {
+				flush()
+				nodes = append(nodes, node)
+				i = next
+				break
+			}
+			text.WriteByte(c)
+			i++
+
+		case c == '<':
+			if match := autolinkPattern.FindStringSubmatch(s[i:]); match != nil {
+				flush()
+				nodes = append(nodes, inline{kind: inlineLink, url: match[1], children: []inline{{kind: inlineText, text: match[1]}}})
+				i += len(match[0])
+				break
+			}
+			if match := emailPattern.FindStringSubmatch(s[i:]); match != nil {
+				flush()
+				nodes = append(nodes, inline{kind: inlineLink, url: "mailto:" + match[1], children: []inline{{kind: inlineText, text: match[1]}}})
+				i += len(match[0])
+				break
+			}
+			text.WriteByte(c)
+			i++
+
+		case c == '&':
+			if match := entityPattern.FindString(s[i:]); match != "" {
+				text.WriteString(html.UnescapeString(match))
+				i += len(match)
+				break
+			}
+			text.WriteByte(c)
+			i++
+
+		case c == '*' || c == '_' || c == '~':
+			if literal, node, next, ok := emphasis.parse(i); ok {
+				// Delimiters left over from a longer run stay text
+				text.WriteString(literal)
+				flush()
+				nodes = append(nodes, node)
+				i = next
+				break
+			}
+			n := runLength(s, i, c)
+			text.WriteString(s[i : i+n])
+			i += n
+
+		default:
+			text.WriteByte(c)
+			i++
+		}
+	}
+
+	flush()
+	return nodes
+}
+
+// parseCodeSpan parses a code span opened by the backtick run at i
+func parseCodeSpan(s string, i int) (string, int, bool) {
+	n := runLength(s, i, '`')
+	for j := i + n; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		m := runLength(s, j, '`')
+		if m == n {
+			code := strings.ReplaceAll(s[i+n:j], "\n", " ")
+			// Strip one space on both sides so code can start with a backtick
+			if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+			return code, j + m, true
+		}
+		j += m
+	}
+	return "", 0, false
+}
+
+// emphasisParser parses the emphasis of one string. It remembers the
+// outcome per opener so nested attempts stay linear instead of retrying
+// the same unmatched delimiters over and over.
+type emphasisParser struct {
+	s       string
+	results map[int]*emphasisResult
+}
+
+// emphasisResult is the outcome of parsing the emphasis at an opener
+type emphasisResult struct {
+	literal string
+	node    inline
+	next    int
+	ok      bool
+}
+
+// parse parses emphasis, strong emphasis or strikethrough opened by the
+// delimiter run at i. It returns the unused part of the delimiter run, the
+// emphasis node and the index after its closer.
+func (p *emphasisParser) parse(i int) (string, inline, int, bool) {
+	result, ok := p.results[i]
+	if !ok {
+		result = p.match(i)
+		p.results[i] = result
+	}
+	return result.literal, result.node, result.next, result.ok
+}
+
+// match tries the possible delimiter widths of the opener at i, longest first
+func (p *emphasisParser) match(i int) *emphasisResult {
+	s := p.s
+	c := s[i]
+	n := runLength(s, i, c)
+
+	// Openers must be followed by text, and _ must not be inside a word
+	if i+n >= len(s) || isSpace(s[i+n]) {
+		return &emphasisResult{}
+	}
+	if c == '_' && i > 0 && isAlphanumeric(s[i-1]) {
+		return &emphasisResult{}
+	}
+
+	type attempt struct {
+		width int
+		kinds []inlineKind
+	}
+	var attempts []attempt
+	switch {
+	case c == '~':
+		if n != 2 {
+			return &emphasisResult{}
+		}
+		attempts = []attempt{{2, []inlineKind{inlineStrike}}}
+	case n >= 3:
+		attempts = []attempt{{3, []inlineKind{inlineEmphasis, inlineStrong}}, {2, []inlineKind{inlineStrong}}, {1, []inlineKind{inlineEmphasis}}}
+	case n == 2:
+		attempts = []attempt{{2, []inlineKind{inlineStrong}}, {1, []inlineKind{inlineEmphasis}}}
+	default:
+		attempts = []attempt{{1, []inlineKind{inlineEmphasis}}}
+	}
+
+	for _, a := range attempts {
+		start := i + n - a.width
+		closer := p.findCloser(start+a.width, c, a.width)
+		if closer < 0 {
+			continue
+		}
+
+		node := inline{kind: a.kinds[len(a.kinds)-1], children: parseInline(s[start+a.width : closer])}
+		for k := len(a.kinds) - 2; k >= 0; k-- {
+			node = inline{kind: a.kinds[k], children: []inline{node}}
+		}
+		return &emphasisResult{literal: s[i:start], node: node, next: closer + a.width, ok: true}
+	}
+
+	return &emphasisResult{}
+}
+
+// findCloser finds a run of at least width delimiters c that closes an
+// emphasis whose content starts at from. It skips escapes, code spans and
+// nested emphasis, and requires the closer to follow text.
+func (p *emphasisParser) findCloser(from int, c byte, width int) int {
+	s := p.s
+	for j := from; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			if _, next, ok := parseCodeSpan(s, j); ok {
+				j = next - 1
+			} else {
+				j += runLength(s, j, '`') - 1
+			}
+			continue
+		}
+		if s[j] != '*' && s[j] != '_' && s[j] != '~' {
+			continue
+		}
+
+		m := runLength(s, j, s[j])
+		if s[j] == c && j > from && !isSpace(s[j-1]) && m >= width {
+			// _ must not close inside a word
+			if c != '_' || j+m >= len(s) || !isAlphanumeric(s[j+m]) {
+				return j
+			}
+		}
+
+		// Skip emphasis nested inside the content
+		if _, _, next, ok := p.parse(j); ok {
+			j = next - 1
+			continue
+		}
+		j += m - 1
+	}
+	return -1
+}
+
+// parseLink parses [text](destination "title") starting at the opening bracket
+func parseLink(s string, open int) (inline, int, bool) {
+	closeBracket := matchingBracket(s, open)
+	if closeBracket < 0 || closeBracket+1 >= len(s) || s[closeBracket+1] != '(' {
+		return inline{}, 0, false
+	}
+
+	i := closeBracket + 2
+	i = skipSpaces(s, i)
+
+	// Destination, either <...> or without spaces and with balanced parentheses
+	var destination string
+	if i < len(s) && s[i] == '<' {
+		end := strings.IndexAny(s[i+1:], ">\n")
+		if end < 0 || s[i+1+end] != '>' {
+			return inline{}, 0, false
+		}
+		destination = s[i+1 : i+1+end]
+		i += end + 2
+	} else {
+		start, depth := i, 0
+		for ; i < len(s) && !isSpace(s[i]); i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				continue
+			}
+			if s[i] == '(' {
+				depth++
+			} else if s[i] == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+		}
+		destination = s[start:i]
+	}
+
+	// Optional title
+	var title string
+	hasSpace := i < len(s) && isSpace(s[i])
+	i = skipSpaces(s, i)
+	if hasSpace && i < len(s) && (s[i] == '"' || s[i] == '\'' || s[i] == '(') {
+		closing := s[i]
+		if closing == '(' {
+			closing = ')'
+		}
+		end := i + 1
+		for end < len(s) && s[end] != closing {
+			if s[end] == '\\' && end+1 < len(s) {
+				end++
+			}
+			end++
+		}
+		if end >= len(s) {
+			return inline{}, 0, false
+		}
+		title = unescape(s[i+1 : end])
+		i = end + 1
+		i = skipSpaces(s, i)
+	}
+
+	if i >= len(s) || s[i] != ')' {
+		return inline{}, 0, false
+	}
+
+	return inline{
+		kind:     inlineLink,
+		url:      unescape(destination),
+		title:    title,
+		children: parseInline(s[open+1 : closeBracket]),
+	}, i + 1, true
+}
+
+// matchingBracket finds the ] closing the [ at open
+func matchingBracket(s string, open int) int {
+	depth := 0
+	for j := open; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			if _, next, ok := parseCodeSpan(s, j); ok {
+				j = next - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// plainText returns the text content of inline nodes
+func plainText(nodes []inline) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.kind {
+		case inlineText, inlineCode, inlineImage:
+			b.WriteString(node.text)
+		case inlineHardBreak, inlineSoftBreak:
+			b.WriteByte(' ')
+		default:
+			b.WriteString(plainText(node.children))
+		}
+	}
+	return b.String()
+}
+
+// unescape resolves backslash escapes and character references
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunctuation(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return html.UnescapeString(b.String())
+}
+
+// runLength counts the consecutive occurrences of c starting at i
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// skipSpaces returns the index of the next non-space character
+func skipSpaces(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n'
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isPunctuation(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
@@ -0,0 +1,439 @@
+// Package markdown renders the commonly used subset of CommonMark to
+// sanitised HTML and to readable plain text for email bodies.
+//
+// Supported are ATX headings, paragraphs, hard and soft line breaks, block
+// quotes, bullet and ordered lists, fenced and indented code blocks,
+// thematic breaks, emphasis, strong emphasis, strikethrough, code spans,
+// links, images and autolinks. Raw HTML is never passed through: it is
+// escaped like any other text, and links or images with a scheme outside
+// an allowlist are rendered without their URL. Rendering only depends on
+// the input, so the same source always produces the same output.
+package markdown
+
+import (
+	"strings"
+)
+
+// blockKind identifies the type of a block element
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockQuote
+	blockList
+	blockRule
+)
+
+// block is a node of the block structure of a document
+type block struct {
+	kind     blockKind
+	level    int        // heading level
+	text     string     // inline source of paragraphs and headings, content of code blocks
+	info     string     // language of fenced code blocks
+	ordered  bool       // ordered list
+	start    int        // number of the first item of an ordered list
+	loose    bool       // list items are separated by blank lines
+	items    [][]*block // list items
+	children []*block   // block quote content
+}
+
+// Document is a parsed Markdown document
+type Document struct {
+	blocks []*block
+}
+
+// Parse parses Markdown source into a document
+func Parse(source string) *Document {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+
+	return &Document{blocks: parseBlocks(strings.Split(source, "\n"))}
+}
+
+// ToHTML renders Markdown source to sanitised HTML
+func ToHTML(source string) string {
+	return Parse(source).HTML()
+}
+
+// ToText renders Markdown source to plain text
+func ToText(source string) string {
+	return Parse(source).Text()
+}
+
+// parseBlocks splits lines into block elements
+func parseBlocks(lines []string) []*block {
+	var blocks []*block
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if isBlank(line) {
+			i++
+			continue
+		}
+
+		indent := leadingSpaces(line)
+		trimmed := line[indent:]
+
+		if indent >= 4 {
+			var b *block
+			b, i = parseIndentedCode(lines, i)
+			blocks = append(blocks, b)
+			continue
+		}
+
+		if fence := fenceOf(trimmed); fence != "" {
+			var b *block
+			b, i = parseFencedCode(lines, i, indent, fence)
+			blocks = append(blocks, b)
+			continue
+		}
+
+		if level, text, ok := heading(trimmed); ok {
+			blocks = append(blocks, &block{kind: blockHeading, level: level, text: text})
+			i++
+			continue
+		}
+
+		if isRule(trimmed) {
+			blocks = append(blocks, &block{kind: blockRule})
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, ">") {
+			var b *block
+			b, i = parseQuote(lines, i)
+			blocks = append(blocks, b)
+			continue
+		}
+
+		if _, ok := listMarker(line); ok {
+			var b *block
+			b, i = parseList(lines, i)
+			blocks = append(blocks, b)
+			continue
+		}
+
+		var b *block
+		b, i = parseParagraph(lines, i)
+		blocks = append(blocks, b)
+	}
+
+	return blocks
+}
+
+// parseIndentedCode consumes a code block indented by four spaces
+func parseIndentedCode(lines []string, i int) (*block, int) {
+	var code []string
+	for i < len(lines) && (isBlank(lines[i]) || leadingSpaces(lines[i]) >= 4) {
+		if isBlank(lines[i]) {
+			code = append(code, "")
+		} else {
+			code = append(code, lines[i][4:])
+		}
+		i++
+	}
+
+	// Trailing blank lines separate the block from what follows
+	for len(code) > 0 && code[len(code)-1] == "" {
+		code = code[:len(code)-1]
+	}
+
+	return &block{kind: blockCode, text: strings.Join(code, "\n")}, i
+}
+
+// parseFencedCode consumes a code block between ``` or ~~~ fences. An
+// unclosed fence runs to the end of the document.
+func parseFencedCode(lines []string, i, indent int, fence string) (*block, int) {
+	info := strings.TrimSpace(lines[i][indent+len(fence):])
+	if fields := strings.Fields(info); len(fields) > 0 {
+		info = fields[0]
+	}
+	i++
+
+	var code []string
+	for i < len(lines) {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]+" ") == "" {
+			i++
+			break
+		}
+		code = append(code, removeIndent(lines[i], indent))
+		i++
+	}
+
+	return &block{kind: blockCode, text: strings.Join(code, "\n"), info: info}, i
+}
+
+// parseQuote consumes a block quote and parses its content recursively
+func parseQuote(lines []string, i int) (*block, int) {
+	var quoted []string
+	for i < len(lines) && !isBlank(lines[i]) {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = strings.TrimPrefix(trimmed[1:], " ")
+		quoted = append(quoted, trimmed)
+		i++
+	}
+
+	return &block{kind: blockQuote, children: parseBlocks(quoted)}, i
+}
+
+// parseList consumes consecutive items of the same list type and parses
+// each item recursively
+func parseList(lines []string, i int) (*block, int) {
+	first, _ := listMarker(lines[i])
+	list := &block{kind: blockList, ordered: first.ordered, start: first.number}
+
+	var items [][]string
+	for i < len(lines) {
+		marker, ok := listMarker(lines[i])
+		if !ok || marker.ordered != first.ordered || marker.delimiter != first.delimiter {
+			break
+		}
+
+		item := []string{lines[i][marker.offset:]}
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+			if isBlank(line) {
+				// The item continues when the next content is indented under it
+				next := skipBlank(lines, i)
+				if next < len(lines) && leadingSpaces(lines[next]) >= marker.offset {
+					for ; i < next; i++ {
+						item = append(item, "")
+					}
+					list.loose = true
+					continue
+				}
+				break
+			}
+
+			if leadingSpaces(line) >= marker.offset {
+				item = append(item, line[marker.offset:])
+				i++
+				continue
+			}
+
+			// Lazy continuation of the item's paragraph
+			if _, isMarker := listMarker(line); isMarker || interruptsParagraph(line) {
+				break
+			}
+			item = append(item, strings.TrimLeft(line, " "))
+			i++
+		}
+		items = append(items, item)
+
+		// Blank lines between items make the list loose
+		if i < len(lines) && isBlank(lines[i]) {
+			next := skipBlank(lines, i)
+			if next >= len(lines) {
+				i = next
+				break
+			}
+			marker, ok := listMarker(lines[next])
+			if !ok || marker.ordered != first.ordered || marker.delimiter != first.delimiter {
+				break
+			}
+			list.loose = true
+			i = next
+		}
+	}
+
+	for _, item := range items {
+		list.items = append(list.items, parseBlocks(item))
+	}
+
+	return list, i
+}
+
+// parseParagraph consumes lines until a blank line or the start of another block
+func parseParagraph(lines []string, i int) (*block, int) {
+	text := []string{strings.TrimLeft(lines[i], " ")}
+	i++
+
+	for i < len(lines) && !isBlank(lines[i]) && !interruptsParagraph(lines[i]) {
+		text = append(text, strings.TrimLeft(lines[i], " "))
+		i++
+	}
+
+	return &block{kind: blockParagraph, text: strings.TrimRight(strings.Join(text, "\n"), " ")}, i
+}
+
+// interruptsParagraph reports whether a line starts a block that may
+// interrupt a paragraph
+func interruptsParagraph(line string) bool {
+	indent := leadingSpaces(line)
+	if indent >= 4 {
+		return false
+	}
+
+	trimmed := line[indent:]
+	if _, _, ok := heading(trimmed); ok {
+		return true
+	}
+	if fenceOf(trimmed) != "" || isRule(trimmed) || strings.HasPrefix(trimmed, ">") {
+		return true
+	}
+
+	// Only bullets and lists starting at 1 interrupt, so "2024. was good"
+	// inside a paragraph stays text
+	marker, ok := listMarker(line)
+	return ok && (!marker.ordered || marker.number == 1) && strings.TrimSpace(line[marker.offset:]) != ""
+}
+
+// heading parses an ATX heading
+func heading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ') {
+		return 0, "", false
+	}
+
+	text := strings.TrimSpace(line[level:])
+
+	// Drop an optional closing sequence of #
+	if trimmed := strings.TrimRight(text, "#"); trimmed != text && (trimmed == "" || strings.HasSuffix(trimmed, " ")) {
+		text = strings.TrimSpace(trimmed)
+	}
+
+	return level, text, true
+}
+
+// isRule reports whether a line is a thematic break: three or more *, -
+// or _ optionally separated by spaces
+func isRule(line string) bool {
+	compact := strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	if len(compact) < 3 {
+		return false
+	}
+
+	c := compact[0]
+	if c != '*' && c != '-' && c != '_' {
+		return false
+	}
+	return strings.Count(compact, string(c)) == len(compact)
+}
+
+// fenceOf returns the opening code fence of a line, if any
+func fenceOf(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := 0
+		for n < len(line) && line[n] == c[0] {
+			n++
+		}
+		if n >= 3 {
+			// Backtick fences can't have backticks in their info string
+			if c == "`" && strings.Contains(line[n:], "`") {
+				return ""
+			}
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+// marker describes the marker of a list item
+type marker struct {
+	ordered   bool
+	number    int
+	delimiter byte // bullet character or the . or ) after the number
+	offset    int  // column where the item content starts
+}
+
+// listMarker parses a bullet (-, * or +) or ordered (1. or 1)) list marker
+func listMarker(line string) (marker, bool) {
+	indent := leadingSpaces(line)
+	if indent >= 4 || indent >= len(line) {
+		return marker{}, false
+	}
+
+	m := marker{}
+	pos := indent
+	switch c := line[pos]; {
+	case c == '-' || c == '*' || c == '+':
+		if isRule(line) {
+			return marker{}, false
+		}
+		m.delimiter = c
+		pos++
+	case c >= '0' && c <= '9':
+		digits := pos
+		for pos < len(line) && pos-digits < 9 && line[pos] >= '0' && line[pos] <= '9' {
+			m.number = m.number*10 + int(line[pos]-'0')
+			pos++
+		}
+		if pos >= len(line) || (line[pos] != '.' && line[pos] != ')') {
+			return marker{}, false
+		}
+		m.ordered = true
+		m.delimiter = line[pos]
+		pos++
+	default:
+		return marker{}, false
+	}
+
+	// The marker must be followed by a space or end the line
+	if pos < len(line) && line[pos] != ' ' {
+		return marker{}, false
+	}
+
+	spaces := 0
+	for pos+spaces < len(line) && line[pos+spaces] == ' ' {
+		spaces++
+	}
+	switch {
+	case pos+spaces >= len(line):
+		// Empty item
+		m.offset = len(line)
+	case spaces > 4:
+		// The content is an indented code block
+		m.offset = pos + 1
+	default:
+		m.offset = pos + spaces
+	}
+	if m.offset > len(line) {
+		m.offset = len(line)
+	}
+
+	return m, true
+}
+
+// isBlank reports whether a line only contains whitespace
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// skipBlank returns the index of the next non-blank line
+func skipBlank(lines []string, i int) int {
+	for i < len(lines) && isBlank(lines[i]) {
+		i++
+	}
+	return i
+}
+
+// leadingSpaces counts the spaces at the start of a line
+func leadingSpaces(line string) int {
+	n := 0
+	for n < len(line) && line[n] == ' ' {
+		n++
+	}
+	return n
+}
+
+// removeIndent removes up to n leading spaces
+func removeIndent(line string, n int) string {
+	spaces := leadingSpaces(line)
+	if spaces > n {
+		spaces = n
+	}
+	return line[spaces:]
+}
@@ -0,0 +1,154 @@
+package markdown
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestGolden renders every testdata/*.md file and compares the output with
+// the .html and .txt golden files next to it. Run with -update to rewrite
+// the golden files after an intended change.
+func TestGolden(t *testing.T) {
+	sources, err := filepath.Glob(filepath.Join("testdata", "*.md"))
+	require.NoError(t, err)
+	require.NotEmpty(t, sources)
+
+	for _, source := range sources {
+		name := strings.TrimSuffix(source, ".md")
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			content, err := os.ReadFile(source)
+			require.NoError(t, err)
+
+			doc := Parse(string(content))
+			for ext, got := range map[string]string{".html": doc.HTML(), ".txt": doc.Text() + "\n"} {
+				golden := name + ext
+				if *update {
+					require.NoError(t, os.WriteFile(golden, []byte(got), 0o644))
+					continue
+				}
+
+				want, err := os.ReadFile(golden)
+				require.NoError(t, err)
+				assert.Equal(t, string(want), got, golden)
+			}
+		})
+	}
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "heading with closing sequence",
+			source: "## Title ##",
+			want:   "<h2>Title</h2>\n",
+		},
+		{
+			name:   "nested emphasis",
+			source: "*a **b** c*",
+			want:   "<p><em>a <strong>b</strong> c</em></p>\n",
+		},
+		{
+			name:   "unmatched delimiters stay text",
+			source: "2 * 3 = 6 and **open",
+			want:   "<p>2 * 3 = 6 and **open</p>\n",
+		},
+		{
+			name:   "code span keeps markup",
+			source: "use `a < b && *c*`",
+			want:   "<p>use <code>a &lt; b &amp;&amp; *c*</code></p>\n",
+		},
+		{
+			name:   "ordered list start",
+			source: "7. seven\n8. eight",
+			want:   "<ol start=\"7\">\n<li>seven</li>\n<li>eight</li>\n</ol>\n",
+		},
+		{
+			name:   "number in paragraph doesn't start a list",
+			source: "We met in\n2024. It was good.",
+			want:   "<p>We met in\n2024. It was good.</p>\n",
+		},
+		{
+			name:   "raw html is escaped",
+			source: "<script>alert(1)</script>",
+			want:   "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n",
+		},
+		{
+			name:   "unsafe link keeps its text",
+			source: "[x](vbscript:msgbox)",
+			want:   "<p>x</p>\n",
+		},
+		{
+			name:   "colon after path isn't a scheme",
+			source: "[x](/a:b)",
+			want:   "<p><a href=\"/a:b\">x</a></p>\n",
+		},
+		{
+			name:   "many delimiters",
+			source: strings.Repeat("*a ", 2000),
+			want:   "<p>" + strings.TrimSuffix(strings.Repeat("*a ", 2000), " ") + "</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ToHTML(tt.source))
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "link shows url",
+			source: "see [docs](https://example.com/docs)",
+			want:   "see docs (https://example.com/docs)",
+		},
+		{
+			name:   "autolink isn't repeated",
+			source: "<https://example.com>",
+			want:   "https://example.com",
+		},
+		{
+			name:   "unsafe link url is dropped",
+			source: "[x](javascript:alert(1))",
+			want:   "x",
+		},
+		{
+			name:   "heading levels",
+			source: "# One\n\n## Two\n\n### Three",
+			want:   "One\n===\n\nTwo\n---\n\nThree",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ToText(tt.source))
+		})
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "document.md"))
+	require.NoError(t, err)
+
+	first := ToHTML(string(content))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, ToHTML(string(content)))
+	}
+}
@@ -0,0 +1,269 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// safeSchemes lists the URL schemes links may use. URLs without a scheme
+// are relative and always allowed.
+var safeSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "tel": true}
+
+// safeImageSchemes lists the URL schemes images may use
+var safeImageSchemes = map[string]bool{"http": true, "https": true, "cid": true}
+
+// htmlEscaper escapes text for HTML element content and attribute values
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// HTML renders the document to sanitised HTML
+func (d *Document) HTML() string {
+	var b strings.Builder
+	writeHTMLBlocks(&b, d.blocks, false)
+	return b.String()
+}
+
+// Text renders the document to plain text. Block elements are separated by
+// blank lines and links are written as "text (url)".
+func (d *Document) Text() string {
+	return textBlocks(d.blocks, false)
+}
+
+// writeHTMLBlocks renders blocks. Paragraphs of tight list items are
+// written without <p> tags.
+func writeHTMLBlocks(b *strings.Builder, blocks []*block, tight bool) {
+	for i, bl := range blocks {
+		switch bl.kind {
+		case blockParagraph:
+			if tight {
+				writeHTMLInline(b, parseInline(bl.text))
+				if i < len(blocks)-1 {
+					b.WriteByte('\n')
+				}
+				continue
+			}
+			b.WriteString("<p>")
+			writeHTMLInline(b, parseInline(bl.text))
+			b.WriteString("</p>\n")
+
+		case blockHeading:
+			fmt.Fprintf(b, "<h%d>", bl.level)
+			writeHTMLInline(b, parseInline(bl.text))
+			fmt.Fprintf(b, "</h%d>\n", bl.level)
+
+		case blockCode:
+			b.WriteString("<pre><code")
+			if language := sanitizeLanguage(bl.info); language != "" {
+				fmt.Fprintf(b, ` class="language-%s"`, language)
+			}
+			b.WriteString(">")
+			b.WriteString(htmlEscaper.Replace(bl.text))
+			if bl.text != "" {
+				b.WriteByte('\n')
+			}
+			b.WriteString("</code></pre>\n")
+
+		case blockQuote:
+			b.WriteString("<blockquote>\n")
+			writeHTMLBlocks(b, bl.children, false)
+			b.WriteString("</blockquote>\n")
+
+		case blockList:
+			tag := "ul"
+			if bl.ordered {
+				tag = "ol"
+			}
+			if bl.ordered && bl.start != 1 {
+				fmt.Fprintf(b, "<ol start=\"%d\">\n", bl.start)
+			} else {
+				fmt.Fprintf(b, "<%s>\n", tag)
+			}
+			for _, item := range bl.items {
+				b.WriteString("<li>")
+				if len(item) > 0 && (bl.loose || item[0].kind != blockParagraph) {
+					b.WriteByte('\n')
+				}
+				writeHTMLBlocks(b, item, !bl.loose)
+				b.WriteString("</li>\n")
+			}
+			fmt.Fprintf(b, "</%s>\n", tag)
+
+		case blockRule:
+			b.WriteString("<hr />\n")
+		}
+	}
+}
+
+// writeHTMLInline renders inline nodes
+func writeHTMLInline(b *strings.Builder, nodes []inline) {
+	for _, node := range nodes {
+		switch node.kind {
+		case inlineText:
+			b.WriteString(htmlEscaper.Replace(node.text))
+		case inlineCode:
+			b.WriteString("<code>")
+			b.WriteString(htmlEscaper.Replace(node.text))
+			b.WriteString("</code>")
+		case inlineEmphasis:
+			b.WriteString("<em>")
+			writeHTMLInline(b, node.children)
+			b.WriteString("</em>")
+		case inlineStrong:
+			b.WriteString("<strong>")
+			writeHTMLInline(b, node.children)
+			b.WriteString("</strong>")
+		case inlineStrike:
+			b.WriteString("<del>")
+			writeHTMLInline(b, node.children)
+			b.WriteString("</del>")
+		case inlineLink:
+			if !isSafeURL(node.url, safeSchemes) {
+				writeHTMLInline(b, node.children)
+				continue
+			}
+			fmt.Fprintf(b, `<a href="%s"`, htmlEscaper.Replace(node.url))
+			if node.title != "" {
+				fmt.Fprintf(b, ` title="%s"`, htmlEscaper.Replace(node.title))
+			}
+			b.WriteString(">")
+			writeHTMLInline(b, node.children)
+			b.WriteString("</a>")
+		case inlineImage:
+			if !isSafeURL(node.url, safeImageSchemes) {
+				b.WriteString(htmlEscaper.Replace(node.text))
+				continue
+			}
+			fmt.Fprintf(b, `<img src="%s" alt="%s"`, htmlEscaper.Replace(node.url), htmlEscaper.Replace(node.text))
+			if node.title != "" {
+				fmt.Fprintf(b, ` title="%s"`, htmlEscaper.Replace(node.title))
+			}
+			b.WriteString(" />")
+		case inlineHardBreak:
+			b.WriteString("<br />\n")
+		case inlineSoftBreak:
+			b.WriteByte('\n')
+		}
+	}
+}
+
+// textBlocks renders blocks to plain text. Blocks of tight list items are
+// separated by a line break instead of a blank line.
+func textBlocks(blocks []*block, tight bool) string {
+	parts := make([]string, 0, len(blocks))
+	for _, bl := range blocks {
+		switch bl.kind {
+		case blockParagraph:
+			parts = append(parts, textInline(parseInline(bl.text)))
+
+		case blockHeading:
+			text := textInline(parseInline(bl.text))
+			switch bl.level {
+			case 1:
+				text += "\n" + strings.Repeat("=", utf8.RuneCountInString(text))
+			case 2:
+				text += "\n" + strings.Repeat("-", utf8.RuneCountInString(text))
+			}
+			parts = append(parts, text)
+
+		case blockCode:
+			parts = append(parts, prefixLines(bl.text, "    ", "    "))
+
+		case blockQuote:
+			parts = append(parts, prefixLines(textBlocks(bl.children, false), "> ", "> "))
+
+		case blockList:
+			items := make([]string, 0, len(bl.items))
+			for n, item := range bl.items {
+				bullet := "- "
+				if bl.ordered {
+					bullet = fmt.Sprintf("%d. ", bl.start+n)
+				}
+				items = append(items, prefixLines(textBlocks(item, !bl.loose), bullet, strings.Repeat(" ", len(bullet))))
+			}
+			separator := "\n"
+			if bl.loose {
+				separator = "\n\n"
+			}
+			parts = append(parts, strings.Join(items, separator))
+
+		case blockRule:
+			parts = append(parts, "----------")
+		}
+	}
+
+	separator := "\n\n"
+	if tight {
+		separator = "\n"
+	}
+	return strings.Join(parts, separator)
+}
+
+// textInline renders inline nodes to plain text
+func textInline(nodes []inline) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.kind {
+		case inlineText, inlineCode:
+			b.WriteString(node.text)
+		case inlineEmphasis, inlineStrong, inlineStrike:
+			b.WriteString(textInline(node.children))
+		case inlineLink:
+			text := textInline(node.children)
+			b.WriteString(text)
+			// Repeat the URL unless the text already shows it
+			if isSafeURL(node.url, safeSchemes) && node.url != text && node.url != "mailto:"+text {
+				fmt.Fprintf(&b, " (%s)", node.url)
+			}
+		case inlineImage:
+			b.WriteString(node.text)
+		case inlineHardBreak, inlineSoftBreak:
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// prefixLines prefixes the first line of text with first and the other
+// non-empty lines with rest
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" && i > 0 {
+			lines[i] = strings.TrimRight(prefix, " ")
+			continue
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isSafeURL reports whether a URL is relative or uses an allowed scheme
+func isSafeURL(url string, schemes map[string]bool) bool {
+	url = strings.TrimSpace(url)
+	colon := strings.IndexByte(url, ':')
+	if colon < 0 {
+		return true
+	}
+
+	// A colon after a path, query or fragment delimiter isn't a scheme
+	if delimiter := strings.IndexAny(url, "/?#"); delimiter >= 0 && delimiter < colon {
+		return true
+	}
+
+	return schemes[strings.ToLower(url[:colon])]
+}
+
+// sanitizeLanguage keeps the characters of a code block language that are
+// safe in a class attribute
+func sanitizeLanguage(info string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '+' {
+			return r
+		}
+		return -1
+	}, info)
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_SignVerify(t *testing.T) {
+	s := New("secret", "download")
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	token := s.Sign([]byte("link-1"), now.Add(time.Hour))
+	assert.NotContains(t, token, "=")
+	assert.NotContains(t, token, "/")
+
+	payload, expiresAt, err := s.Verify(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "link-1", string(payload))
+	assert.True(t, expiresAt.Equal(now.Add(time.Hour)))
+
+	_, _, err = s.Verify(token, now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestSigner_NoExpiry(t *testing.T) {
+	s := New("secret", "unsubscribe")
+
+	payload, expiresAt, err := s.Verify(s.Sign([]byte("ada@example.com"), time.Time{}), time.Now().AddDate(100, 0, 0))
+
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", string(payload))
+	assert.True(t, expiresAt.IsZero())
+}
+
+func TestSigner_Verify_Invalid(t *testing.T) {
+	s := New("secret", "download")
+	now := time.Now()
+	token := s.Sign([]byte("link-1"), now.Add(time.Hour))
+	data, mac, _ := strings.Cut(token, ".")
+
+	tampered := []byte(data)
+	tampered[0] ^= 1
+
+	tests := map[string]string{
+		"empty":           "",
+		"no mac":          data,
+		"tampered data":   string(tampered) + "." + mac,
+		"tampered mac":    data + "." + strings.Repeat("A", len(mac)),
+		"other secret":    New("other", "download").Sign([]byte("link-1"), now.Add(time.Hour)),
+		"other purpose":   New("secret", "tracking").Sign([]byte("link-1"), now.Add(time.Hour)),
+		"not base64":      "!!!." + mac,
+		"shorter than ts": "AA." + mac,
+	}
+
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := s.Verify(token, now)
+
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCalendarEmail(t *testing.T) {
+	ics := []byte("BEGIN:VCALENDAR\r\nMETHOD:REQUEST\r\nEND:VCALENDAR\r\n")
+	invite := CalendarInvite{Method: "request", Content: ics}
+
+	message := buildCalendarEmail("sender@example.com", "recipient@example.com", "Weekly sync", "See you there", invite)
+
+	encoded := base64.StdEncoding.EncodeToString(ics)
+	assert.Contains(t, message, "Content-Type: multipart/mixed; boundary=")
+	assert.Contains(t, message, "Content-Type: multipart/alternative; boundary=")
+	assert.Contains(t, message, "Content-Type: text/plain; charset=UTF-8\r\n\r\nSee you there")
+	assert.Contains(t, message, "Content-Type: text/calendar; charset=UTF-8; method=REQUEST\r\nContent-Transfer-Encoding: base64\r\n\r\n"+encoded)
+	assert.Contains(t, message, "Content-Disposition: attachment; filename=invite.ics")
+	assert.Equal(t, 2, strings.Count(message, encoded))
+}
+
+func TestBuildAlternativeEmail(t *testing.T) {
+	message := written(t, func(w io.Writer) error {
+		return writeAlternativeEmail(w, "sender@example.com", "recipient@example.com", "Hello", "Hi there", "<p>Hi there</p>", nil)
+	})
+
+	assert.Contains(t, message, "Content-Type: multipart/alternative; boundary=")
+	assert.NotContains(t, message, "multipart/mixed")
+
+	// The preferred HTML version comes last
+	text := strings.Index(message, "Content-Type: text/plain; charset=UTF-8\r\n\r\nHi there")
+	html := strings.Index(message, "Content-Type: text/html; charset=UTF-8\r\n\r\n<p>Hi there</p>")
+	assert.True(t, text > 0 && html > text)
+}
+
+func TestBuildAlternativeEmail_Attachments(t *testing.T) {
+	attachments := []Attachment{{Filename: "report.csv", Content: []byte("a,b"), MimeType: "text/csv"}}
+
+	message := written(t, func(w io.Writer) error {
+		return writeAlternativeEmail(w, "sender@example.com", "recipient@example.com", "Hello", "Hi", "<p>Hi</p>", attachments)
+	})
+
+	mixed := strings.Index(message, "Content-Type: multipart/mixed; boundary=")
+	alternative := strings.Index(message, "Content-Type: multipart/alternative; boundary=")
+	attachment := strings.Index(message, "Content-Disposition: attachment; filename=report.csv")
+	assert.True(t, mixed >= 0 && alternative > mixed && attachment > alternative)
+	assert.Contains(t, message, base64.StdEncoding.EncodeToString([]byte("a,b")))
+}
+
+func TestWriteBase64(t *testing.T) {
+	var buf strings.Builder
+	require.NoError(t, writeBase64(&buf, strings.NewReader(strings.Repeat("x", 100))))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	assert.Len(t, lines, 2)
+	assert.Len(t, lines[0], 76)
+}
+
+func TestWriteBase64_LineLengths(t *testing.T) {
+	// Lengths around the 57 bytes that encode to a full line
+	for _, size := range []int{0, 1, 56, 57, 58, 114, 1000} {
+		data := []byte(strings.Repeat("y", size))
+
+		var buf strings.Builder
+		require.NoError(t, writeBase64(&buf, iotest.OneByteReader(bytes.NewReader(data))))
+
+		encoded := base64.StdEncoding.EncodeToString(data)
+		var want strings.Builder
+		for i := 0; i < len(encoded); i += 76 {
+			want.WriteString(encoded[i:min(i+76, len(encoded))] + "\r\n")
+		}
+		assert.Equal(t, want.String(), buf.String(), "size %d", size)
+	}
+}
+
+func TestWriteMultipartEmail_Streams(t *testing.T) {
+	data := bytes.Repeat([]byte("%PDF"), 1000)
+	opened := 0
+	attachments := []Attachment{{
+		Filename: "large.pdf",
+		MimeType: "application/pdf",
+		Size:     int64(len(data)),
+		Open: func() (io.ReadCloser, error) {
+			opened++
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+	}}
+
+	message := written(t, func(w io.Writer) error {
+		return writeMultipartEmail(w, "sender@example.com", "recipient@example.com", "Report", "Attached", attachments)
+	})
+
+	assert.Equal(t, 1, opened)
+	assert.Contains(t, message, "Content-Disposition: attachment; filename=large.pdf\r\n\r\n")
+	start := strings.Index(message, "large.pdf\r\n\r\n") + len("large.pdf\r\n\r\n")
+	end := strings.LastIndex(message, "\r\n--_boundary_")
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(message[start:end], "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+}
+
+func TestWriteMultipartEmail_OpenError(t *testing.T) {
+	attachments := []Attachment{{
+		Filename: "gone.pdf",
+		Open:     func() (io.ReadCloser, error) { return nil, errors.New("file removed") },
+	}}
+
+	err := writeMultipartEmail(io.Discard, "sender@example.com", "recipient@example.com", "Report", "Attached", attachments)
+
+	assert.ErrorContains(t, err, "failed to open attachment gone.pdf: file removed")
+}
+
+func TestWriteMultipartEmail_WriteError(t *testing.T) {
+	attachments := []Attachment{{Filename: "a.txt", MimeType: "text/plain", Content: []byte(strings.Repeat("a", 1000))}}
+
+	err := writeMultipartEmail(&failingWriter{limit: 300}, "sender@example.com", "recipient@example.com", "Report", "Attached", attachments)
+
+	assert.ErrorContains(t, err, "connection reset")
+}
+
+// written returns what write writes
+func written(t *testing.T, write func(w io.Writer) error) string {
+	t.Helper()
+	var buf strings.Builder
+	require.NoError(t, write(&buf))
+	return buf.String()
+}
+
+// failingWriter fails once more than limit bytes are written
+type failingWriter struct {
+	limit int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > f.limit {
+		return 0, errors.New("connection reset")
+	}
+	f.limit -= len(p)
+	return len(p), nil
+}
+
+func TestBuildAlternativeEmail_Inline(t *testing.T) {
+	attachments := []Attachment{
+		{Filename: "logo.png", Content: []byte("png"), MimeType: "image/png", ContentID: "logo@example.com"},
+		{Filename: "report.csv", Content: []byte("a,b"), MimeType: "text/csv"},
+	}
+
+	message := written(t, func(w io.Writer) error {
+		return writeAlternativeEmail(w, "sender@example.com", "recipient@example.com", "Hello", "Hi", `<img src="cid:logo@example.com">`, attachments)
+	})
+
+	mixed := strings.Index(message, "Content-Type: multipart/mixed; boundary=")
+	related := strings.Index(message, "Content-Type: multipart/related; boundary=")
+	alternative := strings.Index(message, "Content-Type: multipart/alternative; boundary=")
+	inline := strings.Index(message, "Content-ID: <logo@example.com>\r\nContent-Disposition: inline; filename=logo.png")
+	attachment := strings.Index(message, "Content-Disposition: attachment; filename=report.csv")
+	assert.True(t, mixed >= 0 && related > mixed && alternative > related && inline > alternative && attachment > inline)
+}
+
+func TestWriteMessage_HTMLAttachments(t *testing.T) {
+	attachments := []Attachment{{Filename: "report.csv", Content: []byte("a,b"), MimeType: "text/csv"}}
+
+	message := written(t, func(w io.Writer) error {
+		return writeMessage(w, "sender@example.com", EmailRequest{To: "recipient@example.com", Subject: "Hi", Body: "<p>Hi</p>", IsHTML: true, Attachments: attachments})
+	})
+
+	mixed := strings.Index(message, "Content-Type: multipart/mixed; boundary=")
+	html := strings.Index(message, "Content-Type: text/html; charset=UTF-8\r\n\r\n<p>Hi</p>")
+	attachment := strings.Index(message, "Content-Disposition: attachment; filename=report.csv")
+	assert.True(t, mixed >= 0 && html > mixed && attachment > html)
+	assert.NotContains(t, message, "multipart/alternative")
+	assert.NotContains(t, message, "text/plain")
+}
+
+func TestWriteMessage_MessageID(t *testing.T) {
+	message := written(t, func(w io.Writer) error {
+		return writeMessage(w, "sender@example.com", EmailRequest{To: "recipient@example.com", Subject: "Hi", Body: "Hello", MessageID: "abc@example.com"})
+	})
+
+	assert.True(t, strings.HasPrefix(message, "Message-ID: <abc@example.com>\r\n"), message)
+	assert.Contains(t, message, "\r\n\r\nHello")
+}
+
+func TestNewMessageID(t *testing.T) {
+	id := NewMessageID("Sender <sender@mail.example.com>")
+
+	assert.Regexp(t, `^[0-9a-f]{32}@mail\.example\.com$`, id)
+	assert.NotEqual(t, id, NewMessageID("sender@mail.example.com"))
+	assert.True(t, strings.HasSuffix(NewMessageID(""), "@localhost"))
+}
+
+func TestXtext(t *testing.T) {
+	assert.Equal(t, "abc@example.com", xtext("abc@example.com"))
+	assert.Equal(t, "a+2Bb+3Dc+20d", xtext("a+b=c d"))
+}
+
+func TestWriteMessage_Headers(t *testing.T) {
+	message := written(t, func(w io.Writer) error {
+		return writeMessage(w, "sender@example.com", EmailRequest{
+			To:        "recipient@example.com",
+			Subject:   "Hi",
+			Body:      "Hello",
+			MessageID: "abc@example.com",
+			Headers:   []Header{{Name: "List-Unsubscribe", Value: "<https://mail.example.com/u/token>"}},
+		})
+	})
+
+	assert.True(t, strings.HasPrefix(message, "Message-ID: <abc@example.com>\r\nList-Unsubscribe: <https://mail.example.com/u/token>\r\n"), message)
+}
+
+func TestWriteMessage_InvalidHeader(t *testing.T) {
+	err := writeMessage(io.Discard, "sender@example.com", EmailRequest{
+		To:      "recipient@example.com",
+		Headers: []Header{{Name: "X-Test", Value: "a\r\nBcc: eve@example.com"}},
+	})
+
+	assert.Error(t, err)
+}
+
+func TestWithHeaders(t *testing.T) {
+	ctx := WithHeaders(context.Background(), Header{Name: "A", Value: "1"})
+	ctx = WithHeaders(ctx, Header{Name: "B", Value: "2"})
+
+	assert.Equal(t, []Header{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}}, headersFrom(ctx))
+}
+
+func TestEnvelopeRecipients(t *testing.T) {
+	req := EmailRequest{To: "ada@example.org, bob@example.org"}
+	assert.Equal(t, []string{"ada@example.org", "bob@example.org"}, envelopeRecipients(req))
+
+	req.Recipients = recipientsFrom(WithRecipients(context.Background(), "bob@example.org"))
+	assert.Equal(t, []string{"bob@example.org"}, envelopeRecipients(req))
+}
+
+func TestShowTo(t *testing.T) {
+	req := EmailRequest{To: "bob@example.org"}
+	assert.Equal(t, req, showTo(req, toHeaderFrom(context.Background())))
+
+	got := showTo(req, toHeaderFrom(WithToHeader(context.Background(), "ada@example.org")))
+	assert.Equal(t, "ada@example.org", got.To)
+	assert.Equal(t, []string{"bob@example.org"}, envelopeRecipients(got))
+
+	// Recipients set for the send are kept
+	req.Recipients = []string{"cy@example.org"}
+	got = showTo(req, "ada@example.org")
+	assert.Equal(t, []string{"cy@example.org"}, envelopeRecipients(got))
+}
@@ -1,12 +1,52 @@
 package smtp
 
-import "time"
+import (
+	"bytes"
+	"io"
+	"time"
+)
 
 // Attachment represents an email attachment
 type Attachment struct {
 	Filename string
 	Content  []byte
 	MimeType string
+
+	// ContentID sends the attachment inline, referenced from HTML bodies
+	// as cid:<ContentID>
+	ContentID string
+
+	// URL is an https address the caller fetches Content from when it is
+	// empty. The client itself only sends Content.
+	URL string
+
+	// Open streams the content instead of Content, for example from an
+	// uploaded file, so it is never held in memory as a whole. It is called
+	// once per send attempt. Size is the length of the content it returns.
+	Open func() (io.ReadCloser, error) `json:"-"`
+	Size int64                         `json:"-"`
+}
+
+// Len returns the length of the attachment's content
+func (a Attachment) Len() int64 {
+	if a.Open != nil {
+		return a.Size
+	}
+	return int64(len(a.Content))
+}
+
+// Reader opens the attachment's content
+func (a Attachment) Reader() (io.ReadCloser, error) {
+	if a.Open != nil {
+		return a.Open()
+	}
+	return io.NopCloser(bytes.NewReader(a.Content)), nil
+}
+
+// CalendarInvite represents an iCalendar object sent as a text/calendar part
+type CalendarInvite struct {
+	Method  string
+	Content []byte
 }
 
 // Config holds configuration for the SMTP client
@@ -32,8 +72,13 @@ type EmailRequest struct {
 	To          string
 	Subject     string
 	Body        string
+	TextBody    string // Plain text alternative of an HTML body
 	IsHTML      bool
 	Attachments []Attachment
+	Calendar    *CalendarInvite
+	MessageID   string   // Without angle brackets, left to the relay when empty
+	Recipients  []string // Envelope recipients, the addresses in To when empty
+	Headers     []Header
 }
 
 // EmailResponse represents a response from sending an email
@@ -0,0 +1,45 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// headersKey is the context key of the extra headers of a send
+type headersKey struct{}
+
+// Header is a header field added to the messages of a send, such as
+// List-Unsubscribe
+type Header struct {
+	Name  string
+	Value string
+}
+
+// WithHeaders returns a context whose sends carry extra header fields,
+// after those set on ctx already
+func WithHeaders(ctx context.Context, headers ...Header) context.Context {
+	all := append(append([]Header(nil), headersFrom(ctx)...), headers...)
+	return context.WithValue(ctx, headersKey{}, all)
+}
+
+// headersFrom returns the extra headers set on a context, if any
+func headersFrom(ctx context.Context) []Header {
+	headers, _ := ctx.Value(headersKey{}).([]Header)
+	return headers
+}
+
+// writeHeaders writes extra header fields, rejecting names and values that
+// would start a new field
+func writeHeaders(w io.Writer, headers []Header) error {
+	for _, header := range headers {
+		if header.Name == "" || strings.ContainsAny(header.Name, ": \t\r\n") || strings.ContainsAny(header.Value, "\r\n") {
+			return fmt.Errorf("smtp: invalid header %q", header.Name)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\r\n", header.Name, header.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package smtp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/smtp"
+	"strings"
+)
+
+// messageIDKey is the context key of the Message-ID of a send
+type messageIDKey struct{}
+
+// NewMessageID returns a unique Message-ID, without angle brackets, in the
+// domain of the sender address
+func NewMessageID(from string) string {
+	var random [16]byte
+	rand.Read(random[:])
+
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	return hex.EncodeToString(random[:]) + "@" + domain
+}
+
+// WithMessageID returns a context whose sends carry the Message-ID id. It
+// is also the envelope ID of servers supporting delivery status
+// notifications (RFC 3461), so bounces can be matched to the email.
+func WithMessageID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, messageIDKey{}, id)
+}
+
+// messageIDFrom returns the Message-ID set on a context, if any
+func messageIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(messageIDKey{}).(string)
+	return id
+}
+
+// startMail starts a mail transaction, with the envelope ID when the server
+// supports delivery status notifications
+func startMail(client *smtp.Client, from, envelopeID string) error {
+	if envelopeID == "" {
+		return client.Mail(from)
+	}
+	if ok, _ := client.Extension("DSN"); !ok {
+		return client.Mail(from)
+	}
+	if strings.ContainsAny(from, "\r\n") {
+		return errors.New("smtp: A line must not contain CR or LF")
+	}
+
+	// Keep the parameters net/smtp sends for the extensions it knows
+	cmd := fmt.Sprintf("MAIL FROM:<%s> ENVID=%s", from, xtext(envelopeID))
+	if ok, _ := client.Extension("8BITMIME"); ok {
+		cmd += " BODY=8BITMIME"
+	}
+	if ok, _ := client.Extension("SMTPUTF8"); ok {
+		cmd += " SMTPUTF8"
+	}
+
+	id, err := client.Text.Cmd("%s", cmd)
+	if err != nil {
+		return err
+	}
+	client.Text.StartResponse(id)
+	defer client.Text.EndResponse(id)
+	_, _, err = client.Text.ReadResponse(250)
+	return err
+}
+
+// xtext encodes a value of an ESMTP parameter as per RFC 3461
+func xtext(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < '!' || c > '~' || c == '+' || c == '=' {
+			fmt.Fprintf(&b, "+%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
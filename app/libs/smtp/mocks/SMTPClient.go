@@ -83,6 +83,34 @@ func (_m *SMTPClient) SendWithAttachments(ctx context.Context, from string, to s
 	return r0
 }
 
+// SendCalendarInvite provides a mock function with given fields: ctx, from, to, subject, body, invite
+func (_m *SMTPClient) SendCalendarInvite(ctx context.Context, from string, to string, subject string, body string, invite smtp.CalendarInvite) error {
+	ret := _m.Called(ctx, from, to, subject, body, invite)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, smtp.CalendarInvite) error); ok {
+		r0 = rf(ctx, from, to, subject, body, invite)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendAlternative provides a mock function with given fields: ctx, from, to, subject, textBody, htmlBody, attachments
+func (_m *SMTPClient) SendAlternative(ctx context.Context, from string, to string, subject string, textBody string, htmlBody string, attachments []smtp.Attachment) error {
+	ret := _m.Called(ctx, from, to, subject, textBody, htmlBody, attachments)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, []smtp.Attachment) error); ok {
+		r0 = rf(ctx, from, to, subject, textBody, htmlBody, attachments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewSMTPClient creates a new instance of SMTPClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSMTPClient(t interface {
@@ -0,0 +1,66 @@
+package smtp
+
+import (
+	"context"
+	"strings"
+)
+
+// recipientsKey is the context key of the envelope recipients of a send
+type recipientsKey struct{}
+
+// WithRecipients returns a context whose sends are delivered to the given
+// addresses only, while the To header still lists every recipient. It lets
+// a message be sent as one copy per recipient.
+func WithRecipients(ctx context.Context, recipients ...string) context.Context {
+	return context.WithValue(ctx, recipientsKey{}, recipients)
+}
+
+// recipientsFrom returns the envelope recipients set on a context, if any
+func recipientsFrom(ctx context.Context) []string {
+	recipients, _ := ctx.Value(recipientsKey{}).([]string)
+	return recipients
+}
+
+// envelopeRecipients returns the addresses a request is delivered to: the
+// recipients set for the send, or the addresses of its To header
+func envelopeRecipients(req EmailRequest) []string {
+	recipients := req.Recipients
+	if len(recipients) == 0 {
+		recipients = strings.Split(req.To, ",")
+	}
+
+	addresses := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			addresses = append(addresses, recipient)
+		}
+	}
+	return addresses
+}
+
+// toHeaderKey is the context key of the To header of a send
+type toHeaderKey struct{}
+
+// WithToHeader returns a context whose sends show to in their To header,
+// while they are delivered to their recipients. It lets a submitted message
+// keep its headers when it's sent to some of its recipients only.
+func WithToHeader(ctx context.Context, to string) context.Context {
+	return context.WithValue(ctx, toHeaderKey{}, to)
+}
+
+// toHeaderFrom returns the To header set on a context, if any
+func toHeaderFrom(ctx context.Context) string {
+	to, _ := ctx.Value(toHeaderKey{}).(string)
+	return to
+}
+
+// showTo returns a request that is delivered to the recipients of req and
+// shows to in its To header, or req when to is empty
+func showTo(req EmailRequest, to string) EmailRequest {
+	if to == "" {
+		return req
+	}
+	req.Recipients = envelopeRecipients(req)
+	req.To = to
+	return req
+}
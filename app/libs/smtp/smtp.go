@@ -1,11 +1,13 @@
 package smtp
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/mail"
@@ -21,6 +23,8 @@ type SMTPClient interface {
 	Send(ctx context.Context, from, to, subject, body string) error
 	SendHTML(ctx context.Context, from, to, subject, htmlBody string) error
 	SendWithAttachments(ctx context.Context, from, to, subject, body string, attachments []Attachment) error
+	SendCalendarInvite(ctx context.Context, from, to, subject, body string, invite CalendarInvite) error
+	SendAlternative(ctx context.Context, from, to, subject, textBody, htmlBody string, attachments []Attachment) error
 	IsConnected() bool
 }
 
@@ -296,9 +300,41 @@ func (c *smtpClient) SendWithAttachments(ctx context.Context, from, to, subject,
 	return c.sendWithRetry(ctx, req)
 }
 
+// SendCalendarInvite sends a calendar invitation through the SMTP server
+func (c *smtpClient) SendCalendarInvite(ctx context.Context, from, to, subject, body string, invite CalendarInvite) error {
+	req := EmailRequest{
+		From:     from,
+		To:       to,
+		Subject:  subject,
+		Body:     body,
+		IsHTML:   false,
+		Calendar: &invite,
+	}
+	return c.sendWithRetry(ctx, req)
+}
+
+// SendAlternative sends an email with plain text and HTML versions of the
+// same content, plus optional attachments, through the SMTP server
+func (c *smtpClient) SendAlternative(ctx context.Context, from, to, subject, textBody, htmlBody string, attachments []Attachment) error {
+	req := EmailRequest{
+		From:        from,
+		To:          to,
+		Subject:     subject,
+		Body:        htmlBody,
+		TextBody:    textBody,
+		IsHTML:      true,
+		Attachments: attachments,
+	}
+	return c.sendWithRetry(ctx, req)
+}
+
 // sendWithRetry attempts to send an email with retries
 func (c *smtpClient) sendWithRetry(ctx context.Context, req EmailRequest) error {
 	var lastErr error
+	req.MessageID = messageIDFrom(ctx)
+	req.Headers = headersFrom(ctx)
+	req.Recipients = recipientsFrom(ctx)
+	req = showTo(req, toHeaderFrom(ctx))
 
 	for attempt := 0; attempt <= c.retryAttempts; attempt++ {
 		select {
@@ -358,29 +394,15 @@ func (c *smtpClient) sendEmail(ctx context.Context, req EmailRequest) error {
 		from = c.config.From
 	}
 
-	// Prepare email headers and body
-	var message string
-	if req.IsHTML {
-		message = buildHTMLEmail(from, req.To, req.Subject, req.Body)
-	} else if len(req.Attachments) > 0 {
-		message = buildMultipartEmail(from, req.To, req.Subject, req.Body, req.Attachments)
-	} else {
-		message = buildPlainEmail(from, req.To, req.Subject, req.Body)
-	}
-
 	// Set the sender
-	if err := client.Mail(from); err != nil {
+	if err := startMail(client, from, req.MessageID); err != nil {
 		return fmt.Errorf("failed to set sender: %w", err)
 	}
 
 	// Set the recipients
-	recipients := strings.Split(req.To, ",")
-	for _, recipient := range recipients {
-		recipient = strings.TrimSpace(recipient)
-		if recipient != "" {
-			if err := client.Rcpt(recipient); err != nil {
-				return fmt.Errorf("failed to add recipient %s: %w", recipient, err)
-			}
+	for _, recipient := range envelopeRecipients(req) {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %w", recipient, err)
 		}
 	}
 
@@ -390,8 +412,15 @@ func (c *smtpClient) sendEmail(ctx context.Context, req EmailRequest) error {
 		return fmt.Errorf("failed to open data writer: %w", err)
 	}
 
-	_, err = w.Write([]byte(message))
-	if err != nil {
+	// Attachments are streamed into the data writer as they are encoded
+	if err := writeMessage(w, from, req); err != nil {
+		// Closing the data writer would send the partial message, so drop
+		// the connection to abort the transaction instead. Pooled clients
+		// are replaced by the health check when they are taken again.
+		client.Close()
+		if !returnToPool {
+			c.client = nil
+		}
 		return fmt.Errorf("failed to write email data: %w", err)
 	}
 
@@ -403,6 +432,34 @@ func (c *smtpClient) sendEmail(ctx context.Context, req EmailRequest) error {
 	return nil
 }
 
+// writeMessage writes the headers and body of a request to w
+func writeMessage(w io.Writer, from string, req EmailRequest) error {
+	if req.MessageID != "" {
+		if _, err := fmt.Fprintf(w, "Message-ID: <%s>\r\n", req.MessageID); err != nil {
+			return err
+		}
+	}
+	if err := writeHeaders(w, req.Headers); err != nil {
+		return err
+	}
+
+	switch {
+	case req.Calendar != nil:
+		_, err := io.WriteString(w, buildCalendarEmail(from, req.To, req.Subject, req.Body, *req.Calendar))
+		return err
+	case req.IsHTML && (req.TextBody != "" || len(req.Attachments) > 0):
+		return writeAlternativeEmail(w, from, req.To, req.Subject, req.TextBody, req.Body, req.Attachments)
+	case req.IsHTML:
+		_, err := io.WriteString(w, buildHTMLEmail(from, req.To, req.Subject, req.Body))
+		return err
+	case len(req.Attachments) > 0:
+		return writeMultipartEmail(w, from, req.To, req.Subject, req.Body, req.Attachments)
+	default:
+		_, err := io.WriteString(w, buildPlainEmail(from, req.To, req.Subject, req.Body))
+		return err
+	}
+}
+
 // Helper functions to build email content
 func buildPlainEmail(from, to, subject, body string) string {
 	fromAddr := parseAddress(from)
@@ -431,12 +488,13 @@ func buildHTMLEmail(from, to, subject, htmlBody string) string {
 	return buildMessage(header, htmlBody)
 }
 
-func buildMultipartEmail(from, to, subject, body string, attachments []Attachment) string {
+// writeMultipartEmail writes a multipart/mixed message with a text body,
+// streaming the attachments
+func writeMultipartEmail(w io.Writer, from, to, subject, body string, attachments []Attachment) error {
 	// Generate a boundary string
 	boundary := fmt.Sprintf("_boundary_%d", time.Now().UnixNano())
 
-	// Create buffer for multipart message
-	var buf strings.Builder
+	buf := &messageWriter{w: w}
 	
 	// Set up headers
 	fromAddr := parseAddress(from)
@@ -457,30 +515,237 @@ func buildMultipartEmail(from, to, subject, body string, attachments []Attachmen
 
 	// Add attachments
 	for _, att := range attachments {
-		buf.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-		buf.WriteString(fmt.Sprintf("Content-Type: %s\r\n", att.MimeType))
-		buf.WriteString("Content-Transfer-Encoding: base64\r\n")
-		buf.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=%s\r\n\r\n", att.Filename))
+		writeAttachment(buf, boundary, att)
+	}
 
-		// Base64 encode the attachment content
-		encoded := base64.StdEncoding.EncodeToString(att.Content)
-		
-		// Write in lines of 76 characters as per RFC 2045
-		for i := 0; i < len(encoded); i += 76 {
-			end := i + 76
-			if end > len(encoded) {
-				end = len(encoded)
-			}
-			buf.WriteString(encoded[i:end])
-			buf.WriteString("\r\n")
+	// Close the MIME multipart message
+	buf.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+
+	return buf.err
+}
+
+// buildCalendarEmail builds an invitation that calendar clients recognise:
+// a multipart/alternative with the text body and a text/calendar part
+// carrying the iTIP method, plus the same object as an .ics attachment
+// for clients that only look at attachments
+func buildCalendarEmail(from, to, subject, body string, invite CalendarInvite) string {
+	mixedBoundary := fmt.Sprintf("_mixed_%d", time.Now().UnixNano())
+	altBoundary := fmt.Sprintf("_alt_%d", time.Now().UnixNano())
+	method := strings.ToUpper(invite.Method)
+
+	var buf strings.Builder
+
+	// Write the headers
+	buf.WriteString(fmt.Sprintf("From: %s\r\n", parseAddress(from)))
+	buf.WriteString(fmt.Sprintf("To: %s\r\n", parseAddress(to)))
+	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixedBoundary))
+
+	// Add the alternative part holding the body and the invitation
+	buf.WriteString(fmt.Sprintf("--%s\r\n", mixedBoundary))
+	buf.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%s\r\n\r\n", altBoundary))
+
+	buf.WriteString(fmt.Sprintf("--%s\r\n", altBoundary))
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	buf.WriteString(body)
+	buf.WriteString("\r\n\r\n")
+
+	buf.WriteString(fmt.Sprintf("--%s\r\n", altBoundary))
+	buf.WriteString(fmt.Sprintf("Content-Type: text/calendar; charset=UTF-8; method=%s\r\n", method))
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	writeBase64(&buf, bytes.NewReader(invite.Content))
+	buf.WriteString("\r\n")
+	buf.WriteString(fmt.Sprintf("--%s--\r\n\r\n", altBoundary))
+
+	// Add the invitation as an attachment
+	buf.WriteString(fmt.Sprintf("--%s\r\n", mixedBoundary))
+	buf.WriteString(fmt.Sprintf("Content-Type: application/ics; name=invite.ics; method=%s\r\n", method))
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("Content-Disposition: attachment; filename=invite.ics\r\n\r\n")
+	writeBase64(&buf, bytes.NewReader(invite.Content))
+	buf.WriteString("\r\n")
+
+	// Close the MIME multipart message
+	buf.WriteString(fmt.Sprintf("--%s--\r\n", mixedBoundary))
+
+	return buf.String()
+}
+
+// writeAlternativeEmail writes a multipart/alternative message with the
+// plain text part first and the preferred HTML part last, as per RFC 2046.
+// Inline attachments wrap the alternative in a multipart/related part the
+// HTML references by Content-ID, other attachments wrap everything in a
+// multipart/mixed message. Without a text part the HTML part takes the
+// place of the alternative.
+func writeAlternativeEmail(w io.Writer, from, to, subject, textBody, htmlBody string, attachments []Attachment) error {
+	mixedBoundary := fmt.Sprintf("_mixed_%d", time.Now().UnixNano())
+	relatedBoundary := fmt.Sprintf("_related_%d", time.Now().UnixNano())
+	altBoundary := fmt.Sprintf("_alt_%d", time.Now().UnixNano())
+
+	var inline, attached []Attachment
+	for _, att := range attachments {
+		if att.ContentID != "" {
+			inline = append(inline, att)
+		} else {
+			attached = append(attached, att)
+		}
+	}
+
+	buf := &messageWriter{w: w}
+
+	// Write the headers
+	buf.WriteString(fmt.Sprintf("From: %s\r\n", parseAddress(from)))
+	buf.WriteString(fmt.Sprintf("To: %s\r\n", parseAddress(to)))
+	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	if len(attached) > 0 {
+		buf.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixedBoundary))
+		buf.WriteString(fmt.Sprintf("--%s\r\n", mixedBoundary))
+	}
+	if len(inline) > 0 {
+		rootType := "multipart/alternative"
+		if textBody == "" {
+			rootType = "text/html"
 		}
+		buf.WriteString(fmt.Sprintf("Content-Type: multipart/related; boundary=%s; type=\"%s\"\r\n\r\n", relatedBoundary, rootType))
+		buf.WriteString(fmt.Sprintf("--%s\r\n", relatedBoundary))
+	}
+
+	if textBody == "" {
+		// Add the HTML part on its own
+		buf.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+		buf.WriteString(htmlBody)
+		buf.WriteString("\r\n\r\n")
+	} else {
+		buf.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%s\r\n\r\n", altBoundary))
+
+		// Add the text and HTML versions
+		buf.WriteString(fmt.Sprintf("--%s\r\n", altBoundary))
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		buf.WriteString(textBody)
+		buf.WriteString("\r\n\r\n")
+
+		buf.WriteString(fmt.Sprintf("--%s\r\n", altBoundary))
+		buf.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+		buf.WriteString(htmlBody)
+		buf.WriteString("\r\n\r\n")
+		buf.WriteString(fmt.Sprintf("--%s--\r\n", altBoundary))
+	}
+
+	// Add the inline parts next to the HTML referencing them
+	if len(inline) > 0 {
 		buf.WriteString("\r\n")
+		for _, att := range inline {
+			writeAttachment(buf, relatedBoundary, att)
+		}
+		buf.WriteString(fmt.Sprintf("--%s--\r\n", relatedBoundary))
+	}
+
+	if len(attached) == 0 {
+		return buf.err
+	}
+
+	// Add attachments
+	buf.WriteString("\r\n")
+	for _, att := range attached {
+		writeAttachment(buf, mixedBoundary, att)
 	}
 
 	// Close the MIME multipart message
-	buf.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+	buf.WriteString(fmt.Sprintf("--%s--\r\n", mixedBoundary))
 
-	return buf.String()
+	return buf.err
+}
+
+// writeAttachment writes an attachment as a base64 encoded part. Parts with
+// a Content-ID are inline, so HTML bodies can show them as cid:<id>.
+func writeAttachment(buf *messageWriter, boundary string, att Attachment) {
+	buf.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	buf.WriteString(fmt.Sprintf("Content-Type: %s\r\n", att.MimeType))
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	if att.ContentID != "" {
+		buf.WriteString(fmt.Sprintf("Content-ID: <%s>\r\n", att.ContentID))
+		buf.WriteString(fmt.Sprintf("Content-Disposition: inline; filename=%s\r\n\r\n", att.Filename))
+	} else {
+		buf.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=%s\r\n\r\n", att.Filename))
+	}
+	if buf.err != nil {
+		return
+	}
+
+	// Stream the content through the base64 encoder
+	content, err := att.Reader()
+	if err != nil {
+		buf.err = fmt.Errorf("failed to open attachment %s: %w", att.Filename, err)
+		return
+	}
+	defer content.Close()
+	if err := writeBase64(buf.w, content); err != nil {
+		buf.err = fmt.Errorf("failed to write attachment %s: %w", att.Filename, err)
+		return
+	}
+	buf.WriteString("\r\n")
+}
+
+// writeBase64 streams data base64 encoded in lines of 76 characters as per RFC 2045
+func writeBase64(w io.Writer, data io.Reader) error {
+	lines := &lineWriter{w: w}
+	encoder := base64.NewEncoder(base64.StdEncoding, lines)
+	if _, err := io.Copy(encoder, data); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if lines.column > 0 {
+		_, err := io.WriteString(w, "\r\n")
+		return err
+	}
+	return nil
+}
+
+// lineWriter breaks the output of the base64 encoder into lines
+type lineWriter struct {
+	w      io.Writer
+	column int
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := 76 - l.column
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := l.w.Write(p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		l.column += n
+		p = p[n:]
+
+		if l.column == 76 {
+			if _, err := io.WriteString(l.w, "\r\n"); err != nil {
+				return written, err
+			}
+			l.column = 0
+		}
+	}
+	return written, nil
+}
+
+// messageWriter writes message parts and keeps the first error, so a
+// message can be written without checking every line
+type messageWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (m *messageWriter) WriteString(s string) {
+	if m.err == nil {
+		_, m.err = io.WriteString(m.w, s)
+	}
 }
 
 // Helper function to build a message with headers
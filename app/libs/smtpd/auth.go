@@ -0,0 +1,164 @@
+package smtpd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+var (
+	errAuthCancelled   = errors.New("authentication cancelled")
+	errInvalidResponse = errors.New("invalid authentication response")
+)
+
+// canAuth reports whether AUTH is offered on the connection
+func (c *conn) canAuth() bool {
+	_, ok := c.server.authenticator()
+	return ok && (c.session.TLS || c.server.AllowInsecureAuth)
+}
+
+// auth authenticates the client with the PLAIN or LOGIN mechanism and
+// reports whether the connection must be closed
+func (c *conn) auth(arg string) bool {
+	authenticator, ok := c.server.authenticator()
+	switch {
+	case !ok:
+		c.fail(502, "5.5.1", "Command not implemented")
+		return false
+	case !c.session.TLS && !c.server.AllowInsecureAuth:
+		c.fail(538, "5.7.11", "Encryption required for requested authentication mechanism")
+		return false
+	case c.session.Helo == "":
+		c.fail(503, "5.5.1", "Send EHLO first")
+		return false
+	case c.session.User != "":
+		c.fail(503, "5.5.1", "Already authenticated")
+		return false
+	case c.session.From != "":
+		c.fail(503, "5.5.1", "AUTH not permitted during a mail transaction")
+		return false
+	}
+
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	initial = strings.TrimSpace(initial)
+
+	var username, password string
+	var err error
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		username, password, err = c.authPlain(initial)
+	case "LOGIN":
+		username, password, err = c.authLogin(initial)
+	default:
+		c.fail(504, "5.5.4", "Unrecognized authentication mechanism")
+		return false
+	}
+	switch {
+	case errors.Is(err, errAuthCancelled):
+		c.fail(501, "5.0.0", "Authentication cancelled")
+		return false
+	case errors.Is(err, errInvalidResponse), errors.Is(err, errLineTooLong):
+		c.fail(501, "5.5.2", "Invalid authentication response")
+		return false
+	case errors.Is(err, ErrAuthFailed):
+		c.fail(ErrAuthFailed.Code, ErrAuthFailed.EnhancedCode, ErrAuthFailed.Message)
+		return false
+	case err != nil:
+		return true
+	}
+
+	user, err := authenticator.Auth(c.ctx, c.envelope(), username, password)
+	if err != nil {
+		var smtpErr *Error
+		if errors.As(err, &smtpErr) {
+			c.fail(smtpErr.Code, smtpErr.EnhancedCode, smtpErr.Message)
+			return false
+		}
+		c.server.logf("SMTP authentication error for %s: %v", c.session.RemoteAddr, err)
+		c.fail(454, "4.7.0", "Temporary authentication failure")
+		return false
+	}
+
+	// An empty identity stands for the username
+	if user == "" {
+		user = username
+	}
+	c.session.User = user
+	c.session.Username = username
+	c.reply(235, "2.7.0", "Authentication successful")
+	return false
+}
+
+// authPlain reads the "authzid NUL authcid NUL passwd" response of the
+// PLAIN mechanism (RFC 4616). An authorization identity other than the
+// username is rejected like invalid credentials.
+func (c *conn) authPlain(initial string) (string, string, error) {
+	var response []byte
+	var err error
+	if initial != "" {
+		response, err = decodeResponse(initial)
+	} else {
+		response, err = c.challenge("")
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	parts := bytes.Split(response, []byte{0})
+	if len(parts) != 3 {
+		return "", "", errInvalidResponse
+	}
+	identity, username, password := string(parts[0]), string(parts[1]), string(parts[2])
+	if identity != "" && identity != username {
+		return "", "", ErrAuthFailed
+	}
+	return username, password, nil
+}
+
+// authLogin asks for the username unless it was given with the command,
+// then for the password
+func (c *conn) authLogin(initial string) (string, string, error) {
+	var username []byte
+	var err error
+	if initial != "" {
+		username, err = decodeResponse(initial)
+	} else {
+		username, err = c.challenge("Username:")
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	password, err := c.challenge("Password:")
+	if err != nil {
+		return "", "", err
+	}
+	return string(username), string(password), nil
+}
+
+// challenge sends a base64 encoded challenge and reads the response
+func (c *conn) challenge(prompt string) ([]byte, error) {
+	c.reply(334, "", base64.StdEncoding.EncodeToString([]byte(prompt)))
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	return decodeResponse(line)
+}
+
+// decodeResponse decodes a client response, where "*" cancels the
+// exchange and "=" is an empty response
+func decodeResponse(line string) ([]byte, error) {
+	switch line = strings.TrimSpace(line); line {
+	case "*":
+		return nil, errAuthCancelled
+	case "=":
+		return []byte{}, nil
+	}
+	response, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return nil, errInvalidResponse
+	}
+	return response, nil
+}
@@ -0,0 +1,489 @@
+package smtpd
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxLineLength bounds a command line, which RFC 5321 limits to 512
+	// bytes before extensions add parameters
+	maxLineLength = 4096
+
+	// maxErrors is how many rejected commands close a connection
+	maxErrors = 10
+)
+
+var (
+	errLineTooLong = errors.New("line too long")
+	errTooLarge    = errors.New("message exceeds the size limit")
+	errShutdown    = errors.New("server shutting down")
+)
+
+// conn serves an SMTP connection
+type conn struct {
+	server  *Server
+	netConn net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	session Session
+	ctx     context.Context
+	cancel  context.CancelFunc
+	errors  int
+
+	mu        sync.Mutex
+	closing   bool
+	inCommand bool
+}
+
+func newConn(server *Server, netConn net.Conn) *conn {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &conn{
+		server:  server,
+		ctx:     ctx,
+		cancel:  cancel,
+		session: Session{RemoteAddr: netConn.RemoteAddr()},
+	}
+	c.setConn(netConn)
+	return c
+}
+
+// setConn switches to a connection, such as after a TLS handshake
+func (c *conn) setConn(netConn net.Conn) {
+	c.netConn = netConn
+	c.reader = bufio.NewReaderSize(connReader{c}, maxLineLength)
+	c.writer = bufio.NewWriter(netConn)
+}
+
+// shutdown closes the connection once its current command is done
+func (c *conn) shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closing = true
+	if !c.inCommand {
+		c.netConn.SetReadDeadline(time.Now())
+	}
+}
+
+// close closes the connection right away
+func (c *conn) close() {
+	c.cancel()
+	c.netConn.Close()
+}
+
+// serve reads and handles commands until the client quits
+func (c *conn) serve() {
+	defer c.close()
+
+	c.reply(220, "", c.server.hostname()+" ESMTP ready")
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			if errors.Is(err, errLineTooLong) {
+				c.reply(500, "5.5.2", "Line too long")
+				continue
+			}
+			if c.isClosing() {
+				c.reply(421, "4.3.2", "Service shutting down")
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				c.reply(421, "4.4.2", "Idle timeout, closing connection")
+			}
+			return
+		}
+
+		if !c.begin() {
+			c.reply(421, "4.3.2", "Service shutting down")
+			return
+		}
+		quit := c.handle(line)
+		c.end()
+
+		if quit || c.errors >= maxErrors {
+			if !quit {
+				c.reply(421, "4.7.0", "Too many errors, closing connection")
+			}
+			return
+		}
+	}
+}
+
+// begin marks a command as running unless the server is shutting down
+func (c *conn) begin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inCommand = true
+	return !c.closing
+}
+
+// end marks the running command as done
+func (c *conn) end() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inCommand = false
+	if c.closing {
+		c.netConn.SetReadDeadline(time.Now())
+	}
+}
+
+func (c *conn) isClosing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closing
+}
+
+// handle runs a command and reports whether the client quit
+func (c *conn) handle(line string) bool {
+	verb, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToUpper(verb) {
+	case "HELO":
+		c.helo(arg, false)
+	case "EHLO":
+		c.helo(arg, true)
+	case "STARTTLS":
+		return c.startTLS()
+	case "AUTH":
+		return c.auth(arg)
+	case "MAIL":
+		c.mail(arg)
+	case "RCPT":
+		c.rcpt(arg)
+	case "DATA":
+		c.data()
+	case "RSET":
+		c.reset()
+		c.reply(250, "2.0.0", "OK")
+	case "NOOP":
+		c.reply(250, "2.0.0", "OK")
+	case "VRFY":
+		c.reply(252, "2.5.0", "Cannot verify the user, but will accept the message")
+	case "QUIT":
+		c.reply(221, "2.0.0", "Bye")
+		return true
+	default:
+		c.fail(500, "5.5.2", "Command not recognized")
+	}
+	return false
+}
+
+// helo greets the client and lists the extensions for EHLO
+func (c *conn) helo(name string, extended bool) {
+	if name == "" {
+		c.fail(501, "5.5.4", "Domain name required")
+		return
+	}
+	c.session.Helo = name
+	c.reset()
+
+	if !extended {
+		c.reply(250, "", c.server.hostname())
+		return
+	}
+	lines := []string{
+		c.server.hostname() + " greets " + name,
+		"PIPELINING",
+		"8BITMIME",
+		"SMTPUTF8",
+		"ENHANCEDSTATUSCODES",
+		"SIZE " + strconv.FormatInt(c.server.maxMessageSize(), 10),
+	}
+	if c.server.TLSConfig != nil && !c.session.TLS {
+		lines = append(lines, "STARTTLS")
+	}
+	if c.canAuth() {
+		lines = append(lines, "AUTH PLAIN LOGIN")
+	}
+	c.replyLines(250, lines)
+}
+
+// startTLS upgrades the connection and reports whether it must be closed
+func (c *conn) startTLS() bool {
+	switch {
+	case c.server.TLSConfig == nil:
+		c.fail(502, "5.5.1", "Command not implemented")
+		return false
+	case c.session.TLS:
+		c.fail(503, "5.5.1", "Already running TLS")
+		return false
+	case c.reader.Buffered() > 0:
+		// Commands sent ahead of the handshake would be trusted as if
+		// they had been encrypted
+		c.fail(503, "5.5.1", "Commands pipelined after STARTTLS")
+		return true
+	}
+
+	c.reply(220, "2.0.0", "Ready to start TLS")
+	tlsConn := tls.Server(c.netConn, c.server.TLSConfig)
+	ctx, cancel := context.WithTimeout(c.ctx, c.server.timeout())
+	defer cancel()
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		c.server.logf("SMTP TLS handshake with %s failed: %v", c.session.RemoteAddr, err)
+		return true
+	}
+
+	c.mu.Lock()
+	c.setConn(tlsConn)
+	c.mu.Unlock()
+
+	// The client starts over with EHLO
+	c.session = Session{RemoteAddr: c.session.RemoteAddr, TLS: true}
+	return false
+}
+
+// mail starts a transaction
+func (c *conn) mail(arg string) {
+	switch {
+	case c.session.Helo == "":
+		c.fail(503, "5.5.1", "Send HELO or EHLO first")
+		return
+	case c.server.RequireTLS && !c.session.TLS:
+		c.fail(530, "5.7.0", "Must issue a STARTTLS command first")
+		return
+	case c.server.RequireAuth && c.session.User == "":
+		c.fail(530, "5.7.0", "Authentication required")
+		return
+	case c.session.From != "" || len(c.session.To) > 0:
+		c.fail(503, "5.5.1", "Nested MAIL command")
+		return
+	}
+
+	from, params, err := parsePath(arg, "FROM:")
+	if err != nil {
+		c.fail(501, "5.5.4", err.Error())
+		return
+	}
+	if size, ok := params["SIZE"]; ok {
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			c.fail(501, "5.5.4", "Invalid SIZE parameter")
+			return
+		}
+		if n > c.server.maxMessageSize() {
+			c.fail(552, "5.3.4", "Message exceeds the size limit")
+			return
+		}
+	}
+
+	// The null sender is kept as "<>" until the recipients are known, so
+	// it can be told apart from no transaction
+	c.session.From = from
+	if from == "" {
+		c.session.From = "<>"
+	}
+	c.reply(250, "2.1.0", "OK")
+}
+
+// rcpt adds a recipient to the transaction
+func (c *conn) rcpt(arg string) {
+	if c.session.From == "" {
+		c.fail(503, "5.5.1", "Send MAIL first")
+		return
+	}
+	if len(c.session.To) >= c.server.maxRecipients() {
+		c.fail(452, "4.5.3", "Too many recipients")
+		return
+	}
+
+	to, _, err := parsePath(arg, "TO:")
+	if err != nil || to == "" {
+		c.fail(501, "5.5.4", "Invalid recipient address")
+		return
+	}
+	if err := c.server.Handler.Rcpt(c.ctx, c.envelope(), to); err != nil {
+		c.rejected(err)
+		return
+	}
+
+	c.session.To = append(c.session.To, to)
+	c.reply(250, "2.1.5", "OK")
+}
+
+// data receives the message of the transaction
+func (c *conn) data() {
+	if c.session.From == "" {
+		c.fail(503, "5.5.1", "Send MAIL first")
+		return
+	}
+	if len(c.session.To) == 0 {
+		c.fail(554, "5.5.1", "No valid recipients")
+		return
+	}
+	c.reply(354, "", "Start mail input; end with <CRLF>.<CRLF>")
+
+	body := &limitReader{r: textproto.NewReader(c.reader).DotReader(), n: c.server.maxMessageSize()}
+	err := c.server.Handler.Data(c.ctx, c.envelope(), body)
+
+	// Read up to the final dot whatever the handler left
+	if _, drainErr := io.Copy(io.Discard, body.r); drainErr != nil {
+		c.session.Helo = ""
+		return
+	}
+	defer c.reset()
+
+	switch {
+	case body.exceeded:
+		c.fail(552, "5.3.4", "Message exceeds the size limit")
+	case err != nil:
+		c.rejected(err)
+	default:
+		c.reply(250, "2.0.0", "OK: queued")
+	}
+}
+
+// envelope returns a copy of the session for handlers
+func (c *conn) envelope() *Session {
+	session := c.session
+	if session.From == "<>" {
+		session.From = ""
+	}
+	session.To = append([]string(nil), c.session.To...)
+	return &session
+}
+
+// reset drops the current transaction
+func (c *conn) reset() {
+	c.session.From = ""
+	c.session.To = nil
+}
+
+// rejected replies to a command a handler refused
+func (c *conn) rejected(err error) {
+	var smtpErr *Error
+	if errors.As(err, &smtpErr) {
+		c.fail(smtpErr.Code, smtpErr.EnhancedCode, smtpErr.Message)
+		return
+	}
+	c.server.logf("SMTP handler error for %s: %v", c.session.RemoteAddr, err)
+	c.fail(451, "4.3.0", "Temporary failure, try again later")
+}
+
+// fail replies with an error and counts it
+func (c *conn) fail(code int, enhanced, message string) {
+	c.errors++
+	c.reply(code, enhanced, message)
+}
+
+// reply writes a single line reply
+func (c *conn) reply(code int, enhanced, message string) {
+	if enhanced != "" {
+		message = enhanced + " " + message
+	}
+	c.replyLines(code, []string{message})
+}
+
+// replyLines writes a reply, continuing all lines but the last with a dash
+func (c *conn) replyLines(code int, lines []string) {
+	c.netConn.SetWriteDeadline(time.Now().Add(c.server.timeout()))
+	for i, line := range lines {
+		sep := " "
+		if i < len(lines)-1 {
+			sep = "-"
+		}
+		fmt.Fprintf(c.writer, "%d%s%s\r\n", code, sep, line)
+	}
+	c.writer.Flush()
+}
+
+// readLine reads a command line without its line ending
+func (c *conn) readLine() (string, error) {
+	line, err := c.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		// Skip the rest of the line
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = c.reader.ReadSlice('\n')
+		}
+		if err != nil {
+			return "", err
+		}
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// parsePath parses the "FROM:<address> PARAM=value" argument of MAIL and
+// RCPT
+func parsePath(arg, prefix string) (string, map[string]string, error) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", nil, fmt.Errorf("Syntax: %s<address>", prefix)
+	}
+	arg = strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(arg, "<") {
+		return "", nil, fmt.Errorf("Syntax: %s<address>", prefix)
+	}
+	end := strings.Index(arg, ">")
+	if end < 0 {
+		return "", nil, fmt.Errorf("Syntax: %s<address>", prefix)
+	}
+	address := arg[1:end]
+
+	// Drop a source route, such as <@relay.example.com:ada@example.com>
+	if strings.HasPrefix(address, "@") {
+		if i := strings.Index(address, ":"); i >= 0 {
+			address = address[i+1:]
+		}
+	}
+	if address != "" && (!strings.Contains(address, "@") || strings.ContainsAny(address, " \t<>")) {
+		return "", nil, fmt.Errorf("Invalid address %q", address)
+	}
+
+	params := make(map[string]string)
+	for _, param := range strings.Fields(arg[end+1:]) {
+		key, value, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = value
+	}
+	return address, params, nil
+}
+
+// limitReader fails once more than n bytes are read
+type limitReader struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, errTooLarge
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		l.exceeded = true
+		return 0, errTooLarge
+	}
+	return n, err
+}
+
+// connReader extends the read deadline of the connection before every
+// read, so a slow but steady message isn't cut off. Between commands it
+// stops reading once the server shuts down.
+type connReader struct {
+	c *conn
+}
+
+func (r connReader) Read(p []byte) (int, error) {
+	r.c.mu.Lock()
+	if r.c.closing && !r.c.inCommand {
+		r.c.mu.Unlock()
+		return 0, errShutdown
+	}
+	netConn := r.c.netConn
+	netConn.SetReadDeadline(time.Now().Add(r.c.server.timeout()))
+	r.c.mu.Unlock()
+
+	return netConn.Read(p)
+}
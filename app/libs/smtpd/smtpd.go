@@ -0,0 +1,296 @@
+// Package smtpd implements an SMTP server (RFC 5321) for receiving email.
+//
+// The server speaks ESMTP with the PIPELINING, 8BITMIME, SMTPUTF8, SIZE,
+// ENHANCEDSTATUSCODES and STARTTLS extensions and leaves deciding which
+// recipients to accept and what to do with a message to a Handler.
+// Handlers that implement Authenticator enable AUTH PLAIN and LOGIN
+// (RFC 4954) for message submission.
+package smtpd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+var (
+	ErrServerClosed = errors.New("smtpd: server closed")
+
+	// ErrAuthFailed is returned by authenticators for invalid credentials
+	ErrAuthFailed = &Error{Code: 535, EnhancedCode: "5.7.8", Message: "Authentication credentials invalid"}
+)
+
+// Defaults used for zero server settings
+const (
+	DefaultHostname       = "localhost"
+	DefaultMaxMessageSize = 10 << 20
+	DefaultMaxRecipients  = 100
+	DefaultTimeout        = 5 * time.Minute
+)
+
+// Session is the state of a connection that handlers see
+type Session struct {
+	RemoteAddr net.Addr
+
+	// Helo is the name the client introduced itself with
+	Helo string
+
+	// TLS tells whether the connection is encrypted
+	TLS bool
+
+	// User is the identity an Authenticator returned for AUTH, and
+	// Username the name the client authenticated as. Both are empty while
+	// the client hasn't authenticated.
+	User     string
+	Username string
+
+	// From and To are the envelope of the current message. From is empty
+	// for the null sender of bounces.
+	From string
+	To   []string
+}
+
+// Handler decides about recipients and receives messages. Returning an
+// *Error replies with its code, any other error with a temporary failure.
+type Handler interface {
+	// Rcpt checks a recipient before it is added to the envelope
+	Rcpt(ctx context.Context, session *Session, to string) error
+
+	// Data receives a message for the recipients of the envelope. The
+	// reader is undone from dot-stuffing and fails once the message grows
+	// beyond the size limit.
+	Data(ctx context.Context, session *Session, r io.Reader) error
+}
+
+// Authenticator is implemented by handlers that accept AUTH. Auth checks
+// the credentials of a client and returns the identity it is known as
+// from then on. Returning ErrAuthFailed rejects the credentials, any other
+// error that isn't an *Error replies with a temporary failure.
+type Authenticator interface {
+	Auth(ctx context.Context, session *Session, username, password string) (string, error)
+}
+
+// Error is an SMTP reply to a rejected command
+type Error struct {
+	Code         int
+	EnhancedCode string
+	Message      string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s %s", e.Code, e.EnhancedCode, e.Message)
+}
+
+// Temporary reports whether the client may try again later
+func (e *Error) Temporary() bool {
+	return e.Code >= 400 && e.Code < 500
+}
+
+// Server receives email over SMTP
+type Server struct {
+	// Hostname is announced in the greeting and EHLO reply
+	Hostname string
+
+	// TLSConfig enables STARTTLS. RequireTLS rejects messages on
+	// connections that didn't issue it.
+	TLSConfig  *tls.Config
+	RequireTLS bool
+
+	// RequireAuth rejects messages of clients that didn't authenticate.
+	// AUTH is only offered on encrypted connections unless
+	// AllowInsecureAuth is set.
+	RequireAuth       bool
+	AllowInsecureAuth bool
+
+	// MaxMessageSize bounds a message in bytes and MaxRecipients the
+	// recipients of its envelope
+	MaxMessageSize int64
+	MaxRecipients  int
+
+	// Timeout bounds waiting for a command or a chunk of message data
+	Timeout time.Duration
+
+	Handler Handler
+
+	// ErrorLog logs failed connections. The log package is used when nil.
+	ErrorLog *log.Logger
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[*conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// ListenAndServe listens on a TCP address and serves connections until
+// the server is shut down
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on a listener until the server is shut down,
+// when it returns ErrServerClosed
+func (s *Server) Serve(l net.Listener) error {
+	if !s.track(l) {
+		l.Close()
+		return ErrServerClosed
+	}
+	defer s.untrack(l)
+
+	for {
+		netConn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		c := newConn(s, netConn)
+		if !s.trackConn(c) {
+			netConn.Close()
+			return ErrServerClosed
+		}
+		go func() {
+			defer s.untrackConn(c)
+			c.serve()
+		}()
+	}
+}
+
+// Shutdown stops accepting connections and waits for the open ones to
+// finish their current command. Connections still open when ctx ends are
+// closed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for l := range s.listeners {
+		l.Close()
+	}
+	for c := range s.conns {
+		c.shutdown()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for c := range s.conns {
+			c.close()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) track(l net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.listeners == nil {
+		s.listeners = make(map[net.Listener]struct{})
+	}
+	s.listeners[l] = struct{}{}
+	return true
+}
+
+func (s *Server) untrack(l net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.listeners, l)
+}
+
+func (s *Server) trackConn(c *conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.conns == nil {
+		s.conns = make(map[*conn]struct{})
+	}
+	s.conns[c] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) untrackConn(c *conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, c)
+	s.wg.Done()
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// authenticator returns the handler when it accepts AUTH
+func (s *Server) authenticator() (Authenticator, bool) {
+	auth, ok := s.Handler.(Authenticator)
+	return auth, ok
+}
+
+func (s *Server) hostname() string {
+	if s.Hostname != "" {
+		return s.Hostname
+	}
+	return DefaultHostname
+}
+
+func (s *Server) maxMessageSize() int64 {
+	if s.MaxMessageSize > 0 {
+		return s.MaxMessageSize
+	}
+	return DefaultMaxMessageSize
+}
+
+func (s *Server) maxRecipients() int {
+	if s.MaxRecipients > 0 {
+		return s.MaxRecipients
+	}
+	return DefaultMaxRecipients
+}
+
+func (s *Server) timeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return DefaultTimeout
+}
+
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
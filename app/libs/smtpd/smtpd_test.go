@@ -0,0 +1,378 @@
+package smtpd
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder accepts recipients of example.com and keeps the messages
+type recorder struct {
+	mu       sync.Mutex
+	messages []received
+	err      error
+}
+
+type received struct {
+	session Session
+	body    string
+}
+
+func (r *recorder) Rcpt(_ context.Context, _ *Session, to string) error {
+	if !strings.HasSuffix(to, "@example.com") {
+		return &Error{Code: 550, EnhancedCode: "5.1.1", Message: "No such user"}
+	}
+	return nil
+}
+
+func (r *recorder) Data(_ context.Context, session *Session, body io.Reader) error {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, received{session: *session, body: string(content)})
+	return r.err
+}
+
+// start serves on a loopback port and returns its address
+func start(t *testing.T, server *Server) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(l)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	})
+	return l.Addr().String()
+}
+
+func TestServer_Receive(t *testing.T) {
+	handler := &recorder{}
+	addr := start(t, &Server{Hostname: "mx.example.com", Handler: handler})
+
+	client, err := smtp.Dial(addr)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Hello("client.example.org"))
+	ok, size := client.Extension("SIZE")
+	assert.True(t, ok)
+	assert.Equal(t, "10485760", size)
+	ok, _ = client.Extension("STARTTLS")
+	assert.False(t, ok, "STARTTLS is only offered with a TLS config")
+
+	require.NoError(t, client.Mail("ada@example.org"))
+	require.NoError(t, client.Rcpt("support@example.com"))
+	assertReply(t, client.Rcpt("eve@example.net"), 550, "5.1.1")
+	require.NoError(t, client.Rcpt("sales@example.com"))
+
+	w, err := client.Data()
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "Subject: Hi\r\n\r\n.leading dot\r\nbye\r\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, client.Quit())
+
+	require.Len(t, handler.messages, 1)
+	got := handler.messages[0]
+	assert.Equal(t, "client.example.org", got.session.Helo)
+	assert.Equal(t, "ada@example.org", got.session.From)
+	assert.Equal(t, []string{"support@example.com", "sales@example.com"}, got.session.To)
+	assert.False(t, got.session.TLS)
+	assert.Equal(t, "Subject: Hi\n\n.leading dot\nbye\n", got.body)
+}
+
+func TestServer_NullSender(t *testing.T) {
+	handler := &recorder{}
+	addr := start(t, &Server{Handler: handler})
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for _, step := range []struct{ send, want string }{
+		{"", "220 "},
+		{"HELO mx.example.org", "250 "},
+		{"DATA", "503 5.5.1"},
+		{"MAIL FROM:<>", "250 2.1.0"},
+		{"MAIL FROM:<>", "503 5.5.1"},
+		{"RCPT TO:<support@example.com>", "250 2.1.5"},
+		{"DATA", "354 "},
+		{"Subject: Bounce\r\n\r\nGone\r\n.", "250 2.0.0"},
+		{"QUIT", "221 "},
+	} {
+		if step.send != "" {
+			_, err := io.WriteString(conn, step.send+"\r\n")
+			require.NoError(t, err)
+		}
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(line, step.want), "%q: got %q", step.send, line)
+	}
+
+	require.Len(t, handler.messages, 1)
+	assert.Equal(t, "", handler.messages[0].session.From)
+}
+
+func TestServer_Limits(t *testing.T) {
+	handler := &recorder{}
+	addr := start(t, &Server{Handler: handler, MaxMessageSize: 64, MaxRecipients: 1})
+
+	client, err := smtp.Dial(addr)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Hello("client.example.org"))
+
+	require.NoError(t, client.Mail("ada@example.org"))
+	require.NoError(t, client.Rcpt("support@example.com"))
+	assertReply(t, client.Rcpt("sales@example.com"), 452, "4.5.3")
+
+	w, err := client.Data()
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "Subject: Large\r\n\r\n"+strings.Repeat("x", 100)+"\r\n")
+	require.NoError(t, err)
+	assertReply(t, w.Close(), 552, "5.3.4")
+
+	// The connection stays usable after a rejected message
+	require.NoError(t, client.Reset())
+	require.NoError(t, client.Noop())
+	assert.Empty(t, handler.messages)
+}
+
+func TestServer_HandlerError(t *testing.T) {
+	handler := &recorder{err: &Error{Code: 554, EnhancedCode: "5.7.1", Message: "Rejected"}}
+	addr := start(t, &Server{Handler: handler})
+
+	err := smtp.SendMail(addr, nil, "ada@example.org", []string{"support@example.com"}, []byte("Subject: Hi\r\n\r\nHi\r\n"))
+
+	assertReply(t, err, 554, "5.7.1")
+}
+
+func TestServer_StartTLS(t *testing.T) {
+	handler := &recorder{}
+	addr := start(t, &Server{
+		Handler:    handler,
+		TLSConfig:  &tls.Config{Certificates: []tls.Certificate{selfSigned(t)}},
+		RequireTLS: true,
+	})
+
+	client, err := smtp.Dial(addr)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Hello("client.example.org"))
+
+	ok, _ := client.Extension("STARTTLS")
+	assert.True(t, ok)
+	assertReply(t, client.Mail("ada@example.org"), 530, "5.7.0")
+
+	require.NoError(t, client.StartTLS(&tls.Config{InsecureSkipVerify: true}))
+	require.NoError(t, client.Mail("ada@example.org"))
+	require.NoError(t, client.Rcpt("support@example.com"))
+	w, err := client.Data()
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "Subject: Secret\r\n\r\nHi\r\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, client.Quit())
+
+	require.Len(t, handler.messages, 1)
+	assert.True(t, handler.messages[0].session.TLS)
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	server := &Server{Handler: &recorder{}}
+	addr := start(t, server)
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	_, err = reader.ReadString('\n')
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, "421 4.3.2"), line)
+}
+
+// authRecorder accepts the password "secret" of any user
+type authRecorder struct {
+	recorder
+}
+
+func (r *authRecorder) Auth(_ context.Context, _ *Session, username, password string) (string, error) {
+	if password != "secret" {
+		return "", ErrAuthFailed
+	}
+	return "user:" + username, nil
+}
+
+func TestServer_AuthPlain(t *testing.T) {
+	handler := &authRecorder{}
+	addr := start(t, &Server{Handler: handler, RequireAuth: true, AllowInsecureAuth: true})
+
+	client, err := smtp.Dial(addr)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Hello("client.example.org"))
+
+	ok, mechanisms := client.Extension("AUTH")
+	assert.True(t, ok)
+	assert.Equal(t, "PLAIN LOGIN", mechanisms)
+	assertReply(t, client.Mail("ada@example.org"), 530, "5.7.0")
+
+	require.NoError(t, client.Auth(smtp.PlainAuth("", "ada", "secret", "127.0.0.1")))
+	require.NoError(t, client.Mail("ada@example.org"))
+	require.NoError(t, client.Rcpt("support@example.com"))
+	w, err := client.Data()
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "Subject: Hi\r\n\r\nHi\r\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, client.Quit())
+
+	require.Len(t, handler.messages, 1)
+	assert.Equal(t, "user:ada", handler.messages[0].session.User)
+	assert.Equal(t, "ada", handler.messages[0].session.Username)
+
+	err = smtp.SendMail(addr, smtp.PlainAuth("", "ada", "wrong", "127.0.0.1"), "ada@example.org", []string{"support@example.com"}, []byte("Subject: Hi\r\n\r\nHi\r\n"))
+	assertReply(t, err, 535, "5.7.8")
+}
+
+func TestServer_AuthLogin(t *testing.T) {
+	addr := start(t, &Server{Handler: &authRecorder{}, AllowInsecureAuth: true})
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for _, step := range []struct{ send, want string }{
+		{"", "220 "},
+		{"AUTH LOGIN", "503 5.5.1"},
+		{"HELO client.example.org", "250 "},
+		{"AUTH CRAM-MD5", "504 5.5.4"},
+		{"AUTH LOGIN", "334 VXNlcm5hbWU6"},
+		{"*", "501 5.0.0"},
+		{"AUTH LOGIN YWRh", "334 UGFzc3dvcmQ6"},
+		{"not base64!", "501 5.5.2"},
+		{"AUTH LOGIN", "334 VXNlcm5hbWU6"},
+		{"YWRh", "334 UGFzc3dvcmQ6"},
+		{"c2VjcmV0", "235 2.7.0"},
+		{"AUTH PLAIN AGFkYQBzZWNyZXQ=", "503 5.5.1"},
+		{"QUIT", "221 "},
+	} {
+		if step.send != "" {
+			_, err := io.WriteString(conn, step.send+"\r\n")
+			require.NoError(t, err)
+		}
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(line, step.want), "%q: got %q", step.send, line)
+	}
+}
+
+func TestServer_AuthRequiresTLS(t *testing.T) {
+	addr := start(t, &Server{
+		Handler:   &authRecorder{},
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{selfSigned(t)}},
+	})
+
+	client, err := smtp.Dial(addr)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Hello("client.example.org"))
+
+	ok, _ := client.Extension("AUTH")
+	assert.False(t, ok, "AUTH is only offered after STARTTLS")
+
+	require.NoError(t, client.StartTLS(&tls.Config{InsecureSkipVerify: true}))
+	ok, _ = client.Extension("AUTH")
+	assert.True(t, ok)
+	require.NoError(t, client.Auth(smtp.PlainAuth("", "ada", "secret", "127.0.0.1")))
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		arg     string
+		address string
+		params  map[string]string
+		wantErr bool
+	}{
+		{arg: "FROM:<ada@example.org>", address: "ada@example.org", params: map[string]string{}},
+		{arg: "from: <ada@example.org> SIZE=1024 BODY=8BITMIME", address: "ada@example.org", params: map[string]string{"SIZE": "1024", "BODY": "8BITMIME"}},
+		{arg: "FROM:<>", address: "", params: map[string]string{}},
+		{arg: "FROM:<@relay.example.net:ada@example.org>", address: "ada@example.org", params: map[string]string{}},
+		{arg: "FROM:ada@example.org", wantErr: true},
+		{arg: "FROM:<ada>", wantErr: true},
+		{arg: "TO:<ada@example.org>", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			address, params, err := parsePath(tt.arg, "FROM:")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.address, address)
+			assert.Equal(t, tt.params, params)
+		})
+	}
+}
+
+// assertReply asserts that a command failed with a reply code and
+// enhanced status code
+func assertReply(t *testing.T, err error, code int, enhanced string) {
+	t.Helper()
+	var reply *textproto.Error
+	require.ErrorAs(t, err, &reply)
+	assert.Equal(t, code, reply.Code)
+	assert.True(t, strings.HasPrefix(reply.Msg, enhanced+" "), reply.Msg)
+}
+
+// selfSigned creates a certificate for localhost
+func selfSigned(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
@@ -0,0 +1,91 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"GoMail/app/config"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+)
+
+var (
+	ErrInvalidAttachment  = errors.New("invalid attachment")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentTooLarge = errors.New("attachment too large")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// sweepBatch is how many expired attachments are removed per query
+	sweepBatch = 100
+)
+
+// Service defines the interface for the reusable attachment store
+type Service interface {
+	// Upload stores a file once. Uploading content the user already stored
+	// returns the existing attachment.
+	Upload(ctx context.Context, userID string, req UploadRequest) (*AttachmentResponse, error)
+
+	// List returns the stored attachments of a user, newest first
+	List(ctx context.Context, userID string, page, limit int) (*ListAttachmentsResponse, error)
+
+	// Get returns the metadata of a stored attachment
+	Get(ctx context.Context, userID, id string) (*AttachmentResponse, error)
+
+	// Delete removes a stored attachment and its content
+	Delete(ctx context.Context, userID, id string) error
+
+	// Resolve returns stored attachments for sending. Their content is
+	// streamed from the store when the email is written.
+	Resolve(ctx context.Context, userID string, ids []string) ([]libSmtp.Attachment, error)
+
+	// DeleteExpired removes the attachments whose expiry time has passed
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	config *config.Config
+}
+
+// New creates a new attachment store service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		config: cfg,
+	}
+}
+
+// toResponse converts a stored attachment into its API representation
+func toResponse(attachment *models.StoredAttachment) *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:        attachment.ID.Hex(),
+		Filename:  attachment.Filename,
+		MimeType:  attachment.MimeType,
+		Size:      attachment.Size,
+		SHA256:    attachment.SHA256,
+		ExpiresAt: attachment.ExpiresAt,
+		CreatedAt: attachment.CreatedAt,
+	}
+}
+
+// expired reports whether an attachment is past its expiry time but not
+// swept yet
+func expired(attachment *models.StoredAttachment, now time.Time) bool {
+	return attachment.ExpiresAt != nil && !attachment.ExpiresAt.After(now)
+}
+
+// opener returns an Open function streaming a file from the store
+func (s *service) opener(ctx context.Context, attachment *models.StoredAttachment) func() (io.ReadCloser, error) {
+	fileID := attachment.FileID
+	return func() (io.ReadCloser, error) {
+		return s.repo.OpenAttachmentFile(ctx, fileID)
+	}
+}
@@ -0,0 +1,206 @@
+package attachment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	attachmentRepo "GoMail/app/repository/attachment"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const terms = "%PDF-1.7 terms of service"
+
+var termsHash = func() string {
+	sum := sha256.Sum256([]byte(terms))
+	return hex.EncodeToString(sum[:])
+}()
+
+// uploadOf returns an upload request streaming content
+func uploadOf(filename, content string) UploadRequest {
+	return UploadRequest{
+		Filename: filename,
+		Size:     int64(len(content)),
+		Open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(content)), nil
+		},
+	}
+}
+
+func newService(repo *repoMocks.Repository) *service {
+	return &service{repo: repo, config: &config.Config{Attachments: config.AttachmentConfig{MaxFileSize: 1024, StoreTTL: time.Hour}}}
+}
+
+func TestService_Upload(t *testing.T) {
+	fileID := primitive.NewObjectID()
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindAttachmentByHash", mock.Anything, "user-1", termsHash).Return(nil, attachmentRepo.ErrAttachmentNotFound)
+	repo.On("UploadAttachmentFile", mock.Anything, "terms.pdf", mock.MatchedBy(func(r io.Reader) bool {
+		data, err := io.ReadAll(r)
+		return err == nil && string(data) == terms
+	})).Return(fileID, nil)
+	repo.On("SaveAttachment", mock.Anything, mock.MatchedBy(func(att *models.StoredAttachment) bool {
+		return att.UserID == "user-1" && att.MimeType == "application/pdf" && att.Size == int64(len(terms)) &&
+			att.FileID == fileID && att.ExpiresAt != nil && time.Until(*att.ExpiresAt) > 59*time.Minute
+	})).Return(nil)
+
+	got, err := newService(repo).Upload(context.Background(), "user-1", uploadOf("../terms.pdf", terms))
+
+	require.NoError(t, err)
+	assert.Equal(t, "terms.pdf", got.Filename)
+	assert.Equal(t, termsHash, got.SHA256)
+	assert.False(t, got.Deduplicated)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Upload_Deduplicates(t *testing.T) {
+	soon := time.Now().Add(time.Minute)
+	existing := &models.StoredAttachment{ID: primitive.NewObjectID(), UserID: "user-1", Filename: "tos.pdf", SHA256: termsHash, ExpiresAt: &soon}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindAttachmentByHash", mock.Anything, "user-1", termsHash).Return(existing, nil)
+	repo.On("SetAttachmentExpiry", mock.Anything, existing.ID, mock.MatchedBy(func(at *time.Time) bool {
+		return at != nil && at.After(soon)
+	})).Return(nil)
+
+	got, err := newService(repo).Upload(context.Background(), "user-1", uploadOf("terms.pdf", terms))
+
+	require.NoError(t, err)
+	assert.True(t, got.Deduplicated)
+	assert.Equal(t, existing.ID.Hex(), got.ID)
+	assert.Equal(t, "tos.pdf", got.Filename)
+	repo.AssertNotCalled(t, "UploadAttachmentFile", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Upload_ConcurrentDuplicate(t *testing.T) {
+	fileID := primitive.NewObjectID()
+	existing := &models.StoredAttachment{ID: primitive.NewObjectID(), UserID: "user-1", SHA256: termsHash}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindAttachmentByHash", mock.Anything, "user-1", termsHash).Return(nil, attachmentRepo.ErrAttachmentNotFound).Once()
+	repo.On("FindAttachmentByHash", mock.Anything, "user-1", termsHash).Return(existing, nil).Once()
+	repo.On("UploadAttachmentFile", mock.Anything, "terms.pdf", mock.Anything).Return(fileID, nil)
+	repo.On("SaveAttachment", mock.Anything, mock.Anything).Return(attachmentRepo.ErrDuplicate)
+	repo.On("DeleteAttachmentFile", mock.Anything, fileID).Return(nil)
+
+	got, err := newService(repo).Upload(context.Background(), "user-1", uploadOf("terms.pdf", terms))
+
+	require.NoError(t, err)
+	assert.True(t, got.Deduplicated)
+	assert.Equal(t, existing.ID.Hex(), got.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Upload_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     UploadRequest
+		wantErr error
+	}{
+		{
+			name:    "declared size too large",
+			req:     UploadRequest{Filename: "a", Size: 2048, Open: uploadOf("a", "x").Open},
+			wantErr: ErrAttachmentTooLarge,
+		},
+		{
+			name:    "content larger than declared",
+			req:     UploadRequest{Filename: "a", Size: 1, Open: uploadOf("a", strings.Repeat("x", 1025)).Open},
+			wantErr: ErrAttachmentTooLarge,
+		},
+		{
+			name:    "unreadable",
+			req:     UploadRequest{Filename: "a", Open: func() (io.ReadCloser, error) { return nil, errors.New("gone") }},
+			wantErr: ErrInvalidAttachment,
+		},
+		{
+			name:    "negative ttl",
+			req:     UploadRequest{Filename: "a", Open: uploadOf("a", "x").Open, TTL: -time.Hour},
+			wantErr: ErrInvalidAttachment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repoMocks.Repository{}
+
+			_, err := newService(repo).Upload(context.Background(), "user-1", tt.req)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			repo.AssertNotCalled(t, "UploadAttachmentFile", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_Resolve(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	stored := &models.StoredAttachment{ID: primitive.NewObjectID(), UserID: "user-1", Filename: "terms.pdf", MimeType: "application/pdf", Size: int64(len(terms)), FileID: primitive.NewObjectID()}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindAttachmentByID", mock.Anything, "own").Return(stored, nil)
+	repo.On("FindAttachmentByID", mock.Anything, "foreign").Return(&models.StoredAttachment{UserID: "user-2"}, nil)
+	repo.On("FindAttachmentByID", mock.Anything, "expired").Return(&models.StoredAttachment{UserID: "user-1", ExpiresAt: &past}, nil)
+	repo.On("FindAttachmentByID", mock.Anything, "missing").Return(nil, attachmentRepo.ErrInvalidID)
+	repo.On("OpenAttachmentFile", mock.Anything, stored.FileID).Return(io.NopCloser(strings.NewReader(terms)), nil)
+
+	s := newService(repo)
+
+	got, err := s.Resolve(context.Background(), "user-1", []string{"own"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "terms.pdf", got[0].Filename)
+	assert.Equal(t, int64(len(terms)), got[0].Len())
+	content, err := got[0].Open()
+	require.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, terms, string(data))
+
+	for _, id := range []string{"foreign", "expired", "missing"} {
+		_, err := s.Resolve(context.Background(), "user-1", []string{"own", id})
+		assert.ErrorIs(t, err, ErrAttachmentNotFound, id)
+	}
+}
+
+func TestService_DeleteExpired(t *testing.T) {
+	now := time.Now()
+	expired := []*models.StoredAttachment{
+		{ID: primitive.NewObjectID(), FileID: primitive.NewObjectID()},
+		{ID: primitive.NewObjectID(), FileID: primitive.NewObjectID()},
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindExpiredAttachments", mock.Anything, now, sweepBatch).Return(expired, nil)
+	for _, att := range expired {
+		repo.On("DeleteAttachment", mock.Anything, att.ID).Return(nil)
+		repo.On("DeleteAttachmentFile", mock.Anything, att.FileID).Return(nil)
+	}
+
+	deleted, err := newService(repo).DeleteExpired(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Delete_OtherUser(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindAttachmentByID", mock.Anything, "id").Return(&models.StoredAttachment{UserID: "user-2"}, nil)
+
+	err := newService(repo).Delete(context.Background(), "user-1", "id")
+
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+	repo.AssertNotCalled(t, "DeleteAttachment", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,59 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	attachmentRepo "GoMail/app/repository/attachment"
+	"GoMail/app/repository/models"
+)
+
+// Delete removes a stored attachment and its content
+func (s *service) Delete(ctx context.Context, userID, id string) error {
+	attachment, err := s.find(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.remove(ctx, attachment)
+}
+
+// DeleteExpired removes the attachments whose expiry time has passed
+func (s *service) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	deleted := 0
+	for {
+		attachments, err := s.repo.FindExpiredAttachments(ctx, now, sweepBatch)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, attachment := range attachments {
+			if err := s.remove(ctx, attachment); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+
+		if len(attachments) < sweepBatch {
+			return deleted, nil
+		}
+	}
+}
+
+// remove deletes the metadata first, so a failure can at worst leave an
+// unreferenced file behind but never a reference to missing content
+func (s *service) remove(ctx context.Context, attachment *models.StoredAttachment) error {
+	if err := s.repo.DeleteAttachment(ctx, attachment.ID); err != nil {
+		if errors.Is(err, attachmentRepo.ErrAttachmentNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := s.repo.DeleteAttachmentFile(ctx, attachment.FileID); err != nil && !errors.Is(err, attachmentRepo.ErrAttachmentNotFound) {
+		log.Printf("Failed to delete attachment file %s: %v", attachment.FileID.Hex(), err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package attachment
+
+import (
+	"io"
+	"time"
+)
+
+// UploadRequest represents a file to store. Open is called for every pass
+// over the content, so it must return a fresh reader each time.
+type UploadRequest struct {
+	Filename string
+	MimeType string
+	Size     int64
+	Open     func() (io.ReadCloser, error)
+
+	// TTL is how long the attachment is kept, zero uses the configured
+	// default
+	TTL time.Duration
+}
+
+// AttachmentResponse represents the metadata of a stored attachment
+type AttachmentResponse struct {
+	ID           string     `json:"id"`
+	Filename     string     `json:"filename"`
+	MimeType     string     `json:"mimeType"`
+	Size         int64      `json:"size"`
+	SHA256       string     `json:"sha256"`
+	Deduplicated bool       `json:"deduplicated,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// ListAttachmentsResponse represents a page of stored attachments
+type ListAttachmentsResponse struct {
+	Attachments []AttachmentResponse `json:"attachments"`
+	Total       int64                `json:"total"`
+	Page        int                  `json:"page"`
+	Limit       int                  `json:"limit"`
+}
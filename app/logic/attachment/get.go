@@ -0,0 +1,92 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	libSmtp "GoMail/app/libs/smtp"
+	attachmentRepo "GoMail/app/repository/attachment"
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Get returns the metadata of a stored attachment
+func (s *service) Get(ctx context.Context, userID, id string) (*AttachmentResponse, error) {
+	attachment, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponse(attachment), nil
+}
+
+// List returns the stored attachments of a user, newest first
+func (s *service) List(ctx context.Context, userID string, page, limit int) (*ListAttachmentsResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	attachments, total, err := s.repo.FindAttachments(ctx, bson.M{"user_id": userID}, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListAttachmentsResponse{
+		Attachments: make([]AttachmentResponse, 0, len(attachments)),
+		Total:       total,
+		Page:        page,
+		Limit:       limit,
+	}
+	for _, attachment := range attachments {
+		resp.Attachments = append(resp.Attachments, *toResponse(attachment))
+	}
+
+	return resp, nil
+}
+
+// Resolve returns stored attachments for sending. Attachments of other users
+// and expired ones are reported as not found.
+func (s *service) Resolve(ctx context.Context, userID string, ids []string) ([]libSmtp.Attachment, error) {
+	attachments := make([]libSmtp.Attachment, 0, len(ids))
+	for _, id := range ids {
+		attachment, err := s.find(ctx, userID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, libSmtp.Attachment{
+			Filename: attachment.Filename,
+			MimeType: attachment.MimeType,
+			Size:     attachment.Size,
+			Open:     s.opener(ctx, attachment),
+		})
+	}
+
+	return attachments, nil
+}
+
+// find retrieves a user's attachment that hasn't expired
+func (s *service) find(ctx context.Context, userID, id string) (*models.StoredAttachment, error) {
+	attachment, err := s.repo.FindAttachmentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, attachmentRepo.ErrAttachmentNotFound) || errors.Is(err, attachmentRepo.ErrInvalidID) {
+			return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+		}
+		return nil, err
+	}
+
+	if attachment.UserID != userID || expired(attachment, time.Now()) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,199 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	attachment "GoMail/app/logic/attachment"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	smtp "GoMail/app/libs/smtp"
+
+	time "time"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Delete(ctx context.Context, userID string, id string) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: ctx, now
+func (_m *Service) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Get(ctx context.Context, userID string, id string) (*attachment.AttachmentResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *attachment.AttachmentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*attachment.AttachmentResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *attachment.AttachmentResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*attachment.AttachmentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID, page, limit
+func (_m *Service) List(ctx context.Context, userID string, page int, limit int) (*attachment.ListAttachmentsResponse, error) {
+	ret := _m.Called(ctx, userID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *attachment.ListAttachmentsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*attachment.ListAttachmentsResponse, error)); ok {
+		return rf(ctx, userID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *attachment.ListAttachmentsResponse); ok {
+		r0 = rf(ctx, userID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*attachment.ListAttachmentsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, userID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resolve provides a mock function with given fields: ctx, userID, ids
+func (_m *Service) Resolve(ctx context.Context, userID string, ids []string) ([]smtp.Attachment, error) {
+	ret := _m.Called(ctx, userID, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 []smtp.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) ([]smtp.Attachment, error)); ok {
+		return rf(ctx, userID, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []smtp.Attachment); ok {
+		r0 = rf(ctx, userID, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]smtp.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, userID, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upload provides a mock function with given fields: ctx, userID, req
+func (_m *Service) Upload(ctx context.Context, userID string, req attachment.UploadRequest) (*attachment.AttachmentResponse, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upload")
+	}
+
+	var r0 *attachment.AttachmentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, attachment.UploadRequest) (*attachment.AttachmentResponse, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, attachment.UploadRequest) *attachment.AttachmentResponse); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*attachment.AttachmentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, attachment.UploadRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
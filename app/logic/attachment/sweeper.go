@@ -0,0 +1,72 @@
+package attachment
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// sweepInterval is how often expired attachments are removed
+const sweepInterval = 10 * time.Minute
+
+// Sweeper periodically removes expired attachments. Running it on several
+// instances is safe, an attachment is only removed once.
+type Sweeper struct {
+	service  Service
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewSweeper creates a new expired attachment sweeper
+func NewSweeper(service Service) *Sweeper {
+	return &Sweeper{
+		service:  service,
+		interval: sweepInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start launches the sweeper loop
+func (s *Sweeper) Start() {
+	go s.run()
+}
+
+// Stop signals the sweeper loop to exit and waits for it
+func (s *Sweeper) Stop(ctx context.Context) error {
+	close(s.stop)
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run removes expired attachments on every tick until the sweeper is stopped
+func (s *Sweeper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		deleted, err := s.service.DeleteExpired(ctx, time.Now())
+		cancel()
+
+		if err != nil {
+			log.Printf("Attachment sweeper error: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Attachment sweeper removed %d expired attachments", deleted)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package attachment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	attachmentRepo "GoMail/app/repository/attachment"
+	"GoMail/app/repository/models"
+)
+
+// Upload stores a file once. The content is hashed in a first pass, so a
+// duplicate is never written to GridFS; the expiry of an existing
+// attachment is extended to the one of the new upload.
+func (s *service) Upload(ctx context.Context, userID string, req UploadRequest) (*AttachmentResponse, error) {
+	if req.Open == nil {
+		return nil, fmt.Errorf("%w: missing content", ErrInvalidAttachment)
+	}
+	if req.TTL < 0 {
+		return nil, fmt.Errorf("%w: negative ttl", ErrInvalidAttachment)
+	}
+	limit := s.config.Attachments.MaxFileSize
+	if limit > 0 && req.Size > limit {
+		return nil, fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrAttachmentTooLarge, req.Filename, req.Size, limit)
+	}
+
+	hash, size, head, err := digest(req.Open, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var expiresAt *time.Time
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = s.config.Attachments.StoreTTL
+	}
+	if ttl > 0 {
+		at := now.Add(ttl)
+		expiresAt = &at
+	}
+
+	existing, err := s.repo.FindAttachmentByHash(ctx, userID, hash)
+	if err == nil {
+		return s.reuse(ctx, existing, expiresAt)
+	}
+	if !errors.Is(err, attachmentRepo.ErrAttachmentNotFound) {
+		return nil, err
+	}
+
+	attachment := &models.StoredAttachment{
+		UserID:    userID,
+		Filename:  filename(req.Filename),
+		MimeType:  req.MimeType,
+		Size:      size,
+		SHA256:    hash,
+		ExpiresAt: expiresAt,
+	}
+	if attachment.MimeType == "" {
+		attachment.MimeType = sniff(head, attachment.Filename)
+	}
+
+	content, err := req.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+	}
+	defer content.Close()
+
+	attachment.FileID, err = s.repo.UploadAttachmentFile(ctx, attachment.Filename, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveAttachment(ctx, attachment); err != nil {
+		// Another request stored the same content first, keep its copy
+		if cleanupErr := s.repo.DeleteAttachmentFile(ctx, attachment.FileID); cleanupErr != nil {
+			log.Printf("Failed to delete attachment file %s: %v", attachment.FileID.Hex(), cleanupErr)
+		}
+		if errors.Is(err, attachmentRepo.ErrDuplicate) {
+			existing, findErr := s.repo.FindAttachmentByHash(ctx, userID, hash)
+			if findErr != nil {
+				return nil, findErr
+			}
+			return s.reuse(ctx, existing, expiresAt)
+		}
+		return nil, err
+	}
+
+	return toResponse(attachment), nil
+}
+
+// reuse returns an existing attachment for a duplicate upload, extending its
+// expiry when the upload asked to keep it longer
+func (s *service) reuse(ctx context.Context, existing *models.StoredAttachment, expiresAt *time.Time) (*AttachmentResponse, error) {
+	if existing.ExpiresAt != nil && (expiresAt == nil || expiresAt.After(*existing.ExpiresAt)) {
+		if err := s.repo.SetAttachmentExpiry(ctx, existing.ID, expiresAt); err != nil {
+			return nil, err
+		}
+		existing.ExpiresAt = expiresAt
+	}
+
+	resp := toResponse(existing)
+	resp.Deduplicated = true
+	return resp, nil
+}
+
+// digest reads the content once, returning its SHA-256, its size and the
+// first 512 bytes for content type sniffing. It stops once the content
+// exceeds limit, a zero limit reads everything.
+func digest(open func() (io.ReadCloser, error), limit int64) (string, int64, []byte, error) {
+	content, err := open()
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+	}
+	defer content.Close()
+
+	var reader io.Reader = content
+	if limit > 0 {
+		reader = io.LimitReader(content, limit+1)
+	}
+
+	hash := sha256.New()
+	head := &prefixWriter{limit: 512}
+	size, err := io.Copy(io.MultiWriter(hash, head), reader)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+	}
+	if limit > 0 && size > limit {
+		return "", 0, nil, fmt.Errorf("%w: the limit is %d bytes", ErrAttachmentTooLarge, limit)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, head.data, nil
+}
+
+// prefixWriter keeps the first limit bytes written to it
+type prefixWriter struct {
+	data  []byte
+	limit int
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	if room := p.limit - len(p.data); room > 0 {
+		if len(b) < room {
+			room = len(b)
+		}
+		p.data = append(p.data, b[:room]...)
+	}
+	return len(b), nil
+}
+
+// sniff detects a MIME type from the start of the content, falling back to
+// the file extension for binary data
+func sniff(head []byte, name string) string {
+	mimeType := http.DetectContentType(head)
+	if mimeType == "application/octet-stream" {
+		if byExtension := mime.TypeByExtension(path.Ext(name)); byExtension != "" {
+			mimeType = byExtension
+		}
+	}
+	return mimeType
+}
+
+// filename keeps the base name of an uploaded file
+func filename(name string) string {
+	name = path.Base(name)
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+	return name
+}
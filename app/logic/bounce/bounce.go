@@ -0,0 +1,47 @@
+package bounce
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+)
+
+var (
+	ErrInvalidReport = errors.New("invalid bounce or complaint report")
+)
+
+// Service defines the interface for processing bounces and complaints.
+// Reports are matched to the sent email by its Message-ID or envelope ID,
+// which every send sets.
+type Service interface {
+	// Process parses a DSN or ARF report from a whole message and records
+	// its failed recipients and complaints on the matching email log. Hard
+	// bounces suppress the recipient for all users.
+	Process(ctx context.Context, r io.Reader) (*ProcessResponse, error)
+}
+
+// eventPublisher notifies webhooks of the bounces of a user's emails
+type eventPublisher interface {
+	Publish(ctx context.Context, userID string, eventType models.WebhookEventType, data webhook.EventData) error
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	events eventPublisher
+	config *config.Config
+}
+
+// New creates a new bounce service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		events: webhook.New(repo, cfg),
+		config: cfg,
+	}
+}
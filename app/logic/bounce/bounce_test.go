@@ -0,0 +1,221 @@
+package bounce
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/repository/emaillog"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const dsn = `From: MAILER-DAEMON@mx.example.net
+To: sender@example.com
+Subject: Undelivered Mail Returned to Sender
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.example.net
+Original-Envelope-Id: 0123abcd@example.com
+
+Final-Recipient: rfc822; ada@example.org
+Action: failed
+Status: 5.1.1
+Diagnostic-Code: smtp; 550 5.1.1 User unknown
+
+Final-Recipient: rfc822; bob@example.org
+Action: delayed
+Status: 4.2.2
+
+Final-Recipient: rfc822; eve@example.org
+Action: failed
+Status: 5.1.1
+
+--BOUNDARY--
+`
+
+const arf = `From: feedback@isp.example.net
+To: abuse@example.com
+Subject: Abuse report
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=feedback-report; boundary="BOUNDARY"
+
+--BOUNDARY
+Content-Type: message/feedback-report
+
+Feedback-Type: abuse
+User-Agent: ISP-FBL/1.0
+Version: 1
+
+--BOUNDARY
+Content-Type: text/rfc822-headers
+
+From: sender@example.com
+To: redacted@example.org
+Message-ID: <4567cdef@example.com>
+
+--BOUNDARY--
+`
+
+func crlf(message string) string {
+	return strings.ReplaceAll(message, "\n", "\r\n")
+}
+
+func newService(repo *repoMocks.Repository) *service {
+	return New(repo, &config.Config{Bounce: config.BounceConfig{MaxReportSize: 1 << 20}}).(*service)
+}
+
+func TestService_Process_Bounce(t *testing.T) {
+	logID := primitive.NewObjectID()
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindEmailLogByMessageID", mock.Anything, "0123abcd@example.com").Return(&models.EmailLog{
+		ID:     logID,
+		UserID: "user-1",
+		To:     "Ada <Ada@example.org>, bob@example.org",
+	}, nil)
+	repo.On("AddEmailLogFeedback", mock.Anything, logID, models.EmailLogStatusBounced, mock.MatchedBy(func(f models.DeliveryFeedback) bool {
+		return f.Type == models.FeedbackTypeBounce && f.Recipient == "Ada@example.org" && f.Status == "5.1.1" && f.Hard
+	})).Return(nil).Once()
+	repo.On("UpsertSuppression", mock.Anything, mock.MatchedBy(func(s *models.Suppression) bool {
+		return s.Scope == models.SuppressionScopeGlobal && s.UserID == "" && s.Value == "ada@example.org" &&
+			s.Reason == models.SuppressionReasonHardBounce
+	})).Return(true, nil).Once()
+	repo.On("FindSubscribedWebhooks", mock.Anything, "user-1", models.WebhookEventBounced).Return([]*models.Webhook{{ID: primitive.NewObjectID()}}, nil).Once()
+	repo.On("SaveWebhookDelivery", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.EventType == models.WebhookEventBounced && strings.Contains(d.Payload, `"recipient":"Ada@example.org"`)
+	})).Return(nil).Once()
+
+	got, err := newService(repo).Process(context.Background(), strings.NewReader(crlf(dsn)))
+
+	require.NoError(t, err)
+	assert.True(t, got.Matched)
+	assert.Equal(t, "0123abcd@example.com", got.MessageID)
+	require.Len(t, got.Events, 3)
+	assert.Equal(t, EventResponse{
+		Recipient: "Ada@example.org", Type: models.FeedbackTypeBounce, Action: "failed", Status: "5.1.1",
+		Hard: true, Recorded: true, Suppressed: true,
+	}, got.Events[0])
+	assert.False(t, got.Events[1].Recorded, "delays are not recorded")
+	assert.False(t, got.Events[2].Recorded, "recipients the email wasn't sent to are not trusted")
+	repo.AssertExpectations(t)
+}
+
+func TestService_Process_Complaint(t *testing.T) {
+	logID := primitive.NewObjectID()
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindEmailLogByMessageID", mock.Anything, "4567cdef@example.com").Return(&models.EmailLog{
+		ID: logID,
+		To: "grace@example.org",
+	}, nil)
+	repo.On("AddEmailLogFeedback", mock.Anything, logID, models.EmailLogStatusComplained, mock.MatchedBy(func(f models.DeliveryFeedback) bool {
+		return f.Type == models.FeedbackTypeComplaint && f.Recipient == "grace@example.org" && f.Category == "abuse"
+	})).Return(nil).Once()
+
+	// The report names the redacted address of its headers, which the
+	// email wasn't sent to
+	got, err := newService(repo).Process(context.Background(), strings.NewReader(crlf(arf)))
+
+	require.NoError(t, err)
+	require.Len(t, got.Events, 1)
+	assert.False(t, got.Events[0].Recorded)
+
+	// Without any recipient the single one of the email complained
+	redacted := strings.Replace(arf, "To: redacted@example.org\n", "", 1)
+	got, err = newService(repo).Process(context.Background(), strings.NewReader(crlf(redacted)))
+
+	require.NoError(t, err)
+	require.Len(t, got.Events, 1)
+	assert.True(t, got.Events[0].Recorded)
+	assert.False(t, got.Events[0].Suppressed)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Process_Unmatched(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindEmailLogByMessageID", mock.Anything, mock.Anything).Return(nil, emaillog.ErrEmailLogNotFound)
+
+	got, err := newService(repo).Process(context.Background(), strings.NewReader(crlf(dsn)))
+
+	require.NoError(t, err)
+	assert.False(t, got.Matched)
+	for _, event := range got.Events {
+		assert.False(t, event.Recorded)
+	}
+	repo.AssertNotCalled(t, "AddEmailLogFeedback", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpsertSuppression", mock.Anything, mock.Anything)
+}
+
+func TestService_Process_Invalid(t *testing.T) {
+	_, err := newService(&repoMocks.Repository{}).Process(context.Background(),
+		strings.NewReader(crlf("From: ada@example.org\nContent-Type: text/plain\n\nHello\n")))
+
+	assert.ErrorIs(t, err, ErrInvalidReport)
+}
+
+func TestService_Record(t *testing.T) {
+	logID := primitive.NewObjectID()
+	bounce := models.DeliveryFeedback{Type: models.FeedbackTypeBounce, Recipient: "ada@example.org", Action: "failed", Status: "5.1.1"}
+
+	repo := &repoMocks.Repository{}
+	repo.On("AddEmailLogFeedback", mock.Anything, logID, models.EmailLogStatusComplained, bounce).Return(nil).Once()
+
+	emailLog := &models.EmailLog{ID: logID, Status: models.EmailLogStatusComplained}
+	s := newService(repo)
+
+	// A complaint stays a complaint and the same report is recorded once
+	require.NoError(t, s.record(context.Background(), emailLog, bounce))
+	require.NoError(t, s.record(context.Background(), emailLog, bounce))
+
+	assert.Len(t, emailLog.Feedback, 1)
+	repo.AssertExpectations(t)
+}
+
+// processFunc is a Service processing reports with a function
+type processFunc func(r io.Reader) (*ProcessResponse, error)
+
+func (f processFunc) Process(_ context.Context, r io.Reader) (*ProcessResponse, error) {
+	return f(r)
+}
+
+func TestMailbox_Poll(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.eml"), []byte("report"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spam.eml"), []byte("spam"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "retry.eml"), []byte("retry"), 0o600))
+
+	service := processFunc(func(r io.Reader) (*ProcessResponse, error) {
+		content, _ := io.ReadAll(r)
+		switch string(content) {
+		case "spam":
+			return nil, ErrInvalidReport
+		case "retry":
+			return nil, context.DeadlineExceeded
+		}
+		return &ProcessResponse{}, nil
+	})
+
+	mailbox := NewMailbox(&config.Config{Bounce: config.BounceConfig{MailboxDir: dir, PollInterval: time.Minute}}, service)
+	processed, err := mailbox.poll()
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.FileExists(t, filepath.Join(dir, processedDir, "report.eml"))
+	assert.FileExists(t, filepath.Join(dir, failedDir, "spam.eml"))
+	assert.FileExists(t, filepath.Join(dir, "retry.eml"))
+}
@@ -0,0 +1,28 @@
+package bounce
+
+import (
+	bounceLib "GoMail/app/libs/bounce"
+	"GoMail/app/repository/models"
+)
+
+// ProcessResponse represents the outcome of processing a report. Matched
+// tells whether it belonged to an email sent by this service.
+type ProcessResponse struct {
+	Type      bounceLib.ReportType `json:"type"`
+	MessageID string               `json:"messageId,omitempty"`
+	Matched   bool                 `json:"matched"`
+	Events    []EventResponse      `json:"events"`
+}
+
+// EventResponse represents the report about one recipient. Only failed
+// deliveries and complaints of recipients of the matched email are
+// recorded.
+type EventResponse struct {
+	Recipient  string              `json:"recipient"`
+	Type       models.FeedbackType `json:"type"`
+	Action     string              `json:"action,omitempty"`
+	Status     string              `json:"status,omitempty"`
+	Hard       bool                `json:"hard"`
+	Recorded   bool                `json:"recorded"`
+	Suppressed bool                `json:"suppressed"`
+}
@@ -0,0 +1,142 @@
+package bounce
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"GoMail/app/config"
+)
+
+const (
+	// processedDir and failedDir are the subdirectories of the mailbox that
+	// reports are moved to, so each is only processed once
+	processedDir = "processed"
+	failedDir    = "failed"
+)
+
+// Mailbox periodically processes the reports delivered to a directory,
+// one message per file. Processed reports are moved to its processed
+// directory and messages that aren't reports to its failed directory.
+// Files that fail for other reasons are retried on the next poll.
+type Mailbox struct {
+	service  Service
+	dir      string
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewMailbox creates a new poller of the configured mailbox directory
+func NewMailbox(cfg *config.Config, service Service) *Mailbox {
+	return &Mailbox{
+		service:  service,
+		dir:      cfg.Bounce.MailboxDir,
+		interval: cfg.Bounce.PollInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start launches the mailbox loop
+func (m *Mailbox) Start() {
+	go m.run()
+}
+
+// Stop signals the mailbox loop to exit and waits for it
+func (m *Mailbox) Stop(ctx context.Context) error {
+	close(m.stop)
+
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run polls the mailbox on every tick until it is stopped
+func (m *Mailbox) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := m.poll()
+		if err != nil {
+			log.Printf("Bounce mailbox error: %v", err)
+		} else if processed > 0 {
+			log.Printf("Bounce mailbox processed %d reports", processed)
+		}
+
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll processes the files in the mailbox and returns how many were
+// reports
+func (m *Mailbox) poll() (int, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		select {
+		case <-m.stop:
+			return processed, nil
+		default:
+		}
+
+		path := filepath.Join(m.dir, entry.Name())
+		err := m.processFile(path)
+		switch {
+		case err == nil:
+			processed++
+			err = m.move(path, processedDir)
+		case errors.Is(err, ErrInvalidReport):
+			log.Printf("Bounce mailbox skipped %s: %v", entry.Name(), err)
+			err = m.move(path, failedDir)
+		}
+		if err != nil {
+			log.Printf("Bounce mailbox error processing %s: %v", entry.Name(), err)
+		}
+	}
+	return processed, nil
+}
+
+// processFile processes the report in a file
+func (m *Mailbox) processFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err = m.service.Process(ctx, file)
+	return err
+}
+
+// move moves a file into a subdirectory of the mailbox
+func (m *Mailbox) move(path, subdir string) error {
+	dir := filepath.Join(m.dir, subdir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	return os.Rename(path, filepath.Join(dir, filepath.Base(path)))
+}
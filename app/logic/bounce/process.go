@@ -0,0 +1,218 @@
+package bounce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	bounceLib "GoMail/app/libs/bounce"
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository/emaillog"
+	"GoMail/app/repository/models"
+)
+
+// Process parses a report and records it on the email it is about
+func (s *service) Process(ctx context.Context, r io.Reader) (*ProcessResponse, error) {
+	report, err := bounceLib.Parse(io.LimitReader(r, s.config.Bounce.MaxReportSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+
+	response := &ProcessResponse{
+		Type:      report.Type,
+		MessageID: report.MessageID,
+		Events:    []EventResponse{},
+	}
+	if response.MessageID == "" {
+		response.MessageID = report.EnvelopeID
+	}
+
+	emailLog, err := s.findLog(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+	response.Matched = emailLog != nil
+
+	recipients := report.Recipients
+	if len(recipients) == 0 && emailLog != nil {
+		// Some feedback reports redact the recipient. A log of a single
+		// recipient still tells who complained.
+		if sent := addresses(emailLog.To); len(sent) == 1 && report.Type == bounceLib.ReportTypeARF {
+			recipients = []bounceLib.Recipient{{Address: sent[0]}}
+		}
+	}
+
+	now := time.Now()
+	for _, recipient := range recipients {
+		event, feedback := toEvent(report, recipient, now)
+		if feedback != nil && emailLog != nil {
+			address, ok := sentTo(emailLog, recipient)
+			if ok {
+				feedback.Recipient = address
+				event.Recipient = address
+				if err := s.record(ctx, emailLog, *feedback); err != nil {
+					return nil, err
+				}
+				event.Recorded = true
+
+				if event.Hard {
+					if event.Suppressed, err = s.suppress(ctx, address, recipient); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		response.Events = append(response.Events, event)
+	}
+
+	return response, nil
+}
+
+// findLog returns the log of the email a report is about, or nil when it
+// wasn't sent by this service
+func (s *service) findLog(ctx context.Context, report *bounceLib.Report) (*models.EmailLog, error) {
+	for _, id := range []string{report.MessageID, report.EnvelopeID} {
+		if id == "" {
+			continue
+		}
+		emailLog, err := s.repo.FindEmailLogByMessageID(ctx, id)
+		if err == nil {
+			return emailLog, nil
+		}
+		if !errors.Is(err, emaillog.ErrEmailLogNotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// record adds feedback to an email log unless the same report was already
+// recorded. A complaint isn't turned back into a bounce.
+func (s *service) record(ctx context.Context, emailLog *models.EmailLog, feedback models.DeliveryFeedback) error {
+	for _, recorded := range emailLog.Feedback {
+		if recorded.Type == feedback.Type && recorded.Action == feedback.Action &&
+			recorded.Status == feedback.Status && strings.EqualFold(recorded.Recipient, feedback.Recipient) {
+			return nil
+		}
+	}
+
+	status := models.EmailLogStatusBounced
+	if feedback.Type == models.FeedbackTypeComplaint || emailLog.Status == models.EmailLogStatusComplained {
+		status = models.EmailLogStatusComplained
+	}
+	if err := s.repo.AddEmailLogFeedback(ctx, emailLog.ID, status, feedback); err != nil {
+		return err
+	}
+
+	emailLog.Status = status
+	emailLog.Feedback = append(emailLog.Feedback, feedback)
+
+	if feedback.Type == models.FeedbackTypeBounce {
+		s.publishBounce(ctx, emailLog, feedback)
+	}
+	return nil
+}
+
+// publishBounce notifies the webhooks of the sender of a recorded bounce.
+// The report is still recorded when they can't be notified.
+func (s *service) publishBounce(ctx context.Context, emailLog *models.EmailLog, feedback models.DeliveryFeedback) {
+	err := s.events.Publish(ctx, emailLog.UserID, models.WebhookEventBounced, webhook.EventData{
+		MessageID:  emailLog.MessageID,
+		From:       emailLog.From,
+		To:         emailLog.To,
+		Subject:    emailLog.Subject,
+		Recipient:  feedback.Recipient,
+		Status:     feedback.Status,
+		Diagnostic: feedback.Diagnostic,
+		Hard:       feedback.Hard,
+		OccurredAt: feedback.ReceivedAt,
+	})
+	if err != nil {
+		log.Printf("Failed to publish bounced event of %s: %v", emailLog.MessageID, err)
+	}
+}
+
+// suppress stops all emails to a recipient whose address doesn't exist.
+// The suppression is global as the address fails for every sender.
+func (s *service) suppress(ctx context.Context, address string, recipient bounceLib.Recipient) (bool, error) {
+	note := "Bounced with " + recipient.Status
+	if recipient.DiagnosticCode != "" {
+		note += ": " + recipient.DiagnosticCode
+	}
+	_, err := s.repo.UpsertSuppression(ctx, &models.Suppression{
+		Scope:  models.SuppressionScopeGlobal,
+		Type:   models.SuppressionTypeAddress,
+		Value:  strings.ToLower(address),
+		Reason: models.SuppressionReasonHardBounce,
+		Note:   note,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// toEvent describes the report about a recipient. The feedback to record
+// is nil for DSN actions other than failed.
+func toEvent(report *bounceLib.Report, recipient bounceLib.Recipient, now time.Time) (EventResponse, *models.DeliveryFeedback) {
+	event := EventResponse{
+		Recipient: recipient.Address,
+		Type:      models.FeedbackTypeBounce,
+		Action:    recipient.Action,
+		Status:    recipient.Status,
+	}
+	if report.Type == bounceLib.ReportTypeARF {
+		event.Type = models.FeedbackTypeComplaint
+	} else if !recipient.Failed() {
+		return event, nil
+	}
+	event.Hard = recipient.Hard()
+
+	return event, &models.DeliveryFeedback{
+		Type:       event.Type,
+		Recipient:  recipient.Address,
+		Action:     recipient.Action,
+		Status:     recipient.Status,
+		Diagnostic: recipient.DiagnosticCode,
+		Category:   report.FeedbackType,
+		Hard:       event.Hard,
+		ReceivedAt: now,
+	}
+}
+
+// sentTo returns the address of the email log that a reported recipient
+// is, by its final or original address. Reports about other addresses
+// are not trusted.
+func sentTo(emailLog *models.EmailLog, recipient bounceLib.Recipient) (string, bool) {
+	for _, address := range addresses(emailLog.To) {
+		if strings.EqualFold(address, recipient.Address) || strings.EqualFold(address, recipient.OriginalAddress) {
+			return address, true
+		}
+	}
+	return "", false
+}
+
+// addresses returns the addresses of a list of recipients, which may have
+// display names
+func addresses(to string) []string {
+	if list, err := mail.ParseAddressList(to); err == nil {
+		result := make([]string, 0, len(list))
+		for _, addr := range list {
+			result = append(result, addr.Address)
+		}
+		return result
+	}
+
+	var result []string
+	for _, address := range strings.Split(to, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			result = append(result, address)
+		}
+	}
+	return result
+}
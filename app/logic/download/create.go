@@ -0,0 +1,58 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	attachmentRepo "GoMail/app/repository/attachment"
+	"GoMail/app/repository/models"
+)
+
+// Create issues a signed, expiring link to a stored attachment of the user
+func (s *service) Create(ctx context.Context, userID string, req CreateRequest) (*LinkResponse, error) {
+	if req.TTL < 0 {
+		return nil, fmt.Errorf("%w: negative ttl", ErrInvalidLink)
+	}
+
+	attachment, err := s.repo.FindAttachmentByID(ctx, req.AttachmentID)
+	if err != nil {
+		if errors.Is(err, attachmentRepo.ErrAttachmentNotFound) || errors.Is(err, attachmentRepo.ErrInvalidID) {
+			return nil, fmt.Errorf("%w: attachment %s not found", ErrInvalidLink, req.AttachmentID)
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if attachment.UserID != userID || (attachment.ExpiresAt != nil && !attachment.ExpiresAt.After(now)) {
+		return nil, fmt.Errorf("%w: attachment %s not found", ErrInvalidLink, req.AttachmentID)
+	}
+
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = s.config.Attachments.LinkTTL
+	}
+	// Tokens carry whole seconds
+	expiresAt := now.Add(ttl).Truncate(time.Second)
+	if attachment.ExpiresAt != nil && attachment.ExpiresAt.Before(expiresAt) {
+		expiresAt = attachment.ExpiresAt.Truncate(time.Second)
+	}
+
+	link := &models.DownloadLink{
+		UserID:       userID,
+		AttachmentID: attachment.ID,
+		Filename:     req.Filename,
+		Size:         attachment.Size,
+		Recipient:    req.Recipient,
+		ExpiresAt:    expiresAt,
+	}
+	if link.Filename == "" {
+		link.Filename = attachment.Filename
+	}
+
+	if err := s.repo.SaveDownloadLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(link), nil
+}
@@ -0,0 +1,87 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/signer"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+)
+
+var (
+	ErrInvalidLink  = errors.New("invalid download link")
+	ErrLinkNotFound = errors.New("download link not found")
+	ErrLinkGone     = errors.New("download link expired or revoked")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// tokenPurpose separates download tokens from other signed tokens
+	tokenPurpose = "download"
+)
+
+// Service defines the interface for download links to stored attachments
+type Service interface {
+	// Create issues a signed, expiring link to a stored attachment
+	Create(ctx context.Context, userID string, req CreateRequest) (*LinkResponse, error)
+
+	// Open verifies a link token, counts the download and opens the file
+	Open(ctx context.Context, token string) (*File, error)
+
+	// List returns the download links of a user, newest first
+	List(ctx context.Context, userID string, page, limit int) (*ListLinksResponse, error)
+
+	// Revoke disables a link before it expires
+	Revoke(ctx context.Context, userID, id string) error
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	signer *signer.Signer
+	config *config.Config
+}
+
+// New creates a new download link service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		signer: signer.New(cfg.Server.SigningSecret, tokenPurpose),
+		config: cfg,
+	}
+}
+
+// url returns the public address of a link. Tokens are derived from the
+// link ID and expiry, so the same URL is returned every time.
+func (s *service) url(link *models.DownloadLink) string {
+	token := s.signer.Sign(link.ID[:], link.ExpiresAt)
+	return strings.TrimRight(s.config.Server.PublicURL, "/") + "/files/" + token
+}
+
+// toResponse converts a download link into its API representation
+func (s *service) toResponse(link *models.DownloadLink) *LinkResponse {
+	return &LinkResponse{
+		ID:             link.ID.Hex(),
+		URL:            s.url(link),
+		AttachmentID:   link.AttachmentID.Hex(),
+		Filename:       link.Filename,
+		Size:           link.Size,
+		Recipient:      link.Recipient,
+		Downloads:      link.Downloads,
+		LastDownloadAt: link.LastDownloadAt,
+		ExpiresAt:      link.ExpiresAt,
+		RevokedAt:      link.RevokedAt,
+		CreatedAt:      link.CreatedAt,
+	}
+}
+
+// gone reports whether a link can no longer be downloaded
+func gone(link *models.DownloadLink, now time.Time) bool {
+	return link.RevokedAt != nil || !link.ExpiresAt.After(now)
+}
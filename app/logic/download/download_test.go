@@ -0,0 +1,148 @@
+package download
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/signer"
+	attachmentRepo "GoMail/app/repository/attachment"
+	linkRepo "GoMail/app/repository/downloadlink"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newService(repo *repoMocks.Repository) *service {
+	return New(repo, &config.Config{
+		Server:      config.ServerConfig{PublicURL: "https://mail.example.com/", SigningSecret: "secret"},
+		Attachments: config.AttachmentConfig{LinkTTL: 24 * time.Hour},
+	}).(*service)
+}
+
+// tokenOf returns the token of a link URL
+func tokenOf(url string) string {
+	return strings.TrimPrefix(url, "https://mail.example.com/files/")
+}
+
+func TestService_Create(t *testing.T) {
+	soon := time.Now().Add(time.Hour)
+	attachment := &models.StoredAttachment{ID: primitive.NewObjectID(), UserID: "user-1", Filename: "stored.pdf", Size: 42, ExpiresAt: &soon}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindAttachmentByID", mock.Anything, attachment.ID.Hex()).Return(attachment, nil)
+	repo.On("SaveDownloadLink", mock.Anything, mock.MatchedBy(func(link *models.DownloadLink) bool {
+		link.ID = primitive.NewObjectID()
+		return link.UserID == "user-1" && link.AttachmentID == attachment.ID && link.Filename == "report.pdf" &&
+			link.Recipient == "ada@example.com" && link.ExpiresAt.Equal(soon.Truncate(time.Second))
+	})).Return(nil)
+
+	s := newService(repo)
+	got, err := s.Create(context.Background(), "user-1", CreateRequest{AttachmentID: attachment.ID.Hex(), Filename: "report.pdf", Recipient: "ada@example.com"})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got.URL, "https://mail.example.com/files/"), got.URL)
+	assert.Equal(t, int64(42), got.Size)
+	payload, _, err := s.signer.Verify(tokenOf(got.URL), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, got.ID, primitive.ObjectID(payload).Hex())
+	repo.AssertExpectations(t)
+}
+
+func TestService_Create_Errors(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindAttachmentByID", mock.Anything, "foreign").Return(&models.StoredAttachment{UserID: "user-2"}, nil)
+	repo.On("FindAttachmentByID", mock.Anything, "expired").Return(&models.StoredAttachment{UserID: "user-1", ExpiresAt: &past}, nil)
+	repo.On("FindAttachmentByID", mock.Anything, "missing").Return(nil, attachmentRepo.ErrInvalidID)
+
+	s := newService(repo)
+	for _, id := range []string{"foreign", "expired", "missing"} {
+		_, err := s.Create(context.Background(), "user-1", CreateRequest{AttachmentID: id})
+		assert.ErrorIs(t, err, ErrInvalidLink, id)
+	}
+	repo.AssertNotCalled(t, "SaveDownloadLink", mock.Anything, mock.Anything)
+}
+
+func TestService_Open(t *testing.T) {
+	attachment := &models.StoredAttachment{ID: primitive.NewObjectID(), MimeType: "application/pdf", Size: 6, FileID: primitive.NewObjectID()}
+	link := &models.DownloadLink{ID: primitive.NewObjectID(), AttachmentID: attachment.ID, Filename: "report.pdf", ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Second)}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindDownloadLinkByID", mock.Anything, link.ID.Hex()).Return(link, nil)
+	repo.On("FindAttachmentByID", mock.Anything, attachment.ID.Hex()).Return(attachment, nil)
+	repo.On("RecordDownload", mock.Anything, link.ID, mock.AnythingOfType("time.Time")).Return(link, nil)
+	repo.On("OpenAttachmentFile", mock.Anything, attachment.FileID).Return(io.NopCloser(strings.NewReader("report")), nil)
+
+	s := newService(repo)
+	file, err := s.Open(context.Background(), tokenOf(s.url(link)))
+
+	require.NoError(t, err)
+	assert.Equal(t, "report.pdf", file.Filename)
+	assert.Equal(t, "application/pdf", file.MimeType)
+	content, _ := io.ReadAll(file.Content)
+	assert.Equal(t, "report", string(content))
+	repo.AssertExpectations(t)
+}
+
+func TestService_Open_Errors(t *testing.T) {
+	now := time.Now()
+	live := &models.DownloadLink{ID: primitive.NewObjectID(), AttachmentID: primitive.NewObjectID(), ExpiresAt: now.Add(time.Hour).Truncate(time.Second)}
+	revoked := &models.DownloadLink{ID: primitive.NewObjectID(), ExpiresAt: now.Add(time.Hour).Truncate(time.Second), RevokedAt: &now}
+	expired := &models.DownloadLink{ID: primitive.NewObjectID(), ExpiresAt: now.Add(-time.Hour).Truncate(time.Second)}
+	removed := &models.DownloadLink{ID: primitive.NewObjectID(), ExpiresAt: now.Add(time.Hour).Truncate(time.Second)}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindDownloadLinkByID", mock.Anything, live.ID.Hex()).Return(live, nil)
+	repo.On("FindDownloadLinkByID", mock.Anything, revoked.ID.Hex()).Return(revoked, nil)
+	repo.On("FindDownloadLinkByID", mock.Anything, removed.ID.Hex()).Return(nil, linkRepo.ErrLinkNotFound)
+	repo.On("FindAttachmentByID", mock.Anything, live.AttachmentID.Hex()).Return(nil, attachmentRepo.ErrAttachmentNotFound)
+
+	s := newService(repo)
+	forged := signer.New("other", tokenPurpose).Sign(live.ID[:], live.ExpiresAt)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "malformed", token: "nope", wantErr: ErrLinkNotFound},
+		{name: "forged", token: forged, wantErr: ErrLinkNotFound},
+		{name: "wrong payload", token: s.signer.Sign([]byte("short"), live.ExpiresAt), wantErr: ErrLinkNotFound},
+		{name: "expired", token: tokenOf(s.url(expired)), wantErr: ErrLinkGone},
+		{name: "revoked", token: tokenOf(s.url(revoked)), wantErr: ErrLinkGone},
+		{name: "link removed", token: tokenOf(s.url(removed)), wantErr: ErrLinkGone},
+		{name: "attachment removed", token: tokenOf(s.url(live)), wantErr: ErrLinkGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Open(context.Background(), tt.token)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+	repo.AssertNotCalled(t, "RecordDownload", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Revoke(t *testing.T) {
+	link := &models.DownloadLink{ID: primitive.NewObjectID(), UserID: "user-1"}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindDownloadLinkByID", mock.Anything, link.ID.Hex()).Return(link, nil)
+	repo.On("RevokeDownloadLink", mock.Anything, link.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	s := newService(repo)
+
+	assert.NoError(t, s.Revoke(context.Background(), "user-1", link.ID.Hex()))
+	assert.ErrorIs(t, s.Revoke(context.Background(), "user-2", link.ID.Hex()), ErrLinkNotFound)
+	repo.AssertNumberOfCalls(t, "RevokeDownloadLink", 1)
+}
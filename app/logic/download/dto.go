@@ -0,0 +1,52 @@
+package download
+
+import (
+	"io"
+	"time"
+)
+
+// CreateRequest represents a link to issue for a stored attachment
+type CreateRequest struct {
+	AttachmentID string
+
+	// Filename is shown for the download instead of the stored one
+	Filename string
+
+	// Recipient is the address the link is sent to, for reporting
+	Recipient string
+
+	// TTL is how long the link is valid, zero uses the configured default.
+	// Links never outlive the attachment.
+	TTL time.Duration
+}
+
+// LinkResponse represents a download link
+type LinkResponse struct {
+	ID             string     `json:"id"`
+	URL            string     `json:"url"`
+	AttachmentID   string     `json:"attachmentId"`
+	Filename       string     `json:"filename"`
+	Size           int64      `json:"size"`
+	Recipient      string     `json:"recipient,omitempty"`
+	Downloads      int64      `json:"downloads"`
+	LastDownloadAt *time.Time `json:"lastDownloadAt,omitempty"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// ListLinksResponse represents a page of download links
+type ListLinksResponse struct {
+	Links []LinkResponse `json:"links"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+// File is an opened download. The caller closes Content.
+type File struct {
+	Filename string
+	MimeType string
+	Size     int64
+	Content  io.ReadCloser
+}
@@ -0,0 +1,58 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	linkRepo "GoMail/app/repository/downloadlink"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// List returns the download links of a user, newest first
+func (s *service) List(ctx context.Context, userID string, page, limit int) (*ListLinksResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	links, total, err := s.repo.FindDownloadLinks(ctx, bson.M{"user_id": userID}, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListLinksResponse{
+		Links: make([]LinkResponse, 0, len(links)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for _, link := range links {
+		resp.Links = append(resp.Links, *s.toResponse(link))
+	}
+
+	return resp, nil
+}
+
+// Revoke disables a link of the user before it expires
+func (s *service) Revoke(ctx context.Context, userID, id string) error {
+	link, err := s.repo.FindDownloadLinkByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, linkRepo.ErrLinkNotFound) || errors.Is(err, linkRepo.ErrInvalidID) {
+			return fmt.Errorf("%w: %s", ErrLinkNotFound, id)
+		}
+		return err
+	}
+	if link.UserID != userID {
+		return fmt.Errorf("%w: %s", ErrLinkNotFound, id)
+	}
+
+	return s.repo.RevokeDownloadLink(ctx, link.ID, time.Now())
+}
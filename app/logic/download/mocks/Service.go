@@ -0,0 +1,137 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	download "GoMail/app/logic/download"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, userID, req
+func (_m *Service) Create(ctx context.Context, userID string, req download.CreateRequest) (*download.LinkResponse, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *download.LinkResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, download.CreateRequest) (*download.LinkResponse, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, download.CreateRequest) *download.LinkResponse); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*download.LinkResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, download.CreateRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID, page, limit
+func (_m *Service) List(ctx context.Context, userID string, page int, limit int) (*download.ListLinksResponse, error) {
+	ret := _m.Called(ctx, userID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *download.ListLinksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*download.ListLinksResponse, error)); ok {
+		return rf(ctx, userID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *download.ListLinksResponse); ok {
+		r0 = rf(ctx, userID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*download.ListLinksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, userID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Open provides a mock function with given fields: ctx, token
+func (_m *Service) Open(ctx context.Context, token string) (*download.File, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 *download.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*download.File, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *download.File); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*download.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Revoke(ctx context.Context, userID string, id string) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
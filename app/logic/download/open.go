@@ -0,0 +1,67 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/libs/signer"
+	attachmentRepo "GoMail/app/repository/attachment"
+	linkRepo "GoMail/app/repository/downloadlink"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Open verifies a link token, counts the download and opens the file. The
+// signature and expiry are checked before the database is queried.
+func (s *service) Open(ctx context.Context, token string) (*File, error) {
+	now := time.Now()
+
+	payload, _, err := s.signer.Verify(token, now)
+	if errors.Is(err, signer.ErrExpiredToken) {
+		return nil, ErrLinkGone
+	}
+	if err != nil || len(payload) != len(primitive.ObjectID{}) {
+		return nil, ErrLinkNotFound
+	}
+	id := primitive.ObjectID(payload)
+
+	link, err := s.repo.FindDownloadLinkByID(ctx, id.Hex())
+	if err != nil {
+		if errors.Is(err, linkRepo.ErrLinkNotFound) {
+			return nil, ErrLinkGone
+		}
+		return nil, err
+	}
+	if gone(link, now) {
+		return nil, ErrLinkGone
+	}
+
+	attachment, err := s.repo.FindAttachmentByID(ctx, link.AttachmentID.Hex())
+	if err != nil {
+		if errors.Is(err, attachmentRepo.ErrAttachmentNotFound) {
+			return nil, ErrLinkGone
+		}
+		return nil, err
+	}
+
+	// Counting checks the link again, in case it was revoked meanwhile
+	if _, err := s.repo.RecordDownload(ctx, id, now); err != nil {
+		if errors.Is(err, linkRepo.ErrLinkNotFound) {
+			return nil, ErrLinkGone
+		}
+		return nil, err
+	}
+
+	content, err := s.repo.OpenAttachmentFile(ctx, attachment.FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Filename: link.Filename,
+		MimeType: attachment.MimeType,
+		Size:     attachment.Size,
+		Content:  content,
+	}, nil
+}
@@ -0,0 +1,168 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"GoMail/app/libs/fetch"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/logic/attachment"
+	"GoMail/app/repository/models"
+)
+
+// remoteFetcher downloads attachments referenced by URL
+type remoteFetcher interface {
+	Fetch(ctx context.Context, url string) (*fetch.Resource, error)
+}
+
+// storedAttachments resolves references to the attachment store and stores
+// the files replaced with download links
+type storedAttachments interface {
+	Resolve(ctx context.Context, userID string, ids []string) ([]libSmtp.Attachment, error)
+	Upload(ctx context.Context, userID string, req attachment.UploadRequest) (*attachment.AttachmentResponse, error)
+}
+
+// withStored resolves the attachment store references of a request and
+// returns them after the given attachments. The stored ones stream their
+// content from the store.
+func (s *emailService) withStored(ctx context.Context, userID string, attachments []libSmtp.Attachment, ids []string) ([]libSmtp.Attachment, error) {
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+	if s.stored == nil {
+		return nil, fmt.Errorf("%w: the attachment store isn't enabled", ErrInvalidAttachment)
+	}
+
+	stored, err := s.stored.Resolve(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+	}
+
+	combined := make([]libSmtp.Attachment, 0, len(attachments)+len(stored))
+	combined = append(combined, attachments...)
+	return append(combined, stored...), nil
+}
+
+// emailAttachments returns the attachments of a stored email, including the
+// ones it references in the attachment store
+func (s *emailService) emailAttachments(ctx context.Context, email *models.Email) ([]libSmtp.Attachment, error) {
+	return s.withStored(ctx, email.UserID, fromEmailAttachments(email.Attachments), email.AttachmentIDs)
+}
+
+// resolveAttachments validates attachments, downloads the ones given by URL
+// and sniffs missing MIME types. The request's slice is left untouched; the
+// returned one carries the content. A filename or MIME type given by the
+// caller wins over the one of the response.
+func (s *emailService) resolveAttachments(ctx context.Context, attachments []libSmtp.Attachment) ([]libSmtp.Attachment, error) {
+	if len(attachments) == 0 {
+		return attachments, nil
+	}
+
+	resolved := make([]libSmtp.Attachment, len(attachments))
+	var total int64
+	for i, att := range attachments {
+		if strings.ContainsAny(att.ContentID, "<>\r\n\t ") {
+			return nil, fmt.Errorf("%w: content id %q", ErrInvalidAttachment, att.ContentID)
+		}
+
+		if att.URL != "" {
+			if len(att.Content) > 0 || att.Open != nil {
+				return nil, fmt.Errorf("%w: %s has both content and a url", ErrInvalidAttachment, att.Filename)
+			}
+			if s.fetcher == nil {
+				return nil, fmt.Errorf("%w: remote attachments aren't enabled", ErrInvalidAttachment)
+			}
+
+			resource, err := s.fetcher.Fetch(ctx, att.URL)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+			}
+
+			att.Content = resource.Content
+			if att.Filename == "" {
+				att.Filename = resource.Filename
+			}
+			if att.MimeType == "" {
+				att.MimeType = resource.MimeType
+			}
+		}
+
+		// Check the limits before anything is read for sniffing
+		size := att.Len()
+		if limit := s.config.Attachments.MaxFileSize; limit > 0 && size > limit {
+			return nil, fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrAttachmentTooLarge, att.Filename, size, limit)
+		}
+		total += size
+		// Attachments replaced with download links don't count, the limit
+		// is checked once they are
+		if limit := s.config.Attachments.MaxMessageSize; limit > 0 && total > limit && !s.linksEnabled() {
+			return nil, fmt.Errorf("%w: attachments exceed %d bytes", ErrAttachmentTooLarge, limit)
+		}
+
+		if att.MimeType == "" {
+			mimeType, err := sniffMimeType(att)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+			}
+			att.MimeType = mimeType
+		}
+		resolved[i] = att
+	}
+	return resolved, nil
+}
+
+// sniffMimeType detects the MIME type of an attachment from the first 512
+// bytes of its content, falling back to the file extension when the
+// content says nothing more specific than binary data
+func sniffMimeType(att libSmtp.Attachment) (string, error) {
+	head := att.Content
+	if att.Open != nil {
+		content, err := att.Open()
+		if err != nil {
+			return "", err
+		}
+		defer content.Close()
+
+		head = make([]byte, 512)
+		n, err := io.ReadFull(content, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return "", err
+		}
+		head = head[:n]
+	}
+
+	mimeType := http.DetectContentType(head)
+	if mimeType == "application/octet-stream" {
+		if byExtension := mime.TypeByExtension(path.Ext(att.Filename)); byExtension != "" {
+			mimeType = byExtension
+		}
+	}
+	return mimeType, nil
+}
+
+// loadAttachments reads streamed attachments into memory, for emails that
+// are stored for the queue workers
+func loadAttachments(attachments []libSmtp.Attachment) ([]libSmtp.Attachment, error) {
+	loaded := make([]libSmtp.Attachment, len(attachments))
+	for i, att := range attachments {
+		if att.Open != nil {
+			content, err := att.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+			}
+			att.Content, err = io.ReadAll(content)
+			content.Close()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+			}
+			att.Open, att.Size = nil, 0
+		}
+		loaded[i] = att
+	}
+	return loaded, nil
+}
@@ -0,0 +1,468 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/fetch"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtp/mocks"
+	"GoMail/app/logic/attachment"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+// fakeFetcher serves resources by URL and fails for unknown ones
+type fakeFetcher map[string]*fetch.Resource
+
+func (f fakeFetcher) Fetch(ctx context.Context, url string) (*fetch.Resource, error) {
+	if resource, ok := f[url]; ok {
+		return resource, nil
+	}
+	return nil, fmt.Errorf("%w: %s", fetch.ErrBlockedAddress, url)
+}
+
+var testFetcher = fakeFetcher{
+	"https://cdn.example.com/logo.png": {Content: []byte("png"), MimeType: "image/png", Filename: "logo.png"},
+}
+
+func TestEmailService_resolveAttachments(t *testing.T) {
+	s := &emailService{fetcher: testFetcher, config: &config.Config{}}
+
+	given := []libSmtp.Attachment{
+		{Filename: "a.txt", Content: []byte("a"), MimeType: "text/plain"},
+		{URL: "https://cdn.example.com/logo.png", ContentID: "logo"},
+		{URL: "https://cdn.example.com/logo.png", Filename: "brand.png"},
+	}
+
+	got, err := s.resolveAttachments(context.Background(), given)
+
+	require.NoError(t, err)
+	assert.Equal(t, given[0], got[0])
+	assert.Equal(t, libSmtp.Attachment{Filename: "logo.png", Content: []byte("png"), MimeType: "image/png", ContentID: "logo", URL: "https://cdn.example.com/logo.png"}, got[1])
+	assert.Equal(t, "brand.png", got[2].Filename)
+	assert.Nil(t, given[1].Content, "the request must not be modified")
+}
+
+func TestEmailService_resolveAttachments_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		fetcher    remoteFetcher
+		attachment libSmtp.Attachment
+	}{
+		{
+			name:       "fetch fails",
+			fetcher:    testFetcher,
+			attachment: libSmtp.Attachment{URL: "https://127.0.0.1/admin"},
+		},
+		{
+			name:       "content and url",
+			fetcher:    testFetcher,
+			attachment: libSmtp.Attachment{URL: "https://cdn.example.com/logo.png", Content: []byte("x")},
+		},
+		{
+			name:       "content id with a line break",
+			fetcher:    testFetcher,
+			attachment: libSmtp.Attachment{Content: []byte("x"), ContentID: "a\r\nBcc: x@example.com"},
+		},
+		{
+			name:       "no fetcher",
+			attachment: libSmtp.Attachment{URL: "https://cdn.example.com/logo.png"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &emailService{fetcher: tt.fetcher, config: &config.Config{}}
+
+			got, err := s.resolveAttachments(context.Background(), []libSmtp.Attachment{tt.attachment})
+
+			assert.ErrorIs(t, err, ErrInvalidAttachment)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestEmailService_resolveAttachments_Limits(t *testing.T) {
+	s := &emailService{config: &config.Config{Attachments: config.AttachmentConfig{MaxFileSize: 10, MaxMessageSize: 15}}}
+
+	tests := []struct {
+		name        string
+		attachments []libSmtp.Attachment
+		wantErr     error
+	}{
+		{
+			name:        "within the limits",
+			attachments: []libSmtp.Attachment{{Filename: "a", Content: make([]byte, 10)}, {Filename: "b", Content: make([]byte, 5)}},
+		},
+		{
+			name:        "file too large",
+			attachments: []libSmtp.Attachment{{Filename: "a", Content: make([]byte, 11)}},
+			wantErr:     ErrAttachmentTooLarge,
+		},
+		{
+			name:        "streamed file too large",
+			attachments: []libSmtp.Attachment{{Filename: "a", Size: 11, Open: openString("")}},
+			wantErr:     ErrAttachmentTooLarge,
+		},
+		{
+			name:        "message too large",
+			attachments: []libSmtp.Attachment{{Filename: "a", Content: make([]byte, 10)}, {Filename: "b", Content: make([]byte, 6)}},
+			wantErr:     ErrAttachmentTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.resolveAttachments(context.Background(), tt.attachments)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEmailService_resolveAttachments_SniffsMimeType(t *testing.T) {
+	s := &emailService{config: &config.Config{}}
+
+	got, err := s.resolveAttachments(context.Background(), []libSmtp.Attachment{
+		{Filename: "scan", Content: []byte("%PDF-1.7")},
+		{Filename: "logo.bin", Size: 16, Open: openString("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+		{Filename: "data.csv", Content: []byte{0x00, 0x01, 0x02}},
+		{Filename: "given.txt", Content: []byte("%PDF-1.7"), MimeType: "text/plain"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", got[0].MimeType)
+	assert.Equal(t, "image/png", got[1].MimeType)
+	assert.Equal(t, "text/csv; charset=utf-8", got[2].MimeType)
+	assert.Equal(t, "text/plain", got[3].MimeType)
+}
+
+func TestLoadAttachments(t *testing.T) {
+	got, err := loadAttachments([]libSmtp.Attachment{
+		{Filename: "a.txt", Content: []byte("a")},
+		{Filename: "b.txt", Size: 1, Open: openString("b")},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []libSmtp.Attachment{{Filename: "a.txt", Content: []byte("a")}, {Filename: "b.txt", Content: []byte("b")}}, got)
+}
+
+func TestEmailService_SendWithAttachments_StreamedAsync(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(email *models.Email) bool {
+		return len(email.Attachments) == 1 && string(email.Attachments[0].Content) == "report"
+	})).Return(nil)
+
+	s := &emailService{client: &mocks.SMTPClient{}, repo: repo, config: &config.Config{}}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Report",
+		Body:        "Attached",
+		Async:       true,
+		Attachments: []libSmtp.Attachment{{Filename: "report.txt", MimeType: "text/plain", Size: 6, Open: openString("report")}},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	repo.AssertExpectations(t)
+}
+
+// openString returns an Open function streaming s
+func openString(s string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(s)), nil
+	}
+}
+
+func TestEmailService_SendWithAttachments_Remote(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendWithAttachments", mock.Anything, "sender@example.com", "recipient@example.com", "Logo", "See attached",
+		[]libSmtp.Attachment{{Filename: "logo.png", Content: []byte("png"), MimeType: "image/png", URL: "https://cdn.example.com/logo.png"}}).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, fetcher: testFetcher, config: &config.Config{}}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Logo",
+		Body:        "See attached",
+		Attachments: []libSmtp.Attachment{{URL: "https://cdn.example.com/logo.png"}},
+	})
+
+	awaitLog(t, logged)
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_SendWithAttachments_RemoteFailure(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	s := &emailService{client: client, fetcher: testFetcher, config: &config.Config{}}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Logo",
+		Body:        "See attached",
+		Attachments: []libSmtp.Attachment{{URL: "https://169.254.169.254/latest/meta-data"}},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidAttachment)
+	assert.False(t, got.Success)
+	client.AssertNotCalled(t, "SendWithAttachments")
+}
+
+func TestEmailService_SendBulk_RemoteAttachmentAsync(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(email *models.Email) bool {
+		return len(email.Attachments) == 1 && email.Attachments[0].ContentID == "logo" && string(email.Attachments[0].Content) == "png"
+	})).Return(nil)
+
+	// Inline images need the multipart/alternative path of generated text parts
+	s := &emailService{client: &mocks.SMTPClient{}, repo: repo, fetcher: testFetcher, config: &config.Config{HTML: config.HTMLConfig{GenerateText: true}}}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		Async: true,
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "b@example.com", Subject: "Hi", Body: `<img src="cid:logo">`, IsHTML: true,
+				Attachments: []libSmtp.Attachment{{URL: "https://cdn.example.com/logo.png", ContentID: "logo"}}},
+			{From: "a@example.com", To: "c@example.com", Subject: "Hi", Body: "x",
+				Attachments: []libSmtp.Attachment{{URL: "https://10.0.0.1/secret"}}},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, got.Results[0].Success)
+	assert.False(t, got.Results[1].Success)
+	assert.Contains(t, got.Results[1].Error, "invalid attachment")
+	repo.AssertNumberOfCalls(t, "SaveEmail", 1)
+}
+
+// fakeStore resolves attachment store references of user-1
+type fakeStore map[string]string
+
+func (f fakeStore) Resolve(ctx context.Context, userID string, ids []string) ([]libSmtp.Attachment, error) {
+	attachments := make([]libSmtp.Attachment, 0, len(ids))
+	for _, id := range ids {
+		content, ok := f[id]
+		if !ok || userID != "user-1" {
+			return nil, fmt.Errorf("attachment %s not found", id)
+		}
+		attachments = append(attachments, libSmtp.Attachment{Filename: id + ".txt", MimeType: "text/plain", Size: int64(len(content)), Open: openString(content)})
+	}
+	return attachments, nil
+}
+
+// Upload stores nothing and returns an ID derived from the filename
+func (f fakeStore) Upload(ctx context.Context, userID string, req attachment.UploadRequest) (*attachment.AttachmentResponse, error) {
+	content, err := req.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	if _, err := io.Copy(io.Discard, content); err != nil {
+		return nil, err
+	}
+	return &attachment.AttachmentResponse{ID: "stored-" + req.Filename, Filename: req.Filename, Size: req.Size}, nil
+}
+
+// streams reports whether attachments are the given ones followed by the
+// stored ones, which stream their content
+func streams(attachments []libSmtp.Attachment, given int, stored ...string) bool {
+	if len(attachments) != given+len(stored) {
+		return false
+	}
+	for i, content := range stored {
+		att := attachments[given+i]
+		if att.Open == nil {
+			return false
+		}
+		r, err := att.Open()
+		if err != nil {
+			return false
+		}
+		data, _ := io.ReadAll(r)
+		if string(data) != content {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEmailService_SendWithAttachments_Stored(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendWithAttachments", mock.Anything, "sender@example.com", "recipient@example.com", "Terms", "Attached",
+		mock.MatchedBy(func(attachments []libSmtp.Attachment) bool {
+			return attachments[0].Filename == "a.txt" && streams(attachments, 1, "terms")
+		})).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, stored: fakeStore{"terms": "terms"}, config: &config.Config{}}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		UserID:        "user-1",
+		From:          "sender@example.com",
+		To:            "recipient@example.com",
+		Subject:       "Terms",
+		Body:          "Attached",
+		Attachments:   []libSmtp.Attachment{{Filename: "a.txt", Content: []byte("a"), MimeType: "text/plain"}},
+		AttachmentIDs: []string{"terms"},
+	})
+
+	awaitLog(t, logged)
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_SendWithAttachments_StoredAsync(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(email *models.Email) bool {
+		return len(email.Attachments) == 0 && email.ContentType == "multipart/mixed" &&
+			assert.ObjectsAreEqual([]string{"terms"}, email.AttachmentIDs)
+	})).Return(nil)
+
+	s := &emailService{client: &mocks.SMTPClient{}, repo: repo, stored: fakeStore{"terms": "terms"}, config: &config.Config{}}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		UserID:        "user-1",
+		From:          "sender@example.com",
+		To:            "recipient@example.com",
+		Subject:       "Terms",
+		Body:          "Attached",
+		Async:         true,
+		AttachmentIDs: []string{"terms"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	repo.AssertExpectations(t)
+}
+
+func TestEmailService_SendWithAttachments_StoredNotFound(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	s := &emailService{client: client, stored: fakeStore{"terms": "terms"}, config: &config.Config{}}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		UserID:        "user-2",
+		From:          "sender@example.com",
+		To:            "recipient@example.com",
+		Subject:       "Terms",
+		Body:          "Attached",
+		AttachmentIDs: []string{"terms"},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidAttachment)
+	assert.False(t, got.Success)
+	client.AssertNotCalled(t, "SendWithAttachments")
+}
+
+func TestEmailService_Deliver_Stored(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendWithAttachments", mock.Anything, "f", "t", "s", "b", mock.MatchedBy(func(attachments []libSmtp.Attachment) bool {
+		return streams(attachments, 0, "terms")
+	})).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	claimDeliveries(repo)
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, stored: fakeStore{"terms": "terms"}, config: &config.Config{}}
+
+	err := s.Deliver(context.Background(), &models.Email{ID: primitive.NewObjectID(), UserID: "user-1", ContentType: "multipart/mixed",
+		From: "f", To: "t", Subject: "s", Body: "b", AttachmentIDs: []string{"terms"}})
+
+	awaitLog(t, logged)
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_SendBulk_HTMLStored(t *testing.T) {
+	noText := false
+	email := BulkEmail{
+		From:          "sender@example.com",
+		To:            "recipient@example.com",
+		Subject:       "Terms",
+		Body:          "<p>Attached</p>",
+		IsHTML:        true,
+		HTMLOptions:   &HTMLOptions{GenerateText: &noText},
+		AttachmentIDs: []string{"terms"},
+	}
+
+	t.Run("sync", func(t *testing.T) {
+		client := &mocks.SMTPClient{}
+		client.On("SendAlternative", mock.Anything, "sender@example.com", "recipient@example.com", "Terms", "", "<p>Attached</p>",
+			mock.MatchedBy(func(attachments []libSmtp.Attachment) bool {
+				return streams(attachments, 0, "terms")
+			})).Return(nil)
+
+		repo := &repoMocks.Repository{}
+		logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+		s := &emailService{client: client, repo: repo, stored: fakeStore{"terms": "terms"}, scanner: fakeScanner{}, config: &config.Config{}}
+
+		got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{UserID: "user-1", Emails: []BulkEmail{email}})
+
+		require.NoError(t, err)
+		assert.True(t, got.Results[0].Success)
+		client.AssertExpectations(t)
+		log := awaitLog(t, logged)
+		assert.Equal(t, "multipart/mixed", log.ContentType)
+		require.NotNil(t, log.Scan)
+		assert.Equal(t, models.ScanStatusClean, log.Scan.Status)
+	})
+
+	t.Run("async", func(t *testing.T) {
+		repo := &repoMocks.Repository{}
+		repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(queued *models.Email) bool {
+			return queued.IsHTML && queued.ContentType == "multipart/mixed" && queued.TextBody == "" &&
+				assert.ObjectsAreEqual([]string{"terms"}, queued.AttachmentIDs)
+		})).Return(nil)
+
+		s := &emailService{client: &mocks.SMTPClient{}, repo: repo, stored: fakeStore{"terms": "terms"}, config: &config.Config{}}
+
+		got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{UserID: "user-1", Async: true, Emails: []BulkEmail{email}})
+
+		require.NoError(t, err)
+		assert.True(t, got.Results[0].Success)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestEmailService_Deliver_HTMLStored(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendAlternative", mock.Anything, "f", "t", "s", "", "<p>b</p>", mock.MatchedBy(func(attachments []libSmtp.Attachment) bool {
+		return streams(attachments, 0, "terms")
+	})).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	claimDeliveries(repo)
+	repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil)
+
+	s := &emailService{client: client, repo: repo, stored: fakeStore{"terms": "terms"}, config: &config.Config{}}
+
+	err := s.Deliver(context.Background(), &models.Email{ID: primitive.NewObjectID(), UserID: "user-1", ContentType: "multipart/mixed", IsHTML: true,
+		From: "f", To: "t", Subject: "s", Body: "<p>b</p>", AttachmentIDs: []string{"terms"}})
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,191 @@
+package email
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/repository/models"
+)
+
+// StatusSuppressedDuplicate is the status of a send that was suppressed
+// because an identical email was sent within the dedup window
+const StatusSuppressedDuplicate = string(models.EmailLogStatusSuppressedDuplicate)
+
+// dedupKey is the content that makes two sends of a user identical
+type dedupKey struct {
+	To          string                 `json:"to"`
+	Subject     string                 `json:"subject"`
+	Body        string                 `json:"body"`
+	HTML        bool                   `json:"html"`
+	TemplateID  string                 `json:"templateId,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	Locale      string                 `json:"locale,omitempty"`
+	Attachments []string               `json:"attachments,omitempty"`
+}
+
+// hash returns a digest of the key. Recipients differing only in case
+// are the same.
+func (k dedupKey) hash() (string, error) {
+	k.To = strings.ToLower(strings.TrimSpace(k.To))
+
+	// Map keys are sorted, so equal data encodes the same
+	encoded, err := json.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// attachmentKeys identifies attachments for a dedup key. Content held in
+// memory is hashed; streamed and remote content is identified by its name,
+// size and source, since it can only be read once.
+func attachmentKeys(attachments []libSmtp.Attachment, ids []string) []string {
+	keys := make([]string, 0, len(attachments)+len(ids))
+	for _, att := range attachments {
+		source := att.URL
+		if att.Open == nil && att.URL == "" {
+			sum := sha256.Sum256(att.Content)
+			source = hex.EncodeToString(sum[:])
+		}
+		keys = append(keys, fmt.Sprintf("%s|%s|%d|%s", att.Filename, att.ContentID, att.Len(), source))
+	}
+	for _, id := range ids {
+		keys = append(keys, "stored|"+id)
+	}
+	return keys
+}
+
+// dedupKey returns the dedup key of a request
+func (r SendEmailRequest) dedupKey(html bool) dedupKey {
+	return dedupKey{
+		To:         r.To,
+		Subject:    r.Subject,
+		Body:       r.Body,
+		HTML:       html,
+		TemplateID: r.TemplateID,
+		Data:       r.Data,
+		Locale:     r.Locale,
+	}
+}
+
+// dedupKey returns the dedup key of a request
+func (r SendWithAttachmentsRequest) dedupKey() dedupKey {
+	return dedupKey{
+		To:          r.To,
+		Subject:     r.Subject,
+		Body:        r.Body,
+		Attachments: attachmentKeys(r.Attachments, r.AttachmentIDs),
+	}
+}
+
+// dedupKey returns the dedup key of an email of a bulk request
+func (e BulkEmail) dedupKey() dedupKey {
+	return dedupKey{
+		To:          e.To,
+		Subject:     e.Subject,
+		Body:        e.Body,
+		HTML:        e.IsHTML,
+		TemplateID:  e.TemplateID,
+		Data:        e.Data,
+		Locale:      e.Locale,
+		Attachments: attachmentKeys(e.Attachments, e.AttachmentIDs),
+	}
+}
+
+// claimSend claims the fingerprint of a send for the dedup window. It
+// reports false when the user sent an identical email within the window.
+// The returned claim is nil when dedup is disabled or bypassed, or when
+// the fingerprints can't be stored: the check is best effort and doesn't
+// hold back sends.
+func (s *emailService) claimSend(ctx context.Context, userID string, bypass bool, key dedupKey) (*models.SendFingerprint, bool) {
+	if !s.config.Dedup.Enabled || bypass || s.repo == nil {
+		return nil, true
+	}
+
+	hash, err := key.hash()
+	if err != nil {
+		log.Printf("WARNING: Failed to hash email for dedup: %v", err)
+		return nil, true
+	}
+
+	// MongoDB keeps milliseconds, and releases match the expiry exactly
+	now := time.Now().Truncate(time.Millisecond)
+	claim := &models.SendFingerprint{
+		UserID:    userID,
+		Hash:      hash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.config.Dedup.Window),
+	}
+
+	claimed, err := s.repo.ClaimSendFingerprint(ctx, claim)
+	if err != nil {
+		log.Printf("WARNING: Failed to check email for duplicates: %v", err)
+		return nil, true
+	}
+	if !claimed {
+		return nil, false
+	}
+
+	return claim, true
+}
+
+// releaseSend gives up the claim of a send that failed, so that retrying
+// it isn't suppressed
+func (s *emailService) releaseSend(claim *models.SendFingerprint) {
+	if claim == nil {
+		return
+	}
+
+	// The request may be canceled already
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.repo.ReleaseSendFingerprint(ctx, claim); err != nil {
+		log.Printf("WARNING: Failed to release dedup claim: %v", err)
+	}
+}
+
+// deduplicate calls send unless the user sent an identical email within
+// the dedup window, in which case the suppressed send is logged instead
+func (s *emailService) deduplicate(ctx context.Context, userID string, bypass bool, key dedupKey, from, contentType string, send func() (*SendEmailResponse, error)) (*SendEmailResponse, error) {
+	claim, ok := s.claimSend(ctx, userID, bypass, key)
+	if !ok {
+		s.logSuppressed(userID, from, key.To, key.Subject, contentType)
+		return &SendEmailResponse{
+			Success: false,
+			Status:  StatusSuppressedDuplicate,
+		}, nil
+	}
+
+	resp, err := send()
+	if err != nil {
+		s.releaseSend(claim)
+	}
+
+	return resp, err
+}
+
+// logSuppressed logs a send suppressed as a duplicate
+func (s *emailService) logSuppressed(userID, from, to, subject, contentType string) {
+	s.logEmailAttempt(&models.EmailLog{
+		UserID:      userID,
+		From:        from,
+		To:          to,
+		Subject:     subject,
+		ContentType: contentType,
+		Success:     false,
+		Status:      models.EmailLogStatusSuppressedDuplicate,
+		Error:       fmt.Sprintf("identical email sent within the last %s", s.config.Dedup.Window),
+		SentAt:      time.Now(),
+		CreatedAt:   time.Now(),
+	})
+}
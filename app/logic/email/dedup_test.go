@@ -0,0 +1,163 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"GoMail/app/config"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtp/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+// dedupConfig suppresses identical sends within ten minutes
+func dedupConfig() *config.Config {
+	return &config.Config{Dedup: config.DedupConfig{Enabled: true, Window: 10 * time.Minute}}
+}
+
+func TestDedupKey_hash(t *testing.T) {
+	base := dedupKey{To: "ada@example.com", Subject: "Hi", Body: "Hello", TemplateID: "welcome", Data: map[string]interface{}{"a": 1, "b": "x"}}
+	hash := func(k dedupKey) string {
+		h, err := k.hash()
+		require.NoError(t, err)
+		return h
+	}
+
+	same := base
+	same.To = " Ada@Example.com "
+	same.Data = map[string]interface{}{"b": "x", "a": 1}
+	assert.Equal(t, hash(base), hash(same))
+
+	for name, change := range map[string]func(*dedupKey){
+		"recipient": func(k *dedupKey) { k.To = "bob@example.com" },
+		"subject":   func(k *dedupKey) { k.Subject = "Hello" },
+		"body":      func(k *dedupKey) { k.Body = "Hi" },
+		"template":  func(k *dedupKey) { k.TemplateID = "goodbye" },
+		"data":      func(k *dedupKey) { k.Data = map[string]interface{}{"a": 2, "b": "x"} },
+		"html":      func(k *dedupKey) { k.HTML = true },
+	} {
+		other := base
+		change(&other)
+		assert.NotEqual(t, hash(base), hash(other), name)
+	}
+}
+
+func TestAttachmentKeys(t *testing.T) {
+	a := libSmtp.Attachment{Filename: "a.txt", Content: []byte("one")}
+	b := libSmtp.Attachment{Filename: "a.txt", Content: []byte("two")}
+	remote := libSmtp.Attachment{URL: "https://cdn.example.com/logo.png", ContentID: "logo"}
+
+	assert.NotEqual(t, attachmentKeys([]libSmtp.Attachment{a}, nil), attachmentKeys([]libSmtp.Attachment{b}, nil))
+	assert.Equal(t, []string{"|logo|0|https://cdn.example.com/logo.png", "stored|abc"}, attachmentKeys([]libSmtp.Attachment{remote}, []string{"abc"}))
+}
+
+func TestEmailService_Send_Dedup(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *config.Config
+		allow       bool
+		claimed     bool
+		claimErr    error
+		sendErr     error
+		wantClaim   bool
+		wantSend    bool
+		wantRelease bool
+		wantStatus  string
+	}{
+		{name: "disabled", config: &config.Config{}, wantSend: true},
+		{name: "bypassed", config: dedupConfig(), allow: true, wantSend: true},
+		{name: "first send", config: dedupConfig(), claimed: true, wantClaim: true, wantSend: true},
+		{name: "duplicate", config: dedupConfig(), wantClaim: true, wantStatus: StatusSuppressedDuplicate},
+		{name: "failed send releases the claim", config: dedupConfig(), claimed: true, sendErr: errors.New("smtp error"), wantClaim: true, wantSend: true, wantRelease: true},
+		{name: "store unavailable", config: dedupConfig(), claimErr: errors.New("connection refused"), wantClaim: true, wantSend: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.SMTPClient{}
+			client.On("Send", mock.Anything, "test@example.com", "recipient@example.com", "Test Subject", "Test Body").Return(tt.sendErr)
+
+			repo := &repoMocks.Repository{}
+			logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+			repo.On("ClaimSendFingerprint", mock.Anything, mock.MatchedBy(func(f *models.SendFingerprint) bool {
+				return f.UserID == "user-1" && len(f.Hash) == 64 && f.ExpiresAt.Sub(f.CreatedAt) == 10*time.Minute
+			})).Return(tt.claimed, tt.claimErr)
+			repo.On("ReleaseSendFingerprint", mock.Anything, mock.AnythingOfType("*models.SendFingerprint")).Return(nil)
+
+			s := &emailService{client: client, repo: repo, config: tt.config}
+			req := validSendEmailRequest
+			req.UserID = "user-1"
+			req.AllowDuplicate = tt.allow
+
+			got, _ := s.Send(context.Background(), req)
+			log := awaitLog(t, logged)
+
+			assert.Equal(t, tt.wantStatus, got.Status)
+			if tt.wantClaim {
+				repo.AssertCalled(t, "ClaimSendFingerprint", mock.Anything, mock.Anything)
+			} else {
+				repo.AssertNotCalled(t, "ClaimSendFingerprint", mock.Anything, mock.Anything)
+			}
+			if tt.wantSend {
+				client.AssertCalled(t, "Send", mock.Anything, "test@example.com", "recipient@example.com", "Test Subject", "Test Body")
+			} else {
+				client.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				assert.False(t, log.Success)
+				assert.Equal(t, models.EmailLogStatusSuppressedDuplicate, log.Status)
+				assert.Equal(t, "recipient@example.com", log.To)
+				assert.Equal(t, "user-1", log.UserID)
+			}
+			if tt.wantRelease {
+				repo.AssertCalled(t, "ReleaseSendFingerprint", mock.Anything, mock.Anything)
+			} else {
+				repo.AssertNotCalled(t, "ReleaseSendFingerprint", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestEmailService_SendBulk_Dedup(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("Send", mock.Anything, "a@example.com", "b@example.com", "Hi", "x").Return(nil)
+	client.On("Send", mock.Anything, "a@example.com", "c@example.com", "Hi", "x").Return(errors.New("smtp error"))
+
+	// The fingerprint of each email is claimed once
+	claimed := map[string]bool{}
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+	repo.On("ClaimSendFingerprint", mock.Anything, mock.AnythingOfType("*models.SendFingerprint")).Return(
+		func(ctx context.Context, f *models.SendFingerprint) bool {
+			first := !claimed[f.Hash]
+			claimed[f.Hash] = true
+			return first
+		}, nil)
+	repo.On("ReleaseSendFingerprint", mock.Anything, mock.AnythingOfType("*models.SendFingerprint")).Return(nil)
+
+	s := &emailService{client: client, repo: repo, config: dedupConfig()}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		UserID: "user-1",
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "b@example.com", Subject: "Hi", Body: "x"},
+			{From: "a@example.com", To: "B@example.com", Subject: "Hi", Body: "x"},
+			{From: "a@example.com", To: "c@example.com", Subject: "Hi", Body: "x"},
+		},
+	})
+
+	require.NoError(t, err)
+	for range got.Results {
+		awaitLog(t, logged)
+	}
+	assert.True(t, got.Results[0].Success)
+	assert.Equal(t, EmailResult{Success: false, Status: StatusSuppressedDuplicate}, got.Results[1])
+	assert.False(t, got.Results[2].Success)
+	client.AssertNumberOfCalls(t, "Send", 2)
+	repo.AssertNumberOfCalls(t, "ReleaseSendFingerprint", 1)
+}
@@ -1,45 +1,126 @@
 package email
 
 import (
+	"time"
+
 	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/repository/models"
 )
 
 // SendEmailRequest represents a request to send an email
 type SendEmailRequest struct {
-	From    string `json:"from"`
-	To      string `json:"to"`
-	Subject string `json:"subject"`
-	Body    string `json:"body"`
+	UserID  string     `json:"-"`
+	From    string     `json:"from"`
+	To      string     `json:"to"`
+	Subject string     `json:"subject"`
+	Body    string     `json:"body"`
+	Async   bool       `json:"async"`
+	SendAt  *time.Time `json:"sendAt,omitempty"`
+
+	// TemplateID renders the published version of a stored template with
+	// Data instead of using Subject and Body. Locale selects the translation
+	// and defaults to the stored preference of the recipient.
+	TemplateID string                 `json:"templateId,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Locale     string                 `json:"locale,omitempty"`
+
+	// Format "markdown" renders Body to an HTML part in the configured
+	// layout and a plain text part
+	Format      string       `json:"format,omitempty"`
+	HTMLOptions *HTMLOptions `json:"htmlOptions,omitempty"`
+
+	// AllowDuplicate skips the dedup check for this request
+	AllowDuplicate bool `json:"allowDuplicate,omitempty"`
+
+	// Topic tags the email with a topic of the user. Recipients who opted
+	// out of it are left out.
+	Topic string `json:"topic,omitempty"`
+}
+
+// HTMLOptions switches the steps of the HTML pipeline for a request. Unset
+// steps use the configured defaults.
+type HTMLOptions struct {
+	InlineCSS    *bool  `json:"inlineCss,omitempty"`
+	GenerateText *bool  `json:"generateText,omitempty"`
+	Preheader    string `json:"preheader,omitempty"`
+
+	// TrackOpens adds a tracking pixel that records when the email is
+	// opened
+	TrackOpens *bool `json:"trackOpens,omitempty"`
+
+	// TrackClicks rewrites links to a redirect that records when they are
+	// followed. Links with a data-notrack attribute are left alone.
+	TrackClicks *bool `json:"trackClicks,omitempty"`
 }
 
 // SendEmailResponse represents a response from sending an email
 type SendEmailResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	Success    bool                  `json:"success"`
+	ID         string                `json:"id,omitempty"`
+	Status     string                `json:"status,omitempty"`
+	Error      string                `json:"error,omitempty"`
+	Suppressed []SuppressedRecipient `json:"suppressed,omitempty"`
 }
 
 // SendWithAttachmentsRequest represents a request to send an email with attachments
 type SendWithAttachmentsRequest struct {
+	UserID      string               `json:"-"`
 	From        string               `json:"from"`
 	To          string               `json:"to"`
 	Subject     string               `json:"subject"`
 	Body        string               `json:"body"`
 	Attachments []libSmtp.Attachment `json:"attachments"`
+	Async       bool                 `json:"async"`
+	SendAt      *time.Time           `json:"sendAt,omitempty"`
+	Format      string               `json:"format,omitempty"`
+	HTMLOptions *HTMLOptions         `json:"htmlOptions,omitempty"`
+
+	// AttachmentIDs references files in the attachment store, streamed
+	// from the store when the email is sent
+	AttachmentIDs []string `json:"attachmentIds,omitempty"`
+
+	// AllowDuplicate skips the dedup check for this request
+	AllowDuplicate bool `json:"allowDuplicate,omitempty"`
+
+	// Topic tags the email with a topic of the user
+	Topic string `json:"topic,omitempty"`
 }
 
 // SendBulkEmailRequest represents a request to send multiple emails
 type SendBulkEmailRequest struct {
+	UserID string      `json:"-"`
 	Emails []BulkEmail `json:"emails"`
+	Async  bool        `json:"async"`
+	SendAt *time.Time  `json:"sendAt,omitempty"`
+
+	// AllowDuplicate skips the dedup check for all emails of the request
+	AllowDuplicate bool `json:"allowDuplicate,omitempty"`
 }
 
 // BulkEmail represents a single email in a bulk send request
 type BulkEmail struct {
-	From        string               `json:"from"`
-	To          string               `json:"to"`
-	Subject     string               `json:"subject"`
-	Body        string               `json:"body"`
-	IsHTML      bool                 `json:"isHtml"`
-	Attachments []libSmtp.Attachment `json:"attachments,omitempty"`
+	From          string                 `json:"from"`
+	To            string                 `json:"to"`
+	Subject       string                 `json:"subject"`
+	Body          string                 `json:"body"`
+	IsHTML        bool                   `json:"isHtml"`
+	Attachments   []libSmtp.Attachment   `json:"attachments,omitempty"`
+	AttachmentIDs []string               `json:"attachmentIds,omitempty"`
+	TemplateID    string                 `json:"templateId,omitempty"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+	Locale        string                 `json:"locale,omitempty"`
+	Format        string                 `json:"format,omitempty"`
+	HTMLOptions   *HTMLOptions           `json:"htmlOptions,omitempty"`
+	Unsubscribe   *UnsubscribeOptions    `json:"unsubscribe,omitempty"`
+	Topic         string                 `json:"topic,omitempty"`
+}
+
+// UnsubscribeOptions adds one-click unsubscribe headers (RFC 8058) with a
+// signed link for the recipient. Unsubscribing with a List only stops the
+// emails sent to that list, otherwise all emails of the sender. List
+// defaults to the topic of the email, which recipients then opt out of.
+type UnsubscribeOptions struct {
+	List string `json:"list,omitempty"`
 }
 
 // SendBulkEmailResponse represents a response from sending multiple emails
@@ -49,6 +130,105 @@ type SendBulkEmailResponse struct {
 
 // EmailResult represents the result of sending a single email
 type EmailResult struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
-} 
\ No newline at end of file
+	Success    bool                  `json:"success"`
+	ID         string                `json:"id,omitempty"`
+	Status     string                `json:"status,omitempty"`
+	Error      string                `json:"error,omitempty"`
+	Suppressed []SuppressedRecipient `json:"suppressed,omitempty"`
+}
+
+// SuppressedRecipient reports a recipient left out of an email because of
+// the suppression list. Value is the suppressed address or domain, and List
+// the list it is suppressed for.
+type SuppressedRecipient struct {
+	Address string                   `json:"address"`
+	Value   string                   `json:"value"`
+	List    string                   `json:"list,omitempty"`
+	Reason  models.SuppressionReason `json:"reason"`
+	Scope   models.SuppressionScope  `json:"scope"`
+	Topic   string                   `json:"topic,omitempty"` // Set when the recipient opted out of the topic
+}
+
+// SendInviteRequest represents a request to send a calendar invitation
+type SendInviteRequest struct {
+	UserID  string        `json:"-"`
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Subject string        `json:"subject"`
+	Body    string        `json:"body"`
+	Event   CalendarEvent `json:"event"`
+	Async   bool          `json:"async"`
+	SendAt  *time.Time    `json:"sendAt,omitempty"`
+}
+
+// CalendarEvent represents the structured event carried by an invitation
+type CalendarEvent struct {
+	Method         string             `json:"method"` // REQUEST, UPDATE or CANCEL
+	UID            string             `json:"uid"`
+	Sequence       int                `json:"sequence"`
+	Organizer      CalendarPerson     `json:"organizer"`
+	Attendees      []CalendarAttendee `json:"attendees"`
+	Summary        string             `json:"summary"`
+	Description    string             `json:"description"`
+	Location       string             `json:"location"`
+	Start          time.Time          `json:"start"`
+	End            time.Time          `json:"end"`
+	TimeZone       string             `json:"timeZone"`
+	RecurrenceRule string             `json:"recurrenceRule"`
+}
+
+// CalendarPerson identifies the organizer of an event
+type CalendarPerson struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// CalendarAttendee represents an invited participant of an event
+type CalendarAttendee struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	RSVP  bool   `json:"rsvp"`
+}
+
+// SendInviteResponse represents a response from sending a calendar invitation
+type SendInviteResponse struct {
+	Success    bool                  `json:"success"`
+	ID         string                `json:"id,omitempty"`
+	Status     string                `json:"status,omitempty"`
+	UID        string                `json:"uid"`
+	Sequence   int                   `json:"sequence"`
+	Error      string                `json:"error,omitempty"`
+	Suppressed []SuppressedRecipient `json:"suppressed,omitempty"`
+}
+
+// EmailStatusResponse represents the delivery state of a queued email
+type EmailStatusResponse struct {
+	ID            string     `json:"id"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	SendAt        *time.Time `json:"sendAt,omitempty"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	SentAt        *time.Time `json:"sentAt,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// ListScheduledResponse represents a page of scheduled emails
+type ListScheduledResponse struct {
+	Emails []ScheduledEmail `json:"emails"`
+	Total  int64            `json:"total"`
+	Page   int              `json:"page"`
+	Limit  int              `json:"limit"`
+}
+
+// ScheduledEmail represents an email waiting for its send time
+type ScheduledEmail struct {
+	ID          string    `json:"id"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Subject     string    `json:"subject"`
+	ContentType string    `json:"contentType"`
+	SendAt      time.Time `json:"sendAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
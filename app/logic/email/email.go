@@ -2,15 +2,44 @@ package email
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html/template"
+	"log"
 	"time"
 
 	"GoMail/app/config"
+	"GoMail/app/libs/clamd"
+	"GoMail/app/libs/fetch"
+	"GoMail/app/logic/attachment"
+	"GoMail/app/logic/download"
 	"GoMail/app/libs/smtp"
+	"GoMail/app/logic/emailtemplate"
+	"GoMail/app/logic/recipient"
+	"GoMail/app/logic/suppression"
+	"GoMail/app/logic/topic"
+	"GoMail/app/logic/tracking"
+	"GoMail/app/logic/unsubscribe"
+	"GoMail/app/logic/webhook"
 	"GoMail/app/repository"
 	"GoMail/app/repository/models"
 )
 
+var (
+	ErrInvalidInvite       = errors.New("invalid calendar invitation")
+	ErrEmailNotFound       = errors.New("email not found")
+	ErrInvalidSchedule     = errors.New("invalid schedule")
+	ErrInvalidTemplate     = errors.New("invalid template request")
+	ErrInvalidFormat       = errors.New("invalid body format")
+	ErrInvalidAttachment   = errors.New("invalid attachment")
+	ErrAttachmentTooLarge  = errors.New("attachment too large")
+	ErrAttachmentInfected  = errors.New("attachment infected")
+	ErrScanFailed          = errors.New("attachment scan failed")
+	ErrRecipientSuppressed = errors.New("all recipients are suppressed")
+	ErrInvalidUnsubscribe  = errors.New("invalid unsubscribe options")
+	ErrInvalidTopic        = errors.New("invalid topic")
+)
+
 // Email defines the interface for email operations
 type Email interface {
 	// Send sends a plain text email
@@ -24,13 +53,43 @@ type Email interface {
 	
 	// SendBulk sends multiple emails concurrently
 	SendBulk(ctx context.Context, req SendBulkEmailRequest) (*SendBulkEmailResponse, error)
+	
+	// SendInvite sends an iCalendar meeting invitation, update or cancellation
+	SendInvite(ctx context.Context, req SendInviteRequest) (*SendInviteResponse, error)
+	
+	// GetStatus returns the delivery state of an email persisted for async sending
+	GetStatus(ctx context.Context, userID, id string) (*EmailStatusResponse, error)
+	
+	// ListScheduled returns the emails a user has scheduled for later delivery
+	ListScheduled(ctx context.Context, userID string, page, limit int) (*ListScheduledResponse, error)
+	
+	// CancelScheduled deletes a scheduled email before it is sent
+	CancelScheduled(ctx context.Context, userID, id string) error
+	
+	// Reschedule changes the send time of a scheduled email
+	Reschedule(ctx context.Context, userID, id string, sendAt time.Time) (*EmailStatusResponse, error)
+	
+	// Deliver sends an email persisted in the outbound queue and logs the attempt
+	Deliver(ctx context.Context, email *models.Email) error
 }
 
 // emailService implements the Email interface
 type emailService struct {
-	client smtp.SMTPClient
-	repo   repository.Repository
-	config *config.Config
+	client       smtp.SMTPClient
+	repo         repository.Repository
+	templates    emailtemplate.Service
+	layout       *template.Template
+	fetcher      remoteFetcher
+	stored       storedAttachments
+	scanner      AttachmentScanner
+	links        downloadLinks
+	suppressions suppressionList
+	topics       topicCatalog
+	preferences  recipientPreferences
+	tracker      emailTracker
+	unsubscribes unsubscribeLinks
+	events       eventPublisher
+	config       *config.Config
 }
 
 // NewEmailService creates a new email service
@@ -74,10 +133,46 @@ func NewEmailService(cfg *config.Config, repo repository.Repository) Email {
 	// Create SMTP client with config
 	client := smtp.NewClient(smtpConfig)
 	
+	// Load the layout for Markdown bodies. A configured layout that can't
+	// be used stops startup rather than being swapped for the built-in one.
+	layout, err := loadLayout(cfg.Markdown.LayoutFile)
+	if err != nil {
+		log.Fatalf("Invalid markdown layout: %v", err)
+	}
+	
+	// Scan attachments with clamd when enabled
+	var scanner AttachmentScanner
+	if cfg.Scan.Enabled {
+		scanner = clamd.New(clamd.Config{
+			Network:   cfg.Scan.Network,
+			Address:   cfg.Scan.Address,
+			Timeout:   cfg.Scan.Timeout,
+			ChunkSize: cfg.Scan.ChunkSize,
+		})
+	}
+	
 	return &emailService{
-		client: client,
-		repo:   repo,
-		config: cfg,
+		client:       client,
+		repo:         repo,
+		templates:    emailtemplate.New(repo, cfg),
+		layout:       layout,
+		stored:       attachment.New(repo, cfg),
+		links:        download.New(repo, cfg),
+		fetcher:      fetch.New(fetch.Config{
+			Timeout:      cfg.Fetch.Timeout,
+			MaxSize:      cfg.Fetch.MaxSize,
+			MaxRedirects: cfg.Fetch.MaxRedirects,
+			ContentTypes: cfg.Fetch.ContentTypes,
+			AllowPrivate: cfg.Fetch.AllowPrivate,
+		}),
+		scanner:      scanner,
+		suppressions: suppression.New(repo, cfg),
+		topics:       topic.New(repo, cfg),
+		preferences:  recipient.New(repo, cfg),
+		tracker:      tracking.New(repo, cfg),
+		unsubscribes: unsubscribe.New(repo, cfg),
+		events:       webhook.New(repo, cfg),
+		config:       cfg,
 	}
 }
 
@@ -100,5 +195,23 @@ func (s *emailService) logEmailAttempt(logData *models.EmailLog) {
 			// Just log the error, don't propagate it
 			fmt.Printf("ERROR: Failed to log email: %v\n", err)
 		}
+		
+		// Notify the webhooks subscribed to the outcome
+		s.publishAttempt(asyncCtx, logData)
 	}()
-} 
\ No newline at end of file
+} 
+
+// withMessageID gives a send a new Message-ID, which its log records so
+// that bounces and complaints can be matched to it
+func (s *emailService) withMessageID(ctx context.Context, from string) (context.Context, string) {
+	id := s.newMessageID(from)
+	return smtp.WithMessageID(ctx, id), id
+}
+
+// newMessageID returns a new Message-ID in the domain of the sender
+func (s *emailService) newMessageID(from string) string {
+	if from == "" {
+		from = s.config.SMTP.From
+	}
+	return smtp.NewMessageID(from)
+}
@@ -0,0 +1,42 @@
+package email
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+// handOffLogs hands the email logs saved through a SaveEmailLog call over on
+// the returned channel. Logs are saved by a goroutine, so tests wait for
+// them with awaitLog instead of sleeping.
+func handOffLogs(call *mock.Call) <-chan *models.EmailLog {
+	logged := make(chan *models.EmailLog, 16)
+	call.Run(func(args mock.Arguments) {
+		logged <- args.Get(1).(*models.EmailLog)
+	})
+	return logged
+}
+
+// claimDeliveries lets queued emails claim their Message-ID before they
+// are sent and settle the claim afterwards
+func claimDeliveries(repo *repoMocks.Repository) {
+	repo.On("ClaimSendFingerprint", mock.Anything, mock.AnythingOfType("*models.SendFingerprint")).Return(true, nil)
+	repo.On("ExtendSendFingerprint", mock.Anything, mock.AnythingOfType("*models.SendFingerprint"), mock.AnythingOfType("time.Time")).Return(nil)
+	repo.On("ReleaseSendFingerprint", mock.Anything, mock.AnythingOfType("*models.SendFingerprint")).Return(nil)
+}
+
+// awaitLog waits for the next email log handed over by handOffLogs
+func awaitLog(t *testing.T, logged <-chan *models.EmailLog) *models.EmailLog {
+	t.Helper()
+	select {
+	case log := <-logged:
+		return log
+	case <-time.After(time.Second):
+		t.Fatal("email was not logged")
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package email
+
+import (
+	"context"
+	"log"
+
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository/models"
+)
+
+// eventPublisher queues email events for the webhooks of their user
+type eventPublisher interface {
+	Publish(ctx context.Context, userID string, eventType models.WebhookEventType, data webhook.EventData) error
+}
+
+// publishAttempt publishes the event of a logged send: sent when the SMTP
+// server accepted it, failed when a direct send didn't go through. Queued
+// emails are retried, so the worker pool publishes their failure once the
+// last attempt failed. Suppressed and duplicate sends aren't events.
+func (s *emailService) publishAttempt(ctx context.Context, emailLog *models.EmailLog) {
+	if s.events == nil {
+		return
+	}
+
+	eventType := models.WebhookEventSent
+	if !emailLog.Success {
+		if emailLog.Status != "" || emailLog.EmailID != nil {
+			return
+		}
+		eventType = models.WebhookEventFailed
+	}
+
+	if err := s.events.Publish(ctx, emailLog.UserID, eventType, logEventData(emailLog)); err != nil {
+		log.Printf("Failed to publish %s event of %s: %v", eventType, emailLog.MessageID, err)
+	}
+}
+
+// logEventData describes a logged send in an event
+func logEventData(emailLog *models.EmailLog) webhook.EventData {
+	data := webhook.EventData{
+		MessageID:  emailLog.MessageID,
+		From:       emailLog.From,
+		To:         emailLog.To,
+		Subject:    emailLog.Subject,
+		Error:      emailLog.Error,
+		OccurredAt: emailLog.SentAt,
+	}
+	if emailLog.EmailID != nil {
+		data.EmailID = emailLog.EmailID.Hex()
+	}
+	return data
+}
+
+// queuedEventData describes a queued email that failed for good in an
+// event
+func queuedEventData(email *models.Email) webhook.EventData {
+	return webhook.EventData{
+		EmailID: email.ID.Hex(),
+		From:    email.From,
+		To:      email.To,
+		Subject: email.Subject,
+		Error:   email.Error,
+	}
+}
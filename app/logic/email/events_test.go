@@ -0,0 +1,37 @@
+package email
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"GoMail/app/repository/models"
+)
+
+func TestEmailService_publishAttempt(t *testing.T) {
+	emailID := primitive.NewObjectID()
+	tests := []struct {
+		name string
+		log  *models.EmailLog
+		want []models.WebhookEventType
+	}{
+		{name: "sent", log: &models.EmailLog{Success: true}, want: []models.WebhookEventType{models.WebhookEventSent}},
+		{name: "queued and sent", log: &models.EmailLog{EmailID: &emailID, Success: true}, want: []models.WebhookEventType{models.WebhookEventSent}},
+		{name: "failed", log: &models.EmailLog{Error: "connection refused"}, want: []models.WebhookEventType{models.WebhookEventFailed}},
+		{name: "queued and failed", log: &models.EmailLog{EmailID: &emailID, Error: "connection refused"}},
+		{name: "suppressed", log: &models.EmailLog{Status: models.EmailLogStatusSuppressed}},
+		{name: "duplicate", log: &models.EmailLog{Status: models.EmailLogStatusSuppressedDuplicate}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &recordingPublisher{}
+			s := &emailService{events: events}
+
+			s.publishAttempt(context.Background(), tt.log)
+
+			assert.Equal(t, tt.want, events.events)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package email
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/libs/htmlmail"
+	"GoMail/app/repository/models"
+)
+
+// prepareHTML runs the HTML pipeline on a body: it inlines the CSS of
+// <style> blocks, generates a plain text part unless text is given and
+// inserts the preheader. Each step follows the request options and falls
+// back to the configured defaults. The body is returned unchanged when no
+// step modifies it.
+func (s *emailService) prepareHTML(body, text string, opts *HTMLOptions) (string, string, error) {
+	inlineCSS, generateText, preheader := s.config.HTML.InlineCSS, s.config.HTML.GenerateText, ""
+	if opts != nil {
+		if opts.InlineCSS != nil {
+			inlineCSS = *opts.InlineCSS
+		}
+		if opts.GenerateText != nil {
+			generateText = *opts.GenerateText
+		}
+		preheader = opts.Preheader
+	}
+	generateText = generateText && text == ""
+
+	if !inlineCSS && !generateText && preheader == "" {
+		return body, text, nil
+	}
+
+	doc, err := htmlmail.Parse(body)
+	if err != nil {
+		return "", "", err
+	}
+
+	if inlineCSS {
+		doc.InlineCSS()
+	}
+
+	// Generate the text after inlining, so elements hidden by a class are
+	// skipped, and before the hidden preheader is added
+	if generateText {
+		text = doc.Text() + "\n"
+	}
+
+	if inlineCSS || preheader != "" {
+		doc.AddPreheader(preheader)
+		if body, err = doc.HTML(); err != nil {
+			return "", "", err
+		}
+	}
+
+	return body, text, nil
+}
+
+// sendAlternative sends an email with an HTML body and a plain text part as
+// multipart/alternative, or persists it for the queue workers when async or
+// scheduled sending is requested
+func (s *emailService) sendAlternative(ctx context.Context, email *models.Email, async bool) (*SendEmailResponse, error) {
+	email.IsHTML = true
+	email.ContentType = "multipart/alternative"
+
+	if async || email.SendAt != nil {
+		return s.enqueue(ctx, email)
+	}
+
+	// Scan the attachments, then create a request to the SMTP client
+	ctx, messageID := s.withMessageID(ctx, email.From)
+	var scan *models.AttachmentScan
+	attachments, err := s.emailAttachments(ctx, email)
+	if err == nil {
+		scan, err = s.scanAttachments(ctx, attachments)
+	}
+	if err == nil {
+		err = s.sendTracked(ctx, email.Body, email.UserID, messageID, email.To, email.TrackOpens, email.TrackClicks, func(ctx context.Context, body string) error {
+			return s.client.SendAlternative(ctx, email.From, email.To, email.Subject, email.TextBody, body, attachments)
+		})
+	}
+
+	// Create success/error response
+	success := err == nil
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	// Create email log
+	emailLog := &models.EmailLog{
+		MessageID:   messageID,
+		UserID:      email.UserID,
+		From:        email.From,
+		To:          email.To,
+		Subject:     email.Subject,
+		ContentType: email.ContentType,
+		SentAt:      time.Now(),
+		Success:     success,
+		Error:       errMsg,
+		Scan:        scan,
+		CreatedAt:   time.Now(),
+	}
+
+	// Log the email asynchronously
+	s.logEmailAttempt(emailLog)
+
+	// Return the response
+	if err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	return &SendEmailResponse{
+		Success: true,
+	}, nil
+}
@@ -0,0 +1,152 @@
+package email
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"GoMail/app/config"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtp/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+const styledHTML = `<style>.title { color: #222 }</style><h1 class="title">Order shipped</h1><p>Track it <a href="https://example.com/t/1">here</a>.</p>`
+
+func TestEmailService_prepareHTML(t *testing.T) {
+	off := false
+
+	tests := []struct {
+		name     string
+		config   config.HTMLConfig
+		opts     *HTMLOptions
+		wantHTML []string
+		wantText string
+	}{
+		{
+			name:     "disabled",
+			wantHTML: []string{styledHTML},
+		},
+		{
+			name:     "configured defaults",
+			config:   config.HTMLConfig{InlineCSS: true, GenerateText: true},
+			wantHTML: []string{`<h1 class="title" style="color: #222">Order shipped</h1>`},
+			wantText: "Order shipped\n\nTrack it here [1].\n\n[1] https://example.com/t/1\n",
+		},
+		{
+			name:     "request switches steps off",
+			config:   config.HTMLConfig{InlineCSS: true, GenerateText: true},
+			opts:     &HTMLOptions{InlineCSS: &off, GenerateText: &off},
+			wantHTML: []string{styledHTML},
+		},
+		{
+			name:     "preheader only",
+			opts:     &HTMLOptions{Preheader: "Arrives Monday"},
+			wantHTML: []string{`<body><div style="display: none;`, "Arrives Monday", `<style>.title { color: #222 }</style>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &emailService{config: &config.Config{HTML: tt.config}}
+
+			html, text, err := s.prepareHTML(styledHTML, "", tt.opts)
+
+			require.NoError(t, err)
+			for _, want := range tt.wantHTML {
+				assert.Contains(t, html, want)
+			}
+			assert.Equal(t, tt.wantText, text)
+		})
+	}
+}
+
+func TestEmailService_prepareHTML_KeepsGivenText(t *testing.T) {
+	s := &emailService{config: &config.Config{HTML: config.HTMLConfig{GenerateText: true}}}
+
+	html, text, err := s.prepareHTML("<p>Hi</p>", "Hi there\n", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hi</p>", html)
+	assert.Equal(t, "Hi there\n", text)
+}
+
+func TestEmailService_SendHTML_Alternative(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendAlternative", mock.Anything, "sender@example.com", "recipient@example.com", "Shipped",
+		"Order shipped\n\nTrack it here [1].\n\n[1] https://example.com/t/1\n",
+		mock.MatchedBy(func(html string) bool {
+			return strings.Contains(html, `style="color: #222"`) && !strings.Contains(html, "<style")
+		}), []libSmtp.Attachment{}).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, config: &config.Config{HTML: config.HTMLConfig{InlineCSS: true, GenerateText: true}}}
+
+	got, err := s.SendHTML(context.Background(), SendEmailRequest{
+		From:    "sender@example.com",
+		To:      "recipient@example.com",
+		Subject: "Shipped",
+		Body:    styledHTML,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	client.AssertExpectations(t)
+	assert.Equal(t, "multipart/alternative", awaitLog(t, logged).ContentType)
+}
+
+func TestEmailService_SendHTML_InlineOnly(t *testing.T) {
+	off := false
+
+	client := &mocks.SMTPClient{}
+	client.On("SendHTML", mock.Anything, "sender@example.com", "recipient@example.com", "Shipped",
+		mock.MatchedBy(func(html string) bool {
+			return strings.Contains(html, `style="color: #222"`)
+		})).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, config: &config.Config{HTML: config.HTMLConfig{InlineCSS: true, GenerateText: true}}}
+
+	got, err := s.SendHTML(context.Background(), SendEmailRequest{
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Shipped",
+		Body:        styledHTML,
+		HTMLOptions: &HTMLOptions{GenerateText: &off},
+	})
+
+	awaitLog(t, logged)
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_SendBulk_HTMLAsync(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(email *models.Email) bool {
+		return email.ContentType == "multipart/alternative" && strings.HasPrefix(email.TextBody, "Order shipped")
+	})).Return(nil)
+
+	s := &emailService{client: &mocks.SMTPClient{}, repo: repo, config: &config.Config{HTML: config.HTMLConfig{GenerateText: true}}}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		Async: true,
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "b@example.com", Subject: "Shipped", Body: styledHTML, IsHTML: true},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, got.Results[0].Success)
+	repo.AssertExpectations(t)
+}
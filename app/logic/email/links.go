@@ -0,0 +1,171 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/logic/attachment"
+	"GoMail/app/logic/download"
+)
+
+// Body formats a block of download links is written in
+const (
+	linkFormatText     = "text"
+	linkFormatHTML     = "html"
+	linkFormatMarkdown = "markdown"
+)
+
+// downloadLinks issues download links to stored attachments
+type downloadLinks interface {
+	Create(ctx context.Context, userID string, req download.CreateRequest) (*download.LinkResponse, error)
+}
+
+// linksEnabled reports whether large attachments are replaced with
+// download links
+func (s *emailService) linksEnabled() bool {
+	return s.links != nil && s.stored != nil && s.config.Attachments.LinkThreshold > 0
+}
+
+// linkAttachments replaces attachments with download links when together
+// they exceed the link threshold. Inline attachments are referenced from
+// the body, so they stay attached. The linked files are scanned and stored
+// before the links are issued for the recipient. It returns the remaining
+// attachments and the links to add to the body.
+func (s *emailService) linkAttachments(ctx context.Context, userID, recipient string, attachments []libSmtp.Attachment) ([]libSmtp.Attachment, []*download.LinkResponse, error) {
+	if !s.linksEnabled() || totalSize(attachments) <= s.config.Attachments.LinkThreshold {
+		return attachments, nil, s.checkMessageSize(attachments)
+	}
+
+	kept := make([]libSmtp.Attachment, 0, len(attachments))
+	linked := make([]libSmtp.Attachment, 0, len(attachments))
+	for _, att := range attachments {
+		if att.ContentID != "" {
+			kept = append(kept, att)
+		} else {
+			linked = append(linked, att)
+		}
+	}
+	if err := s.checkMessageSize(kept); err != nil {
+		return nil, nil, err
+	}
+
+	// Linked files leave the message but are still delivered, so they are
+	// scanned like attachments
+	if _, err := s.scanAttachments(ctx, linked); err != nil {
+		return nil, nil, err
+	}
+
+	links := make([]*download.LinkResponse, 0, len(linked))
+	for _, att := range linked {
+		stored, err := s.stored.Upload(ctx, userID, attachment.UploadRequest{
+			Filename: att.Filename,
+			MimeType: att.MimeType,
+			Size:     att.Len(),
+			Open:     att.Reader,
+			TTL:      s.config.Attachments.LinkTTL,
+		})
+		if err != nil {
+			if errors.Is(err, attachment.ErrAttachmentTooLarge) {
+				return nil, nil, fmt.Errorf("%w: %v", ErrAttachmentTooLarge, err)
+			}
+			return nil, nil, fmt.Errorf("failed to store %s: %w", att.Filename, err)
+		}
+
+		link, err := s.links.Create(ctx, userID, download.CreateRequest{
+			AttachmentID: stored.ID,
+			Filename:     att.Filename,
+			Recipient:    recipient,
+			TTL:          s.config.Attachments.LinkTTL,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create a download link for %s: %w", att.Filename, err)
+		}
+		links = append(links, link)
+	}
+
+	return kept, links, nil
+}
+
+// checkMessageSize enforces the size limit of the attachments sent with an
+// email
+func (s *emailService) checkMessageSize(attachments []libSmtp.Attachment) error {
+	if limit := s.config.Attachments.MaxMessageSize; limit > 0 && totalSize(attachments) > limit {
+		return fmt.Errorf("%w: attachments exceed %d bytes", ErrAttachmentTooLarge, limit)
+	}
+	return nil
+}
+
+// totalSize returns the size of the content of attachments
+func totalSize(attachments []libSmtp.Attachment) int64 {
+	var total int64
+	for _, att := range attachments {
+		total += att.Len()
+	}
+	return total
+}
+
+// withLinks adds a block listing download links to the end of a body in
+// the given format. HTML blocks go before the closing body tag.
+func withLinks(body, format string, links []*download.LinkResponse) string {
+	if len(links) == 0 {
+		return body
+	}
+
+	intro := fmt.Sprintf("The following files are available for download until %s:",
+		links[0].ExpiresAt.UTC().Format("2 January 2006 15:04 MST"))
+
+	var b strings.Builder
+	switch format {
+	case linkFormatHTML:
+		b.WriteString(`<div class="download-links"><p>` + html.EscapeString(intro) + "</p><ul>")
+		for _, link := range links {
+			fmt.Fprintf(&b, `<li><a href="%s">%s</a> (%s)</li>`, html.EscapeString(link.URL), html.EscapeString(link.Filename), formatSize(link.Size))
+		}
+		b.WriteString("</ul></div>")
+
+		if i := strings.LastIndex(strings.ToLower(body), "</body"); i >= 0 {
+			return body[:i] + b.String() + body[i:]
+		}
+		return body + b.String()
+	case linkFormatMarkdown:
+		b.WriteString("\n\n" + intro + "\n\n")
+		for _, link := range links {
+			fmt.Fprintf(&b, "- [%s](%s) (%s)\n", escapeMarkdown(link.Filename), link.URL, formatSize(link.Size))
+		}
+	default:
+		b.WriteString("\n\n" + intro + "\n")
+		for _, link := range links {
+			fmt.Fprintf(&b, "\n%s (%s)\n%s\n", link.Filename, formatSize(link.Size), link.URL)
+		}
+	}
+
+	return strings.TrimRight(body, "\n") + b.String()
+}
+
+// markdownEscaper escapes the characters with a meaning in link texts
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`, "`", "\\`", "<", `\<`,
+)
+
+// escapeMarkdown escapes a file name for a Markdown link text
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// formatSize formats a size in bytes for people
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exp := float64(size)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGT"[exp])
+}
@@ -0,0 +1,227 @@
+package email
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"GoMail/app/config"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtp/mocks"
+	"GoMail/app/logic/download"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+var linkExpiry = time.Date(2026, 10, 25, 14, 0, 0, 0, time.UTC)
+
+// fakeLinks issues links named after the stored attachment and recipient
+type fakeLinks struct {
+	created []download.CreateRequest
+}
+
+func (f *fakeLinks) Create(ctx context.Context, userID string, req download.CreateRequest) (*download.LinkResponse, error) {
+	f.created = append(f.created, req)
+	return &download.LinkResponse{
+		URL:       "https://mail.example.com/files/" + req.AttachmentID + "-" + req.Recipient,
+		Filename:  req.Filename,
+		Size:      3 << 20,
+		ExpiresAt: linkExpiry,
+	}, nil
+}
+
+// linkConfig replaces attachments larger than 1 MB with links
+func linkConfig() *config.Config {
+	return &config.Config{Attachments: config.AttachmentConfig{
+		MaxMessageSize: 2 << 20,
+		LinkThreshold:  1 << 20,
+		LinkTTL:        7 * 24 * time.Hour,
+	}}
+}
+
+// bigAttachment streams size bytes
+func bigAttachment(filename string, size int) libSmtp.Attachment {
+	return libSmtp.Attachment{Filename: filename, MimeType: "application/pdf", Size: int64(size), Open: openString(strings.Repeat("x", size))}
+}
+
+func TestEmailService_SendWithAttachments_Links(t *testing.T) {
+	logo := libSmtp.Attachment{Filename: "logo.png", Content: []byte("png"), MimeType: "image/png", ContentID: "logo"}
+
+	client := &mocks.SMTPClient{}
+	client.On("SendWithAttachments", mock.Anything, "sender@example.com", "recipient@example.com", "Report",
+		mock.MatchedBy(func(body string) bool {
+			return strings.HasPrefix(body, "See the report\n\nThe following files are available for download until 25 October 2026 14:00 UTC:\n") &&
+				strings.Contains(body, "\nreport.pdf (3.0 MB)\nhttps://mail.example.com/files/stored-report.pdf-recipient@example.com\n")
+		}),
+		[]libSmtp.Attachment{logo}).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	links := &fakeLinks{}
+	s := &emailService{client: client, repo: repo, stored: fakeStore{}, links: links, config: linkConfig()}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		UserID:      "user-1",
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Report",
+		Body:        "See the report\n",
+		Attachments: []libSmtp.Attachment{logo, bigAttachment("report.pdf", 3<<20)},
+	})
+
+	awaitLog(t, logged)
+
+	require.NoError(t, err)
+	assert.True(t, got.Success)
+	require.Len(t, links.created, 1)
+	assert.Equal(t, download.CreateRequest{AttachmentID: "stored-report.pdf", Filename: "report.pdf", Recipient: "recipient@example.com", TTL: 7 * 24 * time.Hour}, links.created[0])
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_SendWithAttachments_BelowLinkThreshold(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendWithAttachments", mock.Anything, "sender@example.com", "recipient@example.com", "Report", "See the report",
+		mock.MatchedBy(func(attachments []libSmtp.Attachment) bool { return len(attachments) == 1 })).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	links := &fakeLinks{}
+	s := &emailService{client: client, repo: repo, stored: fakeStore{}, links: links, config: linkConfig()}
+
+	_, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		UserID:      "user-1",
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Report",
+		Body:        "See the report",
+		Attachments: []libSmtp.Attachment{bigAttachment("report.pdf", 1<<20)},
+	})
+
+	awaitLog(t, logged)
+
+	require.NoError(t, err)
+	assert.Empty(t, links.created)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_SendWithAttachments_LinksAsync(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(email *models.Email) bool {
+		return len(email.Attachments) == 0 && len(email.AttachmentIDs) == 0 &&
+			strings.Contains(email.Body, "https://mail.example.com/files/stored-terms.txt-recipient@example.com")
+	})).Return(nil)
+
+	s := &emailService{client: &mocks.SMTPClient{}, repo: repo, stored: fakeStore{"terms": strings.Repeat("t", 3<<20)}, links: &fakeLinks{}, config: linkConfig()}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		UserID:        "user-1",
+		From:          "sender@example.com",
+		To:            "recipient@example.com",
+		Subject:       "Terms",
+		Body:          "Attached",
+		Async:         true,
+		AttachmentIDs: []string{"terms"},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, got.Success)
+	repo.AssertExpectations(t)
+}
+
+func TestEmailService_SendWithAttachments_LinksInfected(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	links := &fakeLinks{}
+	s := &emailService{client: client, stored: fakeStore{}, links: links, scanner: fakeScanner{}, config: linkConfig()}
+
+	_, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		UserID:  "user-1",
+		From:    "sender@example.com",
+		To:      "recipient@example.com",
+		Subject: "Report",
+		Body:    "See the report",
+		Attachments: []libSmtp.Attachment{
+			bigAttachment("report.pdf", 3<<20),
+			{Filename: "invoice.pdf", Content: []byte("EICAR")},
+		},
+	})
+
+	assert.ErrorIs(t, err, ErrAttachmentInfected)
+	assert.Empty(t, links.created)
+	client.AssertNotCalled(t, "SendWithAttachments")
+}
+
+func TestEmailService_SendWithAttachments_InlineTooLarge(t *testing.T) {
+	inline := bigAttachment("banner.png", 3<<20)
+	inline.ContentID = "banner"
+	s := &emailService{client: &mocks.SMTPClient{}, stored: fakeStore{}, links: &fakeLinks{}, config: linkConfig()}
+
+	_, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		UserID:      "user-1",
+		To:          "recipient@example.com",
+		Attachments: []libSmtp.Attachment{inline},
+	})
+
+	assert.ErrorIs(t, err, ErrAttachmentTooLarge)
+}
+
+func TestEmailService_SendBulk_LinksHTML(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendAlternative", mock.Anything, "a@example.com", "b@example.com", "Hi",
+		mock.MatchedBy(func(text string) bool {
+			return strings.Contains(text, "https://mail.example.com/files/stored-report.pdf-b@example.com")
+		}),
+		mock.MatchedBy(func(body string) bool {
+			return strings.Contains(body, `<a href="https://mail.example.com/files/stored-report.pdf-b@example.com">report.pdf</a> (3.0 MB)</li></ul></div></body>`)
+		}),
+		[]libSmtp.Attachment{}).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	cfg := linkConfig()
+	cfg.HTML.GenerateText = true
+	s := &emailService{client: client, repo: repo, stored: fakeStore{}, links: &fakeLinks{}, config: cfg}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		UserID: "user-1",
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "b@example.com", Subject: "Hi", Body: "<html><body><p>Report</p></body></html>", IsHTML: true,
+				Attachments: []libSmtp.Attachment{bigAttachment("report.pdf", 3<<20)}},
+		},
+	})
+
+	require.NoError(t, err)
+	for range got.Results {
+		awaitLog(t, logged)
+	}
+	assert.True(t, got.Results[0].Success, got.Results[0].Error)
+	client.AssertExpectations(t)
+}
+
+func TestWithLinks(t *testing.T) {
+	links := []*download.LinkResponse{
+		{URL: "https://mail.example.com/files/a?b&c", Filename: "q3_[final].pdf", Size: 1536, ExpiresAt: linkExpiry},
+	}
+
+	assert.Equal(t, "Hi\n\nThe following files are available for download until 25 October 2026 14:00 UTC:\n\nq3_[final].pdf (1.5 KB)\nhttps://mail.example.com/files/a?b&c\n",
+		withLinks("Hi\n", linkFormatText, links))
+	assert.Equal(t, "Hi\n\nThe following files are available for download until 25 October 2026 14:00 UTC:\n\n- [q3\\_\\[final\\].pdf](https://mail.example.com/files/a?b&c) (1.5 KB)\n",
+		withLinks("Hi", linkFormatMarkdown, links))
+	assert.Equal(t, `<p>Hi</p><div class="download-links"><p>The following files are available for download until 25 October 2026 14:00 UTC:</p><ul><li><a href="https://mail.example.com/files/a?b&amp;c">q3_[final].pdf</a> (1.5 KB)</li></ul></div></BODY>`,
+		withLinks("<p>Hi</p></BODY>", linkFormatHTML, links))
+	assert.Equal(t, "Hi", withLinks("Hi", linkFormatText, nil))
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.0 KB", formatSize(1024))
+	assert.Equal(t, "30.2 MB", formatSize(31666995))
+	assert.Equal(t, "2.0 GB", formatSize(2<<30))
+}
@@ -0,0 +1,101 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+
+	"GoMail/app/libs/markdown"
+	"GoMail/app/repository/models"
+)
+
+// FormatMarkdown marks a body written in Markdown
+const FormatMarkdown = "markdown"
+
+// defaultLayout wraps rendered Markdown when no layout file is configured
+var defaultLayout = template.Must(template.New("layout").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Subject}}</title>
+</head>
+<body>
+{{.Content}}
+</body>
+</html>
+`))
+
+// layoutData is passed to the Markdown layout template
+type layoutData struct {
+	Subject string
+	Content template.HTML
+}
+
+// loadLayout parses the configured layout file, falling back to the
+// built-in layout when none is configured
+func loadLayout(path string) (*template.Template, error) {
+	if path == "" {
+		return defaultLayout, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read markdown layout: %w", err)
+	}
+
+	layout, err := template.New("layout").Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse markdown layout: %w", err)
+	}
+	return layout, nil
+}
+
+// validateFormat checks the body format of a send request
+func validateFormat(format string) error {
+	if format != "" && format != FormatMarkdown {
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidFormat, format)
+	}
+	return nil
+}
+
+// renderMarkdown renders a Markdown body to an HTML part embedded in the
+// layout and a plain text part
+func (s *emailService) renderMarkdown(subject, source string) (string, string, error) {
+	layout := s.layout
+	if layout == nil {
+		layout = defaultLayout
+	}
+
+	doc := markdown.Parse(source)
+
+	var html bytes.Buffer
+	// The renderer escapes raw HTML, so its output is safe to embed as is
+	if err := layout.Execute(&html, layoutData{Subject: subject, Content: template.HTML(doc.HTML())}); err != nil {
+		return "", "", fmt.Errorf("failed to render markdown layout: %w", err)
+	}
+
+	return html.String(), doc.Text() + "\n", nil
+}
+
+// sendMarkdown renders the Markdown body of an email, runs the HTML
+// pipeline on the result and sends it as multipart/alternative
+func (s *emailService) sendMarkdown(ctx context.Context, email *models.Email, opts *HTMLOptions, async bool) (*SendEmailResponse, error) {
+	html, text, err := s.renderMarkdown(email.Subject, email.Body)
+	if err == nil {
+		html, text, err = s.prepareHTML(html, text, opts)
+	}
+	if err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	email.Body, email.TextBody = html, text
+	email.TrackOpens = s.tracksOpens(opts)
+	email.TrackClicks = s.tracksClicks(opts)
+	return s.sendAlternative(ctx, email, async)
+}
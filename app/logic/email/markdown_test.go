@@ -0,0 +1,152 @@
+package email
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"GoMail/app/config"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtp/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+const markdownHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Welcome &amp; hello</title>
+</head>
+<body>
+<h1>Hi <em>Ada</em></h1>
+<p>Read the <a href="https://example.com/docs">docs</a>.</p>
+
+</body>
+</html>
+`
+
+const markdownText = "Hi Ada\n======\n\nRead the docs (https://example.com/docs).\n"
+
+func TestEmailService_Send_Markdown(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendAlternative", mock.Anything, "sender@example.com", "recipient@example.com", "Welcome & hello",
+		markdownText, markdownHTML, []libSmtp.Attachment{}).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, config: &config.Config{}}
+
+	got, err := s.Send(context.Background(), SendEmailRequest{
+		From:    "sender@example.com",
+		To:      "recipient@example.com",
+		Subject: "Welcome & hello",
+		Body:    "# Hi *Ada*\n\nRead the [docs](https://example.com/docs).",
+		Format:  FormatMarkdown,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	client.AssertExpectations(t)
+	log := awaitLog(t, logged)
+	assert.Equal(t, "multipart/alternative", log.ContentType)
+	assert.True(t, log.Success)
+}
+
+func TestEmailService_Send_InvalidFormat(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	s := &emailService{client: client, config: &config.Config{}}
+
+	got, err := s.SendHTML(context.Background(), SendEmailRequest{
+		From:   "sender@example.com",
+		To:     "recipient@example.com",
+		Body:   "hello",
+		Format: "rst",
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+	assert.False(t, got.Success)
+	client.AssertNotCalled(t, "SendHTML", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailService_SendWithAttachments_MarkdownAsync(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(email *models.Email) bool {
+		return email.ContentType == "multipart/alternative" &&
+			email.IsHTML &&
+			email.Body == markdownHTML &&
+			email.TextBody == markdownText &&
+			len(email.Attachments) == 1
+	})).Return(nil)
+
+	s := &emailService{client: &mocks.SMTPClient{}, repo: repo, config: &config.Config{}}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Welcome & hello",
+		Body:        "# Hi *Ada*\n\nRead the [docs](https://example.com/docs).",
+		Attachments: []libSmtp.Attachment{{Filename: "a.txt", MimeType: "text/plain", Content: []byte("a")}},
+		Async:       true,
+		Format:      FormatMarkdown,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	repo.AssertExpectations(t)
+}
+
+func TestEmailService_SendBulk_Markdown(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendAlternative", mock.Anything, "a@example.com", "b@example.com", "Welcome & hello",
+		markdownText, markdownHTML, []libSmtp.Attachment(nil)).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, config: &config.Config{}}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "b@example.com", Subject: "Welcome & hello", Body: "# Hi *Ada*\n\nRead the [docs](https://example.com/docs).", Format: FormatMarkdown},
+			{From: "a@example.com", To: "c@example.com", Body: "hello", Format: "textile"},
+		},
+	})
+
+	assert.NoError(t, err)
+	// Emails rejected before sending aren't logged
+	awaitLog(t, logged)
+	assert.True(t, got.Results[0].Success)
+	assert.False(t, got.Results[1].Success)
+	assert.Contains(t, got.Results[1].Error, "unsupported format")
+	client.AssertExpectations(t)
+}
+
+func TestLoadLayout(t *testing.T) {
+	layout, err := loadLayout("")
+	require.NoError(t, err)
+	assert.Same(t, defaultLayout, layout)
+
+	path := filepath.Join(t.TempDir(), "layout.html")
+	require.NoError(t, os.WriteFile(path, []byte(`<div class="mail"><h6>{{.Subject}}</h6>{{.Content}}</div>`), 0o644))
+
+	layout, err = loadLayout(path)
+	require.NoError(t, err)
+
+	s := &emailService{layout: layout}
+	html, text, err := s.renderMarkdown("<Hi>", "**bold** <b>")
+	require.NoError(t, err)
+	assert.Equal(t, `<div class="mail"><h6>&lt;Hi&gt;</h6><p><strong>bold</strong> &lt;b&gt;</p>
+</div>`, html)
+	assert.Equal(t, "bold <b>\n", text)
+
+	_, err = loadLayout(filepath.Join(t.TempDir(), "missing.html"))
+	assert.Error(t, err)
+}
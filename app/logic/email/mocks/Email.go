@@ -3,9 +3,14 @@
 package mocks
 
 import (
-	context "context"
 	email "GoMail/app/logic/email"
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
+
+	models "GoMail/app/repository/models"
+
+	time "time"
 )
 
 // Email is an autogenerated mock type for the Email type
@@ -13,13 +18,142 @@ type Email struct {
 	mock.Mock
 }
 
+// CancelScheduled provides a mock function with given fields: ctx, userID, id
+func (_m *Email) CancelScheduled(ctx context.Context, userID string, id string) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduled")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Deliver provides a mock function with given fields: ctx, _a1
+func (_m *Email) Deliver(ctx context.Context, _a1 *models.Email) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deliver")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Email) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetStatus provides a mock function with given fields: ctx, userID, id
+func (_m *Email) GetStatus(ctx context.Context, userID string, id string) (*email.EmailStatusResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatus")
+	}
+
+	var r0 *email.EmailStatusResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*email.EmailStatusResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *email.EmailStatusResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*email.EmailStatusResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListScheduled provides a mock function with given fields: ctx, userID, page, limit
+func (_m *Email) ListScheduled(ctx context.Context, userID string, page int, limit int) (*email.ListScheduledResponse, error) {
+	ret := _m.Called(ctx, userID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScheduled")
+	}
+
+	var r0 *email.ListScheduledResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*email.ListScheduledResponse, error)); ok {
+		return rf(ctx, userID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *email.ListScheduledResponse); ok {
+		r0 = rf(ctx, userID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*email.ListScheduledResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, userID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reschedule provides a mock function with given fields: ctx, userID, id, sendAt
+func (_m *Email) Reschedule(ctx context.Context, userID string, id string, sendAt time.Time) (*email.EmailStatusResponse, error) {
+	ret := _m.Called(ctx, userID, id, sendAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reschedule")
+	}
+
+	var r0 *email.EmailStatusResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*email.EmailStatusResponse, error)); ok {
+		return rf(ctx, userID, id, sendAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *email.EmailStatusResponse); ok {
+		r0 = rf(ctx, userID, id, sendAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*email.EmailStatusResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, id, sendAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Send provides a mock function with given fields: ctx, req
 func (_m *Email) Send(ctx context.Context, req email.SendEmailRequest) (*email.SendEmailResponse, error) {
 	ret := _m.Called(ctx, req)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
 	var r0 *email.SendEmailResponse
 	var r1 error
-
 	if rf, ok := ret.Get(0).(func(context.Context, email.SendEmailRequest) (*email.SendEmailResponse, error)); ok {
 		return rf(ctx, req)
 	}
@@ -44,9 +178,12 @@ func (_m *Email) Send(ctx context.Context, req email.SendEmailRequest) (*email.S
 func (_m *Email) SendBulk(ctx context.Context, req email.SendBulkEmailRequest) (*email.SendBulkEmailResponse, error) {
 	ret := _m.Called(ctx, req)
 
+	if len(ret) == 0 {
+		panic("no return value specified for SendBulk")
+	}
+
 	var r0 *email.SendBulkEmailResponse
 	var r1 error
-
 	if rf, ok := ret.Get(0).(func(context.Context, email.SendBulkEmailRequest) (*email.SendBulkEmailResponse, error)); ok {
 		return rf(ctx, req)
 	}
@@ -71,9 +208,12 @@ func (_m *Email) SendBulk(ctx context.Context, req email.SendBulkEmailRequest) (
 func (_m *Email) SendHTML(ctx context.Context, req email.SendEmailRequest) (*email.SendEmailResponse, error) {
 	ret := _m.Called(ctx, req)
 
+	if len(ret) == 0 {
+		panic("no return value specified for SendHTML")
+	}
+
 	var r0 *email.SendEmailResponse
 	var r1 error
-
 	if rf, ok := ret.Get(0).(func(context.Context, email.SendEmailRequest) (*email.SendEmailResponse, error)); ok {
 		return rf(ctx, req)
 	}
@@ -94,13 +234,46 @@ func (_m *Email) SendHTML(ctx context.Context, req email.SendEmailRequest) (*ema
 	return r0, r1
 }
 
+// SendInvite provides a mock function with given fields: ctx, req
+func (_m *Email) SendInvite(ctx context.Context, req email.SendInviteRequest) (*email.SendInviteResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendInvite")
+	}
+
+	var r0 *email.SendInviteResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, email.SendInviteRequest) (*email.SendInviteResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, email.SendInviteRequest) *email.SendInviteResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*email.SendInviteResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, email.SendInviteRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendWithAttachments provides a mock function with given fields: ctx, req
 func (_m *Email) SendWithAttachments(ctx context.Context, req email.SendWithAttachmentsRequest) (*email.SendEmailResponse, error) {
 	ret := _m.Called(ctx, req)
 
+	if len(ret) == 0 {
+		panic("no return value specified for SendWithAttachments")
+	}
+
 	var r0 *email.SendEmailResponse
 	var r1 error
-
 	if rf, ok := ret.Get(0).(func(context.Context, email.SendWithAttachmentsRequest) (*email.SendEmailResponse, error)); ok {
 		return rf(ctx, req)
 	}
@@ -121,7 +294,16 @@ func (_m *Email) SendWithAttachments(ctx context.Context, req email.SendWithAtta
 	return r0, r1
 }
 
-// NewEmail creates a new instance of Email
-func NewEmail() *Email {
-	return &Email{}
-} 
\ No newline at end of file
+// NewEmail creates a new instance of Email. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmail(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Email {
+	mock := &Email{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,281 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	libSmtp "GoMail/app/libs/smtp"
+	emailRepo "GoMail/app/repository/email"
+	"GoMail/app/repository/models"
+)
+
+// deliveredRetention is how long the Message-ID of a delivered email stays
+// claimed. It outlasts any lease, so the email is recorded as sent before a
+// worker could take it over again.
+const deliveredRetention = 7 * 24 * time.Hour
+
+// enqueue persists an email as pending so a queue worker delivers it later.
+// Emails with a future SendAt are held as scheduled until the scheduler
+// releases them; a SendAt in the past is delivered right away.
+func (s *emailService) enqueue(ctx context.Context, email *models.Email) (*SendEmailResponse, error) {
+	now := time.Now()
+	email.Status = models.EmailStatusPending
+	email.NextAttemptAt = now
+	email.MaxAttempts = s.config.Queue.MaxAttempts
+	email.MessageID = s.newMessageID(email.From)
+
+	if email.SendAt != nil && email.SendAt.After(now) {
+		email.Status = models.EmailStatusScheduled
+		email.NextAttemptAt = *email.SendAt
+	} else {
+		email.SendAt = nil
+	}
+
+	if err := s.repo.SaveEmail(ctx, email); err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	return &SendEmailResponse{
+		Success: true,
+		ID:      email.ID.Hex(),
+		Status:  string(email.Status),
+	}, nil
+}
+
+// GetStatus returns the delivery state of an email persisted for async sending
+func (s *emailService) GetStatus(ctx context.Context, userID, id string) (*EmailStatusResponse, error) {
+	stored, err := s.repo.FindEmailByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, emailRepo.ErrEmailNotFound) || errors.Is(err, emailRepo.ErrInvalidID) {
+			return nil, ErrEmailNotFound
+		}
+		return nil, err
+	}
+
+	// Emails are only visible to the user that queued them
+	if stored.UserID != userID {
+		return nil, ErrEmailNotFound
+	}
+
+	resp := &EmailStatusResponse{
+		ID:        stored.ID.Hex(),
+		Status:    string(stored.Status),
+		Attempts:  stored.Attempts,
+		SendAt:    stored.SendAt,
+		SentAt:    stored.SentAt,
+		Error:     stored.Error,
+		CreatedAt: stored.CreatedAt,
+	}
+	if stored.Status == models.EmailStatusPending {
+		resp.NextAttemptAt = &stored.NextAttemptAt
+	}
+
+	return resp, nil
+}
+
+// Deliver sends an email persisted in the outbound queue and logs the attempt.
+// Recipients suppressed since the email was queued are left out. Every
+// attempt sends the Message-ID assigned when the email was queued, and an
+// email whose Message-ID the SMTP server already accepted isn't sent again.
+func (s *emailService) Deliver(ctx context.Context, email *models.Email) error {
+	// Emails queued before Message-IDs were stored get one on first delivery
+	if email.MessageID == "" {
+		email.MessageID = s.newMessageID(email.From)
+	}
+
+	// Topics deleted since the email was queued no longer apply
+	topic, err := s.findTopic(ctx, email.UserID, email.Topic)
+	if err != nil && !errors.Is(err, ErrInvalidTopic) {
+		return err
+	}
+	to, suppressed, err := s.filterSuppressed(ctx, email.UserID, email.To, email.List, topic)
+	if err != nil {
+		return err
+	}
+	if to == "" && len(suppressed) > 0 {
+		emailID := email.ID
+		s.logSuppressedRecipients(email.UserID, &emailID, email.From, email.To, email.Subject, email.ContentType, suppressed)
+		return fmt.Errorf("%w: %s", ErrRecipientSuppressed, suppressedError(suppressed))
+	}
+	if to != email.To {
+		filtered := *email
+		filtered.To = to
+		email = &filtered
+	}
+
+	var attachments []libSmtp.Attachment
+	var scan *models.AttachmentScan
+
+	// A worker that lost its lease may have had the email accepted already
+	claim, claimed, err := s.claimDelivery(ctx, email)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		log.Printf("Email %s was already sent as %s, not sending it again", email.ID.Hex(), email.MessageID)
+		return nil
+	}
+
+	// Send the email based on its type
+	messageID := email.MessageID
+	ctx = libSmtp.WithMessageID(ctx, messageID)
+	if email.Unsubscribe {
+		ctx = s.withUnsubscribe(ctx, email.UserID, messageID, email.To, email.List)
+	}
+	switch email.ContentType {
+	case "text/html":
+		err = s.sendTracked(ctx, email.Body, email.UserID, messageID, email.To, email.TrackOpens, email.TrackClicks, func(ctx context.Context, body string) error {
+			return s.client.SendHTML(ctx, email.From, email.To, email.Subject, body)
+		})
+	case "multipart/mixed":
+		if attachments, err = s.emailAttachments(ctx, email); err == nil {
+			scan, err = s.scanAttachments(ctx, attachments)
+		}
+		if err == nil && email.IsHTML {
+			// An HTML body without a text part is sent next to the attachments
+			err = s.sendTracked(ctx, email.Body, email.UserID, messageID, email.To, email.TrackOpens, email.TrackClicks, func(ctx context.Context, body string) error {
+				return s.client.SendAlternative(ctx, email.From, email.To, email.Subject, "", body, attachments)
+			})
+		} else if err == nil {
+			err = s.client.SendWithAttachments(ctx, email.From, email.To, email.Subject, email.Body, attachments)
+		}
+	case "multipart/alternative":
+		if attachments, err = s.emailAttachments(ctx, email); err == nil {
+			scan, err = s.scanAttachments(ctx, attachments)
+		}
+		if err == nil {
+			err = s.sendTracked(ctx, email.Body, email.UserID, messageID, email.To, email.TrackOpens, email.TrackClicks, func(ctx context.Context, body string) error {
+				return s.client.SendAlternative(ctx, email.From, email.To, email.Subject, email.TextBody, body, attachments)
+			})
+		}
+	case "text/calendar":
+		if email.Calendar == nil {
+			err = errors.New("queued invitation has no calendar content")
+			break
+		}
+		invite := libSmtp.CalendarInvite{
+			Method:  email.Calendar.Method,
+			Content: email.Calendar.Content,
+		}
+		err = s.client.SendCalendarInvite(ctx, email.From, email.To, email.Subject, email.Body, invite)
+	default:
+		err = s.client.Send(ctx, email.From, email.To, email.Subject, email.Body)
+	}
+
+	s.settleDelivery(claim, err)
+
+	// Create success/error response
+	success := err == nil
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	// Create email log linked to the queued email
+	emailID := email.ID
+	emailLog := &models.EmailLog{
+		EmailID:     &emailID,
+		MessageID:   messageID,
+		UserID:      email.UserID,
+		From:        email.From,
+		To:          email.To,
+		Subject:     email.Subject,
+		ContentType: email.ContentType,
+		SentAt:      time.Now(),
+		Success:     success,
+		Error:       errMsg,
+		Scan:        scan,
+		CreatedAt:   time.Now(),
+	}
+
+	// Log the email asynchronously
+	s.logEmailAttempt(emailLog)
+
+	return err
+}
+
+// claimDelivery claims the Message-ID of a queued email before it is sent.
+// The claim lasts until the lease of the worker ends and is kept for
+// deliveredRetention once the SMTP server accepted the email, so a worker
+// that takes over an expired lease doesn't send it again. It reports false
+// when the Message-ID is claimed already.
+func (s *emailService) claimDelivery(ctx context.Context, email *models.Email) (*models.SendFingerprint, bool, error) {
+	// MongoDB keeps milliseconds, and releases match the expiry exactly
+	now := time.Now().Truncate(time.Millisecond)
+	expiresAt := now.Add(s.config.Queue.LeaseDuration)
+	if deadline, ok := ctx.Deadline(); ok {
+		expiresAt = deadline.Truncate(time.Millisecond)
+	}
+
+	claim := &models.SendFingerprint{
+		UserID:    email.UserID,
+		Hash:      deliveryHash(email.MessageID),
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+
+	claimed, err := s.repo.ClaimSendFingerprint(ctx, claim)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to claim message %s: %w", email.MessageID, err)
+	}
+
+	return claim, claimed, nil
+}
+
+// settleDelivery keeps the claim of a delivery the SMTP server accepted and
+// gives it up when the attempt failed, so the email can be retried
+func (s *emailService) settleDelivery(claim *models.SendFingerprint, sendErr error) {
+	if sendErr != nil {
+		s.releaseSend(claim)
+		return
+	}
+
+	// The send context may be past its deadline already
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.repo.ExtendSendFingerprint(ctx, claim, claim.CreatedAt.Add(deliveredRetention)); err != nil {
+		log.Printf("WARNING: Failed to keep delivery claim %s: %v", claim.Hash, err)
+	}
+}
+
+// deliveryHash returns the fingerprint hash a Message-ID is claimed with.
+// The prefix keeps it apart from the content hashes of the dedup window.
+func deliveryHash(messageID string) string {
+	return "message-id:" + messageID
+}
+
+// toEmailAttachments converts SMTP attachments for storage with a queued email
+func toEmailAttachments(attachments []libSmtp.Attachment) []models.EmailAttachment {
+	stored := make([]models.EmailAttachment, 0, len(attachments))
+	for _, att := range attachments {
+		stored = append(stored, models.EmailAttachment{
+			Filename:  att.Filename,
+			MimeType:  att.MimeType,
+			ContentID: att.ContentID,
+			Content:   att.Content,
+		})
+	}
+	return stored
+}
+
+// fromEmailAttachments converts the attachments stored with a queued email
+// back to SMTP attachments
+func fromEmailAttachments(stored []models.EmailAttachment) []libSmtp.Attachment {
+	attachments := make([]libSmtp.Attachment, 0, len(stored))
+	for _, att := range stored {
+		attachments = append(attachments, libSmtp.Attachment{
+			Filename:  att.Filename,
+			Content:   att.Content,
+			MimeType:  att.MimeType,
+			ContentID: att.ContentID,
+		})
+	}
+	return attachments
+}
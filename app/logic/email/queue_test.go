@@ -0,0 +1,238 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"GoMail/app/config"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtp/mocks"
+	emailRepo "GoMail/app/repository/email"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+func TestEmailService_SendAsync(t *testing.T) {
+	queuedID := primitive.NewObjectID()
+
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(e *models.Email) bool {
+		return e.Status == models.EmailStatusPending &&
+			e.UserID == "user-1" &&
+			e.ContentType == "text/plain" &&
+			e.MaxAttempts == 5 &&
+			e.MessageID != ""
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Email).ID = queuedID
+	}).Return(nil)
+
+	client := &mocks.SMTPClient{}
+	s := &emailService{
+		client: client,
+		repo:   repo,
+		config: &config.Config{Queue: config.QueueConfig{MaxAttempts: 5}},
+	}
+
+	req := validSendEmailRequest
+	req.UserID = "user-1"
+	req.Async = true
+
+	got, err := s.Send(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &SendEmailResponse{Success: true, ID: queuedID.Hex(), Status: "pending"}, got)
+	repo.AssertExpectations(t)
+	client.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailService_SendAsync_SaveError(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.AnythingOfType("*models.Email")).Return(errors.New("db error"))
+
+	s := &emailService{
+		client: &mocks.SMTPClient{},
+		repo:   repo,
+		config: &config.Config{},
+	}
+
+	req := validSendEmailRequest
+	req.Async = true
+
+	got, err := s.Send(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Equal(t, &SendEmailResponse{Success: false, Error: "db error"}, got)
+}
+
+func TestEmailService_Deliver(t *testing.T) {
+	attachment := libSmtp.Attachment{Filename: "a.txt", Content: []byte("a"), MimeType: "text/plain"}
+
+	tests := []struct {
+		name   string
+		email  *models.Email
+		expect func(client *mocks.SMTPClient)
+	}{
+		{
+			name:  "plain text",
+			email: &models.Email{ContentType: "text/plain", From: "f", To: "t", Subject: "s", Body: "b"},
+			expect: func(client *mocks.SMTPClient) {
+				client.On("Send", mock.Anything, "f", "t", "s", "b").Return(nil)
+			},
+		},
+		{
+			name:  "html",
+			email: &models.Email{ContentType: "text/html", From: "f", To: "t", Subject: "s", Body: "<b>b</b>"},
+			expect: func(client *mocks.SMTPClient) {
+				client.On("SendHTML", mock.Anything, "f", "t", "s", "<b>b</b>").Return(nil)
+			},
+		},
+		{
+			name: "attachments",
+			email: &models.Email{ContentType: "multipart/mixed", From: "f", To: "t", Subject: "s", Body: "b",
+				Attachments: []models.EmailAttachment{{Filename: "a.txt", Content: []byte("a"), MimeType: "text/plain"}}},
+			expect: func(client *mocks.SMTPClient) {
+				client.On("SendWithAttachments", mock.Anything, "f", "t", "s", "b", []libSmtp.Attachment{attachment}).Return(nil)
+			},
+		},
+		{
+			name: "calendar",
+			email: &models.Email{ContentType: "text/calendar", From: "f", To: "t", Subject: "s", Body: "b",
+				Calendar: &models.EmailCalendar{Method: "REQUEST", Content: []byte("ics")}},
+			expect: func(client *mocks.SMTPClient) {
+				client.On("SendCalendarInvite", mock.Anything, "f", "t", "s", "b",
+					libSmtp.CalendarInvite{Method: "REQUEST", Content: []byte("ics")}).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.SMTPClient{}
+			tt.expect(client)
+
+			repo := &repoMocks.Repository{}
+			claimDeliveries(repo)
+			logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+			s := &emailService{client: client, repo: repo, config: &config.Config{}}
+			tt.email.ID = primitive.NewObjectID()
+
+			err := s.Deliver(context.Background(), tt.email)
+
+			assert.NoError(t, err)
+			client.AssertExpectations(t)
+			log := awaitLog(t, logged)
+			if assert.NotNil(t, log.EmailID) {
+				assert.Equal(t, tt.email.ID, *log.EmailID)
+			}
+			assert.Equal(t, tt.email.ContentType, log.ContentType)
+		})
+	}
+}
+
+func TestEmailService_Deliver_MessageID(t *testing.T) {
+	email := &models.Email{ID: primitive.NewObjectID(), UserID: "user-1", ContentType: "text/plain",
+		From: "f", To: "t", Subject: "s", Body: "b", MessageID: "<queued@example.com>"}
+	claimed := mock.MatchedBy(func(f *models.SendFingerprint) bool {
+		return f.UserID == "user-1" && f.Hash == "message-id:<queued@example.com>"
+	})
+
+	t.Run("sent", func(t *testing.T) {
+		client := &mocks.SMTPClient{}
+		client.On("Send", mock.Anything, "f", "t", "s", "b").Return(nil)
+
+		repo := &repoMocks.Repository{}
+		repo.On("ClaimSendFingerprint", mock.Anything, claimed).Return(true, nil)
+		repo.On("ExtendSendFingerprint", mock.Anything, claimed, mock.AnythingOfType("time.Time")).Return(nil)
+		logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+		s := &emailService{client: client, repo: repo, config: &config.Config{}}
+
+		err := s.Deliver(context.Background(), email)
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+		assert.Equal(t, "<queued@example.com>", awaitLog(t, logged).MessageID)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "ReleaseSendFingerprint", mock.Anything, mock.Anything)
+	})
+
+	t.Run("already sent", func(t *testing.T) {
+		client := &mocks.SMTPClient{}
+		repo := &repoMocks.Repository{}
+		repo.On("ClaimSendFingerprint", mock.Anything, claimed).Return(false, nil)
+
+		s := &emailService{client: client, repo: repo, config: &config.Config{}}
+
+		err := s.Deliver(context.Background(), email)
+
+		assert.NoError(t, err)
+		client.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "SaveEmailLog", mock.Anything, mock.Anything)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		client := &mocks.SMTPClient{}
+		client.On("Send", mock.Anything, "f", "t", "s", "b").Return(errors.New("smtp error"))
+
+		repo := &repoMocks.Repository{}
+		repo.On("ClaimSendFingerprint", mock.Anything, claimed).Return(true, nil)
+		repo.On("ReleaseSendFingerprint", mock.Anything, claimed).Return(nil)
+		logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+		s := &emailService{client: client, repo: repo, config: &config.Config{}}
+
+		err := s.Deliver(context.Background(), email)
+
+		assert.Error(t, err)
+		assert.False(t, awaitLog(t, logged).Success)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "ExtendSendFingerprint", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("claim error", func(t *testing.T) {
+		client := &mocks.SMTPClient{}
+		repo := &repoMocks.Repository{}
+		repo.On("ClaimSendFingerprint", mock.Anything, claimed).Return(false, errors.New("db error"))
+
+		s := &emailService{client: client, repo: repo, config: &config.Config{}}
+
+		err := s.Deliver(context.Background(), email)
+
+		assert.Error(t, err)
+		client.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEmailService_GetStatus(t *testing.T) {
+	stored := &models.Email{
+		ID:            primitive.NewObjectID(),
+		UserID:        "user-1",
+		Status:        models.EmailStatusPending,
+		Attempts:      1,
+		NextAttemptAt: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindEmailByID", mock.Anything, stored.ID.Hex()).Return(stored, nil)
+	repo.On("FindEmailByID", mock.Anything, "missing").Return(nil, emailRepo.ErrInvalidID)
+
+	s := &emailService{repo: repo, config: &config.Config{}}
+
+	got, err := s.GetStatus(context.Background(), "user-1", stored.ID.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", got.Status)
+	assert.Equal(t, &stored.NextAttemptAt, got.NextAttemptAt)
+
+	_, err = s.GetStatus(context.Background(), "user-2", stored.ID.Hex())
+	assert.ErrorIs(t, err, ErrEmailNotFound)
+
+	_, err = s.GetStatus(context.Background(), "user-1", "missing")
+	assert.ErrorIs(t, err, ErrEmailNotFound)
+}
@@ -0,0 +1,123 @@
+package email
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/repository/models"
+)
+
+// AttachmentScanner checks content for malware. The clamd client of
+// libs/clamd implements it.
+type AttachmentScanner interface {
+	// Scan reads r and returns the name of the malware found in it, or an
+	// empty string when it is clean
+	Scan(ctx context.Context, r io.Reader) (string, error)
+}
+
+// scanAttachments scans the attachments of an email right before it is sent
+// and returns the verdict to record on its log, nil when nothing was
+// scanned. An infected attachment rejects the email with
+// ErrAttachmentInfected. An attachment that can't be scanned rejects it
+// with ErrScanFailed, unless scanning fails open.
+func (s *emailService) scanAttachments(ctx context.Context, attachments []libSmtp.Attachment) (*models.AttachmentScan, error) {
+	return s.scanAttachmentsCached(ctx, attachments, nil)
+}
+
+// scanResult is the outcome of scanning one attachment
+type scanResult struct {
+	signature string
+	err       error
+}
+
+// scanCache holds the scan results of the attachments of one request by the
+// digest of their content, so that an attachment shared by the emails of a
+// bulk request is only scanned once. It isn't safe for concurrent use.
+type scanCache map[string]scanResult
+
+// scanAttachmentsCached scans attachments like scanAttachments, reusing and
+// recording the results in cache unless it is nil
+func (s *emailService) scanAttachmentsCached(ctx context.Context, attachments []libSmtp.Attachment, cache scanCache) (*models.AttachmentScan, error) {
+	if s.scanner == nil || len(attachments) == 0 {
+		return nil, nil
+	}
+
+	verdict := &models.AttachmentScan{
+		Status:    models.ScanStatusClean,
+		ScannedAt: time.Now(),
+	}
+	for _, att := range attachments {
+		signature, err := s.scanCached(ctx, att, cache)
+		if err != nil {
+			if !s.config.Scan.FailOpen {
+				verdict.Status, verdict.Filename, verdict.Error = models.ScanStatusError, att.Filename, err.Error()
+				return verdict, fmt.Errorf("%w: %s: %v", ErrScanFailed, att.Filename, err)
+			}
+
+			// Keep scanning, an infected attachment still rejects the email
+			log.Printf("WARNING: sending %s unscanned: %v", att.Filename, err)
+			if verdict.Status == models.ScanStatusClean {
+				verdict.Status, verdict.Filename, verdict.Error = models.ScanStatusError, att.Filename, err.Error()
+			}
+			continue
+		}
+		if signature != "" {
+			verdict.Status, verdict.Filename, verdict.Signature, verdict.Error = models.ScanStatusInfected, att.Filename, signature, ""
+			return verdict, fmt.Errorf("%w: %s contains %s", ErrAttachmentInfected, att.Filename, signature)
+		}
+	}
+
+	return verdict, nil
+}
+
+// scanCached returns the cached result for the content of an attachment,
+// scanning it when there is none
+func (s *emailService) scanCached(ctx context.Context, att libSmtp.Attachment, cache scanCache) (string, error) {
+	if cache == nil {
+		return s.scan(ctx, att)
+	}
+
+	key, err := attachmentDigest(att)
+	if err != nil {
+		return "", err
+	}
+	if result, ok := cache[key]; ok {
+		return result.signature, result.err
+	}
+
+	signature, err := s.scan(ctx, att)
+	cache[key] = scanResult{signature: signature, err: err}
+	return signature, err
+}
+
+// attachmentDigest returns the SHA-256 of the content of an attachment
+func attachmentDigest(att libSmtp.Attachment) (string, error) {
+	content, err := att.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// scan streams the content of a single attachment to the scanner
+func (s *emailService) scan(ctx context.Context, att libSmtp.Attachment) (string, error) {
+	content, err := att.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	return s.scanner.Scan(ctx, content)
+}
@@ -0,0 +1,236 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"GoMail/app/config"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtp/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+// fakeScanner reports content containing "EICAR" as infected and fails
+// for content containing "unscannable"
+type fakeScanner struct{}
+
+func (fakeScanner) Scan(ctx context.Context, r io.Reader) (string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case strings.Contains(string(content), "EICAR"):
+		return "Eicar-Signature", nil
+	case strings.Contains(string(content), "unscannable"):
+		return "", errors.New("clamd unreachable")
+	}
+	return "", nil
+}
+
+// countingScanner counts the attachments it scans
+type countingScanner struct {
+	fakeScanner
+	scans int
+}
+
+func (c *countingScanner) Scan(ctx context.Context, r io.Reader) (string, error) {
+	c.scans++
+	return c.fakeScanner.Scan(ctx, r)
+}
+
+func TestEmailService_scanAttachments(t *testing.T) {
+	clean := libSmtp.Attachment{Filename: "report.txt", Content: []byte("report")}
+	infected := libSmtp.Attachment{Filename: "invoice.pdf", Size: 5, Open: openString("EICAR")}
+	unscannable := libSmtp.Attachment{Filename: "big.zip", Content: []byte("unscannable")}
+
+	tests := []struct {
+		name        string
+		scanner     AttachmentScanner
+		failOpen    bool
+		attachments []libSmtp.Attachment
+		wantStatus  models.ScanStatus
+		wantFile    string
+		wantErr     error
+	}{
+		{
+			name:        "scanning disabled",
+			attachments: []libSmtp.Attachment{infected},
+		},
+		{
+			name:    "no attachments",
+			scanner: fakeScanner{},
+		},
+		{
+			name:        "clean",
+			scanner:     fakeScanner{},
+			attachments: []libSmtp.Attachment{clean},
+			wantStatus:  models.ScanStatusClean,
+		},
+		{
+			name:        "infected",
+			scanner:     fakeScanner{},
+			attachments: []libSmtp.Attachment{clean, infected},
+			wantStatus:  models.ScanStatusInfected,
+			wantFile:    "invoice.pdf",
+			wantErr:     ErrAttachmentInfected,
+		},
+		{
+			name:        "fail closed",
+			scanner:     fakeScanner{},
+			attachments: []libSmtp.Attachment{unscannable, clean},
+			wantStatus:  models.ScanStatusError,
+			wantFile:    "big.zip",
+			wantErr:     ErrScanFailed,
+		},
+		{
+			name:        "fail open",
+			scanner:     fakeScanner{},
+			failOpen:    true,
+			attachments: []libSmtp.Attachment{unscannable, clean},
+			wantStatus:  models.ScanStatusError,
+			wantFile:    "big.zip",
+		},
+		{
+			name:        "fail open still rejects infected",
+			scanner:     fakeScanner{},
+			failOpen:    true,
+			attachments: []libSmtp.Attachment{unscannable, infected},
+			wantStatus:  models.ScanStatusInfected,
+			wantFile:    "invoice.pdf",
+			wantErr:     ErrAttachmentInfected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &emailService{scanner: tt.scanner, config: &config.Config{Scan: config.ScanConfig{FailOpen: tt.failOpen}}}
+
+			verdict, err := s.scanAttachments(context.Background(), tt.attachments)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantStatus == "" {
+				assert.Nil(t, verdict)
+				return
+			}
+			require.NotNil(t, verdict)
+			assert.Equal(t, tt.wantStatus, verdict.Status)
+			assert.Equal(t, tt.wantFile, verdict.Filename)
+		})
+	}
+}
+
+func TestEmailService_SendWithAttachments_Infected(t *testing.T) {
+	client := &mocks.SMTPClient{}
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, scanner: fakeScanner{}, config: &config.Config{}}
+
+	got, err := s.SendWithAttachments(context.Background(), SendWithAttachmentsRequest{
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Invoice",
+		Body:        "Attached",
+		Attachments: []libSmtp.Attachment{{Filename: "invoice.pdf", Content: []byte("EICAR")}},
+	})
+
+	assert.ErrorIs(t, err, ErrAttachmentInfected)
+	assert.False(t, got.Success)
+	client.AssertNotCalled(t, "SendWithAttachments")
+	log := awaitLog(t, logged)
+	assert.False(t, log.Success)
+	require.NotNil(t, log.Scan)
+	assert.Equal(t, models.ScanStatusInfected, log.Scan.Status)
+	assert.Equal(t, "Eicar-Signature", log.Scan.Signature)
+}
+
+func TestEmailService_Deliver_Scanned(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendWithAttachments", mock.Anything, "f", "t", "s", "b", mock.Anything).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	claimDeliveries(repo)
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, scanner: fakeScanner{}, config: &config.Config{}}
+
+	err := s.Deliver(context.Background(), &models.Email{ID: primitive.NewObjectID(), ContentType: "multipart/mixed",
+		From: "f", To: "t", Subject: "s", Body: "b", Attachments: []models.EmailAttachment{{Filename: "a.txt", Content: []byte("a")}}})
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+	log := awaitLog(t, logged)
+	assert.True(t, log.Success)
+	require.NotNil(t, log.Scan)
+	assert.Equal(t, models.ScanStatusClean, log.Scan.Status)
+}
+
+func TestEmailService_SendBulk_Infected(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendWithAttachments", mock.Anything, "a@example.com", "b@example.com", "Hi", "x", mock.Anything).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, scanner: fakeScanner{}, config: &config.Config{}}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "b@example.com", Subject: "Hi", Body: "x",
+				Attachments: []libSmtp.Attachment{{Filename: "a.txt", Content: []byte("a")}}},
+			{From: "a@example.com", To: "c@example.com", Subject: "Hi", Body: "x",
+				Attachments: []libSmtp.Attachment{{Filename: "invoice.pdf", Content: []byte("EICAR")}}},
+		},
+	})
+
+	assert.NoError(t, err)
+	for range got.Results {
+		awaitLog(t, logged)
+	}
+	assert.True(t, got.Results[0].Success)
+	assert.False(t, got.Results[1].Success)
+	assert.Contains(t, got.Results[1].Error, "attachment infected")
+	client.AssertNumberOfCalls(t, "SendWithAttachments", 1)
+}
+
+func TestEmailService_SendBulk_ScansSharedAttachmentOnce(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("SendWithAttachments", mock.Anything, "a@example.com", mock.Anything, "Hi", "x", mock.Anything).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil)
+
+	scanner := &countingScanner{}
+	s := &emailService{client: client, repo: repo, scanner: scanner, config: &config.Config{}}
+
+	report := []libSmtp.Attachment{{Filename: "report.pdf", Content: []byte("numbers")}}
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "b@example.com", Subject: "Hi", Body: "x", Attachments: report},
+			{From: "a@example.com", To: "c@example.com", Subject: "Hi", Body: "x", Attachments: report},
+			{From: "a@example.com", To: "d@example.com", Subject: "Hi", Body: "x", Attachments: report},
+		},
+	})
+
+	require.NoError(t, err)
+	for _, result := range got.Results {
+		assert.True(t, result.Success)
+	}
+	assert.Equal(t, 1, scanner.scans)
+	client.AssertNumberOfCalls(t, "SendWithAttachments", 3)
+}
@@ -0,0 +1,101 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	emailRepo "GoMail/app/repository/email"
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultScheduledPageSize = 20
+	maxScheduledPageSize     = 100
+)
+
+// ListScheduled returns the emails a user has scheduled for later delivery
+func (s *emailService) ListScheduled(ctx context.Context, userID string, page, limit int) (*ListScheduledResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultScheduledPageSize
+	}
+	if limit > maxScheduledPageSize {
+		limit = maxScheduledPageSize
+	}
+
+	filter := bson.M{
+		"user_id": userID,
+		"status":  models.EmailStatusScheduled,
+	}
+
+	emails, total, err := s.repo.FindEmails(ctx, filter, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := make([]ScheduledEmail, 0, len(emails))
+	for _, email := range emails {
+		scheduled = append(scheduled, ScheduledEmail{
+			ID:          email.ID.Hex(),
+			From:        email.From,
+			To:          email.To,
+			Subject:     email.Subject,
+			ContentType: email.ContentType,
+			SendAt:      email.NextAttemptAt,
+			CreatedAt:   email.CreatedAt,
+		})
+	}
+
+	return &ListScheduledResponse{
+		Emails: scheduled,
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	}, nil
+}
+
+// CancelScheduled deletes a scheduled email before it is sent. Emails the
+// scheduler already handed to the workers can no longer be cancelled.
+func (s *emailService) CancelScheduled(ctx context.Context, userID, id string) error {
+	err := s.repo.DeleteEmail(ctx, id, bson.M{
+		"user_id": userID,
+		"status":  models.EmailStatusScheduled,
+	})
+	if err != nil {
+		if errors.Is(err, emailRepo.ErrEmailNotFound) || errors.Is(err, emailRepo.ErrInvalidID) {
+			return ErrEmailNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Reschedule changes the send time of a scheduled email
+func (s *emailService) Reschedule(ctx context.Context, userID, id string, sendAt time.Time) (*EmailStatusResponse, error) {
+	if !sendAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: sendAt must be in the future", ErrInvalidSchedule)
+	}
+
+	stored, err := s.repo.RescheduleEmail(ctx, id, userID, sendAt)
+	if err != nil {
+		if errors.Is(err, emailRepo.ErrEmailNotFound) || errors.Is(err, emailRepo.ErrInvalidID) {
+			return nil, ErrEmailNotFound
+		}
+		return nil, err
+	}
+
+	return &EmailStatusResponse{
+		ID:        stored.ID.Hex(),
+		Status:    string(stored.Status),
+		Attempts:  stored.Attempts,
+		SendAt:    stored.SendAt,
+		CreatedAt: stored.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,191 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/smtp/mocks"
+	emailRepo "GoMail/app/repository/email"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+func TestEmailService_SendScheduled(t *testing.T) {
+	sendAt := time.Now().Add(time.Hour)
+
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(e *models.Email) bool {
+		return e.Status == models.EmailStatusScheduled &&
+			e.SendAt != nil && e.SendAt.Equal(sendAt) &&
+			e.NextAttemptAt.Equal(sendAt)
+	})).Return(nil)
+
+	client := &mocks.SMTPClient{}
+	s := &emailService{client: client, repo: repo, config: &config.Config{}}
+
+	req := validSendEmailRequest
+	req.SendAt = &sendAt
+
+	got, err := s.Send(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "scheduled", got.Status)
+	repo.AssertExpectations(t)
+	client.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailService_SendScheduled_PastSendAt(t *testing.T) {
+	sendAt := time.Now().Add(-time.Minute)
+
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmail", mock.Anything, mock.MatchedBy(func(e *models.Email) bool {
+		return e.Status == models.EmailStatusPending && e.SendAt == nil
+	})).Return(nil)
+
+	s := &emailService{client: &mocks.SMTPClient{}, repo: repo, config: &config.Config{}}
+
+	req := validSendEmailRequest
+	req.SendAt = &sendAt
+
+	got, err := s.Send(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", got.Status)
+	repo.AssertExpectations(t)
+}
+
+func TestEmailService_ListScheduled(t *testing.T) {
+	sendAt := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	stored := &models.Email{
+		ID:            primitive.NewObjectID(),
+		To:            "recipient@example.com",
+		Subject:       "Reminder",
+		ContentType:   "text/plain",
+		Status:        models.EmailStatusScheduled,
+		SendAt:        &sendAt,
+		NextAttemptAt: sendAt,
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindEmails", mock.Anything, bson.M{"user_id": "user-1", "status": models.EmailStatusScheduled}, 1, maxScheduledPageSize).
+		Return([]*models.Email{stored}, int64(1), nil)
+
+	s := &emailService{repo: repo, config: &config.Config{}}
+
+	got, err := s.ListScheduled(context.Background(), "user-1", 0, 1000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), got.Total)
+	assert.Equal(t, 1, got.Page)
+	assert.Equal(t, maxScheduledPageSize, got.Limit)
+	assert.Equal(t, []ScheduledEmail{{
+		ID:          stored.ID.Hex(),
+		To:          "recipient@example.com",
+		Subject:     "Reminder",
+		ContentType: "text/plain",
+		SendAt:      sendAt,
+	}}, got.Emails)
+}
+
+func TestEmailService_CancelScheduled(t *testing.T) {
+	condition := bson.M{"user_id": "user-1", "status": models.EmailStatusScheduled}
+
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "cancelled"},
+		{name: "not scheduled", repoErr: emailRepo.ErrEmailNotFound, wantErr: ErrEmailNotFound},
+		{name: "invalid id", repoErr: emailRepo.ErrInvalidID, wantErr: ErrEmailNotFound},
+		{name: "db error", repoErr: errors.New("db error"), wantErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repoMocks.Repository{}
+			repo.On("DeleteEmail", mock.Anything, "abc", condition).Return(tt.repoErr)
+
+			s := &emailService{repo: repo, config: &config.Config{}}
+
+			err := s.CancelScheduled(context.Background(), "user-1", "abc")
+
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmailService_Reschedule(t *testing.T) {
+	sendAt := time.Now().Add(time.Hour)
+	stored := &models.Email{
+		ID:     primitive.NewObjectID(),
+		Status: models.EmailStatusScheduled,
+		SendAt: &sendAt,
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("RescheduleEmail", mock.Anything, stored.ID.Hex(), "user-1", sendAt).Return(stored, nil)
+	repo.On("RescheduleEmail", mock.Anything, "sent", "user-1", sendAt).Return(nil, emailRepo.ErrEmailNotFound)
+
+	s := &emailService{repo: repo, config: &config.Config{}}
+
+	got, err := s.Reschedule(context.Background(), "user-1", stored.ID.Hex(), sendAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "scheduled", got.Status)
+	assert.Equal(t, &sendAt, got.SendAt)
+
+	_, err = s.Reschedule(context.Background(), "user-1", "sent", sendAt)
+	assert.ErrorIs(t, err, ErrEmailNotFound)
+
+	_, err = s.Reschedule(context.Background(), "user-1", stored.ID.Hex(), time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+}
+
+func TestScheduler_promoteDue(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("PromoteScheduledEmails", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(2), nil).Once()
+	repo.On("PromoteScheduledEmails", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("db error")).Once()
+
+	s := NewScheduler(&config.Config{Queue: config.QueueConfig{SchedulerInterval: time.Second}}, repo)
+
+	assert.NoError(t, s.promoteDue(context.Background()))
+	assert.Error(t, s.promoteDue(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	polled := make(chan struct{}, 1)
+	repo := &repoMocks.Repository{}
+	repo.On("PromoteScheduledEmails", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		select {
+		case polled <- struct{}{}:
+		default:
+		}
+	}).Return(int64(0), nil)
+
+	s := NewScheduler(&config.Config{Queue: config.QueueConfig{SchedulerInterval: 10 * time.Millisecond}}, repo)
+	s.Start()
+	select {
+	case <-polled:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler didn't promote due emails")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, s.Stop(ctx))
+	repo.AssertCalled(t, "PromoteScheduledEmails", mock.Anything, mock.Anything)
+}
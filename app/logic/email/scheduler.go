@@ -0,0 +1,84 @@
+package email
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/repository"
+)
+
+// Scheduler releases scheduled emails to the outbound queue once their send
+// time has passed. Every instance may run one: promotion is an atomic status
+// change, and delivery claims the Message-ID of the email before each
+// attempt, so each email is sent exactly once even across restarts.
+type Scheduler struct {
+	repo     repository.Repository
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler(cfg *config.Config, repo repository.Repository) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		interval: cfg.Queue.SchedulerInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start launches the scheduler loop
+func (s *Scheduler) Start() {
+	go s.run()
+}
+
+// Stop signals the scheduler loop to exit and waits for it
+func (s *Scheduler) Stop(ctx context.Context) error {
+	close(s.stop)
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run promotes due emails on every tick until the scheduler is stopped
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.promoteDue(context.Background()); err != nil {
+			log.Printf("Scheduler error: %v", err)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// promoteDue moves every scheduled email whose send time has passed onto the queue
+func (s *Scheduler) promoteDue(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	promoted, err := s.repo.PromoteScheduledEmails(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if promoted > 0 {
+		log.Printf("Scheduler queued %d due emails", promoted)
+	}
+	return nil
+}
@@ -4,12 +4,75 @@ import (
 	"context"
 	"time"
 
+	"GoMail/app/logic/emailtemplate"
 	"GoMail/app/repository/models"
 )
 
-// Send sends a plain text email
+// Send sends a plain text email to the recipients that aren't suppressed,
+// unless it duplicates a recent one
 func (s *emailService) Send(ctx context.Context, req SendEmailRequest) (*SendEmailResponse, error) {
+	return s.withoutSuppressed(ctx, req.UserID, req.From, req.To, req.Subject, "text/plain", req.Topic, func(to string) (*SendEmailResponse, error) {
+		req.To = to
+		return s.deduplicate(ctx, req.UserID, req.AllowDuplicate, req.dedupKey(false), req.From, "text/plain", func() (*SendEmailResponse, error) {
+			return s.send(ctx, req)
+		})
+	})
+}
+
+// send sends a plain text email
+func (s *emailService) send(ctx context.Context, req SendEmailRequest) (*SendEmailResponse, error) {
+	if err := validateFormat(req.Format); err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	// Render the stored template instead of the raw subject and body
+	if req.TemplateID != "" {
+		subject, body, err := s.renderTemplate(ctx, req.UserID, req.TemplateID, emailtemplate.RenderRequest{
+			Data:      req.Data,
+			Locale:    req.Locale,
+			Recipient: req.To,
+		}, req.Subject, false)
+		if err != nil {
+			return &SendEmailResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, err
+		}
+		req.Subject, req.Body = subject, body
+	}
+
+	// Markdown bodies are sent as HTML with a plain text alternative
+	if req.Format == FormatMarkdown {
+		return s.sendMarkdown(ctx, &models.Email{
+			UserID:  req.UserID,
+			SendAt:  req.SendAt,
+			From:    req.From,
+			To:      req.To,
+			Topic:   req.Topic,
+			Subject: req.Subject,
+			Body:    req.Body,
+		}, req.HTMLOptions, req.Async)
+	}
+
+	// Persist the email for the queue workers when async or scheduled sending is requested
+	if req.Async || req.SendAt != nil {
+		return s.enqueue(ctx, &models.Email{
+			UserID:      req.UserID,
+			SendAt:      req.SendAt,
+			From:        req.From,
+			To:          req.To,
+			Topic:       req.Topic,
+			Subject:     req.Subject,
+			Body:        req.Body,
+			ContentType: "text/plain",
+		})
+	}
+	
 	// Create a request to the SMTP client
+	ctx, messageID := s.withMessageID(ctx, req.From)
 	err := s.client.Send(ctx, req.From, req.To, req.Subject, req.Body)
 	
 	// Create success/error response
@@ -21,6 +84,8 @@ func (s *emailService) Send(ctx context.Context, req SendEmailRequest) (*SendEma
 	
 	// Create email log
 	emailLog := &models.EmailLog{
+		MessageID:   messageID,
+		UserID:      req.UserID,
 		From:        req.From,
 		To:          req.To,
 		Subject:     req.Subject,
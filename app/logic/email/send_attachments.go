@@ -4,13 +4,110 @@ import (
 	"context"
 	"time"
 
+	"GoMail/app/logic/download"
 	"GoMail/app/repository/models"
 )
 
-// SendWithAttachments sends an email with attachments
+// SendWithAttachments sends an email with attachments to the recipients
+// that aren't suppressed, unless it duplicates a recent one
 func (s *emailService) SendWithAttachments(ctx context.Context, req SendWithAttachmentsRequest) (*SendEmailResponse, error) {
-	// Create a request to the SMTP client
-	err := s.client.SendWithAttachments(ctx, req.From, req.To, req.Subject, req.Body, req.Attachments)
+	return s.withoutSuppressed(ctx, req.UserID, req.From, req.To, req.Subject, "multipart/mixed", req.Topic, func(to string) (*SendEmailResponse, error) {
+		req.To = to
+		return s.deduplicate(ctx, req.UserID, req.AllowDuplicate, req.dedupKey(), req.From, "multipart/mixed", func() (*SendEmailResponse, error) {
+			return s.sendWithAttachments(ctx, req)
+		})
+	})
+}
+
+// sendWithAttachments sends an email with attachments
+func (s *emailService) sendWithAttachments(ctx context.Context, req SendWithAttachmentsRequest) (*SendEmailResponse, error) {
+	if err := validateFormat(req.Format); err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	// Resolve attachment store references and download attachments given by
+	// URL before anything is sent or stored, so the size limits cover all
+	// of them. The stored ones come last.
+	given := len(req.Attachments)
+	attachments, err := s.withStored(ctx, req.UserID, req.Attachments, req.AttachmentIDs)
+	if err == nil {
+		attachments, err = s.resolveAttachments(ctx, attachments)
+	}
+
+	// Replace large attachments with download links in the body. Only
+	// inline attachments, all of them given, remain.
+	var links []*download.LinkResponse
+	if err == nil {
+		attachments, links, err = s.linkAttachments(ctx, req.UserID, req.To, attachments)
+	}
+	if err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	if len(links) > 0 {
+		format := linkFormatText
+		if req.Format == FormatMarkdown {
+			format = linkFormatMarkdown
+		}
+		req.Body = withLinks(req.Body, format, links)
+		req.AttachmentIDs, given = nil, len(attachments)
+	}
+	req.Attachments = attachments
+
+	// Stored emails carry their content, uploaded files are gone once the
+	// request ends, and keep the store references for delivery. Markdown
+	// emails are built as stored emails too.
+	if req.Async || req.SendAt != nil || req.Format == FormatMarkdown {
+		if req.Attachments, err = loadAttachments(attachments[:given]); err != nil {
+			return &SendEmailResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, err
+		}
+	}
+
+	// Markdown bodies are sent as HTML with a plain text alternative
+	if req.Format == FormatMarkdown {
+		return s.sendMarkdown(ctx, &models.Email{
+			UserID:        req.UserID,
+			SendAt:        req.SendAt,
+			From:          req.From,
+			To:            req.To,
+			Topic:         req.Topic,
+			Subject:       req.Subject,
+			Body:          req.Body,
+			Attachments:   toEmailAttachments(req.Attachments),
+			AttachmentIDs: req.AttachmentIDs,
+		}, req.HTMLOptions, req.Async)
+	}
+
+	// Persist the email for the queue workers when async or scheduled sending is requested
+	if req.Async || req.SendAt != nil {
+		return s.enqueue(ctx, &models.Email{
+			UserID:        req.UserID,
+			SendAt:        req.SendAt,
+			From:          req.From,
+			To:            req.To,
+			Topic:         req.Topic,
+			Subject:       req.Subject,
+			Body:          req.Body,
+			ContentType:   "multipart/mixed",
+			Attachments:   toEmailAttachments(req.Attachments),
+			AttachmentIDs: req.AttachmentIDs,
+		})
+	}
+	
+	// Scan the attachments, then create a request to the SMTP client
+	ctx, messageID := s.withMessageID(ctx, req.From)
+	scan, err := s.scanAttachments(ctx, req.Attachments)
+	if err == nil {
+		err = s.client.SendWithAttachments(ctx, req.From, req.To, req.Subject, req.Body, req.Attachments)
+	}
 	
 	// Create success/error response
 	success := err == nil
@@ -21,6 +118,8 @@ func (s *emailService) SendWithAttachments(ctx context.Context, req SendWithAtta
 	
 	// Create email log
 	emailLog := &models.EmailLog{
+		MessageID:   messageID,
+		UserID:      req.UserID,
 		From:        req.From,
 		To:          req.To,
 		Subject:     req.Subject,
@@ -28,6 +127,7 @@ func (s *emailService) SendWithAttachments(ctx context.Context, req SendWithAtta
 		SentAt:      time.Now(),
 		Success:     success,
 		Error:       errMsg,
+		Scan:        scan,
 		CreatedAt:   time.Now(),
 	}
 	
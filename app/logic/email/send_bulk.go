@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"GoMail/app/logic/emailtemplate"
 	"GoMail/app/repository/models"
 )
 
@@ -12,10 +13,177 @@ import (
 func (s *emailService) SendBulk(ctx context.Context, req SendBulkEmailRequest) (*SendBulkEmailResponse, error) {
 	// Initialize a slice to store the results
 	results := make([]EmailResult, len(req.Emails))
+	
+	// Resolve attachments and render stored templates up front so an invalid
+	// one only fails its own email
+	emails := make([]BulkEmail, len(req.Emails))
+	copy(emails, req.Emails)
+	failed := make([]bool, len(emails))
+
+	// Suppress emails identical to ones sent within the dedup window. The
+	// claims of emails that fail are given up once the results are known.
+	claims := make([]*models.SendFingerprint, len(emails))
+	suppressed := make([][]SuppressedRecipient, len(emails))
+	defer func() {
+		for i, claim := range claims {
+			if claim != nil && !results[i].Success {
+				s.releaseSend(claim)
+			}
+		}
+	}()
+
+	for i := range emails {
+		if err := validateFormat(emails[i].Format); err != nil {
+			results[i] = EmailResult{Success: false, Error: err.Error()}
+			failed[i] = true
+			continue
+		}
+		topic, err := s.findTopic(ctx, req.UserID, emails[i].Topic)
+		if err == nil {
+			emails[i].Topic = topicName(topic)
+			emails[i].Unsubscribe, err = withTopicList(emails[i].Unsubscribe, topic)
+		}
+		if err == nil {
+			err = validateUnsubscribe(emails[i].Unsubscribe, emails[i].To)
+		}
+		if err != nil {
+			results[i] = EmailResult{Success: false, Error: err.Error()}
+			failed[i] = true
+			continue
+		}
+
+		// Leave out suppressed recipients, dropping emails that have none left
+		to, dropped, err := s.filterSuppressed(ctx, req.UserID, emails[i].To, unsubscribeList(emails[i].Unsubscribe), topic)
+		if err != nil {
+			results[i] = EmailResult{Success: false, Error: err.Error()}
+			failed[i] = true
+			continue
+		}
+		suppressed[i] = dropped
+		if to == "" && len(dropped) > 0 {
+			s.logSuppressedRecipients(req.UserID, nil, emails[i].From, emails[i].To, emails[i].Subject, bulkContentType(emails[i]), dropped)
+			results[i] = EmailResult{Success: false, Status: StatusSuppressed}
+			failed[i] = true
+			continue
+		}
+		emails[i].To = to
+
+		claim, ok := s.claimSend(ctx, req.UserID, req.AllowDuplicate, emails[i].dedupKey())
+		if !ok {
+			s.logSuppressed(req.UserID, emails[i].From, emails[i].To, emails[i].Subject, bulkContentType(emails[i]))
+			results[i] = EmailResult{Success: false, Status: StatusSuppressedDuplicate}
+			failed[i] = true
+			continue
+		}
+		claims[i] = claim
+		attachments, err := s.withStored(ctx, req.UserID, emails[i].Attachments, emails[i].AttachmentIDs)
+		if err == nil {
+			attachments, err = s.resolveAttachments(ctx, attachments)
+		}
+		if err != nil {
+			results[i] = EmailResult{Success: false, Error: err.Error()}
+			failed[i] = true
+			continue
+		}
+		if emails[i].TemplateID != "" {
+			subject, body, err := s.renderTemplate(ctx, req.UserID, emails[i].TemplateID, emailtemplate.RenderRequest{
+				Data:      emails[i].Data,
+				Locale:    emails[i].Locale,
+				Recipient: emails[i].To,
+			}, emails[i].Subject, emails[i].IsHTML && emails[i].Format == "")
+			if err != nil {
+				results[i] = EmailResult{Success: false, Error: err.Error()}
+				failed[i] = true
+				continue
+			}
+			emails[i].Subject, emails[i].Body = subject, body
+		}
+
+		// Replace large attachments with download links in the rendered
+		// body, before Markdown and HTML are processed
+		attachments, links, err := s.linkAttachments(ctx, req.UserID, emails[i].To, attachments)
+		if err != nil {
+			results[i] = EmailResult{Success: false, Error: err.Error()}
+			failed[i] = true
+			continue
+		}
+		given := len(emails[i].Attachments)
+		if len(links) > 0 {
+			format := linkFormatText
+			switch {
+			case emails[i].Format == FormatMarkdown:
+				format = linkFormatMarkdown
+			case emails[i].IsHTML:
+				format = linkFormatHTML
+			}
+			emails[i].Body = withLinks(emails[i].Body, format, links)
+			emails[i].AttachmentIDs, given = nil, len(attachments)
+		}
+
+		// Queued emails keep the store references, resolved at delivery
+		if req.Async || req.SendAt != nil {
+			attachments = attachments[:given]
+		}
+		emails[i].Attachments = attachments
+	}
+
+	// Render Markdown bodies and run the HTML pipeline. Emails with a plain
+	// text part are sent as multipart/alternative.
+	textBodies := make([]string, len(emails))
+	for i := range emails {
+		if failed[i] {
+			continue
+		}
+
+		var html, text string
+		var err error
+		switch {
+		case emails[i].Format == FormatMarkdown:
+			html, text, err = s.renderMarkdown(emails[i].Subject, emails[i].Body)
+			if err == nil {
+				html, text, err = s.prepareHTML(html, text, emails[i].HTMLOptions)
+			}
+		case emails[i].IsHTML:
+			html, text, err = s.prepareHTML(emails[i].Body, "", emails[i].HTMLOptions)
+		default:
+			continue
+		}
+		if err != nil {
+			results[i] = EmailResult{Success: false, Error: err.Error()}
+			failed[i] = true
+			continue
+		}
+		emails[i].Body, textBodies[i] = html, text
+	}
+
+	// Persist the emails for the queue workers when async or scheduled sending is requested
+	if req.Async || req.SendAt != nil {
+		for i, email := range emails {
+			if failed[i] {
+				continue
+			}
+			results[i] = s.enqueueBulkEmail(ctx, req.UserID, email, textBodies[i], req.SendAt)
+		}
+		withSuppressed(results, suppressed)
+		return &SendBulkEmailResponse{
+			Results: results,
+		}, nil
+	}
+
+	// Scan the attachments before sending. Emails sharing an attachment
+	// reuse the verdict of its first scan.
+	scans := make([]*models.AttachmentScan, len(emails))
+	scanErrs := make([]error, len(emails))
+	cache := scanCache{}
+	for i := range emails {
+		if failed[i] {
+			continue
+		}
+		scans[i], scanErrs[i] = s.scanAttachmentsCached(ctx, emails[i].Attachments, cache)
+	}
 
 	// Create a wait group to wait for all emails to be sent
 	var wg sync.WaitGroup
-	wg.Add(len(req.Emails))
 
 	// Get max concurrent from config, with a default fallback
 	maxConcurrent := 5
@@ -27,7 +195,12 @@ func (s *emailService) SendBulk(ctx context.Context, req SendBulkEmailRequest) (
 	semaphore := make(chan struct{}, maxConcurrent)
 	
 	// Send emails concurrently
-	for i, email := range req.Emails {
+	for i, email := range emails {
+		if failed[i] {
+			continue
+		}
+
+		wg.Add(1)
 		go func(idx int, email BulkEmail) {
 			defer wg.Done()
 			
@@ -35,18 +208,42 @@ func (s *emailService) SendBulk(ctx context.Context, req SendBulkEmailRequest) (
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 			
-			var err error
+			scan, err := scans[idx], scanErrs[idx]
 			
 			// Determine the content type
 			contentType := "text/plain"
+			ctx, messageID := s.withMessageID(ctx, email.From)
+			if email.Unsubscribe != nil {
+				ctx = s.withUnsubscribe(ctx, req.UserID, messageID, email.To, unsubscribeList(email.Unsubscribe))
+			}
 			
-			// Send the email based on its type
-			if email.IsHTML {
+			// Send the email based on its type, unless its attachments were
+			// rejected by the scan
+			if textBodies[idx] != "" {
+				contentType = "multipart/alternative"
+				if err == nil {
+					err = s.sendTracked(ctx, email.Body, req.UserID, messageID, email.To, s.tracksOpens(email.HTMLOptions), s.tracksClicks(email.HTMLOptions), func(ctx context.Context, body string) error {
+						return s.client.SendAlternative(ctx, email.From, email.To, email.Subject, textBodies[idx], body, email.Attachments)
+					})
+				}
+			} else if email.IsHTML && len(email.Attachments) > 0 {
+				// Without a text part the HTML is sent next to the attachments
+				contentType = "multipart/mixed"
+				if err == nil {
+					err = s.sendTracked(ctx, email.Body, req.UserID, messageID, email.To, s.tracksOpens(email.HTMLOptions), s.tracksClicks(email.HTMLOptions), func(ctx context.Context, body string) error {
+						return s.client.SendAlternative(ctx, email.From, email.To, email.Subject, "", body, email.Attachments)
+					})
+				}
+			} else if email.IsHTML {
 				contentType = "text/html"
-				err = s.client.SendHTML(ctx, email.From, email.To, email.Subject, email.Body)
+				err = s.sendTracked(ctx, email.Body, req.UserID, messageID, email.To, s.tracksOpens(email.HTMLOptions), s.tracksClicks(email.HTMLOptions), func(ctx context.Context, body string) error {
+					return s.client.SendHTML(ctx, email.From, email.To, email.Subject, body)
+				})
 			} else if len(email.Attachments) > 0 {
 				contentType = "multipart/mixed"
-				err = s.client.SendWithAttachments(ctx, email.From, email.To, email.Subject, email.Body, email.Attachments)
+				if err == nil {
+					err = s.client.SendWithAttachments(ctx, email.From, email.To, email.Subject, email.Body, email.Attachments)
+				}
 			} else {
 				err = s.client.Send(ctx, email.From, email.To, email.Subject, email.Body)
 			}
@@ -66,6 +263,8 @@ func (s *emailService) SendBulk(ctx context.Context, req SendBulkEmailRequest) (
 			
 			// Create email log
 			emailLog := &models.EmailLog{
+				MessageID:   messageID,
+				UserID:      req.UserID,
 				From:        email.From,
 				To:          email.To,
 				Subject:     email.Subject,
@@ -73,6 +272,7 @@ func (s *emailService) SendBulk(ctx context.Context, req SendBulkEmailRequest) (
 				SentAt:      time.Now(),
 				Success:     success,
 				Error:       errMsg,
+				Scan:        scan,
 				CreatedAt:   time.Now(),
 			}
 			
@@ -83,9 +283,74 @@ func (s *emailService) SendBulk(ctx context.Context, req SendBulkEmailRequest) (
 	
 	// Wait for all emails to be sent
 	wg.Wait()
+	withSuppressed(results, suppressed)
 	
 	// Return the results
 	return &SendBulkEmailResponse{
 		Results: results,
 	}, nil
-} 
\ No newline at end of file
+}
+
+// enqueueBulkEmail persists a single email of a bulk request for async sending
+// A textBody is the plain text part of an email sent as multipart/alternative.
+func (s *emailService) enqueueBulkEmail(ctx context.Context, userID string, email BulkEmail, textBody string, sendAt *time.Time) EmailResult {
+	queued := &models.Email{
+		UserID:      userID,
+		SendAt:      sendAt,
+		From:        email.From,
+		To:          email.To,
+		Subject:     email.Subject,
+		Body:        email.Body,
+		IsHTML:      email.IsHTML,
+		ContentType: "text/plain",
+		Unsubscribe: email.Unsubscribe != nil,
+		List:        unsubscribeList(email.Unsubscribe),
+		Topic:       email.Topic,
+	}
+
+	// Determine the content type the same way as for synchronous sends
+	if textBody != "" || email.IsHTML {
+		queued.TrackOpens = s.tracksOpens(email.HTMLOptions)
+		queued.TrackClicks = s.tracksClicks(email.HTMLOptions)
+	}
+	if textBody != "" {
+		queued.IsHTML = true
+		queued.TextBody = textBody
+		queued.ContentType = "multipart/alternative"
+		queued.Attachments = toEmailAttachments(email.Attachments)
+		queued.AttachmentIDs = email.AttachmentIDs
+	} else if email.IsHTML && (len(email.Attachments) > 0 || len(email.AttachmentIDs) > 0) {
+		queued.ContentType = "multipart/mixed"
+		queued.Attachments = toEmailAttachments(email.Attachments)
+		queued.AttachmentIDs = email.AttachmentIDs
+	} else if email.IsHTML {
+		queued.ContentType = "text/html"
+	} else if len(email.Attachments) > 0 || len(email.AttachmentIDs) > 0 {
+		queued.ContentType = "multipart/mixed"
+		queued.Attachments = toEmailAttachments(email.Attachments)
+		queued.AttachmentIDs = email.AttachmentIDs
+	}
+
+	resp, _ := s.enqueue(ctx, queued)
+	return EmailResult{
+		Success: resp.Success,
+		ID:      resp.ID,
+		Error:   resp.Error,
+	}
+}
+
+// bulkContentType returns the content type logged for an email of a bulk
+// request that wasn't sent
+func bulkContentType(email BulkEmail) string {
+	if email.IsHTML || email.Format == FormatMarkdown {
+		return "text/html"
+	}
+	return "text/plain"
+}
+
+// withSuppressed reports the recipients left out of each email in its result
+func withSuppressed(results []EmailResult, suppressed [][]SuppressedRecipient) {
+	for i := range results {
+		results[i].Suppressed = suppressed[i]
+	}
+}
@@ -4,13 +4,105 @@ import (
 	"context"
 	"time"
 
+	"GoMail/app/logic/emailtemplate"
 	"GoMail/app/repository/models"
 )
 
-// SendHTML sends an HTML email
+// SendHTML sends an HTML email to the recipients that aren't suppressed,
+// unless it duplicates a recent one
 func (s *emailService) SendHTML(ctx context.Context, req SendEmailRequest) (*SendEmailResponse, error) {
+	return s.withoutSuppressed(ctx, req.UserID, req.From, req.To, req.Subject, "text/html", req.Topic, func(to string) (*SendEmailResponse, error) {
+		req.To = to
+		return s.deduplicate(ctx, req.UserID, req.AllowDuplicate, req.dedupKey(true), req.From, "text/html", func() (*SendEmailResponse, error) {
+			return s.sendHTML(ctx, req)
+		})
+	})
+}
+
+// sendHTML sends an HTML email
+func (s *emailService) sendHTML(ctx context.Context, req SendEmailRequest) (*SendEmailResponse, error) {
+	if err := validateFormat(req.Format); err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	// Render the stored template instead of the raw subject and body
+	if req.TemplateID != "" {
+		subject, body, err := s.renderTemplate(ctx, req.UserID, req.TemplateID, emailtemplate.RenderRequest{
+			Data:      req.Data,
+			Locale:    req.Locale,
+			Recipient: req.To,
+		}, req.Subject, req.Format == "")
+		if err != nil {
+			return &SendEmailResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, err
+		}
+		req.Subject, req.Body = subject, body
+	}
+
+	// Markdown bodies are sent as HTML with a plain text alternative
+	if req.Format == FormatMarkdown {
+		return s.sendMarkdown(ctx, &models.Email{
+			UserID:  req.UserID,
+			SendAt:  req.SendAt,
+			From:    req.From,
+			To:      req.To,
+			Topic:   req.Topic,
+			Subject: req.Subject,
+			Body:    req.Body,
+		}, req.HTMLOptions, req.Async)
+	}
+
+	// Inline CSS, add the preheader and generate the plain text part
+	html, text, err := s.prepareHTML(req.Body, "", req.HTMLOptions)
+	if err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	if text != "" {
+		return s.sendAlternative(ctx, &models.Email{
+			UserID:      req.UserID,
+			SendAt:      req.SendAt,
+			From:        req.From,
+			To:          req.To,
+			Topic:       req.Topic,
+			Subject:     req.Subject,
+			Body:        html,
+			TextBody:    text,
+			TrackOpens:  s.tracksOpens(req.HTMLOptions),
+			TrackClicks: s.tracksClicks(req.HTMLOptions),
+		}, req.Async)
+	}
+	req.Body = html
+
+	// Persist the email for the queue workers when async or scheduled sending is requested
+	if req.Async || req.SendAt != nil {
+		return s.enqueue(ctx, &models.Email{
+			UserID:      req.UserID,
+			SendAt:      req.SendAt,
+			From:        req.From,
+			To:          req.To,
+			Topic:       req.Topic,
+			Subject:     req.Subject,
+			Body:        req.Body,
+			IsHTML:      true,
+			ContentType: "text/html",
+			TrackOpens:  s.tracksOpens(req.HTMLOptions),
+			TrackClicks: s.tracksClicks(req.HTMLOptions),
+		})
+	}
+	
 	// Create a request to the SMTP client
-	err := s.client.SendHTML(ctx, req.From, req.To, req.Subject, req.Body)
+	ctx, messageID := s.withMessageID(ctx, req.From)
+	err = s.sendTracked(ctx, req.Body, req.UserID, messageID, req.To, s.tracksOpens(req.HTMLOptions), s.tracksClicks(req.HTMLOptions), func(ctx context.Context, body string) error {
+		return s.client.SendHTML(ctx, req.From, req.To, req.Subject, body)
+	})
 	
 	// Create success/error response
 	success := err == nil
@@ -21,6 +113,8 @@ func (s *emailService) SendHTML(ctx context.Context, req SendEmailRequest) (*Sen
 	
 	// Create email log
 	emailLog := &models.EmailLog{
+		MessageID:   messageID,
+		UserID:      req.UserID,
 		From:        req.From,
 		To:          req.To,
 		Subject:     req.Subject,
@@ -0,0 +1,224 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"GoMail/app/libs/ical"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/repository/models"
+)
+
+const (
+	// inviteMethodUpdate is accepted from clients and sent as a REQUEST
+	// with the caller-supplied (incremented) sequence, as iTIP requires
+	inviteMethodUpdate = "UPDATE"
+)
+
+// SendInvite sends an iCalendar meeting invitation, update or cancellation
+func (s *emailService) SendInvite(ctx context.Context, req SendInviteRequest) (*SendInviteResponse, error) {
+	// Build the calendar object from the structured event
+	event, err := toICalEvent(req)
+	if err != nil {
+		return &SendInviteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	content, err := ical.Build(event)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrInvalidInvite, err)
+		return &SendInviteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	// Default the recipients and subject from the event
+	to := req.To
+	if to == "" {
+		emails := make([]string, 0, len(event.Attendees))
+		for _, attendee := range event.Attendees {
+			emails = append(emails, attendee.Email)
+		}
+		to = strings.Join(emails, ",")
+	}
+	subject := req.Subject
+	if subject == "" {
+		subject = event.Summary
+	}
+
+	// Leave out suppressed attendees, sending nothing when none is left
+	to, suppressed, err := s.filterSuppressed(ctx, req.UserID, to, "", nil)
+	if err != nil {
+		return &SendInviteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	if to == "" && len(suppressed) > 0 {
+		s.logSuppressedRecipients(req.UserID, nil, req.From, req.To, subject, "text/calendar", suppressed)
+		return &SendInviteResponse{
+			Success:    false,
+			Status:     StatusSuppressed,
+			UID:        event.UID,
+			Sequence:   event.Sequence,
+			Suppressed: suppressed,
+		}, nil
+	}
+
+	// Persist the email for the queue workers when async or scheduled sending is requested
+	if req.Async || req.SendAt != nil {
+		resp, err := s.enqueue(ctx, &models.Email{
+			UserID:      req.UserID,
+			SendAt:      req.SendAt,
+			From:        req.From,
+			To:          to,
+			Subject:     subject,
+			Body:        req.Body,
+			ContentType: "text/calendar",
+			Calendar: &models.EmailCalendar{
+				Method:  event.Method,
+				Content: content,
+			},
+		})
+		return &SendInviteResponse{
+			Success:    resp.Success,
+			ID:         resp.ID,
+			Status:     resp.Status,
+			UID:        event.UID,
+			Sequence:   event.Sequence,
+			Error:      resp.Error,
+			Suppressed: suppressed,
+		}, err
+	}
+
+	// Create a request to the SMTP client
+	invite := libSmtp.CalendarInvite{
+		Method:  event.Method,
+		Content: content,
+	}
+	ctx, messageID := s.withMessageID(ctx, req.From)
+	err = s.client.SendCalendarInvite(ctx, req.From, to, subject, req.Body, invite)
+
+	// Create success/error response
+	success := err == nil
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	// Create email log
+	emailLog := &models.EmailLog{
+		MessageID:   messageID,
+		UserID:      req.UserID,
+		From:        req.From,
+		To:          to,
+		Subject:     subject,
+		ContentType: "text/calendar",
+		SentAt:      time.Now(),
+		Success:     success,
+		Error:       errMsg,
+		CreatedAt:   time.Now(),
+	}
+
+	// Log the email asynchronously
+	s.logEmailAttempt(emailLog)
+
+	// Return the response
+	if err != nil {
+		return &SendInviteResponse{
+			Success:    false,
+			UID:        event.UID,
+			Sequence:   event.Sequence,
+			Error:      err.Error(),
+			Suppressed: suppressed,
+		}, err
+	}
+
+	return &SendInviteResponse{
+		Success:    true,
+		UID:        event.UID,
+		Sequence:   event.Sequence,
+		Suppressed: suppressed,
+	}, nil
+}
+
+// toICalEvent converts the request event into the iCalendar representation
+func toICalEvent(req SendInviteRequest) (ical.Event, error) {
+	method := strings.ToUpper(strings.TrimSpace(req.Event.Method))
+	if method == "" {
+		method = ical.MethodRequest
+	}
+
+	uid := req.Event.UID
+	switch method {
+	case ical.MethodRequest:
+		if uid == "" {
+			uid = generateUID(req.Event.Organizer.Email)
+		}
+	case inviteMethodUpdate, ical.MethodCancel:
+		// Updates and cancellations must refer to a previously sent event
+		if uid == "" {
+			return ical.Event{}, fmt.Errorf("%w: uid is required for %s", ErrInvalidInvite, method)
+		}
+		if method == inviteMethodUpdate {
+			method = ical.MethodRequest
+		}
+	default:
+		return ical.Event{}, fmt.Errorf("%w: unsupported method %q", ErrInvalidInvite, req.Event.Method)
+	}
+
+	summary := req.Event.Summary
+	if summary == "" {
+		summary = req.Subject
+	}
+
+	attendees := make([]ical.Attendee, 0, len(req.Event.Attendees))
+	for _, attendee := range req.Event.Attendees {
+		attendees = append(attendees, ical.Attendee{
+			Name:  attendee.Name,
+			Email: attendee.Email,
+			Role:  attendee.Role,
+			RSVP:  attendee.RSVP,
+		})
+	}
+
+	return ical.Event{
+		Method:   method,
+		UID:      uid,
+		Sequence: req.Event.Sequence,
+		Organizer: ical.Person{
+			Name:  req.Event.Organizer.Name,
+			Email: req.Event.Organizer.Email,
+		},
+		Attendees:      attendees,
+		Summary:        summary,
+		Description:    req.Event.Description,
+		Location:       req.Event.Location,
+		Start:          req.Event.Start,
+		End:            req.Event.End,
+		TimeZone:       req.Event.TimeZone,
+		RecurrenceRule: req.Event.RecurrenceRule,
+	}, nil
+}
+
+// generateUID creates a globally unique event identifier
+func generateUID(organizer string) string {
+	domain := "gomail"
+	if _, host, ok := strings.Cut(organizer, "@"); ok && host != "" {
+		domain = host
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to a timestamp if the random source is unavailable
+		return fmt.Sprintf("%d@%s", time.Now().UnixNano(), domain)
+	}
+	return hex.EncodeToString(b) + "@" + domain
+}
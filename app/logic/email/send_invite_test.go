@@ -0,0 +1,211 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"GoMail/app/config"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtp/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+var (
+	validInviteEvent = CalendarEvent{
+		UID:       "event-1@example.com",
+		Organizer: CalendarPerson{Name: "Organizer", Email: "test@example.com"},
+		Attendees: []CalendarAttendee{
+			{Name: "Recipient", Email: "recipient@example.com", RSVP: true},
+		},
+		Start:    time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC),
+		End:      time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC),
+		TimeZone: "America/New_York",
+	}
+
+	validSendInviteRequest = SendInviteRequest{
+		From:    "test@example.com",
+		Subject: "Test Subject",
+		Body:    "Test Body",
+		Event:   validInviteEvent,
+	}
+
+	inviteTestError = errors.New("smtp error")
+)
+
+func buildInviteMockSMTPClient(method string, err error) *mocks.SMTPClient {
+	client := &mocks.SMTPClient{}
+	client.On("SendCalendarInvite",
+		mock.Anything,
+		"test@example.com",
+		"recipient@example.com",
+		"Test Subject",
+		"Test Body",
+		mock.MatchedBy(func(invite libSmtp.CalendarInvite) bool {
+			return invite.Method == method &&
+				strings.Contains(string(invite.Content), "METHOD:"+method) &&
+				strings.Contains(string(invite.Content), "UID:event-1@example.com")
+		})).Return(err)
+	return client
+}
+
+func TestEmailService_SendInvite(t *testing.T) {
+	type fields struct {
+		client *mocks.SMTPClient
+		repo   *repoMocks.Repository
+		config *config.Config
+	}
+
+	type args struct {
+		ctx context.Context
+		req SendInviteRequest
+	}
+
+	withMethod := func(method string) SendInviteRequest {
+		req := validSendInviteRequest
+		req.Event.Method = method
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *SendInviteResponse
+		wantErr error
+		wantLog bool
+	}{
+		{
+			name: "happy path",
+			fields: fields{
+				client: buildInviteMockSMTPClient("REQUEST", nil),
+				repo:   &repoMocks.Repository{},
+				config: &config.Config{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: validSendInviteRequest,
+			},
+			want:    &SendInviteResponse{Success: true, UID: "event-1@example.com"},
+			wantErr: nil,
+			wantLog: true,
+		},
+		{
+			name: "update is sent as request",
+			fields: fields{
+				client: buildInviteMockSMTPClient("REQUEST", nil),
+				repo:   &repoMocks.Repository{},
+				config: &config.Config{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: withMethod("update"),
+			},
+			want:    &SendInviteResponse{Success: true, UID: "event-1@example.com"},
+			wantErr: nil,
+			wantLog: true,
+		},
+		{
+			name: "cancel",
+			fields: fields{
+				client: buildInviteMockSMTPClient("CANCEL", nil),
+				repo:   &repoMocks.Repository{},
+				config: &config.Config{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: withMethod("CANCEL"),
+			},
+			want:    &SendInviteResponse{Success: true, UID: "event-1@example.com"},
+			wantErr: nil,
+			wantLog: true,
+		},
+		{
+			name: "smtp error",
+			fields: fields{
+				client: buildInviteMockSMTPClient("REQUEST", inviteTestError),
+				repo:   &repoMocks.Repository{},
+				config: &config.Config{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: validSendInviteRequest,
+			},
+			want:    &SendInviteResponse{Success: false, UID: "event-1@example.com", Error: "smtp error"},
+			wantErr: inviteTestError,
+			wantLog: true,
+		},
+		{
+			name: "invalid method",
+			fields: fields{
+				client: &mocks.SMTPClient{},
+				repo:   &repoMocks.Repository{},
+				config: &config.Config{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: withMethod("PUBLISH"),
+			},
+			want:    &SendInviteResponse{Success: false, Error: `invalid calendar invitation: unsupported method "PUBLISH"`},
+			wantErr: ErrInvalidInvite,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged <-chan *models.EmailLog
+			if tt.wantLog {
+				logged = handOffLogs(tt.fields.repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+			}
+
+			s := &emailService{
+				client: tt.fields.client,
+				repo:   tt.fields.repo,
+				config: tt.fields.config,
+			}
+
+			got, err := s.SendInvite(tt.args.ctx, tt.args.req)
+			if tt.wantLog {
+				awaitLog(t, logged)
+			}
+
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			tt.fields.client.AssertExpectations(t)
+			tt.fields.repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestToICalEvent(t *testing.T) {
+	t.Run("generates uid for new requests", func(t *testing.T) {
+		req := validSendInviteRequest
+		req.Event.UID = ""
+
+		event, err := toICalEvent(req)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(event.UID, "@example.com"))
+		assert.Equal(t, "Test Subject", event.Summary)
+	})
+
+	t.Run("requires uid for cancellations", func(t *testing.T) {
+		req := validSendInviteRequest
+		req.Event.UID = ""
+		req.Event.Method = "CANCEL"
+
+		_, err := toICalEvent(req)
+		assert.ErrorIs(t, err, ErrInvalidInvite)
+	})
+}
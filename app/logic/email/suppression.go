@@ -0,0 +1,162 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"GoMail/app/logic/suppression"
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StatusSuppressed is the status of a send that was dropped because all
+// of its recipients are on the suppression list
+const StatusSuppressed = string(models.EmailLogStatusSuppressed)
+
+// suppressionList looks up the suppressions that stop emails to an address
+type suppressionList interface {
+	Check(ctx context.Context, userID, address, list string) (*models.Suppression, error)
+}
+
+// filterSuppressed removes suppressed addresses from the comma-separated
+// recipients of an email sent to a list, which is empty for emails that
+// aren't, and tagged with a topic, which is nil for untagged ones. The
+// recipients are returned unchanged when none is suppressed, and empty when
+// all are.
+func (s *emailService) filterSuppressed(ctx context.Context, userID, to, list string, topic *models.Topic) (string, []SuppressedRecipient, error) {
+	if s.suppressions == nil && (topic == nil || s.preferences == nil) {
+		return to, nil, nil
+	}
+
+	var allowed []string
+	var suppressed []SuppressedRecipient
+	for _, address := range strings.Split(to, ",") {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
+		recipient, err := s.suppressedRecipient(ctx, userID, address, list, topic)
+		if err != nil {
+			return "", nil, err
+		}
+		if recipient == nil {
+			allowed = append(allowed, address)
+			continue
+		}
+		suppressed = append(suppressed, *recipient)
+	}
+
+	if len(suppressed) == 0 {
+		return to, nil, nil
+	}
+	return strings.Join(allowed, ","), suppressed, nil
+}
+
+// suppressedRecipient tells why an address doesn't receive an email, and
+// returns nil when it does. Unsubscribes don't stop emails of transactional
+// topics, and recipients who opted out of a topic don't receive the other
+// emails of it.
+func (s *emailService) suppressedRecipient(ctx context.Context, userID, address, list string, topic *models.Topic) (*SuppressedRecipient, error) {
+	transactional := topic != nil && topic.Transactional
+	if s.suppressions != nil {
+		found, err := s.suppressions.Check(ctx, userID, address, list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check the suppression list: %w", err)
+		}
+		if found != nil && !(transactional && found.Reason == models.SuppressionReasonUnsubscribe) {
+			return &SuppressedRecipient{
+				Address: address,
+				Value:   found.Value,
+				List:    found.List,
+				Reason:  found.Reason,
+				Scope:   found.Scope,
+			}, nil
+		}
+	}
+
+	if topic == nil || transactional || s.preferences == nil {
+		return nil, nil
+	}
+	value, err := suppression.NormalizeAddress(address)
+	if err != nil {
+		return nil, nil
+	}
+	optedOut, err := s.preferences.OptedOut(ctx, userID, value, topic.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the preferences of the recipients: %w", err)
+	}
+	if !optedOut {
+		return nil, nil
+	}
+	return &SuppressedRecipient{
+		Address: address,
+		Value:   value,
+		Reason:  models.SuppressionReasonUnsubscribe,
+		Scope:   models.SuppressionScopeUser,
+		Topic:   topic.Name,
+	}, nil
+}
+
+// withoutSuppressed calls send with the recipients that aren't suppressed
+// for an email tagged with a topic, which is empty for untagged ones. When
+// all of them are, nothing is sent and the suppressed send is logged.
+func (s *emailService) withoutSuppressed(ctx context.Context, userID, from, to, subject, contentType, topicName string, send func(to string) (*SendEmailResponse, error)) (*SendEmailResponse, error) {
+	topic, err := s.findTopic(ctx, userID, topicName)
+	if err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	allowed, suppressed, err := s.filterSuppressed(ctx, userID, to, "", topic)
+	if err != nil {
+		return &SendEmailResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+	if allowed == "" && len(suppressed) > 0 {
+		s.logSuppressedRecipients(userID, nil, from, to, subject, contentType, suppressed)
+		return &SendEmailResponse{
+			Success:    false,
+			Status:     StatusSuppressed,
+			Suppressed: suppressed,
+		}, nil
+	}
+
+	resp, err := send(allowed)
+	if resp != nil {
+		resp.Suppressed = suppressed
+	}
+	return resp, err
+}
+
+// logSuppressedRecipients logs a send dropped because all of its
+// recipients are suppressed
+func (s *emailService) logSuppressedRecipients(userID string, emailID *primitive.ObjectID, from, to, subject, contentType string, suppressed []SuppressedRecipient) {
+	s.logEmailAttempt(&models.EmailLog{
+		UserID:      userID,
+		EmailID:     emailID,
+		From:        from,
+		To:          to,
+		Subject:     subject,
+		ContentType: contentType,
+		Success:     false,
+		Status:      models.EmailLogStatusSuppressed,
+		Error:       suppressedError(suppressed),
+		SentAt:      time.Now(),
+		CreatedAt:   time.Now(),
+	})
+}
+
+// suppressedError describes why recipients were suppressed
+func suppressedError(suppressed []SuppressedRecipient) string {
+	reasons := make([]string, 0, len(suppressed))
+	for _, recipient := range suppressed {
+		reasons = append(reasons, fmt.Sprintf("%s (%s)", recipient.Address, recipient.Reason))
+	}
+	return "recipients are suppressed: " + strings.Join(reasons, ", ")
+}
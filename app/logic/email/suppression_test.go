@@ -0,0 +1,139 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/smtp/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeSuppressions suppresses the addresses it holds, for all emails or
+// those of their list
+type fakeSuppressions map[string]*models.Suppression
+
+func (f fakeSuppressions) Check(ctx context.Context, userID, address, list string) (*models.Suppression, error) {
+	if address == "broken@example.com" {
+		return nil, errors.New("connection refused")
+	}
+	suppression := f[strings.ToLower(address)]
+	if suppression != nil && suppression.List != "" && suppression.List != list {
+		return nil, nil
+	}
+	return suppression, nil
+}
+
+var bounced = fakeSuppressions{
+	"ana@example.com": {Value: "ana@example.com", Reason: models.SuppressionReasonHardBounce, Scope: models.SuppressionScopeUser},
+	"bo@example.com":  {Value: "example.com", Reason: models.SuppressionReasonManual, Scope: models.SuppressionScopeGlobal},
+}
+
+func TestEmailService_Send_Suppressed(t *testing.T) {
+	tests := []struct {
+		name           string
+		to             string
+		wantTo         string
+		wantStatus     string
+		wantSuppressed []string
+		wantErr        bool
+	}{
+		{name: "not suppressed", to: "cy@example.org", wantTo: "cy@example.org"},
+		{name: "some recipients suppressed", to: "Ana@example.com, cy@example.org", wantTo: "cy@example.org", wantSuppressed: []string{"Ana@example.com"}},
+		{name: "all recipients suppressed", to: "ana@example.com,bo@example.com", wantStatus: StatusSuppressed, wantSuppressed: []string{"ana@example.com", "bo@example.com"}},
+		{name: "lookup fails", to: "broken@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.SMTPClient{}
+			client.On("Send", mock.Anything, "sender@example.com", tt.wantTo, "Hi", "Hello").Return(nil)
+
+			repo := &repoMocks.Repository{}
+			logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+			s := &emailService{client: client, repo: repo, suppressions: bounced, config: &config.Config{}}
+
+			got, err := s.Send(context.Background(), SendEmailRequest{UserID: "user-1", From: "sender@example.com", To: tt.to, Subject: "Hi", Body: "Hello"})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				client.AssertNotCalled(t, "Send")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			var addresses []string
+			for _, suppressed := range got.Suppressed {
+				addresses = append(addresses, suppressed.Address)
+			}
+			assert.Equal(t, tt.wantSuppressed, addresses)
+			if tt.wantTo == "" {
+				assert.False(t, got.Success)
+				client.AssertNotCalled(t, "Send")
+				log := awaitLog(t, logged)
+				assert.Equal(t, models.EmailLogStatusSuppressed, log.Status)
+				assert.Equal(t, "user-1", log.UserID)
+				return
+			}
+			assert.True(t, got.Success)
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmailService_SendBulk_Suppressed(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("Send", mock.Anything, "a@example.com", "cy@example.org", "Hi", "Hello").Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, suppressions: bounced, config: &config.Config{}}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		UserID: "user-1",
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "ana@example.com", Subject: "Hi", Body: "Hello"},
+			{From: "a@example.com", To: "bo@example.com,cy@example.org", Subject: "Hi", Body: "Hello"},
+		},
+	})
+
+	require.NoError(t, err)
+	for range got.Results {
+		awaitLog(t, logged)
+	}
+	assert.False(t, got.Results[0].Success)
+	assert.Equal(t, StatusSuppressed, got.Results[0].Status)
+	require.Len(t, got.Results[0].Suppressed, 1)
+	assert.Equal(t, models.SuppressionReasonHardBounce, got.Results[0].Suppressed[0].Reason)
+	assert.True(t, got.Results[1].Success)
+	require.Len(t, got.Results[1].Suppressed, 1)
+	assert.Equal(t, "example.com", got.Results[1].Suppressed[0].Value)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_Deliver_Suppressed(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, suppressions: bounced, config: &config.Config{}}
+
+	err := s.Deliver(context.Background(), &models.Email{ID: primitive.NewObjectID(), UserID: "user-1", From: "a@example.com", To: "ana@example.com", Subject: "Hi"})
+
+	assert.ErrorIs(t, err, ErrRecipientSuppressed)
+	client.AssertNotCalled(t, "Send")
+	log := awaitLog(t, logged)
+	assert.Equal(t, models.EmailLogStatusSuppressed, log.Status)
+	assert.NotNil(t, log.EmailID)
+}
@@ -0,0 +1,31 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"GoMail/app/logic/emailtemplate"
+)
+
+// renderTemplate renders the published version of a stored template and
+// returns the subject and the requested body part. A subject given on the
+// request overrides the template subject.
+func (s *emailService) renderTemplate(ctx context.Context, userID, templateID string, req emailtemplate.RenderRequest, subject string, html bool) (string, string, error) {
+	rendered, err := s.templates.Render(ctx, userID, templateID, req)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	body, part := rendered.Text, "text"
+	if html {
+		body, part = rendered.HTML, "html"
+	}
+	if body == "" {
+		return "", "", fmt.Errorf("%w: template %s has no %s part", ErrInvalidTemplate, templateID, part)
+	}
+
+	if subject == "" {
+		subject = rendered.Subject
+	}
+	return subject, body, nil
+}
@@ -0,0 +1,123 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/smtp/mocks"
+	"GoMail/app/logic/emailtemplate"
+	templateMocks "GoMail/app/logic/emailtemplate/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+)
+
+func TestEmailService_SendHTML_Template(t *testing.T) {
+	data := map[string]interface{}{"name": "Ada"}
+
+	templates := &templateMocks.Service{}
+	templates.On("Render", mock.Anything, "user-1", "tmpl-1", emailtemplate.RenderRequest{
+		Data:      data,
+		Locale:    "de",
+		Recipient: "recipient@example.com",
+	}).
+		Return(&emailtemplate.Rendered{Subject: "Hi Ada", HTML: "<p>Hi Ada</p>", Version: 1}, nil)
+
+	client := &mocks.SMTPClient{}
+	client.On("SendHTML", mock.Anything, "sender@example.com", "recipient@example.com", "Hi Ada", "<p>Hi Ada</p>").Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, templates: templates, config: &config.Config{}}
+
+	got, err := s.SendHTML(context.Background(), SendEmailRequest{
+		UserID:     "user-1",
+		From:       "sender@example.com",
+		To:         "recipient@example.com",
+		TemplateID: "tmpl-1",
+		Data:       data,
+		Locale:     "de",
+	})
+
+	awaitLog(t, logged)
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_Send_TemplateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		rendered *emailtemplate.Rendered
+		err      error
+	}{
+		{
+			name: "missing variable",
+			err:  fmt.Errorf("%w %q in text", emailtemplate.ErrMissingVariable, "name"),
+		},
+		{
+			name: "unknown template",
+			err:  emailtemplate.ErrTemplateNotFound,
+		},
+		{
+			name:     "no text part",
+			rendered: &emailtemplate.Rendered{Subject: "s", HTML: "<p>only html</p>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates := &templateMocks.Service{}
+			templates.On("Render", mock.Anything, "", "tmpl-1", emailtemplate.RenderRequest{Recipient: "b@example.com"}).Return(tt.rendered, tt.err)
+
+			client := &mocks.SMTPClient{}
+			s := &emailService{client: client, templates: templates, config: &config.Config{}}
+
+			got, err := s.Send(context.Background(), SendEmailRequest{From: "a@example.com", To: "b@example.com", TemplateID: "tmpl-1"})
+
+			assert.ErrorIs(t, err, ErrInvalidTemplate)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			assert.False(t, got.Success)
+			client.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEmailService_SendBulk_Template(t *testing.T) {
+	templates := &templateMocks.Service{}
+	templates.On("Render", mock.Anything, "user-1", "good", emailtemplate.RenderRequest{Data: map[string]interface{}{"n": 1}, Recipient: "b@example.com"}).
+		Return(&emailtemplate.Rendered{Subject: "Order 1", Text: "Shipped 1"}, nil)
+	templates.On("Render", mock.Anything, "user-1", "good", emailtemplate.RenderRequest{Data: map[string]interface{}{}, Recipient: "c@example.com"}).
+		Return(nil, fmt.Errorf("%w %q in text", emailtemplate.ErrMissingVariable, "n"))
+
+	client := &mocks.SMTPClient{}
+	client.On("Send", mock.Anything, "a@example.com", "b@example.com", "Order 1", "Shipped 1").Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, templates: templates, config: &config.Config{}}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		UserID: "user-1",
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "b@example.com", TemplateID: "good", Data: map[string]interface{}{"n": 1}},
+			{From: "a@example.com", To: "c@example.com", TemplateID: "good", Data: map[string]interface{}{}},
+		},
+	})
+
+	assert.NoError(t, err)
+	// Emails rejected before sending aren't logged
+	awaitLog(t, logged)
+	assert.True(t, got.Results[0].Success)
+	assert.False(t, got.Results[1].Success)
+	assert.Contains(t, got.Results[1].Error, `missing template variable "n"`)
+	client.AssertNumberOfCalls(t, "Send", 1)
+}
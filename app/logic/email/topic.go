@@ -0,0 +1,62 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"GoMail/app/logic/topic"
+	"GoMail/app/repository/models"
+)
+
+// topicCatalog looks up the topics sends are tagged with
+type topicCatalog interface {
+	Find(ctx context.Context, userID, name string) (*models.Topic, error)
+}
+
+// recipientPreferences tells which topics recipients opted out of
+type recipientPreferences interface {
+	OptedOut(ctx context.Context, userID, email, topic string) (bool, error)
+}
+
+// findTopic returns the topic of the user a send is tagged with, which is
+// nil for untagged sends
+func (s *emailService) findTopic(ctx context.Context, userID, name string) (*models.Topic, error) {
+	if name == "" || s.topics == nil {
+		return nil, nil
+	}
+	t, err := s.topics.Find(ctx, userID, name)
+	if err != nil {
+		if errors.Is(err, topic.ErrTopicNotFound) {
+			return nil, fmt.Errorf("%w: unknown topic %q", ErrInvalidTopic, name)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// topicName returns the name of a topic, which is empty for none
+func topicName(t *models.Topic) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// withTopicList returns the unsubscribe options of an email tagged with a
+// topic, whose list defaults to the topic. Recipients can't unsubscribe
+// from transactional topics.
+func withTopicList(opts *UnsubscribeOptions, t *models.Topic) (*UnsubscribeOptions, error) {
+	if opts == nil || t == nil {
+		return opts, nil
+	}
+	if t.Transactional {
+		return nil, fmt.Errorf("%w: topic %q is transactional", ErrInvalidUnsubscribe, t.Name)
+	}
+	if unsubscribeList(opts) != "" {
+		return opts, nil
+	}
+	withList := *opts
+	withList.List = t.Name
+	return &withList, nil
+}
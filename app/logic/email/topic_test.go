@@ -0,0 +1,129 @@
+package email
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/smtp/mocks"
+	"GoMail/app/logic/topic"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeTopics holds the topics of a user
+type fakeTopics map[string]*models.Topic
+
+func (f fakeTopics) Find(ctx context.Context, userID, name string) (*models.Topic, error) {
+	if t := f[name]; t != nil {
+		return t, nil
+	}
+	return nil, topic.ErrTopicNotFound
+}
+
+// fakePreferences holds the topics recipients opted out of
+type fakePreferences map[string][]string
+
+func (f fakePreferences) OptedOut(ctx context.Context, userID, email, topic string) (bool, error) {
+	return slices.Contains(f[email], topic), nil
+}
+
+var topics = fakeTopics{
+	"billing": {Name: "billing", Transactional: true},
+	"news":    {Name: "news"},
+}
+
+func TestEmailService_SendBulk_Topics(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("Send", mock.Anything, "a@example.com", "cy@example.org", "Invoice", "Hello").Return(nil)
+	client.On("Send", mock.Anything, "a@example.com", "bo@example.org", "News", "Hello").Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	links := &fakeUnsubscribes{}
+	s := &emailService{
+		client:       client,
+		repo:         repo,
+		unsubscribes: links,
+		topics:       topics,
+		preferences:  fakePreferences{"ada@example.org": {"news"}},
+		suppressions: fakeSuppressions{
+			"cy@example.org": {Value: "cy@example.org", Reason: models.SuppressionReasonUnsubscribe, Scope: models.SuppressionScopeUser},
+		},
+		config: &config.Config{},
+	}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		UserID: "user-1",
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "Ada@example.org", Subject: "News", Body: "Hello", Topic: "news"},
+			{From: "a@example.com", To: "cy@example.org", Subject: "Invoice", Body: "Hello", Topic: "billing"},
+			{From: "a@example.com", To: "cy@example.org", Subject: "News", Body: "Hello", Topic: "news"},
+			{From: "a@example.com", To: "bo@example.org", Subject: "Sale", Body: "Hello", Topic: "sales"},
+			{From: "a@example.com", To: "bo@example.org", Subject: "Invoice", Body: "Hello", Topic: "billing", Unsubscribe: &UnsubscribeOptions{}},
+			{From: "a@example.com", To: "bo@example.org", Subject: "News", Body: "Hello", Topic: "news", Unsubscribe: &UnsubscribeOptions{}},
+		},
+	})
+
+	require.NoError(t, err)
+	// Two emails are sent and two suppressed, the rejected ones aren't logged
+	for range 4 {
+		awaitLog(t, logged)
+	}
+	assert.Equal(t, StatusSuppressed, got.Results[0].Status)
+	assert.Equal(t, "news", got.Results[0].Suppressed[0].Topic)
+	assert.Equal(t, "ada@example.org", got.Results[0].Suppressed[0].Value)
+	assert.True(t, got.Results[1].Success, "unsubscribes don't stop transactional topics")
+	assert.Equal(t, StatusSuppressed, got.Results[2].Status)
+	assert.Contains(t, got.Results[3].Error, ErrInvalidTopic.Error())
+	assert.Contains(t, got.Results[4].Error, ErrInvalidUnsubscribe.Error())
+	assert.True(t, got.Results[5].Success)
+	assert.Equal(t, []string{"https://mail.example.com/u/user-1/bo@example.org/news"}, links.links)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_Send_UnknownTopic(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	s := &emailService{client: client, topics: topics, config: &config.Config{}}
+
+	_, err := s.Send(context.Background(), SendEmailRequest{UserID: "user-1", From: "a@example.com", To: "ada@example.org", Subject: "Hi", Body: "Hello", Topic: "sales"})
+
+	assert.ErrorIs(t, err, ErrInvalidTopic)
+	client.AssertNotCalled(t, "Send")
+}
+
+func TestEmailService_Deliver_Topic(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("Send", mock.Anything, "a@example.com", "bo@example.org", "Hi", "Hello").Return(nil)
+
+	repo := &repoMocks.Repository{}
+	claimDeliveries(repo)
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{
+		client:      client,
+		repo:        repo,
+		topics:      topics,
+		preferences: fakePreferences{"ada@example.org": {"news"}},
+		config:      &config.Config{},
+	}
+
+	// Ada opted out since the email was queued
+	err := s.Deliver(context.Background(), &models.Email{ID: primitive.NewObjectID(), UserID: "user-1", From: "a@example.com", To: "ada@example.org,bo@example.org", Subject: "Hi", Body: "Hello", ContentType: "text/plain", Topic: "news"})
+	require.NoError(t, err)
+
+	// Topics deleted since the email was queued no longer apply
+	err = s.Deliver(context.Background(), &models.Email{ID: primitive.NewObjectID(), UserID: "user-1", From: "a@example.com", To: "bo@example.org", Subject: "Hi", Body: "Hello", ContentType: "text/plain", Topic: "deleted"})
+	require.NoError(t, err)
+	awaitLog(t, logged)
+	awaitLog(t, logged)
+
+	client.AssertNumberOfCalls(t, "Send", 2)
+}
@@ -0,0 +1,181 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strings"
+
+	"GoMail/app/libs/htmlmail"
+	libSmtp "GoMail/app/libs/smtp"
+)
+
+// emailTracker creates the addresses of tracking pixels and tracked links
+type emailTracker interface {
+	PixelURL(userID, messageID, recipient string) string
+	ClickURL(userID, messageID, recipient, url string) string
+}
+
+// tracksOpens tells whether open tracking is requested for an HTML email,
+// falling back to the configured default
+func (s *emailService) tracksOpens(opts *HTMLOptions) bool {
+	if opts != nil && opts.TrackOpens != nil {
+		return *opts.TrackOpens
+	}
+	return s.config.Tracking.Opens
+}
+
+// tracksClicks tells whether click tracking is requested for an HTML
+// email, falling back to the configured default
+func (s *emailService) tracksClicks(opts *HTMLOptions) bool {
+	if opts != nil && opts.TrackClicks != nil {
+		return *opts.TrackClicks
+	}
+	return s.config.Tracking.Clicks
+}
+
+// withTracking prepares an HTML body for delivery of a message: it adds
+// the configured UTM parameters to its links, rewrites them to tracked
+// links and adds the tracking pixel
+func (s *emailService) withTracking(body, userID, messageID, to string, opens, clicks bool) string {
+	body = s.withTrackedLinks(body, userID, messageID, to, clicks)
+	return s.withOpenPixel(body, userID, messageID, to, opens)
+}
+
+// sendTracked sends an HTML body with the tracking of a message. Events are
+// recorded per recipient, so a tracked email to several recipients is sent
+// as one copy per recipient with its own pixel and links. Every copy lists
+// all recipients in To and carries the same Message-ID.
+func (s *emailService) sendTracked(ctx context.Context, body, userID, messageID, to string, opens, clicks bool, send func(ctx context.Context, body string) error) error {
+	var recipients []string
+	for _, address := range strings.Split(to, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			recipients = append(recipients, address)
+		}
+	}
+	if len(recipients) < 2 || s.tracker == nil || (!opens && !clicks) {
+		return send(ctx, s.withTracking(body, userID, messageID, to, opens, clicks))
+	}
+
+	for _, recipient := range recipients {
+		copyCtx := libSmtp.WithRecipients(ctx, recipient)
+		if err := send(copyCtx, s.withTracking(body, userID, messageID, recipient, opens, clicks)); err != nil {
+			return fmt.Errorf("failed to send to %s: %w", recipient, err)
+		}
+	}
+	return nil
+}
+
+// trackedRecipient returns the single recipient of an email, or an empty
+// string when it has several
+func trackedRecipient(to string) string {
+	recipient := strings.TrimSpace(to)
+	if strings.Contains(recipient, ",") {
+		return ""
+	}
+	return recipient
+}
+
+// withOpenPixel adds the tracking pixel of a message to the end of an HTML
+// body
+func (s *emailService) withOpenPixel(body, userID, messageID, to string, track bool) string {
+	if !track || s.tracker == nil {
+		return body
+	}
+
+	pixel := `<img src="` + html.EscapeString(s.tracker.PixelURL(userID, messageID, trackedRecipient(to))) +
+		`" width="1" height="1" alt="" style="display:block;width:1px;height:1px;border:0">`
+
+	if i := strings.LastIndex(strings.ToLower(body), "</body>"); i >= 0 {
+		return body[:i] + pixel + body[i:]
+	}
+	return body + pixel
+}
+
+// withTrackedLinks adds the UTM parameters to the http and https links of
+// an HTML body and, when tracked, rewrites them to the redirect endpoint.
+// Links to GoMail itself, such as download links, are left alone. The body
+// is sent unchanged when it can't be parsed.
+func (s *emailService) withTrackedLinks(body, userID, messageID, to string, track bool) string {
+	track = track && s.tracker != nil
+	utm := s.utmParams()
+	if !track && len(utm) == 0 {
+		return body
+	}
+
+	doc, err := htmlmail.Parse(body)
+	if err != nil {
+		log.Printf("Failed to track links of %s: %v", messageID, err)
+		return body
+	}
+
+	publicURL := strings.TrimRight(s.config.Server.PublicURL, "/") + "/"
+	recipient := trackedRecipient(to)
+	doc.RewriteLinks(func(href string) string {
+		target := strings.TrimSpace(href)
+		lower := strings.ToLower(target)
+		web := strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+		if !web || (publicURL != "/" && strings.HasPrefix(target, publicURL)) {
+			return href
+		}
+
+		target = withUTM(target, utm)
+		if !track {
+			return target
+		}
+		return s.tracker.ClickURL(userID, messageID, recipient, target)
+	})
+
+	tracked, err := doc.HTML()
+	if err != nil {
+		log.Printf("Failed to track links of %s: %v", messageID, err)
+		return body
+	}
+	return tracked
+}
+
+// utmParams returns the configured UTM parameters that are set
+func (s *emailService) utmParams() url.Values {
+	params := url.Values{}
+	for key, value := range map[string]string{
+		"utm_source":   s.config.Tracking.UTM.Source,
+		"utm_medium":   s.config.Tracking.UTM.Medium,
+		"utm_campaign": s.config.Tracking.UTM.Campaign,
+	} {
+		if value != "" {
+			params.Set(key, value)
+		}
+	}
+	return params
+}
+
+// withUTM adds the UTM parameters a link doesn't carry yet to the end of
+// its query, keeping the existing query as it is
+func withUTM(link string, utm url.Values) string {
+	if len(utm) == 0 {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	existing := u.Query()
+	missing := url.Values{}
+	for key, values := range utm {
+		if !existing.Has(key) {
+			missing[key] = values
+		}
+	}
+	if len(missing) == 0 {
+		return link
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery += "&"
+	}
+	u.RawQuery += missing.Encode()
+	return u.String()
+}
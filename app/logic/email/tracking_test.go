@@ -0,0 +1,209 @@
+package email
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/smtp/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+)
+
+// fakeTracker builds pixel and link addresses from what it is given
+type fakeTracker struct{}
+
+func (fakeTracker) PixelURL(userID, messageID, recipient string) string {
+	return "https://mail.example.com/t/o/" + userID + "?r=" + recipient + "&m=" + messageID
+}
+
+func (fakeTracker) ClickURL(userID, messageID, recipient, link string) string {
+	return "https://mail.example.com/t/c/" + userID + "?r=" + recipient + "&u=" + url.QueryEscape(link)
+}
+
+func TestEmailService_withOpenPixel(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		to    string
+		track bool
+		want  string
+	}{
+		{name: "disabled", body: "<p>Hi</p>", to: "ada@example.org", want: "<p>Hi</p>"},
+		{
+			name:  "before the closing body tag",
+			body:  "<html><body><p>Hi</p></BODY></html>",
+			to:    "ada@example.org",
+			track: true,
+			want:  `<html><body><p>Hi</p><img src="https://mail.example.com/t/o/user-1?r=ada@example.org&amp;m=&lt;abc@example.com&gt;" width="1" height="1" alt="" style="display:block;width:1px;height:1px;border:0"></BODY></html>`,
+		},
+		{
+			name:  "appended to a fragment",
+			body:  "<p>Hi</p>",
+			to:    "ada@example.org, bob@example.org",
+			track: true,
+			want:  `<p>Hi</p><img src="https://mail.example.com/t/o/user-1?r=&amp;m=&lt;abc@example.com&gt;" width="1" height="1" alt="" style="display:block;width:1px;height:1px;border:0">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &emailService{tracker: fakeTracker{}, config: &config.Config{}}
+
+			got := s.withOpenPixel(tt.body, "user-1", "<abc@example.com>", tt.to, tt.track)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEmailService_tracksOpens(t *testing.T) {
+	on, off := true, false
+
+	s := &emailService{config: &config.Config{Tracking: config.TrackingConfig{Opens: true}}}
+	assert.True(t, s.tracksOpens(nil))
+	assert.False(t, s.tracksOpens(&HTMLOptions{TrackOpens: &off}))
+
+	s = &emailService{config: &config.Config{}}
+	assert.False(t, s.tracksOpens(&HTMLOptions{}))
+	assert.True(t, s.tracksOpens(&HTMLOptions{TrackOpens: &on}))
+}
+
+func TestEmailService_withTrackedLinks(t *testing.T) {
+	const body = `<p><a href="https://shop.example.com/order?id=7">Order</a> ` +
+		`<a href="https://shop.example.com/help?utm_source=footer" data-notrack>Help</a> ` +
+		`<a href="https://shop.example.com/faq?utm_source=footer">FAQ</a> ` +
+		`<a href="mailto:help@example.com">Mail us</a> ` +
+		`<a href="https://mail.example.com/files/abc">Invoice</a></p>`
+
+	tests := []struct {
+		name  string
+		track bool
+		utm   config.UTMConfig
+		want  []string
+	}{
+		{
+			name: "disabled",
+			want: []string{body},
+		},
+		{
+			name:  "tracked",
+			track: true,
+			want: []string{
+				`<a href="https://mail.example.com/t/c/user-1?r=ada@example.org&amp;u=https%3A%2F%2Fshop.example.com%2Forder%3Fid%3D7">Order</a>`,
+				`<a href="https://shop.example.com/help?utm_source=footer">Help</a>`,
+				`<a href="mailto:help@example.com">Mail us</a>`,
+				`<a href="https://mail.example.com/files/abc">Invoice</a>`,
+			},
+		},
+		{
+			name: "utm only",
+			utm:  config.UTMConfig{Source: "gomail", Campaign: "spring sale"},
+			want: []string{
+				`<a href="https://shop.example.com/order?id=7&amp;utm_campaign=spring+sale&amp;utm_source=gomail">Order</a>`,
+				`<a href="https://shop.example.com/help?utm_source=footer">Help</a>`,
+				`<a href="https://shop.example.com/faq?utm_source=footer&amp;utm_campaign=spring+sale">FAQ</a>`,
+			},
+		},
+		{
+			name:  "utm added before tracking",
+			track: true,
+			utm:   config.UTMConfig{Medium: "email"},
+			want: []string{
+				`u=https%3A%2F%2Fshop.example.com%2Forder%3Fid%3D7%26utm_medium%3Demail">Order</a>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &emailService{tracker: fakeTracker{}, config: &config.Config{
+				Server:   config.ServerConfig{PublicURL: "https://mail.example.com"},
+				Tracking: config.TrackingConfig{UTM: tt.utm},
+			}}
+
+			got := s.withTrackedLinks(body, "user-1", "<abc@example.com>", "ada@example.org", tt.track)
+
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+		})
+	}
+}
+
+func TestEmailService_SendHTML_TrackOpens(t *testing.T) {
+	on := true
+
+	client := &mocks.SMTPClient{}
+	client.On("SendHTML", mock.Anything, "sender@example.com", "recipient@example.com", "Shipped",
+		mock.MatchedBy(func(html string) bool {
+			return strings.HasPrefix(html, "<p>Shipped</p>") &&
+				strings.Contains(html, `<img src="https://mail.example.com/t/o/user-1?r=recipient@example.com`)
+		})).Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	s := &emailService{client: client, repo: repo, tracker: fakeTracker{}, config: &config.Config{}}
+
+	got, err := s.SendHTML(context.Background(), SendEmailRequest{
+		UserID:      "user-1",
+		From:        "sender@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Shipped",
+		Body:        "<p>Shipped</p>",
+		HTMLOptions: &HTMLOptions{TrackOpens: &on},
+	})
+
+	awaitLog(t, logged)
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	client.AssertExpectations(t)
+}
+
+func TestEmailService_SendHTML_TrackOpens_Recipients(t *testing.T) {
+	on := true
+	to := "ada@example.org, bob@example.org"
+
+	bodies := make(chan string, 2)
+	client := &mocks.SMTPClient{}
+	client.On("SendHTML", mock.Anything, "sender@example.com", to, "Shipped", mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		bodies <- args.String(4)
+	}).Return(nil).Twice()
+
+	repo := &repoMocks.Repository{}
+	repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil)
+
+	s := &emailService{client: client, repo: repo, tracker: fakeTracker{}, config: &config.Config{}}
+
+	got, err := s.SendHTML(context.Background(), SendEmailRequest{
+		UserID:      "user-1",
+		From:        "sender@example.com",
+		To:          to,
+		Subject:     "Shipped",
+		Body:        `<p>Shipped, <a href="https://shop.example.com/orders">track it</a></p>`,
+		HTMLOptions: &HTMLOptions{TrackOpens: &on, TrackClicks: &on},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, got.Success)
+	client.AssertExpectations(t)
+
+	// Each recipient gets a copy with its own pixel and links
+	first, second := <-bodies, <-bodies
+	for i, recipient := range []string{"ada@example.org", "bob@example.org"} {
+		body := []string{first, second}[i]
+		assert.Contains(t, body, `<img src="https://mail.example.com/t/o/user-1?r=`+recipient+`&amp;m=`)
+		assert.Contains(t, body, `https://mail.example.com/t/c/user-1?r=`+recipient+`&amp;u=`)
+	}
+	messageID := func(body string) string {
+		start := strings.Index(body, "&amp;m=")
+		return body[start : start+strings.Index(body[start:], `"`)]
+	}
+	assert.Equal(t, messageID(first), messageID(second))
+}
@@ -0,0 +1,57 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	libSmtp "GoMail/app/libs/smtp"
+)
+
+// maxListLength is the longest list name an email may be sent to
+const maxListLength = 100
+
+// unsubscribeLinks creates the signed addresses recipients unsubscribe at
+type unsubscribeLinks interface {
+	URL(userID, messageID, recipient, list string) string
+}
+
+// validateUnsubscribe checks the unsubscribe options of an email. The
+// link is personal, so it can only be added to emails to one recipient.
+func validateUnsubscribe(opts *UnsubscribeOptions, to string) error {
+	if opts == nil {
+		return nil
+	}
+	if trackedRecipient(to) == "" {
+		return fmt.Errorf("%w: unsubscribe links need a single recipient", ErrInvalidUnsubscribe)
+	}
+	list := strings.TrimSpace(opts.List)
+	if len(list) > maxListLength || strings.ContainsAny(list, "\r\n") {
+		return fmt.Errorf("%w: invalid list %q", ErrInvalidUnsubscribe, opts.List)
+	}
+	return nil
+}
+
+// unsubscribeList returns the list an email is sent to, which is empty
+// without unsubscribe options
+func unsubscribeList(opts *UnsubscribeOptions) string {
+	if opts == nil {
+		return ""
+	}
+	return strings.TrimSpace(opts.List)
+}
+
+// withUnsubscribe returns a context whose sends carry the List-Unsubscribe
+// headers of a message, with the one-click POST of RFC 8058
+func (s *emailService) withUnsubscribe(ctx context.Context, userID, messageID, to, list string) context.Context {
+	recipient := trackedRecipient(to)
+	if s.unsubscribes == nil || recipient == "" {
+		return ctx
+	}
+
+	link := s.unsubscribes.URL(userID, messageID, recipient, list)
+	return libSmtp.WithHeaders(ctx,
+		libSmtp.Header{Name: "List-Unsubscribe", Value: "<" + link + ">"},
+		libSmtp.Header{Name: "List-Unsubscribe-Post", Value: "List-Unsubscribe=One-Click"},
+	)
+}
@@ -0,0 +1,100 @@
+package email
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/smtp/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUnsubscribes records the links it creates
+type fakeUnsubscribes struct {
+	mu    sync.Mutex
+	links []string
+}
+
+func (f *fakeUnsubscribes) URL(userID, messageID, recipient, list string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	link := "https://mail.example.com/u/" + userID + "/" + recipient + "/" + list
+	f.links = append(f.links, link)
+	return link
+}
+
+func TestValidateUnsubscribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *UnsubscribeOptions
+		to      string
+		wantErr bool
+	}{
+		{name: "not requested", to: "ada@example.org,bo@example.org"},
+		{name: "all emails", opts: &UnsubscribeOptions{}, to: "ada@example.org"},
+		{name: "list", opts: &UnsubscribeOptions{List: "news"}, to: "ada@example.org"},
+		{name: "several recipients", opts: &UnsubscribeOptions{}, to: "ada@example.org,bo@example.org", wantErr: true},
+		{name: "line break in list", opts: &UnsubscribeOptions{List: "news\nBcc: eve@example.com"}, to: "ada@example.org", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUnsubscribe(tt.opts, tt.to)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidUnsubscribe)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEmailService_SendBulk_Unsubscribe(t *testing.T) {
+	client := &mocks.SMTPClient{}
+	client.On("Send", mock.Anything, "a@example.com", "bo@example.org", "News", "Hello").Return(nil)
+	client.On("Send", mock.Anything, "a@example.com", "ada@example.org", "Invoice", "Hello").Return(nil)
+
+	repo := &repoMocks.Repository{}
+	logged := handOffLogs(repo.On("SaveEmailLog", mock.Anything, mock.AnythingOfType("*models.EmailLog")).Return(nil))
+
+	links := &fakeUnsubscribes{}
+	s := &emailService{
+		client:       client,
+		repo:         repo,
+		unsubscribes: links,
+		suppressions: fakeSuppressions{
+			"ada@example.org": {Value: "ada@example.org", List: "news", Reason: models.SuppressionReasonUnsubscribe, Scope: models.SuppressionScopeUser},
+		},
+		config: &config.Config{},
+	}
+
+	got, err := s.SendBulk(context.Background(), SendBulkEmailRequest{
+		UserID: "user-1",
+		Emails: []BulkEmail{
+			{From: "a@example.com", To: "ada@example.org", Subject: "News", Body: "Hello", Unsubscribe: &UnsubscribeOptions{List: "news"}},
+			{From: "a@example.com", To: "bo@example.org", Subject: "News", Body: "Hello", Unsubscribe: &UnsubscribeOptions{List: "news"}},
+			{From: "a@example.com", To: "ada@example.org", Subject: "Invoice", Body: "Hello"},
+			{From: "a@example.com", To: "ada@example.org,bo@example.org", Subject: "News", Body: "Hello", Unsubscribe: &UnsubscribeOptions{}},
+		},
+	})
+
+	require.NoError(t, err)
+	// Two emails are sent and one suppressed, the rejected one isn't logged
+	for range 3 {
+		awaitLog(t, logged)
+	}
+	assert.Equal(t, StatusSuppressed, got.Results[0].Status)
+	assert.Equal(t, "news", got.Results[0].Suppressed[0].List)
+	assert.True(t, got.Results[1].Success)
+	assert.True(t, got.Results[2].Success)
+	assert.False(t, got.Results[3].Success)
+	assert.Contains(t, got.Results[3].Error, ErrInvalidUnsubscribe.Error())
+	assert.Equal(t, []string{"https://mail.example.com/u/user-1/bo@example.org/news"}, links.links)
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,130 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/lease"
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository"
+	emailRepo "GoMail/app/repository/email"
+	"GoMail/app/repository/models"
+)
+
+// deliverer sends a single queued email
+type deliverer interface {
+	Deliver(ctx context.Context, email *models.Email) error
+}
+
+// WorkerPool delivers emails from the persistent outbound queue. Emails are
+// claimed with an atomic find-and-modify lease, so any number of pools on
+// any number of GoMail instances can share the same collection safely.
+type WorkerPool struct {
+	email    deliverer
+	repo     repository.Repository
+	events   eventPublisher
+	config   config.QueueConfig
+	workerID string
+	poller   *lease.Poller
+}
+
+// NewWorkerPool creates a new queue worker pool
+func NewWorkerPool(cfg *config.Config, emailService Email, repo repository.Repository) *WorkerPool {
+	p := &WorkerPool{
+		email:    emailService,
+		repo:     repo,
+		events:   webhook.New(repo, cfg),
+		config:   cfg.Queue,
+		workerID: lease.NewOwnerID(),
+	}
+	p.poller = lease.NewPoller("Queue worker", cfg.Queue.PollInterval, p.processNext)
+	return p
+}
+
+// Start launches the configured number of workers
+func (p *WorkerPool) Start() {
+	log.Printf("Starting %d queue workers (id: %s)", p.config.Workers, p.workerID)
+	p.poller.Start(p.config.Workers)
+}
+
+// Stop signals the workers to exit and waits for in-flight deliveries
+func (p *WorkerPool) Stop(ctx context.Context) error {
+	return p.poller.Stop(ctx)
+}
+
+// processNext claims a single due email and delivers it. It reports whether
+// an email was claimed.
+func (p *WorkerPool) processNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	email, err := p.repo.ClaimEmail(ctx, p.workerID, now, p.config.LeaseDuration)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim email: %w", err)
+	}
+	if email == nil {
+		return false, nil
+	}
+
+	// Bound the delivery by the lease so another worker never sends concurrently
+	sendCtx, cancel := context.WithDeadline(ctx, now.Add(p.config.LeaseDuration))
+	sendErr := p.email.Deliver(sendCtx, email)
+	cancel()
+
+	p.applyResult(email, sendErr, time.Now())
+
+	if err := p.repo.ReleaseEmail(ctx, email, p.workerID); err != nil {
+		if errors.Is(err, emailRepo.ErrLeaseLost) {
+			log.Printf("Queue worker lost lease on email %s", email.ID.Hex())
+			return true, nil
+		}
+		return true, fmt.Errorf("failed to release email %s: %w", email.ID.Hex(), err)
+	}
+
+	// Only a failure that won't be retried is an event; emails to
+	// suppressed recipients were never meant to go out
+	if email.Status == models.EmailStatusFailed && !errors.Is(sendErr, ErrRecipientSuppressed) && p.events != nil {
+		if err := p.events.Publish(ctx, email.UserID, models.WebhookEventFailed, queuedEventData(email)); err != nil {
+			log.Printf("Failed to publish failed event of email %s: %v", email.ID.Hex(), err)
+		}
+	}
+
+	return true, nil
+}
+
+// applyResult updates the email with the outcome of a delivery attempt
+func (p *WorkerPool) applyResult(email *models.Email, sendErr error, now time.Time) {
+	if sendErr == nil {
+		email.Status = models.EmailStatusSent
+		email.SentAt = &now
+		email.Error = ""
+		return
+	}
+
+	email.Error = sendErr.Error()
+
+	// Retrying doesn't clean an infected attachment or lift a suppression
+	if errors.Is(sendErr, ErrAttachmentInfected) || errors.Is(sendErr, ErrRecipientSuppressed) {
+		email.Status = models.EmailStatusFailed
+		return
+	}
+
+	maxAttempts := email.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = p.config.MaxAttempts
+	}
+	if email.Attempts >= maxAttempts {
+		email.Status = models.EmailStatusFailed
+		return
+	}
+
+	email.Status = models.EmailStatusPending
+	email.NextAttemptAt = now.Add(p.backoff(email.Attempts))
+}
+
+// backoff returns the exponential retry delay after the given attempt
+func (p *WorkerPool) backoff(attempt int) time.Duration {
+	return lease.Backoff(p.config.RetryBackoff, p.config.MaxBackoff, attempt)
+}
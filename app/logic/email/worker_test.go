@@ -0,0 +1,246 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/lease"
+	"GoMail/app/logic/webhook"
+	emailRepo "GoMail/app/repository/email"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+)
+
+// mockDeliverer is a testify mock for the deliverer interface
+type mockDeliverer struct {
+	mock.Mock
+}
+
+func (m *mockDeliverer) Deliver(ctx context.Context, email *models.Email) error {
+	return m.Called(ctx, email).Error(0)
+}
+
+var testQueueConfig = config.QueueConfig{
+	Workers:       1,
+	PollInterval:  10 * time.Millisecond,
+	LeaseDuration: time.Minute,
+	MaxAttempts:   3,
+	RetryBackoff:  time.Second,
+	MaxBackoff:    3 * time.Second,
+}
+
+func buildQueuedEmail(attempts int) *models.Email {
+	return &models.Email{
+		ID:          primitive.NewObjectID(),
+		From:        "test@example.com",
+		To:          "recipient@example.com",
+		Subject:     "Test Subject",
+		Body:        "Test Body",
+		ContentType: "text/plain",
+		Status:      models.EmailStatusProcessing,
+		Attempts:    attempts,
+		MaxAttempts: 3,
+	}
+}
+
+func TestWorkerPool_processNext(t *testing.T) {
+	tests := []struct {
+		name          string
+		queued        *models.Email
+		claimErr      error
+		deliverErr    error
+		releaseErr    error
+		wantProcessed bool
+		wantErr       bool
+		wantStatus    models.EmailStatus
+		wantBackoff   time.Duration
+	}{
+		{
+			name:          "queue empty",
+			queued:        nil,
+			wantProcessed: false,
+		},
+		{
+			name:          "claim error",
+			claimErr:      errors.New("db down"),
+			wantProcessed: false,
+			wantErr:       true,
+		},
+		{
+			name:          "delivered",
+			queued:        buildQueuedEmail(1),
+			wantProcessed: true,
+			wantStatus:    models.EmailStatusSent,
+		},
+		{
+			name:          "retry with backoff",
+			queued:        buildQueuedEmail(2),
+			deliverErr:    errors.New("smtp error"),
+			wantProcessed: true,
+			wantStatus:    models.EmailStatusPending,
+			wantBackoff:   2 * time.Second,
+		},
+		{
+			name:          "attempts exhausted",
+			queued:        buildQueuedEmail(3),
+			deliverErr:    errors.New("smtp error"),
+			wantProcessed: true,
+			wantStatus:    models.EmailStatusFailed,
+		},
+		{
+			name:          "infected attachment not retried",
+			queued:        buildQueuedEmail(1),
+			deliverErr:    fmt.Errorf("%w: invoice.pdf contains Eicar-Signature", ErrAttachmentInfected),
+			wantProcessed: true,
+			wantStatus:    models.EmailStatusFailed,
+		},
+		{
+			name:          "suppressed recipients not retried",
+			queued:        buildQueuedEmail(1),
+			deliverErr:    fmt.Errorf("%w: recipients are suppressed: ana@example.com (complaint)", ErrRecipientSuppressed),
+			wantProcessed: true,
+			wantStatus:    models.EmailStatusFailed,
+		},
+		{
+			name:          "lease lost",
+			queued:        buildQueuedEmail(1),
+			releaseErr:    emailRepo.ErrLeaseLost,
+			wantProcessed: true,
+			wantStatus:    models.EmailStatusSent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repoMocks.Repository{}
+			emailService := &mockDeliverer{}
+
+			repo.On("ClaimEmail", mock.Anything, "worker-1", mock.AnythingOfType("time.Time"), time.Minute).Return(tt.queued, tt.claimErr)
+			if tt.queued != nil {
+				emailService.On("Deliver", mock.Anything, tt.queued).Return(tt.deliverErr)
+				repo.On("ReleaseEmail", mock.Anything, tt.queued, "worker-1").Return(tt.releaseErr)
+			}
+
+			p := &WorkerPool{
+				email:    emailService,
+				repo:     repo,
+				config:   testQueueConfig,
+				workerID: "worker-1",
+			}
+
+			before := time.Now()
+			processed, err := p.processNext(context.Background())
+
+			assert.Equal(t, tt.wantProcessed, processed)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.queued != nil {
+				assert.Equal(t, tt.wantStatus, tt.queued.Status)
+				if tt.wantBackoff > 0 {
+					assert.WithinDuration(t, before.Add(tt.wantBackoff), tt.queued.NextAttemptAt, time.Second)
+				}
+			}
+
+			repo.AssertExpectations(t)
+			emailService.AssertExpectations(t)
+		})
+	}
+}
+
+// recordingPublisher records the events published to webhooks
+type recordingPublisher struct {
+	events []models.WebhookEventType
+}
+
+func (r *recordingPublisher) Publish(_ context.Context, _ string, eventType models.WebhookEventType, _ webhook.EventData) error {
+	r.events = append(r.events, eventType)
+	return nil
+}
+
+func TestWorkerPool_processNext_PublishesFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempts   int
+		deliverErr error
+		want       []models.WebhookEventType
+	}{
+		{name: "retried", attempts: 1, deliverErr: errors.New("smtp unavailable")},
+		{name: "last attempt", attempts: 3, deliverErr: errors.New("smtp unavailable"), want: []models.WebhookEventType{models.WebhookEventFailed}},
+		{name: "suppressed", attempts: 1, deliverErr: ErrRecipientSuppressed},
+		{name: "sent", attempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queued := buildQueuedEmail(tt.attempts)
+			repo := &repoMocks.Repository{}
+			repo.On("ClaimEmail", mock.Anything, "worker-1", mock.Anything, time.Minute).Return(queued, nil)
+			repo.On("ReleaseEmail", mock.Anything, queued, "worker-1").Return(nil)
+			emailService := &mockDeliverer{}
+			emailService.On("Deliver", mock.Anything, queued).Return(tt.deliverErr)
+			events := &recordingPublisher{}
+
+			p := &WorkerPool{
+				email:    emailService,
+				repo:     repo,
+				events:   events,
+				config:   testQueueConfig,
+				workerID: "worker-1",
+			}
+			_, err := p.processNext(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, events.events)
+		})
+	}
+}
+
+func TestWorkerPool_backoff(t *testing.T) {
+	p := &WorkerPool{config: testQueueConfig}
+
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 3*time.Second, p.backoff(3))
+	assert.Equal(t, 3*time.Second, p.backoff(10))
+}
+
+func TestWorkerPool_StartStop(t *testing.T) {
+	polled := make(chan struct{}, 1)
+	repo := &repoMocks.Repository{}
+	repo.On("ClaimEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		select {
+		case polled <- struct{}{}:
+		default:
+		}
+	}).Return(nil, nil)
+
+	p := &WorkerPool{
+		email:    &mockDeliverer{},
+		repo:     repo,
+		config:   testQueueConfig,
+		workerID: lease.NewOwnerID(),
+	}
+	p.poller = lease.NewPoller("Queue worker", p.config.PollInterval, p.processNext)
+	p.Start()
+	select {
+	case <-polled:
+	case <-time.After(time.Second):
+		t.Fatal("worker didn't claim emails")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, p.Stop(ctx))
+}
@@ -0,0 +1,56 @@
+package emailtemplate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"GoMail/app/repository/models"
+)
+
+// Create stores a new template and publishes its first version
+func (s *service) Create(ctx context.Context, req CreateTemplateRequest) (*TemplateResponse, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+	if err := validateContent(req.Subject, req.Text, req.HTML); err != nil {
+		return nil, err
+	}
+	locales, err := normalizeLocales(req.Locales)
+	if err != nil {
+		return nil, err
+	}
+	translations, err := normalizeTranslations(req.Translations, req.Text, req.HTML)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.Template{
+		UserID:           req.UserID,
+		Name:             req.Name,
+		Description:      req.Description,
+		LatestVersion:    1,
+		PublishedVersion: 1,
+		Locales:          locales,
+	}
+	if err := s.repo.SaveTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	version := &models.TemplateVersion{
+		TemplateID: template.ID,
+		Version:    1,
+		Subject:    req.Subject,
+		Text:       req.Text,
+		HTML:       req.HTML,
+
+		Translations: translations,
+	}
+	if err := s.repo.InsertTemplateVersion(ctx, version); err != nil {
+		// Don't leave a template behind that points at a missing version
+		_ = s.repo.DeleteTemplate(ctx, template.ID.Hex())
+		return nil, err
+	}
+
+	return toResponse(template), nil
+}
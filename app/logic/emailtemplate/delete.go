@@ -0,0 +1,25 @@
+package emailtemplate
+
+import (
+	"context"
+	"errors"
+
+	templateRepo "GoMail/app/repository/emailtemplate"
+)
+
+// Delete removes a template and all of its versions
+func (s *service) Delete(ctx context.Context, userID, id string) error {
+	template, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteTemplate(ctx, id); err != nil {
+		if errors.Is(err, templateRepo.ErrTemplateNotFound) {
+			return ErrTemplateNotFound
+		}
+		return err
+	}
+
+	return s.repo.DeleteTemplateVersions(ctx, template.ID)
+}
@@ -0,0 +1,108 @@
+package emailtemplate
+
+import "time"
+
+// CreateTemplateRequest represents a request to create a template with its first version
+type CreateTemplateRequest struct {
+	UserID      string `json:"-"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Subject     string `json:"subject"`
+	Text        string `json:"text"`
+	HTML        string `json:"html"`
+
+	// Locales lists the locales the template should be translated into
+	Locales      []string               `json:"locales,omitempty"`
+	Translations map[string]Translation `json:"translations,omitempty"`
+}
+
+// UpdateTemplateRequest represents a request to change template metadata
+type UpdateTemplateRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Locales     []string `json:"locales,omitempty"`
+}
+
+// Translation represents the localised content of a version. It must have
+// the same parts as the default content.
+type Translation struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text,omitempty"`
+	HTML    string `json:"html,omitempty"`
+}
+
+// VersionRequest represents a request to add a new version to a template
+type VersionRequest struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	Publish bool   `json:"publish"`
+
+	Translations map[string]Translation `json:"translations,omitempty"`
+}
+
+// TemplateResponse represents a stored template
+type TemplateResponse struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description,omitempty"`
+	LatestVersion    int       `json:"latestVersion"`
+	PublishedVersion int       `json:"publishedVersion"`
+	Locales          []string  `json:"locales,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// ListTemplatesResponse represents a page of templates
+type ListTemplatesResponse struct {
+	Templates []TemplateResponse `json:"templates"`
+	Total     int64              `json:"total"`
+	Page      int                `json:"page"`
+	Limit     int                `json:"limit"`
+}
+
+// VersionResponse represents an immutable template version
+type VersionResponse struct {
+	Version   int       `json:"version"`
+	Subject   string    `json:"subject"`
+	Text      string    `json:"text,omitempty"`
+	HTML      string    `json:"html,omitempty"`
+	Published bool      `json:"published"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	Translations map[string]Translation `json:"translations,omitempty"`
+}
+
+// RenderRequest represents a request to render a template with data
+type RenderRequest struct {
+	Version int                    `json:"version"` // 0 renders the published version
+	Data    map[string]interface{} `json:"data"`
+
+	// Locale selects the translation. When it is empty the stored locale
+	// preference of Recipient is used.
+	Locale    string `json:"locale,omitempty"`
+	Recipient string `json:"recipient,omitempty"`
+}
+
+// Rendered represents the output of a rendered template
+type Rendered struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text,omitempty"`
+	HTML    string `json:"html,omitempty"`
+	Version int    `json:"version"`
+	Locale  string `json:"locale,omitempty"` // Translation used, empty for the default content
+}
+
+// MissingTranslationsResponse reports the locales of a template that a
+// version does not translate
+type MissingTranslationsResponse struct {
+	Version int                  `json:"version"`
+	Missing []MissingTranslation `json:"missing"`
+}
+
+// MissingTranslation describes a locale without its own translation and
+// the content it falls back to
+type MissingTranslation struct {
+	Locale   string `json:"locale"`
+	Fallback string `json:"fallback"` // Translation used instead, or "default"
+}
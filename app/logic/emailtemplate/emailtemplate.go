@@ -0,0 +1,137 @@
+package emailtemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"GoMail/app/config"
+	"GoMail/app/repository"
+	templateRepo "GoMail/app/repository/emailtemplate"
+	"GoMail/app/repository/models"
+)
+
+var (
+	ErrInvalidTemplate  = errors.New("invalid template")
+	ErrMissingVariable  = errors.New("missing template variable")
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrVersionNotFound  = errors.New("template version not found")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Service defines the interface for stored template operations
+type Service interface {
+	// Create stores a new template and publishes its first version
+	Create(ctx context.Context, req CreateTemplateRequest) (*TemplateResponse, error)
+
+	// Get returns a template owned by the user
+	Get(ctx context.Context, userID, id string) (*TemplateResponse, error)
+
+	// List returns the templates owned by the user
+	List(ctx context.Context, userID string, page, limit int) (*ListTemplatesResponse, error)
+
+	// Update changes the name and description of a template
+	Update(ctx context.Context, userID, id string, req UpdateTemplateRequest) (*TemplateResponse, error)
+
+	// Delete removes a template and all of its versions
+	Delete(ctx context.Context, userID, id string) error
+
+	// CreateVersion adds a new immutable version to a template
+	CreateVersion(ctx context.Context, userID, id string, req VersionRequest) (*VersionResponse, error)
+
+	// ListVersions returns every version of a template
+	ListVersions(ctx context.Context, userID, id string) ([]VersionResponse, error)
+
+	// GetVersion returns a single version of a template
+	GetVersion(ctx context.Context, userID, id string, version int) (*VersionResponse, error)
+
+	// Publish points the template at the version used for sending
+	Publish(ctx context.Context, userID, id string, version int) (*TemplateResponse, error)
+
+	// Render renders a template version (0 for the published one) with data
+	// in the requested or preferred locale
+	Render(ctx context.Context, userID, id string, req RenderRequest) (*Rendered, error)
+
+	// MissingTranslations reports the template locales a version does not translate
+	MissingTranslations(ctx context.Context, userID, id string, version int) (*MissingTranslationsResponse, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	config *config.Config
+}
+
+// New creates a new template service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		config: cfg,
+	}
+}
+
+// findOwned loads a template and hides templates owned by other users
+func (s *service) findOwned(ctx context.Context, userID, id string) (*models.Template, error) {
+	template, err := s.repo.FindTemplateByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, templateRepo.ErrTemplateNotFound) || errors.Is(err, templateRepo.ErrInvalidID) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+
+	if template.UserID != userID {
+		return nil, ErrTemplateNotFound
+	}
+
+	return template, nil
+}
+
+// validateContent checks that a version has a subject, a body and that
+// every part parses
+func validateContent(subject, text, html string) error {
+	if strings.TrimSpace(subject) == "" {
+		return fmt.Errorf("%w: subject is required", ErrInvalidTemplate)
+	}
+	if strings.TrimSpace(text) == "" && strings.TrimSpace(html) == "" {
+		return fmt.Errorf("%w: text or html is required", ErrInvalidTemplate)
+	}
+
+	if _, err := parseContent(subject, text, html, ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+// toResponse converts a stored template into its API representation
+func toResponse(template *models.Template) *TemplateResponse {
+	return &TemplateResponse{
+		ID:               template.ID.Hex(),
+		Name:             template.Name,
+		Description:      template.Description,
+		LatestVersion:    template.LatestVersion,
+		PublishedVersion: template.PublishedVersion,
+		Locales:          template.Locales,
+		CreatedAt:        template.CreatedAt,
+		UpdatedAt:        template.UpdatedAt,
+	}
+}
+
+// toVersionResponse converts a stored version into its API representation
+func toVersionResponse(template *models.Template, version *models.TemplateVersion) VersionResponse {
+	return VersionResponse{
+		Version:   version.Version,
+		Subject:   version.Subject,
+		Text:      version.Text,
+		HTML:      version.HTML,
+		Published: template.PublishedVersion == version.Version,
+		CreatedAt: version.CreatedAt,
+
+		Translations: toTranslations(version.Translations),
+	}
+}
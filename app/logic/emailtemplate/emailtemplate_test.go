@@ -0,0 +1,228 @@
+package emailtemplate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"GoMail/app/config"
+	templateRepo "GoMail/app/repository/emailtemplate"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+	recipientRepo "GoMail/app/repository/recipient"
+	versionRepo "GoMail/app/repository/templateversion"
+)
+
+func TestService_Create(t *testing.T) {
+	templateID := primitive.NewObjectID()
+
+	repo := &repoMocks.Repository{}
+	repo.On("SaveTemplate", mock.Anything, mock.MatchedBy(func(tmpl *models.Template) bool {
+		return tmpl.UserID == "user-1" && tmpl.LatestVersion == 1 && tmpl.PublishedVersion == 1
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Template).ID = templateID
+	}).Return(nil)
+	repo.On("InsertTemplateVersion", mock.Anything, mock.MatchedBy(func(v *models.TemplateVersion) bool {
+		return v.TemplateID == templateID && v.Version == 1 && v.Subject == "Hi {{.name}}"
+	})).Return(nil)
+
+	s := &service{repo: repo, config: &config.Config{}}
+
+	got, err := s.Create(context.Background(), CreateTemplateRequest{
+		UserID:  "user-1",
+		Name:    "welcome",
+		Subject: "Hi {{.name}}",
+		Text:    "Welcome {{.name}}",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, templateID.Hex(), got.ID)
+	assert.Equal(t, 1, got.PublishedVersion)
+	repo.AssertExpectations(t)
+
+	_, err = s.Create(context.Background(), CreateTemplateRequest{UserID: "user-1", Name: "broken", Subject: "s", Text: "{{.name"})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+}
+
+func TestService_CreateVersion_RetriesOnConflict(t *testing.T) {
+	stale := &models.Template{ID: primitive.NewObjectID(), UserID: "user-1", LatestVersion: 1, PublishedVersion: 1}
+	fresh := &models.Template{ID: stale.ID, UserID: "user-1", LatestVersion: 2, PublishedVersion: 1}
+	id := stale.ID.Hex()
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindTemplateByID", mock.Anything, id).Return(stale, nil).Once()
+	repo.On("FindTemplateByID", mock.Anything, id).Return(fresh, nil).Once()
+	repo.On("ReserveTemplateVersion", mock.Anything, id, 1).Return(templateRepo.ErrVersionConflict)
+	repo.On("ReserveTemplateVersion", mock.Anything, id, 2).Return(nil)
+	repo.On("InsertTemplateVersion", mock.Anything, mock.MatchedBy(func(v *models.TemplateVersion) bool {
+		return v.Version == 3
+	})).Return(nil)
+	repo.On("SaveTemplate", mock.Anything, mock.MatchedBy(func(tmpl *models.Template) bool {
+		return tmpl.PublishedVersion == 3 && tmpl.LatestVersion == 3
+	})).Return(nil)
+
+	s := &service{repo: repo, config: &config.Config{}}
+
+	got, err := s.CreateVersion(context.Background(), "user-1", id, VersionRequest{Subject: "s", HTML: "<p>v3</p>", Publish: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, got.Version)
+	assert.True(t, got.Published)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Publish(t *testing.T) {
+	stored := &models.Template{ID: primitive.NewObjectID(), UserID: "user-1", LatestVersion: 2, PublishedVersion: 2}
+	id := stored.ID.Hex()
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindTemplateByID", mock.Anything, id).Return(stored, nil)
+	repo.On("FindTemplateVersion", mock.Anything, stored.ID, 1).Return(&models.TemplateVersion{Version: 1}, nil)
+	repo.On("FindTemplateVersion", mock.Anything, stored.ID, 9).Return(nil, versionRepo.ErrVersionNotFound)
+	repo.On("SaveTemplate", mock.Anything, stored).Return(nil)
+
+	s := &service{repo: repo, config: &config.Config{}}
+
+	got, err := s.Publish(context.Background(), "user-1", id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.PublishedVersion)
+
+	_, err = s.Publish(context.Background(), "user-1", id, 9)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+
+	_, err = s.Publish(context.Background(), "user-2", id, 1)
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+}
+
+func TestService_Render_Published(t *testing.T) {
+	stored := &models.Template{ID: primitive.NewObjectID(), UserID: "user-1", LatestVersion: 3, PublishedVersion: 2}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindTemplateByID", mock.Anything, stored.ID.Hex()).Return(stored, nil)
+	repo.On("FindTemplateVersion", mock.Anything, stored.ID, 2).Return(&models.TemplateVersion{
+		Version: 2,
+		Subject: "Order {{.id}}",
+		Text:    "Shipped",
+	}, nil)
+
+	s := &service{repo: repo, config: &config.Config{}}
+
+	got, err := s.Render(context.Background(), "user-1", stored.ID.Hex(), RenderRequest{Data: map[string]interface{}{"id": 42}})
+
+	require.NoError(t, err)
+	assert.Equal(t, &Rendered{Version: 2, Subject: "Order 42", Text: "Shipped"}, got)
+}
+
+func TestService_Render_Locale(t *testing.T) {
+	stored := &models.Template{ID: primitive.NewObjectID(), UserID: "user-1", LatestVersion: 1, PublishedVersion: 1}
+	version := &models.TemplateVersion{
+		Version: 1,
+		Subject: "Your order",
+		Text:    "Total: {{formatCurrency .total \"EUR\"}}",
+		Translations: map[string]models.TemplateContent{
+			"pt": {Subject: "O seu pedido", Text: "Total: {{formatCurrency .total \"EUR\"}}"},
+		},
+	}
+	data := map[string]interface{}{"total": 1234.5}
+
+	tests := []struct {
+		name       string
+		req        RenderRequest
+		preference *models.Recipient
+		prefErr    error
+		want       *Rendered
+		wantErr    bool
+	}{
+		{
+			name: "explicit locale falls back to language",
+			req:  RenderRequest{Locale: "pt-BR", Recipient: "ana@example.com", Data: data},
+			want: &Rendered{Version: 1, Locale: "pt", Subject: "O seu pedido", Text: "Total: \u20ac\u00a01.234,50"},
+		},
+		{
+			name:       "recipient preference",
+			req:        RenderRequest{Recipient: "ana@example.com", Data: data},
+			preference: &models.Recipient{Locale: "pt-PT"},
+			want:       &Rendered{Version: 1, Locale: "pt", Subject: "O seu pedido", Text: "Total: 1\u00a0234,50\u00a0\u20ac"},
+		},
+		{
+			name:    "no preference uses default",
+			req:     RenderRequest{Recipient: "ana@example.com", Data: data},
+			prefErr: recipientRepo.ErrRecipientNotFound,
+			want:    &Rendered{Version: 1, Subject: "Your order", Text: "Total: \u20ac1,234.50"},
+		},
+		{
+			name:    "preference lookup fails",
+			req:     RenderRequest{Recipient: "ana@example.com", Data: data},
+			prefErr: errors.New("db down"),
+			wantErr: true,
+		},
+		{
+			name: "unknown locale uses default",
+			req:  RenderRequest{Locale: "de", Data: data},
+			want: &Rendered{Version: 1, Subject: "Your order", Text: "Total: 1.234,50\u00a0\u20ac"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repoMocks.Repository{}
+			repo.On("FindTemplateByID", mock.Anything, stored.ID.Hex()).Return(stored, nil)
+			repo.On("FindTemplateVersion", mock.Anything, stored.ID, 1).Return(version, nil).Maybe()
+			if tt.req.Locale == "" {
+				repo.On("FindRecipient", mock.Anything, "user-1", "ana@example.com").Return(tt.preference, tt.prefErr)
+			}
+
+			s := &service{repo: repo, config: &config.Config{}}
+
+			got, err := s.Render(context.Background(), "user-1", stored.ID.Hex(), tt.req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_MissingTranslations(t *testing.T) {
+	stored := &models.Template{
+		ID:               primitive.NewObjectID(),
+		UserID:           "user-1",
+		LatestVersion:    1,
+		PublishedVersion: 1,
+		Locales:          []string{"de", "pt", "pt-BR", "fr"},
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindTemplateByID", mock.Anything, stored.ID.Hex()).Return(stored, nil)
+	repo.On("FindTemplateVersion", mock.Anything, stored.ID, 1).Return(&models.TemplateVersion{
+		Version: 1,
+		Subject: "s",
+		Text:    "t",
+		Translations: map[string]models.TemplateContent{
+			"de": {Subject: "s", Text: "t"},
+			"pt": {Subject: "s", Text: "t"},
+		},
+	}, nil)
+
+	s := &service{repo: repo, config: &config.Config{}}
+
+	got, err := s.MissingTranslations(context.Background(), "user-1", stored.ID.Hex(), 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, &MissingTranslationsResponse{
+		Version: 1,
+		Missing: []MissingTranslation{
+			{Locale: "pt-BR", Fallback: "pt"},
+			{Locale: "fr", Fallback: "default"},
+		},
+	}, got)
+}
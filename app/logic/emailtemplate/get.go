@@ -0,0 +1,89 @@
+package emailtemplate
+
+import (
+	"context"
+	"errors"
+
+	versionRepo "GoMail/app/repository/templateversion"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Get returns a template owned by the user
+func (s *service) Get(ctx context.Context, userID, id string) (*TemplateResponse, error) {
+	template, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponse(template), nil
+}
+
+// List returns the templates owned by the user
+func (s *service) List(ctx context.Context, userID string, page, limit int) (*ListTemplatesResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	templates, total, err := s.repo.FindTemplates(ctx, bson.M{"user_id": userID}, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListTemplatesResponse{
+		Templates: make([]TemplateResponse, 0, len(templates)),
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}
+	for _, template := range templates {
+		resp.Templates = append(resp.Templates, *toResponse(template))
+	}
+
+	return resp, nil
+}
+
+// ListVersions returns every version of a template, newest first
+func (s *service) ListVersions(ctx context.Context, userID, id string) ([]VersionResponse, error) {
+	template, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.FindTemplateVersions(ctx, template.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]VersionResponse, 0, len(versions))
+	for _, version := range versions {
+		resp = append(resp, toVersionResponse(template, version))
+	}
+
+	return resp, nil
+}
+
+// GetVersion returns a single version of a template
+func (s *service) GetVersion(ctx context.Context, userID, id string, version int) (*VersionResponse, error) {
+	template, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repo.FindTemplateVersion(ctx, template.ID, version)
+	if err != nil {
+		if errors.Is(err, versionRepo.ErrVersionNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	resp := toVersionResponse(template, stored)
+	return &resp, nil
+}
@@ -0,0 +1,249 @@
+package emailtemplate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"GoMail/app/libs/locale"
+	"GoMail/app/repository/models"
+	recipientRepo "GoMail/app/repository/recipient"
+	versionRepo "GoMail/app/repository/templateversion"
+)
+
+// defaultLocale names the untranslated content in reports
+const defaultLocale = "default"
+
+// MissingTranslations reports the locales of a template that a version (0
+// for the published one) does not translate
+func (s *service) MissingTranslations(ctx context.Context, userID, id string, version int) (*MissingTranslationsResponse, error) {
+	template, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == 0 {
+		version = template.PublishedVersion
+	}
+
+	stored, err := s.repo.FindTemplateVersion(ctx, template.ID, version)
+	if err != nil {
+		if errors.Is(err, versionRepo.ErrVersionNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	resp := &MissingTranslationsResponse{
+		Version: stored.Version,
+		Missing: make([]MissingTranslation, 0),
+	}
+	for _, tag := range template.Locales {
+		if _, ok := stored.Translations[tag]; ok {
+			continue
+		}
+
+		_, fallback := resolveContent(stored, tag)
+		if fallback == "" {
+			fallback = defaultLocale
+		}
+		resp.Missing = append(resp.Missing, MissingTranslation{Locale: tag, Fallback: fallback})
+	}
+
+	return resp, nil
+}
+
+// resolveLocale returns the locale to render for: the requested one, or
+// the stored preference of the recipient
+func (s *service) resolveLocale(ctx context.Context, userID string, req RenderRequest) (string, error) {
+	if req.Locale != "" || req.Recipient == "" {
+		return req.Locale, nil
+	}
+
+	recipient, err := s.repo.FindRecipient(ctx, userID, req.Recipient)
+	if err != nil {
+		if errors.Is(err, recipientRepo.ErrRecipientNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return recipient.Locale, nil
+}
+
+// resolveContent walks the fallback chain of tag (pt-BR, pt, ...) and
+// returns the first translation found, or the default content. It also
+// returns the locale of the translation used, empty for the default.
+func resolveContent(version *models.TemplateVersion, tag string) (models.TemplateContent, string) {
+	for _, candidate := range locale.Fallbacks(tag) {
+		if content, ok := version.Translations[candidate]; ok {
+			return content, candidate
+		}
+	}
+
+	return models.TemplateContent{
+		Subject: version.Subject,
+		Text:    version.Text,
+		HTML:    version.HTML,
+	}, ""
+}
+
+// normalizeLocales canonicalises and deduplicates a list of locales
+func normalizeLocales(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		canonical, err := locale.Normalize(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w %q", ErrInvalidTemplate, err, tag)
+		}
+		if !seen[canonical] {
+			seen[canonical] = true
+			normalized = append(normalized, canonical)
+		}
+	}
+	return normalized, nil
+}
+
+// normalizeTranslations validates the translations of a version and keys
+// them by canonical locale. Each translation must provide the same parts as
+// the default content so every locale renders the same email.
+func normalizeTranslations(translations map[string]Translation, text, html string) (map[string]models.TemplateContent, error) {
+	if len(translations) == 0 {
+		return nil, nil
+	}
+
+	normalized := make(map[string]models.TemplateContent, len(translations))
+	for tag, translation := range translations {
+		canonical, err := locale.Normalize(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w %q", ErrInvalidTemplate, err, tag)
+		}
+		if _, ok := normalized[canonical]; ok {
+			return nil, fmt.Errorf("%w: duplicate translation for %s", ErrInvalidTemplate, canonical)
+		}
+
+		if err := validateContent(translation.Subject, translation.Text, translation.HTML); err != nil {
+			return nil, fmt.Errorf("%w (translation %s)", err, canonical)
+		}
+		if (strings.TrimSpace(text) == "") != (strings.TrimSpace(translation.Text) == "") ||
+			(strings.TrimSpace(html) == "") != (strings.TrimSpace(translation.HTML) == "") {
+			return nil, fmt.Errorf("%w: translation %s must have the same parts as the default content", ErrInvalidTemplate, canonical)
+		}
+
+		normalized[canonical] = models.TemplateContent{
+			Subject: translation.Subject,
+			Text:    translation.Text,
+			HTML:    translation.HTML,
+		}
+	}
+	return normalized, nil
+}
+
+// toTranslations converts stored translations into their API representation
+func toTranslations(contents map[string]models.TemplateContent) map[string]Translation {
+	if len(contents) == 0 {
+		return nil
+	}
+
+	translations := make(map[string]Translation, len(contents))
+	for tag, content := range contents {
+		translations[tag] = Translation{
+			Subject: content.Subject,
+			Text:    content.Text,
+			HTML:    content.HTML,
+		}
+	}
+	return translations
+}
+
+// funcMap returns the locale-aware formatting helpers available to
+// templates:
+//
+//	{{formatDate .orderedAt}}            03/07/2026 or 07.03.2026
+//	{{formatNumber .count}}              1,234 or 1.234
+//	{{formatNumber .ratio 2}}            0.50 or 0,50
+//	{{formatCurrency .total "EUR"}}      €12.50 or 12,50 €
+//	{{locale}}                           the locale being rendered
+func funcMap(tag string) map[string]interface{} {
+	format := locale.For(tag)
+
+	return map[string]interface{}{
+		"formatDate": func(value interface{}) (string, error) {
+			t, err := toTime(value)
+			if err != nil {
+				return "", err
+			}
+			return format.Date(t), nil
+		},
+		"formatNumber": func(value interface{}, decimals ...int) (string, error) {
+			number, err := toFloat(value)
+			if err != nil {
+				return "", err
+			}
+			places := -1
+			if len(decimals) > 0 {
+				places = decimals[0]
+			}
+			return format.Number(number, places), nil
+		},
+		"formatCurrency": func(value interface{}, code string) (string, error) {
+			amount, err := toFloat(value)
+			if err != nil {
+				return "", err
+			}
+			return format.Currency(amount, code), nil
+		},
+		"locale": func() string {
+			return tag
+		},
+	}
+}
+
+// toFloat converts template data, typically decoded from JSON, to a number
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("cannot format %T as a number", value)
+	}
+}
+
+// toTime converts template data to a time. Strings may be RFC 3339
+// timestamps or plain dates.
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v != nil {
+			return *v, nil
+		}
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, v)
+	}
+	return time.Time{}, fmt.Errorf("cannot format %T as a date", value)
+}
@@ -0,0 +1,347 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	emailtemplate "GoMail/app/logic/emailtemplate"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, req
+func (_m *Service) Create(ctx context.Context, req emailtemplate.CreateTemplateRequest) (*emailtemplate.TemplateResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *emailtemplate.TemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, emailtemplate.CreateTemplateRequest) (*emailtemplate.TemplateResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, emailtemplate.CreateTemplateRequest) *emailtemplate.TemplateResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.TemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, emailtemplate.CreateTemplateRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateVersion provides a mock function with given fields: ctx, userID, id, req
+func (_m *Service) CreateVersion(ctx context.Context, userID string, id string, req emailtemplate.VersionRequest) (*emailtemplate.VersionResponse, error) {
+	ret := _m.Called(ctx, userID, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVersion")
+	}
+
+	var r0 *emailtemplate.VersionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, emailtemplate.VersionRequest) (*emailtemplate.VersionResponse, error)); ok {
+		return rf(ctx, userID, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, emailtemplate.VersionRequest) *emailtemplate.VersionResponse); ok {
+		r0 = rf(ctx, userID, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.VersionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, emailtemplate.VersionRequest) error); ok {
+		r1 = rf(ctx, userID, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Delete(ctx context.Context, userID string, id string) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Get(ctx context.Context, userID string, id string) (*emailtemplate.TemplateResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *emailtemplate.TemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*emailtemplate.TemplateResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *emailtemplate.TemplateResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.TemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVersion provides a mock function with given fields: ctx, userID, id, version
+func (_m *Service) GetVersion(ctx context.Context, userID string, id string, version int) (*emailtemplate.VersionResponse, error) {
+	ret := _m.Called(ctx, userID, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVersion")
+	}
+
+	var r0 *emailtemplate.VersionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*emailtemplate.VersionResponse, error)); ok {
+		return rf(ctx, userID, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *emailtemplate.VersionResponse); ok {
+		r0 = rf(ctx, userID, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.VersionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, userID, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID, page, limit
+func (_m *Service) List(ctx context.Context, userID string, page int, limit int) (*emailtemplate.ListTemplatesResponse, error) {
+	ret := _m.Called(ctx, userID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *emailtemplate.ListTemplatesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*emailtemplate.ListTemplatesResponse, error)); ok {
+		return rf(ctx, userID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *emailtemplate.ListTemplatesResponse); ok {
+		r0 = rf(ctx, userID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.ListTemplatesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, userID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListVersions provides a mock function with given fields: ctx, userID, id
+func (_m *Service) ListVersions(ctx context.Context, userID string, id string) ([]emailtemplate.VersionResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListVersions")
+	}
+
+	var r0 []emailtemplate.VersionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]emailtemplate.VersionResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []emailtemplate.VersionResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]emailtemplate.VersionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MissingTranslations provides a mock function with given fields: ctx, userID, id, version
+func (_m *Service) MissingTranslations(ctx context.Context, userID string, id string, version int) (*emailtemplate.MissingTranslationsResponse, error) {
+	ret := _m.Called(ctx, userID, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MissingTranslations")
+	}
+
+	var r0 *emailtemplate.MissingTranslationsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*emailtemplate.MissingTranslationsResponse, error)); ok {
+		return rf(ctx, userID, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *emailtemplate.MissingTranslationsResponse); ok {
+		r0 = rf(ctx, userID, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.MissingTranslationsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, userID, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, userID, id, version
+func (_m *Service) Publish(ctx context.Context, userID string, id string, version int) (*emailtemplate.TemplateResponse, error) {
+	ret := _m.Called(ctx, userID, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 *emailtemplate.TemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*emailtemplate.TemplateResponse, error)); ok {
+		return rf(ctx, userID, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *emailtemplate.TemplateResponse); ok {
+		r0 = rf(ctx, userID, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.TemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, userID, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Render provides a mock function with given fields: ctx, userID, id, req
+func (_m *Service) Render(ctx context.Context, userID string, id string, req emailtemplate.RenderRequest) (*emailtemplate.Rendered, error) {
+	ret := _m.Called(ctx, userID, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Render")
+	}
+
+	var r0 *emailtemplate.Rendered
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, emailtemplate.RenderRequest) (*emailtemplate.Rendered, error)); ok {
+		return rf(ctx, userID, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, emailtemplate.RenderRequest) *emailtemplate.Rendered); ok {
+		r0 = rf(ctx, userID, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.Rendered)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, emailtemplate.RenderRequest) error); ok {
+		r1 = rf(ctx, userID, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, userID, id, req
+func (_m *Service) Update(ctx context.Context, userID string, id string, req emailtemplate.UpdateTemplateRequest) (*emailtemplate.TemplateResponse, error) {
+	ret := _m.Called(ctx, userID, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *emailtemplate.TemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, emailtemplate.UpdateTemplateRequest) (*emailtemplate.TemplateResponse, error)); ok {
+		return rf(ctx, userID, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, emailtemplate.UpdateTemplateRequest) *emailtemplate.TemplateResponse); ok {
+		r0 = rf(ctx, userID, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emailtemplate.TemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, emailtemplate.UpdateTemplateRequest) error); ok {
+		r1 = rf(ctx, userID, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,170 @@
+package emailtemplate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"io"
+	"regexp"
+	textTemplate "text/template"
+
+	"GoMail/app/repository/models"
+	versionRepo "GoMail/app/repository/templateversion"
+)
+
+// missingKeyPattern extracts the variable name from the error text/template
+// reports for missing map keys
+var missingKeyPattern = regexp.MustCompile(`map has no entry for key "([^"]+)"`)
+
+// parsedContent holds the compiled parts of a template version
+type parsedContent struct {
+	subject *textTemplate.Template
+	text    *textTemplate.Template
+	html    *htmlTemplate.Template
+}
+
+// Render renders a template version (0 for the published one) with data in
+// the requested locale
+func (s *service) Render(ctx context.Context, userID, id string, req RenderRequest) (*Rendered, error) {
+	template, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	version := req.Version
+	if version == 0 {
+		version = template.PublishedVersion
+	}
+
+	tag, err := s.resolveLocale(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repo.FindTemplateVersion(ctx, template.ID, version)
+	if err != nil {
+		if errors.Is(err, versionRepo.ErrVersionNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return renderVersion(stored, tag, req.Data)
+}
+
+// renderVersion executes every part of the translation of a version that
+// best matches tag with data
+func renderVersion(version *models.TemplateVersion, tag string, data map[string]interface{}) (*Rendered, error) {
+	content, resolved := resolveContent(version, tag)
+
+	parsed, err := parseContent(content.Subject, content.Text, content.HTML, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := parsed.render(data)
+	if err != nil {
+		return nil, err
+	}
+	rendered.Version, rendered.Locale = version.Version, resolved
+
+	return rendered, nil
+}
+
+// Content is a subject and body compiled once and rendered with the data
+// of many recipients, such as the message of a mail merge
+type Content struct {
+	parsed *parsedContent
+}
+
+// ParseContent compiles a subject and body that aren't stored as a
+// template, with the same syntax and helpers. The body is compiled as HTML
+// when isHTML is set.
+func ParseContent(subject, body string, isHTML bool, tag string) (*Content, error) {
+	text, html := body, ""
+	if isHTML {
+		text, html = "", body
+	}
+
+	parsed, err := parseContent(subject, text, html, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &Content{parsed: parsed}, nil
+}
+
+// Render executes the content with data
+func (c *Content) Render(data map[string]interface{}) (*Rendered, error) {
+	return c.parsed.render(data)
+}
+
+// render executes every part with data
+func (p *parsedContent) render(data map[string]interface{}) (*Rendered, error) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	rendered := &Rendered{}
+	var err error
+	if rendered.Subject, err = execute(p.subject, data); err != nil {
+		return nil, err
+	}
+	if p.text != nil {
+		if rendered.Text, err = execute(p.text, data); err != nil {
+			return nil, err
+		}
+	}
+	if p.html != nil {
+		if rendered.HTML, err = execute(p.html, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return rendered, nil
+}
+
+// parseContent compiles the subject and text parts with text/template and
+// the HTML part with html/template so data is escaped. Referencing a
+// variable that is missing from the data fails rendering. The formatting
+// helpers follow the conventions of tag.
+func parseContent(subject, text, html, tag string) (*parsedContent, error) {
+	parsed := &parsedContent{}
+	funcs := funcMap(tag)
+
+	var err error
+	if parsed.subject, err = textTemplate.New("subject").Funcs(funcs).Option("missingkey=error").Parse(subject); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	if text != "" {
+		if parsed.text, err = textTemplate.New("text").Funcs(funcs).Option("missingkey=error").Parse(text); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+	}
+	if html != "" {
+		if parsed.html, err = htmlTemplate.New("html").Funcs(funcs).Option("missingkey=error").Parse(html); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+	}
+
+	return parsed, nil
+}
+
+// executor is implemented by both text/template and html/template
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+	Name() string
+}
+
+// execute renders a single part and reports missing variables by name
+func execute(tmpl executor, data map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		if match := missingKeyPattern.FindStringSubmatch(err.Error()); match != nil {
+			return "", fmt.Errorf("%w %q in %s", ErrMissingVariable, match[1], tmpl.Name())
+		}
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,150 @@
+package emailtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"GoMail/app/repository/models"
+)
+
+func TestRenderVersion(t *testing.T) {
+	version := &models.TemplateVersion{
+		Version: 2,
+		Subject: "Welcome, {{.name}}",
+		Text:    "Hi {{.name}}, your plan is {{.plan}}.",
+		HTML:    "<p>Hi {{.name}}, your plan is <b>{{.plan}}</b>.</p>",
+	}
+
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		want    *Rendered
+		wantErr error
+		errText string
+	}{
+		{
+			name: "renders every part",
+			data: map[string]interface{}{"name": "Ada", "plan": "Pro"},
+			want: &Rendered{
+				Version: 2,
+				Subject: "Welcome, Ada",
+				Text:    "Hi Ada, your plan is Pro.",
+				HTML:    "<p>Hi Ada, your plan is <b>Pro</b>.</p>",
+			},
+		},
+		{
+			name: "escapes html but not text",
+			data: map[string]interface{}{"name": "<script>x</script>", "plan": "A&B"},
+			want: &Rendered{
+				Version: 2,
+				Subject: "Welcome, <script>x</script>",
+				Text:    "Hi <script>x</script>, your plan is A&B.",
+				HTML:    "<p>Hi &lt;script&gt;x&lt;/script&gt;, your plan is <b>A&amp;B</b>.</p>",
+			},
+		},
+		{
+			name:    "missing variable",
+			data:    map[string]interface{}{"name": "Ada"},
+			wantErr: ErrMissingVariable,
+			errText: `missing template variable "plan" in text`,
+		},
+		{
+			name:    "nil data",
+			data:    nil,
+			wantErr: ErrMissingVariable,
+			errText: `missing template variable "name" in subject`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderVersion(version, "", tt.data)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.EqualError(t, err, tt.errText)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseContent(t *testing.T) {
+	text, err := ParseContent("Hi {{.name}}", "Your code is {{.code}}", false, "")
+	require.NoError(t, err)
+	got, err := text.Render(map[string]interface{}{"name": "Ada", "code": "<1>"})
+	require.NoError(t, err)
+	assert.Equal(t, &Rendered{Subject: "Hi Ada", Text: "Your code is <1>"}, got)
+
+	html, err := ParseContent("Hi {{.name}}", "<p>{{.code}}</p>", true, "")
+	require.NoError(t, err)
+	got, err = html.Render(map[string]interface{}{"name": "Ada", "code": "<1>"})
+	require.NoError(t, err)
+	assert.Equal(t, &Rendered{Subject: "Hi Ada", HTML: "<p>&lt;1&gt;</p>"}, got)
+
+	_, err = html.Render(map[string]interface{}{"name": "Ada"})
+	assert.ErrorIs(t, err, ErrMissingVariable)
+
+	_, err = ParseContent("Hi {{.name", "body", false, "")
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+}
+
+func TestValidateContent(t *testing.T) {
+	assert.NoError(t, validateContent("Hi {{.name}}", "", "<p>{{.name}}</p>"))
+	assert.ErrorIs(t, validateContent("", "body", ""), ErrInvalidTemplate)
+	assert.ErrorIs(t, validateContent("subject", "", ""), ErrInvalidTemplate)
+	assert.ErrorIs(t, validateContent("subject", "{{.name", ""), ErrInvalidTemplate)
+	assert.ErrorIs(t, validateContent("subject", "", "{{if .x}}"), ErrInvalidTemplate)
+}
+
+func TestNormalizeTranslations(t *testing.T) {
+	got, err := normalizeTranslations(map[string]Translation{
+		"pt_br": {Subject: "Olá {{.name}}", Text: "Bem-vindo"},
+	}, "Welcome", "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]models.TemplateContent{
+		"pt-BR": {Subject: "Olá {{.name}}", Text: "Bem-vindo"},
+	}, got)
+
+	tests := []struct {
+		name         string
+		translations map[string]Translation
+	}{
+		{name: "invalid locale", translations: map[string]Translation{"portuguese": {Subject: "s", Text: "t"}}},
+		{name: "duplicate locale", translations: map[string]Translation{"pt-BR": {Subject: "s", Text: "t"}, "pt_BR": {Subject: "s", Text: "t"}}},
+		{name: "different parts", translations: map[string]Translation{"de": {Subject: "s", HTML: "<p>t</p>"}}},
+		{name: "parse error", translations: map[string]Translation{"de": {Subject: "{{.name", Text: "t"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := normalizeTranslations(tt.translations, "Welcome", "")
+			assert.ErrorIs(t, err, ErrInvalidTemplate)
+		})
+	}
+}
+
+func TestFuncMap(t *testing.T) {
+	version := &models.TemplateVersion{
+		Subject: "{{locale}}",
+		Text:    `{{formatDate .date}} {{formatNumber .count}} {{formatNumber .ratio 2}} {{formatCurrency .price "USD"}}`,
+	}
+	data := map[string]interface{}{
+		"date":  "2026-03-07T10:00:00Z",
+		"count": float64(12345),
+		"ratio": "0.5",
+		"price": 9.99,
+	}
+
+	got, err := renderVersion(version, "de-DE", data)
+	require.NoError(t, err)
+	assert.Equal(t, "de-DE", got.Subject)
+	assert.Equal(t, "07.03.2026 12.345 0,50 9,99\u00a0$", got.Text)
+
+	_, err = renderVersion(version, "en", map[string]interface{}{"date": true, "count": 1, "ratio": 1, "price": 1})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+}
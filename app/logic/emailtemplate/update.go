@@ -0,0 +1,116 @@
+package emailtemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	templateRepo "GoMail/app/repository/emailtemplate"
+	"GoMail/app/repository/models"
+	versionRepo "GoMail/app/repository/templateversion"
+)
+
+// reserveAttempts bounds the retries when versions are created concurrently
+const reserveAttempts = 3
+
+// Update changes the name and description of a template
+func (s *service) Update(ctx context.Context, userID, id string, req UpdateTemplateRequest) (*TemplateResponse, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+	locales, err := normalizeLocales(req.Locales)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = req.Name
+	template.Description = req.Description
+	template.Locales = locales
+	if err := s.repo.SaveTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return toResponse(template), nil
+}
+
+// CreateVersion adds a new immutable version to a template and optionally
+// publishes it
+func (s *service) CreateVersion(ctx context.Context, userID, id string, req VersionRequest) (*VersionResponse, error) {
+	if err := validateContent(req.Subject, req.Text, req.HTML); err != nil {
+		return nil, err
+	}
+	translations, err := normalizeTranslations(req.Translations, req.Text, req.HTML)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reserve the next version number, reloading if another request won
+	var template *models.Template
+	for attempt := 0; ; attempt++ {
+		var err error
+		template, err = s.findOwned(ctx, userID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.repo.ReserveTemplateVersion(ctx, id, template.LatestVersion)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, templateRepo.ErrVersionConflict) || attempt+1 >= reserveAttempts {
+			return nil, err
+		}
+	}
+	template.LatestVersion++
+
+	version := &models.TemplateVersion{
+		TemplateID: template.ID,
+		Version:    template.LatestVersion,
+		Subject:    req.Subject,
+		Text:       req.Text,
+		HTML:       req.HTML,
+
+		Translations: translations,
+	}
+	if err := s.repo.InsertTemplateVersion(ctx, version); err != nil {
+		return nil, err
+	}
+
+	if req.Publish {
+		template.PublishedVersion = version.Version
+		if err := s.repo.SaveTemplate(ctx, template); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := toVersionResponse(template, version)
+	return &resp, nil
+}
+
+// Publish points the template at the version used for sending
+func (s *service) Publish(ctx context.Context, userID, id string, version int) (*TemplateResponse, error) {
+	template, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindTemplateVersion(ctx, template.ID, version); err != nil {
+		if errors.Is(err, versionRepo.ErrVersionNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	template.PublishedVersion = version
+	if err := s.repo.SaveTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return toResponse(template), nil
+}
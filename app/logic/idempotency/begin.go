@@ -0,0 +1,81 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	idempotencyRepo "GoMail/app/repository/idempotency"
+	"GoMail/app/repository/models"
+)
+
+// Begin reserves a key for a request or returns the response stored for it
+func (s *service) Begin(ctx context.Context, userID, key string, req Request) (*Response, error) {
+	if !validKey(key) {
+		return nil, fmt.Errorf("%w: must be 1 to %d printable ASCII characters", ErrInvalidKey, MaxKeyLength)
+	}
+
+	now := time.Now()
+	record := &models.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		Method:      req.Method,
+		Path:        req.Path,
+		RequestHash: req.Hash,
+		Status:      models.IdempotencyInProgress,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.config.Idempotency.TTL),
+	}
+
+	// A stale key is removed and reserved again once; losing that race to
+	// another retry leaves its reservation in progress
+	for attempt := 0; attempt < 2; attempt++ {
+		err := s.repo.InsertIdempotencyRecord(ctx, record)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, idempotencyRepo.ErrKeyExists) {
+			return nil, err
+		}
+
+		existing, err := s.repo.FindIdempotencyRecord(ctx, userID, key)
+		if errors.Is(err, idempotencyRepo.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if s.stale(existing, now) {
+			if _, err := s.repo.DeleteStaleIdempotencyRecord(ctx, userID, key, now, now.Add(-s.config.Idempotency.LockTimeout)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if existing.RequestHash != req.Hash {
+			return nil, ErrKeyReused
+		}
+		if existing.Status != models.IdempotencyCompleted || existing.Response == nil {
+			return nil, ErrInProgress
+		}
+
+		return &Response{
+			StatusCode:  existing.Response.StatusCode,
+			ContentType: existing.Response.ContentType,
+			Body:        existing.Response.Body,
+		}, nil
+	}
+
+	return nil, ErrInProgress
+}
+
+// stale reports whether a key expired or its request was abandoned
+func (s *service) stale(record *models.IdempotencyRecord, now time.Time) bool {
+	if !record.ExpiresAt.After(now) {
+		return true
+	}
+	return record.Status == models.IdempotencyInProgress &&
+		record.CreatedAt.Before(now.Add(-s.config.Idempotency.LockTimeout))
+}
@@ -0,0 +1,22 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+)
+
+// Complete stores the response to a request begun with a key
+func (s *service) Complete(ctx context.Context, userID, key string, resp Response) error {
+	return s.repo.CompleteIdempotencyRecord(ctx, userID, key, &models.StoredResponse{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.ContentType,
+		Body:        resp.Body,
+	}, time.Now())
+}
+
+// Release removes the reservation of a key begun for a request
+func (s *service) Release(ctx context.Context, userID, key string) error {
+	return s.repo.ReleaseIdempotencyRecord(ctx, userID, key)
+}
@@ -0,0 +1,18 @@
+package idempotency
+
+// Request identifies a request sent with an idempotency key
+type Request struct {
+	Method string
+	Path   string
+
+	// Hash is a digest of the method, path and body, compared to detect a
+	// key reused with a different request
+	Hash string
+}
+
+// Response is the response stored for a key and replayed to retries
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/config"
+	"GoMail/app/repository"
+)
+
+var (
+	ErrInvalidKey = errors.New("invalid idempotency key")
+	ErrInProgress = errors.New("a request with this idempotency key is in progress")
+	ErrKeyReused  = errors.New("idempotency key was used with a different request")
+)
+
+// MaxKeyLength is the longest idempotency key accepted
+const MaxKeyLength = 255
+
+// Service defines the interface for idempotency keys. A key reserved with
+// Begin is completed with the response to the request, which is then
+// replayed to retries of the request until the key expires.
+type Service interface {
+	// Begin reserves a key for a request. It returns the stored response
+	// when the request was already handled, or nil when the caller should
+	// handle it and call Complete.
+	Begin(ctx context.Context, userID, key string, req Request) (*Response, error)
+
+	// Complete stores the response to a request begun with a key
+	Complete(ctx context.Context, userID, key string, resp Response) error
+
+	// Release gives up a key begun for a request that failed, so that a
+	// retry with the key handles the request again
+	Release(ctx context.Context, userID, key string) error
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	config *config.Config
+}
+
+// New creates a new idempotency key service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		config: cfg,
+	}
+}
+
+// validKey reports whether a key is non-empty printable ASCII that fits
+// MaxKeyLength
+func validKey(key string) bool {
+	if key == "" || len(key) > MaxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package idempotency
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	idempotencyRepo "GoMail/app/repository/idempotency"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(repo *repoMocks.Repository) *service {
+	return New(repo, &config.Config{
+		Idempotency: config.IdempotencyConfig{TTL: 24 * time.Hour, LockTimeout: 5 * time.Minute},
+	}).(*service)
+}
+
+var sendRequest = Request{Method: "POST", Path: "/api/v1/email/send", Hash: "abc"}
+
+func TestService_Begin_Reserves(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("InsertIdempotencyRecord", mock.Anything, mock.MatchedBy(func(r *models.IdempotencyRecord) bool {
+		return r.UserID == "user-1" && r.Key == "key-1" && r.RequestHash == "abc" &&
+			r.Status == models.IdempotencyInProgress && r.ExpiresAt.Sub(r.CreatedAt) == 24*time.Hour
+	})).Return(nil)
+
+	got, err := newService(repo).Begin(context.Background(), "user-1", "key-1", sendRequest)
+
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Begin_Existing(t *testing.T) {
+	now := time.Now()
+	completed := &models.IdempotencyRecord{
+		RequestHash: "abc",
+		Status:      models.IdempotencyCompleted,
+		Response:    &models.StoredResponse{StatusCode: 202, ContentType: "application/json", Body: []byte(`{"success":true}`)},
+		CreatedAt:   now.Add(-time.Hour),
+		ExpiresAt:   now.Add(time.Hour),
+	}
+	inProgress := &models.IdempotencyRecord{RequestHash: "abc", Status: models.IdempotencyInProgress, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	otherRequest := &models.IdempotencyRecord{RequestHash: "def", Status: models.IdempotencyCompleted, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name     string
+		existing *models.IdempotencyRecord
+		want     *Response
+		wantErr  error
+	}{
+		{name: "completed", existing: completed, want: &Response{StatusCode: 202, ContentType: "application/json", Body: []byte(`{"success":true}`)}},
+		{name: "in progress", existing: inProgress, wantErr: ErrInProgress},
+		{name: "different request", existing: otherRequest, wantErr: ErrKeyReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repoMocks.Repository{}
+			repo.On("InsertIdempotencyRecord", mock.Anything, mock.Anything).Return(idempotencyRepo.ErrKeyExists)
+			repo.On("FindIdempotencyRecord", mock.Anything, "user-1", "key-1").Return(tt.existing, nil)
+
+			got, err := newService(repo).Begin(context.Background(), "user-1", "key-1", sendRequest)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			repo.AssertNotCalled(t, "DeleteStaleIdempotencyRecord", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_Begin_Stale(t *testing.T) {
+	now := time.Now()
+	abandoned := &models.IdempotencyRecord{RequestHash: "def", Status: models.IdempotencyInProgress, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+	expired := &models.IdempotencyRecord{RequestHash: "def", Status: models.IdempotencyCompleted, CreatedAt: now.Add(-25 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+
+	for name, existing := range map[string]*models.IdempotencyRecord{"abandoned": abandoned, "expired": expired} {
+		t.Run(name, func(t *testing.T) {
+			repo := &repoMocks.Repository{}
+			repo.On("InsertIdempotencyRecord", mock.Anything, mock.Anything).Return(idempotencyRepo.ErrKeyExists).Once()
+			repo.On("FindIdempotencyRecord", mock.Anything, "user-1", "key-1").Return(existing, nil)
+			repo.On("DeleteStaleIdempotencyRecord", mock.Anything, "user-1", "key-1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(true, nil)
+			repo.On("InsertIdempotencyRecord", mock.Anything, mock.Anything).Return(nil).Once()
+
+			got, err := newService(repo).Begin(context.Background(), "user-1", "key-1", sendRequest)
+
+			require.NoError(t, err)
+			assert.Nil(t, got)
+			repo.AssertNumberOfCalls(t, "InsertIdempotencyRecord", 2)
+		})
+	}
+}
+
+func TestService_Begin_InvalidKey(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	s := newService(repo)
+
+	for _, key := range []string{strings.Repeat("k", MaxKeyLength+1), "café", "line\nbreak"} {
+		_, err := s.Begin(context.Background(), "user-1", key, sendRequest)
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+	repo.AssertNotCalled(t, "InsertIdempotencyRecord", mock.Anything, mock.Anything)
+}
+
+func TestService_Complete(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("CompleteIdempotencyRecord", mock.Anything, "user-1", "key-1",
+		&models.StoredResponse{StatusCode: 200, ContentType: "application/json", Body: []byte("{}")},
+		mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := newService(repo).Complete(context.Background(), "user-1", "key-1", Response{StatusCode: 200, ContentType: "application/json", Body: []byte("{}")})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Release(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("ReleaseIdempotencyRecord", mock.Anything, "user-1", "key-1").Return(nil)
+
+	err := newService(repo).Release(context.Background(), "user-1", "key-1")
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	idempotency "GoMail/app/logic/idempotency"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields: ctx, userID, key, req
+func (_m *Service) Begin(ctx context.Context, userID string, key string, req idempotency.Request) (*idempotency.Response, error) {
+	ret := _m.Called(ctx, userID, key, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 *idempotency.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, idempotency.Request) (*idempotency.Response, error)); ok {
+		return rf(ctx, userID, key, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, idempotency.Request) *idempotency.Response); ok {
+		r0 = rf(ctx, userID, key, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*idempotency.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, idempotency.Request) error); ok {
+		r1 = rf(ctx, userID, key, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Complete provides a mock function with given fields: ctx, userID, key, resp
+func (_m *Service) Complete(ctx context.Context, userID string, key string, resp idempotency.Response) error {
+	ret := _m.Called(ctx, userID, key, resp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, idempotency.Response) error); ok {
+		r0 = rf(ctx, userID, key, resp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Release provides a mock function with given fields: ctx, userID, key
+func (_m *Service) Release(ctx context.Context, userID string, key string) error {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
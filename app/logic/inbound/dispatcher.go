@@ -0,0 +1,203 @@
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/lease"
+	"GoMail/app/repository"
+	inboundRepo "GoMail/app/repository/inbound"
+	"GoMail/app/repository/models"
+)
+
+// Dispatcher posts received emails to their webhooks. Messages are claimed
+// with an atomic find-and-modify lease, so dispatchers on several GoMail
+// instances can share the same collection safely. Failed webhooks are
+// retried with exponential backoff; the ones that succeeded aren't called
+// again.
+type Dispatcher struct {
+	repo    repository.Repository
+	config  config.InboundConfig
+	client  *http.Client
+	ownerID string
+	poller  *lease.Poller
+}
+
+// NewDispatcher creates a new webhook dispatcher
+func NewDispatcher(cfg *config.Config, repo repository.Repository) *Dispatcher {
+	d := &Dispatcher{
+		repo:    repo,
+		config:  cfg.Inbound,
+		client:  &http.Client{Timeout: cfg.Inbound.WebhookTimeout},
+		ownerID: lease.NewOwnerID(),
+	}
+	d.poller = lease.NewPoller("Inbound dispatcher", cfg.Inbound.PollInterval, d.processNext)
+	return d
+}
+
+// Start launches the dispatcher loop
+func (d *Dispatcher) Start() {
+	d.poller.Start(1)
+}
+
+// Stop signals the dispatcher loop to exit and waits for the message it
+// is dispatching
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	return d.poller.Stop(ctx)
+}
+
+// processNext claims a single due message and calls its webhooks. It
+// reports whether a message was claimed.
+func (d *Dispatcher) processNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	message, err := d.repo.ClaimInboundMessage(ctx, d.ownerID, now, d.config.LeaseDuration)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim inbound message: %w", err)
+	}
+	if message == nil {
+		return false, nil
+	}
+
+	// Bound the calls by the lease so another dispatcher never posts concurrently
+	callCtx, cancel := context.WithDeadline(ctx, now.Add(d.config.LeaseDuration))
+	d.dispatch(callCtx, message)
+	cancel()
+
+	d.applyResult(message, time.Now())
+
+	if err := d.repo.ReleaseInboundMessage(ctx, message, d.ownerID); err != nil {
+		if errors.Is(err, inboundRepo.ErrLeaseLost) {
+			log.Printf("Inbound dispatcher lost lease on message %s", message.ID.Hex())
+			return true, nil
+		}
+		return true, fmt.Errorf("failed to release inbound message %s: %w", message.ID.Hex(), err)
+	}
+
+	return true, nil
+}
+
+// dispatch posts a message to the webhooks that haven't accepted it yet
+func (d *Dispatcher) dispatch(ctx context.Context, message *models.InboundMessage) {
+	for i := range message.Webhooks {
+		delivery := &message.Webhooks[i]
+		if delivery.Delivered {
+			continue
+		}
+
+		statusCode, err := d.post(ctx, delivery.URL, toPayload(message, delivery.Recipients))
+		delivery.StatusCode = statusCode
+		if err != nil {
+			delivery.Error = err.Error()
+			continue
+		}
+
+		now := time.Now()
+		delivery.Delivered = true
+		delivery.DeliveredAt = &now
+		delivery.Error = ""
+	}
+}
+
+// post sends a payload to a webhook, which accepts it with a 2xx status
+func (d *Dispatcher) post(ctx context.Context, url string, payload *WebhookPayload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoMail-Inbound/1.0")
+	// Lets webhooks recognise a message they already handled on retries
+	req.Header.Set("X-GoMail-Inbound-Id", payload.ID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// applyResult updates the status of a message after its webhooks were
+// called
+func (d *Dispatcher) applyResult(message *models.InboundMessage, now time.Time) {
+	pending := false
+	for _, delivery := range message.Webhooks {
+		if !delivery.Delivered {
+			pending = true
+		}
+	}
+
+	switch {
+	case !pending:
+		message.Status = models.InboundStatusDelivered
+	case message.Attempts >= d.config.MaxAttempts:
+		message.Status = models.InboundStatusFailed
+	default:
+		message.Status = models.InboundStatusPending
+		message.NextAttemptAt = now.Add(d.backoff(message.Attempts))
+	}
+}
+
+// backoff returns the exponential retry delay after the given attempt
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	return lease.Backoff(d.config.RetryBackoff, d.config.MaxBackoff, attempt)
+}
+
+// toPayload converts a message into the body posted to a webhook
+func toPayload(message *models.InboundMessage, recipients []string) *WebhookPayload {
+	payload := &WebhookPayload{
+		ID:         message.ID.Hex(),
+		Recipients: recipients,
+		Envelope: Envelope{
+			MailFrom:   message.MailFrom,
+			Recipients: message.Recipients,
+			RemoteAddr: message.RemoteAddr,
+			Helo:       message.Helo,
+			TLS:        message.TLS,
+		},
+		MessageID:   message.MessageID,
+		InReplyTo:   message.InReplyTo,
+		References:  message.References,
+		Subject:     message.Subject,
+		From:        message.From,
+		To:          message.To,
+		Cc:          message.Cc,
+		ReplyTo:     message.ReplyTo,
+		Date:        message.Date,
+		Headers:     message.Headers,
+		Text:        message.Text,
+		HTML:        message.HTML,
+		Attachments: []WebhookAttachment{},
+		Size:        message.Size,
+		ReceivedAt:  message.ReceivedAt,
+	}
+	for _, attachment := range message.Attachments {
+		payload.Attachments = append(payload.Attachments, WebhookAttachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			ContentID:   attachment.ContentID,
+			Inline:      attachment.Inline,
+			Size:        attachment.Size,
+			Content:     attachment.Content,
+		})
+	}
+	return payload
+}
@@ -0,0 +1,55 @@
+package inbound
+
+import (
+	"time"
+
+	"GoMail/app/repository/models"
+)
+
+// Envelope represents the SMTP transaction an email was received with
+type Envelope struct {
+	MailFrom   string   `json:"mailFrom"` // Empty for bounces
+	Recipients []string `json:"recipients"`
+	RemoteAddr string   `json:"remoteAddr"`
+	Helo       string   `json:"helo"`
+	TLS        bool     `json:"tls"`
+}
+
+// ReceiveResponse represents a stored email
+type ReceiveResponse struct {
+	ID       string   `json:"id"`
+	Webhooks []string `json:"webhooks"`
+}
+
+// WebhookPayload is the JSON body webhooks receive. Recipients are the
+// ones routed to the webhook, Envelope has all of them.
+type WebhookPayload struct {
+	ID          string                  `json:"id"`
+	Recipients  []string                `json:"recipients"`
+	Envelope    Envelope                `json:"envelope"`
+	MessageID   string                  `json:"messageId,omitempty"`
+	InReplyTo   string                  `json:"inReplyTo,omitempty"`
+	References  []string                `json:"references,omitempty"`
+	Subject     string                  `json:"subject"`
+	From        []models.InboundAddress `json:"from"`
+	To          []models.InboundAddress `json:"to"`
+	Cc          []models.InboundAddress `json:"cc,omitempty"`
+	ReplyTo     []models.InboundAddress `json:"replyTo,omitempty"`
+	Date        *time.Time              `json:"date,omitempty"`
+	Headers     map[string][]string     `json:"headers"`
+	Text        string                  `json:"text"`
+	HTML        string                  `json:"html"`
+	Attachments []WebhookAttachment     `json:"attachments"`
+	Size        int64                   `json:"size"`
+	ReceivedAt  time.Time               `json:"receivedAt"`
+}
+
+// WebhookAttachment represents an attachment in a webhook payload
+type WebhookAttachment struct {
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType"`
+	ContentID   string `json:"contentId,omitempty"`
+	Inline      bool   `json:"inline"`
+	Size        int64  `json:"size"`
+	Content     []byte `json:"content"` // Base64 encoded
+}
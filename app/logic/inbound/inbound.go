@@ -0,0 +1,80 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"GoMail/app/config"
+	"GoMail/app/repository"
+)
+
+var (
+	ErrDomainNotAccepted = errors.New("recipient domain not accepted")
+	ErrNoRoute           = errors.New("no webhook routes the recipient")
+	ErrInvalidMessage    = errors.New("invalid message")
+)
+
+// Service defines the interface for received emails. Each recipient is
+// routed to the webhooks whose pattern matches it.
+type Service interface {
+	// Route returns the URLs of the webhooks a recipient is routed to. It
+	// fails for recipients of domains that aren't accepted or that no
+	// webhook routes.
+	Route(address string) ([]string, error)
+
+	// Receive parses and stores an email, whose webhooks are then called
+	// by the dispatcher
+	Receive(ctx context.Context, envelope Envelope, r io.Reader) (*ReceiveResponse, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	config *config.Config
+}
+
+// New creates a new inbound email service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	for _, webhook := range cfg.Inbound.Webhooks {
+		if _, err := path.Match(webhook.Pattern, ""); err != nil {
+			log.Printf("WARNING: Invalid inbound webhook pattern %q: %v", webhook.Pattern, err)
+		}
+	}
+
+	return &service{
+		repo:   repo,
+		config: cfg,
+	}
+}
+
+// Route returns the webhooks of a recipient
+func (s *service) Route(address string) ([]string, error) {
+	address = strings.ToLower(strings.TrimSpace(address))
+	domain := address[strings.LastIndex(address, "@")+1:]
+
+	accepted := false
+	for _, d := range s.config.Inbound.Domains {
+		if strings.EqualFold(strings.TrimSpace(d), domain) {
+			accepted = true
+			break
+		}
+	}
+	if !accepted {
+		return nil, ErrDomainNotAccepted
+	}
+
+	var urls []string
+	for _, webhook := range s.config.Inbound.Webhooks {
+		if matched, _ := path.Match(strings.ToLower(webhook.Pattern), address); matched {
+			urls = append(urls, webhook.URL)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, ErrNoRoute
+	}
+	return urls, nil
+}
@@ -0,0 +1,241 @@
+package inbound
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const reply = "From: Ada <ada@example.org>\r\n" +
+	"To: support+1234@reply.example.com\r\n" +
+	"Subject: Re: Your ticket\r\n" +
+	"Message-ID: <reply-1@example.org>\r\n" +
+	"In-Reply-To: <0123abcd@example.com>\r\n" +
+	"Content-Type: multipart/mixed; boundary=B\r\n" +
+	"\r\n" +
+	"--B\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Thanks!\r\n" +
+	"--B\r\n" +
+	"Content-Type: text/csv; name=report.csv\r\n" +
+	"\r\n" +
+	"a,b\r\n" +
+	"--B--\r\n"
+
+func newConfig(webhooks ...config.InboundWebhook) *config.Config {
+	return &config.Config{Inbound: config.InboundConfig{
+		Domains:       []string{"reply.example.com", "Example.com"},
+		Webhooks:      webhooks,
+		PollInterval:  time.Second,
+		LeaseDuration: time.Minute,
+		MaxAttempts:   3,
+		RetryBackoff:  time.Minute,
+		MaxBackoff:    time.Hour,
+	}}
+}
+
+func TestService_Route(t *testing.T) {
+	s := New(&repoMocks.Repository{}, newConfig(
+		config.InboundWebhook{Pattern: "support+*@reply.example.com", URL: "https://support.example.com/hook"},
+		config.InboundWebhook{Pattern: "*@reply.example.com", URL: "https://archive.example.com/hook"},
+		config.InboundWebhook{Pattern: "sales@example.com", URL: "https://crm.example.com/hook"},
+	))
+
+	tests := []struct {
+		address string
+		want    []string
+		wantErr error
+	}{
+		{address: "Support+1234@Reply.Example.com", want: []string{"https://support.example.com/hook", "https://archive.example.com/hook"}},
+		{address: "noreply@reply.example.com", want: []string{"https://archive.example.com/hook"}},
+		{address: "sales@example.com", want: []string{"https://crm.example.com/hook"}},
+		{address: "info@example.com", wantErr: ErrNoRoute},
+		{address: "sales@example.net", wantErr: ErrDomainNotAccepted},
+		{address: "sales@sub.example.com", wantErr: ErrDomainNotAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			got, err := s.Route(tt.address)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_Receive(t *testing.T) {
+	var saved *models.InboundMessage
+	repo := &repoMocks.Repository{}
+	repo.On("SaveInboundMessage", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*models.InboundMessage)
+		saved.ID = primitive.NewObjectID()
+	}).Return(nil)
+
+	s := New(repo, newConfig(
+		config.InboundWebhook{Pattern: "support+*@reply.example.com", URL: "https://support.example.com/hook"},
+		config.InboundWebhook{Pattern: "*@reply.example.com", URL: "https://archive.example.com/hook"},
+	))
+	envelope := Envelope{
+		MailFrom:   "ada@example.org",
+		Recipients: []string{"support+1234@reply.example.com", "team@reply.example.com"},
+		RemoteAddr: "192.0.2.1:40000",
+		Helo:       "mx.example.org",
+	}
+
+	got, err := s.Receive(context.Background(), envelope, strings.NewReader(reply))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://support.example.com/hook", "https://archive.example.com/hook"}, got.Webhooks)
+	require.NotNil(t, saved)
+	assert.Equal(t, models.InboundStatusPending, saved.Status)
+	assert.Equal(t, "Re: Your ticket", saved.Subject)
+	assert.Equal(t, "0123abcd@example.com", saved.InReplyTo)
+	assert.Equal(t, []models.InboundAddress{{Name: "Ada", Address: "ada@example.org"}}, saved.From)
+	assert.Equal(t, "Thanks!", saved.Text)
+	require.Len(t, saved.Attachments, 1)
+	assert.Equal(t, "report.csv", saved.Attachments[0].Filename)
+	assert.Equal(t, int64(len(reply)), saved.Size)
+	assert.Equal(t, []models.InboundDelivery{
+		{URL: "https://support.example.com/hook", Recipients: []string{"support+1234@reply.example.com"}},
+		{URL: "https://archive.example.com/hook", Recipients: []string{"support+1234@reply.example.com", "team@reply.example.com"}},
+	}, saved.Webhooks)
+}
+
+func TestService_Receive_Invalid(t *testing.T) {
+	s := New(&repoMocks.Repository{}, newConfig())
+
+	_, err := s.Receive(context.Background(), Envelope{}, strings.NewReader("not a message"))
+
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestDispatcher_ProcessNext(t *testing.T) {
+	var mu sync.Mutex
+	var payloads []WebhookPayload
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, payload.ID, r.Header.Get("X-GoMail-Inbound-Id"))
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	message := &models.InboundMessage{
+		ID:         primitive.NewObjectID(),
+		Recipients: []string{"support@reply.example.com", "sales@reply.example.com"},
+		Subject:    "Hello",
+		Attachments: []models.InboundAttachment{
+			{Filename: "a.txt", ContentType: "text/plain", Size: 2, Content: []byte("hi")},
+		},
+		Status:   models.InboundStatusProcessing,
+		Attempts: 1,
+		Webhooks: []models.InboundDelivery{
+			{URL: ok.URL, Recipients: []string{"support@reply.example.com"}},
+			{URL: failing.URL, Recipients: []string{"sales@reply.example.com"}},
+		},
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("ClaimInboundMessage", mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(message, nil).Once()
+	repo.On("ReleaseInboundMessage", mock.Anything, message, mock.Anything).Return(nil).Once()
+
+	d := NewDispatcher(newConfig(), repo)
+	processed, err := d.processNext(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, processed)
+	require.Len(t, payloads, 1)
+	assert.Equal(t, []string{"support@reply.example.com"}, payloads[0].Recipients)
+	assert.Equal(t, message.Recipients, payloads[0].Envelope.Recipients)
+	assert.Equal(t, []byte("hi"), payloads[0].Attachments[0].Content)
+
+	assert.True(t, message.Webhooks[0].Delivered)
+	assert.False(t, message.Webhooks[1].Delivered)
+	assert.Equal(t, http.StatusServiceUnavailable, message.Webhooks[1].StatusCode)
+	assert.Equal(t, models.InboundStatusPending, message.Status)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), message.NextAttemptAt, 5*time.Second)
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_ApplyResult(t *testing.T) {
+	d := NewDispatcher(newConfig(), &repoMocks.Repository{})
+	now := time.Now()
+
+	delivered := &models.InboundMessage{Attempts: 1, Webhooks: []models.InboundDelivery{{Delivered: true}}}
+	d.applyResult(delivered, now)
+	assert.Equal(t, models.InboundStatusDelivered, delivered.Status)
+
+	exhausted := &models.InboundMessage{Attempts: 3, Webhooks: []models.InboundDelivery{{Delivered: false}}}
+	d.applyResult(exhausted, now)
+	assert.Equal(t, models.InboundStatusFailed, exhausted.Status)
+
+	retried := &models.InboundMessage{Attempts: 2, Webhooks: []models.InboundDelivery{{Delivered: false}}}
+	d.applyResult(retried, now)
+	assert.Equal(t, models.InboundStatusPending, retried.Status)
+	assert.Equal(t, now.Add(2*time.Minute), retried.NextAttemptAt)
+}
+
+func TestListener(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveInboundMessage", mock.Anything, mock.MatchedBy(func(m *models.InboundMessage) bool {
+		return m.MailFrom == "ada@example.org" && len(m.Recipients) == 1 && m.Subject == "Re: Your ticket"
+	})).Return(nil).Once()
+
+	cfg := newConfig(config.InboundWebhook{Pattern: "support+*@reply.example.com", URL: "https://support.example.com/hook"})
+	listener, err := NewListener(cfg, New(repo, cfg))
+	require.NoError(t, err)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go listener.server.Serve(l)
+	defer listener.Stop(context.Background())
+
+	client, err := smtp.Dial(l.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Mail("ada@example.org"))
+	assert.ErrorContains(t, client.Rcpt("ada@example.net"), "Relaying denied")
+	assert.ErrorContains(t, client.Rcpt("info@reply.example.com"), "Mailbox unavailable")
+	require.NoError(t, client.Rcpt("support+1234@reply.example.com"))
+	w, err := client.Data()
+	require.NoError(t, err)
+	_, err = w.Write([]byte(reply))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, client.Quit())
+
+	repo.AssertExpectations(t)
+}
+
+func TestNewListener_RequireTLS(t *testing.T) {
+	cfg := newConfig()
+	cfg.Inbound.RequireTLS = true
+
+	_, err := NewListener(cfg, New(&repoMocks.Repository{}, cfg))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package inbound
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/smtpd"
+)
+
+// Listener receives emails over SMTP and hands them to the service
+type Listener struct {
+	service Service
+	server  *smtpd.Server
+	addr    string
+}
+
+// NewListener creates an SMTP listener on the configured port. It fails
+// when the TLS certificate can't be loaded.
+func NewListener(cfg *config.Config, service Service) (*Listener, error) {
+	l := &Listener{
+		service: service,
+		addr:    ":" + cfg.Inbound.Port,
+	}
+	l.server = &smtpd.Server{
+		Hostname:       cfg.Inbound.Hostname,
+		RequireTLS:     cfg.Inbound.RequireTLS,
+		MaxMessageSize: cfg.Inbound.MaxMessageSize,
+		MaxRecipients:  cfg.Inbound.MaxRecipients,
+		Timeout:        cfg.Inbound.Timeout,
+		Handler:        l,
+	}
+
+	if cfg.Inbound.TLSCertFile != "" || cfg.Inbound.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.Inbound.TLSCertFile, cfg.Inbound.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load inbound TLS certificate: %w", err)
+		}
+		l.server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+	if l.server.RequireTLS && l.server.TLSConfig == nil {
+		return nil, errors.New("inbound TLS is required but no certificate is configured")
+	}
+
+	return l, nil
+}
+
+// Start listens for SMTP connections in the background
+func (l *Listener) Start() {
+	go func() {
+		log.Printf("Inbound SMTP listening on %s", l.addr)
+		if err := l.server.ListenAndServe(l.addr); err != nil && !errors.Is(err, smtpd.ErrServerClosed) {
+			log.Printf("Inbound SMTP listener error: %v", err)
+		}
+	}()
+}
+
+// Stop closes the listener and waits for open connections to finish
+func (l *Listener) Stop(ctx context.Context) error {
+	return l.server.Shutdown(ctx)
+}
+
+// Rcpt accepts recipients that are routed to a webhook
+func (l *Listener) Rcpt(_ context.Context, _ *smtpd.Session, to string) error {
+	if _, err := l.service.Route(to); err != nil {
+		if errors.Is(err, ErrDomainNotAccepted) {
+			return &smtpd.Error{Code: 550, EnhancedCode: "5.7.1", Message: "Relaying denied"}
+		}
+		return &smtpd.Error{Code: 550, EnhancedCode: "5.1.1", Message: "Mailbox unavailable"}
+	}
+	return nil
+}
+
+// Data stores a received email
+func (l *Listener) Data(ctx context.Context, session *smtpd.Session, r io.Reader) error {
+	envelope := Envelope{
+		MailFrom:   session.From,
+		Recipients: session.To,
+		RemoteAddr: session.RemoteAddr.String(),
+		Helo:       session.Helo,
+		TLS:        session.TLS,
+	}
+
+	if _, err := l.service.Receive(ctx, envelope, r); err != nil {
+		if errors.Is(err, ErrInvalidMessage) {
+			return &smtpd.Error{Code: 554, EnhancedCode: "5.6.0", Message: "Malformed message"}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	inbound "GoMail/app/logic/inbound"
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Receive provides a mock function with given fields: ctx, envelope, r
+func (_m *Service) Receive(ctx context.Context, envelope inbound.Envelope, r io.Reader) (*inbound.ReceiveResponse, error) {
+	ret := _m.Called(ctx, envelope, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Receive")
+	}
+
+	var r0 *inbound.ReceiveResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, inbound.Envelope, io.Reader) (*inbound.ReceiveResponse, error)); ok {
+		return rf(ctx, envelope, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, inbound.Envelope, io.Reader) *inbound.ReceiveResponse); ok {
+		r0 = rf(ctx, envelope, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*inbound.ReceiveResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, inbound.Envelope, io.Reader) error); ok {
+		r1 = rf(ctx, envelope, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Route provides a mock function with given fields: address
+func (_m *Service) Route(address string) ([]string, error) {
+	ret := _m.Called(address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Route")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(address)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
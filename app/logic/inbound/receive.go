@@ -0,0 +1,115 @@
+package inbound
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"GoMail/app/libs/mailparse"
+	"GoMail/app/repository/models"
+)
+
+// Receive parses an email and stores it with the webhooks of its recipients
+func (s *service) Receive(ctx context.Context, envelope Envelope, r io.Reader) (*ReceiveResponse, error) {
+	counter := &countingReader{r: r}
+	parsed, err := mailparse.Parse(counter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	now := time.Now()
+	message := &models.InboundMessage{
+		MailFrom:      envelope.MailFrom,
+		Recipients:    envelope.Recipients,
+		RemoteAddr:    envelope.RemoteAddr,
+		Helo:          envelope.Helo,
+		TLS:           envelope.TLS,
+		MessageID:     parsed.MessageID,
+		InReplyTo:     parsed.InReplyTo,
+		References:    parsed.References,
+		Subject:       parsed.Subject,
+		From:          toAddresses(parsed.From),
+		To:            toAddresses(parsed.To),
+		Cc:            toAddresses(parsed.Cc),
+		ReplyTo:       toAddresses(parsed.ReplyTo),
+		Headers:       parsed.Header,
+		Text:          parsed.Text,
+		HTML:          parsed.HTML,
+		Size:          counter.n,
+		Status:        models.InboundStatusPending,
+		Webhooks:      s.deliveries(envelope.Recipients),
+		NextAttemptAt: now,
+		ReceivedAt:    now,
+	}
+	if !parsed.Date.IsZero() {
+		message.Date = &parsed.Date
+	}
+	for _, attachment := range parsed.Attachments {
+		message.Attachments = append(message.Attachments, models.InboundAttachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			ContentID:   attachment.ContentID,
+			Inline:      attachment.Inline,
+			Size:        int64(len(attachment.Content)),
+			Content:     attachment.Content,
+		})
+	}
+
+	if err := s.repo.SaveInboundMessage(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to store inbound message: %w", err)
+	}
+
+	response := &ReceiveResponse{ID: message.ID.Hex(), Webhooks: []string{}}
+	for _, delivery := range message.Webhooks {
+		response.Webhooks = append(response.Webhooks, delivery.URL)
+	}
+	return response, nil
+}
+
+// deliveries groups the recipients of an email by the webhooks they are
+// routed to, so each webhook is called once
+func (s *service) deliveries(recipients []string) []models.InboundDelivery {
+	var deliveries []models.InboundDelivery
+	index := make(map[string]int)
+	for _, recipient := range recipients {
+		urls, err := s.Route(recipient)
+		if err != nil {
+			continue
+		}
+		for _, url := range urls {
+			i, ok := index[url]
+			if !ok {
+				i = len(deliveries)
+				index[url] = i
+				deliveries = append(deliveries, models.InboundDelivery{URL: url})
+			}
+			deliveries[i].Recipients = append(deliveries[i].Recipients, recipient)
+		}
+	}
+	return deliveries
+}
+
+// toAddresses converts parsed addresses to their stored form
+func toAddresses(list []mailparse.Address) []models.InboundAddress {
+	if len(list) == 0 {
+		return nil
+	}
+	addresses := make([]models.InboundAddress, 0, len(list))
+	for _, addr := range list {
+		addresses = append(addresses, models.InboundAddress{Name: addr.Name, Address: addr.Address})
+	}
+	return addresses
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
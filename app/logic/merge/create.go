@@ -0,0 +1,344 @@
+package merge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"GoMail/app/logic/email"
+	"GoMail/app/logic/emailtemplate"
+	"GoMail/app/logic/topic"
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// utf8BOM is written at the start of CSV files by some spreadsheets
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// recipientRow is a recipient of an uploaded list, or the error parsing it
+type recipientRow struct {
+	row  int
+	to   string
+	data map[string]interface{}
+	err  error
+}
+
+// Create validates the recipients of a mail merge and stores its job
+func (s *service) Create(ctx context.Context, req CreateRequest, recipients io.Reader) (*CreateResponse, error) {
+	message, content, err := s.buildMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.parseRecipients(recipients, req.RecipientsFormat)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the recipient list is empty", ErrInvalidMerge)
+	}
+
+	resp := &CreateResponse{Total: len(rows), Errors: []RowError{}}
+	seen := make(map[string]int, len(rows))
+	for _, row := range rows {
+		if row.err == nil {
+			if row.err = s.validateRow(ctx, req.UserID, message, content, row, seen); row.err != nil && !errors.Is(row.err, ErrInvalidMerge) {
+				return nil, row.err
+			}
+		}
+		if row.err != nil {
+			resp.Invalid++
+			resp.Errors = append(resp.Errors, RowError{Row: row.row, Email: row.to, Error: row.err.Error()})
+			continue
+		}
+		resp.Valid++
+	}
+
+	if req.DryRun {
+		return resp, nil
+	}
+	if resp.Valid == 0 {
+		return resp, fmt.Errorf("%w: no valid recipients", ErrInvalidMerge)
+	}
+	if resp.Invalid > 0 && !req.SkipInvalid {
+		return resp, fmt.Errorf("%w: %d of %d rows are invalid", ErrInvalidMerge, resp.Invalid, resp.Total)
+	}
+
+	job := &models.MergeJob{
+		ID:      primitive.NewObjectID(),
+		UserID:  req.UserID,
+		Message: message,
+		Status:  models.MergeJobPending,
+		Total:   resp.Total,
+		Invalid: resp.Invalid,
+	}
+	stored := make([]*models.MergeRow, len(rows))
+	for i, row := range rows {
+		stored[i] = &models.MergeRow{
+			JobID:  job.ID,
+			Row:    row.row,
+			To:     row.to,
+			Data:   row.data,
+			Status: models.MergeRowPending,
+		}
+		if row.err != nil {
+			stored[i].Status = models.MergeRowInvalid
+			stored[i].Error = row.err.Error()
+		}
+	}
+
+	// The rows are stored first so that a runner never claims a job whose
+	// recipients aren't all there yet
+	if err := s.repo.SaveMergeRows(ctx, stored); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveMergeJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	resp.Job = toJobResponse(job)
+	return resp, nil
+}
+
+// buildMessage validates the message of a mail merge. The subject and
+// body of messages that don't use a stored template are compiled once to
+// be rendered for every recipient.
+func (s *service) buildMessage(ctx context.Context, req CreateRequest) (models.MergeMessage, *emailtemplate.Content, error) {
+	message := models.MergeMessage{
+		From:           strings.TrimSpace(req.From),
+		Subject:        req.Subject,
+		Body:           req.Body,
+		IsHTML:         req.IsHTML,
+		Format:         req.Format,
+		TemplateID:     strings.TrimSpace(req.TemplateID),
+		Locale:         strings.TrimSpace(req.Locale),
+		TrackOpens:     req.TrackOpens,
+		TrackClicks:    req.TrackClicks,
+		SendAt:         req.SendAt,
+		AllowDuplicate: req.AllowDuplicate,
+	}
+
+	if _, err := mail.ParseAddress(message.From); err != nil {
+		return message, nil, fmt.Errorf("%w: invalid from address: %v", ErrInvalidMerge, err)
+	}
+	if message.Format != "" && message.Format != email.FormatMarkdown {
+		return message, nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidMerge, message.Format)
+	}
+
+	if req.Topic != "" {
+		found, err := s.topics.Find(ctx, req.UserID, req.Topic)
+		if err != nil {
+			if errors.Is(err, topic.ErrTopicNotFound) {
+				return message, nil, fmt.Errorf("%w: unknown topic %q", ErrInvalidMerge, req.Topic)
+			}
+			return message, nil, err
+		}
+		message.Topic = found.Name
+	}
+	if req.Unsubscribe != nil {
+		message.Unsubscribe = true
+		message.List = strings.TrimSpace(req.Unsubscribe.List)
+	}
+
+	if message.TemplateID != "" {
+		if message.Subject != "" || message.Body != "" {
+			return message, nil, fmt.Errorf("%w: subject and body can't be combined with a template", ErrInvalidMerge)
+		}
+		// Rendering without data only fails on variables when the
+		// template is found
+		_, err := s.templates.Render(ctx, req.UserID, message.TemplateID, emailtemplate.RenderRequest{Locale: message.Locale})
+		if errors.Is(err, emailtemplate.ErrTemplateNotFound) || errors.Is(err, emailtemplate.ErrVersionNotFound) {
+			return message, nil, fmt.Errorf("%w: %v", ErrInvalidMerge, err)
+		}
+		if err != nil && !errors.Is(err, emailtemplate.ErrMissingVariable) && !errors.Is(err, emailtemplate.ErrInvalidTemplate) {
+			return message, nil, err
+		}
+		return message, nil, nil
+	}
+
+	if strings.TrimSpace(message.Subject) == "" || strings.TrimSpace(message.Body) == "" {
+		return message, nil, fmt.Errorf("%w: subject and body, or a template, are required", ErrInvalidMerge)
+	}
+	content, err := emailtemplate.ParseContent(message.Subject, message.Body, message.IsHTML && message.Format == "", message.Locale)
+	if err != nil {
+		return message, nil, fmt.Errorf("%w: %v", ErrInvalidMerge, err)
+	}
+	return message, content, nil
+}
+
+// validateRow checks the address of a recipient and renders the message
+// with its variables. seen maps the addresses of the list to their first
+// row, so that a recipient listed twice is reported.
+func (s *service) validateRow(ctx context.Context, userID string, message models.MergeMessage, content *emailtemplate.Content, row *recipientRow, seen map[string]int) error {
+	address, err := mail.ParseAddress(row.to)
+	if err != nil {
+		return fmt.Errorf("%w: invalid email address: %v", ErrInvalidMerge, err)
+	}
+	key := strings.ToLower(address.Address)
+	if first, ok := seen[key]; ok {
+		return fmt.Errorf("%w: duplicate of row %d", ErrInvalidMerge, first)
+	}
+	seen[key] = row.row
+
+	if content != nil {
+		_, err = content.Render(row.data)
+	} else {
+		_, err = s.templates.Render(ctx, userID, message.TemplateID, emailtemplate.RenderRequest{
+			Data:      row.data,
+			Locale:    message.Locale,
+			Recipient: address.Address,
+		})
+	}
+	if err != nil {
+		if errors.Is(err, emailtemplate.ErrMissingVariable) || errors.Is(err, emailtemplate.ErrInvalidTemplate) {
+			return fmt.Errorf("%w: %v", ErrInvalidMerge, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// parseRecipients reads a recipient list in the given format, or in the
+// format detected from its content: JSON lines when it starts with an
+// object, CSV otherwise
+func (s *service) parseRecipients(r io.Reader, format string) ([]*recipientRow, error) {
+	reader := bufio.NewReader(r)
+	if prefix, _ := reader.Peek(len(utf8BOM)); bytes.Equal(prefix, utf8BOM) {
+		reader.Discard(len(utf8BOM))
+	}
+
+	if format == "" {
+		format = FormatCSV
+		if start, _ := reader.Peek(512); bytes.HasPrefix(bytes.TrimLeft(start, " \t\r\n"), []byte("{")) {
+			format = FormatJSONLines
+		}
+	}
+
+	switch format {
+	case FormatCSV:
+		return s.parseCSV(reader)
+	case FormatJSONLines:
+		return s.parseJSONLines(reader)
+	default:
+		return nil, fmt.Errorf("%w: unsupported recipient format %q", ErrInvalidMerge, format)
+	}
+}
+
+// parseCSV reads a CSV recipient list. The header row names the variables
+// of the columns; the email column, matched in any case, holds the
+// address of the recipient.
+func (s *service) parseCSV(r io.Reader) ([]*recipientRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV header: %v", ErrInvalidMerge, err)
+	}
+	address := -1
+	names := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: CSV column %d has no name", ErrInvalidMerge, i+1)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("%w: CSV column %q is repeated", ErrInvalidMerge, name)
+		}
+		names[name] = true
+		header[i] = name
+		if address < 0 && strings.EqualFold(name, emailColumn) {
+			address = i
+		}
+	}
+	if address < 0 {
+		return nil, fmt.Errorf("%w: CSV needs an email column", ErrInvalidMerge)
+	}
+
+	var rows []*recipientRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if len(rows) == s.config.Merge.MaxRows {
+			return nil, fmt.Errorf("%w: more than %d recipients", ErrInvalidMerge, s.config.Merge.MaxRows)
+		}
+		if err != nil {
+			// Malformed quoting leaves the rest of the file unreadable
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMerge, err)
+		}
+
+		row := &recipientRow{row: len(rows) + 1, data: make(map[string]interface{}, len(header))}
+		if address < len(record) {
+			row.to = strings.TrimSpace(record[address])
+		}
+		if len(record) != len(header) {
+			row.err = fmt.Errorf("%w: row has %d fields, the header has %d", ErrInvalidMerge, len(record), len(header))
+		} else {
+			for i, name := range header {
+				row.data[name] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseJSONLines reads a recipient list with a JSON object per line. The
+// email key holds the address of the recipient and every key is a
+// variable. Blank lines are skipped.
+func (s *service) parseJSONLines(r io.Reader) ([]*recipientRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxJSONLineBytes)
+
+	var rows []*recipientRow
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(rows) == s.config.Merge.MaxRows {
+			return nil, fmt.Errorf("%w: more than %d recipients", ErrInvalidMerge, s.config.Merge.MaxRows)
+		}
+
+		row := &recipientRow{row: len(rows) + 1}
+		rows = append(rows, row)
+
+		// Numbers keep their text, so they render as they were written
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&row.data); err != nil || row.data == nil {
+			row.err = fmt.Errorf("%w: line is not a JSON object", ErrInvalidMerge)
+			continue
+		}
+		to, ok := row.data[emailColumn].(string)
+		if !ok {
+			row.err = fmt.Errorf("%w: %s is required", ErrInvalidMerge, emailColumn)
+			continue
+		}
+		row.to = strings.TrimSpace(to)
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: line %d is longer than %d bytes", ErrInvalidMerge, len(rows)+1, maxJSONLineBytes)
+		}
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,107 @@
+package merge
+
+import (
+	"time"
+
+	"GoMail/app/logic/email"
+	"GoMail/app/repository/models"
+)
+
+// CreateRequest represents a mail merge: a message rendered for every
+// recipient of the uploaded list. The message is either a subject and body
+// with template placeholders such as {{.first_name}}, or a stored template.
+type CreateRequest struct {
+	UserID      string                    `json:"-"`
+	From        string                    `json:"from" binding:"required"`
+	Subject     string                    `json:"subject,omitempty"`
+	Body        string                    `json:"body,omitempty"`
+	IsHTML      bool                      `json:"isHtml"`
+	Format      string                    `json:"format,omitempty"`
+	TemplateID  string                    `json:"templateId,omitempty"`
+	Locale      string                    `json:"locale,omitempty"`
+	Topic       string                    `json:"topic,omitempty"`
+	Unsubscribe *email.UnsubscribeOptions `json:"unsubscribe,omitempty"`
+	TrackOpens  *bool                     `json:"trackOpens,omitempty"`
+	TrackClicks *bool                     `json:"trackClicks,omitempty"`
+	SendAt      *time.Time                `json:"sendAt,omitempty"`
+
+	// AllowDuplicate skips the dedup check for the emails of the job
+	AllowDuplicate bool `json:"allowDuplicate,omitempty"`
+
+	// SkipInvalid creates the job for the valid rows when some rows fail
+	// validation. Otherwise no job is created.
+	SkipInvalid bool `json:"skipInvalid,omitempty"`
+
+	// DryRun only validates the recipients
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// RecipientsFormat is csv or jsonl, detected from the content when
+	// empty
+	RecipientsFormat string `json:"-"`
+}
+
+// CreateResponse reports the validation of a recipient list and the job
+// created for it. Job is nil for dry runs and rejected lists.
+type CreateResponse struct {
+	Job     *JobResponse `json:"job,omitempty"`
+	Total   int          `json:"total"`
+	Valid   int          `json:"valid"`
+	Invalid int          `json:"invalid"`
+	Errors  []RowError   `json:"errors"`
+}
+
+// RowError reports a recipient that failed validation. Row counts
+// recipients from 1, not counting the header row of CSV lists.
+type RowError struct {
+	Row   int    `json:"row"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+// JobResponse represents a mail merge job and its progress
+type JobResponse struct {
+	ID          string                `json:"id"`
+	Status      models.MergeJobStatus `json:"status"`
+	From        string                `json:"from"`
+	Subject     string                `json:"subject,omitempty"`
+	TemplateID  string                `json:"templateId,omitempty"`
+	Topic       string                `json:"topic,omitempty"`
+	SendAt      *time.Time            `json:"sendAt,omitempty"`
+	Total       int                   `json:"total"`
+	Invalid     int                   `json:"invalid"`
+	Pending     int                   `json:"pending"`
+	Queued      int                   `json:"queued"`
+	Suppressed  int                   `json:"suppressed"`
+	Failed      int                   `json:"failed"`
+	StartedAt   *time.Time            `json:"startedAt,omitempty"`
+	CompletedAt *time.Time            `json:"completedAt,omitempty"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt"`
+}
+
+// ListJobsResponse represents a page of jobs
+type ListJobsResponse struct {
+	Jobs  []JobResponse `json:"jobs"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
+
+// RowResponse represents a recipient of a job and its outcome. EmailID is
+// the queued email of the recipient.
+type RowResponse struct {
+	Row     int                    `json:"row"`
+	Email   string                 `json:"email"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+	Status  models.MergeRowStatus  `json:"status"`
+	EmailID string                 `json:"emailId,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// ListRowsResponse represents a page of recipients
+type ListRowsResponse struct {
+	Rows  []RowResponse `json:"rows"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
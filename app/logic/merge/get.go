@@ -0,0 +1,109 @@
+package merge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	mergeJobRepo "GoMail/app/repository/mergejob"
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Get returns a job owned by the user
+func (s *service) Get(ctx context.Context, userID, id string) (*JobResponse, error) {
+	job, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toJobResponse(job), nil
+}
+
+// List returns the jobs owned by the user
+func (s *service) List(ctx context.Context, userID string, page, limit int) (*ListJobsResponse, error) {
+	page, limit = pageBounds(page, limit)
+
+	jobs, total, err := s.repo.FindMergeJobs(ctx, bson.M{"user_id": userID}, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListJobsResponse{
+		Jobs:  make([]JobResponse, 0, len(jobs)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for _, job := range jobs {
+		resp.Jobs = append(resp.Jobs, *toJobResponse(job))
+	}
+	return resp, nil
+}
+
+// Rows returns the recipients of a job, optionally with a single status
+func (s *service) Rows(ctx context.Context, userID, id string, status models.MergeRowStatus, page, limit int) (*ListRowsResponse, error) {
+	job, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	page, limit = pageBounds(page, limit)
+
+	query := bson.M{"job_id": job.ID}
+	switch status {
+	case "":
+	case models.MergeRowPending, models.MergeRowQueued, models.MergeRowSuppressed,
+		models.MergeRowFailed, models.MergeRowInvalid:
+		query["status"] = status
+	default:
+		return nil, fmt.Errorf("%w: unknown row status %q", ErrInvalidMerge, status)
+	}
+
+	rows, total, err := s.repo.FindMergeRows(ctx, query, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListRowsResponse{
+		Rows:  make([]RowResponse, 0, len(rows)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for _, row := range rows {
+		resp.Rows = append(resp.Rows, toRowResponse(row))
+	}
+	return resp, nil
+}
+
+// Cancel stops a pending or running job
+func (s *service) Cancel(ctx context.Context, userID, id string) (*JobResponse, error) {
+	job, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CancelMergeJob(ctx, job.ID, time.Now()); err != nil {
+		if errors.Is(err, mergeJobRepo.ErrJobFinished) {
+			return nil, ErrJobFinished
+		}
+		return nil, err
+	}
+
+	return s.Get(ctx, userID, id)
+}
+
+// pageBounds applies the default and maximum page size
+func pageBounds(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page, limit
+}
@@ -0,0 +1,139 @@
+package merge
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/emailtemplate"
+	"GoMail/app/logic/topic"
+	"GoMail/app/repository"
+	mergeJobRepo "GoMail/app/repository/mergejob"
+	"GoMail/app/repository/models"
+)
+
+var (
+	ErrInvalidMerge = errors.New("invalid mail merge")
+	ErrJobNotFound  = errors.New("merge job not found")
+	ErrJobFinished  = errors.New("merge job is already finished")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Recipient list formats
+const (
+	FormatCSV        = "csv"
+	FormatJSONLines  = "jsonl"
+	emailColumn      = "email"
+	maxJSONLineBytes = 1 << 20
+)
+
+// Service defines the interface for mail merges. A merge sends one message
+// to every recipient of an uploaded list, rendered with the variables of
+// the recipient. The recipients are validated when the merge is created
+// and sent in batches by the Runner.
+type Service interface {
+	// Create validates a recipient list and, unless it is a dry run,
+	// creates a job sending the message to its valid recipients. The
+	// response reports the rows that failed validation; the job is only
+	// created with invalid rows when they are skipped.
+	Create(ctx context.Context, req CreateRequest, recipients io.Reader) (*CreateResponse, error)
+
+	// Get returns a job and its progress
+	Get(ctx context.Context, userID, id string) (*JobResponse, error)
+
+	// List returns the jobs of a user, newest first
+	List(ctx context.Context, userID string, page, limit int) (*ListJobsResponse, error)
+
+	// Rows returns the recipients of a job and their outcome, in the order
+	// of the upload
+	Rows(ctx context.Context, userID, id string, status models.MergeRowStatus, page, limit int) (*ListRowsResponse, error)
+
+	// Cancel stops a job. Recipients that weren't sent yet are left pending.
+	Cancel(ctx context.Context, userID, id string) (*JobResponse, error)
+}
+
+// templateRenderer renders the stored templates of a user
+type templateRenderer interface {
+	Render(ctx context.Context, userID, id string, req emailtemplate.RenderRequest) (*emailtemplate.Rendered, error)
+}
+
+// topicCatalog looks up the topics of a user
+type topicCatalog interface {
+	Find(ctx context.Context, userID, name string) (*models.Topic, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo      repository.Repository
+	templates templateRenderer
+	topics    topicCatalog
+	config    *config.Config
+}
+
+// New creates a new mail merge service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:      repo,
+		templates: emailtemplate.New(repo, cfg),
+		topics:    topic.New(repo, cfg),
+		config:    cfg,
+	}
+}
+
+// find returns a job of a user. Jobs of other users are not found.
+func (s *service) find(ctx context.Context, userID, id string) (*models.MergeJob, error) {
+	job, err := s.repo.FindMergeJobByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mergeJobRepo.ErrJobNotFound) || errors.Is(err, mergeJobRepo.ErrInvalidID) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	if job.UserID != userID {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// toJobResponse converts a job into its API representation
+func toJobResponse(job *models.MergeJob) *JobResponse {
+	return &JobResponse{
+		ID:          job.ID.Hex(),
+		Status:      job.Status,
+		From:        job.Message.From,
+		Subject:     job.Message.Subject,
+		TemplateID:  job.Message.TemplateID,
+		Topic:       job.Message.Topic,
+		SendAt:      job.Message.SendAt,
+		Total:       job.Total,
+		Invalid:     job.Invalid,
+		Pending:     job.Total - job.Invalid - job.Queued - job.Suppressed - job.Failed,
+		Queued:      job.Queued,
+		Suppressed:  job.Suppressed,
+		Failed:      job.Failed,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
+
+// toRowResponse converts a recipient of a job into its API representation
+func toRowResponse(row *models.MergeRow) RowResponse {
+	resp := RowResponse{
+		Row:    row.Row,
+		Email:  row.To,
+		Data:   row.Data,
+		Status: row.Status,
+		Error:  row.Error,
+	}
+	if row.EmailID != nil {
+		resp.EmailID = row.EmailID.Hex()
+	}
+	return resp
+}
@@ -0,0 +1,296 @@
+package merge
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/email"
+	emailMocks "GoMail/app/logic/email/mocks"
+	"GoMail/app/logic/emailtemplate"
+	mergeJobRepo "GoMail/app/repository/mergejob"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newConfig() *config.Config {
+	return &config.Config{Merge: config.MergeConfig{
+		MaxRows:       3,
+		BatchSize:     100,
+		PollInterval:  time.Second,
+		LeaseDuration: time.Minute,
+	}}
+}
+
+// stubTemplates renders a stored template that needs a name variable
+type stubTemplates struct{}
+
+func (stubTemplates) Render(_ context.Context, _, id string, req emailtemplate.RenderRequest) (*emailtemplate.Rendered, error) {
+	if id != "tmpl-1" {
+		return nil, emailtemplate.ErrTemplateNotFound
+	}
+	if _, ok := req.Data["name"]; !ok {
+		return nil, emailtemplate.ErrMissingVariable
+	}
+	return &emailtemplate.Rendered{Subject: "Hi"}, nil
+}
+
+func TestService_Create(t *testing.T) {
+	message := CreateRequest{UserID: "user-1", From: "app@example.com", Subject: "Hi {{.name}}", Body: "Your code is {{.code}}"}
+
+	tests := []struct {
+		name        string
+		req         func(req CreateRequest) CreateRequest
+		recipients  string
+		wantErr     error
+		wantValid   int
+		wantErrors  []RowError
+		wantJob     bool
+		wantInvalid int
+	}{
+		{
+			name:       "csv",
+			recipients: "\xEF\xBB\xBFEmail,name,code\nada@example.org,Ada,1\nbob@example.org,Bob,2\n",
+			wantValid:  2,
+			wantErrors: []RowError{},
+			wantJob:    true,
+		},
+		{
+			name:       "json lines",
+			recipients: "{\"email\":\"ada@example.org\",\"name\":\"Ada\",\"code\":1}\n\n{\"email\":\"bob@example.org\",\"name\":\"Bob\",\"code\":2}\n",
+			wantValid:  2,
+			wantErrors: []RowError{},
+			wantJob:    true,
+		},
+		{
+			name:       "invalid rows reject the list",
+			recipients: "email,name,code\nada@example.org,Ada,1\nnot-an-address,Bob,2\nADA@example.org,Ada,3\n",
+			wantErr:    ErrInvalidMerge,
+			wantValid:  1,
+			wantErrors: []RowError{
+				{Row: 2, Email: "not-an-address", Error: "invalid mail merge: invalid email address: mail: missing '@' or angle-addr"},
+				{Row: 3, Email: "ADA@example.org", Error: "invalid mail merge: duplicate of row 1"},
+			},
+		},
+		{
+			name:       "missing variable",
+			recipients: "{\"email\":\"ada@example.org\",\"name\":\"Ada\"}\n[1]\n",
+			req: func(req CreateRequest) CreateRequest {
+				req.SkipInvalid = true
+				return req
+			},
+			wantErr:   ErrInvalidMerge,
+			wantValid: 0,
+			wantErrors: []RowError{
+				{Row: 1, Email: "ada@example.org", Error: `invalid mail merge: missing template variable "code" in text`},
+				{Row: 2, Error: "invalid mail merge: line is not a JSON object"},
+			},
+		},
+		{
+			name:       "skip invalid rows",
+			recipients: "email,name,code\nada@example.org,Ada,1\nbob,Bob,2\ncyd@example.org,Cyd\n",
+			req: func(req CreateRequest) CreateRequest {
+				req.SkipInvalid = true
+				return req
+			},
+			wantValid: 1,
+			wantErrors: []RowError{
+				{Row: 2, Email: "bob", Error: "invalid mail merge: invalid email address: mail: missing '@' or angle-addr"},
+				{Row: 3, Email: "cyd@example.org", Error: "invalid mail merge: row has 2 fields, the header has 3"},
+			},
+			wantJob:     true,
+			wantInvalid: 2,
+		},
+		{
+			name:       "dry run",
+			recipients: "email,name,code\nada@example.org,Ada,1\n",
+			req: func(req CreateRequest) CreateRequest {
+				req.DryRun = true
+				return req
+			},
+			wantValid:  1,
+			wantErrors: []RowError{},
+		},
+		{
+			name:       "stored template",
+			recipients: "email,name\nada@example.org,Ada\nbob@example.org,\n",
+			req: func(req CreateRequest) CreateRequest {
+				req.Subject, req.Body, req.TemplateID, req.DryRun = "", "", "tmpl-1", true
+				return req
+			},
+			wantValid:  2,
+			wantErrors: []RowError{},
+		},
+		{
+			name:       "unknown template",
+			recipients: "email\nada@example.org\n",
+			req: func(req CreateRequest) CreateRequest {
+				req.Subject, req.Body, req.TemplateID = "", "", "tmpl-2"
+				return req
+			},
+			wantErr: ErrInvalidMerge,
+		},
+		{
+			name:       "no email column",
+			recipients: "address,name,code\nada@example.org,Ada,1\n",
+			wantErr:    ErrInvalidMerge,
+		},
+		{
+			name:       "too many rows",
+			recipients: "email,name,code\na@example.org,A,1\nb@example.org,B,2\nc@example.org,C,3\nd@example.org,D,4\n",
+			wantErr:    ErrInvalidMerge,
+		},
+		{
+			name:       "invalid placeholder",
+			recipients: "email,name,code\nada@example.org,Ada,1\n",
+			req: func(req CreateRequest) CreateRequest {
+				req.Subject = "Hi {{.name"
+				return req
+			},
+			wantErr: ErrInvalidMerge,
+		},
+		{
+			name:       "no body",
+			recipients: "email,name,code\nada@example.org,Ada,1\n",
+			req: func(req CreateRequest) CreateRequest {
+				req.Body = ""
+				return req
+			},
+			wantErr: ErrInvalidMerge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := message
+			if tt.req != nil {
+				req = tt.req(req)
+			}
+			repo := &repoMocks.Repository{}
+			var rows []*models.MergeRow
+			repo.On("SaveMergeRows", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				rows = args.Get(1).([]*models.MergeRow)
+			}).Return(nil)
+			repo.On("SaveMergeJob", mock.Anything, mock.Anything).Return(nil)
+			s := &service{repo: repo, templates: stubTemplates{}, config: newConfig()}
+
+			got, err := s.Create(context.Background(), req, strings.NewReader(tt.recipients))
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErrors != nil {
+				require.NotNil(t, got)
+				assert.Equal(t, tt.wantValid, got.Valid)
+				assert.Equal(t, tt.wantErrors, got.Errors)
+			}
+			if !tt.wantJob {
+				repo.AssertNotCalled(t, "SaveMergeJob", mock.Anything, mock.Anything)
+				return
+			}
+			require.NotNil(t, got.Job)
+			assert.Equal(t, models.MergeJobPending, got.Job.Status)
+			assert.Equal(t, tt.wantInvalid, got.Job.Invalid)
+			assert.Equal(t, tt.wantValid, got.Job.Pending)
+			require.Len(t, rows, tt.wantValid+tt.wantInvalid)
+			assert.Equal(t, "ada@example.org", rows[0].To)
+			assert.Equal(t, "Ada", rows[0].Data["name"])
+		})
+	}
+}
+
+func TestService_Cancel(t *testing.T) {
+	job := &models.MergeJob{ID: primitive.NewObjectID(), UserID: "user-1", Status: models.MergeJobCompleted}
+	repo := &repoMocks.Repository{}
+	repo.On("FindMergeJobByID", mock.Anything, job.ID.Hex()).Return(job, nil)
+	repo.On("CancelMergeJob", mock.Anything, job.ID, mock.Anything).Return(mergeJobRepo.ErrJobFinished)
+
+	_, err := New(repo, newConfig()).Cancel(context.Background(), "user-1", job.ID.Hex())
+	assert.ErrorIs(t, err, ErrJobFinished)
+
+	_, err = New(repo, newConfig()).Cancel(context.Background(), "user-2", job.ID.Hex())
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestService_Rows_UnknownStatus(t *testing.T) {
+	job := &models.MergeJob{ID: primitive.NewObjectID(), UserID: "user-1"}
+	repo := &repoMocks.Repository{}
+	repo.On("FindMergeJobByID", mock.Anything, job.ID.Hex()).Return(job, nil)
+
+	_, err := New(repo, newConfig()).Rows(context.Background(), "user-1", job.ID.Hex(), "sent", 1, 20)
+
+	assert.ErrorIs(t, err, ErrInvalidMerge)
+}
+
+func TestRunner_ProcessNext(t *testing.T) {
+	emailID := primitive.NewObjectID()
+	opens := true
+	job := &models.MergeJob{
+		ID:     primitive.NewObjectID(),
+		UserID: "user-1",
+		Status: models.MergeJobRunning,
+		Total:  3,
+		Message: models.MergeMessage{
+			From:        "app@example.com",
+			Subject:     "Hi {{.name}}",
+			Body:        "<p>{{.name}}</p>",
+			IsHTML:      true,
+			Topic:       "news",
+			Unsubscribe: true,
+			TrackOpens:  &opens,
+		},
+	}
+	rows := []*models.MergeRow{
+		{ID: primitive.NewObjectID(), Row: 1, To: "ada@example.org", Data: map[string]interface{}{"name": "Ada & co"}},
+		{ID: primitive.NewObjectID(), Row: 2, To: "bob@example.org", Data: map[string]interface{}{"name": "Bob"}},
+		{ID: primitive.NewObjectID(), Row: 3, To: "cyd@example.org", Data: map[string]interface{}{}},
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("ClaimMergeJob", mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(job, nil).Once()
+	repo.On("FindPendingMergeRows", mock.Anything, job.ID, 100).Return(rows, nil).Once()
+	repo.On("SaveMergeRowResults", mock.Anything, rows).Return(nil).Once()
+	repo.On("ReleaseMergeJob", mock.Anything, job, mock.Anything).Return(nil).Once()
+	emails := &emailMocks.Email{}
+	emails.On("SendBulk", mock.Anything, mock.MatchedBy(func(req email.SendBulkEmailRequest) bool {
+		return req.UserID == "user-1" && req.Async && len(req.Emails) == 2 &&
+			req.Emails[0].Subject == "Hi Ada & co" &&
+			req.Emails[0].Body == "<p>Ada &amp; co</p>" &&
+			req.Emails[0].Topic == "news" &&
+			req.Emails[0].Unsubscribe != nil &&
+			*req.Emails[0].HTMLOptions.TrackOpens &&
+			req.Emails[1].To == "bob@example.org"
+	})).Return(&email.SendBulkEmailResponse{Results: []email.EmailResult{
+		{Success: true, ID: emailID.Hex()},
+		{Status: email.StatusSuppressed},
+	}}, nil).Once()
+
+	processed, err := NewRunner(newConfig(), repo, emails).processNext(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, models.MergeRowQueued, rows[0].Status)
+	assert.Equal(t, &emailID, rows[0].EmailID)
+	assert.Equal(t, models.MergeRowSuppressed, rows[1].Status)
+	assert.Equal(t, models.MergeRowFailed, rows[2].Status)
+	assert.Contains(t, rows[2].Error, "missing template variable")
+	assert.Equal(t, models.MergeJobCompleted, job.Status)
+	assert.NotNil(t, job.CompletedAt)
+	assert.Equal(t, [3]int{1, 1, 1}, [3]int{job.Queued, job.Suppressed, job.Failed})
+	repo.AssertExpectations(t)
+	emails.AssertExpectations(t)
+}
+
+func TestRunner_ProcessNext_Idle(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("ClaimMergeJob", mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(nil, nil).Once()
+
+	processed, err := NewRunner(newConfig(), repo, &emailMocks.Email{}).processNext(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
@@ -0,0 +1,183 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	merge "GoMail/app/logic/merge"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "GoMail/app/repository/models"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Cancel provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Cancel(ctx context.Context, userID string, id string) (*merge.JobResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 *merge.JobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*merge.JobResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *merge.JobResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*merge.JobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, req, recipients
+func (_m *Service) Create(ctx context.Context, req merge.CreateRequest, recipients io.Reader) (*merge.CreateResponse, error) {
+	ret := _m.Called(ctx, req, recipients)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *merge.CreateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, merge.CreateRequest, io.Reader) (*merge.CreateResponse, error)); ok {
+		return rf(ctx, req, recipients)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, merge.CreateRequest, io.Reader) *merge.CreateResponse); ok {
+		r0 = rf(ctx, req, recipients)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*merge.CreateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, merge.CreateRequest, io.Reader) error); ok {
+		r1 = rf(ctx, req, recipients)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Get(ctx context.Context, userID string, id string) (*merge.JobResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *merge.JobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*merge.JobResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *merge.JobResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*merge.JobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID, page, limit
+func (_m *Service) List(ctx context.Context, userID string, page int, limit int) (*merge.ListJobsResponse, error) {
+	ret := _m.Called(ctx, userID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *merge.ListJobsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*merge.ListJobsResponse, error)); ok {
+		return rf(ctx, userID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *merge.ListJobsResponse); ok {
+		r0 = rf(ctx, userID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*merge.ListJobsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, userID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rows provides a mock function with given fields: ctx, userID, id, status, page, limit
+func (_m *Service) Rows(ctx context.Context, userID string, id string, status models.MergeRowStatus, page int, limit int) (*merge.ListRowsResponse, error) {
+	ret := _m.Called(ctx, userID, id, status, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rows")
+	}
+
+	var r0 *merge.ListRowsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.MergeRowStatus, int, int) (*merge.ListRowsResponse, error)); ok {
+		return rf(ctx, userID, id, status, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.MergeRowStatus, int, int) *merge.ListRowsResponse); ok {
+		r0 = rf(ctx, userID, id, status, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*merge.ListRowsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, models.MergeRowStatus, int, int) error); ok {
+		r1 = rf(ctx, userID, id, status, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
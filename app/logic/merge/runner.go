@@ -0,0 +1,208 @@
+package merge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/lease"
+	"GoMail/app/logic/email"
+	"GoMail/app/logic/emailtemplate"
+	"GoMail/app/repository"
+	mergeJobRepo "GoMail/app/repository/mergejob"
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Runner sends the recipients of mail merge jobs in batches through the
+// email service, which queues them like a bulk send. Jobs are claimed with
+// an atomic find-and-modify lease for a single batch, so runners on several
+// GoMail instances take turns on the jobs of the same collection, and a job
+// whose runner stopped resumes with the next batch once the lease expires.
+type Runner struct {
+	repo    repository.Repository
+	email   email.Email
+	config  config.MergeConfig
+	ownerID string
+	poller  *lease.Poller
+}
+
+// NewRunner creates a new mail merge runner
+func NewRunner(cfg *config.Config, repo repository.Repository, emailService email.Email) *Runner {
+	r := &Runner{
+		repo:    repo,
+		email:   emailService,
+		config:  cfg.Merge,
+		ownerID: lease.NewOwnerID(),
+	}
+	r.poller = lease.NewPoller("Mail merge runner", cfg.Merge.PollInterval, r.processNext)
+	return r
+}
+
+// Start launches the runner loop
+func (r *Runner) Start() {
+	r.poller.Start(1)
+}
+
+// Stop signals the runner loop to exit and waits for the batch it is
+// sending
+func (r *Runner) Stop(ctx context.Context) error {
+	return r.poller.Stop(ctx)
+}
+
+// processNext claims a job and sends its next batch of recipients. It
+// reports whether a job was claimed.
+func (r *Runner) processNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	job, err := r.repo.ClaimMergeJob(ctx, r.ownerID, now, r.config.LeaseDuration)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim merge job: %w", err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	if err := r.sendBatch(ctx, job); err != nil {
+		// The job is claimed again for the batch once the lease expires
+		return true, fmt.Errorf("failed to send batch of merge job %s: %w", job.ID.Hex(), err)
+	}
+
+	if err := r.repo.ReleaseMergeJob(ctx, job, r.ownerID); err != nil {
+		if errors.Is(err, mergeJobRepo.ErrLeaseLost) {
+			log.Printf("Mail merge runner lost lease on job %s", job.ID.Hex())
+			return true, nil
+		}
+		return true, fmt.Errorf("failed to release merge job %s: %w", job.ID.Hex(), err)
+	}
+
+	return true, nil
+}
+
+// sendBatch sends the next recipients of a job and counts their outcome.
+// The job is completed once no recipient is left.
+func (r *Runner) sendBatch(ctx context.Context, job *models.MergeJob) error {
+	rows, err := r.repo.FindPendingMergeRows(ctx, job.ID, r.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) > 0 {
+		if err := r.send(ctx, job, rows); err != nil {
+			return err
+		}
+		if err := r.repo.SaveMergeRowResults(ctx, rows); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			switch row.Status {
+			case models.MergeRowQueued:
+				job.Queued++
+			case models.MergeRowSuppressed:
+				job.Suppressed++
+			default:
+				job.Failed++
+			}
+		}
+	}
+
+	if len(rows) < r.config.BatchSize {
+		now := time.Now()
+		job.Status = models.MergeJobCompleted
+		job.CompletedAt = &now
+	}
+	return nil
+}
+
+// send renders the message of a job for a batch of recipients and queues
+// it, setting the outcome of every row
+func (r *Runner) send(ctx context.Context, job *models.MergeJob, rows []*models.MergeRow) error {
+	message := job.Message
+
+	var content *emailtemplate.Content
+	if message.TemplateID == "" {
+		var err error
+		content, err = emailtemplate.ParseContent(message.Subject, message.Body, message.IsHTML && message.Format == "", message.Locale)
+		if err != nil {
+			return err
+		}
+	}
+
+	var htmlOptions *email.HTMLOptions
+	if message.TrackOpens != nil || message.TrackClicks != nil {
+		htmlOptions = &email.HTMLOptions{TrackOpens: message.TrackOpens, TrackClicks: message.TrackClicks}
+	}
+	var unsubscribe *email.UnsubscribeOptions
+	if message.Unsubscribe {
+		unsubscribe = &email.UnsubscribeOptions{List: message.List}
+	}
+
+	// Rows whose message can't be rendered fail without being sent
+	emails := make([]email.BulkEmail, 0, len(rows))
+	sent := make([]*models.MergeRow, 0, len(rows))
+	for _, row := range rows {
+		bulk := email.BulkEmail{
+			From:        message.From,
+			To:          row.To,
+			IsHTML:      message.IsHTML,
+			Format:      message.Format,
+			TemplateID:  message.TemplateID,
+			Locale:      message.Locale,
+			Topic:       message.Topic,
+			HTMLOptions: htmlOptions,
+			Unsubscribe: unsubscribe,
+		}
+		if content != nil {
+			rendered, err := content.Render(row.Data)
+			if err != nil {
+				row.Status, row.Error = models.MergeRowFailed, err.Error()
+				continue
+			}
+			bulk.Subject, bulk.Body = rendered.Subject, rendered.Text
+			if rendered.HTML != "" {
+				bulk.Body = rendered.HTML
+			}
+		} else {
+			bulk.Data = row.Data
+		}
+		emails = append(emails, bulk)
+		sent = append(sent, row)
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	resp, err := r.email.SendBulk(ctx, email.SendBulkEmailRequest{
+		UserID:         job.UserID,
+		Emails:         emails,
+		Async:          true,
+		SendAt:         message.SendAt,
+		AllowDuplicate: message.AllowDuplicate,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, row := range sent {
+		applyResult(row, resp.Results[i])
+	}
+	return nil
+}
+
+// applyResult records the outcome of the email of a recipient
+func applyResult(row *models.MergeRow, result email.EmailResult) {
+	switch {
+	case result.Success:
+		row.Status, row.Error = models.MergeRowQueued, ""
+		if id, err := primitive.ObjectIDFromHex(result.ID); err == nil {
+			row.EmailID = &id
+		}
+	case result.Status == email.StatusSuppressed || result.Status == email.StatusSuppressedDuplicate:
+		row.Status, row.Error = models.MergeRowSuppressed, result.Status
+	default:
+		row.Status, row.Error = models.MergeRowFailed, result.Error
+	}
+}
@@ -0,0 +1,49 @@
+package recipient
+
+import "time"
+
+// PreferenceRequest represents a request to store the preferences of a
+// recipient. OptedOut, when set, replaces the topics the recipient opted
+// out of.
+type PreferenceRequest struct {
+	Locale   string    `json:"locale"`
+	OptedOut *[]string `json:"optedOut,omitempty"`
+}
+
+// RecipientResponse represents the stored preferences of a recipient
+type RecipientResponse struct {
+	Email     string    `json:"email"`
+	Locale    string    `json:"locale,omitempty"`
+	OptedOut  []string  `json:"optedOut,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ListRecipientsResponse represents a page of recipients
+type ListRecipientsResponse struct {
+	Recipients []RecipientResponse `json:"recipients"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+}
+
+// PreferencesURLResponse represents the address of the preference page of
+// a recipient
+type PreferencesURLResponse struct {
+	URL string `json:"url"`
+}
+
+// PreferencesResponse represents the topics a recipient receives, as shown
+// on the preference page
+type PreferencesResponse struct {
+	Email  string            `json:"email"`
+	Topics []TopicPreference `json:"topics"`
+}
+
+// TopicPreference tells whether a recipient receives the emails of a topic
+type TopicPreference struct {
+	Name          string `json:"name"`
+	Label         string `json:"label"`
+	Description   string `json:"description,omitempty"`
+	Transactional bool   `json:"transactional"` // Always received
+	Subscribed    bool   `json:"subscribed"`
+}
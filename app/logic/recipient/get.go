@@ -0,0 +1,53 @@
+package recipient
+
+import (
+	"context"
+	"errors"
+
+	recipientRepo "GoMail/app/repository/recipient"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Get returns the stored preferences of a recipient
+func (s *service) Get(ctx context.Context, userID, email string) (*RecipientResponse, error) {
+	recipient, err := s.repo.FindRecipient(ctx, userID, email)
+	if err != nil {
+		if errors.Is(err, recipientRepo.ErrRecipientNotFound) {
+			return nil, ErrRecipientNotFound
+		}
+		return nil, err
+	}
+
+	return toResponse(recipient), nil
+}
+
+// List returns the recipients with stored preferences
+func (s *service) List(ctx context.Context, userID string, page, limit int) (*ListRecipientsResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	recipients, total, err := s.repo.FindRecipients(ctx, bson.M{"user_id": userID}, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListRecipientsResponse{
+		Recipients: make([]RecipientResponse, 0, len(recipients)),
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+	}
+	for _, recipient := range recipients {
+		resp.Recipients = append(resp.Recipients, *toResponse(recipient))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,243 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	recipient "GoMail/app/logic/recipient"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, userID, email
+func (_m *Service) Delete(ctx context.Context, userID string, email string) error {
+	ret := _m.Called(ctx, userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, userID, email
+func (_m *Service) Get(ctx context.Context, userID string, email string) (*recipient.RecipientResponse, error) {
+	ret := _m.Called(ctx, userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *recipient.RecipientResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*recipient.RecipientResponse, error)); ok {
+		return rf(ctx, userID, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *recipient.RecipientResponse); ok {
+		r0 = rf(ctx, userID, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*recipient.RecipientResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPreferences provides a mock function with given fields: ctx, token
+func (_m *Service) GetPreferences(ctx context.Context, token string) (*recipient.PreferencesResponse, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreferences")
+	}
+
+	var r0 *recipient.PreferencesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*recipient.PreferencesResponse, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *recipient.PreferencesResponse); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*recipient.PreferencesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID, page, limit
+func (_m *Service) List(ctx context.Context, userID string, page int, limit int) (*recipient.ListRecipientsResponse, error) {
+	ret := _m.Called(ctx, userID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *recipient.ListRecipientsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*recipient.ListRecipientsResponse, error)); ok {
+		return rf(ctx, userID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *recipient.ListRecipientsResponse); ok {
+		r0 = rf(ctx, userID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*recipient.ListRecipientsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, userID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OptedOut provides a mock function with given fields: ctx, userID, email, topic
+func (_m *Service) OptedOut(ctx context.Context, userID string, email string, topic string) (bool, error) {
+	ret := _m.Called(ctx, userID, email, topic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OptedOut")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, userID, email, topic)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, userID, email, topic)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, email, topic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PreferencesURL provides a mock function with given fields: userID, email
+func (_m *Service) PreferencesURL(userID string, email string) string {
+	ret := _m.Called(userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreferencesURL")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(userID, email)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, userID, email, req
+func (_m *Service) Update(ctx context.Context, userID string, email string, req recipient.PreferenceRequest) (*recipient.RecipientResponse, error) {
+	ret := _m.Called(ctx, userID, email, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *recipient.RecipientResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, recipient.PreferenceRequest) (*recipient.RecipientResponse, error)); ok {
+		return rf(ctx, userID, email, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, recipient.PreferenceRequest) *recipient.RecipientResponse); ok {
+		r0 = rf(ctx, userID, email, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*recipient.RecipientResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, recipient.PreferenceRequest) error); ok {
+		r1 = rf(ctx, userID, email, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePreferences provides a mock function with given fields: ctx, token, subscribed
+func (_m *Service) UpdatePreferences(ctx context.Context, token string, subscribed []string) (*recipient.PreferencesResponse, error) {
+	ret := _m.Called(ctx, token, subscribed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePreferences")
+	}
+
+	var r0 *recipient.PreferencesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*recipient.PreferencesResponse, error)); ok {
+		return rf(ctx, token, subscribed)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *recipient.PreferencesResponse); ok {
+		r0 = rf(ctx, token, subscribed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*recipient.PreferencesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, token, subscribed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
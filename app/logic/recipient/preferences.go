@@ -0,0 +1,125 @@
+package recipient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"GoMail/app/logic/topic"
+	"GoMail/app/repository/models"
+	recipientRepo "GoMail/app/repository/recipient"
+)
+
+// OptedOut tells whether a recipient opted out of a topic. Recipients
+// without stored preferences receive every topic.
+func (s *service) OptedOut(ctx context.Context, userID, email, topic string) (bool, error) {
+	recipient, err := s.repo.FindRecipient(ctx, userID, email)
+	if err != nil {
+		if errors.Is(err, recipientRepo.ErrRecipientNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return slices.Contains(recipient.OptedOut, topic), nil
+}
+
+// GetPreferences returns the topics of the user of a token and whether its
+// recipient receives them
+func (s *service) GetPreferences(ctx context.Context, token string) (*PreferencesResponse, error) {
+	userID, email, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var optedOut []string
+	recipient, err := s.repo.FindRecipient(ctx, userID, email)
+	switch {
+	case err == nil:
+		optedOut = recipient.OptedOut
+	case !errors.Is(err, recipientRepo.ErrRecipientNotFound):
+		return nil, err
+	}
+
+	topics, err := s.repo.FindUserTopics(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPreferences(email, topics, optedOut), nil
+}
+
+// UpdatePreferences replaces the topics the recipient of a token opted out
+// of with the ones of the user it didn't subscribe to. Transactional topics
+// stay subscribed.
+func (s *service) UpdatePreferences(ctx context.Context, token string, subscribed []string) (*PreferencesResponse, error) {
+	userID, email, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := s.repo.FindUserTopics(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	optedOut := []string{}
+	for _, t := range topics {
+		if !t.Transactional && !slices.Contains(subscribed, t.Name) {
+			optedOut = append(optedOut, t.Name)
+		}
+	}
+
+	recipient, err := s.repo.UpdateRecipientOptedOut(ctx, userID, email, optedOut)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPreferences(recipient.Email, topics, recipient.OptedOut), nil
+}
+
+// toPreferences converts the topics of a user into the preferences of a
+// recipient that opted out of some
+func toPreferences(email string, topics []*models.Topic, optedOut []string) *PreferencesResponse {
+	resp := &PreferencesResponse{
+		Email:  email,
+		Topics: make([]TopicPreference, 0, len(topics)),
+	}
+	for _, t := range topics {
+		resp.Topics = append(resp.Topics, TopicPreference{
+			Name:          t.Name,
+			Label:         t.Label,
+			Description:   t.Description,
+			Transactional: t.Transactional,
+			Subscribed:    t.Transactional || !slices.Contains(optedOut, t.Name),
+		})
+	}
+	return resp
+}
+
+// validateOptedOut normalizes the topics a recipient opts out of, failing
+// for unknown and transactional topics
+func (s *service) validateOptedOut(ctx context.Context, userID string, names []string) ([]string, error) {
+	topics, err := s.repo.FindUserTopics(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*models.Topic, len(topics))
+	for _, t := range topics {
+		byName[t.Name] = t
+	}
+
+	optedOut := make([]string, 0, len(names))
+	for _, name := range names {
+		normalized, err := topic.NormalizeName(name)
+		if err != nil || byName[normalized] == nil {
+			return nil, fmt.Errorf("%w: unknown topic %q", ErrInvalidRecipient, name)
+		}
+		if byName[normalized].Transactional {
+			return nil, fmt.Errorf("%w: can't opt out of transactional topic %q", ErrInvalidRecipient, name)
+		}
+		if !slices.Contains(optedOut, normalized) {
+			optedOut = append(optedOut, normalized)
+		}
+	}
+	return optedOut, nil
+}
@@ -0,0 +1,118 @@
+package recipient
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"GoMail/app/config"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+	recipientRepo "GoMail/app/repository/recipient"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var topics = []*models.Topic{
+	{Name: "billing", Label: "Billing", Transactional: true},
+	{Name: "news", Label: "News"},
+	{Name: "product-updates", Label: "Product updates"},
+}
+
+func newTestService(repo *repoMocks.Repository) *service {
+	cfg := &config.Config{}
+	cfg.Server.SigningSecret = "secret"
+	cfg.Server.PublicURL = "https://mail.example.com/"
+	return New(repo, cfg).(*service)
+}
+
+func TestService_PreferencesURL(t *testing.T) {
+	s := newTestService(&repoMocks.Repository{})
+
+	url := s.PreferencesURL("user-1", "Ada@Example.org")
+
+	require.True(t, strings.HasPrefix(url, "https://mail.example.com/p/"))
+	userID, email, err := s.verify(strings.TrimPrefix(url, "https://mail.example.com/p/"))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+	assert.Equal(t, "ada@example.org", email)
+
+	_, _, err = s.verify("not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestService_OptedOut(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindRecipient", mock.Anything, "user-1", "ada@example.org").Return(&models.Recipient{OptedOut: []string{"news"}}, nil)
+	repo.On("FindRecipient", mock.Anything, "user-1", "bob@example.org").Return(nil, recipientRepo.ErrRecipientNotFound)
+	s := newTestService(repo)
+
+	for _, tt := range []struct {
+		email string
+		topic string
+		want  bool
+	}{
+		{email: "ada@example.org", topic: "news", want: true},
+		{email: "ada@example.org", topic: "product-updates", want: false},
+		{email: "bob@example.org", topic: "news", want: false},
+	} {
+		got, err := s.OptedOut(context.Background(), "user-1", tt.email, tt.topic)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.email+" "+tt.topic)
+	}
+}
+
+func TestService_UpdatePreferences(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindUserTopics", mock.Anything, "user-1").Return(topics, nil)
+	repo.On("UpdateRecipientOptedOut", mock.Anything, "user-1", "ada@example.org", []string{"product-updates"}).
+		Return(&models.Recipient{Email: "ada@example.org", OptedOut: []string{"product-updates"}}, nil)
+	s := newTestService(repo)
+	token := strings.TrimPrefix(s.PreferencesURL("user-1", "ada@example.org"), "https://mail.example.com/p/")
+
+	// Billing is transactional and stays subscribed without being posted
+	got, err := s.UpdatePreferences(context.Background(), token, []string{"news"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []TopicPreference{
+		{Name: "billing", Label: "Billing", Transactional: true, Subscribed: true},
+		{Name: "news", Label: "News", Subscribed: true},
+		{Name: "product-updates", Label: "Product updates", Subscribed: false},
+	}, got.Topics)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Update_OptedOut(t *testing.T) {
+	tests := []struct {
+		name     string
+		optedOut []string
+		want     []string
+		wantErr  error
+	}{
+		{name: "happy path", optedOut: []string{"News", "news", "product-updates"}, want: []string{"news", "product-updates"}},
+		{name: "unknown topic", optedOut: []string{"sales"}, wantErr: ErrInvalidRecipient},
+		{name: "transactional topic", optedOut: []string{"billing"}, wantErr: ErrInvalidRecipient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repoMocks.Repository{}
+			repo.On("FindUserTopics", mock.Anything, "user-1").Return(topics, nil)
+			repo.On("UpsertRecipient", mock.Anything, mock.AnythingOfType("*models.Recipient")).Return(nil)
+			repo.On("UpdateRecipientOptedOut", mock.Anything, "user-1", "ada@example.org", tt.want).
+				Return(&models.Recipient{Email: "ada@example.org", OptedOut: tt.want}, nil)
+			s := newTestService(repo)
+
+			got, err := s.Update(context.Background(), "user-1", "ada@example.org", PreferenceRequest{OptedOut: &tt.optedOut})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "UpsertRecipient", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.OptedOut)
+		})
+	}
+}
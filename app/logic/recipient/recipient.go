@@ -0,0 +1,116 @@
+package recipient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/signer"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+)
+
+var (
+	ErrInvalidRecipient  = errors.New("invalid recipient")
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrInvalidToken      = errors.New("invalid preference token")
+)
+
+// tokenPurpose separates preference page tokens from other signed tokens
+const tokenPurpose = "preferences"
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Service defines the interface for recipient preference operations
+type Service interface {
+	// Get returns the stored preferences of a recipient
+	Get(ctx context.Context, userID, email string) (*RecipientResponse, error)
+
+	// List returns the recipients with stored preferences
+	List(ctx context.Context, userID string, page, limit int) (*ListRecipientsResponse, error)
+
+	// Update stores the preferences of a recipient
+	Update(ctx context.Context, userID, email string, req PreferenceRequest) (*RecipientResponse, error)
+
+	// Delete removes the preferences of a recipient
+	Delete(ctx context.Context, userID, email string) error
+
+	// PreferencesURL returns the address of the preference page of a
+	// recipient
+	PreferencesURL(userID, email string) string
+
+	// OptedOut tells whether a recipient opted out of a topic
+	OptedOut(ctx context.Context, userID, email, topic string) (bool, error)
+
+	// GetPreferences verifies a preference page token and returns the
+	// topics its recipient receives
+	GetPreferences(ctx context.Context, token string) (*PreferencesResponse, error)
+
+	// UpdatePreferences verifies a preference page token and opts its
+	// recipient out of the topics, except transactional ones, that aren't
+	// subscribed
+	UpdatePreferences(ctx context.Context, token string, subscribed []string) (*PreferencesResponse, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	signer *signer.Signer
+	config *config.Config
+}
+
+// New creates a new recipient preference service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		signer: signer.New(cfg.Server.SigningSecret, tokenPurpose),
+		config: cfg,
+	}
+}
+
+// parseAddress validates a recipient email address
+func parseAddress(email string) (string, error) {
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidRecipient, err)
+	}
+	return address.Address, nil
+}
+
+// toResponse converts stored preferences into their API representation
+func toResponse(recipient *models.Recipient) *RecipientResponse {
+	return &RecipientResponse{
+		Email:     recipient.Email,
+		Locale:    recipient.Locale,
+		OptedOut:  recipient.OptedOut,
+		UpdatedAt: recipient.UpdatedAt,
+	}
+}
+
+// PreferencesURL returns the public address of the preference page of a
+// recipient. Tokens never expire, since recipients follow the links of old
+// emails too.
+func (s *service) PreferencesURL(userID, email string) string {
+	token := s.signer.Sign([]byte(userID+"\n"+strings.ToLower(email)), time.Time{})
+	return strings.TrimRight(s.config.Server.PublicURL, "/") + "/p/" + token
+}
+
+// verify checks a preference page token and returns its user and recipient
+func (s *service) verify(token string) (string, string, error) {
+	payload, _, err := s.signer.Verify(token, time.Now())
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+	userID, email, ok := strings.Cut(string(payload), "\n")
+	if !ok || userID == "" || email == "" || strings.Contains(email, "\n") {
+		return "", "", ErrInvalidToken
+	}
+	return userID, email, nil
+}
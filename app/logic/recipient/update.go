@@ -0,0 +1,58 @@
+package recipient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"GoMail/app/libs/locale"
+	"GoMail/app/repository/models"
+	recipientRepo "GoMail/app/repository/recipient"
+)
+
+// Update stores the preferences of a recipient
+func (s *service) Update(ctx context.Context, userID, email string, req PreferenceRequest) (*RecipientResponse, error) {
+	address, err := parseAddress(email)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient := &models.Recipient{
+		UserID: userID,
+		Email:  address,
+	}
+	if req.Locale != "" {
+		if recipient.Locale, err = locale.Normalize(req.Locale); err != nil {
+			return nil, fmt.Errorf("%w: %w %q", ErrInvalidRecipient, err, req.Locale)
+		}
+	}
+
+	var optedOut []string
+	if req.OptedOut != nil {
+		if optedOut, err = s.validateOptedOut(ctx, userID, *req.OptedOut); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpsertRecipient(ctx, recipient); err != nil {
+		return nil, err
+	}
+	if req.OptedOut != nil {
+		if recipient, err = s.repo.UpdateRecipientOptedOut(ctx, userID, address, optedOut); err != nil {
+			return nil, err
+		}
+	}
+
+	return toResponse(recipient), nil
+}
+
+// Delete removes the preferences of a recipient
+func (s *service) Delete(ctx context.Context, userID, email string) error {
+	if err := s.repo.DeleteRecipient(ctx, userID, email); err != nil {
+		if errors.Is(err, recipientRepo.ErrRecipientNotFound) {
+			return ErrRecipientNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+)
+
+// Create stores a new recurring schedule
+func (s *service) Create(ctx context.Context, req ScheduleRequest) (*ScheduleResponse, error) {
+	spec, loc, err := validateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateTemplate(ctx, req.UserID, req.Message); err != nil {
+		return nil, err
+	}
+
+	schedule := &models.Schedule{UserID: req.UserID}
+	applyRequest(schedule, req, spec, loc, time.Now())
+
+	if err := s.repo.SaveSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return toResponse(schedule), nil
+}
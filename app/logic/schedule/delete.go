@@ -0,0 +1,24 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+
+	scheduleRepo "GoMail/app/repository/schedule"
+)
+
+// Delete removes a schedule. Its run history is kept.
+func (s *service) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.findOwned(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteSchedule(ctx, id); err != nil {
+		if errors.Is(err, scheduleRepo.ErrScheduleNotFound) {
+			return ErrScheduleNotFound
+		}
+		return err
+	}
+
+	return nil
+}
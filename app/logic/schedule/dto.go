@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"time"
+
+	"GoMail/app/repository/models"
+)
+
+// ScheduleRequest represents a request to create or replace a recurring schedule
+type ScheduleRequest struct {
+	UserID         string          `json:"-"`
+	Name           string          `json:"name"`
+	CronExpression string          `json:"cronExpression"`
+	TimeZone       string          `json:"timeZone"`
+	Message        ScheduleMessage `json:"message"`
+	Recipients     []string        `json:"recipients"`
+	StartAt        *time.Time      `json:"startAt,omitempty"`
+	EndAt          *time.Time      `json:"endAt,omitempty"`
+}
+
+// ScheduleMessage represents the email sent on every run of a schedule
+type ScheduleMessage struct {
+	From    string `json:"from"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	IsHTML  bool   `json:"isHtml"`
+
+	// TemplateID renders the published version of a stored template with
+	// Data instead of using Body. Subject overrides the template subject
+	// when set, and Locale selects the translation.
+	TemplateID string                 `json:"templateId,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Locale     string                 `json:"locale,omitempty"`
+}
+
+// ScheduleResponse represents a recurring schedule
+type ScheduleResponse struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	CronExpression string          `json:"cronExpression"`
+	TimeZone       string          `json:"timeZone"`
+	Message        ScheduleMessage `json:"message"`
+	Recipients     []string        `json:"recipients"`
+	StartAt        *time.Time      `json:"startAt,omitempty"`
+	EndAt          *time.Time      `json:"endAt,omitempty"`
+	Status         string          `json:"status"`
+	NextRunAt      *time.Time      `json:"nextRunAt,omitempty"`
+	LastRunAt      *time.Time      `json:"lastRunAt,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+// ListSchedulesResponse represents a page of recurring schedules
+type ListSchedulesResponse struct {
+	Schedules []ScheduleResponse `json:"schedules"`
+	Total     int64              `json:"total"`
+	Page      int                `json:"page"`
+	Limit     int                `json:"limit"`
+}
+
+// PreviewRequest represents a request to preview the runs of a cron expression
+type PreviewRequest struct {
+	CronExpression string     `json:"cronExpression"`
+	TimeZone       string     `json:"timeZone"`
+	StartAt        *time.Time `json:"startAt,omitempty"`
+	EndAt          *time.Time `json:"endAt,omitempty"`
+	Count          int        `json:"count"`
+}
+
+// NextRunsResponse represents the upcoming run times of a schedule
+type NextRunsResponse struct {
+	NextRuns []time.Time `json:"nextRuns"`
+}
+
+// ScheduleRunResponse represents a single run of a schedule and the email
+// logs of the messages it sent
+type ScheduleRunResponse struct {
+	ID           string             `json:"id"`
+	ScheduledFor time.Time          `json:"scheduledFor"`
+	EmailIDs     []string           `json:"emailIds"`
+	Failed       int                `json:"failed"`
+	Error        string             `json:"error,omitempty"`
+	Logs         []*models.EmailLog `json:"logs"`
+	CreatedAt    time.Time          `json:"createdAt"`
+}
+
+// ListRunsResponse represents a page of schedule runs
+type ListRunsResponse struct {
+	Runs  []ScheduleRunResponse `json:"runs"`
+	Total int64                 `json:"total"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+}
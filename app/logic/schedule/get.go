@@ -0,0 +1,39 @@
+package schedule
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Get returns a schedule owned by the user
+func (s *service) Get(ctx context.Context, userID, id string) (*ScheduleResponse, error) {
+	schedule, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponse(schedule), nil
+}
+
+// List returns the schedules owned by the user
+func (s *service) List(ctx context.Context, userID string, page, limit int) (*ListSchedulesResponse, error) {
+	page, limit = pagination(page, limit)
+
+	schedules, total, err := s.repo.FindSchedules(ctx, bson.M{"user_id": userID}, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListSchedulesResponse{
+		Schedules: make([]ScheduleResponse, 0, len(schedules)),
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}
+	for _, schedule := range schedules {
+		resp.Schedules = append(resp.Schedules, *toResponse(schedule))
+	}
+
+	return resp, nil
+}
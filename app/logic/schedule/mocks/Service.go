@@ -0,0 +1,347 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	schedule "GoMail/app/logic/schedule"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, req
+func (_m *Service) Create(ctx context.Context, req schedule.ScheduleRequest) (*schedule.ScheduleResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *schedule.ScheduleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, schedule.ScheduleRequest) (*schedule.ScheduleResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, schedule.ScheduleRequest) *schedule.ScheduleResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.ScheduleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, schedule.ScheduleRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Delete(ctx context.Context, userID string, id string) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Get(ctx context.Context, userID string, id string) (*schedule.ScheduleResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *schedule.ScheduleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*schedule.ScheduleResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *schedule.ScheduleResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.ScheduleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID, page, limit
+func (_m *Service) List(ctx context.Context, userID string, page int, limit int) (*schedule.ListSchedulesResponse, error) {
+	ret := _m.Called(ctx, userID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *schedule.ListSchedulesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (*schedule.ListSchedulesResponse, error)); ok {
+		return rf(ctx, userID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *schedule.ListSchedulesResponse); ok {
+		r0 = rf(ctx, userID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.ListSchedulesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, userID, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NextRuns provides a mock function with given fields: ctx, userID, id, count
+func (_m *Service) NextRuns(ctx context.Context, userID string, id string, count int) (*schedule.NextRunsResponse, error) {
+	ret := _m.Called(ctx, userID, id, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextRuns")
+	}
+
+	var r0 *schedule.NextRunsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*schedule.NextRunsResponse, error)); ok {
+		return rf(ctx, userID, id, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *schedule.NextRunsResponse); ok {
+		r0 = rf(ctx, userID, id, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.NextRunsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, userID, id, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Pause provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Pause(ctx context.Context, userID string, id string) (*schedule.ScheduleResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pause")
+	}
+
+	var r0 *schedule.ScheduleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*schedule.ScheduleResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *schedule.ScheduleResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.ScheduleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Preview provides a mock function with given fields: ctx, req
+func (_m *Service) Preview(ctx context.Context, req schedule.PreviewRequest) (*schedule.NextRunsResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Preview")
+	}
+
+	var r0 *schedule.NextRunsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, schedule.PreviewRequest) (*schedule.NextRunsResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, schedule.PreviewRequest) *schedule.NextRunsResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.NextRunsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, schedule.PreviewRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resume provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Resume(ctx context.Context, userID string, id string) (*schedule.ScheduleResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resume")
+	}
+
+	var r0 *schedule.ScheduleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*schedule.ScheduleResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *schedule.ScheduleResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.ScheduleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunDue provides a mock function with given fields: ctx, now
+func (_m *Service) RunDue(ctx context.Context, now time.Time) (int, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunDue")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Runs provides a mock function with given fields: ctx, userID, id, page, limit
+func (_m *Service) Runs(ctx context.Context, userID string, id string, page int, limit int) (*schedule.ListRunsResponse, error) {
+	ret := _m.Called(ctx, userID, id, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Runs")
+	}
+
+	var r0 *schedule.ListRunsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) (*schedule.ListRunsResponse, error)); ok {
+		return rf(ctx, userID, id, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) *schedule.ListRunsResponse); ok {
+		r0 = rf(ctx, userID, id, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.ListRunsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int) error); ok {
+		r1 = rf(ctx, userID, id, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, userID, id, req
+func (_m *Service) Update(ctx context.Context, userID string, id string, req schedule.ScheduleRequest) (*schedule.ScheduleResponse, error) {
+	ret := _m.Called(ctx, userID, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *schedule.ScheduleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, schedule.ScheduleRequest) (*schedule.ScheduleResponse, error)); ok {
+		return rf(ctx, userID, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, schedule.ScheduleRequest) *schedule.ScheduleResponse); ok {
+		r0 = rf(ctx, userID, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.ScheduleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, schedule.ScheduleRequest) error); ok {
+		r1 = rf(ctx, userID, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
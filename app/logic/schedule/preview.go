@@ -0,0 +1,64 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/libs/cron"
+)
+
+// NextRuns previews the upcoming run times of a schedule
+func (s *service) NextRuns(ctx context.Context, userID, id string, count int) (*NextRunsResponse, error) {
+	schedule, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, loc, err := parseTiming(schedule.CronExpression, schedule.TimeZone, schedule.StartAt, schedule.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start from the stored next run so a preview matches what will fire
+	from := time.Now()
+	if schedule.NextRunAt != nil {
+		from = schedule.NextRunAt.Add(-time.Nanosecond)
+	}
+
+	return &NextRunsResponse{
+		NextRuns: upcomingRuns(spec, loc, from, schedule.StartAt, schedule.EndAt, count),
+	}, nil
+}
+
+// Preview returns the upcoming run times of a cron expression
+func (s *service) Preview(ctx context.Context, req PreviewRequest) (*NextRunsResponse, error) {
+	spec, loc, err := parseTiming(req.CronExpression, req.TimeZone, req.StartAt, req.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NextRunsResponse{
+		NextRuns: upcomingRuns(spec, loc, time.Now(), req.StartAt, req.EndAt, req.Count),
+	}, nil
+}
+
+// upcomingRuns lists up to count run times after from, in the schedule time zone
+func upcomingRuns(spec *cron.Schedule, loc *time.Location, from time.Time, startAt, endAt *time.Time, count int) []time.Time {
+	if count < 1 {
+		count = defaultPreviewCount
+	}
+	if count > maxPreviewCount {
+		count = maxPreviewCount
+	}
+
+	runs := make([]time.Time, 0, count)
+	for len(runs) < count {
+		next := nextRun(spec, loc, from, startAt, endAt)
+		if next == nil {
+			break
+		}
+		runs = append(runs, next.In(loc))
+		from = *next
+	}
+	return runs
+}
@@ -0,0 +1,117 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"GoMail/app/logic/email"
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// dueBatchSize bounds the number of schedules fired per evaluation
+const dueBatchSize = 100
+
+// RunDue fires every schedule that is due. Each schedule is advanced with a
+// compare-and-swap before its emails are queued, so a run is only fired by
+// one instance. Runs missed while GoMail was down are coalesced into one.
+func (s *service) RunDue(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.repo.FindDueSchedules(ctx, now, dueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for _, schedule := range schedules {
+		ok, err := s.fire(ctx, schedule, now)
+		if err != nil {
+			log.Printf("Failed to run schedule %s: %v", schedule.ID.Hex(), err)
+			continue
+		}
+		if ok {
+			fired++
+		}
+	}
+
+	return fired, nil
+}
+
+// fire claims the due run of a schedule and queues its emails. It reports
+// whether this instance won the run.
+func (s *service) fire(ctx context.Context, schedule *models.Schedule, now time.Time) (bool, error) {
+	scheduledFor := *schedule.NextRunAt
+
+	// Advance to the first run after now, skipping any missed runs
+	schedule.LastRunAt = &scheduledFor
+	schedule.NextRunAt = nil
+	schedule.Status = models.ScheduleStatusCompleted
+	if spec, loc, err := parseTiming(schedule.CronExpression, schedule.TimeZone, schedule.StartAt, schedule.EndAt); err == nil {
+		if next := nextRun(spec, loc, now, schedule.StartAt, schedule.EndAt); next != nil {
+			schedule.NextRunAt = next
+			schedule.Status = models.ScheduleStatusActive
+		}
+	}
+
+	won, err := s.repo.AdvanceSchedule(ctx, schedule, scheduledFor)
+	if err != nil || !won {
+		return false, err
+	}
+
+	run := &models.ScheduleRun{
+		ScheduleID:   schedule.ID,
+		UserID:       schedule.UserID,
+		ScheduledFor: scheduledFor,
+		EmailIDs:     make([]primitive.ObjectID, 0, len(schedule.Recipients)),
+	}
+
+	for _, recipient := range schedule.Recipients {
+		id, err := s.enqueue(ctx, schedule, recipient)
+		if err != nil {
+			run.Failed++
+			run.Error = err.Error()
+			continue
+		}
+		run.EmailIDs = append(run.EmailIDs, id)
+	}
+
+	if err := s.repo.SaveScheduleRun(ctx, run); err != nil {
+		return true, fmt.Errorf("failed to record run: %w", err)
+	}
+
+	return true, nil
+}
+
+// enqueue queues the schedule message for a single recipient
+func (s *service) enqueue(ctx context.Context, schedule *models.Schedule, recipient string) (primitive.ObjectID, error) {
+	req := email.SendEmailRequest{
+		UserID:  schedule.UserID,
+		From:    schedule.Message.From,
+		To:      recipient,
+		Subject: schedule.Message.Subject,
+		Body:    schedule.Message.Body,
+		Async:   true,
+
+		TemplateID: schedule.Message.TemplateID,
+		Data:       schedule.Message.Data,
+		Locale:     schedule.Message.Locale,
+
+		// Runs repeat the same message on purpose
+		AllowDuplicate: true,
+	}
+
+	var resp *email.SendEmailResponse
+	var err error
+	if schedule.Message.IsHTML {
+		resp, err = s.email.SendHTML(ctx, req)
+	} else {
+		resp, err = s.email.Send(ctx, req)
+	}
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	return primitive.ObjectIDFromHex(resp.ID)
+}
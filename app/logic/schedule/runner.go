@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"GoMail/app/config"
+)
+
+// Runner periodically fires due recurring schedules
+type Runner struct {
+	service  Service
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewRunner creates a new recurring schedule runner
+func NewRunner(cfg *config.Config, service Service) *Runner {
+	return &Runner{
+		service:  service,
+		interval: cfg.Queue.SchedulerInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start launches the runner loop
+func (r *Runner) Start() {
+	go r.run()
+}
+
+// Stop signals the runner loop to exit and waits for it
+func (r *Runner) Stop(ctx context.Context) error {
+	close(r.stop)
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run fires due schedules on every tick until the runner is stopped
+func (r *Runner) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		fired, err := r.service.RunDue(ctx, time.Now())
+		cancel()
+
+		if err != nil {
+			log.Printf("Recurring schedule error: %v", err)
+		} else if fired > 0 {
+			log.Printf("Fired %d recurring schedules", fired)
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
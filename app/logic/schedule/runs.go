@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Runs returns the run history of a schedule together with the email logs
+// of the messages each run sent
+func (s *service) Runs(ctx context.Context, userID, id string, page, limit int) (*ListRunsResponse, error) {
+	schedule, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	page, limit = pagination(page, limit)
+
+	runs, total, err := s.repo.FindScheduleRuns(ctx, bson.M{"schedule_id": schedule.ID}, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListRunsResponse{
+		Runs:  make([]ScheduleRunResponse, 0, len(runs)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for _, run := range runs {
+		logs, err := s.runLogs(ctx, run)
+		if err != nil {
+			return nil, err
+		}
+
+		emailIDs := make([]string, 0, len(run.EmailIDs))
+		for _, emailID := range run.EmailIDs {
+			emailIDs = append(emailIDs, emailID.Hex())
+		}
+
+		resp.Runs = append(resp.Runs, ScheduleRunResponse{
+			ID:           run.ID.Hex(),
+			ScheduledFor: run.ScheduledFor,
+			EmailIDs:     emailIDs,
+			Failed:       run.Failed,
+			Error:        run.Error,
+			Logs:         logs,
+			CreatedAt:    run.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+// runLogs loads the delivery logs of the emails queued by a run
+func (s *service) runLogs(ctx context.Context, run *models.ScheduleRun) ([]*models.EmailLog, error) {
+	if len(run.EmailIDs) == 0 {
+		return []*models.EmailLog{}, nil
+	}
+
+	// Every attempt is logged, so allow a few retries per email
+	filter := bson.M{"email_id": bson.M{"$in": append([]primitive.ObjectID(nil), run.EmailIDs...)}}
+	logs, _, err := s.repo.FindEmailLogs(ctx, filter, 1, len(run.EmailIDs)*s.config.Queue.MaxAttempts+1)
+	return logs, err
+}
@@ -0,0 +1,264 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/cron"
+	"GoMail/app/logic/email"
+	"GoMail/app/logic/emailtemplate"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+	scheduleRepo "GoMail/app/repository/schedule"
+)
+
+var (
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+	ErrScheduleNotFound = errors.New("schedule not found")
+)
+
+const (
+	defaultPageSize     = 20
+	maxPageSize         = 100
+	defaultPreviewCount = 5
+	maxPreviewCount     = 50
+)
+
+// Service defines the interface for recurring schedule operations
+type Service interface {
+	// Create stores a new recurring schedule
+	Create(ctx context.Context, req ScheduleRequest) (*ScheduleResponse, error)
+
+	// Get returns a schedule owned by the user
+	Get(ctx context.Context, userID, id string) (*ScheduleResponse, error)
+
+	// List returns the schedules owned by the user
+	List(ctx context.Context, userID string, page, limit int) (*ListSchedulesResponse, error)
+
+	// Update replaces the definition of a schedule
+	Update(ctx context.Context, userID, id string, req ScheduleRequest) (*ScheduleResponse, error)
+
+	// Delete removes a schedule
+	Delete(ctx context.Context, userID, id string) error
+
+	// Pause stops a schedule from firing until it is resumed
+	Pause(ctx context.Context, userID, id string) (*ScheduleResponse, error)
+
+	// Resume reactivates a paused schedule from the current time
+	Resume(ctx context.Context, userID, id string) (*ScheduleResponse, error)
+
+	// NextRuns previews the upcoming run times of a schedule
+	NextRuns(ctx context.Context, userID, id string, count int) (*NextRunsResponse, error)
+
+	// Preview returns the upcoming run times of a cron expression
+	Preview(ctx context.Context, req PreviewRequest) (*NextRunsResponse, error)
+
+	// Runs returns the run history of a schedule
+	Runs(ctx context.Context, userID, id string, page, limit int) (*ListRunsResponse, error)
+
+	// RunDue fires every schedule that is due and returns how many fired
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// templateRenderer renders the stored templates of a user
+type templateRenderer interface {
+	Render(ctx context.Context, userID, id string, req emailtemplate.RenderRequest) (*emailtemplate.Rendered, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo      repository.Repository
+	email     email.Email
+	templates templateRenderer
+	config    *config.Config
+}
+
+// New creates a new recurring schedule service
+func New(repo repository.Repository, emailService email.Email, cfg *config.Config) Service {
+	return &service{
+		repo:      repo,
+		email:     emailService,
+		templates: emailtemplate.New(repo, cfg),
+		config:    cfg,
+	}
+}
+
+// findOwned loads a schedule and hides schedules owned by other users
+func (s *service) findOwned(ctx context.Context, userID, id string) (*models.Schedule, error) {
+	stored, err := s.repo.FindScheduleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, scheduleRepo.ErrScheduleNotFound) || errors.Is(err, scheduleRepo.ErrInvalidID) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	if stored.UserID != userID {
+		return nil, ErrScheduleNotFound
+	}
+
+	return stored, nil
+}
+
+// parseTiming validates a cron expression, time zone and date window
+func parseTiming(expr, timeZone string, startAt, endAt *time.Time) (*cron.Schedule, *time.Location, error) {
+	spec, err := cron.Parse(expr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidSchedule, timeZone)
+	}
+
+	if startAt != nil && endAt != nil && !endAt.After(*startAt) {
+		return nil, nil, fmt.Errorf("%w: endAt must be after startAt", ErrInvalidSchedule)
+	}
+
+	return spec, loc, nil
+}
+
+// validateRequest checks a schedule definition and returns its parsed timing
+func validateRequest(req ScheduleRequest) (*cron.Schedule, *time.Location, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, nil, fmt.Errorf("%w: name is required", ErrInvalidSchedule)
+	}
+	if len(req.Recipients) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one recipient is required", ErrInvalidSchedule)
+	}
+	if req.Message.TemplateID != "" {
+		if req.Message.Body != "" {
+			return nil, nil, fmt.Errorf("%w: message body can't be combined with a template", ErrInvalidSchedule)
+		}
+	} else if req.Message.Subject == "" || req.Message.Body == "" {
+		return nil, nil, fmt.Errorf("%w: message subject and body, or a template, are required", ErrInvalidSchedule)
+	}
+
+	return parseTiming(req.CronExpression, req.TimeZone, req.StartAt, req.EndAt)
+}
+
+// validateTemplate renders the template of a message with its data, so that
+// a schedule whose runs would fail to render is rejected up front. Like a
+// template send, it fails when the template lacks the part the message sends.
+func (s *service) validateTemplate(ctx context.Context, userID string, message ScheduleMessage) error {
+	if message.TemplateID == "" {
+		return nil
+	}
+
+	rendered, err := s.templates.Render(ctx, userID, message.TemplateID, emailtemplate.RenderRequest{
+		Data:   message.Data,
+		Locale: message.Locale,
+	})
+	if err != nil {
+		if errors.Is(err, emailtemplate.ErrTemplateNotFound) || errors.Is(err, emailtemplate.ErrVersionNotFound) ||
+			errors.Is(err, emailtemplate.ErrMissingVariable) || errors.Is(err, emailtemplate.ErrInvalidTemplate) {
+			return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		return err
+	}
+
+	body, part := rendered.Text, "text"
+	if message.IsHTML {
+		body, part = rendered.HTML, "html"
+	}
+	if body == "" {
+		return fmt.Errorf("%w: template %s has no %s part", ErrInvalidSchedule, message.TemplateID, part)
+	}
+	return nil
+}
+
+// nextRun returns the first run strictly after the given time that falls
+// within the start and end dates, or nil if there is none
+func nextRun(spec *cron.Schedule, loc *time.Location, after time.Time, startAt, endAt *time.Time) *time.Time {
+	// A run exactly at the start date is allowed
+	if startAt != nil && after.Before(*startAt) {
+		after = startAt.Add(-time.Nanosecond)
+	}
+
+	next := spec.Next(after.In(loc))
+	if next.IsZero() || (endAt != nil && next.After(*endAt)) {
+		return nil
+	}
+
+	next = next.UTC()
+	return &next
+}
+
+// applyRequest copies a validated request onto a schedule and computes its next run
+func applyRequest(schedule *models.Schedule, req ScheduleRequest, spec *cron.Schedule, loc *time.Location, now time.Time) {
+	schedule.Name = req.Name
+	schedule.CronExpression = req.CronExpression
+	schedule.TimeZone = loc.String()
+	schedule.Message = models.ScheduleMessage{
+		From:       req.Message.From,
+		Subject:    req.Message.Subject,
+		Body:       req.Message.Body,
+		IsHTML:     req.Message.IsHTML,
+		TemplateID: req.Message.TemplateID,
+		Data:       req.Message.Data,
+		Locale:     req.Message.Locale,
+	}
+	schedule.Recipients = req.Recipients
+	schedule.StartAt = req.StartAt
+	schedule.EndAt = req.EndAt
+
+	if schedule.Status == models.ScheduleStatusPaused {
+		schedule.NextRunAt = nil
+		return
+	}
+
+	schedule.NextRunAt = nextRun(spec, loc, now, req.StartAt, req.EndAt)
+	schedule.Status = models.ScheduleStatusActive
+	if schedule.NextRunAt == nil {
+		schedule.Status = models.ScheduleStatusCompleted
+	}
+}
+
+// toResponse converts a stored schedule into its API representation
+func toResponse(schedule *models.Schedule) *ScheduleResponse {
+	return &ScheduleResponse{
+		ID:             schedule.ID.Hex(),
+		Name:           schedule.Name,
+		CronExpression: schedule.CronExpression,
+		TimeZone:       schedule.TimeZone,
+		Message: ScheduleMessage{
+			From:       schedule.Message.From,
+			Subject:    schedule.Message.Subject,
+			Body:       schedule.Message.Body,
+			IsHTML:     schedule.Message.IsHTML,
+			TemplateID: schedule.Message.TemplateID,
+			Data:       schedule.Message.Data,
+			Locale:     schedule.Message.Locale,
+		},
+		Recipients: schedule.Recipients,
+		StartAt:    schedule.StartAt,
+		EndAt:      schedule.EndAt,
+		Status:     string(schedule.Status),
+		NextRunAt:  schedule.NextRunAt,
+		LastRunAt:  schedule.LastRunAt,
+		CreatedAt:  schedule.CreatedAt,
+		UpdatedAt:  schedule.UpdatedAt,
+	}
+}
+
+// pagination normalizes the page and limit query parameters
+func pagination(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page, limit
+}
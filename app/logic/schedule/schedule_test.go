@@ -0,0 +1,348 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/email"
+	emailMocks "GoMail/app/logic/email/mocks"
+	"GoMail/app/logic/emailtemplate"
+	templateMocks "GoMail/app/logic/emailtemplate/mocks"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+	scheduleRepo "GoMail/app/repository/schedule"
+)
+
+var validScheduleRequest = ScheduleRequest{
+	UserID:         "user-1",
+	Name:           "Weekly report",
+	CronExpression: "0 9 * * MON",
+	TimeZone:       "Europe/Berlin",
+	Message: ScheduleMessage{
+		From:    "reports@example.com",
+		Subject: "Weekly report",
+		Body:    "See attached numbers",
+	},
+	Recipients: []string{"ops@example.com", "cto@example.com"},
+}
+
+func newTestService(repo *repoMocks.Repository, emailService *emailMocks.Email) *service {
+	return &service{
+		repo:   repo,
+		email:  emailService,
+		config: &config.Config{Queue: config.QueueConfig{MaxAttempts: 3}},
+	}
+}
+
+func TestService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(req *ScheduleRequest)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(req *ScheduleRequest) {}},
+		{name: "invalid cron", mutate: func(req *ScheduleRequest) { req.CronExpression = "every monday" }, wantErr: true},
+		{name: "unknown time zone", mutate: func(req *ScheduleRequest) { req.TimeZone = "Mars/Olympus" }, wantErr: true},
+		{name: "no recipients", mutate: func(req *ScheduleRequest) { req.Recipients = nil }, wantErr: true},
+		{name: "missing body", mutate: func(req *ScheduleRequest) { req.Message.Body = "" }, wantErr: true},
+		{
+			name: "end before start",
+			mutate: func(req *ScheduleRequest) {
+				start := time.Now().Add(48 * time.Hour)
+				end := start.Add(-time.Hour)
+				req.StartAt, req.EndAt = &start, &end
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validScheduleRequest
+			tt.mutate(&req)
+
+			repo := &repoMocks.Repository{}
+			repo.On("SaveSchedule", mock.Anything, mock.AnythingOfType("*models.Schedule")).Return(nil)
+
+			got, err := newTestService(repo, &emailMocks.Email{}).Create(context.Background(), req)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSchedule)
+				repo.AssertNotCalled(t, "SaveSchedule", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "active", got.Status)
+			require.NotNil(t, got.NextRunAt)
+
+			berlin, _ := time.LoadLocation("Europe/Berlin")
+			next := got.NextRunAt.In(berlin)
+			assert.Equal(t, time.Monday, next.Weekday())
+			assert.Equal(t, 9, next.Hour())
+		})
+	}
+}
+
+func TestService_Create_Template(t *testing.T) {
+	data := map[string]interface{}{"team": "ops"}
+
+	tests := []struct {
+		name     string
+		mutate   func(msg *ScheduleMessage)
+		rendered *emailtemplate.Rendered
+		err      error
+		wantErr  bool
+	}{
+		{name: "valid", mutate: func(msg *ScheduleMessage) {}, rendered: &emailtemplate.Rendered{Subject: "Report", Text: "Hi ops"}},
+		{name: "valid html", mutate: func(msg *ScheduleMessage) { msg.IsHTML = true }, rendered: &emailtemplate.Rendered{Subject: "Report", HTML: "<p>Hi ops</p>"}},
+		{name: "unknown template", mutate: func(msg *ScheduleMessage) {}, err: emailtemplate.ErrTemplateNotFound, wantErr: true},
+		{name: "missing variable", mutate: func(msg *ScheduleMessage) {}, err: fmt.Errorf("%w %q", emailtemplate.ErrMissingVariable, "team"), wantErr: true},
+		{name: "missing part", mutate: func(msg *ScheduleMessage) { msg.IsHTML = true }, rendered: &emailtemplate.Rendered{Subject: "Report", Text: "Hi ops"}, wantErr: true},
+		{name: "body with template", mutate: func(msg *ScheduleMessage) { msg.Body = "Hi" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validScheduleRequest
+			req.Message = ScheduleMessage{From: "reports@example.com", TemplateID: "tmpl-1", Data: data, Locale: "de"}
+			tt.mutate(&req.Message)
+
+			repo := &repoMocks.Repository{}
+			repo.On("SaveSchedule", mock.Anything, mock.AnythingOfType("*models.Schedule")).Return(nil)
+
+			templates := &templateMocks.Service{}
+			templates.On("Render", mock.Anything, "user-1", "tmpl-1", emailtemplate.RenderRequest{Data: data, Locale: "de"}).Return(tt.rendered, tt.err)
+
+			s := newTestService(repo, &emailMocks.Email{})
+			s.templates = templates
+			got, err := s.Create(context.Background(), req)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSchedule)
+				repo.AssertNotCalled(t, "SaveSchedule", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "tmpl-1", got.Message.TemplateID)
+			assert.Equal(t, data, got.Message.Data)
+			assert.Equal(t, "de", got.Message.Locale)
+		})
+	}
+}
+
+func TestService_Get_OtherUser(t *testing.T) {
+	stored := &models.Schedule{ID: primitive.NewObjectID(), UserID: "user-2"}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindScheduleByID", mock.Anything, stored.ID.Hex()).Return(stored, nil)
+	repo.On("FindScheduleByID", mock.Anything, "bad").Return(nil, scheduleRepo.ErrInvalidID)
+
+	s := newTestService(repo, &emailMocks.Email{})
+
+	_, err := s.Get(context.Background(), "user-1", stored.ID.Hex())
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+
+	_, err = s.Get(context.Background(), "user-1", "bad")
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+}
+
+func TestService_PauseResume(t *testing.T) {
+	next := time.Now().Add(time.Hour)
+	stored := &models.Schedule{
+		ID:             primitive.NewObjectID(),
+		UserID:         "user-1",
+		CronExpression: "*/5 * * * *",
+		TimeZone:       "UTC",
+		Status:         models.ScheduleStatusActive,
+		NextRunAt:      &next,
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindScheduleByID", mock.Anything, stored.ID.Hex()).Return(stored, nil)
+	repo.On("SaveSchedule", mock.Anything, stored).Return(nil)
+
+	s := newTestService(repo, &emailMocks.Email{})
+
+	paused, err := s.Pause(context.Background(), "user-1", stored.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "paused", paused.Status)
+	assert.Nil(t, paused.NextRunAt)
+
+	resumed, err := s.Resume(context.Background(), "user-1", stored.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "active", resumed.Status)
+	require.NotNil(t, resumed.NextRunAt)
+	assert.WithinDuration(t, time.Now(), *resumed.NextRunAt, 5*time.Minute)
+}
+
+func TestService_Preview(t *testing.T) {
+	start := time.Now().AddDate(0, 0, 1)
+	end := start.AddDate(0, 0, 3)
+
+	s := newTestService(&repoMocks.Repository{}, &emailMocks.Email{})
+
+	got, err := s.Preview(context.Background(), PreviewRequest{
+		CronExpression: "@daily",
+		TimeZone:       "Asia/Tokyo",
+		StartAt:        &start,
+		EndAt:          &end,
+		Count:          10,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, got.NextRuns, 3)
+	for _, run := range got.NextRuns {
+		assert.Equal(t, "Asia/Tokyo", run.Location().String())
+		assert.Equal(t, 0, run.Hour())
+	}
+
+	_, err = s.Preview(context.Background(), PreviewRequest{CronExpression: "61 * * * *"})
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+}
+
+func TestService_RunDue(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 0, 30, 0, time.UTC)
+	due := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	queuedID := primitive.NewObjectID()
+
+	won := &models.Schedule{
+		ID:             primitive.NewObjectID(),
+		UserID:         "user-1",
+		CronExpression: "0 9 * * *",
+		TimeZone:       "UTC",
+		Message:        models.ScheduleMessage{From: "f@example.com", Subject: "s", Body: "b", IsHTML: true},
+		Recipients:     []string{"a@example.com", "b@example.com"},
+		Status:         models.ScheduleStatusActive,
+		NextRunAt:      &due,
+	}
+	lostDue := due
+	lost := &models.Schedule{
+		ID:             primitive.NewObjectID(),
+		CronExpression: "0 9 * * *",
+		Recipients:     []string{"c@example.com"},
+		Status:         models.ScheduleStatusActive,
+		NextRunAt:      &lostDue,
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindDueSchedules", mock.Anything, now, dueBatchSize).Return([]*models.Schedule{won, lost}, nil)
+	repo.On("AdvanceSchedule", mock.Anything, won, due).Return(true, nil)
+	repo.On("AdvanceSchedule", mock.Anything, lost, due).Return(false, nil)
+	repo.On("SaveScheduleRun", mock.Anything, mock.MatchedBy(func(run *models.ScheduleRun) bool {
+		return run.ScheduleID == won.ID &&
+			run.ScheduledFor.Equal(due) &&
+			len(run.EmailIDs) == 1 && run.EmailIDs[0] == queuedID &&
+			run.Failed == 1
+	})).Return(nil)
+
+	emailService := &emailMocks.Email{}
+	emailService.On("SendHTML", mock.Anything, mock.MatchedBy(func(req email.SendEmailRequest) bool {
+		return req.To == "a@example.com" && req.Async && req.UserID == "user-1"
+	})).Return(&email.SendEmailResponse{Success: true, ID: queuedID.Hex()}, nil)
+	emailService.On("SendHTML", mock.Anything, mock.MatchedBy(func(req email.SendEmailRequest) bool {
+		return req.To == "b@example.com"
+	})).Return(&email.SendEmailResponse{Success: false}, errors.New("db error"))
+
+	fired, err := newTestService(repo, emailService).RunDue(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Equal(t, time.Date(2026, time.March, 3, 9, 0, 0, 0, time.UTC), *won.NextRunAt)
+	assert.Equal(t, due, *won.LastRunAt)
+	repo.AssertExpectations(t)
+	emailService.AssertExpectations(t)
+}
+
+func TestService_RunDue_Completes(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 0, 30, 0, time.UTC)
+	due := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.March, 2, 12, 0, 0, 0, time.UTC)
+
+	schedule := &models.Schedule{
+		ID:             primitive.NewObjectID(),
+		CronExpression: "0 9 * * *",
+		TimeZone:       "UTC",
+		EndAt:          &end,
+		Status:         models.ScheduleStatusActive,
+		NextRunAt:      &due,
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindDueSchedules", mock.Anything, now, dueBatchSize).Return([]*models.Schedule{schedule}, nil)
+	repo.On("AdvanceSchedule", mock.Anything, schedule, due).Return(true, nil)
+	repo.On("SaveScheduleRun", mock.Anything, mock.AnythingOfType("*models.ScheduleRun")).Return(nil)
+
+	_, err := newTestService(repo, &emailMocks.Email{}).RunDue(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ScheduleStatusCompleted, schedule.Status)
+	assert.Nil(t, schedule.NextRunAt)
+}
+
+func TestService_RunDue_Template(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 0, 30, 0, time.UTC)
+	due := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	data := map[string]interface{}{"team": "ops"}
+
+	schedule := &models.Schedule{
+		ID:             primitive.NewObjectID(),
+		UserID:         "user-1",
+		CronExpression: "0 9 * * *",
+		TimeZone:       "UTC",
+		Message:        models.ScheduleMessage{From: "f@example.com", TemplateID: "tmpl-1", Data: data, Locale: "de"},
+		Recipients:     []string{"a@example.com"},
+		Status:         models.ScheduleStatusActive,
+		NextRunAt:      &due,
+	}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindDueSchedules", mock.Anything, now, dueBatchSize).Return([]*models.Schedule{schedule}, nil)
+	repo.On("AdvanceSchedule", mock.Anything, schedule, due).Return(true, nil)
+	repo.On("SaveScheduleRun", mock.Anything, mock.AnythingOfType("*models.ScheduleRun")).Return(nil)
+
+	emailService := &emailMocks.Email{}
+	emailService.On("Send", mock.Anything, mock.MatchedBy(func(req email.SendEmailRequest) bool {
+		return req.To == "a@example.com" && req.TemplateID == "tmpl-1" &&
+			req.Data["team"] == "ops" && req.Locale == "de" && req.Body == ""
+	})).Return(&email.SendEmailResponse{Success: true, ID: primitive.NewObjectID().Hex()}, nil)
+
+	fired, err := newTestService(repo, emailService).RunDue(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+	emailService.AssertExpectations(t)
+}
+
+func TestService_Runs(t *testing.T) {
+	stored := &models.Schedule{ID: primitive.NewObjectID(), UserID: "user-1"}
+	emailID := primitive.NewObjectID()
+	run := &models.ScheduleRun{
+		ID:         primitive.NewObjectID(),
+		ScheduleID: stored.ID,
+		EmailIDs:   []primitive.ObjectID{emailID},
+	}
+	emailLog := &models.EmailLog{EmailID: &emailID, Success: true}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindScheduleByID", mock.Anything, stored.ID.Hex()).Return(stored, nil)
+	repo.On("FindScheduleRuns", mock.Anything, mock.Anything, 1, defaultPageSize).Return([]*models.ScheduleRun{run}, int64(1), nil)
+	repo.On("FindEmailLogs", mock.Anything, mock.Anything, 1, 4).Return([]*models.EmailLog{emailLog}, int64(1), nil)
+
+	got, err := newTestService(repo, &emailMocks.Email{}).Runs(context.Background(), "user-1", stored.ID.Hex(), 0, 0)
+
+	require.NoError(t, err)
+	require.Len(t, got.Runs, 1)
+	assert.Equal(t, []string{emailID.Hex()}, got.Runs[0].EmailIDs)
+	assert.Equal(t, []*models.EmailLog{emailLog}, got.Runs[0].Logs)
+}
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+)
+
+// Update replaces the definition of a schedule. Its next run is recomputed
+// from the current time.
+func (s *service) Update(ctx context.Context, userID, id string, req ScheduleRequest) (*ScheduleResponse, error) {
+	spec, loc, err := validateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateTemplate(ctx, userID, req.Message); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyRequest(schedule, req, spec, loc, time.Now())
+
+	if err := s.repo.SaveSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return toResponse(schedule), nil
+}
+
+// Pause stops a schedule from firing until it is resumed
+func (s *service) Pause(ctx context.Context, userID, id string) (*ScheduleResponse, error) {
+	schedule, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.Status == models.ScheduleStatusActive {
+		schedule.Status = models.ScheduleStatusPaused
+		schedule.NextRunAt = nil
+
+		if err := s.repo.SaveSchedule(ctx, schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	return toResponse(schedule), nil
+}
+
+// Resume reactivates a paused schedule. Runs missed while it was paused
+// are skipped.
+func (s *service) Resume(ctx context.Context, userID, id string) (*ScheduleResponse, error) {
+	schedule, err := s.findOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.Status != models.ScheduleStatusPaused {
+		return toResponse(schedule), nil
+	}
+
+	spec, loc, err := parseTiming(schedule.CronExpression, schedule.TimeZone, schedule.StartAt, schedule.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.NextRunAt = nextRun(spec, loc, time.Now(), schedule.StartAt, schedule.EndAt)
+	schedule.Status = models.ScheduleStatusActive
+	if schedule.NextRunAt == nil {
+		schedule.Status = models.ScheduleStatusCompleted
+	}
+
+	if err := s.repo.SaveSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return toResponse(schedule), nil
+}
@@ -0,0 +1,21 @@
+package submission
+
+import (
+	"GoMail/app/logic/email"
+)
+
+// SubmitRequest represents the SMTP transaction an email was submitted
+// with. Username is the address the user authenticated with.
+type SubmitRequest struct {
+	UserID     string
+	Username   string
+	MailFrom   string
+	Recipients []string
+	RemoteAddr string
+}
+
+// SubmitResponse represents the sends of a submitted email: one to the
+// recipients of its To and Cc headers and one to each blind copy recipient
+type SubmitResponse struct {
+	Sends []*email.SendEmailResponse
+}
@@ -0,0 +1,80 @@
+package submission
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// authLimiter counts failed authentication attempts per remote IP address
+// and per username, so credentials can't be guessed from many connections
+// or against one account from many addresses
+type authLimiter struct {
+	mu          sync.Mutex
+	failures    map[string][]time.Time
+	maxFailures int
+	window      time.Duration
+}
+
+// newAuthLimiter creates a limiter allowing maxFailures failed attempts
+// per window, the same budget the API grants its login endpoint
+func newAuthLimiter(maxFailures int, window time.Duration) *authLimiter {
+	return &authLimiter{
+		failures:    make(map[string][]time.Time),
+		maxFailures: maxFailures,
+		window:      window,
+	}
+}
+
+// blocked reports whether the address or the username used up its failures
+func (l *authLimiter) blocked(ip, username string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range limiterKeys(ip, username) {
+		if len(l.recent(key, now)) >= l.maxFailures {
+			return true
+		}
+	}
+	return false
+}
+
+// fail records a failed attempt for the address and the username
+func (l *authLimiter) fail(ip, username string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range limiterKeys(ip, username) {
+		l.failures[key] = append(l.recent(key, now), now)
+	}
+
+	// Forget addresses and usernames whose failures have all expired
+	for key := range l.failures {
+		l.recent(key, now)
+	}
+}
+
+// recent drops the expired failures of a key and returns the rest
+func (l *authLimiter) recent(key string, now time.Time) []time.Time {
+	oldestAllowed := now.Add(-l.window)
+	var recent []time.Time
+	for _, failure := range l.failures[key] {
+		if failure.After(oldestAllowed) {
+			recent = append(recent, failure)
+		}
+	}
+	if len(recent) == 0 {
+		delete(l.failures, key)
+	} else {
+		l.failures[key] = recent
+	}
+	return recent
+}
+
+// limiterKeys returns the keys of an address and a username. Usernames are
+// email addresses, so they are compared without case.
+func limiterKeys(ip, username string) []string {
+	return []string{"ip:" + ip, "user:" + strings.ToLower(username)}
+}
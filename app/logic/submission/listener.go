@@ -0,0 +1,145 @@
+package submission
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/smtpd"
+	"GoMail/app/logic/email"
+)
+
+// errTooManyFailures rejects clients whose address or username failed to
+// authenticate too often
+var errTooManyFailures = &smtpd.Error{Code: 454, EnhancedCode: "4.7.0", Message: "Too many failed authentication attempts, try again later"}
+
+// Listener accepts emails from authenticated clients over SMTP and hands
+// them to the service
+type Listener struct {
+	service Service
+	server  *smtpd.Server
+	limiter *authLimiter
+	addr    string
+}
+
+// NewListener creates an SMTP submission listener on the configured port.
+// It fails when the TLS certificate can't be loaded, or when there is none
+// and authentication over unencrypted connections isn't allowed.
+func NewListener(cfg *config.Config, service Service) (*Listener, error) {
+	l := &Listener{
+		service: service,
+		limiter: newAuthLimiter(5, time.Minute),
+		addr:    ":" + cfg.Submission.Port,
+	}
+	l.server = &smtpd.Server{
+		Hostname:          cfg.Submission.Hostname,
+		RequireAuth:       true,
+		AllowInsecureAuth: cfg.Submission.AllowInsecureAuth,
+		MaxMessageSize:    cfg.Submission.MaxMessageSize,
+		MaxRecipients:     cfg.Submission.MaxRecipients,
+		Timeout:           cfg.Submission.Timeout,
+		Handler:           l,
+	}
+
+	if cfg.Submission.TLSCertFile != "" || cfg.Submission.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.Submission.TLSCertFile, cfg.Submission.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load submission TLS certificate: %w", err)
+		}
+		l.server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+	if l.server.TLSConfig == nil && !l.server.AllowInsecureAuth {
+		return nil, errors.New("submission requires a TLS certificate unless insecure authentication is allowed")
+	}
+
+	return l, nil
+}
+
+// Start listens for SMTP connections in the background
+func (l *Listener) Start() {
+	go func() {
+		log.Printf("SMTP submission listening on %s", l.addr)
+		if err := l.server.ListenAndServe(l.addr); err != nil && !errors.Is(err, smtpd.ErrServerClosed) {
+			log.Printf("SMTP submission listener error: %v", err)
+		}
+	}()
+}
+
+// Stop closes the listener and waits for open connections to finish
+func (l *Listener) Stop(ctx context.Context) error {
+	return l.server.Shutdown(ctx)
+}
+
+// Auth authenticates a client as a GoMail user. After 5 failed attempts a
+// minute from its address or for its username, further attempts are
+// turned away without checking the credentials.
+func (l *Listener) Auth(ctx context.Context, session *smtpd.Session, username, password string) (string, error) {
+	ip := remoteIP(session.RemoteAddr)
+	if l.limiter.blocked(ip, username) {
+		return "", errTooManyFailures
+	}
+
+	userID, err := l.service.Authenticate(ctx, username, password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			l.limiter.fail(ip, username)
+			return "", smtpd.ErrAuthFailed
+		}
+		return "", err
+	}
+	return userID, nil
+}
+
+// Rcpt accepts any recipient, since only authenticated clients get to
+// start a transaction
+func (l *Listener) Rcpt(_ context.Context, _ *smtpd.Session, _ string) error {
+	return nil
+}
+
+// Data sends a submitted email
+func (l *Listener) Data(ctx context.Context, session *smtpd.Session, r io.Reader) error {
+	req := SubmitRequest{
+		UserID:     session.User,
+		Username:   session.Username,
+		MailFrom:   session.From,
+		Recipients: session.To,
+		RemoteAddr: session.RemoteAddr.String(),
+	}
+
+	_, err := l.service.Submit(ctx, req, r)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrSenderNotAllowed):
+		return &smtpd.Error{Code: 553, EnhancedCode: "5.7.1", Message: "Sender address not allowed for this user"}
+	case errors.Is(err, ErrInvalidMessage), errors.Is(err, email.ErrInvalidAttachment):
+		return &smtpd.Error{Code: 554, EnhancedCode: "5.6.0", Message: "Malformed message"}
+	case errors.Is(err, email.ErrAttachmentTooLarge):
+		return &smtpd.Error{Code: 552, EnhancedCode: "5.3.4", Message: "Attachment exceeds the size limit"}
+	case errors.Is(err, email.ErrAttachmentInfected):
+		return &smtpd.Error{Code: 554, EnhancedCode: "5.7.1", Message: "Attachment rejected by the virus scanner"}
+	default:
+		return err
+	}
+}
+
+// remoteIP returns the IP address of a client without its port
+func remoteIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	submission "GoMail/app/logic/submission"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, username, password
+func (_m *Service) Authenticate(ctx context.Context, username string, password string) (string, error) {
+	ret := _m.Called(ctx, username, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, username, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, username, password)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, username, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Submit provides a mock function with given fields: ctx, req, r
+func (_m *Service) Submit(ctx context.Context, req submission.SubmitRequest, r io.Reader) (*submission.SubmitResponse, error) {
+	ret := _m.Called(ctx, req, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Submit")
+	}
+
+	var r0 *submission.SubmitResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, submission.SubmitRequest, io.Reader) (*submission.SubmitResponse, error)); ok {
+		return rf(ctx, req, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, submission.SubmitRequest, io.Reader) *submission.SubmitResponse); ok {
+		r0 = rf(ctx, req, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*submission.SubmitResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, submission.SubmitRequest, io.Reader) error); ok {
+		r1 = rf(ctx, req, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
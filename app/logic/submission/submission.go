@@ -0,0 +1,77 @@
+package submission
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/auth"
+	"GoMail/app/logic/email"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidMessage     = errors.New("invalid message")
+	ErrSenderNotAllowed   = errors.New("sender not allowed")
+)
+
+// Service defines the interface for emails submitted over SMTP. Submitted
+// emails are sent through the email service like API requests, so they
+// are logged, checked against the suppression list and deduplicated.
+type Service interface {
+	// Authenticate checks the credentials of a client and returns the ID
+	// of its user. The password is either the password of the user or an
+	// API token issued to them.
+	Authenticate(ctx context.Context, username, password string) (string, error)
+
+	// Submit parses and sends an email for a user
+	Submit(ctx context.Context, req SubmitRequest, r io.Reader) (*SubmitResponse, error)
+}
+
+// credentials checks the passwords and API tokens of users
+type credentials interface {
+	Login(ctx context.Context, email, password string) (string, error)
+	VerifyToken(tokenString string) (*auth.Claims, error)
+}
+
+// service implements the Service interface
+type service struct {
+	emails email.Email
+	auth   credentials
+	config *config.Config
+}
+
+// New creates a new submission service
+func New(emails email.Email, authService credentials, cfg *config.Config) Service {
+	return &service{
+		emails: emails,
+		auth:   authService,
+		config: cfg,
+	}
+}
+
+// Authenticate returns the user of a password or API token
+func (s *service) Authenticate(ctx context.Context, username, password string) (string, error) {
+	// A token is only accepted for the user it was issued to
+	if claims, err := s.auth.VerifyToken(password); err == nil {
+		if !strings.EqualFold(claims.Email, username) {
+			return "", ErrInvalidCredentials
+		}
+		return claims.UserID, nil
+	}
+
+	token, err := s.auth.Login(ctx, username, password)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			return "", ErrInvalidCredentials
+		}
+		return "", err
+	}
+	claims, err := s.auth.VerifyToken(token)
+	if err != nil {
+		return "", err
+	}
+	return claims.UserID, nil
+}
@@ -0,0 +1,300 @@
+package submission
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/mailparse"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/libs/smtpd"
+	"GoMail/app/logic/auth"
+	authMocks "GoMail/app/logic/auth/mocks"
+	"GoMail/app/logic/email"
+	emailMocks "GoMail/app/logic/email/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const message = "From: App <app@example.com>\r\n" +
+	"To: ada@example.org\r\n" +
+	"Cc: bob@example.org\r\n" +
+	"Subject: Your invoice\r\n" +
+	"\r\n" +
+	"Hello\r\n"
+
+func TestService_Authenticate(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		setup    func(a *authMocks.Service)
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "api token",
+			password: "token",
+			setup: func(a *authMocks.Service) {
+				a.On("VerifyToken", "token").Return(&auth.Claims{UserID: "user-1", Email: "App@example.com"}, nil)
+			},
+			want: "user-1",
+		},
+		{
+			name:     "token of another user",
+			password: "token",
+			setup: func(a *authMocks.Service) {
+				a.On("VerifyToken", "token").Return(&auth.Claims{UserID: "user-2", Email: "eve@example.com"}, nil)
+			},
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:     "password",
+			password: "secret",
+			setup: func(a *authMocks.Service) {
+				a.On("VerifyToken", "secret").Return(nil, errors.New("malformed"))
+				a.On("Login", mock.Anything, "app@example.com", "secret").Return("issued", nil)
+				a.On("VerifyToken", "issued").Return(&auth.Claims{UserID: "user-1", Email: "app@example.com"}, nil)
+			},
+			want: "user-1",
+		},
+		{
+			name:     "wrong password",
+			password: "wrong",
+			setup: func(a *authMocks.Service) {
+				a.On("VerifyToken", "wrong").Return(nil, errors.New("malformed"))
+				a.On("Login", mock.Anything, "app@example.com", "wrong").Return("", auth.ErrInvalidCredentials)
+			},
+			wantErr: ErrInvalidCredentials,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := &authMocks.Service{}
+			tt.setup(authService)
+			s := New(&emailMocks.Email{}, authService, &config.Config{})
+
+			got, err := s.Authenticate(context.Background(), "app@example.com", tt.password)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			authService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Submit(t *testing.T) {
+	emails := &emailMocks.Email{}
+	emails.On("Send", mock.Anything, email.SendEmailRequest{
+		UserID:  "user-1",
+		From:    "app@example.com",
+		To:      "ada@example.org,Bob@example.org",
+		Subject: "Your invoice",
+		Body:    "Hello\n",
+	}).Return(&email.SendEmailResponse{Success: true}, nil).Once()
+	emails.On("Send", mock.Anything, mock.MatchedBy(func(req email.SendEmailRequest) bool {
+		return req.To == "audit@example.com"
+	})).Return(&email.SendEmailResponse{Success: true}, nil).Once()
+
+	s := New(emails, &authMocks.Service{}, &config.Config{})
+	got, err := s.Submit(context.Background(), SubmitRequest{
+		UserID:     "user-1",
+		Username:   "app@example.com",
+		MailFrom:   "bounces@example.com",
+		Recipients: []string{"ada@example.org", "audit@example.com", "Bob@example.org"},
+	}, strings.NewReader(message))
+
+	require.NoError(t, err)
+	assert.Len(t, got.Sends, 2)
+	emails.AssertExpectations(t)
+}
+
+func TestService_Submit_Attachments(t *testing.T) {
+	emails := &emailMocks.Email{}
+	emails.On("SendWithAttachments", mock.Anything, mock.MatchedBy(func(req email.SendWithAttachmentsRequest) bool {
+		return req.Body == "Hi there\n" &&
+			len(req.Attachments) == 1 &&
+			req.Attachments[0].Filename == "invoice.csv" &&
+			string(req.Attachments[0].Content) == "a,b"
+	})).Return(&email.SendEmailResponse{Success: true}, nil).Once()
+
+	s := New(emails, &authMocks.Service{}, &config.Config{})
+	_, err := s.Submit(context.Background(), SubmitRequest{UserID: "user-1", Username: "app@example.com", Recipients: []string{"ada@example.org"}}, strings.NewReader(
+		"From: app@example.com\r\n"+
+			"To: ada@example.org\r\n"+
+			"Content-Type: multipart/mixed; boundary=B\r\n"+
+			"\r\n"+
+			"--B\r\n"+
+			"Content-Type: text/html\r\n"+
+			"\r\n"+
+			"<p>Hi <b>there</b></p>\r\n"+
+			"--B\r\n"+
+			"Content-Type: text/csv; name=invoice.csv\r\n"+
+			"\r\n"+
+			"a,b\r\n"+
+			"--B--\r\n"))
+
+	require.NoError(t, err)
+	emails.AssertExpectations(t)
+}
+
+func TestService_Submit_Sender(t *testing.T) {
+	tests := []struct {
+		name     string
+		mailFrom string
+		from     string
+		wantErr  error
+	}{
+		{name: "from header of the user", mailFrom: "bounces@example.com", from: "From: App <App@example.com>\r\n"},
+		{name: "envelope sender of the user", mailFrom: "app@example.com"},
+		{name: "from header of someone else", mailFrom: "app@example.com", from: "From: ceo@example.com\r\n", wantErr: ErrSenderNotAllowed},
+		{name: "one of several from addresses", mailFrom: "app@example.com", from: "From: app@example.com, ceo@example.com\r\n", wantErr: ErrSenderNotAllowed},
+		{name: "envelope sender of someone else", mailFrom: "ceo@example.com", wantErr: ErrSenderNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emails := &emailMocks.Email{}
+			emails.On("Send", mock.Anything, mock.AnythingOfType("email.SendEmailRequest")).Return(&email.SendEmailResponse{Success: true}, nil).Maybe()
+
+			s := New(emails, &authMocks.Service{}, &config.Config{})
+			_, err := s.Submit(context.Background(), SubmitRequest{
+				UserID:     "user-1",
+				Username:   "app@example.com",
+				MailFrom:   tt.mailFrom,
+				Recipients: []string{"ada@example.org"},
+			}, strings.NewReader(tt.from+"To: ada@example.org\r\nSubject: Hi\r\n\r\nHello\r\n"))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				emails.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			emails.AssertNumberOfCalls(t, "Send", 1)
+		})
+	}
+}
+
+func TestMessageHeaders(t *testing.T) {
+	msg, err := mailparse.Parse(strings.NewReader("From: app@example.com\r\n" +
+		"To: Ada Lovelace <ada@example.org>, bob@example.org\r\n" +
+		"Cc: cy@example.org\r\n" +
+		"Bcc: audit@example.com\r\n" +
+		"Reply-To: Support <support@example.com>\r\n" +
+		"In-Reply-To: <1@example.org>\r\n" +
+		"References: <0@example.org> <1@example.org>\r\n" +
+		"Subject: Re: Your invoice\r\n" +
+		"\r\n" +
+		"Hello\r\n"))
+	require.NoError(t, err)
+
+	to, headers := messageHeaders(msg)
+
+	assert.Equal(t, `"Ada Lovelace" <ada@example.org>, <bob@example.org>`, to)
+	assert.Equal(t, []libSmtp.Header{
+		{Name: "Cc", Value: "<cy@example.org>"},
+		{Name: "Reply-To", Value: `"Support" <support@example.com>`},
+		{Name: "In-Reply-To", Value: "<1@example.org>"},
+		{Name: "References", Value: "<0@example.org> <1@example.org>"},
+	}, headers)
+
+	// Messages with blind copy recipients only don't show them
+	msg, err = mailparse.Parse(strings.NewReader("From: app@example.com\r\nSubject: Hi\r\n\r\nHello\r\n"))
+	require.NoError(t, err)
+	to, headers = messageHeaders(msg)
+	assert.Equal(t, "undisclosed-recipients:;", to)
+	assert.Empty(t, headers)
+}
+
+func TestService_Submit_Invalid(t *testing.T) {
+	s := New(&emailMocks.Email{}, &authMocks.Service{}, &config.Config{})
+
+	_, err := s.Submit(context.Background(), SubmitRequest{}, strings.NewReader("not a message"))
+
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestListener(t *testing.T) {
+	authService := &authMocks.Service{}
+	authService.On("VerifyToken", "issued").Return(&auth.Claims{UserID: "user-1", Email: "app@example.com"}, nil)
+	authService.On("VerifyToken", mock.Anything).Return(nil, errors.New("malformed"))
+	authService.On("Login", mock.Anything, "app@example.com", "wrong").Return("", auth.ErrInvalidCredentials)
+	authService.On("Login", mock.Anything, "app@example.com", "secret").Return("issued", nil)
+
+	emails := &emailMocks.Email{}
+	emails.On("Send", mock.Anything, mock.MatchedBy(func(req email.SendEmailRequest) bool {
+		return req.UserID == "user-1" && req.To == "ada@example.org"
+	})).Return(&email.SendEmailResponse{Success: true}, nil).Once()
+
+	cfg := &config.Config{Submission: config.SubmissionConfig{AllowInsecureAuth: true}}
+	listener, err := NewListener(cfg, New(emails, authService, cfg))
+	require.NoError(t, err)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go listener.server.Serve(l)
+	defer listener.Stop(context.Background())
+
+	err = smtp.SendMail(l.Addr().String(), smtp.PlainAuth("", "app@example.com", "wrong", "127.0.0.1"), "app@example.com", []string{"ada@example.org"}, []byte(message))
+	assert.ErrorContains(t, err, "Authentication credentials invalid")
+
+	err = smtp.SendMail(l.Addr().String(), nil, "app@example.com", []string{"ada@example.org"}, []byte(message))
+	assert.ErrorContains(t, err, "Authentication required")
+
+	err = smtp.SendMail(l.Addr().String(), smtp.PlainAuth("", "app@example.com", "secret", "127.0.0.1"), "app@example.com", []string{"ada@example.org"}, []byte(message))
+	require.NoError(t, err)
+	emails.AssertExpectations(t)
+
+	forged := strings.Replace(message, "From: App <app@example.com>", "From: ceo@example.com", 1)
+	err = smtp.SendMail(l.Addr().String(), smtp.PlainAuth("", "app@example.com", "secret", "127.0.0.1"), "app@example.com", []string{"ada@example.org"}, []byte(forged))
+	assert.ErrorContains(t, err, "Sender address not allowed")
+}
+
+func TestListener_Auth_Throttled(t *testing.T) {
+	authService := &authMocks.Service{}
+	authService.On("VerifyToken", "issued").Return(&auth.Claims{UserID: "user-2", Email: "other@example.com"}, nil)
+	authService.On("VerifyToken", mock.Anything).Return(nil, errors.New("malformed"))
+	authService.On("Login", mock.Anything, mock.Anything, "wrong").Return("", auth.ErrInvalidCredentials)
+	authService.On("Login", mock.Anything, "other@example.com", "secret").Return("issued", nil)
+
+	cfg := &config.Config{Submission: config.SubmissionConfig{AllowInsecureAuth: true}}
+	listener, err := NewListener(cfg, New(&emailMocks.Email{}, authService, cfg))
+	require.NoError(t, err)
+
+	session := func(ip string) *smtpd.Session {
+		return &smtpd.Session{RemoteAddr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}}
+	}
+
+	// Five failures from one address are answered as invalid credentials
+	for i := 0; i < 5; i++ {
+		_, err := listener.Auth(context.Background(), session("192.0.2.1"), "app@example.com", "wrong")
+		assert.ErrorIs(t, err, smtpd.ErrAuthFailed)
+	}
+
+	// Then the address is turned away, even with the right password
+	_, err = listener.Auth(context.Background(), session("192.0.2.1"), "other@example.com", "secret")
+	assert.ErrorIs(t, err, errTooManyFailures)
+
+	// And so is the username from any other address
+	_, err = listener.Auth(context.Background(), session("198.51.100.7"), "APP@example.com", "wrong")
+	assert.ErrorIs(t, err, errTooManyFailures)
+
+	// Other users on other addresses are unaffected
+	userID, err := listener.Auth(context.Background(), session("198.51.100.7"), "other@example.com", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "user-2", userID)
+}
+
+func TestNewListener_RequiresTLS(t *testing.T) {
+	cfg := &config.Config{}
+
+	_, err := NewListener(cfg, New(&emailMocks.Email{}, &authMocks.Service{}, cfg))
+
+	assert.Error(t, err)
+}
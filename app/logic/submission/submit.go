@@ -0,0 +1,161 @@
+package submission
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"strings"
+
+	"GoMail/app/libs/htmlmail"
+	"GoMail/app/libs/mailparse"
+	libSmtp "GoMail/app/libs/smtp"
+	"GoMail/app/logic/email"
+)
+
+// Submit sends a submitted email from the address of its user. Recipients
+// of the envelope that aren't in its To or Cc headers are blind copies and
+// get an email of their own. Every email keeps the To, Cc and reply headers
+// of the submitted one, so no one sees the blind copy recipients.
+func (s *service) Submit(ctx context.Context, req SubmitRequest, r io.Reader) (*SubmitResponse, error) {
+	msg, err := mailparse.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	from := req.MailFrom
+	if len(msg.From) > 0 {
+		from = msg.From[0].Address
+	}
+	if err := checkSender(req.Username, req.MailFrom, msg.From); err != nil {
+		return nil, err
+	}
+	to, headers := messageHeaders(msg)
+	ctx = libSmtp.WithHeaders(libSmtp.WithToHeader(ctx, to), headers...)
+
+	visible := make(map[string]bool)
+	for _, addr := range append(msg.To, msg.Cc...) {
+		visible[strings.ToLower(addr.Address)] = true
+	}
+	var shown []string
+	var groups [][]string
+	for _, to := range req.Recipients {
+		if visible[strings.ToLower(to)] {
+			shown = append(shown, to)
+			continue
+		}
+		groups = append(groups, []string{to})
+	}
+	if len(shown) > 0 {
+		groups = append([][]string{shown}, groups...)
+	}
+
+	resp := &SubmitResponse{}
+	for _, group := range groups {
+		sent, err := s.send(ctx, req.UserID, from, strings.Join(group, ","), msg)
+		if err != nil {
+			return nil, err
+		}
+		resp.Sends = append(resp.Sends, sent)
+	}
+
+	log.Printf("SMTP submission from %s by user %s to %d recipients", req.RemoteAddr, req.UserID, len(req.Recipients))
+	return resp, nil
+}
+
+// checkSender checks that an email is sent from the address a user
+// authenticated with: every address of its From header, or the envelope
+// sender when it has none
+func checkSender(username, mailFrom string, from []mailparse.Address) error {
+	senders := []string{mailFrom}
+	if len(from) > 0 {
+		senders = senders[:0]
+		for _, addr := range from {
+			senders = append(senders, addr.Address)
+		}
+	}
+	for _, sender := range senders {
+		if !strings.EqualFold(sender, username) {
+			return fmt.Errorf("%w: %s", ErrSenderNotAllowed, sender)
+		}
+	}
+	return nil
+}
+
+// messageHeaders returns the To header of a submitted email and its Cc and
+// reply headers, which its sends carry whichever of its recipients they are
+// delivered to
+func messageHeaders(msg *mailparse.Message) (string, []libSmtp.Header) {
+	to := formatAddresses(msg.To)
+	if to == "" {
+		to = "undisclosed-recipients:;"
+	}
+
+	var headers []libSmtp.Header
+	if len(msg.Cc) > 0 {
+		headers = append(headers, libSmtp.Header{Name: "Cc", Value: formatAddresses(msg.Cc)})
+	}
+	if len(msg.ReplyTo) > 0 {
+		headers = append(headers, libSmtp.Header{Name: "Reply-To", Value: formatAddresses(msg.ReplyTo)})
+	}
+	if msg.InReplyTo != "" {
+		headers = append(headers, libSmtp.Header{Name: "In-Reply-To", Value: "<" + msg.InReplyTo + ">"})
+	}
+	if len(msg.References) > 0 {
+		headers = append(headers, libSmtp.Header{Name: "References", Value: "<" + strings.Join(msg.References, "> <") + ">"})
+	}
+	return to, headers
+}
+
+// formatAddresses formats addresses for an address list header
+func formatAddresses(addrs []mailparse.Address) string {
+	formatted := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		formatted = append(formatted, (&mail.Address{Name: addr.Name, Address: addr.Address}).String())
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// send sends a parsed email with the email service method for its parts
+func (s *service) send(ctx context.Context, userID, from, to string, msg *mailparse.Message) (*email.SendEmailResponse, error) {
+	if len(msg.Attachments) == 0 {
+		req := email.SendEmailRequest{
+			UserID:  userID,
+			From:    from,
+			To:      to,
+			Subject: msg.Subject,
+			Body:    msg.Text,
+		}
+		if msg.HTML != "" {
+			req.Body = msg.HTML
+			return s.emails.SendHTML(ctx, req)
+		}
+		return s.emails.Send(ctx, req)
+	}
+
+	// Emails with attachments are sent with a plain text body
+	body := msg.Text
+	if body == "" && msg.HTML != "" {
+		if doc, err := htmlmail.Parse(msg.HTML); err == nil {
+			body = doc.Text() + "\n"
+		}
+	}
+	attachments := make([]libSmtp.Attachment, 0, len(msg.Attachments))
+	for _, a := range msg.Attachments {
+		attachments = append(attachments, libSmtp.Attachment{
+			Filename:  a.Filename,
+			Content:   a.Content,
+			MimeType:  a.ContentType,
+			ContentID: a.ContentID,
+		})
+	}
+	return s.emails.SendWithAttachments(ctx, email.SendWithAttachmentsRequest{
+		UserID:      userID,
+		From:        from,
+		To:          to,
+		Subject:     msg.Subject,
+		Body:        body,
+		Attachments: attachments,
+	})
+}
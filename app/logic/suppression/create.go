@@ -0,0 +1,256 @@
+package suppression
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"GoMail/app/repository/models"
+	suppressionRepo "GoMail/app/repository/suppression"
+)
+
+// Create suppresses an address or domain
+func (s *service) Create(ctx context.Context, userID string, req SuppressionRequest) (*SuppressionResponse, error) {
+	suppression, err := s.build(req, userID, time.Now(), func() (bool, error) {
+		return s.isAdmin(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveSuppression(ctx, suppression); err != nil {
+		if errors.Is(err, suppressionRepo.ErrSuppressionExists) {
+			return nil, fmt.Errorf("%w: %s", ErrSuppressionExists, suppression.Value)
+		}
+		return nil, err
+	}
+
+	return toResponse(suppression), nil
+}
+
+// Import creates or updates many suppressions. Invalid entries are
+// reported with their row and skipped.
+func (s *service) Import(ctx context.Context, userID string, entries []SuppressionRequest) (*ImportResponse, error) {
+	rows := make([]importRow, len(entries))
+	for i, entry := range entries {
+		rows[i] = importRow{req: entry}
+	}
+	return s.importRows(ctx, userID, rows)
+}
+
+// ImportCSV imports suppressions from CSV. The header row names the
+// columns: address, domain or value, which holds either, and optionally
+// list, reason, scope, note and expiresAt as RFC 3339. Other columns are
+// ignored.
+func (s *service) ImportCSV(ctx context.Context, userID string, r io.Reader) (*ImportResponse, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV header: %v", ErrInvalidSuppression, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	_, hasAddress := columns["address"]
+	_, hasDomain := columns["domain"]
+	_, hasValue := columns["value"]
+	if !hasAddress && !hasDomain && !hasValue {
+		return nil, fmt.Errorf("%w: CSV needs an address, domain or value column", ErrInvalidSuppression)
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if len(rows) == MaxImportEntries {
+			return nil, fmt.Errorf("%w: more than %d entries", ErrInvalidSuppression, MaxImportEntries)
+		}
+		if err != nil {
+			// Malformed quoting leaves the rest of the file unreadable
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSuppression, err)
+		}
+		rows = append(rows, csvRow(record, columns))
+	}
+
+	return s.importRows(ctx, userID, rows)
+}
+
+// importRow is an entry of an import, or the error parsing it
+type importRow struct {
+	req SuppressionRequest
+	err error
+}
+
+// csvRow converts a CSV record into an import entry
+func csvRow(record []string, columns map[string]int) importRow {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	req := SuppressionRequest{
+		Address: field("address"),
+		Domain:  field("domain"),
+		List:    field("list"),
+		Reason:  models.SuppressionReason(field("reason")),
+		Scope:   models.SuppressionScope(field("scope")),
+		Note:    field("note"),
+	}
+	if value := field("value"); value != "" {
+		if strings.Contains(value, "@") && !strings.HasPrefix(value, "@") {
+			req.Address = value
+		} else {
+			req.Domain = value
+		}
+	}
+
+	expiresAt := field("expiresat")
+	if expiresAt == "" {
+		expiresAt = field("expires_at")
+	}
+	if expiresAt != "" {
+		t, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return importRow{req: req, err: fmt.Errorf("%w: invalid expiresAt %q", ErrInvalidSuppression, expiresAt)}
+		}
+		req.ExpiresAt = &t
+	}
+
+	return importRow{req: req}
+}
+
+// importRows upserts the valid rows of an import
+func (s *service) importRows(ctx context.Context, userID string, rows []importRow) (*ImportResponse, error) {
+	if len(rows) > MaxImportEntries {
+		return nil, fmt.Errorf("%w: more than %d entries", ErrInvalidSuppression, MaxImportEntries)
+	}
+
+	// Whether the user is an admin is looked up once, when needed
+	var admin *bool
+	isAdmin := func() (bool, error) {
+		if admin == nil {
+			allowed, err := s.isAdmin(ctx, userID)
+			if err != nil {
+				return false, err
+			}
+			admin = &allowed
+		}
+		return *admin, nil
+	}
+
+	now := time.Now()
+	resp := &ImportResponse{Errors: []ImportError{}}
+	for i, row := range rows {
+		err := row.err
+		if err == nil {
+			var suppression *models.Suppression
+			if suppression, err = s.build(row.req, userID, now, isAdmin); err == nil {
+				var created bool
+				if created, err = s.repo.UpsertSuppression(ctx, suppression); err != nil {
+					return nil, err
+				}
+				if created {
+					resp.Created++
+				} else {
+					resp.Updated++
+				}
+				continue
+			}
+		}
+		if !errors.Is(err, ErrInvalidSuppression) && !errors.Is(err, ErrForbidden) {
+			return nil, err
+		}
+
+		value := row.req.Address
+		if value == "" {
+			value = row.req.Domain
+		}
+		resp.Errors = append(resp.Errors, ImportError{Row: i + 1, Value: value, Error: err.Error()})
+	}
+
+	return resp, nil
+}
+
+// build validates a request and converts it into a suppression. isAdmin
+// is only called for global suppressions.
+func (s *service) build(req SuppressionRequest, userID string, now time.Time, isAdmin func() (bool, error)) (*models.Suppression, error) {
+	suppression := &models.Suppression{
+		UserID:    userID,
+		List:      strings.TrimSpace(req.List),
+		Scope:     req.Scope,
+		Reason:    req.Reason,
+		Note:      req.Note,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	var err error
+	switch {
+	case req.Address != "" && req.Domain != "":
+		return nil, fmt.Errorf("%w: set either address or domain", ErrInvalidSuppression)
+	case req.Address != "":
+		suppression.Type = models.SuppressionTypeAddress
+		suppression.Value, err = NormalizeAddress(req.Address)
+	case req.Domain != "":
+		suppression.Type = models.SuppressionTypeDomain
+		suppression.Value, err = normalizeDomain(req.Domain)
+	default:
+		return nil, fmt.Errorf("%w: address or domain required", ErrInvalidSuppression)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if suppression.Reason == "" {
+		suppression.Reason = models.SuppressionReasonManual
+	}
+	if !validReason(suppression.Reason) {
+		return nil, fmt.Errorf("%w: unknown reason %q", ErrInvalidSuppression, suppression.Reason)
+	}
+	if suppression.ExpiresAt != nil && !suppression.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expiresAt is in the past", ErrInvalidSuppression)
+	}
+
+	switch suppression.Scope {
+	case "", models.SuppressionScopeUser:
+		suppression.Scope = models.SuppressionScopeUser
+	case models.SuppressionScopeGlobal:
+		allowed, err := isAdmin()
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, ErrForbidden
+		}
+		suppression.UserID = ""
+	default:
+		return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidSuppression, suppression.Scope)
+	}
+
+	return suppression, nil
+}
+
+// validReason reports whether a reason is known
+func validReason(reason models.SuppressionReason) bool {
+	switch reason {
+	case models.SuppressionReasonHardBounce, models.SuppressionReasonComplaint,
+		models.SuppressionReasonUnsubscribe, models.SuppressionReasonManual:
+		return true
+	}
+	return false
+}
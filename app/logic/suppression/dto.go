@@ -0,0 +1,78 @@
+package suppression
+
+import (
+	"time"
+
+	"GoMail/app/repository/models"
+)
+
+// SuppressionRequest represents a request to suppress an address or a
+// domain. Exactly one of Address and Domain is set. Reason defaults to
+// manual and Scope to user. A List limits the suppression to the emails
+// sent to that list.
+type SuppressionRequest struct {
+	Address   string                   `json:"address,omitempty"`
+	Domain    string                   `json:"domain,omitempty"`
+	List      string                   `json:"list,omitempty"`
+	Reason    models.SuppressionReason `json:"reason,omitempty"`
+	Scope     models.SuppressionScope  `json:"scope,omitempty"`
+	Note      string                   `json:"note,omitempty"`
+	ExpiresAt *time.Time               `json:"expiresAt,omitempty"`
+}
+
+// UpdateRequest represents a request to change a suppression. A nil
+// ExpiresAt makes it permanent.
+type UpdateRequest struct {
+	Reason    models.SuppressionReason `json:"reason"`
+	Note      string                   `json:"note,omitempty"`
+	ExpiresAt *time.Time               `json:"expiresAt,omitempty"`
+}
+
+// ListFilter narrows a list of suppressions. Scope defaults to user.
+type ListFilter struct {
+	Scope  models.SuppressionScope
+	Reason models.SuppressionReason
+	Value  string
+}
+
+// SuppressionResponse represents a suppression
+type SuppressionResponse struct {
+	ID        string                   `json:"id"`
+	Type      models.SuppressionType   `json:"type"`
+	Value     string                   `json:"value"`
+	List      string                   `json:"list,omitempty"`
+	Reason    models.SuppressionReason `json:"reason"`
+	Scope     models.SuppressionScope  `json:"scope"`
+	Note      string                   `json:"note,omitempty"`
+	ExpiresAt *time.Time               `json:"expiresAt,omitempty"`
+	CreatedAt time.Time                `json:"createdAt"`
+	UpdatedAt time.Time                `json:"updatedAt"`
+}
+
+// ListSuppressionsResponse represents a page of suppressions
+type ListSuppressionsResponse struct {
+	Suppressions []SuppressionResponse `json:"suppressions"`
+	Total        int64                 `json:"total"`
+	Page         int                   `json:"page"`
+	Limit        int                   `json:"limit"`
+}
+
+// ImportRequest represents a bulk import of suppressions
+type ImportRequest struct {
+	Entries []SuppressionRequest `json:"entries"`
+}
+
+// ImportResponse reports the outcome of a bulk import
+type ImportResponse struct {
+	Created int           `json:"created"`
+	Updated int           `json:"updated"`
+	Errors  []ImportError `json:"errors"`
+}
+
+// ImportError reports an entry that wasn't imported. Row counts entries
+// from 1, not counting the header row of CSV imports.
+type ImportError struct {
+	Row   int    `json:"row"`
+	Value string `json:"value,omitempty"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,118 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"GoMail/app/repository/models"
+	suppressionRepo "GoMail/app/repository/suppression"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Get returns a suppression
+func (s *service) Get(ctx context.Context, userID, id string) (*SuppressionResponse, error) {
+	suppression, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponse(suppression), nil
+}
+
+// List returns the suppressions of a scope, newest first. Only admins
+// list global suppressions.
+func (s *service) List(ctx context.Context, userID string, filter ListFilter, page, limit int) (*ListSuppressionsResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	query := bson.M{"user_id": userID}
+	switch filter.Scope {
+	case "", models.SuppressionScopeUser:
+	case models.SuppressionScopeGlobal:
+		admin, err := s.isAdmin(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !admin {
+			return nil, ErrForbidden
+		}
+		query["user_id"] = ""
+	default:
+		return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidSuppression, filter.Scope)
+	}
+	if filter.Reason != "" {
+		query["reason"] = filter.Reason
+	}
+	if filter.Value != "" {
+		query["value"] = strings.ToLower(strings.TrimSpace(filter.Value))
+	}
+
+	suppressions, total, err := s.repo.FindSuppressions(ctx, query, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListSuppressionsResponse{
+		Suppressions: make([]SuppressionResponse, 0, len(suppressions)),
+		Total:        total,
+		Page:         page,
+		Limit:        limit,
+	}
+	for _, suppression := range suppressions {
+		resp.Suppressions = append(resp.Suppressions, *toResponse(suppression))
+	}
+
+	return resp, nil
+}
+
+// Check returns the suppression that stops emails of a user to an
+// address: one of the address, its domain or a parent domain, either
+// global or the user's own, for all emails or those of the list
+func (s *service) Check(ctx context.Context, userID, address, list string) (*models.Suppression, error) {
+	normalized, err := NormalizeAddress(address)
+	if err != nil {
+		normalized = strings.ToLower(strings.TrimSpace(address))
+	}
+
+	suppression, err := s.repo.FindActiveSuppression(ctx, userID, list, candidates(normalized), time.Now())
+	if err != nil {
+		if errors.Is(err, suppressionRepo.ErrSuppressionNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return suppression, nil
+}
+
+// find retrieves a suppression visible to a user
+func (s *service) find(ctx context.Context, userID, id string) (*models.Suppression, error) {
+	suppression, err := s.repo.FindSuppressionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, suppressionRepo.ErrSuppressionNotFound) || errors.Is(err, suppressionRepo.ErrInvalidID) {
+			return nil, ErrSuppressionNotFound
+		}
+		return nil, err
+	}
+
+	visible, err := s.visible(ctx, userID, suppression)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrSuppressionNotFound
+	}
+
+	return suppression, nil
+}
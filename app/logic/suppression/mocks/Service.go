@@ -0,0 +1,261 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "GoMail/app/repository/models"
+
+	suppression "GoMail/app/logic/suppression"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: ctx, userID, address, list
+func (_m *Service) Check(ctx context.Context, userID string, address string, list string) (*models.Suppression, error) {
+	ret := _m.Called(ctx, userID, address, list)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 *models.Suppression
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*models.Suppression, error)); ok {
+		return rf(ctx, userID, address, list)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *models.Suppression); ok {
+		r0 = rf(ctx, userID, address, list)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Suppression)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, address, list)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, userID, req
+func (_m *Service) Create(ctx context.Context, userID string, req suppression.SuppressionRequest) (*suppression.SuppressionResponse, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *suppression.SuppressionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, suppression.SuppressionRequest) (*suppression.SuppressionResponse, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, suppression.SuppressionRequest) *suppression.SuppressionResponse); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*suppression.SuppressionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, suppression.SuppressionRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Delete(ctx context.Context, userID string, id string) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Get(ctx context.Context, userID string, id string) (*suppression.SuppressionResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *suppression.SuppressionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*suppression.SuppressionResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *suppression.SuppressionResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*suppression.SuppressionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, userID, entries
+func (_m *Service) Import(ctx context.Context, userID string, entries []suppression.SuppressionRequest) (*suppression.ImportResponse, error) {
+	ret := _m.Called(ctx, userID, entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 *suppression.ImportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []suppression.SuppressionRequest) (*suppression.ImportResponse, error)); ok {
+		return rf(ctx, userID, entries)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []suppression.SuppressionRequest) *suppression.ImportResponse); ok {
+		r0 = rf(ctx, userID, entries)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*suppression.ImportResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []suppression.SuppressionRequest) error); ok {
+		r1 = rf(ctx, userID, entries)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportCSV provides a mock function with given fields: ctx, userID, r
+func (_m *Service) ImportCSV(ctx context.Context, userID string, r io.Reader) (*suppression.ImportResponse, error) {
+	ret := _m.Called(ctx, userID, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportCSV")
+	}
+
+	var r0 *suppression.ImportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) (*suppression.ImportResponse, error)); ok {
+		return rf(ctx, userID, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) *suppression.ImportResponse); ok {
+		r0 = rf(ctx, userID, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*suppression.ImportResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader) error); ok {
+		r1 = rf(ctx, userID, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID, filter, page, limit
+func (_m *Service) List(ctx context.Context, userID string, filter suppression.ListFilter, page int, limit int) (*suppression.ListSuppressionsResponse, error) {
+	ret := _m.Called(ctx, userID, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *suppression.ListSuppressionsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, suppression.ListFilter, int, int) (*suppression.ListSuppressionsResponse, error)); ok {
+		return rf(ctx, userID, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, suppression.ListFilter, int, int) *suppression.ListSuppressionsResponse); ok {
+		r0 = rf(ctx, userID, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*suppression.ListSuppressionsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, suppression.ListFilter, int, int) error); ok {
+		r1 = rf(ctx, userID, filter, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, userID, id, req
+func (_m *Service) Update(ctx context.Context, userID string, id string, req suppression.UpdateRequest) (*suppression.SuppressionResponse, error) {
+	ret := _m.Called(ctx, userID, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *suppression.SuppressionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, suppression.UpdateRequest) (*suppression.SuppressionResponse, error)); ok {
+		return rf(ctx, userID, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, suppression.UpdateRequest) *suppression.SuppressionResponse); ok {
+		r0 = rf(ctx, userID, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*suppression.SuppressionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, suppression.UpdateRequest) error); ok {
+		r1 = rf(ctx, userID, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,171 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"GoMail/app/config"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+)
+
+var (
+	ErrInvalidSuppression  = errors.New("invalid suppression")
+	ErrSuppressionNotFound = errors.New("suppression not found")
+	ErrSuppressionExists   = errors.New("suppression already exists")
+	ErrForbidden           = errors.New("global suppressions can only be managed by admins")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// MaxImportEntries is the most suppressions imported in one request
+	MaxImportEntries = 10000
+)
+
+// Service defines the interface for the suppression list. Suppressions
+// stop emails to an address or to all addresses of a domain, either for
+// the user that owns them or, when global, for all users.
+type Service interface {
+	// Create suppresses an address or domain
+	Create(ctx context.Context, userID string, req SuppressionRequest) (*SuppressionResponse, error)
+
+	// Get returns a suppression
+	Get(ctx context.Context, userID, id string) (*SuppressionResponse, error)
+
+	// List returns the suppressions of a scope, newest first
+	List(ctx context.Context, userID string, filter ListFilter, page, limit int) (*ListSuppressionsResponse, error)
+
+	// Update changes the reason, note and expiry of a suppression
+	Update(ctx context.Context, userID, id string, req UpdateRequest) (*SuppressionResponse, error)
+
+	// Delete lifts a suppression
+	Delete(ctx context.Context, userID, id string) error
+
+	// Import creates or updates many suppressions, reporting invalid
+	// entries without stopping
+	Import(ctx context.Context, userID string, entries []SuppressionRequest) (*ImportResponse, error)
+
+	// ImportCSV imports suppressions from CSV with a header row
+	ImportCSV(ctx context.Context, userID string, r io.Reader) (*ImportResponse, error)
+
+	// Check returns the suppression that stops emails of a user to an
+	// address, or nil when the address isn't suppressed. Emails sent to a
+	// list are also stopped by the suppressions of that list.
+	Check(ctx context.Context, userID, address, list string) (*models.Suppression, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	config *config.Config
+}
+
+// New creates a new suppression service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		config: cfg,
+	}
+}
+
+// isAdmin reports whether a user may manage global suppressions
+func (s *service) isAdmin(ctx context.Context, userID string) (bool, error) {
+	if len(s.config.Suppression.Admins) == 0 {
+		return false, nil
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, admin := range s.config.Suppression.Admins {
+		if strings.EqualFold(admin, user.Email) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// visible reports whether a user may see and change a suppression. Global
+// suppressions are only visible to admins.
+func (s *service) visible(ctx context.Context, userID string, suppression *models.Suppression) (bool, error) {
+	if suppression.Scope == models.SuppressionScopeGlobal {
+		return s.isAdmin(ctx, userID)
+	}
+	return suppression.UserID == userID, nil
+}
+
+// NormalizeAddress returns the lower-case address of an email address,
+// which may have a display name
+func NormalizeAddress(address string) (string, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(address))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSuppression, err)
+	}
+	return strings.ToLower(parsed.Address), nil
+}
+
+// normalizeDomain returns the lower-case form of a domain name, which may
+// be given with a leading @ or a trailing dot
+func normalizeDomain(domain string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(domain))
+	normalized = strings.TrimSuffix(strings.TrimPrefix(normalized, "@"), ".")
+	if !validDomain(normalized) {
+		return "", fmt.Errorf("%w: invalid domain %q", ErrInvalidSuppression, domain)
+	}
+	return normalized, nil
+}
+
+// validDomain reports whether a lower-case name has at least two labels of
+// letters, digits and inner hyphens
+func validDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 || len(domain) > 253 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// candidates returns the values that suppress a normalised address: the
+// address, its domain and the parent domains of its domain
+func candidates(address string) []string {
+	values := []string{address}
+	domain := address[strings.LastIndex(address, "@")+1:]
+	for strings.Contains(domain, ".") {
+		values = append(values, domain)
+		domain = domain[strings.Index(domain, ".")+1:]
+	}
+	return values
+}
+
+// toResponse converts a suppression into its API representation
+func toResponse(suppression *models.Suppression) *SuppressionResponse {
+	return &SuppressionResponse{
+		ID:        suppression.ID.Hex(),
+		Type:      suppression.Type,
+		Value:     suppression.Value,
+		List:      suppression.List,
+		Reason:    suppression.Reason,
+		Scope:     suppression.Scope,
+		Note:      suppression.Note,
+		ExpiresAt: suppression.ExpiresAt,
+		CreatedAt: suppression.CreatedAt,
+		UpdatedAt: suppression.UpdatedAt,
+	}
+}
@@ -0,0 +1,174 @@
+package suppression
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+	suppressionRepo "GoMail/app/repository/suppression"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newService(repo *repoMocks.Repository) *service {
+	return New(repo, &config.Config{
+		Suppression: config.SuppressionConfig{Admins: []string{"admin@example.com"}},
+	}).(*service)
+}
+
+func TestService_Create(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveSuppression", mock.Anything, mock.MatchedBy(func(s *models.Suppression) bool {
+		return s.UserID == "user-1" && s.Scope == models.SuppressionScopeUser && s.Type == models.SuppressionTypeAddress &&
+			s.Value == "ana@example.com" && s.Reason == models.SuppressionReasonManual
+	})).Return(nil)
+
+	got, err := newService(repo).Create(context.Background(), "user-1", SuppressionRequest{Address: "Ana <Ana@Example.com>"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", got.Value)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Create_Errors(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindUserByID", mock.Anything, "user-1").Return(&models.User{Email: "user@example.com"}, nil)
+	repo.On("SaveSuppression", mock.Anything, mock.Anything).Return(suppressionRepo.ErrSuppressionExists)
+
+	tests := []struct {
+		name    string
+		req     SuppressionRequest
+		wantErr error
+	}{
+		{name: "nothing to suppress", req: SuppressionRequest{}, wantErr: ErrInvalidSuppression},
+		{name: "address and domain", req: SuppressionRequest{Address: "ana@example.com", Domain: "example.com"}, wantErr: ErrInvalidSuppression},
+		{name: "invalid address", req: SuppressionRequest{Address: "ana"}, wantErr: ErrInvalidSuppression},
+		{name: "invalid domain", req: SuppressionRequest{Domain: "localhost"}, wantErr: ErrInvalidSuppression},
+		{name: "unknown reason", req: SuppressionRequest{Domain: "example.com", Reason: "spite"}, wantErr: ErrInvalidSuppression},
+		{name: "expired", req: SuppressionRequest{Domain: "example.com", ExpiresAt: &past}, wantErr: ErrInvalidSuppression},
+		{name: "global without admin", req: SuppressionRequest{Domain: "example.com", Scope: models.SuppressionScopeGlobal}, wantErr: ErrForbidden},
+		{name: "already suppressed", req: SuppressionRequest{Domain: "@Example.com."}, wantErr: ErrSuppressionExists},
+	}
+
+	s := newService(repo)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Create(context.Background(), "user-1", tt.req)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_ImportCSV(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindUserByID", mock.Anything, "admin-1").Return(&models.User{Email: "Admin@example.com"}, nil).Once()
+	repo.On("UpsertSuppression", mock.Anything, mock.MatchedBy(func(s *models.Suppression) bool {
+		return s.Value == "ana@example.com" && s.Reason == models.SuppressionReasonHardBounce
+	})).Return(true, nil)
+	repo.On("UpsertSuppression", mock.Anything, mock.MatchedBy(func(s *models.Suppression) bool {
+		return s.Value == "example.org" && s.Type == models.SuppressionTypeDomain && s.Scope == models.SuppressionScopeGlobal && s.UserID == ""
+	})).Return(false, nil)
+	repo.On("UpsertSuppression", mock.Anything, mock.MatchedBy(func(s *models.Suppression) bool {
+		return s.Value == "bo@example.com" && s.Scope == models.SuppressionScopeGlobal
+	})).Return(true, nil)
+
+	csv := strings.Join([]string{
+		"Value,Reason,Scope,Comment",
+		"ana@example.com,hard_bounce,,",
+		"example.org,,global,",
+		"not an address,,,",
+		"bo@example.com,complaint,global,",
+		"bo@example.com,spite,,",
+	}, "\n")
+
+	got, err := newService(repo).ImportCSV(context.Background(), "admin-1", strings.NewReader(csv))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Created)
+	assert.Equal(t, 1, got.Updated)
+	require.Len(t, got.Errors, 2)
+	assert.Equal(t, 3, got.Errors[0].Row)
+	assert.Equal(t, "not an address", got.Errors[0].Value)
+	assert.Equal(t, 5, got.Errors[1].Row)
+	repo.AssertExpectations(t)
+}
+
+func TestService_ImportCSV_Errors(t *testing.T) {
+	s := newService(&repoMocks.Repository{})
+
+	for name, csv := range map[string]string{
+		"empty":             "",
+		"no value column":   "reason,note\nmanual,x\n",
+		"malformed quoting": "address\n\"ana@example.com\n",
+		"too many entries":  "domain\n" + strings.Repeat("example.com\n", MaxImportEntries+1),
+	} {
+		_, err := s.ImportCSV(context.Background(), "user-1", strings.NewReader(csv))
+
+		assert.ErrorIs(t, err, ErrInvalidSuppression, name)
+	}
+}
+
+func TestService_Check(t *testing.T) {
+	suppression := &models.Suppression{Value: "example.com", Reason: models.SuppressionReasonManual}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindActiveSuppression", mock.Anything, "user-1", "", []string{"ana@mail.example.com", "mail.example.com", "example.com"}, mock.AnythingOfType("time.Time")).
+		Return(suppression, nil)
+	repo.On("FindActiveSuppression", mock.Anything, "user-1", "news", []string{"bo@example.org", "example.org"}, mock.AnythingOfType("time.Time")).
+		Return(nil, suppressionRepo.ErrSuppressionNotFound)
+
+	s := newService(repo)
+
+	got, err := s.Check(context.Background(), "user-1", "Ana <ANA@mail.example.com>", "")
+	require.NoError(t, err)
+	assert.Equal(t, suppression, got)
+
+	got, err = s.Check(context.Background(), "user-1", "bo@example.org", "news")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestService_Get_Visibility(t *testing.T) {
+	own := &models.Suppression{ID: primitive.NewObjectID(), UserID: "user-1", Scope: models.SuppressionScopeUser}
+	global := &models.Suppression{ID: primitive.NewObjectID(), Scope: models.SuppressionScopeGlobal}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindSuppressionByID", mock.Anything, own.ID.Hex()).Return(own, nil)
+	repo.On("FindSuppressionByID", mock.Anything, global.ID.Hex()).Return(global, nil)
+	repo.On("FindSuppressionByID", mock.Anything, "nope").Return(nil, suppressionRepo.ErrInvalidID)
+	repo.On("FindUserByID", mock.Anything, "user-1").Return(&models.User{Email: "user@example.com"}, nil)
+	repo.On("FindUserByID", mock.Anything, "admin-1").Return(&models.User{Email: "admin@example.com"}, nil)
+
+	s := newService(repo)
+
+	_, err := s.Get(context.Background(), "user-1", own.ID.Hex())
+	assert.NoError(t, err)
+	_, err = s.Get(context.Background(), "user-2", own.ID.Hex())
+	assert.ErrorIs(t, err, ErrSuppressionNotFound)
+	_, err = s.Get(context.Background(), "user-1", global.ID.Hex())
+	assert.ErrorIs(t, err, ErrSuppressionNotFound)
+	_, err = s.Get(context.Background(), "admin-1", global.ID.Hex())
+	assert.NoError(t, err)
+	_, err = s.Get(context.Background(), "user-1", "nope")
+	assert.ErrorIs(t, err, ErrSuppressionNotFound)
+}
+
+func TestService_List_Global(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindUserByID", mock.Anything, "user-1").Return(&models.User{Email: "user@example.com"}, nil)
+
+	_, err := newService(repo).List(context.Background(), "user-1", ListFilter{Scope: models.SuppressionScopeGlobal}, 1, 20)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "FindSuppressions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
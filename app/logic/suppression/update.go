@@ -0,0 +1,55 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	suppressionRepo "GoMail/app/repository/suppression"
+)
+
+// Update changes the reason, note and expiry of a suppression
+func (s *service) Update(ctx context.Context, userID, id string, req UpdateRequest) (*SuppressionResponse, error) {
+	suppression, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Reason != "" {
+		suppression.Reason = req.Reason
+	}
+	if !validReason(suppression.Reason) {
+		return nil, fmt.Errorf("%w: unknown reason %q", ErrInvalidSuppression, suppression.Reason)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiresAt is in the past", ErrInvalidSuppression)
+	}
+	suppression.Note = req.Note
+	suppression.ExpiresAt = req.ExpiresAt
+
+	if err := s.repo.UpdateSuppression(ctx, suppression); err != nil {
+		if errors.Is(err, suppressionRepo.ErrSuppressionNotFound) {
+			return nil, ErrSuppressionNotFound
+		}
+		return nil, err
+	}
+
+	return toResponse(suppression), nil
+}
+
+// Delete lifts a suppression
+func (s *service) Delete(ctx context.Context, userID, id string) error {
+	suppression, err := s.find(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteSuppression(ctx, suppression.ID); err != nil {
+		if errors.Is(err, suppressionRepo.ErrSuppressionNotFound) {
+			return ErrSuppressionNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package topic
+
+import "time"
+
+// TopicRequest represents a request to add a topic. Label is shown to
+// recipients and defaults to the name.
+type TopicRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Label         string `json:"label,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Transactional bool   `json:"transactional"`
+}
+
+// UpdateRequest represents a request to change a topic
+type UpdateRequest struct {
+	Label         string `json:"label,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Transactional bool   `json:"transactional"`
+}
+
+// TopicResponse represents a topic
+type TopicResponse struct {
+	Name          string    `json:"name"`
+	Label         string    `json:"label"`
+	Description   string    `json:"description,omitempty"`
+	Transactional bool      `json:"transactional"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ListTopicsResponse represents the topics of a user
+type ListTopicsResponse struct {
+	Topics []TopicResponse `json:"topics"`
+}
@@ -0,0 +1,131 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"GoMail/app/repository/models"
+	topicRepo "GoMail/app/repository/topic"
+)
+
+// maxLabelLength is the longest label and description of a topic
+const maxLabelLength = 200
+
+// Create adds a topic
+func (s *service) Create(ctx context.Context, userID string, req TopicRequest) (*TopicResponse, error) {
+	name, err := NormalizeName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	label, description, err := validateText(req.Label, req.Description)
+	if err != nil {
+		return nil, err
+	}
+	if label == "" {
+		label = name
+	}
+
+	existing, err := s.repo.FindUserTopics(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxTopics {
+		return nil, fmt.Errorf("%w: at most %d topics per user", ErrInvalidTopic, MaxTopics)
+	}
+
+	topic := &models.Topic{
+		UserID:        userID,
+		Name:          name,
+		Label:         label,
+		Description:   description,
+		Transactional: req.Transactional,
+	}
+	if err := s.repo.SaveTopic(ctx, topic); err != nil {
+		if errors.Is(err, topicRepo.ErrTopicExists) {
+			return nil, fmt.Errorf("%w: %s", ErrTopicExists, name)
+		}
+		return nil, err
+	}
+
+	return toResponse(topic), nil
+}
+
+// Get returns a topic
+func (s *service) Get(ctx context.Context, userID, name string) (*TopicResponse, error) {
+	topic, err := s.Find(ctx, userID, name)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(topic), nil
+}
+
+// List returns the topics of a user, sorted by name
+func (s *service) List(ctx context.Context, userID string) (*ListTopicsResponse, error) {
+	topics, err := s.repo.FindUserTopics(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListTopicsResponse{Topics: make([]TopicResponse, 0, len(topics))}
+	for _, topic := range topics {
+		resp.Topics = append(resp.Topics, *toResponse(topic))
+	}
+	return resp, nil
+}
+
+// Update changes the label, description and kind of a topic. Making it
+// transactional overrides the recipients that opted out of it.
+func (s *service) Update(ctx context.Context, userID, name string, req UpdateRequest) (*TopicResponse, error) {
+	topic, err := s.Find(ctx, userID, name)
+	if err != nil {
+		return nil, err
+	}
+	label, description, err := validateText(req.Label, req.Description)
+	if err != nil {
+		return nil, err
+	}
+	if label == "" {
+		label = topic.Name
+	}
+
+	topic.Label = label
+	topic.Description = description
+	topic.Transactional = req.Transactional
+	if err := s.repo.UpdateTopic(ctx, topic); err != nil {
+		if errors.Is(err, topicRepo.ErrTopicNotFound) {
+			return nil, ErrTopicNotFound
+		}
+		return nil, err
+	}
+
+	return toResponse(topic), nil
+}
+
+// Delete removes a topic. The opt-outs of recipients are kept, so they
+// apply again when a topic of the same name is added.
+func (s *service) Delete(ctx context.Context, userID, name string) error {
+	topic, err := s.Find(ctx, userID, name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteTopic(ctx, topic.ID); err != nil {
+		if errors.Is(err, topicRepo.ErrTopicNotFound) {
+			return ErrTopicNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// validateText trims the label and description of a topic and checks
+// their length
+func validateText(label, description string) (string, string, error) {
+	label, description = strings.TrimSpace(label), strings.TrimSpace(description)
+	if len(label) > maxLabelLength || len(description) > maxLabelLength {
+		return "", "", fmt.Errorf("%w: label and description are limited to %d characters", ErrInvalidTopic, maxLabelLength)
+	}
+	return label, description, nil
+}
@@ -0,0 +1,199 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "GoMail/app/repository/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	topic "GoMail/app/logic/topic"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, userID, req
+func (_m *Service) Create(ctx context.Context, userID string, req topic.TopicRequest) (*topic.TopicResponse, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *topic.TopicResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, topic.TopicRequest) (*topic.TopicResponse, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, topic.TopicRequest) *topic.TopicResponse); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*topic.TopicResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, topic.TopicRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userID, name
+func (_m *Service) Delete(ctx context.Context, userID string, name string) error {
+	ret := _m.Called(ctx, userID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: ctx, userID, name
+func (_m *Service) Find(ctx context.Context, userID string, name string) (*models.Topic, error) {
+	ret := _m.Called(ctx, userID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 *models.Topic
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.Topic, error)); ok {
+		return rf(ctx, userID, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.Topic); ok {
+		r0 = rf(ctx, userID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Topic)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, userID, name
+func (_m *Service) Get(ctx context.Context, userID string, name string) (*topic.TopicResponse, error) {
+	ret := _m.Called(ctx, userID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *topic.TopicResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*topic.TopicResponse, error)); ok {
+		return rf(ctx, userID, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *topic.TopicResponse); ok {
+		r0 = rf(ctx, userID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*topic.TopicResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID
+func (_m *Service) List(ctx context.Context, userID string) (*topic.ListTopicsResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *topic.ListTopicsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*topic.ListTopicsResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *topic.ListTopicsResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*topic.ListTopicsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, userID, name, req
+func (_m *Service) Update(ctx context.Context, userID string, name string, req topic.UpdateRequest) (*topic.TopicResponse, error) {
+	ret := _m.Called(ctx, userID, name, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *topic.TopicResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, topic.UpdateRequest) (*topic.TopicResponse, error)); ok {
+		return rf(ctx, userID, name, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, topic.UpdateRequest) *topic.TopicResponse); ok {
+		r0 = rf(ctx, userID, name, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*topic.TopicResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, topic.UpdateRequest) error); ok {
+		r1 = rf(ctx, userID, name, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
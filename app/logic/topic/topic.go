@@ -0,0 +1,103 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"GoMail/app/config"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+	topicRepo "GoMail/app/repository/topic"
+)
+
+var (
+	ErrInvalidTopic  = errors.New("invalid topic")
+	ErrTopicNotFound = errors.New("topic not found")
+	ErrTopicExists   = errors.New("topic already exists")
+)
+
+// MaxTopics is the most topics a user can have, all shown on the
+// preference page
+const MaxTopics = 50
+
+// namePattern matches the names of topics, which are used as keys
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Service defines the interface for the topics users tag their emails
+// with. Recipients opt out of topics, except transactional ones, on the
+// preference page.
+type Service interface {
+	// Create adds a topic
+	Create(ctx context.Context, userID string, req TopicRequest) (*TopicResponse, error)
+
+	// Get returns a topic
+	Get(ctx context.Context, userID, name string) (*TopicResponse, error)
+
+	// List returns the topics of a user, sorted by name
+	List(ctx context.Context, userID string) (*ListTopicsResponse, error)
+
+	// Update changes the label, description and kind of a topic
+	Update(ctx context.Context, userID, name string, req UpdateRequest) (*TopicResponse, error)
+
+	// Delete removes a topic. Sends can no longer be tagged with it.
+	Delete(ctx context.Context, userID, name string) error
+
+	// Find returns a topic of a user for a send tagged with it
+	Find(ctx context.Context, userID, name string) (*models.Topic, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	config *config.Config
+}
+
+// New creates a new topic service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		config: cfg,
+	}
+}
+
+// NormalizeName returns the lower-case form of a topic name, failing for
+// names that aren't letters, digits, hyphens and underscores
+func NormalizeName(name string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if !namePattern.MatchString(normalized) {
+		return "", fmt.Errorf("%w: invalid name %q", ErrInvalidTopic, name)
+	}
+	return normalized, nil
+}
+
+// Find returns a topic of a user
+func (s *service) Find(ctx context.Context, userID, name string) (*models.Topic, error) {
+	normalized, err := NormalizeName(name)
+	if err != nil {
+		return nil, ErrTopicNotFound
+	}
+
+	topic, err := s.repo.FindTopic(ctx, userID, normalized)
+	if err != nil {
+		if errors.Is(err, topicRepo.ErrTopicNotFound) {
+			return nil, ErrTopicNotFound
+		}
+		return nil, err
+	}
+	return topic, nil
+}
+
+// toResponse converts a topic into its API representation
+func toResponse(topic *models.Topic) *TopicResponse {
+	return &TopicResponse{
+		Name:          topic.Name,
+		Label:         topic.Label,
+		Description:   topic.Description,
+		Transactional: topic.Transactional,
+		CreatedAt:     topic.CreatedAt,
+		UpdatedAt:     topic.UpdatedAt,
+	}
+}
@@ -0,0 +1,88 @@
+package topic
+
+import (
+	"context"
+	"testing"
+
+	"GoMail/app/config"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+	topicRepo "GoMail/app/repository/topic"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Create(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       TopicRequest
+		existing  int
+		saveErr   error
+		wantLabel string
+		wantErr   error
+	}{
+		{name: "happy path", req: TopicRequest{Name: " Product-Updates ", Label: "Product updates"}, wantLabel: "Product updates"},
+		{name: "label defaults to name", req: TopicRequest{Name: "billing", Transactional: true}, wantLabel: "billing"},
+		{name: "invalid name", req: TopicRequest{Name: "product updates"}, wantErr: ErrInvalidTopic},
+		{name: "too many topics", req: TopicRequest{Name: "billing"}, existing: MaxTopics, wantErr: ErrInvalidTopic},
+		{name: "duplicate", req: TopicRequest{Name: "billing"}, saveErr: topicRepo.ErrTopicExists, wantErr: ErrTopicExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repoMocks.Repository{}
+			repo.On("FindUserTopics", mock.Anything, "user-1").Return(make([]*models.Topic, tt.existing), nil)
+			repo.On("SaveTopic", mock.Anything, mock.AnythingOfType("*models.Topic")).Return(tt.saveErr)
+
+			s := New(repo, &config.Config{})
+			got, err := s.Create(context.Background(), "user-1", tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLabel, got.Label)
+			assert.Equal(t, tt.req.Transactional, got.Transactional)
+			repo.AssertCalled(t, "SaveTopic", mock.Anything, mock.MatchedBy(func(topic *models.Topic) bool {
+				return topic.UserID == "user-1" && (topic.Name == "product-updates" || topic.Name == "billing")
+			}))
+		})
+	}
+}
+
+func TestService_Find(t *testing.T) {
+	topic := &models.Topic{Name: "billing", Transactional: true}
+
+	repo := &repoMocks.Repository{}
+	repo.On("FindTopic", mock.Anything, "user-1", "billing").Return(topic, nil)
+	repo.On("FindTopic", mock.Anything, "user-1", "news").Return(nil, topicRepo.ErrTopicNotFound)
+
+	s := New(repo, &config.Config{})
+
+	got, err := s.Find(context.Background(), "user-1", "Billing")
+	require.NoError(t, err)
+	assert.Equal(t, topic, got)
+
+	_, err = s.Find(context.Background(), "user-1", "news")
+	assert.ErrorIs(t, err, ErrTopicNotFound)
+
+	_, err = s.Find(context.Background(), "user-1", "not a name")
+	assert.ErrorIs(t, err, ErrTopicNotFound)
+}
+
+func TestService_Update(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindTopic", mock.Anything, "user-1", "billing").Return(&models.Topic{Name: "billing", Label: "Billing"}, nil)
+	repo.On("UpdateTopic", mock.Anything, mock.MatchedBy(func(topic *models.Topic) bool {
+		return topic.Label == "billing" && topic.Transactional
+	})).Return(nil)
+
+	s := New(repo, &config.Config{})
+	got, err := s.Update(context.Background(), "user-1", "billing", UpdateRequest{Transactional: true})
+
+	require.NoError(t, err)
+	assert.True(t, got.Transactional)
+	repo.AssertExpectations(t)
+}
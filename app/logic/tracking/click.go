@@ -0,0 +1,25 @@
+package tracking
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+)
+
+// RecordClick records a click of a tracked link. The click is only lost,
+// not the redirect, when it can't be saved.
+func (s *service) RecordClick(ctx context.Context, token string, req EventRequest) (string, error) {
+	t, err := verify(s.clickSigner, token)
+	if err != nil {
+		return "", err
+	}
+	if t.url == "" {
+		return "", ErrInvalidToken
+	}
+	event := s.newEvent(ctx, models.EmailEventClick, t, req)
+	if err := s.repo.SaveEmailEvent(ctx, event); err != nil {
+		return t.url, err
+	}
+	s.publish(ctx, models.WebhookEventClicked, event)
+	return t.url, nil
+}
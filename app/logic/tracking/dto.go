@@ -0,0 +1,76 @@
+package tracking
+
+import (
+	"time"
+
+	"GoMail/app/repository/models"
+)
+
+// EventRequest represents the request a tracking pixel was loaded or a
+// tracked link followed with
+type EventRequest struct {
+	IP        string
+	UserAgent string
+}
+
+// ReportResponse summarises the opens and clicks of a message. Opens and
+// UniqueOpens leave out prefetched opens, which PrefetchedOpens counts.
+type ReportResponse struct {
+	MessageID       string           `json:"messageId"`
+	Opens           int              `json:"opens"`
+	UniqueOpens     int              `json:"uniqueOpens"`
+	PrefetchedOpens int              `json:"prefetchedOpens"`
+	FirstOpenedAt   *time.Time       `json:"firstOpenedAt,omitempty"`
+	LastOpenedAt    *time.Time       `json:"lastOpenedAt,omitempty"`
+	Recipients      []RecipientOpens `json:"recipients"`
+	Clicks          int              `json:"clicks"`
+	UniqueClicks    int              `json:"uniqueClicks"`
+	Links           []LinkClicks     `json:"links"`
+}
+
+// RecipientOpens summarises the opens of a recipient. Recipient is empty
+// for messages to several recipients.
+type RecipientOpens struct {
+	Recipient     string    `json:"recipient"`
+	Opens         int       `json:"opens"`
+	FirstOpenedAt time.Time `json:"firstOpenedAt"`
+	LastOpenedAt  time.Time `json:"lastOpenedAt"`
+}
+
+// LinkClicks summarises the clicks of a link. UniqueClicks counts each
+// recipient once.
+type LinkClicks struct {
+	URL          string `json:"url"`
+	Clicks       int    `json:"clicks"`
+	UniqueClicks int    `json:"uniqueClicks"`
+}
+
+// ListFilter narrows a list of events
+type ListFilter struct {
+	MessageID string
+	Type      models.EmailEventType
+}
+
+// EventResponse represents an event
+type EventResponse struct {
+	ID         string                `json:"id"`
+	Type       models.EmailEventType `json:"type"`
+	MessageID  string                `json:"messageId"`
+	EmailLogID string                `json:"emailLogId,omitempty"`
+	Recipient  string                `json:"recipient,omitempty"`
+	IP         string                `json:"ip,omitempty"`
+	UserAgent  string                `json:"userAgent,omitempty"`
+	URL        string                `json:"url,omitempty"`
+	List       string                `json:"list,omitempty"`
+	Proxy      string                `json:"proxy,omitempty"`
+	Prefetched bool                  `json:"prefetched"`
+	OccurredAt time.Time             `json:"occurredAt"`
+}
+
+// ListEventsResponse represents a page of events
+type ListEventsResponse struct {
+	Events []EventResponse `json:"events"`
+	Total  int64           `json:"total"`
+	Page   int             `json:"page"`
+	Limit  int             `json:"limit"`
+}
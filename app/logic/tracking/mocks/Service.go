@@ -0,0 +1,171 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	tracking "GoMail/app/logic/tracking"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// ClickURL provides a mock function with given fields: userID, messageID, recipient, url
+func (_m *Service) ClickURL(userID string, messageID string, recipient string, url string) string {
+	ret := _m.Called(userID, messageID, recipient, url)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClickURL")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string, string) string); ok {
+		r0 = rf(userID, messageID, recipient, url)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ListEvents provides a mock function with given fields: ctx, userID, filter, page, limit
+func (_m *Service) ListEvents(ctx context.Context, userID string, filter tracking.ListFilter, page int, limit int) (*tracking.ListEventsResponse, error) {
+	ret := _m.Called(ctx, userID, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEvents")
+	}
+
+	var r0 *tracking.ListEventsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, tracking.ListFilter, int, int) (*tracking.ListEventsResponse, error)); ok {
+		return rf(ctx, userID, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, tracking.ListFilter, int, int) *tracking.ListEventsResponse); ok {
+		r0 = rf(ctx, userID, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tracking.ListEventsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, tracking.ListFilter, int, int) error); ok {
+		r1 = rf(ctx, userID, filter, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PixelURL provides a mock function with given fields: userID, messageID, recipient
+func (_m *Service) PixelURL(userID string, messageID string, recipient string) string {
+	ret := _m.Called(userID, messageID, recipient)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PixelURL")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(userID, messageID, recipient)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// RecordClick provides a mock function with given fields: ctx, token, req
+func (_m *Service) RecordClick(ctx context.Context, token string, req tracking.EventRequest) (string, error) {
+	ret := _m.Called(ctx, token, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordClick")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, tracking.EventRequest) (string, error)); ok {
+		return rf(ctx, token, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, tracking.EventRequest) string); ok {
+		r0 = rf(ctx, token, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, tracking.EventRequest) error); ok {
+		r1 = rf(ctx, token, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordOpen provides a mock function with given fields: ctx, token, req
+func (_m *Service) RecordOpen(ctx context.Context, token string, req tracking.EventRequest) error {
+	ret := _m.Called(ctx, token, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordOpen")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, tracking.EventRequest) error); ok {
+		r0 = rf(ctx, token, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Report provides a mock function with given fields: ctx, userID, messageID
+func (_m *Service) Report(ctx context.Context, userID string, messageID string) (*tracking.ReportResponse, error) {
+	ret := _m.Called(ctx, userID, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Report")
+	}
+
+	var r0 *tracking.ReportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*tracking.ReportResponse, error)); ok {
+		return rf(ctx, userID, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *tracking.ReportResponse); ok {
+		r0 = rf(ctx, userID, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tracking.ReportResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+package tracking
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository/models"
+)
+
+// proxies are image proxies of mail providers, recognised by their user
+// agent. Apple Mail Privacy Protection loads the images of all emails on
+// delivery, with a bare "Mozilla/5.0" user agent.
+var proxies = []struct {
+	name       string
+	match      func(userAgent string) bool
+	prefetched bool
+}{
+	{name: "apple", match: func(ua string) bool { return ua == "Mozilla/5.0" }, prefetched: true},
+	{name: "google", match: func(ua string) bool { return strings.Contains(ua, "GoogleImageProxy") }},
+	{name: "yahoo", match: func(ua string) bool { return strings.Contains(ua, "YahooMailProxy") }},
+}
+
+// RecordOpen records an open of a tracking pixel
+func (s *service) RecordOpen(ctx context.Context, token string, req EventRequest) error {
+	t, err := verify(s.signer, token)
+	if err != nil {
+		return err
+	}
+	event := s.newEvent(ctx, models.EmailEventOpen, t, req)
+	if err := s.repo.SaveEmailEvent(ctx, event); err != nil {
+		return err
+	}
+	s.publish(ctx, models.WebhookEventOpened, event)
+	return nil
+}
+
+// publish notifies the webhooks of the sender of a recorded event. The
+// event stays recorded when they can't be notified.
+func (s *service) publish(ctx context.Context, eventType models.WebhookEventType, event *models.EmailEvent) {
+	err := s.events.Publish(ctx, event.UserID, eventType, webhook.EventData{
+		MessageID:  event.MessageID,
+		Recipient:  event.Recipient,
+		URL:        event.URL,
+		IP:         event.IP,
+		UserAgent:  event.UserAgent,
+		Proxy:      event.Proxy,
+		Prefetched: event.Prefetched,
+		OccurredAt: event.OccurredAt,
+	})
+	if err != nil {
+		log.Printf("Failed to publish %s event of %s: %v", eventType, event.MessageID, err)
+	}
+}
+
+// newEvent creates an event of a tracked target from the request it was
+// recorded with
+func (s *service) newEvent(ctx context.Context, eventType models.EmailEventType, t target, req EventRequest) *models.EmailEvent {
+	event := &models.EmailEvent{
+		Type:       eventType,
+		UserID:     t.userID,
+		MessageID:  t.messageID,
+		Recipient:  t.recipient,
+		URL:        t.url,
+		IP:         s.clientIP(req.IP),
+		UserAgent:  req.UserAgent,
+		OccurredAt: time.Now(),
+	}
+	event.Proxy, event.Prefetched = detectProxy(req.UserAgent)
+
+	// The log may be missing when it couldn't be saved, the event is
+	// still found by the Message-ID
+	if emailLog, err := s.repo.FindEmailLogByMessageID(ctx, t.messageID); err == nil && emailLog != nil {
+		event.EmailLogID = &emailLog.ID
+	}
+	return event
+}
+
+// detectProxy returns the name of the image proxy a request came through
+// and whether it prefetches images
+func detectProxy(userAgent string) (string, bool) {
+	userAgent = strings.TrimSpace(userAgent)
+	for _, proxy := range proxies {
+		if proxy.match(userAgent) {
+			return proxy.name, proxy.prefetched
+		}
+	}
+	return "", false
+}
+
+// clientIP returns the address an event is recorded with, keeping only
+// its network when anonymisation is enabled
+func (s *service) clientIP(address string) string {
+	if !s.config.Tracking.AnonymizeIP {
+		return address
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return ""
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
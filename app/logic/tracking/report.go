@@ -0,0 +1,135 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Report summarises the opens and clicks of a message
+func (s *service) Report(ctx context.Context, userID, messageID string) (*ReportResponse, error) {
+	events, err := s.repo.FindMessageEmailEvents(ctx, userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ReportResponse{
+		MessageID:  messageID,
+		Recipients: make([]RecipientOpens, 0),
+		Links:      make([]LinkClicks, 0),
+	}
+	recipients := make(map[string]int)
+	links := make(map[string]int)
+	clickers := make(map[string]bool)
+	linkClickers := make(map[string]bool)
+	for _, event := range events {
+		switch event.Type {
+		case models.EmailEventOpen:
+			if event.Prefetched {
+				resp.PrefetchedOpens++
+				continue
+			}
+			resp.Opens++
+			at := event.OccurredAt
+			if resp.FirstOpenedAt == nil {
+				resp.FirstOpenedAt = &at
+			}
+			resp.LastOpenedAt = &at
+
+			// Events come oldest first
+			i, seen := recipients[event.Recipient]
+			if !seen {
+				i = len(resp.Recipients)
+				recipients[event.Recipient] = i
+				resp.Recipients = append(resp.Recipients, RecipientOpens{Recipient: event.Recipient, FirstOpenedAt: at})
+			}
+			resp.Recipients[i].Opens++
+			resp.Recipients[i].LastOpenedAt = at
+
+		case models.EmailEventClick:
+			resp.Clicks++
+			clickers[event.Recipient] = true
+
+			i, seen := links[event.URL]
+			if !seen {
+				i = len(resp.Links)
+				links[event.URL] = i
+				resp.Links = append(resp.Links, LinkClicks{URL: event.URL})
+			}
+			resp.Links[i].Clicks++
+			if key := event.URL + "\n" + event.Recipient; !linkClickers[key] {
+				linkClickers[key] = true
+				resp.Links[i].UniqueClicks++
+			}
+		}
+	}
+	resp.UniqueOpens = len(resp.Recipients)
+	resp.UniqueClicks = len(clickers)
+
+	return resp, nil
+}
+
+// ListEvents returns the events of a user, newest first
+func (s *service) ListEvents(ctx context.Context, userID string, filter ListFilter, page, limit int) (*ListEventsResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	query := bson.M{"user_id": userID}
+	if filter.MessageID != "" {
+		query["message_id"] = filter.MessageID
+	}
+	switch filter.Type {
+	case "":
+	case models.EmailEventOpen, models.EmailEventClick, models.EmailEventUnsubscribe:
+		query["type"] = filter.Type
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidEvent, filter.Type)
+	}
+
+	events, total, err := s.repo.FindEmailEvents(ctx, query, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListEventsResponse{
+		Events: make([]EventResponse, 0, len(events)),
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, toResponse(event))
+	}
+	return resp, nil
+}
+
+// toResponse converts an event into its API representation
+func toResponse(event *models.EmailEvent) EventResponse {
+	resp := EventResponse{
+		ID:         event.ID.Hex(),
+		Type:       event.Type,
+		MessageID:  event.MessageID,
+		Recipient:  event.Recipient,
+		IP:         event.IP,
+		UserAgent:  event.UserAgent,
+		URL:        event.URL,
+		List:       event.List,
+		Proxy:      event.Proxy,
+		Prefetched: event.Prefetched,
+		OccurredAt: event.OccurredAt,
+	}
+	if event.EmailLogID != nil {
+		resp.EmailLogID = event.EmailLogID.Hex()
+	}
+	return resp
+}
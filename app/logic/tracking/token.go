@@ -0,0 +1,43 @@
+package tracking
+
+import (
+	"strings"
+	"time"
+
+	"GoMail/app/libs/signer"
+)
+
+// target is what a tracking token points at
+type target struct {
+	userID    string
+	messageID string
+	recipient string
+	url       string // Only set for links
+}
+
+// encodeTarget joins the fields of a target with newlines, which none of
+// them contains
+func encodeTarget(t target) []byte {
+	fields := []string{t.userID, t.messageID, t.recipient}
+	if t.url != "" {
+		fields = append(fields, t.url)
+	}
+	return []byte(strings.Join(fields, "\n"))
+}
+
+// verify checks a token of a signer and returns its target
+func verify(s *signer.Signer, token string) (target, error) {
+	payload, _, err := s.Verify(token, time.Now())
+	if err != nil {
+		return target{}, ErrInvalidToken
+	}
+	fields := strings.Split(string(payload), "\n")
+	if len(fields) < 3 || len(fields) > 4 || fields[1] == "" {
+		return target{}, ErrInvalidToken
+	}
+	t := target{userID: fields[0], messageID: fields[1], recipient: fields[2]}
+	if len(fields) == 4 {
+		t.url = fields[3]
+	}
+	return t, nil
+}
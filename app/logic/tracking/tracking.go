@@ -0,0 +1,96 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/signer"
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid tracking token")
+	ErrInvalidEvent = errors.New("invalid event type")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// tokenPurpose separates tracking tokens from other signed tokens
+	tokenPurpose = "tracking"
+	// clickPurpose separates link tokens from pixel tokens
+	clickPurpose = "tracking-click"
+)
+
+// Service defines the interface for tracking what recipients do with sent
+// emails
+type Service interface {
+	// PixelURL returns the address of the tracking pixel of a message. The
+	// recipient is empty for messages to several recipients.
+	PixelURL(userID, messageID, recipient string) string
+
+	// ClickURL returns the address a link of a message is rewritten to,
+	// which redirects to url
+	ClickURL(userID, messageID, recipient, url string) string
+
+	// RecordOpen verifies a pixel token and records the open
+	RecordOpen(ctx context.Context, token string, req EventRequest) error
+
+	// RecordClick verifies a link token, records the click and returns the
+	// address to redirect to
+	RecordClick(ctx context.Context, token string, req EventRequest) (string, error)
+
+	// Report summarises the opens and clicks of a message of a user. Opens
+	// and clicks of the same recipient are counted once as unique ones and
+	// opens by prefetching proxies are counted apart.
+	Report(ctx context.Context, userID, messageID string) (*ReportResponse, error)
+
+	// ListEvents returns the events of the messages of a user, newest first
+	ListEvents(ctx context.Context, userID string, filter ListFilter, page, limit int) (*ListEventsResponse, error)
+}
+
+// eventPublisher passes opens and clicks on to the webhooks of the sender
+type eventPublisher interface {
+	Publish(ctx context.Context, userID string, eventType models.WebhookEventType, data webhook.EventData) error
+}
+
+// service implements the Service interface
+type service struct {
+	repo        repository.Repository
+	signer      *signer.Signer
+	clickSigner *signer.Signer
+	events      eventPublisher
+	config      *config.Config
+}
+
+// New creates a new tracking service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:        repo,
+		signer:      signer.New(cfg.Server.SigningSecret, tokenPurpose),
+		clickSigner: signer.New(cfg.Server.SigningSecret, clickPurpose),
+		events:      webhook.New(repo, cfg),
+		config:      cfg,
+	}
+}
+
+// PixelURL returns the public address of a tracking pixel. Tokens never
+// expire, so opens of old emails are still recorded.
+func (s *service) PixelURL(userID, messageID, recipient string) string {
+	token := s.signer.Sign(encodeTarget(target{userID: userID, messageID: messageID, recipient: recipient}), time.Time{})
+	return strings.TrimRight(s.config.Server.PublicURL, "/") + "/t/o/" + token
+}
+
+// ClickURL returns the public address of a tracked link. The target is
+// part of the signed token, so the endpoint only redirects to addresses
+// GoMail sent.
+func (s *service) ClickURL(userID, messageID, recipient, url string) string {
+	token := s.clickSigner.Sign(encodeTarget(target{userID: userID, messageID: messageID, recipient: recipient, url: url}), time.Time{})
+	return strings.TrimRight(s.config.Server.PublicURL, "/") + "/t/c/" + token
+}
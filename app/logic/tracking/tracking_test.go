@@ -0,0 +1,174 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newConfig() *config.Config {
+	return &config.Config{Server: config.ServerConfig{PublicURL: "https://mail.example.com/", SigningSecret: "secret"}}
+}
+
+func TestService_RecordOpen(t *testing.T) {
+	logID := primitive.NewObjectID()
+
+	tests := []struct {
+		name           string
+		userAgent      string
+		ip             string
+		anonymize      bool
+		wantIP         string
+		wantProxy      string
+		wantPrefetched bool
+	}{
+		{name: "mail client", userAgent: "Mozilla/5.0 (Macintosh) AppleWebKit/605.1.15", ip: "192.0.2.10", wantIP: "192.0.2.10"},
+		{name: "apple privacy protection", userAgent: "Mozilla/5.0", ip: "17.58.0.1", wantIP: "17.58.0.1", wantProxy: "apple", wantPrefetched: true},
+		{name: "gmail proxy", userAgent: "Mozilla/5.0 (Windows NT 5.1; rv:11.0) Gecko Firefox/11.0 (via ggpht.com GoogleImageProxy)", ip: "66.249.84.1", wantIP: "66.249.84.1", wantProxy: "google"},
+		{name: "anonymised ipv4", ip: "192.0.2.10", anonymize: true, wantIP: "192.0.2.0"},
+		{name: "anonymised ipv6", ip: "2001:db8:1234:5678::1", anonymize: true, wantIP: "2001:db8:1234::"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			cfg.Tracking.AnonymizeIP = tt.anonymize
+
+			var saved *models.EmailEvent
+			repo := &repoMocks.Repository{}
+			repo.On("FindEmailLogByMessageID", mock.Anything, "<abc@example.com>").Return(&models.EmailLog{ID: logID}, nil)
+			repo.On("SaveEmailEvent", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(*models.EmailEvent)
+			}).Return(nil)
+			repo.On("FindSubscribedWebhooks", mock.Anything, "user-1", models.WebhookEventOpened).Return([]*models.Webhook{}, nil)
+
+			s := New(repo, cfg)
+			pixel := s.PixelURL("user-1", "<abc@example.com>", "ada@example.org")
+			require.True(t, strings.HasPrefix(pixel, "https://mail.example.com/t/o/"))
+
+			err := s.RecordOpen(context.Background(), strings.TrimPrefix(pixel, "https://mail.example.com/t/o/"), EventRequest{IP: tt.ip, UserAgent: tt.userAgent})
+
+			require.NoError(t, err)
+			require.NotNil(t, saved)
+			assert.Equal(t, models.EmailEventOpen, saved.Type)
+			assert.Equal(t, "user-1", saved.UserID)
+			assert.Equal(t, "<abc@example.com>", saved.MessageID)
+			assert.Equal(t, "ada@example.org", saved.Recipient)
+			assert.Equal(t, &logID, saved.EmailLogID)
+			assert.Equal(t, tt.wantIP, saved.IP)
+			assert.Equal(t, tt.wantProxy, saved.Proxy)
+			assert.Equal(t, tt.wantPrefetched, saved.Prefetched)
+		})
+	}
+}
+
+func TestService_RecordOpen_InvalidToken(t *testing.T) {
+	s := New(&repoMocks.Repository{}, newConfig())
+	other := New(&repoMocks.Repository{}, &config.Config{Server: config.ServerConfig{SigningSecret: "other"}})
+	forged := strings.TrimPrefix(other.PixelURL("user-1", "<abc@example.com>", ""), "/t/o/")
+
+	for _, token := range []string{"", "garbage", forged} {
+		err := s.RecordOpen(context.Background(), token, EventRequest{})
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
+
+func TestService_RecordClick(t *testing.T) {
+	var saved *models.EmailEvent
+	repo := &repoMocks.Repository{}
+	repo.On("FindEmailLogByMessageID", mock.Anything, "<abc@example.com>").Return(nil, errors.New("not found"))
+	repo.On("SaveEmailEvent", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*models.EmailEvent)
+	}).Return(nil)
+	repo.On("FindSubscribedWebhooks", mock.Anything, "user-1", models.WebhookEventClicked).Return([]*models.Webhook{{ID: primitive.NewObjectID()}}, nil)
+	repo.On("SaveWebhookDelivery", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.EventType == models.WebhookEventClicked && strings.Contains(d.Payload, `"url":"https://shop.example.com/order?id=7"`)
+	})).Return(nil).Once()
+
+	s := New(repo, newConfig())
+	link := s.ClickURL("user-1", "<abc@example.com>", "", "https://shop.example.com/order?id=7")
+	require.True(t, strings.HasPrefix(link, "https://mail.example.com/t/c/"))
+
+	got, err := s.RecordClick(context.Background(), strings.TrimPrefix(link, "https://mail.example.com/t/c/"), EventRequest{IP: "192.0.2.10"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://shop.example.com/order?id=7", got)
+	require.NotNil(t, saved)
+	assert.Equal(t, models.EmailEventClick, saved.Type)
+	assert.Equal(t, "https://shop.example.com/order?id=7", saved.URL)
+	assert.Empty(t, saved.Recipient)
+	assert.Nil(t, saved.EmailLogID)
+	repo.AssertExpectations(t)
+}
+
+func TestService_RecordClick_InvalidToken(t *testing.T) {
+	s := New(&repoMocks.Repository{}, newConfig())
+	pixel := strings.TrimPrefix(s.PixelURL("user-1", "<abc@example.com>", ""), "https://mail.example.com/t/o/")
+
+	for _, token := range []string{"", "garbage", pixel} {
+		_, err := s.RecordClick(context.Background(), token, EventRequest{})
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
+
+func TestService_Report(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	repo := &repoMocks.Repository{}
+	repo.On("FindMessageEmailEvents", mock.Anything, "user-1", "<abc@example.com>").Return([]*models.EmailEvent{
+		{Type: models.EmailEventOpen, Recipient: "ada@example.org", Proxy: "apple", Prefetched: true, OccurredAt: start},
+		{Type: models.EmailEventOpen, Recipient: "ada@example.org", OccurredAt: start.Add(time.Hour)},
+		{Type: models.EmailEventOpen, Recipient: "bob@example.org", OccurredAt: start.Add(2 * time.Hour)},
+		{Type: models.EmailEventOpen, Recipient: "ada@example.org", OccurredAt: start.Add(3 * time.Hour)},
+		{Type: models.EmailEventClick, Recipient: "ada@example.org", URL: "https://shop.example.com/a", OccurredAt: start.Add(time.Hour)},
+		{Type: models.EmailEventClick, Recipient: "ada@example.org", URL: "https://shop.example.com/a", OccurredAt: start.Add(2 * time.Hour)},
+		{Type: models.EmailEventClick, Recipient: "ada@example.org", URL: "https://shop.example.com/b", OccurredAt: start.Add(2 * time.Hour)},
+		{Type: models.EmailEventClick, Recipient: "bob@example.org", URL: "https://shop.example.com/a", OccurredAt: start.Add(3 * time.Hour)},
+	}, nil)
+
+	got, err := New(repo, newConfig()).Report(context.Background(), "user-1", "<abc@example.com>")
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, got.Opens)
+	assert.Equal(t, 2, got.UniqueOpens)
+	assert.Equal(t, 1, got.PrefetchedOpens)
+	assert.Equal(t, start.Add(time.Hour), *got.FirstOpenedAt)
+	assert.Equal(t, start.Add(3*time.Hour), *got.LastOpenedAt)
+	assert.Equal(t, []RecipientOpens{
+		{Recipient: "ada@example.org", Opens: 2, FirstOpenedAt: start.Add(time.Hour), LastOpenedAt: start.Add(3 * time.Hour)},
+		{Recipient: "bob@example.org", Opens: 1, FirstOpenedAt: start.Add(2 * time.Hour), LastOpenedAt: start.Add(2 * time.Hour)},
+	}, got.Recipients)
+	assert.Equal(t, 4, got.Clicks)
+	assert.Equal(t, 2, got.UniqueClicks)
+	assert.Equal(t, []LinkClicks{
+		{URL: "https://shop.example.com/a", Clicks: 3, UniqueClicks: 2},
+		{URL: "https://shop.example.com/b", Clicks: 1, UniqueClicks: 1},
+	}, got.Links)
+}
+
+func TestService_ListEvents(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindEmailEvents", mock.Anything, bson.M{"user_id": "user-1", "message_id": "<abc@example.com>", "type": models.EmailEventOpen}, 1, maxPageSize).
+		Return([]*models.EmailEvent{{ID: primitive.NewObjectID(), Type: models.EmailEventOpen}}, int64(1), nil)
+	s := New(repo, newConfig())
+
+	got, err := s.ListEvents(context.Background(), "user-1", ListFilter{MessageID: "<abc@example.com>", Type: models.EmailEventOpen}, 0, 500)
+	require.NoError(t, err)
+	assert.Len(t, got.Events, 1)
+	assert.Equal(t, int64(1), got.Total)
+
+	_, err = s.ListEvents(context.Background(), "user-1", ListFilter{Type: "bounce"}, 1, 10)
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+}
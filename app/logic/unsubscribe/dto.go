@@ -0,0 +1,11 @@
+package unsubscribe
+
+// UnsubscribeResponse tells who an unsubscribe link is for. List is empty
+// for links that unsubscribe from all emails of the sender. PreferencesURL
+// links to the preference page when the list is a topic.
+type UnsubscribeResponse struct {
+	Recipient      string `json:"recipient"`
+	List           string `json:"list,omitempty"`
+	Unsubscribed   bool   `json:"unsubscribed"`
+	PreferencesURL string `json:"preferencesUrl,omitempty"`
+}
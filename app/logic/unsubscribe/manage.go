@@ -0,0 +1,179 @@
+package unsubscribe
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository/models"
+	recipientRepo "GoMail/app/repository/recipient"
+	suppressionRepo "GoMail/app/repository/suppression"
+	topicRepo "GoMail/app/repository/topic"
+)
+
+// Get verifies a token and tells whether its recipient has unsubscribed
+// already
+func (s *service) Get(ctx context.Context, token string) (*UnsubscribeResponse, error) {
+	t, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	resp := &UnsubscribeResponse{Recipient: t.recipient, List: t.list}
+
+	topic, err := s.topicOf(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	if topic != nil {
+		resp.Unsubscribed, err = s.optedOut(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		resp.PreferencesURL = s.preferences.PreferencesURL(t.userID, t.recipient)
+		return resp, nil
+	}
+
+	_, err = s.repo.FindActiveSuppression(ctx, t.userID, t.list, []string{t.recipient}, time.Now())
+	if err != nil && !errors.Is(err, suppressionRepo.ErrSuppressionNotFound) {
+		return nil, err
+	}
+	resp.Unsubscribed = err == nil
+
+	return resp, nil
+}
+
+// Unsubscribe suppresses the recipient of a token for the list of the
+// user, or opts it out when the list is a topic of the user, records the
+// unsubscribe with the message and notifies the webhooks of the user.
+// Mailbox providers may repeat one-click requests, so only the first one
+// is recorded.
+func (s *service) Unsubscribe(ctx context.Context, token string) (*UnsubscribeResponse, error) {
+	t, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	resp := &UnsubscribeResponse{Recipient: t.recipient, List: t.list, Unsubscribed: true}
+
+	topic, err := s.topicOf(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	var first bool
+	if topic != nil {
+		resp.PreferencesURL = s.preferences.PreferencesURL(t.userID, t.recipient)
+		first, err = s.optOut(ctx, t)
+	} else {
+		first, err = s.suppress(ctx, t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		return resp, nil
+	}
+
+	event := &models.EmailEvent{
+		Type:       models.EmailEventUnsubscribe,
+		UserID:     t.userID,
+		MessageID:  t.messageID,
+		Recipient:  t.recipient,
+		List:       t.list,
+		OccurredAt: time.Now(),
+	}
+	if emailLog, err := s.repo.FindEmailLogByMessageID(ctx, t.messageID); err == nil && emailLog != nil {
+		event.EmailLogID = &emailLog.ID
+	}
+	if err := s.repo.SaveEmailEvent(ctx, event); err != nil {
+		log.Printf("Failed to record unsubscribe of %s: %v", t.messageID, err)
+	}
+
+	err = s.events.Publish(ctx, t.userID, models.WebhookEventUnsubscribed, webhook.EventData{
+		MessageID:  t.messageID,
+		Recipient:  t.recipient,
+		List:       t.list,
+		OccurredAt: event.OccurredAt,
+	})
+	if err != nil {
+		log.Printf("Failed to publish unsubscribe of %s: %v", t.messageID, err)
+	}
+
+	return resp, nil
+}
+
+// topicOf returns the topic of the user a token unsubscribes from, or nil
+// when its list isn't one. Recipients can't opt out of transactional
+// topics, so they are suppressed for the list instead.
+func (s *service) topicOf(ctx context.Context, t target) (*models.Topic, error) {
+	if t.list == "" {
+		return nil, nil
+	}
+	topic, err := s.repo.FindTopic(ctx, t.userID, t.list)
+	if err != nil {
+		if errors.Is(err, topicRepo.ErrTopicNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if topic.Transactional {
+		return nil, nil
+	}
+	return topic, nil
+}
+
+// suppress suppresses the recipient of a token for its list, telling
+// whether it wasn't already
+func (s *service) suppress(ctx context.Context, t target) (bool, error) {
+	err := s.repo.SaveSuppression(ctx, &models.Suppression{
+		UserID: t.userID,
+		Scope:  models.SuppressionScopeUser,
+		Type:   models.SuppressionTypeAddress,
+		Value:  t.recipient,
+		List:   t.list,
+		Reason: models.SuppressionReasonUnsubscribe,
+		Note:   "Unsubscribed from " + t.messageID,
+	})
+	if errors.Is(err, suppressionRepo.ErrSuppressionExists) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// optedOutOf returns the topics the recipient of a token opted out of
+func (s *service) optedOutOf(ctx context.Context, t target) ([]string, error) {
+	recipient, err := s.repo.FindRecipient(ctx, t.userID, t.recipient)
+	if err != nil {
+		if errors.Is(err, recipientRepo.ErrRecipientNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return recipient.OptedOut, nil
+}
+
+// optedOut tells whether the recipient of a token opted out of its topic
+func (s *service) optedOut(ctx context.Context, t target) (bool, error) {
+	optedOut, err := s.optedOutOf(ctx, t)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(optedOut, t.list), nil
+}
+
+// optOut adds the topic of a token to the preferences of its recipient,
+// telling whether it wasn't already
+func (s *service) optOut(ctx context.Context, t target) (bool, error) {
+	optedOut, err := s.optedOutOf(ctx, t)
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(optedOut, t.list) {
+		return false, nil
+	}
+	if _, err := s.repo.UpdateRecipientOptedOut(ctx, t.userID, t.recipient, append(optedOut, t.list)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	unsubscribe "GoMail/app/logic/unsubscribe"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, token
+func (_m *Service) Get(ctx context.Context, token string) (*unsubscribe.UnsubscribeResponse, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *unsubscribe.UnsubscribeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*unsubscribe.UnsubscribeResponse, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *unsubscribe.UnsubscribeResponse); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unsubscribe.UnsubscribeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URL provides a mock function with given fields: userID, messageID, recipient, list
+func (_m *Service) URL(userID string, messageID string, recipient string, list string) string {
+	ret := _m.Called(userID, messageID, recipient, list)
+
+	if len(ret) == 0 {
+		panic("no return value specified for URL")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string, string) string); ok {
+		r0 = rf(userID, messageID, recipient, list)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Unsubscribe provides a mock function with given fields: ctx, token
+func (_m *Service) Unsubscribe(ctx context.Context, token string) (*unsubscribe.UnsubscribeResponse, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unsubscribe")
+	}
+
+	var r0 *unsubscribe.UnsubscribeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*unsubscribe.UnsubscribeResponse, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *unsubscribe.UnsubscribeResponse); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unsubscribe.UnsubscribeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
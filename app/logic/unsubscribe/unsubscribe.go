@@ -0,0 +1,106 @@
+package unsubscribe
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/signer"
+	"GoMail/app/logic/recipient"
+	"GoMail/app/logic/suppression"
+	"GoMail/app/logic/webhook"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+)
+
+var ErrInvalidToken = errors.New("invalid unsubscribe token")
+
+// tokenPurpose separates unsubscribe tokens from other signed tokens
+const tokenPurpose = "unsubscribe"
+
+// Service defines the interface for recipients unsubscribing from the
+// emails of a sender with the signed link of an email
+type Service interface {
+	// URL returns the address the recipient of a message unsubscribes at,
+	// from the list when set or otherwise from all emails of the user
+	URL(userID, messageID, recipient, list string) string
+
+	// Get verifies a token and returns who it unsubscribes from what
+	Get(ctx context.Context, token string) (*UnsubscribeResponse, error)
+
+	// Unsubscribe verifies a token and suppresses the recipient for the
+	// emails of the user to the list, or opts it out of the topic the list
+	// names. Unsubscribing again changes nothing.
+	Unsubscribe(ctx context.Context, token string) (*UnsubscribeResponse, error)
+}
+
+// eventPublisher passes unsubscribes on to the webhooks of the sender
+type eventPublisher interface {
+	Publish(ctx context.Context, userID string, eventType models.WebhookEventType, data webhook.EventData) error
+}
+
+// preferenceLinks creates the addresses of the preference pages of
+// recipients
+type preferenceLinks interface {
+	PreferencesURL(userID, email string) string
+}
+
+// service implements the Service interface
+type service struct {
+	repo        repository.Repository
+	signer      *signer.Signer
+	events      eventPublisher
+	preferences preferenceLinks
+	config      *config.Config
+}
+
+// New creates a new unsubscribe service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:        repo,
+		signer:      signer.New(cfg.Server.SigningSecret, tokenPurpose),
+		events:      webhook.New(repo, cfg),
+		preferences: recipient.New(repo, cfg),
+		config:      cfg,
+	}
+}
+
+// URL returns the public address of an unsubscribe link. Tokens never
+// expire, since recipients unsubscribe from old emails too.
+func (s *service) URL(userID, messageID, recipient, list string) string {
+	token := s.signer.Sign(encodeTarget(target{userID: userID, messageID: messageID, recipient: recipient, list: list}), time.Time{})
+	return strings.TrimRight(s.config.Server.PublicURL, "/") + "/u/" + token
+}
+
+// target is who an unsubscribe token unsubscribes from what
+type target struct {
+	userID    string
+	messageID string
+	recipient string
+	list      string
+}
+
+// encodeTarget joins the fields of a target with newlines, which none of
+// them contains
+func encodeTarget(t target) []byte {
+	return []byte(strings.Join([]string{t.userID, t.messageID, t.recipient, t.list}, "\n"))
+}
+
+// verify checks a token and returns its target
+func (s *service) verify(token string) (target, error) {
+	payload, _, err := s.signer.Verify(token, time.Now())
+	if err != nil {
+		return target{}, ErrInvalidToken
+	}
+	fields := strings.Split(string(payload), "\n")
+	if len(fields) != 4 || fields[0] == "" || fields[2] == "" {
+		return target{}, ErrInvalidToken
+	}
+	recipient, err := suppression.NormalizeAddress(fields[2])
+	if err != nil {
+		return target{}, ErrInvalidToken
+	}
+	return target{userID: fields[0], messageID: fields[1], recipient: recipient, list: fields[3]}, nil
+}
@@ -0,0 +1,142 @@
+package unsubscribe
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/signer"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+	recipientRepo "GoMail/app/repository/recipient"
+	suppressionRepo "GoMail/app/repository/suppression"
+	topicRepo "GoMail/app/repository/topic"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newConfig() *config.Config {
+	return &config.Config{Server: config.ServerConfig{PublicURL: "https://mail.example.com/", SigningSecret: "secret"}}
+}
+
+// tokenOf returns the token of an unsubscribe link
+func tokenOf(t *testing.T, link string) string {
+	require.True(t, strings.HasPrefix(link, "https://mail.example.com/u/"), link)
+	return strings.TrimPrefix(link, "https://mail.example.com/u/")
+}
+
+func TestService_Unsubscribe(t *testing.T) {
+	logID := primitive.NewObjectID()
+
+	var saved *models.Suppression
+	var event *models.EmailEvent
+	repo := &repoMocks.Repository{}
+	repo.On("FindTopic", mock.Anything, "user-1", "news").Return(nil, topicRepo.ErrTopicNotFound)
+	repo.On("SaveSuppression", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*models.Suppression)
+	}).Return(nil).Once()
+	repo.On("FindEmailLogByMessageID", mock.Anything, "abc@example.com").Return(&models.EmailLog{ID: logID}, nil)
+	repo.On("SaveEmailEvent", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(*models.EmailEvent)
+	}).Return(nil).Once()
+	repo.On("FindSubscribedWebhooks", mock.Anything, "user-1", models.WebhookEventUnsubscribed).Return([]*models.Webhook{}, nil).Once()
+
+	s := New(repo, newConfig())
+	token := tokenOf(t, s.URL("user-1", "abc@example.com", "Ada@Example.org", "news"))
+
+	got, err := s.Unsubscribe(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, &UnsubscribeResponse{Recipient: "ada@example.org", List: "news", Unsubscribed: true}, got)
+	require.NotNil(t, saved)
+	assert.Equal(t, "user-1", saved.UserID)
+	assert.Equal(t, models.SuppressionScopeUser, saved.Scope)
+	assert.Equal(t, models.SuppressionTypeAddress, saved.Type)
+	assert.Equal(t, "ada@example.org", saved.Value)
+	assert.Equal(t, "news", saved.List)
+	assert.Equal(t, models.SuppressionReasonUnsubscribe, saved.Reason)
+	require.NotNil(t, event)
+	assert.Equal(t, models.EmailEventUnsubscribe, event.Type)
+	assert.Equal(t, &logID, event.EmailLogID)
+	assert.Equal(t, "news", event.List)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Unsubscribe_Again(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("SaveSuppression", mock.Anything, mock.Anything).Return(suppressionRepo.ErrSuppressionExists)
+
+	s := New(repo, newConfig())
+	token := tokenOf(t, s.URL("user-1", "abc@example.com", "ada@example.org", ""))
+
+	got, err := s.Unsubscribe(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.True(t, got.Unsubscribed)
+	repo.AssertNotCalled(t, "SaveEmailEvent", mock.Anything, mock.Anything)
+}
+
+func TestService_Get(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindTopic", mock.Anything, "user-1", "news").Return(nil, topicRepo.ErrTopicNotFound)
+	repo.On("FindActiveSuppression", mock.Anything, "user-1", "news", []string{"ada@example.org"}, mock.AnythingOfType("time.Time")).
+		Return(nil, suppressionRepo.ErrSuppressionNotFound)
+
+	s := New(repo, newConfig())
+	token := tokenOf(t, s.URL("user-1", "abc@example.com", "ada@example.org", "news"))
+
+	got, err := s.Get(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, &UnsubscribeResponse{Recipient: "ada@example.org", List: "news"}, got)
+}
+
+func TestService_Unsubscribe_Topic(t *testing.T) {
+	repo := &repoMocks.Repository{}
+	repo.On("FindTopic", mock.Anything, "user-1", "news").Return(&models.Topic{Name: "news"}, nil)
+	repo.On("FindRecipient", mock.Anything, "user-1", "ada@example.org").Return(nil, recipientRepo.ErrRecipientNotFound).Once()
+	repo.On("UpdateRecipientOptedOut", mock.Anything, "user-1", "ada@example.org", []string{"news"}).
+		Return(&models.Recipient{OptedOut: []string{"news"}}, nil).Once()
+	repo.On("FindEmailLogByMessageID", mock.Anything, "abc@example.com").Return(nil, nil)
+	repo.On("SaveEmailEvent", mock.Anything, mock.Anything).Return(nil).Once()
+	repo.On("FindSubscribedWebhooks", mock.Anything, "user-1", models.WebhookEventUnsubscribed).Return([]*models.Webhook{}, nil).Once()
+
+	s := New(repo, newConfig())
+	token := tokenOf(t, s.URL("user-1", "abc@example.com", "ada@example.org", "news"))
+
+	got, err := s.Unsubscribe(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.True(t, got.Unsubscribed)
+	assert.True(t, strings.HasPrefix(got.PreferencesURL, "https://mail.example.com/p/"), got.PreferencesURL)
+	repo.AssertNotCalled(t, "SaveSuppression", mock.Anything, mock.Anything)
+
+	// Opting out again changes nothing
+	repo.On("FindRecipient", mock.Anything, "user-1", "ada@example.org").Return(&models.Recipient{OptedOut: []string{"news"}}, nil)
+
+	got, err = s.Unsubscribe(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.True(t, got.Unsubscribed)
+	repo.AssertExpectations(t)
+}
+
+func TestService_InvalidToken(t *testing.T) {
+	s := New(&repoMocks.Repository{}, newConfig())
+	for name, token := range map[string]string{
+		"malformed":       "not-a-token",
+		"tampered":        tokenOf(t, s.URL("user-1", "abc@example.com", "ada@example.org", "")) + "x",
+		"no recipient":    tokenOf(t, s.URL("user-1", "abc@example.com", "", "")),
+		"invalid address": tokenOf(t, s.URL("user-1", "abc@example.com", "not an address", "")),
+		"other purpose":   signer.New("secret", "tracking").Sign([]byte("user-1\nabc@example.com\nada@example.org\n"), time.Time{}),
+	} {
+		_, err := s.Unsubscribe(context.Background(), token)
+
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"GoMail/app/repository/models"
+	deliveryRepo "GoMail/app/repository/webhookdelivery"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Publish queues an event for every enabled webhook of a user subscribed
+// to its type. All deliveries of the event share its ID and payload.
+// Events of emails without a user, such as ones logged before users were
+// recorded, aren't published.
+func (s *service) Publish(ctx context.Context, userID string, eventType models.WebhookEventType, data EventData) error {
+	if userID == "" {
+		return nil
+	}
+
+	webhooks, err := s.repo.FindSubscribedWebhooks(ctx, userID, eventType)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if data.OccurredAt.IsZero() {
+		data.OccurredAt = now
+	}
+	event := Event{
+		ID:        primitive.NewObjectID().Hex(),
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, webhook := range webhooks {
+		delivery := &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			UserID:        userID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		}
+		if err := s.repo.SaveWebhookDelivery(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("failed to queue %s event for webhook %s: %w", eventType, webhook.ID.Hex(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListDeliveries returns the deliveries of a webhook, newest first,
+// optionally only the ones with a status
+func (s *service) ListDeliveries(ctx context.Context, userID, id string, status models.WebhookDeliveryStatus, page, limit int) (*ListDeliveriesResponse, error) {
+	webhook, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	query := bson.M{"webhook_id": webhook.ID}
+	switch status {
+	case "":
+	case models.WebhookDeliveryPending, models.WebhookDeliveryProcessing,
+		models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+		query["status"] = status
+	default:
+		return nil, fmt.Errorf("%w: unknown delivery status %q", ErrInvalidWebhook, status)
+	}
+
+	deliveries, total, err := s.repo.FindWebhookDeliveries(ctx, query, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListDeliveriesResponse{
+		Deliveries: make([]DeliveryResponse, 0, len(deliveries)),
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+	}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, *toDeliveryResponse(delivery))
+	}
+	return resp, nil
+}
+
+// Redeliver queues a new delivery of the event of a delivery to its
+// webhook, which must be enabled. The original delivery is kept as it is.
+func (s *service) Redeliver(ctx context.Context, userID, deliveryID string) (*DeliveryResponse, error) {
+	original, err := s.repo.FindWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, deliveryRepo.ErrDeliveryNotFound) || errors.Is(err, deliveryRepo.ErrInvalidID) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if original.UserID != userID {
+		return nil, ErrDeliveryNotFound
+	}
+
+	webhook, err := s.find(ctx, userID, original.WebhookID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Enabled {
+		return nil, ErrWebhookDisabled
+	}
+
+	originalID := original.ID
+	delivery := &models.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		UserID:        userID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		RedeliveryOf:  &originalID,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := s.repo.SaveWebhookDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return toDeliveryResponse(delivery), nil
+}
+
+// toDeliveryResponse converts a delivery into its API representation
+func toDeliveryResponse(delivery *models.WebhookDelivery) *DeliveryResponse {
+	resp := &DeliveryResponse{
+		ID:          delivery.ID.Hex(),
+		WebhookID:   delivery.WebhookID.Hex(),
+		EventID:     delivery.EventID,
+		EventType:   delivery.EventType,
+		Payload:     json.RawMessage(delivery.Payload),
+		Status:      delivery.Status,
+		Attempts:    delivery.AttemptLog,
+		DeliveredAt: delivery.DeliveredAt,
+		CreatedAt:   delivery.CreatedAt,
+	}
+	if resp.Attempts == nil {
+		resp.Attempts = []models.WebhookAttempt{}
+	}
+	if delivery.RedeliveryOf != nil {
+		resp.RedeliveryOf = delivery.RedeliveryOf.Hex()
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		next := delivery.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+	return resp
+}
@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/fetch"
+	"GoMail/app/libs/lease"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+	webhookRepo "GoMail/app/repository/webhook"
+	deliveryRepo "GoMail/app/repository/webhookdelivery"
+)
+
+// Dispatcher posts the events in the webhook outbox. Deliveries are
+// claimed with an atomic find-and-modify lease, so dispatchers on several
+// GoMail instances can share the same collection safely. Failed deliveries
+// are retried with exponential backoff, and a webhook is disabled once too
+// many attempts in a row failed.
+type Dispatcher struct {
+	repo    repository.Repository
+	config  config.WebhooksConfig
+	client  *http.Client
+	ownerID string
+	poller  *lease.Poller
+}
+
+// NewDispatcher creates a new webhook dispatcher
+func NewDispatcher(cfg *config.Config, repo repository.Repository) *Dispatcher {
+	allowed := fetch.IsPublic
+	if cfg.Webhooks.AllowPrivate {
+		allowed = func(net.IP) bool { return true }
+	}
+
+	dialer := &net.Dialer{
+		Timeout: cfg.Webhooks.Timeout,
+		// Checked after DNS resolution, so a public host name can't lead
+		// to an internal address
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("blocked webhook address %s", address)
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return fmt.Errorf("blocked webhook address %s", host)
+			}
+			return nil
+		},
+	}
+
+	d := &Dispatcher{
+		repo:   repo,
+		config: cfg.Webhooks,
+		client: &http.Client{
+			Timeout: cfg.Webhooks.Timeout,
+			Transport: &http.Transport{
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: cfg.Webhooks.Timeout,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     30 * time.Second,
+			},
+			// A redirect is reported as the response, it isn't followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		ownerID: lease.NewOwnerID(),
+	}
+	d.poller = lease.NewPoller("Webhook dispatcher", cfg.Webhooks.PollInterval, d.processNext)
+	return d
+}
+
+// Start launches the dispatcher loop
+func (d *Dispatcher) Start() {
+	d.poller.Start(1)
+}
+
+// Stop signals the dispatcher loop to exit and waits for the delivery it
+// is posting
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	return d.poller.Stop(ctx)
+}
+
+// processNext claims a single due delivery and posts it. It reports
+// whether a delivery was claimed.
+func (d *Dispatcher) processNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	delivery, err := d.repo.ClaimWebhookDelivery(ctx, d.ownerID, now, d.config.LeaseDuration)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	if delivery == nil {
+		return false, nil
+	}
+
+	webhook, err := d.repo.FindWebhookByID(ctx, delivery.WebhookID.Hex())
+	switch {
+	case errors.Is(err, webhookRepo.ErrWebhookNotFound):
+		d.abandon(delivery, "webhook was deleted", now)
+	case err != nil:
+		// Keep the delivery for the next attempt
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+	case !webhook.Enabled:
+		d.abandon(delivery, "webhook is disabled", now)
+	default:
+		// Bound the call by the lease so another dispatcher never posts concurrently
+		callCtx, cancel := context.WithDeadline(ctx, now.Add(d.config.LeaseDuration))
+		attempt := d.post(callCtx, webhook, delivery)
+		cancel()
+
+		delivered := attempt.Error == ""
+		if webhook, err = d.repo.RecordWebhookResult(ctx, webhook.ID, delivered, d.config.DisableAfter, time.Now()); err != nil {
+			log.Printf("Webhook dispatcher failed to record result of webhook %s: %v", delivery.WebhookID.Hex(), err)
+		} else if !webhook.Enabled && !delivered {
+			log.Printf("Webhook %s disabled: %s", webhook.ID.Hex(), webhook.DisabledReason)
+		}
+		d.applyResult(delivery, attempt, webhook == nil || webhook.Enabled, time.Now())
+	}
+
+	if err := d.repo.ReleaseWebhookDelivery(ctx, delivery, d.ownerID); err != nil {
+		if errors.Is(err, deliveryRepo.ErrLeaseLost) {
+			log.Printf("Webhook dispatcher lost lease on delivery %s", delivery.ID.Hex())
+			return true, nil
+		}
+		return true, fmt.Errorf("failed to release webhook delivery %s: %w", delivery.ID.Hex(), err)
+	}
+
+	return true, nil
+}
+
+// post sends the payload of a delivery to its webhook, signed with the
+// secret of the webhook. The webhook accepts it with a 2xx status.
+func (d *Dispatcher) post(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) models.WebhookAttempt {
+	start := time.Now()
+	attempt := models.WebhookAttempt{AttemptedAt: start}
+
+	statusCode, err := d.send(ctx, webhook, delivery, start)
+	attempt.StatusCode = statusCode
+	attempt.Duration = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	return attempt
+}
+
+// send makes the request of a delivery attempt
+func (d *Dispatcher) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoMail-Webhooks/1.0")
+	req.Header.Set("X-GoMail-Event", string(delivery.EventType))
+	req.Header.Set("X-GoMail-Event-Id", delivery.EventID)
+	req.Header.Set("X-GoMail-Delivery-Id", delivery.ID.Hex())
+	req.Header.Set("X-GoMail-Timestamp", timestamp)
+	req.Header.Set("X-GoMail-Signature", Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// applyResult records an attempt on its delivery and updates its status.
+// Deliveries of a webhook that was disabled aren't retried.
+func (d *Dispatcher) applyResult(delivery *models.WebhookDelivery, attempt models.WebhookAttempt, enabled bool, now time.Time) {
+	delivery.AttemptLog = append(delivery.AttemptLog, attempt)
+
+	switch {
+	case attempt.Error == "":
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+	case !enabled || delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+	default:
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+	}
+}
+
+// abandon fails a delivery whose webhook can't be called anymore
+func (d *Dispatcher) abandon(delivery *models.WebhookDelivery, reason string, now time.Time) {
+	delivery.AttemptLog = append(delivery.AttemptLog, models.WebhookAttempt{Error: reason, AttemptedAt: now})
+	delivery.Status = models.WebhookDeliveryFailed
+}
+
+// backoff returns the exponential retry delay after the given attempt
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	return lease.Backoff(d.config.RetryBackoff, d.config.MaxBackoff, attempt)
+}
@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"GoMail/app/repository/models"
+)
+
+// WebhookRequest represents a request to subscribe a webhook to events
+type WebhookRequest struct {
+	URL         string                    `json:"url" binding:"required"`
+	Description string                    `json:"description,omitempty"`
+	Events      []models.WebhookEventType `json:"events" binding:"required"`
+}
+
+// UpdateRequest represents a request to change a webhook. Enabled is kept
+// when nil; enabling a webhook clears the failures that disabled it.
+type UpdateRequest struct {
+	URL         string                    `json:"url" binding:"required"`
+	Description string                    `json:"description,omitempty"`
+	Events      []models.WebhookEventType `json:"events" binding:"required"`
+	Enabled     *bool                     `json:"enabled,omitempty"`
+}
+
+// WebhookResponse represents a webhook. Secret is only set when the
+// webhook is created.
+type WebhookResponse struct {
+	ID                  string                    `json:"id"`
+	URL                 string                    `json:"url"`
+	Description         string                    `json:"description,omitempty"`
+	Events              []models.WebhookEventType `json:"events"`
+	Secret              string                    `json:"secret,omitempty"`
+	Enabled             bool                      `json:"enabled"`
+	ConsecutiveFailures int                       `json:"consecutiveFailures"`
+	DisabledAt          *time.Time                `json:"disabledAt,omitempty"`
+	DisabledReason      string                    `json:"disabledReason,omitempty"`
+	CreatedAt           time.Time                 `json:"createdAt"`
+	UpdatedAt           time.Time                 `json:"updatedAt"`
+}
+
+// ListWebhooksResponse represents the webhooks of a user
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// DeliveryResponse represents a delivery of an event to a webhook and its
+// attempts
+type DeliveryResponse struct {
+	ID            string                       `json:"id"`
+	WebhookID     string                       `json:"webhookId"`
+	EventID       string                       `json:"eventId"`
+	EventType     models.WebhookEventType      `json:"eventType"`
+	Payload       json.RawMessage              `json:"payload"`
+	RedeliveryOf  string                       `json:"redeliveryOf,omitempty"`
+	Status        models.WebhookDeliveryStatus `json:"status"`
+	Attempts      []models.WebhookAttempt      `json:"attempts"`
+	NextAttemptAt *time.Time                   `json:"nextAttemptAt,omitempty"`
+	DeliveredAt   *time.Time                   `json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time                    `json:"createdAt"`
+}
+
+// ListDeliveriesResponse represents a page of deliveries
+type ListDeliveriesResponse struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+}
+
+// Event is the JSON body posted to webhooks. ID is the same for every
+// delivery of an event, so receivers can drop the ones they already
+// handled.
+type Event struct {
+	ID        string                  `json:"id"`
+	Type      models.WebhookEventType `json:"type"`
+	CreatedAt time.Time               `json:"createdAt"`
+	Data      EventData               `json:"data"`
+}
+
+// EventData describes the email an event is about. Fields that don't
+// apply to the type of the event are left out.
+type EventData struct {
+	MessageID string `json:"messageId,omitempty"`
+	EmailID   string `json:"emailId,omitempty"` // Set for emails sent through the queue
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+	Recipient string `json:"recipient,omitempty"` // The recipient the event is about
+
+	// Failed sends
+	Error string `json:"error,omitempty"`
+
+	// Bounces
+	Status     string `json:"status,omitempty"` // Enhanced status code, such as 5.1.1
+	Diagnostic string `json:"diagnostic,omitempty"`
+	Hard       bool   `json:"hard,omitempty"`
+
+	// Opens and clicks
+	URL        string `json:"url,omitempty"`
+	IP         string `json:"ip,omitempty"`
+	UserAgent  string `json:"userAgent,omitempty"`
+	Proxy      string `json:"proxy,omitempty"`
+	Prefetched bool   `json:"prefetched,omitempty"`
+
+	// Unsubscribes
+	List string `json:"list,omitempty"`
+
+	OccurredAt time.Time `json:"occurredAt"`
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"GoMail/app/repository/models"
+	webhookRepo "GoMail/app/repository/webhook"
+)
+
+// Create subscribes a webhook to events. It is enabled from the start.
+func (s *service) Create(ctx context.Context, userID string, req WebhookRequest) (*WebhookResponse, error) {
+	webhookURL, err := s.validateURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	events, err := validateEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindUserWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxWebhooks {
+		return nil, fmt.Errorf("%w: at most %d webhooks per user", ErrInvalidWebhook, MaxWebhooks)
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook := &models.Webhook{
+		UserID:      userID,
+		URL:         webhookURL,
+		Description: req.Description,
+		Events:      events,
+		Secret:      secret,
+		Enabled:     true,
+	}
+	if err := s.repo.SaveWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	resp := toResponse(webhook)
+	resp.Secret = webhook.Secret
+	return resp, nil
+}
+
+// Get returns a webhook
+func (s *service) Get(ctx context.Context, userID, id string) (*WebhookResponse, error) {
+	webhook, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(webhook), nil
+}
+
+// List returns the webhooks of a user, oldest first
+func (s *service) List(ctx context.Context, userID string) (*ListWebhooksResponse, error) {
+	webhooks, err := s.repo.FindUserWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListWebhooksResponse{Webhooks: make([]WebhookResponse, 0, len(webhooks))}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, *toResponse(webhook))
+	}
+	return resp, nil
+}
+
+// Update changes a webhook. Enabling a disabled webhook resets its failure
+// count; deliveries that failed while it was disabled can be redelivered.
+func (s *service) Update(ctx context.Context, userID, id string, req UpdateRequest) (*WebhookResponse, error) {
+	webhook, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.URL, err = s.validateURL(req.URL); err != nil {
+		return nil, err
+	}
+	if webhook.Events, err = validateEvents(req.Events); err != nil {
+		return nil, err
+	}
+	webhook.Description = req.Description
+
+	if req.Enabled != nil && *req.Enabled != webhook.Enabled {
+		webhook.Enabled = *req.Enabled
+		if webhook.Enabled {
+			webhook.ConsecutiveFailures = 0
+			webhook.DisabledAt = nil
+			webhook.DisabledReason = ""
+		}
+	}
+
+	if err := s.repo.UpdateWebhook(ctx, webhook); err != nil {
+		if errors.Is(err, webhookRepo.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return toResponse(webhook), nil
+}
+
+// Delete removes a webhook and its deliveries, including the ones that
+// are still pending
+func (s *service) Delete(ctx context.Context, userID, id string) error {
+	webhook, err := s.find(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteWebhook(ctx, webhook.ID); err != nil {
+		if errors.Is(err, webhookRepo.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return s.repo.DeleteWebhookDeliveries(ctx, webhook.ID)
+}
@@ -0,0 +1,247 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "GoMail/app/repository/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	webhook "GoMail/app/logic/webhook"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, userID, req
+func (_m *Service) Create(ctx context.Context, userID string, req webhook.WebhookRequest) (*webhook.WebhookResponse, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *webhook.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, webhook.WebhookRequest) (*webhook.WebhookResponse, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, webhook.WebhookRequest) *webhook.WebhookResponse); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, webhook.WebhookRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Delete(ctx context.Context, userID string, id string) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, userID, id
+func (_m *Service) Get(ctx context.Context, userID string, id string) (*webhook.WebhookResponse, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *webhook.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*webhook.WebhookResponse, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *webhook.WebhookResponse); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userID
+func (_m *Service) List(ctx context.Context, userID string) (*webhook.ListWebhooksResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *webhook.ListWebhooksResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*webhook.ListWebhooksResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *webhook.ListWebhooksResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.ListWebhooksResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, userID, id, status, page, limit
+func (_m *Service) ListDeliveries(ctx context.Context, userID string, id string, status models.WebhookDeliveryStatus, page int, limit int) (*webhook.ListDeliveriesResponse, error) {
+	ret := _m.Called(ctx, userID, id, status, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 *webhook.ListDeliveriesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.WebhookDeliveryStatus, int, int) (*webhook.ListDeliveriesResponse, error)); ok {
+		return rf(ctx, userID, id, status, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.WebhookDeliveryStatus, int, int) *webhook.ListDeliveriesResponse); ok {
+		r0 = rf(ctx, userID, id, status, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.ListDeliveriesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, models.WebhookDeliveryStatus, int, int) error); ok {
+		r1 = rf(ctx, userID, id, status, page, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, userID, eventType, data
+func (_m *Service) Publish(ctx context.Context, userID string, eventType models.WebhookEventType, data webhook.EventData) error {
+	ret := _m.Called(ctx, userID, eventType, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.WebhookEventType, webhook.EventData) error); ok {
+		r0 = rf(ctx, userID, eventType, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Redeliver provides a mock function with given fields: ctx, userID, deliveryID
+func (_m *Service) Redeliver(ctx context.Context, userID string, deliveryID string) (*webhook.DeliveryResponse, error) {
+	ret := _m.Called(ctx, userID, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Redeliver")
+	}
+
+	var r0 *webhook.DeliveryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*webhook.DeliveryResponse, error)); ok {
+		return rf(ctx, userID, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *webhook.DeliveryResponse); ok {
+		r0 = rf(ctx, userID, deliveryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.DeliveryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, userID, id, req
+func (_m *Service) Update(ctx context.Context, userID string, id string, req webhook.UpdateRequest) (*webhook.WebhookResponse, error) {
+	ret := _m.Called(ctx, userID, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *webhook.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, webhook.UpdateRequest) (*webhook.WebhookResponse, error)); ok {
+		return rf(ctx, userID, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, webhook.UpdateRequest) *webhook.WebhookResponse); ok {
+		r0 = rf(ctx, userID, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, webhook.UpdateRequest) error); ok {
+		r1 = rf(ctx, userID, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Service {
+	mock := &Service{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
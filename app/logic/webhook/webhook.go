@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"GoMail/app/config"
+	"GoMail/app/libs/fetch"
+	"GoMail/app/repository"
+	"GoMail/app/repository/models"
+	webhookRepo "GoMail/app/repository/webhook"
+)
+
+var (
+	ErrInvalidWebhook   = errors.New("invalid webhook")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookDisabled  = errors.New("webhook is disabled")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// MaxWebhooks is the most webhooks a user can subscribe
+	MaxWebhooks = 20
+)
+
+// Service defines the interface for webhook subscriptions. Events about
+// the emails of a user are queued in an outbox for each webhook subscribed
+// to them and posted by the Dispatcher.
+type Service interface {
+	// Create subscribes a webhook. The response carries the signing
+	// secret, which isn't returned again.
+	Create(ctx context.Context, userID string, req WebhookRequest) (*WebhookResponse, error)
+
+	// Get returns a webhook
+	Get(ctx context.Context, userID, id string) (*WebhookResponse, error)
+
+	// List returns the webhooks of a user, oldest first
+	List(ctx context.Context, userID string) (*ListWebhooksResponse, error)
+
+	// Update changes the URL, description and events of a webhook, and
+	// enables or disables it
+	Update(ctx context.Context, userID, id string, req UpdateRequest) (*WebhookResponse, error)
+
+	// Delete removes a webhook and its deliveries
+	Delete(ctx context.Context, userID, id string) error
+
+	// ListDeliveries returns the deliveries of a webhook, newest first
+	ListDeliveries(ctx context.Context, userID, id string, status models.WebhookDeliveryStatus, page, limit int) (*ListDeliveriesResponse, error)
+
+	// Redeliver queues a delivery again with the same event ID and payload
+	Redeliver(ctx context.Context, userID, deliveryID string) (*DeliveryResponse, error)
+
+	// Publish queues an event for the enabled webhooks of a user that
+	// subscribe to it
+	Publish(ctx context.Context, userID string, eventType models.WebhookEventType, data EventData) error
+}
+
+// service implements the Service interface
+type service struct {
+	repo   repository.Repository
+	config *config.Config
+}
+
+// New creates a new webhook service
+func New(repo repository.Repository, cfg *config.Config) Service {
+	return &service{
+		repo:   repo,
+		config: cfg,
+	}
+}
+
+// find returns a webhook of a user. Webhooks of other users are not found.
+func (s *service) find(ctx context.Context, userID, id string) (*models.Webhook, error) {
+	webhook, err := s.repo.FindWebhookByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, webhookRepo.ErrWebhookNotFound) || errors.Is(err, webhookRepo.ErrInvalidID) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	if webhook.UserID != userID {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// validateURL checks that a webhook URL is an absolute http or https URL.
+// Addresses on loopback and private networks are refused unless allowed;
+// host names are checked again for every delivery, once resolved.
+func (s *service) validateURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if u.User != nil {
+		return "", fmt.Errorf("%w: url must not contain credentials", ErrInvalidWebhook)
+	}
+
+	if !s.config.Webhooks.AllowPrivate {
+		host := strings.ToLower(u.Hostname())
+		ip := net.ParseIP(host)
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") || (ip != nil && !fetch.IsPublic(ip)) {
+			return "", fmt.Errorf("%w: url must be on a public address", ErrInvalidWebhook)
+		}
+	}
+	return u.String(), nil
+}
+
+// validateEvents checks the events of a webhook, dropping duplicates
+func validateEvents(events []models.WebhookEventType) ([]models.WebhookEventType, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: events must not be empty", ErrInvalidWebhook)
+	}
+
+	seen := make(map[models.WebhookEventType]bool, len(events))
+	result := make([]models.WebhookEventType, 0, len(events))
+	for _, event := range events {
+		if !validEvent(event) {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+// validEvent reports whether webhooks can subscribe to an event type
+func validEvent(eventType models.WebhookEventType) bool {
+	for _, known := range models.WebhookEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// newSecret returns a random secret for signing the payloads of a webhook
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature of a payload sent at a Unix timestamp: the
+// hex encoded HMAC-SHA256 of the timestamp, a dot and the payload, keyed
+// with the secret of the webhook. Receivers compute it the same way and
+// compare it with the X-GoMail-Signature header.
+func Sign(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// toResponse converts a webhook into its API representation, without its
+// secret
+func toResponse(webhook *models.Webhook) *WebhookResponse {
+	return &WebhookResponse{
+		ID:                  webhook.ID.Hex(),
+		URL:                 webhook.URL,
+		Description:         webhook.Description,
+		Events:              webhook.Events,
+		Enabled:             webhook.Enabled,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		DisabledAt:          webhook.DisabledAt,
+		DisabledReason:      webhook.DisabledReason,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+}
@@ -0,0 +1,297 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"GoMail/app/config"
+	repoMocks "GoMail/app/repository/mocks"
+	"GoMail/app/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newConfig() *config.Config {
+	return &config.Config{Webhooks: config.WebhooksConfig{
+		Timeout:       5 * time.Second,
+		PollInterval:  time.Second,
+		LeaseDuration: time.Minute,
+		MaxAttempts:   3,
+		RetryBackoff:  time.Minute,
+		MaxBackoff:    time.Hour,
+		DisableAfter:  5,
+		AllowPrivate:  true,
+	}}
+}
+
+func TestService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     WebhookRequest
+		private bool
+		wantErr error
+	}{
+		{
+			name: "valid",
+			req:  WebhookRequest{URL: "https://hooks.example.com/gomail", Events: []models.WebhookEventType{models.WebhookEventSent, models.WebhookEventSent}},
+		},
+		{
+			name:    "unknown event",
+			req:     WebhookRequest{URL: "https://hooks.example.com/gomail", Events: []models.WebhookEventType{"delivered"}},
+			wantErr: ErrInvalidWebhook,
+		},
+		{
+			name:    "no events",
+			req:     WebhookRequest{URL: "https://hooks.example.com/gomail"},
+			wantErr: ErrInvalidWebhook,
+		},
+		{
+			name:    "not http",
+			req:     WebhookRequest{URL: "ftp://hooks.example.com", Events: []models.WebhookEventType{models.WebhookEventSent}},
+			wantErr: ErrInvalidWebhook,
+		},
+		{
+			name:    "private address",
+			req:     WebhookRequest{URL: "http://10.0.0.5/hook", Events: []models.WebhookEventType{models.WebhookEventSent}},
+			private: false,
+			wantErr: ErrInvalidWebhook,
+		},
+		{
+			name:    "private address allowed",
+			req:     WebhookRequest{URL: "http://10.0.0.5/hook", Events: []models.WebhookEventType{models.WebhookEventSent}},
+			private: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			cfg.Webhooks.AllowPrivate = tt.private
+			repo := &repoMocks.Repository{}
+			repo.On("FindUserWebhooks", mock.Anything, "user-1").Return([]*models.Webhook{}, nil)
+			repo.On("SaveWebhook", mock.Anything, mock.Anything).Return(nil)
+
+			got, err := New(repo, cfg).Create(context.Background(), "user-1", tt.req)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				repo.AssertNotCalled(t, "SaveWebhook", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(got.Secret, "whsec_"))
+			assert.True(t, got.Enabled)
+			assert.Equal(t, []models.WebhookEventType{tt.req.Events[0]}, got.Events)
+		})
+	}
+}
+
+func TestService_Update_Enable(t *testing.T) {
+	id := primitive.NewObjectID()
+	disabledAt := time.Now()
+	webhook := &models.Webhook{
+		ID:                  id,
+		UserID:              "user-1",
+		URL:                 "https://hooks.example.com/gomail",
+		Events:              []models.WebhookEventType{models.WebhookEventSent},
+		ConsecutiveFailures: 20,
+		DisabledAt:          &disabledAt,
+		DisabledReason:      "20 delivery attempts failed in a row",
+	}
+	repo := &repoMocks.Repository{}
+	repo.On("FindWebhookByID", mock.Anything, id.Hex()).Return(webhook, nil)
+	repo.On("UpdateWebhook", mock.Anything, webhook).Return(nil)
+
+	enabled := true
+	got, err := New(repo, newConfig()).Update(context.Background(), "user-1", id.Hex(), UpdateRequest{
+		URL:     webhook.URL,
+		Events:  []models.WebhookEventType{models.WebhookEventBounced},
+		Enabled: &enabled,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, got.Enabled)
+	assert.Zero(t, got.ConsecutiveFailures)
+	assert.Nil(t, got.DisabledAt)
+	assert.Equal(t, []models.WebhookEventType{models.WebhookEventBounced}, got.Events)
+
+	_, err = New(repo, newConfig()).Get(context.Background(), "user-2", id.Hex())
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestService_Publish(t *testing.T) {
+	hooks := []*models.Webhook{{ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}}
+	var deliveries []*models.WebhookDelivery
+	repo := &repoMocks.Repository{}
+	repo.On("FindSubscribedWebhooks", mock.Anything, "user-1", models.WebhookEventOpened).Return(hooks, nil)
+	repo.On("SaveWebhookDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		deliveries = append(deliveries, args.Get(1).(*models.WebhookDelivery))
+	}).Return(nil)
+
+	err := New(repo, newConfig()).Publish(context.Background(), "user-1", models.WebhookEventOpened, EventData{MessageID: "abc@example.com", Recipient: "ada@example.org"})
+
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, hooks[1].ID, deliveries[1].WebhookID)
+	assert.Equal(t, deliveries[0].EventID, deliveries[1].EventID)
+	assert.Equal(t, deliveries[0].Payload, deliveries[1].Payload)
+	assert.Equal(t, models.WebhookDeliveryPending, deliveries[0].Status)
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(deliveries[0].Payload), &event))
+	assert.Equal(t, deliveries[0].EventID, event.ID)
+	assert.Equal(t, models.WebhookEventOpened, event.Type)
+	assert.Equal(t, "ada@example.org", event.Data.Recipient)
+	assert.False(t, event.Data.OccurredAt.IsZero())
+
+	// Emails without a user have no webhooks
+	require.NoError(t, New(&repoMocks.Repository{}, newConfig()).Publish(context.Background(), "", models.WebhookEventSent, EventData{}))
+}
+
+func TestService_Redeliver(t *testing.T) {
+	webhook := &models.Webhook{ID: primitive.NewObjectID(), UserID: "user-1", Enabled: true}
+	original := &models.WebhookDelivery{
+		ID:        primitive.NewObjectID(),
+		WebhookID: webhook.ID,
+		UserID:    "user-1",
+		EventID:   "event-1",
+		EventType: models.WebhookEventSent,
+		Payload:   `{"id":"event-1"}`,
+		Status:    models.WebhookDeliveryFailed,
+	}
+	repo := &repoMocks.Repository{}
+	repo.On("FindWebhookDeliveryByID", mock.Anything, original.ID.Hex()).Return(original, nil)
+	repo.On("FindWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil)
+	repo.On("SaveWebhookDelivery", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.EventID == "event-1" && d.Payload == original.Payload && *d.RedeliveryOf == original.ID &&
+			d.Status == models.WebhookDeliveryPending
+	})).Return(nil).Once()
+	s := New(repo, newConfig())
+
+	got, err := s.Redeliver(context.Background(), "user-1", original.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, original.ID.Hex(), got.RedeliveryOf)
+
+	_, err = s.Redeliver(context.Background(), "user-2", original.ID.Hex())
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+
+	webhook.Enabled = false
+	_, err = s.Redeliver(context.Background(), "user-1", original.ID.Hex())
+	assert.ErrorIs(t, err, ErrWebhookDisabled)
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_ProcessNext(t *testing.T) {
+	payload := `{"id":"event-1","type":"sent"}`
+	var received *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received, body = r, string(raw)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{ID: primitive.NewObjectID(), URL: server.URL, Secret: "whsec_test", Enabled: true}
+	delivery := &models.WebhookDelivery{
+		ID:        primitive.NewObjectID(),
+		WebhookID: webhook.ID,
+		EventID:   "event-1",
+		EventType: models.WebhookEventSent,
+		Payload:   payload,
+		Status:    models.WebhookDeliveryProcessing,
+		Attempts:  1,
+	}
+	repo := &repoMocks.Repository{}
+	repo.On("ClaimWebhookDelivery", mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(delivery, nil).Once()
+	repo.On("FindWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil)
+	repo.On("RecordWebhookResult", mock.Anything, webhook.ID, true, 5, mock.Anything).Return(webhook, nil).Once()
+	repo.On("ReleaseWebhookDelivery", mock.Anything, delivery, mock.Anything).Return(nil).Once()
+
+	processed, err := NewDispatcher(newConfig(), repo).processNext(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, processed)
+	require.NotNil(t, received)
+	assert.Equal(t, payload, body)
+	timestamp := received.Header.Get("X-GoMail-Timestamp")
+	assert.NotEmpty(t, timestamp)
+	assert.Equal(t, Sign("whsec_test", timestamp, payload), received.Header.Get("X-GoMail-Signature"))
+	assert.Equal(t, "event-1", received.Header.Get("X-GoMail-Event-Id"))
+	assert.Equal(t, "sent", received.Header.Get("X-GoMail-Event"))
+
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivery.Status)
+	require.Len(t, delivery.AttemptLog, 1)
+	assert.Equal(t, http.StatusOK, delivery.AttemptLog[0].StatusCode)
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_ProcessNext_Disables(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{ID: primitive.NewObjectID(), URL: server.URL, Enabled: true, ConsecutiveFailures: 4}
+	delivery := &models.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID, Payload: "{}", Attempts: 1}
+	disabled := *webhook
+	disabled.Enabled = false
+	disabled.ConsecutiveFailures = 5
+
+	repo := &repoMocks.Repository{}
+	repo.On("ClaimWebhookDelivery", mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(delivery, nil).Once()
+	repo.On("FindWebhookByID", mock.Anything, webhook.ID.Hex()).Return(webhook, nil)
+	repo.On("RecordWebhookResult", mock.Anything, webhook.ID, false, 5, mock.Anything).Return(&disabled, nil).Once()
+	repo.On("ReleaseWebhookDelivery", mock.Anything, delivery, mock.Anything).Return(nil).Once()
+
+	_, err := NewDispatcher(newConfig(), repo).processNext(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, http.StatusInternalServerError, delivery.AttemptLog[0].StatusCode)
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_BlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private webhook was called")
+	}))
+	defer server.Close()
+
+	cfg := newConfig()
+	cfg.Webhooks.AllowPrivate = false
+	d := NewDispatcher(cfg, &repoMocks.Repository{})
+
+	attempt := d.post(context.Background(), &models.Webhook{URL: server.URL}, &models.WebhookDelivery{Payload: "{}"})
+
+	assert.Contains(t, attempt.Error, "blocked webhook address")
+}
+
+func TestDispatcher_ApplyResult(t *testing.T) {
+	d := NewDispatcher(newConfig(), &repoMocks.Repository{})
+	now := time.Now()
+	failed := models.WebhookAttempt{StatusCode: 500, Error: "webhook responded with status 500"}
+
+	delivered := &models.WebhookDelivery{Attempts: 1}
+	d.applyResult(delivered, models.WebhookAttempt{StatusCode: 200}, true, now)
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivered.Status)
+	assert.Equal(t, &now, delivered.DeliveredAt)
+
+	exhausted := &models.WebhookDelivery{Attempts: 3}
+	d.applyResult(exhausted, failed, true, now)
+	assert.Equal(t, models.WebhookDeliveryFailed, exhausted.Status)
+
+	retried := &models.WebhookDelivery{Attempts: 2}
+	d.applyResult(retried, failed, true, now)
+	assert.Equal(t, models.WebhookDeliveryPending, retried.Status)
+	assert.Equal(t, now.Add(2*time.Minute), retried.NextAttemptAt)
+	assert.Len(t, retried.AttemptLog, 1)
+}
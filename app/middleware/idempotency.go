@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/idempotency"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the key a client retries a request with
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader marks a response replayed from a key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// memoryBodySize is how much of a request body is buffered in memory
+	// before it is spooled to a temporary file
+	memoryBodySize = 1 << 20
+
+	// completeTimeout bounds storing a response after the client is gone
+	completeTimeout = 10 * time.Second
+)
+
+// Idempotency replays the first response to POST requests sent with an
+// Idempotency-Key header, unless it was a server error. The key is reserved before the request is
+// handled, so concurrent retries are rejected with 409 Conflict, and a key
+// reused with a different method, path or body is rejected with 422. It
+// must run after VerifyAuthToken, since keys are scoped to the user.
+func Idempotency(service idempotency.Service, cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+		userID := c.GetString("userID")
+
+		body, hash, err := bufferBody(c.Request, cfg.Idempotency.MaxBodySize)
+		defer body.close()
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+
+		stored, err := service.Begin(c.Request.Context(), userID, key, idempotency.Request{
+			Method: c.Request.Method,
+			Path:   c.Request.URL.RequestURI(),
+			Hash:   hash,
+		})
+		if err != nil {
+			writeIdempotencyError(c, err)
+			return
+		}
+		if stored != nil {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		if c.Request.Body, err = body.reader(); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		// Clients retry when a connection drops, so the response is stored
+		// even when the request context is canceled
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), completeTimeout)
+		defer cancel()
+
+		// Server errors are usually transient, so the key is released for
+		// the retry to handle the request again instead of replaying them
+		if writer.Status() >= http.StatusInternalServerError {
+			if err := service.Release(ctx, userID, key); err != nil {
+				log.Printf("Failed to release idempotency key %q: %v", key, err)
+			}
+			return
+		}
+
+		err = service.Complete(ctx, userID, key, idempotency.Response{
+			StatusCode:  writer.Status(),
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		})
+		if err != nil {
+			log.Printf("Failed to store response for idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// writeIdempotencyError aborts a request whose key can't be used
+func writeIdempotencyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, idempotency.ErrInvalidKey):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, idempotency.ErrInProgress):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, idempotency.ErrKeyReused):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// bufferBody reads the body of r, up to limit bytes, and returns a copy of
+// it along with a digest of the method, URI and body
+func bufferBody(r *http.Request, limit int64) (*spool, string, error) {
+	body := &spool{}
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+
+	if r.Body != nil {
+		defer r.Body.Close()
+		if _, err := io.Copy(io.MultiWriter(body, hash), http.MaxBytesReader(nil, r.Body, limit)); err != nil {
+			return body, "", err
+		}
+	}
+
+	return body, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// spool holds a request body in memory, moving it to a temporary file once
+// it outgrows memoryBodySize
+type spool struct {
+	memory bytes.Buffer
+	file   *os.File
+}
+
+func (s *spool) Write(p []byte) (int, error) {
+	if s.file == nil && s.memory.Len()+len(p) <= memoryBodySize {
+		return s.memory.Write(p)
+	}
+	if s.file == nil {
+		file, err := os.CreateTemp("", "gomail-body-*")
+		if err != nil {
+			return 0, err
+		}
+		s.file = file
+		if _, err := s.file.Write(s.memory.Bytes()); err != nil {
+			return 0, err
+		}
+		s.memory.Reset()
+	}
+	return s.file.Write(p)
+}
+
+// reader returns the buffered body from the start
+func (s *spool) reader() (io.ReadCloser, error) {
+	if s.file == nil {
+		return io.NopCloser(bytes.NewReader(s.memory.Bytes())), nil
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(s.file), nil
+}
+
+// close removes the temporary file, if any
+func (s *spool) close() {
+	if s.file != nil {
+		s.file.Close()
+		os.Remove(s.file.Name())
+	}
+}
+
+// recordingWriter keeps a copy of the response body
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
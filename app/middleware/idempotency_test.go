@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"GoMail/app/config"
+	"GoMail/app/logic/idempotency"
+	"GoMail/app/logic/idempotency/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newIdempotentRouter serves POST /send behind the Idempotency middleware,
+// echoing the request body and counting the requests it handles
+func newIdempotentRouter(service idempotency.Service, maxBodySize int64, handled *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("userID", "user-1") })
+	router.Use(Idempotency(service, &config.Config{Idempotency: config.IdempotencyConfig{MaxBodySize: maxBodySize}}))
+	router.POST("/send", func(c *gin.Context) {
+		*handled++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(http.StatusAccepted, gin.H{"body": string(body)})
+	})
+	router.GET("/send", func(c *gin.Context) {
+		*handled++
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestIdempotency(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		key          string
+		body         string
+		maxBodySize  int64
+		setupMock    func(*mocks.Service)
+		wantStatus   int
+		wantBody     string
+		wantHandled  int
+		wantReplayed bool
+	}{
+		{
+			name:        "no key",
+			method:      "POST",
+			body:        "hello",
+			setupMock:   func(s *mocks.Service) {},
+			wantStatus:  http.StatusAccepted,
+			wantBody:    `{"body":"hello"}`,
+			wantHandled: 1,
+		},
+		{
+			name:        "not a POST",
+			method:      "GET",
+			key:         "key-1",
+			setupMock:   func(s *mocks.Service) {},
+			wantStatus:  http.StatusOK,
+			wantHandled: 1,
+		},
+		{
+			name:   "first request",
+			method: "POST",
+			key:    "key-1",
+			body:   "hello",
+			setupMock: func(s *mocks.Service) {
+				s.On("Begin", mock.Anything, "user-1", "key-1", mock.MatchedBy(func(r idempotency.Request) bool {
+					return r.Method == "POST" && r.Path == "/send" && len(r.Hash) == 64
+				})).Return(nil, nil)
+				s.On("Complete", mock.Anything, "user-1", "key-1", idempotency.Response{
+					StatusCode:  http.StatusAccepted,
+					ContentType: "application/json; charset=utf-8",
+					Body:        []byte(`{"body":"hello"}`),
+				}).Return(nil)
+			},
+			wantStatus:  http.StatusAccepted,
+			wantBody:    `{"body":"hello"}`,
+			wantHandled: 1,
+		},
+		{
+			name:   "large body",
+			method: "POST",
+			key:    "key-1",
+			body:   strings.Repeat("x", memoryBodySize+10),
+			setupMock: func(s *mocks.Service) {
+				s.On("Begin", mock.Anything, "user-1", "key-1", mock.Anything).Return(nil, nil)
+				s.On("Complete", mock.Anything, "user-1", "key-1", mock.Anything).Return(nil)
+			},
+			wantStatus:  http.StatusAccepted,
+			wantBody:    `{"body":"` + strings.Repeat("x", memoryBodySize+10) + `"}`,
+			wantHandled: 1,
+		},
+		{
+			name:   "replay",
+			method: "POST",
+			key:    "key-1",
+			body:   "hello",
+			setupMock: func(s *mocks.Service) {
+				s.On("Begin", mock.Anything, "user-1", "key-1", mock.Anything).
+					Return(&idempotency.Response{StatusCode: http.StatusAccepted, ContentType: "application/json", Body: []byte(`{"body":"first"}`)}, nil)
+			},
+			wantStatus:   http.StatusAccepted,
+			wantBody:     `{"body":"first"}`,
+			wantReplayed: true,
+		},
+		{
+			name:   "in progress",
+			method: "POST",
+			key:    "key-1",
+			setupMock: func(s *mocks.Service) {
+				s.On("Begin", mock.Anything, "user-1", "key-1", mock.Anything).Return(nil, idempotency.ErrInProgress)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "reused with another body",
+			method: "POST",
+			key:    "key-1",
+			setupMock: func(s *mocks.Service) {
+				s.On("Begin", mock.Anything, "user-1", "key-1", mock.Anything).Return(nil, idempotency.ErrKeyReused)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "invalid key",
+			method: "POST",
+			key:    "key-1",
+			setupMock: func(s *mocks.Service) {
+				s.On("Begin", mock.Anything, "user-1", "key-1", mock.Anything).Return(nil, idempotency.ErrInvalidKey)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "store unavailable",
+			method: "POST",
+			key:    "key-1",
+			setupMock: func(s *mocks.Service) {
+				s.On("Begin", mock.Anything, "user-1", "key-1", mock.Anything).Return(nil, errors.New("connection refused"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:        "body too large",
+			method:      "POST",
+			key:         "key-1",
+			body:        "hello",
+			maxBodySize: 4,
+			setupMock:   func(s *mocks.Service) {},
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assemble
+			service := &mocks.Service{}
+			tt.setupMock(service)
+			if tt.maxBodySize == 0 {
+				tt.maxBodySize = 64 << 20
+			}
+			handled := 0
+			router := newIdempotentRouter(service, tt.maxBodySize, &handled)
+
+			req := httptest.NewRequest(tt.method, "/send", strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+			}
+			assert.Equal(t, tt.wantHandled, handled)
+			assert.Equal(t, tt.wantReplayed, w.Header().Get(IdempotentReplayedHeader) == "true")
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotency_RetryAfterServerError(t *testing.T) {
+	// Assemble
+	service := &mocks.Service{}
+	service.On("Begin", mock.Anything, "user-1", "key-1", mock.Anything).Return(nil, nil).Twice()
+	service.On("Release", mock.Anything, "user-1", "key-1").Return(nil).Once()
+	service.On("Complete", mock.Anything, "user-1", "key-1", mock.MatchedBy(func(r idempotency.Response) bool {
+		return r.StatusCode == http.StatusOK
+	})).Return(nil).Once()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("userID", "user-1") })
+	router.Use(Idempotency(service, &config.Config{Idempotency: config.IdempotencyConfig{MaxBodySize: 1 << 20}}))
+	handled := 0
+	router.POST("/send", func(c *gin.Context) {
+		handled++
+		if handled == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "smtp unavailable"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/send", strings.NewReader("hello"))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act
+	first := send()
+	retry := send()
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusOK, retry.Code)
+	assert.Empty(t, retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, handled)
+	service.AssertExpectations(t)
+}
+
+func TestIdempotency_SameBodySameHash(t *testing.T) {
+	var hashes []string
+	service := &mocks.Service{}
+	service.On("Begin", mock.Anything, "user-1", mock.Anything, mock.MatchedBy(func(r idempotency.Request) bool {
+		hashes = append(hashes, r.Hash)
+		return true
+	})).Return(nil, nil)
+	service.On("Complete", mock.Anything, "user-1", mock.Anything, mock.Anything).Return(nil)
+
+	handled := 0
+	router := newIdempotentRouter(service, 1<<20, &handled)
+	for _, body := range []string{"hello", "hello", "goodbye"} {
+		req := httptest.NewRequest("POST", "/send", strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Len(t, hashes, 3)
+	assert.Equal(t, hashes[0], hashes[1])
+	assert.NotEqual(t, hashes[0], hashes[2])
+}
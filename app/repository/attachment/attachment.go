@@ -0,0 +1,73 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	CollectionName = "attachments"
+	BucketName     = "attachment_files"
+)
+
+var (
+	ErrInvalidID          = errors.New("invalid ID type")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrDuplicate          = errors.New("attachment with the same content exists")
+)
+
+// AttachmentRepository stores reusable attachments: metadata in a
+// collection and content in a GridFS bucket
+type AttachmentRepository interface {
+	Save(ctx context.Context, attachment *models.StoredAttachment) error
+	FindByID(ctx context.Context, id string) (*models.StoredAttachment, error)
+	FindByHash(ctx context.Context, userID, hash string) (*models.StoredAttachment, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.StoredAttachment, int64, error)
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*models.StoredAttachment, error)
+	SetExpiry(ctx context.Context, id primitive.ObjectID, expiresAt *time.Time) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	UploadFile(ctx context.Context, filename string, content io.Reader) (primitive.ObjectID, error)
+	OpenFile(ctx context.Context, fileID primitive.ObjectID) (io.ReadCloser, error)
+	DeleteFile(ctx context.Context, fileID primitive.ObjectID) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	database   *mongo.Database
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) AttachmentRepository {
+	return &mongoDB{
+		database:   database,
+		collection: database.Collection(CollectionName),
+	}
+}
+
+// bucket returns the GridFS bucket with the deadline of ctx. Buckets keep
+// their deadlines as state, so every operation gets its own.
+func (m *mongoDB) bucket(ctx context.Context) (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(m.database, options.GridFSBucket().SetName(BucketName))
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := bucket.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+		if err := bucket.SetWriteDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	return bucket, nil
+}
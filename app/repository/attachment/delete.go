@@ -0,0 +1,44 @@
+package attachment
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Delete removes the metadata of an attachment. The content is removed
+// separately with DeleteFile.
+func (m *mongoDB) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := m.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrAttachmentNotFound
+	}
+
+	return nil
+}
+
+// CreateIndexes creates the dedup index on user and content hash and the
+// index used to find expired attachments
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "sha256", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	})
+	return err
+}
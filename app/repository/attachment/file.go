@@ -0,0 +1,51 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+)
+
+// UploadFile streams content into GridFS and returns the ID of the file
+func (m *mongoDB) UploadFile(ctx context.Context, filename string, content io.Reader) (primitive.ObjectID, error) {
+	bucket, err := m.bucket(ctx)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	return bucket.UploadFromStream(filename, content)
+}
+
+// OpenFile opens the content of a file for streaming
+func (m *mongoDB) OpenFile(ctx context.Context, fileID primitive.ObjectID) (io.ReadCloser, error) {
+	bucket, err := m.bucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := bucket.OpenDownloadStream(fileID)
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return stream, nil
+}
+
+// DeleteFile removes a file and its chunks from GridFS
+func (m *mongoDB) DeleteFile(ctx context.Context, fileID primitive.ObjectID) error {
+	bucket, err := m.bucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = bucket.DeleteContext(ctx, fileID)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return ErrAttachmentNotFound
+	}
+	return err
+}
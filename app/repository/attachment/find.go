@@ -0,0 +1,91 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByID retrieves the metadata of an attachment by ID
+func (m *mongoDB) FindByID(ctx context.Context, id string) (*models.StoredAttachment, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	return m.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// FindByHash retrieves a user's attachment with the given content hash
+func (m *mongoDB) FindByHash(ctx context.Context, userID, hash string) (*models.StoredAttachment, error) {
+	return m.findOne(ctx, bson.M{"user_id": userID, "sha256": hash})
+}
+
+func (m *mongoDB) findOne(ctx context.Context, filter bson.M) (*models.StoredAttachment, error) {
+	attachment := &models.StoredAttachment{}
+	err := m.collection.FindOne(ctx, filter).Decode(attachment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// FindAll retrieves attachments with optional filtering and pagination,
+// newest first
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.StoredAttachment, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"created_at": -1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	attachments := make([]*models.StoredAttachment, 0)
+	if err := cursor.All(ctx, &attachments); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return attachments, total, nil
+}
+
+// FindExpired retrieves attachments whose expiry time has passed
+func (m *mongoDB) FindExpired(ctx context.Context, now time.Time, limit int) ([]*models.StoredAttachment, error) {
+	opts := options.Find().SetLimit(int64(limit)).SetSort(bson.M{"expires_at": 1})
+
+	cursor, err := m.collection.Find(ctx, bson.M{"expires_at": bson.M{"$lte": now}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	attachments := make([]*models.StoredAttachment, 0)
+	if err := cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,50 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Save stores the metadata of a new attachment. It fails with ErrDuplicate
+// when the user already stored the same content.
+func (m *mongoDB) Save(ctx context.Context, attachment *models.StoredAttachment) error {
+	attachment.CreatedAt = time.Now()
+
+	result, err := m.collection.InsertOne(ctx, attachment)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		attachment.ID = oid
+	}
+	return nil
+}
+
+// SetExpiry changes when an attachment expires, nil keeps it until deleted
+func (m *mongoDB) SetExpiry(ctx context.Context, id primitive.ObjectID, expiresAt *time.Time) error {
+	update := bson.M{"$set": bson.M{"expires_at": expiresAt}}
+	if expiresAt == nil {
+		update = bson.M{"$unset": bson.M{"expires_at": ""}}
+	}
+
+	result, err := m.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrAttachmentNotFound
+	}
+
+	return nil
+}
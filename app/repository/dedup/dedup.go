@@ -0,0 +1,33 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "send_fingerprints"
+
+var ErrClaimLost = errors.New("fingerprint claim expired and was taken over")
+
+// DedupRepository stores the fingerprints of recent sends
+type DedupRepository interface {
+	Claim(ctx context.Context, fingerprint *models.SendFingerprint) (bool, error)
+	Release(ctx context.Context, fingerprint *models.SendFingerprint) error
+	Extend(ctx context.Context, fingerprint *models.SendFingerprint, expiresAt time.Time) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) DedupRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
@@ -0,0 +1,90 @@
+package dedup
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Claim stores a fingerprint until it expires. It reports false when the
+// user already holds an unexpired claim on the same fingerprint.
+func (m *mongoDB) Claim(ctx context.Context, fingerprint *models.SendFingerprint) (bool, error) {
+	_, err := m.collection.InsertOne(ctx, fingerprint)
+	if err == nil {
+		return true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, err
+	}
+
+	// Take over a claim that expired but wasn't removed by the TTL
+	// monitor yet
+	filter := bson.M{
+		"user_id":    fingerprint.UserID,
+		"hash":       fingerprint.Hash,
+		"expires_at": bson.M{"$lte": fingerprint.CreatedAt},
+	}
+	update := bson.M{"$set": bson.M{
+		"created_at": fingerprint.CreatedAt,
+		"expires_at": fingerprint.ExpiresAt,
+	}}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// Release removes a claim, unless it was taken over since
+func (m *mongoDB) Release(ctx context.Context, fingerprint *models.SendFingerprint) error {
+	_, err := m.collection.DeleteOne(ctx, bson.M{
+		"user_id":    fingerprint.UserID,
+		"hash":       fingerprint.Hash,
+		"expires_at": fingerprint.ExpiresAt,
+	})
+	return err
+}
+
+// Extend moves the expiry of a claim, unless it was taken over since
+func (m *mongoDB) Extend(ctx context.Context, fingerprint *models.SendFingerprint, expiresAt time.Time) error {
+	filter := bson.M{
+		"user_id":    fingerprint.UserID,
+		"hash":       fingerprint.Hash,
+		"expires_at": fingerprint.ExpiresAt,
+	}
+	update := bson.M{"$set": bson.M{"expires_at": expiresAt}}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrClaimLost
+	}
+
+	fingerprint.ExpiresAt = expiresAt
+	return nil
+}
+
+// CreateIndexes makes fingerprints unique per user and removes them once
+// they expire
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
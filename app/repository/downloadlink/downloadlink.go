@@ -0,0 +1,43 @@
+package downloadlink
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "download_links"
+
+// retention is how long links are kept after they expire, so their
+// download counts can still be listed
+const retention = 30 * 24 * time.Hour
+
+var (
+	ErrInvalidID    = errors.New("invalid ID type")
+	ErrLinkNotFound = errors.New("download link not found")
+)
+
+// DownloadLinkRepository stores download links to attachments
+type DownloadLinkRepository interface {
+	Save(ctx context.Context, link *models.DownloadLink) error
+	FindByID(ctx context.Context, id string) (*models.DownloadLink, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.DownloadLink, int64, error)
+	RecordDownload(ctx context.Context, id primitive.ObjectID, now time.Time) (*models.DownloadLink, error)
+	Revoke(ctx context.Context, id primitive.ObjectID, now time.Time) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) DownloadLinkRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
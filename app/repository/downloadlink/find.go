@@ -0,0 +1,62 @@
+package downloadlink
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByID retrieves a download link by ID
+func (m *mongoDB) FindByID(ctx context.Context, id string) (*models.DownloadLink, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	link := &models.DownloadLink{}
+	if err := m.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(link); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrLinkNotFound
+		}
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// FindAll retrieves download links with optional filtering and pagination,
+// newest first
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.DownloadLink, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"created_at": -1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	links := make([]*models.DownloadLink, 0)
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return links, total, nil
+}
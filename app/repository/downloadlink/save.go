@@ -0,0 +1,84 @@
+package downloadlink
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Save stores a new download link
+func (m *mongoDB) Save(ctx context.Context, link *models.DownloadLink) error {
+	link.CreatedAt = time.Now()
+
+	result, err := m.collection.InsertOne(ctx, link)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		link.ID = oid
+	}
+	return nil
+}
+
+// RecordDownload counts a download of a link that is neither revoked nor
+// expired and returns the updated link. It fails with ErrLinkNotFound
+// otherwise.
+func (m *mongoDB) RecordDownload(ctx context.Context, id primitive.ObjectID, now time.Time) (*models.DownloadLink, error) {
+	filter := bson.M{
+		"_id":        id,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{
+		"$inc": bson.M{"downloads": 1},
+		"$set": bson.M{"last_download_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	link := &models.DownloadLink{}
+	if err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(link); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrLinkNotFound
+		}
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// Revoke disables a link. Revoking a revoked link keeps the first time.
+func (m *mongoDB) Revoke(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	result, err := m.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$min": bson.M{"revoked_at": now}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrLinkNotFound
+	}
+
+	return nil
+}
+
+// CreateIndexes creates the index used to list a user's links and removes
+// links some time after they expire
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(retention.Seconds())),
+		},
+	})
+	return err
+}
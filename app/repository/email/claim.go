@@ -0,0 +1,96 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Claim atomically leases the next email that is due for delivery.
+// Pending emails become claimable once next_attempt_at has passed, and
+// processing emails whose lease expired (e.g. the worker crashed) are
+// recovered. Returns nil when nothing is due.
+func (m *mongoDB) Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.Email, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{
+				"status":          models.EmailStatusPending,
+				"next_attempt_at": bson.M{"$lte": now},
+			},
+			{
+				"status":       models.EmailStatusProcessing,
+				"locked_until": bson.M{"$lt": now},
+			},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.EmailStatusProcessing,
+			"locked_by":    owner,
+			"locked_until": now.Add(lease),
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	email := &models.Email{}
+	err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return email, nil
+}
+
+// Release stores the outcome of a delivery attempt and drops the lease.
+// The write only applies while owner still holds the lease, so a worker
+// whose lease expired cannot overwrite the state set by its successor.
+func (m *mongoDB) Release(ctx context.Context, email *models.Email, owner string) error {
+	email.LockedBy = ""
+	email.LockedUntil = nil
+	email.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": email.ID, "locked_by": owner}
+	result, err := m.collection.ReplaceOne(ctx, filter, email)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// CreateIndexes creates the indexes used by the queue workers and scheduler
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "locked_until", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "send_at", Value: 1}},
+		},
+	}
+
+	_, err := m.collection.Indexes().CreateMany(ctx, indexModels)
+	return err
+}
@@ -7,8 +7,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Delete removes an email from the database
-func (m *mongoDB) Delete(ctx context.Context, id interface{}) error {
+// Delete removes an email from the database. Optional conditions are added
+// to the filter so the delete only applies while the email still matches
+// them (e.g. it has not been picked up for delivery yet).
+func (m *mongoDB) Delete(ctx context.Context, id interface{}, conditions ...bson.M) error {
 	// Convert ID to ObjectID if needed
 	var objectID primitive.ObjectID
 	switch v := id.(type) {
@@ -24,8 +26,15 @@ func (m *mongoDB) Delete(ctx context.Context, id interface{}) error {
 		return ErrInvalidID
 	}
 
+	filter := bson.M{"_id": objectID}
+	for _, condition := range conditions {
+		for key, value := range condition {
+			filter[key] = value
+		}
+	}
+
 	// Execute delete
-	result, err := m.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	result, err := m.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
 	}
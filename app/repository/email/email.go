@@ -3,9 +3,11 @@ package email
 import (
 	"context"
 	"errors"
+	"time"
 
 	"GoMail/app/repository/models"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -15,12 +17,19 @@ var (
 	ErrInvalidEmailType = errors.New("invalid email type")
 	ErrInvalidID        = errors.New("invalid ID type")
 	ErrEmailNotFound    = errors.New("email not found")
+	ErrLeaseLost        = errors.New("email lease is held by another worker")
 )
 
 type EmailRepository interface {
 	Save(ctx context.Context, email *models.Email) error
 	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Email, int64, error)
-	Delete(ctx context.Context, id interface{}) error
+	Delete(ctx context.Context, id interface{}, conditions ...bson.M) error
+	FindByID(ctx context.Context, id string) (*models.Email, error)
+	Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.Email, error)
+	Release(ctx context.Context, email *models.Email, owner string) error
+	PromoteDue(ctx context.Context, now time.Time) (int64, error)
+	Reschedule(ctx context.Context, id, userID string, sendAt time.Time) (*models.Email, error)
+	CreateIndexes(ctx context.Context) error
 }
 
 type mongoDB struct {
@@ -2,11 +2,13 @@ package email
 
 import (
 	"context"
+	"errors"
 
 	"GoMail/app/repository/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -59,4 +61,25 @@ func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit i
 	}
 
 	return emails, total, nil
-} 
\ No newline at end of file
+}
+
+// FindByID retrieves an email by ID
+func (m *mongoDB) FindByID(ctx context.Context, id string) (*models.Email, error) {
+	// Convert string ID to ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	// Execute query
+	email := &models.Email{}
+	err = m.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEmailNotFound
+		}
+		return nil, err
+	}
+
+	return email, nil
+}
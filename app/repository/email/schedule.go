@@ -0,0 +1,74 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PromoteDue moves scheduled emails whose send time has passed onto the
+// outbound queue. The status transition is a single atomic update, so
+// concurrent schedulers never hand the same email to the workers twice.
+func (m *mongoDB) PromoteDue(ctx context.Context, now time.Time) (int64, error) {
+	filter := bson.M{
+		"status":  models.EmailStatusScheduled,
+		"send_at": bson.M{"$lte": now},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     models.EmailStatusPending,
+			"updated_at": now,
+		},
+	}
+
+	result, err := m.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// Reschedule changes the send time of a scheduled email owned by userID.
+// Emails that were already promoted to the queue are not matched.
+func (m *mongoDB) Reschedule(ctx context.Context, id, userID string, sendAt time.Time) (*models.Email, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	filter := bson.M{
+		"_id":     objectID,
+		"user_id": userID,
+		"status":  models.EmailStatusScheduled,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"send_at":         sendAt,
+			"next_attempt_at": sendAt,
+			"updated_at":      time.Now(),
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	email := &models.Email{}
+	err = m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEmailNotFound
+		}
+		return nil, err
+	}
+
+	return email, nil
+}
@@ -0,0 +1,30 @@
+package emailevent
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "email_events"
+
+// EmailEventRepository stores the interactions of recipients with sent
+// emails
+type EmailEventRepository interface {
+	Save(ctx context.Context, event *models.EmailEvent) error
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.EmailEvent, int64, error)
+	FindByMessageID(ctx context.Context, userID, messageID string) ([]*models.EmailEvent, error)
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) EmailEventRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
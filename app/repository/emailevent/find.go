@@ -0,0 +1,58 @@
+package emailevent
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindAll retrieves events with optional filtering and pagination, newest
+// first
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.EmailEvent, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"occurred_at": -1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	events := make([]*models.EmailEvent, 0)
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// FindByMessageID retrieves all events of a message of a user, oldest first
+func (m *mongoDB) FindByMessageID(ctx context.Context, userID, messageID string) ([]*models.EmailEvent, error) {
+	opts := options.Find().SetSort(bson.M{"occurred_at": 1})
+
+	cursor, err := m.collection.Find(ctx, bson.M{"user_id": userID, "message_id": messageID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := make([]*models.EmailEvent, 0)
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,38 @@
+package emailevent
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Save stores a new event
+func (m *mongoDB) Save(ctx context.Context, event *models.EmailEvent) error {
+	result, err := m.collection.InsertOne(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		event.ID = oid
+	}
+	return nil
+}
+
+// CreateIndexes serves the reports of a message and the event list of a
+// user
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "message_id", Value: 1}, {Key: "occurred_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "occurred_at", Value: -1}},
+		},
+	})
+	return err
+}
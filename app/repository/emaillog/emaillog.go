@@ -6,6 +6,7 @@ import (
 
 	"GoMail/app/repository/models"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -21,6 +22,9 @@ type EmailLogRepository interface {
 	Save(ctx context.Context, emailLog *models.EmailLog) error
 	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.EmailLog, int64, error)
 	FindByID(ctx context.Context, id string) (*models.EmailLog, error)
+	FindByMessageID(ctx context.Context, messageID string) (*models.EmailLog, error)
+	AddFeedback(ctx context.Context, id primitive.ObjectID, status models.EmailLogStatus, feedback models.DeliveryFeedback) error
+	CreateIndexes(ctx context.Context) error
 }
 
 type mongoDB struct {
@@ -85,4 +85,18 @@ func (db *mongoDB) FindByID(ctx context.Context, id string) (*models.EmailLog, e
 	}
 
 	return emailLog, nil
-} 
\ No newline at end of file
+} 
+
+// FindByMessageID retrieves the email log of a sent message
+func (db *mongoDB) FindByMessageID(ctx context.Context, messageID string) (*models.EmailLog, error) {
+	emailLog := &models.EmailLog{}
+	err := db.collection.FindOne(ctx, bson.M{"message_id": messageID}).Decode(emailLog)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEmailLogNotFound
+		}
+		return nil, err
+	}
+
+	return emailLog, nil
+}
@@ -4,8 +4,12 @@ package mocks
 
 import (
 	context "context"
-	models "GoMail/app/repository/models"
+
 	mock "github.com/stretchr/testify/mock"
+
+	models "GoMail/app/repository/models"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // EmailLogRepository is an autogenerated mock type for the EmailLogRepository type
@@ -13,14 +17,53 @@ type EmailLogRepository struct {
 	mock.Mock
 }
 
+// AddFeedback provides a mock function with given fields: ctx, id, status, feedback
+func (_m *EmailLogRepository) AddFeedback(ctx context.Context, id primitive.ObjectID, status models.EmailLogStatus, feedback models.DeliveryFeedback) error {
+	ret := _m.Called(ctx, id, status, feedback)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddFeedback")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, models.EmailLogStatus, models.DeliveryFeedback) error); ok {
+		r0 = rf(ctx, id, status, feedback)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateIndexes provides a mock function with given fields: ctx
+func (_m *EmailLogRepository) CreateIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindAll provides a mock function with given fields: ctx, filter, page, limit
 func (_m *EmailLogRepository) FindAll(ctx context.Context, filter interface{}, page int, limit int) ([]*models.EmailLog, int64, error) {
 	ret := _m.Called(ctx, filter, page, limit)
 
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
 	var r0 []*models.EmailLog
 	var r1 int64
 	var r2 error
-
 	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.EmailLog, int64, error)); ok {
 		return rf(ctx, filter, page, limit)
 	}
@@ -51,9 +94,12 @@ func (_m *EmailLogRepository) FindAll(ctx context.Context, filter interface{}, p
 func (_m *EmailLogRepository) FindByID(ctx context.Context, id string) (*models.EmailLog, error) {
 	ret := _m.Called(ctx, id)
 
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
 	var r0 *models.EmailLog
 	var r1 error
-
 	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.EmailLog, error)); ok {
 		return rf(ctx, id)
 	}
@@ -74,10 +120,44 @@ func (_m *EmailLogRepository) FindByID(ctx context.Context, id string) (*models.
 	return r0, r1
 }
 
+// FindByMessageID provides a mock function with given fields: ctx, messageID
+func (_m *EmailLogRepository) FindByMessageID(ctx context.Context, messageID string) (*models.EmailLog, error) {
+	ret := _m.Called(ctx, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByMessageID")
+	}
+
+	var r0 *models.EmailLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.EmailLog, error)); ok {
+		return rf(ctx, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.EmailLog); ok {
+		r0 = rf(ctx, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: ctx, emailLog
 func (_m *EmailLogRepository) Save(ctx context.Context, emailLog *models.EmailLog) error {
 	ret := _m.Called(ctx, emailLog)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, *models.EmailLog) error); ok {
 		r0 = rf(ctx, emailLog)
@@ -88,7 +168,16 @@ func (_m *EmailLogRepository) Save(ctx context.Context, emailLog *models.EmailLo
 	return r0
 }
 
-// NewEmailLogRepository creates a new instance of EmailLogRepository
-func NewEmailLogRepository() *EmailLogRepository {
-	return &EmailLogRepository{}
-} 
\ No newline at end of file
+// NewEmailLogRepository creates a new instance of EmailLogRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailLogRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailLogRepository {
+	mock := &EmailLogRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -6,7 +6,10 @@ import (
 
 	"GoMail/app/repository/models"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // Save inserts or updates an email log in the database
@@ -24,4 +27,30 @@ func (db *mongoDB) Save(ctx context.Context, emailLog *models.EmailLog) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// AddFeedback records a bounce or complaint about a sent email and sets
+// its status
+func (db *mongoDB) AddFeedback(ctx context.Context, id primitive.ObjectID, status models.EmailLogStatus, feedback models.DeliveryFeedback) error {
+	result, err := db.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":  bson.M{"status": status},
+		"$push": bson.M{"feedback": feedback},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrEmailLogNotFound
+	}
+
+	return nil
+}
+
+// CreateIndexes creates the index bounces and complaints are matched with
+func (db *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := db.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "message_id", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+	return err
+}
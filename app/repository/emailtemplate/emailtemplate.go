@@ -0,0 +1,37 @@
+package emailtemplate
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "templates"
+
+var (
+	ErrInvalidID        = errors.New("invalid ID type")
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrVersionConflict  = errors.New("template was modified concurrently")
+)
+
+type TemplateRepository interface {
+	Save(ctx context.Context, template *models.Template) error
+	FindByID(ctx context.Context, id string) (*models.Template, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Template, int64, error)
+	Delete(ctx context.Context, id string) error
+	NextVersion(ctx context.Context, id string, latest int) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) TemplateRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
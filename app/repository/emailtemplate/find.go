@@ -0,0 +1,62 @@
+package emailtemplate
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByID retrieves a template by ID
+func (m *mongoDB) FindByID(ctx context.Context, id string) (*models.Template, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	template := &models.Template{}
+	err = m.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// FindAll retrieves templates with optional filtering and pagination
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Template, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"name": 1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	templates := make([]*models.Template, 0)
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return templates, total, nil
+}
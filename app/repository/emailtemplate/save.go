@@ -0,0 +1,71 @@
+package emailtemplate
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Save creates or updates a template in the database
+func (m *mongoDB) Save(ctx context.Context, template *models.Template) error {
+	now := time.Now()
+	template.UpdatedAt = now
+
+	if template.ID.IsZero() {
+		template.CreatedAt = now
+
+		result, err := m.collection.InsertOne(ctx, template)
+		if err != nil {
+			return err
+		}
+
+		if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+			template.ID = oid
+		}
+		return nil
+	}
+
+	result, err := m.collection.ReplaceOne(ctx, bson.M{"_id": template.ID}, template)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// NextVersion reserves the version after latest. It fails with
+// ErrVersionConflict when another request created a version first, so two
+// versions never share a number.
+func (m *mongoDB) NextVersion(ctx context.Context, id string, latest int) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	filter := bson.M{"_id": objectID, "latest_version": latest}
+	update := bson.M{
+		"$set": bson.M{
+			"latest_version": latest + 1,
+			"updated_at":     time.Now(),
+		},
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Find retrieves the record of a key sent by a user
+func (m *mongoDB) Find(ctx context.Context, userID, key string) (*models.IdempotencyRecord, error) {
+	record := &models.IdempotencyRecord{}
+	if err := m.collection.FindOne(ctx, bson.M{"user_id": userID, "key": key}).Decode(record); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,39 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "idempotency_keys"
+
+var (
+	ErrKeyExists      = errors.New("idempotency key already exists")
+	ErrRecordNotFound = errors.New("idempotency record not found")
+)
+
+// IdempotencyRepository stores the responses to requests sent with an
+// idempotency key
+type IdempotencyRepository interface {
+	Insert(ctx context.Context, record *models.IdempotencyRecord) error
+	Find(ctx context.Context, userID, key string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, userID, key string, response *models.StoredResponse, now time.Time) error
+	DeleteStale(ctx context.Context, userID, key string, now, lockedBefore time.Time) (bool, error)
+	Release(ctx context.Context, userID, key string) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) IdempotencyRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Insert reserves a key for a request. It fails with ErrKeyExists when the
+// user already sent a request with the key.
+func (m *mongoDB) Insert(ctx context.Context, record *models.IdempotencyRecord) error {
+	if _, err := m.collection.InsertOne(ctx, record); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrKeyExists
+		}
+		return err
+	}
+	return nil
+}
+
+// Complete stores the response to a request that is in progress
+func (m *mongoDB) Complete(ctx context.Context, userID, key string, response *models.StoredResponse, now time.Time) error {
+	filter := bson.M{"user_id": userID, "key": key, "status": models.IdempotencyInProgress}
+	update := bson.M{"$set": bson.M{
+		"status":       models.IdempotencyCompleted,
+		"response":     response,
+		"completed_at": now,
+	}}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Release removes a key whose request is still in progress, so that the
+// request can be retried with it
+func (m *mongoDB) Release(ctx context.Context, userID, key string) error {
+	filter := bson.M{"user_id": userID, "key": key, "status": models.IdempotencyInProgress}
+
+	if _, err := m.collection.DeleteOne(ctx, filter); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteStale removes a key that expired but wasn't removed by the TTL
+// monitor yet, or whose request started before lockedBefore and never
+// completed, for example because the server stopped while sending. It
+// reports whether a key was removed.
+func (m *mongoDB) DeleteStale(ctx context.Context, userID, key string, now, lockedBefore time.Time) (bool, error) {
+	filter := bson.M{
+		"user_id": userID,
+		"key":     key,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lte": now}},
+			bson.M{"status": models.IdempotencyInProgress, "created_at": bson.M{"$lt": lockedBefore}},
+		},
+	}
+
+	result, err := m.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// CreateIndexes makes keys unique per user and removes them once they
+// expire
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
@@ -0,0 +1,36 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "inbound_messages"
+
+var (
+	ErrLeaseLost = errors.New("inbound message lease is held by another dispatcher")
+)
+
+// InboundRepository stores received emails and the state of their
+// webhook deliveries
+type InboundRepository interface {
+	Save(ctx context.Context, message *models.InboundMessage) error
+	Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.InboundMessage, error)
+	Release(ctx context.Context, message *models.InboundMessage, owner string) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) InboundRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
@@ -0,0 +1,116 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Save stores a received email
+func (m *mongoDB) Save(ctx context.Context, message *models.InboundMessage) error {
+	if message.ID.IsZero() {
+		message.ID = primitive.NewObjectID()
+	}
+	message.UpdatedAt = time.Now()
+
+	_, err := m.collection.InsertOne(ctx, message)
+	return err
+}
+
+// Claim leases the next message whose webhooks are due. Messages whose
+// lease expired, because their dispatcher stopped, are claimed again.
+func (m *mongoDB) Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.InboundMessage, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{
+				"status":          models.InboundStatusPending,
+				"next_attempt_at": bson.M{"$lte": now},
+			},
+			{
+				"status":       models.InboundStatusProcessing,
+				"locked_until": bson.M{"$lt": now},
+			},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.InboundStatusProcessing,
+			"locked_by":    owner,
+			"locked_until": now.Add(lease),
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	message := &models.InboundMessage{}
+	err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(message)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// Release stores the outcome of a dispatch attempt and drops the lease.
+// Only the delivery state is written, the message itself never changes.
+func (m *mongoDB) Release(ctx context.Context, message *models.InboundMessage, owner string) error {
+	message.LockedBy = ""
+	message.LockedUntil = nil
+	message.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": message.ID, "locked_by": owner}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          message.Status,
+			"webhooks":        message.Webhooks,
+			"next_attempt_at": message.NextAttemptAt,
+			"updated_at":      message.UpdatedAt,
+		},
+		"$unset": bson.M{"locked_by": "", "locked_until": ""},
+	}
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// CreateIndexes creates the indexes used by the webhook dispatchers and
+// for looking up replies
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "locked_until", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "in_reply_to", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "received_at", Value: -1}},
+		},
+	})
+	return err
+}
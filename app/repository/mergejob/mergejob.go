@@ -0,0 +1,42 @@
+package mergejob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "merge_jobs"
+
+var (
+	ErrInvalidID   = errors.New("invalid ID type")
+	ErrJobNotFound = errors.New("merge job not found")
+	ErrJobFinished = errors.New("merge job is already finished")
+	ErrLeaseLost   = errors.New("merge job lease is held by another runner")
+)
+
+// MergeJobRepository stores mail merge jobs and their progress
+type MergeJobRepository interface {
+	Save(ctx context.Context, job *models.MergeJob) error
+	FindByID(ctx context.Context, id string) (*models.MergeJob, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.MergeJob, int64, error)
+	Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.MergeJob, error)
+	Release(ctx context.Context, job *models.MergeJob, owner string) error
+	Cancel(ctx context.Context, id primitive.ObjectID, now time.Time) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) MergeJobRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
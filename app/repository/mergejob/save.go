@@ -0,0 +1,143 @@
+package mergejob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Save stores a new job
+func (m *mongoDB) Save(ctx context.Context, job *models.MergeJob) error {
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = now
+	}
+	job.UpdatedAt = now
+
+	_, err := m.collection.InsertOne(ctx, job)
+	return err
+}
+
+// Claim leases the unfinished job that waited longest for a runner, so
+// that runners take turns between the batches of concurrent jobs. Jobs
+// whose lease expired, because their runner stopped, are claimed again.
+func (m *mongoDB) Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.MergeJob, error) {
+	filter := bson.M{
+		"status": bson.M{"$in": []models.MergeJobStatus{models.MergeJobPending, models.MergeJobRunning}},
+		"$or": []bson.M{
+			{"locked_until": bson.M{"$exists": false}},
+			{"locked_until": bson.M{"$lt": now}},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.MergeJobRunning,
+			"locked_by":    owner,
+			"locked_until": now.Add(lease),
+			"updated_at":   now,
+		},
+		// Only sets the start time on the first claim
+		"$min": bson.M{"started_at": now},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "updated_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	job := &models.MergeJob{}
+	err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Release stores the progress of a job and drops the lease. A job that was
+// cancelled while the runner held the lease stays cancelled.
+func (m *mongoDB) Release(ctx context.Context, job *models.MergeJob, owner string) error {
+	job.LockedBy = ""
+	job.LockedUntil = nil
+	job.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": job.ID, "locked_by": owner}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"status": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$status", models.MergeJobCancelled}},
+				models.MergeJobCancelled,
+				job.Status,
+			}},
+			"queued":       job.Queued,
+			"suppressed":   job.Suppressed,
+			"failed":       job.Failed,
+			"completed_at": bson.M{"$ifNull": bson.A{"$completed_at", job.CompletedAt}},
+			"updated_at":   job.UpdatedAt,
+		}}},
+		{{Key: "$unset", Value: bson.A{"locked_by", "locked_until"}}},
+	}
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// Cancel stops a job that isn't finished yet. A runner holding its lease
+// stops after the batch it is sending.
+func (m *mongoDB) Cancel(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	filter := bson.M{
+		"_id":    id,
+		"status": bson.M{"$in": []models.MergeJobStatus{models.MergeJobPending, models.MergeJobRunning}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.MergeJobCancelled,
+			"completed_at": now,
+			"updated_at":   now,
+		},
+	}
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrJobFinished
+	}
+
+	return nil
+}
+
+// CreateIndexes creates the indexes used by the runners and for listing
+// the jobs of a user
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	return err
+}
@@ -0,0 +1,64 @@
+package mergerow
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindPending retrieves the next recipients of a job that weren't sent
+// yet, in the order of the upload
+func (m *mongoDB) FindPending(ctx context.Context, jobID primitive.ObjectID, limit int) ([]*models.MergeRow, error) {
+	filter := bson.M{"job_id": jobID, "status": models.MergeRowPending}
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"row": 1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rows := make([]*models.MergeRow, 0)
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// FindAll retrieves rows with optional filtering and pagination, in the
+// order of the upload
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.MergeRow, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "job_id", Value: 1}, {Key: "row", Value: 1}})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	rows := make([]*models.MergeRow, 0)
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rows, total, nil
+}
@@ -0,0 +1,32 @@
+package mergerow
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "merge_rows"
+
+// MergeRowRepository stores the recipients of mail merge jobs and their
+// outcome
+type MergeRowRepository interface {
+	SaveMany(ctx context.Context, rows []*models.MergeRow) error
+	FindPending(ctx context.Context, jobID primitive.ObjectID, limit int) ([]*models.MergeRow, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.MergeRow, int64, error)
+	SaveResults(ctx context.Context, rows []*models.MergeRow) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) MergeRowRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
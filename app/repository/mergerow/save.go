@@ -0,0 +1,72 @@
+package mergerow
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveMany stores the recipients of a new job
+func (m *mongoDB) SaveMany(ctx context.Context, rows []*models.MergeRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	documents := make([]interface{}, len(rows))
+	for i, row := range rows {
+		if row.ID.IsZero() {
+			row.ID = primitive.NewObjectID()
+		}
+		row.UpdatedAt = now
+		documents[i] = row
+	}
+
+	_, err := m.collection.InsertMany(ctx, documents)
+	return err
+}
+
+// SaveResults stores the outcome of a batch of recipients
+func (m *mongoDB) SaveResults(ctx context.Context, rows []*models.MergeRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, len(rows))
+	for i, row := range rows {
+		row.UpdatedAt = now
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": row.ID}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"status":     row.Status,
+				"email_id":   row.EmailID,
+				"error":      row.Error,
+				"updated_at": row.UpdatedAt,
+			}})
+	}
+
+	_, err := m.collection.BulkWrite(ctx, writes)
+	return err
+}
+
+// CreateIndexes creates the indexes used by the runners and for listing
+// the rows of a job
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "job_id", Value: 1}, {Key: "row", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "job_id", Value: 1}, {Key: "status", Value: 1}, {Key: "row", Value: 1}},
+		},
+	})
+	return err
+}
@@ -3,11 +3,15 @@
 package mocks
 
 import (
-	models "GoMail/app/repository/models"
 	context "context"
+	io "io"
 
 	mock "github.com/stretchr/testify/mock"
 
+	models "GoMail/app/repository/models"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+
 	time "time"
 )
 
@@ -16,27 +20,45 @@ type Repository struct {
 	mock.Mock
 }
 
-// CleanupExpiredTokens provides a mock function with given fields: ctx, beforeTime
-func (_m *Repository) CleanupExpiredTokens(ctx context.Context, beforeTime time.Time) (int64, error) {
-	ret := _m.Called(ctx, beforeTime)
+// AddEmailLogFeedback provides a mock function with given fields: ctx, id, status, feedback
+func (_m *Repository) AddEmailLogFeedback(ctx context.Context, id primitive.ObjectID, status models.EmailLogStatus, feedback models.DeliveryFeedback) error {
+	ret := _m.Called(ctx, id, status, feedback)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CleanupExpiredTokens")
+		panic("no return value specified for AddEmailLogFeedback")
 	}
 
-	var r0 int64
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, models.EmailLogStatus, models.DeliveryFeedback) error); ok {
+		r0 = rf(ctx, id, status, feedback)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdvanceSchedule provides a mock function with given fields: ctx, schedule, previous
+func (_m *Repository) AdvanceSchedule(ctx context.Context, schedule *models.Schedule, previous time.Time) (bool, error) {
+	ret := _m.Called(ctx, schedule, previous)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdvanceSchedule")
+	}
+
+	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
-		return rf(ctx, beforeTime)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Schedule, time.Time) (bool, error)); ok {
+		return rf(ctx, schedule, previous)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
-		r0 = rf(ctx, beforeTime)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Schedule, time.Time) bool); ok {
+		r0 = rf(ctx, schedule, previous)
 	} else {
-		r0 = ret.Get(0).(int64)
+		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
-		r1 = rf(ctx, beforeTime)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Schedule, time.Time) error); ok {
+		r1 = rf(ctx, schedule, previous)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -44,17 +66,17 @@ func (_m *Repository) CleanupExpiredTokens(ctx context.Context, beforeTime time.
 	return r0, r1
 }
 
-// DeleteEmail provides a mock function with given fields: ctx, id
-func (_m *Repository) DeleteEmail(ctx context.Context, id interface{}) error {
-	ret := _m.Called(ctx, id)
+// CancelMergeJob provides a mock function with given fields: ctx, id, now
+func (_m *Repository) CancelMergeJob(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	ret := _m.Called(ctx, id, now)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteEmail")
+		panic("no return value specified for CancelMergeJob")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) error); ok {
+		r0 = rf(ctx, id, now)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -62,29 +84,29 @@ func (_m *Repository) DeleteEmail(ctx context.Context, id interface{}) error {
 	return r0
 }
 
-// FindEmailLogByID provides a mock function with given fields: ctx, id
-func (_m *Repository) FindEmailLogByID(ctx context.Context, id string) (*models.EmailLog, error) {
-	ret := _m.Called(ctx, id)
+// ClaimEmail provides a mock function with given fields: ctx, owner, now, lease
+func (_m *Repository) ClaimEmail(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.Email, error) {
+	ret := _m.Called(ctx, owner, now, lease)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindEmailLogByID")
+		panic("no return value specified for ClaimEmail")
 	}
 
-	var r0 *models.EmailLog
+	var r0 *models.Email
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.EmailLog, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) (*models.Email, error)); ok {
+		return rf(ctx, owner, now, lease)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.EmailLog); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) *models.Email); ok {
+		r0 = rf(ctx, owner, now, lease)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.EmailLog)
+			r0 = ret.Get(0).(*models.Email)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, owner, now, lease)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -92,103 +114,87 @@ func (_m *Repository) FindEmailLogByID(ctx context.Context, id string) (*models.
 	return r0, r1
 }
 
-// FindEmailLogs provides a mock function with given fields: ctx, filter, page, limit
-func (_m *Repository) FindEmailLogs(ctx context.Context, filter interface{}, page int, limit int) ([]*models.EmailLog, int64, error) {
-	ret := _m.Called(ctx, filter, page, limit)
+// ClaimInboundMessage provides a mock function with given fields: ctx, owner, now, lease
+func (_m *Repository) ClaimInboundMessage(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.InboundMessage, error) {
+	ret := _m.Called(ctx, owner, now, lease)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindEmailLogs")
+		panic("no return value specified for ClaimInboundMessage")
 	}
 
-	var r0 []*models.EmailLog
-	var r1 int64
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.EmailLog, int64, error)); ok {
-		return rf(ctx, filter, page, limit)
+	var r0 *models.InboundMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) (*models.InboundMessage, error)); ok {
+		return rf(ctx, owner, now, lease)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.EmailLog); ok {
-		r0 = rf(ctx, filter, page, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) *models.InboundMessage); ok {
+		r0 = rf(ctx, owner, now, lease)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.EmailLog)
+			r0 = ret.Get(0).(*models.InboundMessage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
-		r1 = rf(ctx, filter, page, limit)
-	} else {
-		r1 = ret.Get(1).(int64)
-	}
-
-	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
-		r2 = rf(ctx, filter, page, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, owner, now, lease)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// FindEmails provides a mock function with given fields: ctx, filter, page, limit
-func (_m *Repository) FindEmails(ctx context.Context, filter interface{}, page int, limit int) ([]*models.Email, int64, error) {
-	ret := _m.Called(ctx, filter, page, limit)
+// ClaimMergeJob provides a mock function with given fields: ctx, owner, now, lease
+func (_m *Repository) ClaimMergeJob(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.MergeJob, error) {
+	ret := _m.Called(ctx, owner, now, lease)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindEmails")
+		panic("no return value specified for ClaimMergeJob")
 	}
 
-	var r0 []*models.Email
-	var r1 int64
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.Email, int64, error)); ok {
-		return rf(ctx, filter, page, limit)
+	var r0 *models.MergeJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) (*models.MergeJob, error)); ok {
+		return rf(ctx, owner, now, lease)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.Email); ok {
-		r0 = rf(ctx, filter, page, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) *models.MergeJob); ok {
+		r0 = rf(ctx, owner, now, lease)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Email)
+			r0 = ret.Get(0).(*models.MergeJob)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
-		r1 = rf(ctx, filter, page, limit)
-	} else {
-		r1 = ret.Get(1).(int64)
-	}
-
-	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
-		r2 = rf(ctx, filter, page, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, owner, now, lease)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// FindUserByEmail provides a mock function with given fields: ctx, email
-func (_m *Repository) FindUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	ret := _m.Called(ctx, email)
+// ClaimSendFingerprint provides a mock function with given fields: ctx, fingerprint
+func (_m *Repository) ClaimSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint) (bool, error) {
+	ret := _m.Called(ctx, fingerprint)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUserByEmail")
+		panic("no return value specified for ClaimSendFingerprint")
 	}
 
-	var r0 *models.User
+	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
-		return rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SendFingerprint) (bool, error)); ok {
+		return rf(ctx, fingerprint)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
-		r0 = rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SendFingerprint) bool); ok {
+		r0 = rf(ctx, fingerprint)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
-		}
+		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, email)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SendFingerprint) error); ok {
+		r1 = rf(ctx, fingerprint)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -196,29 +202,29 @@ func (_m *Repository) FindUserByEmail(ctx context.Context, email string) (*model
 	return r0, r1
 }
 
-// FindUserByID provides a mock function with given fields: ctx, id
-func (_m *Repository) FindUserByID(ctx context.Context, id string) (*models.User, error) {
-	ret := _m.Called(ctx, id)
+// ClaimWebhookDelivery provides a mock function with given fields: ctx, owner, now, lease
+func (_m *Repository) ClaimWebhookDelivery(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, owner, now, lease)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUserByID")
+		panic("no return value specified for ClaimWebhookDelivery")
 	}
 
-	var r0 *models.User
+	var r0 *models.WebhookDelivery
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) (*models.WebhookDelivery, error)); ok {
+		return rf(ctx, owner, now, lease)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) *models.WebhookDelivery); ok {
+		r0 = rf(ctx, owner, now, lease)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
+			r0 = ret.Get(0).(*models.WebhookDelivery)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, owner, now, lease)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -226,54 +232,63 @@ func (_m *Repository) FindUserByID(ctx context.Context, id string) (*models.User
 	return r0, r1
 }
 
-// FindUsers provides a mock function with given fields: ctx, filter, page, limit
-func (_m *Repository) FindUsers(ctx context.Context, filter interface{}, page int, limit int) ([]*models.User, int64, error) {
-	ret := _m.Called(ctx, filter, page, limit)
+// CleanupExpiredTokens provides a mock function with given fields: ctx, beforeTime
+func (_m *Repository) CleanupExpiredTokens(ctx context.Context, beforeTime time.Time) (int64, error) {
+	ret := _m.Called(ctx, beforeTime)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUsers")
+		panic("no return value specified for CleanupExpiredTokens")
 	}
 
-	var r0 []*models.User
-	var r1 int64
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.User, int64, error)); ok {
-		return rf(ctx, filter, page, limit)
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, beforeTime)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.User); ok {
-		r0 = rf(ctx, filter, page, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, beforeTime)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.User)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
-		r1 = rf(ctx, filter, page, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, beforeTime)
 	} else {
-		r1 = ret.Get(1).(int64)
+		r1 = ret.Error(1)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
-		r2 = rf(ctx, filter, page, limit)
+	return r0, r1
+}
+
+// CompleteIdempotencyRecord provides a mock function with given fields: ctx, userID, key, response, now
+func (_m *Repository) CompleteIdempotencyRecord(ctx context.Context, userID string, key string, response *models.StoredResponse, now time.Time) error {
+	ret := _m.Called(ctx, userID, key, response, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteIdempotencyRecord")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *models.StoredResponse, time.Time) error); ok {
+		r0 = rf(ctx, userID, key, response, now)
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1, r2
+	return r0
 }
 
-// InitTokenIndexes provides a mock function with given fields: ctx
-func (_m *Repository) InitTokenIndexes(ctx context.Context) error {
-	ret := _m.Called(ctx)
+// DeleteAttachment provides a mock function with given fields: ctx, id
+func (_m *Repository) DeleteAttachment(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InitTokenIndexes")
+		panic("no return value specified for DeleteAttachment")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -281,45 +296,60 @@ func (_m *Repository) InitTokenIndexes(ctx context.Context) error {
 	return r0
 }
 
-// IsTokenRevoked provides a mock function with given fields: ctx, tokenID
-func (_m *Repository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
-	ret := _m.Called(ctx, tokenID)
+// DeleteAttachmentFile provides a mock function with given fields: ctx, fileID
+func (_m *Repository) DeleteAttachmentFile(ctx context.Context, fileID primitive.ObjectID) error {
+	ret := _m.Called(ctx, fileID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsTokenRevoked")
+		panic("no return value specified for DeleteAttachmentFile")
 	}
 
-	var r0 bool
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
-		return rf(ctx, tokenID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = rf(ctx, tokenID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, fileID)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, tokenID)
+	return r0
+}
+
+// DeleteEmail provides a mock function with given fields: ctx, id, conditions
+func (_m *Repository) DeleteEmail(ctx context.Context, id interface{}, conditions ...primitive.M) error {
+	_va := make([]interface{}, len(conditions))
+	for _i := range conditions {
+		_va[_i] = conditions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, ...primitive.M) error); ok {
+		r0 = rf(ctx, id, conditions...)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// RevokeToken provides a mock function with given fields: ctx, token
-func (_m *Repository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
-	ret := _m.Called(ctx, token)
+// DeleteRecipient provides a mock function with given fields: ctx, userID, email
+func (_m *Repository) DeleteRecipient(ctx context.Context, userID string, email string) error {
+	ret := _m.Called(ctx, userID, email)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RevokeToken")
+		panic("no return value specified for DeleteRecipient")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.RevokedToken) error); ok {
-		r0 = rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, email)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -327,17 +357,17 @@ func (_m *Repository) RevokeToken(ctx context.Context, token *models.RevokedToke
 	return r0
 }
 
-// SaveEmail provides a mock function with given fields: ctx, email
-func (_m *Repository) SaveEmail(ctx context.Context, email *models.Email) error {
-	ret := _m.Called(ctx, email)
+// DeleteSchedule provides a mock function with given fields: ctx, id
+func (_m *Repository) DeleteSchedule(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveEmail")
+		panic("no return value specified for DeleteSchedule")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Email) error); ok {
-		r0 = rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -345,17 +375,45 @@ func (_m *Repository) SaveEmail(ctx context.Context, email *models.Email) error
 	return r0
 }
 
-// SaveEmailLog provides a mock function with given fields: ctx, emailLog
-func (_m *Repository) SaveEmailLog(ctx context.Context, emailLog *models.EmailLog) error {
-	ret := _m.Called(ctx, emailLog)
+// DeleteStaleIdempotencyRecord provides a mock function with given fields: ctx, userID, key, now, lockedBefore
+func (_m *Repository) DeleteStaleIdempotencyRecord(ctx context.Context, userID string, key string, now time.Time, lockedBefore time.Time) (bool, error) {
+	ret := _m.Called(ctx, userID, key, now, lockedBefore)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveEmailLog")
+		panic("no return value specified for DeleteStaleIdempotencyRecord")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) (bool, error)); ok {
+		return rf(ctx, userID, key, now, lockedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) bool); ok {
+		r0 = rf(ctx, userID, key, now, lockedBefore)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, userID, key, now, lockedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSuppression provides a mock function with given fields: ctx, id
+func (_m *Repository) DeleteSuppression(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSuppression")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.EmailLog) error); ok {
-		r0 = rf(ctx, emailLog)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -363,17 +421,17 @@ func (_m *Repository) SaveEmailLog(ctx context.Context, emailLog *models.EmailLo
 	return r0
 }
 
-// SaveUser provides a mock function with given fields: ctx, user
-func (_m *Repository) SaveUser(ctx context.Context, user *models.User) error {
-	ret := _m.Called(ctx, user)
+// DeleteTemplate provides a mock function with given fields: ctx, id
+func (_m *Repository) DeleteTemplate(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveUser")
+		panic("no return value specified for DeleteTemplate")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -381,6 +439,2606 @@ func (_m *Repository) SaveUser(ctx context.Context, user *models.User) error {
 	return r0
 }
 
+// DeleteTemplateVersions provides a mock function with given fields: ctx, templateID
+func (_m *Repository) DeleteTemplateVersions(ctx context.Context, templateID primitive.ObjectID) error {
+	ret := _m.Called(ctx, templateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTemplateVersions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, templateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTopic provides a mock function with given fields: ctx, id
+func (_m *Repository) DeleteTopic(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTopic")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, id
+func (_m *Repository) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhookDeliveries provides a mock function with given fields: ctx, webhookID
+func (_m *Repository) DeleteWebhookDeliveries(ctx context.Context, webhookID primitive.ObjectID) error {
+	ret := _m.Called(ctx, webhookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhookDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExtendSendFingerprint provides a mock function with given fields: ctx, fingerprint, expiresAt
+func (_m *Repository) ExtendSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint, expiresAt time.Time) error {
+	ret := _m.Called(ctx, fingerprint, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExtendSendFingerprint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SendFingerprint, time.Time) error); ok {
+		r0 = rf(ctx, fingerprint, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindActiveSuppression provides a mock function with given fields: ctx, userID, list, values, now
+func (_m *Repository) FindActiveSuppression(ctx context.Context, userID string, list string, values []string, now time.Time) (*models.Suppression, error) {
+	ret := _m.Called(ctx, userID, list, values, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindActiveSuppression")
+	}
+
+	var r0 *models.Suppression
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, time.Time) (*models.Suppression, error)); ok {
+		return rf(ctx, userID, list, values, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, time.Time) *models.Suppression); ok {
+		r0 = rf(ctx, userID, list, values, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Suppression)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string, time.Time) error); ok {
+		r1 = rf(ctx, userID, list, values, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAttachmentByHash provides a mock function with given fields: ctx, userID, hash
+func (_m *Repository) FindAttachmentByHash(ctx context.Context, userID string, hash string) (*models.StoredAttachment, error) {
+	ret := _m.Called(ctx, userID, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAttachmentByHash")
+	}
+
+	var r0 *models.StoredAttachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.StoredAttachment, error)); ok {
+		return rf(ctx, userID, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.StoredAttachment); ok {
+		r0 = rf(ctx, userID, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StoredAttachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAttachmentByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindAttachmentByID(ctx context.Context, id string) (*models.StoredAttachment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAttachmentByID")
+	}
+
+	var r0 *models.StoredAttachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.StoredAttachment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.StoredAttachment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StoredAttachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAttachments provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindAttachments(ctx context.Context, filter interface{}, page int, limit int) ([]*models.StoredAttachment, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAttachments")
+	}
+
+	var r0 []*models.StoredAttachment
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.StoredAttachment, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.StoredAttachment); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.StoredAttachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindDownloadLinkByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindDownloadLinkByID(ctx context.Context, id string) (*models.DownloadLink, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDownloadLinkByID")
+	}
+
+	var r0 *models.DownloadLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.DownloadLink, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.DownloadLink); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DownloadLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDownloadLinks provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindDownloadLinks(ctx context.Context, filter interface{}, page int, limit int) ([]*models.DownloadLink, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDownloadLinks")
+	}
+
+	var r0 []*models.DownloadLink
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.DownloadLink, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.DownloadLink); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.DownloadLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindDueSchedules provides a mock function with given fields: ctx, now, limit
+func (_m *Repository) FindDueSchedules(ctx context.Context, now time.Time, limit int) ([]*models.Schedule, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDueSchedules")
+	}
+
+	var r0 []*models.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*models.Schedule, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*models.Schedule); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEmailByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindEmailByID(ctx context.Context, id string) (*models.Email, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmailByID")
+	}
+
+	var r0 *models.Email
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Email, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Email); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Email)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEmailEvents provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindEmailEvents(ctx context.Context, filter interface{}, page int, limit int) ([]*models.EmailEvent, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmailEvents")
+	}
+
+	var r0 []*models.EmailEvent
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.EmailEvent, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.EmailEvent); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.EmailEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindEmailLogByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindEmailLogByID(ctx context.Context, id string) (*models.EmailLog, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmailLogByID")
+	}
+
+	var r0 *models.EmailLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.EmailLog, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.EmailLog); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEmailLogByMessageID provides a mock function with given fields: ctx, messageID
+func (_m *Repository) FindEmailLogByMessageID(ctx context.Context, messageID string) (*models.EmailLog, error) {
+	ret := _m.Called(ctx, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmailLogByMessageID")
+	}
+
+	var r0 *models.EmailLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.EmailLog, error)); ok {
+		return rf(ctx, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.EmailLog); ok {
+		r0 = rf(ctx, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEmailLogs provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindEmailLogs(ctx context.Context, filter interface{}, page int, limit int) ([]*models.EmailLog, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmailLogs")
+	}
+
+	var r0 []*models.EmailLog
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.EmailLog, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.EmailLog); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.EmailLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindEmails provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindEmails(ctx context.Context, filter interface{}, page int, limit int) ([]*models.Email, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmails")
+	}
+
+	var r0 []*models.Email
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.Email, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.Email); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Email)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindExpiredAttachments provides a mock function with given fields: ctx, now, limit
+func (_m *Repository) FindExpiredAttachments(ctx context.Context, now time.Time, limit int) ([]*models.StoredAttachment, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindExpiredAttachments")
+	}
+
+	var r0 []*models.StoredAttachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*models.StoredAttachment, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*models.StoredAttachment); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.StoredAttachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindIdempotencyRecord provides a mock function with given fields: ctx, userID, key
+func (_m *Repository) FindIdempotencyRecord(ctx context.Context, userID string, key string) (*models.IdempotencyRecord, error) {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindIdempotencyRecord")
+	}
+
+	var r0 *models.IdempotencyRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.IdempotencyRecord, error)); ok {
+		return rf(ctx, userID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.IdempotencyRecord); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.IdempotencyRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMergeJobByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindMergeJobByID(ctx context.Context, id string) (*models.MergeJob, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMergeJobByID")
+	}
+
+	var r0 *models.MergeJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.MergeJob, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.MergeJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.MergeJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMergeJobs provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindMergeJobs(ctx context.Context, filter interface{}, page int, limit int) ([]*models.MergeJob, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMergeJobs")
+	}
+
+	var r0 []*models.MergeJob
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.MergeJob, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.MergeJob); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.MergeJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindMergeRows provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindMergeRows(ctx context.Context, filter interface{}, page int, limit int) ([]*models.MergeRow, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMergeRows")
+	}
+
+	var r0 []*models.MergeRow
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.MergeRow, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.MergeRow); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.MergeRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindMessageEmailEvents provides a mock function with given fields: ctx, userID, messageID
+func (_m *Repository) FindMessageEmailEvents(ctx context.Context, userID string, messageID string) ([]*models.EmailEvent, error) {
+	ret := _m.Called(ctx, userID, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMessageEmailEvents")
+	}
+
+	var r0 []*models.EmailEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*models.EmailEvent, error)); ok {
+		return rf(ctx, userID, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*models.EmailEvent); ok {
+		r0 = rf(ctx, userID, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.EmailEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPendingMergeRows provides a mock function with given fields: ctx, jobID, limit
+func (_m *Repository) FindPendingMergeRows(ctx context.Context, jobID primitive.ObjectID, limit int) ([]*models.MergeRow, error) {
+	ret := _m.Called(ctx, jobID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPendingMergeRows")
+	}
+
+	var r0 []*models.MergeRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, int) ([]*models.MergeRow, error)); ok {
+		return rf(ctx, jobID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, int) []*models.MergeRow); ok {
+		r0 = rf(ctx, jobID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.MergeRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, int) error); ok {
+		r1 = rf(ctx, jobID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRecipient provides a mock function with given fields: ctx, userID, email
+func (_m *Repository) FindRecipient(ctx context.Context, userID string, email string) (*models.Recipient, error) {
+	ret := _m.Called(ctx, userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRecipient")
+	}
+
+	var r0 *models.Recipient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.Recipient, error)); ok {
+		return rf(ctx, userID, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.Recipient); ok {
+		r0 = rf(ctx, userID, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Recipient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRecipients provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindRecipients(ctx context.Context, filter interface{}, page int, limit int) ([]*models.Recipient, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRecipients")
+	}
+
+	var r0 []*models.Recipient
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.Recipient, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.Recipient); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Recipient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindScheduleByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindScheduleByID(ctx context.Context, id string) (*models.Schedule, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindScheduleByID")
+	}
+
+	var r0 *models.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Schedule, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Schedule); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindScheduleRuns provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindScheduleRuns(ctx context.Context, filter interface{}, page int, limit int) ([]*models.ScheduleRun, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindScheduleRuns")
+	}
+
+	var r0 []*models.ScheduleRun
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.ScheduleRun, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.ScheduleRun); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ScheduleRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindSchedules provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindSchedules(ctx context.Context, filter interface{}, page int, limit int) ([]*models.Schedule, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSchedules")
+	}
+
+	var r0 []*models.Schedule
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.Schedule, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.Schedule); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindSubscribedWebhooks provides a mock function with given fields: ctx, userID, eventType
+func (_m *Repository) FindSubscribedWebhooks(ctx context.Context, userID string, eventType models.WebhookEventType) ([]*models.Webhook, error) {
+	ret := _m.Called(ctx, userID, eventType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSubscribedWebhooks")
+	}
+
+	var r0 []*models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.WebhookEventType) ([]*models.Webhook, error)); ok {
+		return rf(ctx, userID, eventType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.WebhookEventType) []*models.Webhook); ok {
+		r0 = rf(ctx, userID, eventType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.WebhookEventType) error); ok {
+		r1 = rf(ctx, userID, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSuppressionByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindSuppressionByID(ctx context.Context, id string) (*models.Suppression, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSuppressionByID")
+	}
+
+	var r0 *models.Suppression
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Suppression, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Suppression); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Suppression)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSuppressions provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindSuppressions(ctx context.Context, filter interface{}, page int, limit int) ([]*models.Suppression, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSuppressions")
+	}
+
+	var r0 []*models.Suppression
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.Suppression, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.Suppression); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Suppression)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindTemplateByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindTemplateByID(ctx context.Context, id string) (*models.Template, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTemplateByID")
+	}
+
+	var r0 *models.Template
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Template, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Template); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Template)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTemplateVersion provides a mock function with given fields: ctx, templateID, version
+func (_m *Repository) FindTemplateVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*models.TemplateVersion, error) {
+	ret := _m.Called(ctx, templateID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTemplateVersion")
+	}
+
+	var r0 *models.TemplateVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, int) (*models.TemplateVersion, error)); ok {
+		return rf(ctx, templateID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, int) *models.TemplateVersion); ok {
+		r0 = rf(ctx, templateID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TemplateVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, int) error); ok {
+		r1 = rf(ctx, templateID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTemplateVersions provides a mock function with given fields: ctx, templateID
+func (_m *Repository) FindTemplateVersions(ctx context.Context, templateID primitive.ObjectID) ([]*models.TemplateVersion, error) {
+	ret := _m.Called(ctx, templateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTemplateVersions")
+	}
+
+	var r0 []*models.TemplateVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]*models.TemplateVersion, error)); ok {
+		return rf(ctx, templateID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []*models.TemplateVersion); ok {
+		r0 = rf(ctx, templateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TemplateVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTemplates provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindTemplates(ctx context.Context, filter interface{}, page int, limit int) ([]*models.Template, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTemplates")
+	}
+
+	var r0 []*models.Template
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.Template, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.Template); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Template)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindTopic provides a mock function with given fields: ctx, userID, name
+func (_m *Repository) FindTopic(ctx context.Context, userID string, name string) (*models.Topic, error) {
+	ret := _m.Called(ctx, userID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTopic")
+	}
+
+	var r0 *models.Topic
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.Topic, error)); ok {
+		return rf(ctx, userID, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.Topic); ok {
+		r0 = rf(ctx, userID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Topic)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserByEmail provides a mock function with given fields: ctx, email
+func (_m *Repository) FindUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByEmail")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindUserByID(ctx context.Context, id string) (*models.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByID")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserTopics provides a mock function with given fields: ctx, userID
+func (_m *Repository) FindUserTopics(ctx context.Context, userID string) ([]*models.Topic, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserTopics")
+	}
+
+	var r0 []*models.Topic
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*models.Topic, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*models.Topic); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Topic)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserWebhooks provides a mock function with given fields: ctx, userID
+func (_m *Repository) FindUserWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserWebhooks")
+	}
+
+	var r0 []*models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*models.Webhook, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*models.Webhook); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUsers provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindUsers(ctx context.Context, filter interface{}, page int, limit int) ([]*models.User, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUsers")
+	}
+
+	var r0 []*models.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.User, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.User); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindWebhookByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindWebhookByID(ctx context.Context, id string) (*models.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindWebhookByID")
+	}
+
+	var r0 *models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Webhook, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Webhook); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindWebhookDeliveries provides a mock function with given fields: ctx, filter, page, limit
+func (_m *Repository) FindWebhookDeliveries(ctx context.Context, filter interface{}, page int, limit int) ([]*models.WebhookDelivery, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindWebhookDeliveries")
+	}
+
+	var r0 []*models.WebhookDelivery
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) ([]*models.WebhookDelivery, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) []*models.WebhookDelivery); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindWebhookDeliveryByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindWebhookDeliveryByID(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindWebhookDeliveryByID")
+	}
+
+	var r0 *models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.WebhookDelivery, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.WebhookDelivery); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InitAttachmentIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitAttachmentIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitAttachmentIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitDedupIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitDedupIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitDedupIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitDownloadLinkIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitDownloadLinkIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitDownloadLinkIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitEmailEventIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitEmailEventIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitEmailEventIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitEmailIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitEmailIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitEmailIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitEmailLogIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitEmailLogIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitEmailLogIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitIdempotencyIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitIdempotencyIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitIdempotencyIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitInboundIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitInboundIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitInboundIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitMergeJobIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitMergeJobIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitMergeJobIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitMergeRowIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitMergeRowIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitMergeRowIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitRecipientIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitRecipientIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitRecipientIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitScheduleIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitScheduleIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitScheduleIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitSuppressionIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitSuppressionIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitSuppressionIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitTemplateIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitTemplateIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitTemplateIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitTokenIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitTokenIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitTokenIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitTopicIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitTopicIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitTopicIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitWebhookDeliveryIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitWebhookDeliveryIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitWebhookDeliveryIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InitWebhookIndexes provides a mock function with given fields: ctx
+func (_m *Repository) InitWebhookIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitWebhookIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertIdempotencyRecord provides a mock function with given fields: ctx, record
+func (_m *Repository) InsertIdempotencyRecord(ctx context.Context, record *models.IdempotencyRecord) error {
+	ret := _m.Called(ctx, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertIdempotencyRecord")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.IdempotencyRecord) error); ok {
+		r0 = rf(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertTemplateVersion provides a mock function with given fields: ctx, version
+func (_m *Repository) InsertTemplateVersion(ctx context.Context, version *models.TemplateVersion) error {
+	ret := _m.Called(ctx, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertTemplateVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TemplateVersion) error); ok {
+		r0 = rf(ctx, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IsTokenRevoked provides a mock function with given fields: ctx, tokenID
+func (_m *Repository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	ret := _m.Called(ctx, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, tokenID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, tokenID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenAttachmentFile provides a mock function with given fields: ctx, fileID
+func (_m *Repository) OpenAttachmentFile(ctx context.Context, fileID primitive.ObjectID) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, fileID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenAttachmentFile")
+	}
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (io.ReadCloser, error)); ok {
+		return rf(ctx, fileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) io.ReadCloser); ok {
+		r0 = rf(ctx, fileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PromoteScheduledEmails provides a mock function with given fields: ctx, now
+func (_m *Repository) PromoteScheduledEmails(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PromoteScheduledEmails")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordDownload provides a mock function with given fields: ctx, id, now
+func (_m *Repository) RecordDownload(ctx context.Context, id primitive.ObjectID, now time.Time) (*models.DownloadLink, error) {
+	ret := _m.Called(ctx, id, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordDownload")
+	}
+
+	var r0 *models.DownloadLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) (*models.DownloadLink, error)); ok {
+		return rf(ctx, id, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) *models.DownloadLink); ok {
+		r0 = rf(ctx, id, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DownloadLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, time.Time) error); ok {
+		r1 = rf(ctx, id, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordWebhookResult provides a mock function with given fields: ctx, id, delivered, disableAfter, now
+func (_m *Repository) RecordWebhookResult(ctx context.Context, id primitive.ObjectID, delivered bool, disableAfter int, now time.Time) (*models.Webhook, error) {
+	ret := _m.Called(ctx, id, delivered, disableAfter, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordWebhookResult")
+	}
+
+	var r0 *models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, bool, int, time.Time) (*models.Webhook, error)); ok {
+		return rf(ctx, id, delivered, disableAfter, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, bool, int, time.Time) *models.Webhook); ok {
+		r0 = rf(ctx, id, delivered, disableAfter, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, bool, int, time.Time) error); ok {
+		r1 = rf(ctx, id, delivered, disableAfter, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseEmail provides a mock function with given fields: ctx, email, owner
+func (_m *Repository) ReleaseEmail(ctx context.Context, email *models.Email, owner string) error {
+	ret := _m.Called(ctx, email, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Email, string) error); ok {
+		r0 = rf(ctx, email, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseIdempotencyRecord provides a mock function with given fields: ctx, userID, key
+func (_m *Repository) ReleaseIdempotencyRecord(ctx context.Context, userID string, key string) error {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseIdempotencyRecord")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseInboundMessage provides a mock function with given fields: ctx, message, owner
+func (_m *Repository) ReleaseInboundMessage(ctx context.Context, message *models.InboundMessage, owner string) error {
+	ret := _m.Called(ctx, message, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseInboundMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.InboundMessage, string) error); ok {
+		r0 = rf(ctx, message, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseMergeJob provides a mock function with given fields: ctx, job, owner
+func (_m *Repository) ReleaseMergeJob(ctx context.Context, job *models.MergeJob, owner string) error {
+	ret := _m.Called(ctx, job, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseMergeJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.MergeJob, string) error); ok {
+		r0 = rf(ctx, job, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseSendFingerprint provides a mock function with given fields: ctx, fingerprint
+func (_m *Repository) ReleaseSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint) error {
+	ret := _m.Called(ctx, fingerprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseSendFingerprint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SendFingerprint) error); ok {
+		r0 = rf(ctx, fingerprint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseWebhookDelivery provides a mock function with given fields: ctx, delivery, owner
+func (_m *Repository) ReleaseWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery, owner string) error {
+	ret := _m.Called(ctx, delivery, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseWebhookDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WebhookDelivery, string) error); ok {
+		r0 = rf(ctx, delivery, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RescheduleEmail provides a mock function with given fields: ctx, id, userID, sendAt
+func (_m *Repository) RescheduleEmail(ctx context.Context, id string, userID string, sendAt time.Time) (*models.Email, error) {
+	ret := _m.Called(ctx, id, userID, sendAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RescheduleEmail")
+	}
+
+	var r0 *models.Email
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*models.Email, error)); ok {
+		return rf(ctx, id, userID, sendAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *models.Email); ok {
+		r0 = rf(ctx, id, userID, sendAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Email)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, id, userID, sendAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReserveTemplateVersion provides a mock function with given fields: ctx, id, latest
+func (_m *Repository) ReserveTemplateVersion(ctx context.Context, id string, latest int) error {
+	ret := _m.Called(ctx, id, latest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveTemplateVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, id, latest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeDownloadLink provides a mock function with given fields: ctx, id, now
+func (_m *Repository) RevokeDownloadLink(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	ret := _m.Called(ctx, id, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeDownloadLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) error); ok {
+		r0 = rf(ctx, id, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeToken provides a mock function with given fields: ctx, token
+func (_m *Repository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.RevokedToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveAttachment provides a mock function with given fields: ctx, attachment
+func (_m *Repository) SaveAttachment(ctx context.Context, attachment *models.StoredAttachment) error {
+	ret := _m.Called(ctx, attachment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAttachment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.StoredAttachment) error); ok {
+		r0 = rf(ctx, attachment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveDownloadLink provides a mock function with given fields: ctx, link
+func (_m *Repository) SaveDownloadLink(ctx context.Context, link *models.DownloadLink) error {
+	ret := _m.Called(ctx, link)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDownloadLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DownloadLink) error); ok {
+		r0 = rf(ctx, link)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveEmail provides a mock function with given fields: ctx, email
+func (_m *Repository) SaveEmail(ctx context.Context, email *models.Email) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Email) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveEmailEvent provides a mock function with given fields: ctx, event
+func (_m *Repository) SaveEmailEvent(ctx context.Context, event *models.EmailEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEmailEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EmailEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveEmailLog provides a mock function with given fields: ctx, emailLog
+func (_m *Repository) SaveEmailLog(ctx context.Context, emailLog *models.EmailLog) error {
+	ret := _m.Called(ctx, emailLog)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEmailLog")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EmailLog) error); ok {
+		r0 = rf(ctx, emailLog)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveInboundMessage provides a mock function with given fields: ctx, message
+func (_m *Repository) SaveInboundMessage(ctx context.Context, message *models.InboundMessage) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveInboundMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.InboundMessage) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveMergeJob provides a mock function with given fields: ctx, job
+func (_m *Repository) SaveMergeJob(ctx context.Context, job *models.MergeJob) error {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMergeJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.MergeJob) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveMergeRowResults provides a mock function with given fields: ctx, rows
+func (_m *Repository) SaveMergeRowResults(ctx context.Context, rows []*models.MergeRow) error {
+	ret := _m.Called(ctx, rows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMergeRowResults")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.MergeRow) error); ok {
+		r0 = rf(ctx, rows)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveMergeRows provides a mock function with given fields: ctx, rows
+func (_m *Repository) SaveMergeRows(ctx context.Context, rows []*models.MergeRow) error {
+	ret := _m.Called(ctx, rows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMergeRows")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.MergeRow) error); ok {
+		r0 = rf(ctx, rows)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSchedule provides a mock function with given fields: ctx, schedule
+func (_m *Repository) SaveSchedule(ctx context.Context, schedule *models.Schedule) error {
+	ret := _m.Called(ctx, schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Schedule) error); ok {
+		r0 = rf(ctx, schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveScheduleRun provides a mock function with given fields: ctx, run
+func (_m *Repository) SaveScheduleRun(ctx context.Context, run *models.ScheduleRun) error {
+	ret := _m.Called(ctx, run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveScheduleRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ScheduleRun) error); ok {
+		r0 = rf(ctx, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSuppression provides a mock function with given fields: ctx, suppression
+func (_m *Repository) SaveSuppression(ctx context.Context, suppression *models.Suppression) error {
+	ret := _m.Called(ctx, suppression)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSuppression")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Suppression) error); ok {
+		r0 = rf(ctx, suppression)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveTemplate provides a mock function with given fields: ctx, template
+func (_m *Repository) SaveTemplate(ctx context.Context, template *models.Template) error {
+	ret := _m.Called(ctx, template)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTemplate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Template) error); ok {
+		r0 = rf(ctx, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveTopic provides a mock function with given fields: ctx, topic
+func (_m *Repository) SaveTopic(ctx context.Context, topic *models.Topic) error {
+	ret := _m.Called(ctx, topic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTopic")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Topic) error); ok {
+		r0 = rf(ctx, topic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveUser provides a mock function with given fields: ctx, user
+func (_m *Repository) SaveUser(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveWebhook provides a mock function with given fields: ctx, webhook
+func (_m *Repository) SaveWebhook(ctx context.Context, webhook *models.Webhook) error {
+	ret := _m.Called(ctx, webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Webhook) error); ok {
+		r0 = rf(ctx, webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveWebhookDelivery provides a mock function with given fields: ctx, delivery
+func (_m *Repository) SaveWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ret := _m.Called(ctx, delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWebhookDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WebhookDelivery) error); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetAttachmentExpiry provides a mock function with given fields: ctx, id, expiresAt
+func (_m *Repository) SetAttachmentExpiry(ctx context.Context, id primitive.ObjectID, expiresAt *time.Time) error {
+	ret := _m.Called(ctx, id, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAttachmentExpiry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, *time.Time) error); ok {
+		r0 = rf(ctx, id, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRecipientOptedOut provides a mock function with given fields: ctx, userID, email, optedOut
+func (_m *Repository) UpdateRecipientOptedOut(ctx context.Context, userID string, email string, optedOut []string) (*models.Recipient, error) {
+	ret := _m.Called(ctx, userID, email, optedOut)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRecipientOptedOut")
+	}
+
+	var r0 *models.Recipient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) (*models.Recipient, error)); ok {
+		return rf(ctx, userID, email, optedOut)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) *models.Recipient); ok {
+		r0 = rf(ctx, userID, email, optedOut)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Recipient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string) error); ok {
+		r1 = rf(ctx, userID, email, optedOut)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSuppression provides a mock function with given fields: ctx, suppression
+func (_m *Repository) UpdateSuppression(ctx context.Context, suppression *models.Suppression) error {
+	ret := _m.Called(ctx, suppression)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSuppression")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Suppression) error); ok {
+		r0 = rf(ctx, suppression)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTopic provides a mock function with given fields: ctx, topic
+func (_m *Repository) UpdateTopic(ctx context.Context, topic *models.Topic) error {
+	ret := _m.Called(ctx, topic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTopic")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Topic) error); ok {
+		r0 = rf(ctx, topic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWebhook provides a mock function with given fields: ctx, webhook
+func (_m *Repository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	ret := _m.Called(ctx, webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Webhook) error); ok {
+		r0 = rf(ctx, webhook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UploadAttachmentFile provides a mock function with given fields: ctx, filename, content
+func (_m *Repository) UploadAttachmentFile(ctx context.Context, filename string, content io.Reader) (primitive.ObjectID, error) {
+	ret := _m.Called(ctx, filename, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadAttachmentFile")
+	}
+
+	var r0 primitive.ObjectID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) (primitive.ObjectID, error)); ok {
+		return rf(ctx, filename, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) primitive.ObjectID); ok {
+		r0 = rf(ctx, filename, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(primitive.ObjectID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader) error); ok {
+		r1 = rf(ctx, filename, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertRecipient provides a mock function with given fields: ctx, recipient
+func (_m *Repository) UpsertRecipient(ctx context.Context, recipient *models.Recipient) error {
+	ret := _m.Called(ctx, recipient)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertRecipient")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Recipient) error); ok {
+		r0 = rf(ctx, recipient)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertSuppression provides a mock function with given fields: ctx, suppression
+func (_m *Repository) UpsertSuppression(ctx context.Context, suppression *models.Suppression) (bool, error) {
+	ret := _m.Called(ctx, suppression)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertSuppression")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Suppression) (bool, error)); ok {
+		return rf(ctx, suppression)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Suppression) bool); ok {
+		r0 = rf(ctx, suppression)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Suppression) error); ok {
+		r1 = rf(ctx, suppression)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
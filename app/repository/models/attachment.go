@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StoredAttachment describes a file uploaded once and attached to emails by
+// ID. The content lives in GridFS under FileID; SHA256 deduplicates uploads
+// of the same content per user.
+type StoredAttachment struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	Filename  string             `bson:"filename" json:"filename"`
+	MimeType  string             `bson:"mime_type" json:"mime_type"`
+	Size      int64              `bson:"size" json:"size"`
+	SHA256    string             `bson:"sha256" json:"sha256"`
+	FileID    primitive.ObjectID `bson:"file_id" json:"-"`
+	ExpiresAt *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // Removed by the sweeper after this time
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DownloadLink is a link to a stored attachment sent in place of the
+// attachment itself. Its token is signed, so the link is only looked up
+// to count downloads and check revocation.
+type DownloadLink struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID         string             `bson:"user_id" json:"user_id"`
+	AttachmentID   primitive.ObjectID `bson:"attachment_id" json:"attachment_id"`
+	Filename       string             `bson:"filename" json:"filename"`
+	Size           int64              `bson:"size" json:"size"`
+	Recipient      string             `bson:"recipient,omitempty" json:"recipient,omitempty"`
+	Downloads      int64              `bson:"downloads" json:"downloads"`
+	LastDownloadAt *time.Time         `bson:"last_download_at,omitempty" json:"last_download_at,omitempty"`
+	ExpiresAt      time.Time          `bson:"expires_at" json:"expires_at"`
+	RevokedAt      *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
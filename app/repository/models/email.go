@@ -16,19 +16,55 @@ const (
 	EmailStatusSent EmailStatus = "sent"
 	// EmailStatusFailed indicates the email failed to send
 	EmailStatusFailed EmailStatus = "failed"
+	// EmailStatusProcessing indicates a queue worker holds a lease on the email
+	EmailStatusProcessing EmailStatus = "processing"
+	// EmailStatusScheduled indicates the email is held until its send time
+	EmailStatusScheduled EmailStatus = "scheduled"
 )
 
 // Email represents an email document in the database
 type Email struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	From      string             `bson:"from" json:"from"`
-	To        string             `bson:"to" json:"to"`
-	Subject   string             `bson:"subject" json:"subject"`
-	Body      string             `bson:"body" json:"body"`
-	IsHTML    bool               `bson:"is_html" json:"is_html"`
-	Status    EmailStatus        `bson:"status" json:"status"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
-	SentAt    *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
-	Error     string             `bson:"error,omitempty" json:"error,omitempty"`
-} 
\ No newline at end of file
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        string             `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	From          string             `bson:"from" json:"from"`
+	To            string             `bson:"to" json:"to"`
+	Subject       string             `bson:"subject" json:"subject"`
+	Body          string             `bson:"body" json:"body"`
+	TextBody      string             `bson:"text_body,omitempty" json:"-"`
+	IsHTML        bool               `bson:"is_html" json:"is_html"`
+	ContentType   string             `bson:"content_type" json:"content_type"`
+	Attachments   []EmailAttachment  `bson:"attachments,omitempty" json:"-"`
+	AttachmentIDs []string           `bson:"attachment_ids,omitempty" json:"-"` // Attachment store references resolved at delivery
+	Calendar      *EmailCalendar     `bson:"calendar,omitempty" json:"-"`
+	TrackOpens    bool               `bson:"track_opens,omitempty" json:"-"`  // Add the tracking pixel at delivery
+	TrackClicks   bool               `bson:"track_clicks,omitempty" json:"-"` // Rewrite links to the redirect endpoint at delivery
+	Unsubscribe   bool               `bson:"unsubscribe,omitempty" json:"-"`  // Add List-Unsubscribe headers at delivery
+	List          string             `bson:"list,omitempty" json:"-"`         // List the recipient unsubscribes from
+	Topic         string             `bson:"topic,omitempty" json:"-"`        // Recipients who opted out of it are left out at delivery
+	MessageID     string             `bson:"message_id,omitempty" json:"-"`   // Sent with every attempt, so an accepted email isn't sent again
+	Status        EmailStatus        `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	MaxAttempts   int                `bson:"max_attempts" json:"max_attempts"`
+	SendAt        *time.Time         `bson:"send_at,omitempty" json:"send_at,omitempty"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedBy      string             `bson:"locked_by,omitempty" json:"-"`
+	LockedUntil   *time.Time         `bson:"locked_until,omitempty" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	Error         string             `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// EmailAttachment represents an attachment stored with a queued email
+type EmailAttachment struct {
+	Filename  string `bson:"filename" json:"filename"`
+	MimeType  string `bson:"mime_type" json:"mime_type"`
+	ContentID string `bson:"content_id,omitempty" json:"content_id,omitempty"`
+	Content   []byte `bson:"content" json:"-"`
+}
+
+// EmailCalendar represents a rendered iCalendar object stored with a queued email
+type EmailCalendar struct {
+	Method  string `bson:"method" json:"method"`
+	Content []byte `bson:"content" json:"-"`
+}
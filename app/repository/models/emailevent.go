@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailEventType is the kind of interaction of a recipient with a sent
+// email
+type EmailEventType string
+
+const (
+	// EmailEventOpen indicates the tracking pixel of an email was loaded
+	EmailEventOpen EmailEventType = "open"
+	// EmailEventClick indicates a tracked link of an email was followed
+	EmailEventClick EmailEventType = "click"
+	// EmailEventUnsubscribe indicates the recipient followed the
+	// unsubscribe link of an email
+	EmailEventUnsubscribe EmailEventType = "unsubscribe"
+)
+
+// EmailEvent records an interaction of a recipient with a sent email. It
+// is linked to the EmailLog of the email by its Message-ID.
+type EmailEvent struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Type       EmailEventType      `bson:"type" json:"type"`
+	UserID     string              `bson:"user_id,omitempty" json:"-"`
+	MessageID  string              `bson:"message_id" json:"message_id"`
+	EmailLogID *primitive.ObjectID `bson:"email_log_id,omitempty" json:"email_log_id,omitempty"`
+	Recipient  string              `bson:"recipient,omitempty" json:"recipient,omitempty"` // Empty for emails to several recipients
+	IP         string              `bson:"ip,omitempty" json:"ip,omitempty"`               // Anonymised when configured
+	UserAgent  string              `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	URL        string              `bson:"url,omitempty" json:"url,omitempty"`   // Target of a clicked link
+	List       string              `bson:"list,omitempty" json:"list,omitempty"` // List unsubscribed from
+
+	// Proxy names a known image proxy the request came through, such as
+	// google or apple. Prefetched proxies load images when an email is
+	// delivered, so their opens don't mean the recipient read it.
+	Proxy      string `bson:"proxy,omitempty" json:"proxy,omitempty"`
+	Prefetched bool   `bson:"prefetched" json:"prefetched"`
+
+	OccurredAt time.Time `bson:"occurred_at" json:"occurred_at"`
+}
@@ -8,13 +8,83 @@ import (
 
 // EmailLog represents a log of an email that was sent
 type EmailLog struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	From        string             `bson:"from" json:"from"`
-	To          string             `bson:"to" json:"to"`
-	Subject     string             `bson:"subject" json:"subject"`
-	ContentType string             `bson:"content_type" json:"content_type"`
-	Success     bool               `bson:"success" json:"success"`
-	SentAt      time.Time          `bson:"sent_at" json:"sent_at"`
-	Error       string             `bson:"error,omitempty" json:"error,omitempty"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-} 
\ No newline at end of file
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	EmailID     *primitive.ObjectID `bson:"email_id,omitempty" json:"email_id,omitempty"`
+	UserID      string              `bson:"user_id,omitempty" json:"-"`
+	MessageID   string              `bson:"message_id,omitempty" json:"message_id,omitempty"` // Without angle brackets
+	From        string              `bson:"from" json:"from"`
+	To          string              `bson:"to" json:"to"`
+	Subject     string              `bson:"subject" json:"subject"`
+	ContentType string              `bson:"content_type" json:"content_type"`
+	Success     bool                `bson:"success" json:"success"`
+	Status      EmailLogStatus      `bson:"status,omitempty" json:"status,omitempty"`
+	SentAt      time.Time           `bson:"sent_at" json:"sent_at"`
+	Error       string              `bson:"error,omitempty" json:"error,omitempty"`
+	Scan        *AttachmentScan     `bson:"scan,omitempty" json:"scan,omitempty"`
+	Feedback    []DeliveryFeedback  `bson:"feedback,omitempty" json:"feedback,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// EmailLogStatus records an outcome of a send that Success doesn't tell
+type EmailLogStatus string
+
+const (
+	// EmailLogStatusSuppressedDuplicate indicates the email was not sent because an
+	// identical one was sent within the dedup window
+	EmailLogStatusSuppressedDuplicate EmailLogStatus = "suppressed_duplicate"
+
+	// EmailLogStatusSuppressed indicates the email was not sent because all of
+	// its recipients are on the suppression list
+	EmailLogStatusSuppressed EmailLogStatus = "suppressed"
+
+	// EmailLogStatusBounced indicates a recipient's server reported that the
+	// email could not be delivered
+	EmailLogStatusBounced EmailLogStatus = "bounced"
+
+	// EmailLogStatusComplained indicates a recipient reported the email as
+	// spam or abuse
+	EmailLogStatusComplained EmailLogStatus = "complained"
+)
+
+// FeedbackType is the kind of report received about a sent email
+type FeedbackType string
+
+const (
+	// FeedbackTypeBounce is a delivery status notification (RFC 3464)
+	FeedbackTypeBounce FeedbackType = "bounce"
+	// FeedbackTypeComplaint is an abuse feedback report (RFC 5965)
+	FeedbackTypeComplaint FeedbackType = "complaint"
+)
+
+// DeliveryFeedback records a bounce or complaint about a sent email
+type DeliveryFeedback struct {
+	Type       FeedbackType `bson:"type" json:"type"`
+	Recipient  string       `bson:"recipient" json:"recipient"`
+	Action     string       `bson:"action,omitempty" json:"action,omitempty"`         // DSN action, such as failed or delayed
+	Status     string       `bson:"status,omitempty" json:"status,omitempty"`         // Enhanced status code, such as 5.1.1
+	Diagnostic string       `bson:"diagnostic,omitempty" json:"diagnostic,omitempty"` // Diagnostic code of the reporting server
+	Category   string       `bson:"category,omitempty" json:"category,omitempty"`     // ARF feedback type, such as abuse
+	Hard       bool         `bson:"hard" json:"hard"`                                 // A permanent failure
+	ReceivedAt time.Time    `bson:"received_at" json:"received_at"`
+}
+
+// ScanStatus is the malware scan verdict of an email's attachments
+type ScanStatus string
+
+const (
+	// ScanStatusClean indicates no malware was found
+	ScanStatusClean ScanStatus = "clean"
+	// ScanStatusInfected indicates an attachment contains malware and the email was not sent
+	ScanStatusInfected ScanStatus = "infected"
+	// ScanStatusError indicates an attachment couldn't be scanned
+	ScanStatusError ScanStatus = "error"
+)
+
+// AttachmentScan records the malware scan of the attachments of a send
+type AttachmentScan struct {
+	Status    ScanStatus `bson:"status" json:"status"`
+	Filename  string     `bson:"filename,omitempty" json:"filename,omitempty"` // The infected or unscanned attachment
+	Signature string     `bson:"signature,omitempty" json:"signature,omitempty"`
+	Error     string     `bson:"error,omitempty" json:"error,omitempty"`
+	ScannedAt time.Time  `bson:"scanned_at" json:"scanned_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// IdempotencyStatus is the state of a request sent with an idempotency key
+type IdempotencyStatus string
+
+const (
+	IdempotencyInProgress IdempotencyStatus = "in_progress"
+	IdempotencyCompleted  IdempotencyStatus = "completed"
+)
+
+// IdempotencyRecord holds the first response to a request sent with an
+// Idempotency-Key header, so retries of the request replay it instead of
+// sending the email again. Keys are scoped to the user that sent them.
+type IdempotencyRecord struct {
+	UserID      string            `bson:"user_id" json:"user_id"`
+	Key         string            `bson:"key" json:"key"`
+	Method      string            `bson:"method" json:"method"`
+	Path        string            `bson:"path" json:"path"`
+	RequestHash string            `bson:"request_hash" json:"request_hash"`
+	Status      IdempotencyStatus `bson:"status" json:"status"`
+	Response    *StoredResponse   `bson:"response,omitempty" json:"response,omitempty"`
+	CreatedAt   time.Time         `bson:"created_at" json:"created_at"`
+	CompletedAt *time.Time        `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiresAt   time.Time         `bson:"expires_at" json:"expires_at"`
+}
+
+// StoredResponse is a response replayed to retries of a request
+type StoredResponse struct {
+	StatusCode  int    `bson:"status_code" json:"status_code"`
+	ContentType string `bson:"content_type,omitempty" json:"content_type,omitempty"`
+	Body        []byte `bson:"body,omitempty" json:"body,omitempty"`
+}
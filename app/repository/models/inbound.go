@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InboundStatus represents the webhook delivery status of a received email
+type InboundStatus string
+
+const (
+	// InboundStatusPending indicates webhooks are waiting to be called
+	InboundStatusPending InboundStatus = "pending"
+	// InboundStatusProcessing indicates a dispatcher holds a lease on the message
+	InboundStatusProcessing InboundStatus = "processing"
+	// InboundStatusDelivered indicates all webhooks accepted the message
+	InboundStatusDelivered InboundStatus = "delivered"
+	// InboundStatusFailed indicates a webhook still failed after the last attempt
+	InboundStatusFailed InboundStatus = "failed"
+)
+
+// InboundMessage represents an email received by the inbound SMTP listener
+type InboundMessage struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+
+	// Envelope of the SMTP transaction
+	MailFrom   string   `bson:"mail_from" json:"mail_from"` // Empty for bounces
+	Recipients []string `bson:"recipients" json:"recipients"`
+	RemoteAddr string   `bson:"remote_addr" json:"remote_addr"`
+	Helo       string   `bson:"helo" json:"helo"`
+	TLS        bool     `bson:"tls" json:"tls"`
+
+	// Parsed message
+	MessageID   string              `bson:"message_id,omitempty" json:"message_id,omitempty"`
+	InReplyTo   string              `bson:"in_reply_to,omitempty" json:"in_reply_to,omitempty"`
+	References  []string            `bson:"references,omitempty" json:"references,omitempty"`
+	Subject     string              `bson:"subject" json:"subject"`
+	From        []InboundAddress    `bson:"from,omitempty" json:"from,omitempty"`
+	To          []InboundAddress    `bson:"to,omitempty" json:"to,omitempty"`
+	Cc          []InboundAddress    `bson:"cc,omitempty" json:"cc,omitempty"`
+	ReplyTo     []InboundAddress    `bson:"reply_to,omitempty" json:"reply_to,omitempty"`
+	Date        *time.Time          `bson:"date,omitempty" json:"date,omitempty"`
+	Headers     map[string][]string `bson:"headers" json:"headers"`
+	Text        string              `bson:"text,omitempty" json:"text,omitempty"`
+	HTML        string              `bson:"html,omitempty" json:"html,omitempty"`
+	Attachments []InboundAttachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	Size        int64               `bson:"size" json:"size"`
+
+	// Webhook delivery, claimed by dispatchers like the outbound queue
+	Status        InboundStatus     `bson:"status" json:"status"`
+	Webhooks      []InboundDelivery `bson:"webhooks,omitempty" json:"webhooks,omitempty"`
+	Attempts      int               `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time         `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedBy      string            `bson:"locked_by,omitempty" json:"-"`
+	LockedUntil   *time.Time        `bson:"locked_until,omitempty" json:"-"`
+
+	ReceivedAt time.Time `bson:"received_at" json:"received_at"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// InboundAddress represents a mailbox of an address header
+type InboundAddress struct {
+	Name    string `bson:"name,omitempty" json:"name,omitempty"`
+	Address string `bson:"address" json:"address"`
+}
+
+// InboundAttachment represents an attachment of a received email
+type InboundAttachment struct {
+	Filename    string `bson:"filename,omitempty" json:"filename,omitempty"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	ContentID   string `bson:"content_id,omitempty" json:"content_id,omitempty"`
+	Inline      bool   `bson:"inline" json:"inline"`
+	Size        int64  `bson:"size" json:"size"`
+	Content     []byte `bson:"content" json:"content"` // Base64 in JSON
+}
+
+// InboundDelivery represents a webhook a received email is posted to and
+// the outcome of its last attempt
+type InboundDelivery struct {
+	URL         string     `bson:"url" json:"url"`
+	Recipients  []string   `bson:"recipients" json:"recipients"` // Recipients whose pattern routes to the webhook
+	Delivered   bool       `bson:"delivered" json:"delivered"`
+	StatusCode  int        `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Error       string     `bson:"error,omitempty" json:"error,omitempty"`
+	DeliveredAt *time.Time `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MergeJobStatus represents the state of a mail merge job
+type MergeJobStatus string
+
+const (
+	// MergeJobPending indicates the job waits for a runner
+	MergeJobPending MergeJobStatus = "pending"
+	// MergeJobRunning indicates a runner holds a lease on the job
+	MergeJobRunning MergeJobStatus = "running"
+	// MergeJobCompleted indicates every recipient of the job was processed
+	MergeJobCompleted MergeJobStatus = "completed"
+	// MergeJobCancelled indicates the job was stopped by its owner
+	MergeJobCancelled MergeJobStatus = "cancelled"
+)
+
+// MergeJob is a mail merge: one message sent to every recipient of a list
+// with the variables of the recipient
+type MergeJob struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID  string             `bson:"user_id" json:"-"`
+	Message MergeMessage       `bson:"message" json:"message"`
+	Status  MergeJobStatus     `bson:"status" json:"status"`
+
+	// Progress counts the recipients by outcome
+	Total      int `bson:"total" json:"total"`
+	Invalid    int `bson:"invalid" json:"invalid"` // Skipped when the job was created
+	Queued     int `bson:"queued" json:"queued"`
+	Suppressed int `bson:"suppressed" json:"suppressed"`
+	Failed     int `bson:"failed" json:"failed"`
+
+	// Lease of the runner processing the job, like the outbound queue
+	LockedBy    string     `bson:"locked_by,omitempty" json:"-"`
+	LockedUntil *time.Time `bson:"locked_until,omitempty" json:"-"`
+
+	StartedAt   *time.Time `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	CompletedAt *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// MergeMessage is the message of a mail merge. Its subject and body, or
+// the stored template, are rendered with the variables of each recipient.
+type MergeMessage struct {
+	From           string     `bson:"from" json:"from"`
+	Subject        string     `bson:"subject,omitempty" json:"subject,omitempty"`
+	Body           string     `bson:"body,omitempty" json:"body,omitempty"`
+	IsHTML         bool       `bson:"is_html" json:"is_html"`
+	Format         string     `bson:"format,omitempty" json:"format,omitempty"`
+	TemplateID     string     `bson:"template_id,omitempty" json:"template_id,omitempty"`
+	Locale         string     `bson:"locale,omitempty" json:"locale,omitempty"`
+	Topic          string     `bson:"topic,omitempty" json:"topic,omitempty"`
+	Unsubscribe    bool       `bson:"unsubscribe,omitempty" json:"unsubscribe,omitempty"`
+	List           string     `bson:"list,omitempty" json:"list,omitempty"`
+	TrackOpens     *bool      `bson:"track_opens,omitempty" json:"track_opens,omitempty"`
+	TrackClicks    *bool      `bson:"track_clicks,omitempty" json:"track_clicks,omitempty"`
+	SendAt         *time.Time `bson:"send_at,omitempty" json:"send_at,omitempty"`
+	AllowDuplicate bool       `bson:"allow_duplicate,omitempty" json:"allow_duplicate,omitempty"`
+}
+
+// MergeRowStatus represents the outcome for a recipient of a mail merge
+type MergeRowStatus string
+
+const (
+	// MergeRowPending indicates the recipient waits for its batch
+	MergeRowPending MergeRowStatus = "pending"
+	// MergeRowQueued indicates the email of the recipient is in the outbound queue
+	MergeRowQueued MergeRowStatus = "queued"
+	// MergeRowSuppressed indicates the recipient is suppressed or opted out
+	MergeRowSuppressed MergeRowStatus = "suppressed"
+	// MergeRowFailed indicates the email of the recipient couldn't be queued
+	MergeRowFailed MergeRowStatus = "failed"
+	// MergeRowInvalid indicates the row failed validation and was skipped
+	MergeRowInvalid MergeRowStatus = "invalid"
+)
+
+// MergeRow is a recipient of a mail merge with its variables
+type MergeRow struct {
+	ID      primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	JobID   primitive.ObjectID     `bson:"job_id" json:"job_id"`
+	Row     int                    `bson:"row" json:"row"` // Counted from 1, not counting the CSV header
+	To      string                 `bson:"to" json:"to"`
+	Data    map[string]interface{} `bson:"data,omitempty" json:"data,omitempty"`
+	Status  MergeRowStatus         `bson:"status" json:"status"`
+	EmailID *primitive.ObjectID    `bson:"email_id,omitempty" json:"email_id,omitempty"`
+	Error   string                 `bson:"error,omitempty" json:"error,omitempty"`
+
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Recipient holds the stored preferences of an email address for a user
+type Recipient struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	Email     string             `bson:"email" json:"email"`
+	Locale    string             `bson:"locale,omitempty" json:"locale,omitempty"`
+	OptedOut  []string           `bson:"opted_out,omitempty" json:"opted_out,omitempty"` // Topics the recipient doesn't receive
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
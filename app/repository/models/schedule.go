@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ScheduleStatus represents the state of a recurring schedule
+type ScheduleStatus string
+
+const (
+	// ScheduleStatusActive indicates the schedule fires at its next run time
+	ScheduleStatusActive ScheduleStatus = "active"
+	// ScheduleStatusPaused indicates the schedule is suspended by its owner
+	ScheduleStatusPaused ScheduleStatus = "paused"
+	// ScheduleStatusCompleted indicates the schedule has no runs left before its end date
+	ScheduleStatusCompleted ScheduleStatus = "completed"
+)
+
+// Schedule represents a recurring email schedule in the database
+type Schedule struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID         string             `bson:"user_id" json:"user_id"`
+	Name           string             `bson:"name" json:"name"`
+	CronExpression string             `bson:"cron_expression" json:"cron_expression"`
+	TimeZone       string             `bson:"time_zone" json:"time_zone"`
+	Message        ScheduleMessage    `bson:"message" json:"message"`
+	Recipients     []string           `bson:"recipients" json:"recipients"`
+	StartAt        *time.Time         `bson:"start_at,omitempty" json:"start_at,omitempty"`
+	EndAt          *time.Time         `bson:"end_at,omitempty" json:"end_at,omitempty"`
+	Status         ScheduleStatus     `bson:"status" json:"status"`
+	NextRunAt      *time.Time         `bson:"next_run_at,omitempty" json:"next_run_at,omitempty"`
+	LastRunAt      *time.Time         `bson:"last_run_at,omitempty" json:"last_run_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ScheduleMessage represents the email sent on every run of a schedule.
+// A TemplateID renders the published version of a stored template with
+// Data instead of Body.
+type ScheduleMessage struct {
+	From       string                 `bson:"from" json:"from"`
+	Subject    string                 `bson:"subject" json:"subject"`
+	Body       string                 `bson:"body" json:"body"`
+	IsHTML     bool                   `bson:"is_html" json:"is_html"`
+	TemplateID string                 `bson:"template_id,omitempty" json:"template_id,omitempty"`
+	Data       map[string]interface{} `bson:"data,omitempty" json:"data,omitempty"`
+	Locale     string                 `bson:"locale,omitempty" json:"locale,omitempty"`
+}
+
+// ScheduleRun records a single firing of a recurring schedule
+type ScheduleRun struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	ScheduleID   primitive.ObjectID   `bson:"schedule_id" json:"schedule_id"`
+	UserID       string               `bson:"user_id" json:"user_id"`
+	ScheduledFor time.Time            `bson:"scheduled_for" json:"scheduled_for"`
+	EmailIDs     []primitive.ObjectID `bson:"email_ids" json:"email_ids"`
+	Failed       int                  `bson:"failed" json:"failed"`
+	Error        string               `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt    time.Time            `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// SendFingerprint claims the content of an email sent by a user for the
+// dedup window, so identical sends within the window are suppressed
+type SendFingerprint struct {
+	UserID    string    `bson:"user_id" json:"user_id"`
+	Hash      string    `bson:"hash" json:"hash"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
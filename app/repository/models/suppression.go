@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SuppressionReason is why emails to an address or domain are suppressed
+type SuppressionReason string
+
+const (
+	SuppressionReasonHardBounce  SuppressionReason = "hard_bounce"
+	SuppressionReasonComplaint   SuppressionReason = "complaint"
+	SuppressionReasonUnsubscribe SuppressionReason = "unsubscribe"
+	SuppressionReasonManual      SuppressionReason = "manual"
+)
+
+// SuppressionScope is who a suppression applies to
+type SuppressionScope string
+
+const (
+	// SuppressionScopeGlobal applies to the emails of all users
+	SuppressionScopeGlobal SuppressionScope = "global"
+	// SuppressionScopeUser applies to the emails of the user that owns it
+	SuppressionScopeUser SuppressionScope = "user"
+)
+
+// SuppressionType tells whether a suppression matches an address or a
+// whole domain
+type SuppressionType string
+
+const (
+	SuppressionTypeAddress SuppressionType = "address"
+	SuppressionTypeDomain  SuppressionType = "domain"
+)
+
+// Suppression stops emails to an address or domain. Values are normalised
+// to lower case and global suppressions have an empty UserID. A suppression
+// with a List only stops the emails sent to that mailing list or topic.
+type Suppression struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id,omitempty"`
+	Scope     SuppressionScope   `bson:"scope" json:"scope"`
+	Type      SuppressionType    `bson:"type" json:"type"`
+	Value     string             `bson:"value" json:"value"`
+	List      string             `bson:"list,omitempty" json:"list,omitempty"`
+	Reason    SuppressionReason  `bson:"reason" json:"reason"`
+	Note      string             `bson:"note,omitempty" json:"note,omitempty"`
+	ExpiresAt *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
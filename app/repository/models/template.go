@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Template represents a stored email template. Its content lives in
+// immutable versions; PublishedVersion points at the one used for sending.
+type Template struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID           string             `bson:"user_id" json:"user_id"`
+	Name             string             `bson:"name" json:"name"`
+	Description      string             `bson:"description,omitempty" json:"description,omitempty"`
+	LatestVersion    int                `bson:"latest_version" json:"latest_version"`
+	PublishedVersion int                `bson:"published_version" json:"published_version"`
+	Locales          []string           `bson:"locales,omitempty" json:"locales,omitempty"` // Locales the template should be translated into
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// TemplateVersion represents an immutable revision of a template's content
+type TemplateVersion struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TemplateID primitive.ObjectID `bson:"template_id" json:"template_id"`
+	Version    int                `bson:"version" json:"version"`
+	Subject    string             `bson:"subject" json:"subject"`
+	Text       string             `bson:"text,omitempty" json:"text,omitempty"`
+	HTML       string             `bson:"html,omitempty" json:"html,omitempty"`
+	// Translations holds per-locale variants keyed by normalised language tag
+	Translations map[string]TemplateContent `bson:"translations,omitempty" json:"translations,omitempty"`
+	CreatedAt    time.Time                  `bson:"created_at" json:"created_at"`
+}
+
+// TemplateContent represents the localised content of a template version
+type TemplateContent struct {
+	Subject string `bson:"subject" json:"subject"`
+	Text    string `bson:"text,omitempty" json:"text,omitempty"`
+	HTML    string `bson:"html,omitempty" json:"html,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Topic is a category of the emails of a user, such as product updates or
+// billing, that sends are tagged with and recipients opt out of. Recipients
+// can't opt out of transactional topics.
+type Topic struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        string             `bson:"user_id" json:"-"`
+	Name          string             `bson:"name" json:"name"` // Lower-case key sends are tagged with
+	Label         string             `bson:"label" json:"label"`
+	Description   string             `bson:"description,omitempty" json:"description,omitempty"`
+	Transactional bool               `bson:"transactional" json:"transactional"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookEventType is the kind of email event a webhook subscribes to
+type WebhookEventType string
+
+const (
+	// WebhookEventSent indicates the SMTP server accepted an email
+	WebhookEventSent WebhookEventType = "sent"
+	// WebhookEventFailed indicates an email could not be sent, after the
+	// last attempt for queued emails
+	WebhookEventFailed WebhookEventType = "failed"
+	// WebhookEventBounced indicates a recipient's server reported that an
+	// email could not be delivered
+	WebhookEventBounced WebhookEventType = "bounced"
+	// WebhookEventOpened indicates the tracking pixel of an email was loaded
+	WebhookEventOpened WebhookEventType = "opened"
+	// WebhookEventClicked indicates a tracked link of an email was followed
+	WebhookEventClicked WebhookEventType = "clicked"
+	// WebhookEventUnsubscribed indicates a recipient unsubscribed
+	WebhookEventUnsubscribed WebhookEventType = "unsubscribed"
+)
+
+// WebhookEventTypes lists the events webhooks can subscribe to
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventSent,
+	WebhookEventFailed,
+	WebhookEventBounced,
+	WebhookEventOpened,
+	WebhookEventClicked,
+	WebhookEventUnsubscribed,
+}
+
+// Webhook represents a subscription of a user to email events
+type Webhook struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      string             `bson:"user_id" json:"-"`
+	URL         string             `bson:"url" json:"url"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Events      []WebhookEventType `bson:"events" json:"events"`
+	Secret      string             `bson:"secret" json:"-"` // Signs the payloads
+	Enabled     bool               `bson:"enabled" json:"enabled"`
+
+	// ConsecutiveFailures counts the failed attempts since the last
+	// successful one. The webhook is disabled when it reaches the limit.
+	ConsecutiveFailures int        `bson:"consecutive_failures" json:"consecutive_failures"`
+	DisabledAt          *time.Time `bson:"disabled_at,omitempty" json:"disabled_at,omitempty"`
+	DisabledReason      string     `bson:"disabled_reason,omitempty" json:"disabled_reason,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Subscribes reports whether the webhook receives events of a type
+func (w *Webhook) Subscribes(eventType WebhookEventType) bool {
+	for _, event := range w.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus represents the state of a delivery of an event to
+// a webhook
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending indicates the delivery waits for its next attempt
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryProcessing indicates a dispatcher holds a lease on the delivery
+	WebhookDeliveryProcessing WebhookDeliveryStatus = "processing"
+	// WebhookDeliveryDelivered indicates the webhook accepted the event
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed indicates the last attempt failed or the webhook
+	// was disabled
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is an event waiting in the outbox of a webhook, and the
+// log of its attempts once sent
+type WebhookDelivery struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID primitive.ObjectID `bson:"webhook_id" json:"webhook_id"`
+	UserID    string             `bson:"user_id" json:"-"`
+	EventID   string             `bson:"event_id" json:"event_id"` // Shared by redeliveries of the same event
+	EventType WebhookEventType   `bson:"event_type" json:"event_type"`
+	Payload   string             `bson:"payload" json:"payload"` // JSON body, signed as is
+
+	// RedeliveryOf links a manual redelivery to the delivery it repeats
+	RedeliveryOf *primitive.ObjectID `bson:"redelivery_of,omitempty" json:"redelivery_of,omitempty"`
+
+	// Delivery state, claimed by dispatchers like the outbound queue
+	Status        WebhookDeliveryStatus `bson:"status" json:"status"`
+	Attempts      int                   `bson:"attempts" json:"attempts"`
+	AttemptLog    []WebhookAttempt      `bson:"attempt_log,omitempty" json:"attempt_log,omitempty"`
+	NextAttemptAt time.Time             `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedBy      string                `bson:"locked_by,omitempty" json:"-"`
+	LockedUntil   *time.Time            `bson:"locked_until,omitempty" json:"-"`
+
+	DeliveredAt *time.Time `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// WebhookAttempt records a call of a webhook
+type WebhookAttempt struct {
+	StatusCode  int       `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Error       string    `bson:"error,omitempty" json:"error,omitempty"`
+	Duration    int64     `bson:"duration_ms" json:"duration_ms"`
+	AttemptedAt time.Time `bson:"attempted_at" json:"attempted_at"`
+}
@@ -0,0 +1,66 @@
+package recipient
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByEmail retrieves the preferences of a recipient
+func (m *mongoDB) FindByEmail(ctx context.Context, userID, email string) (*models.Recipient, error) {
+	result := &models.Recipient{}
+	err := m.collection.FindOne(ctx, bson.M{"user_id": userID, "email": strings.ToLower(email)}).Decode(result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecipientNotFound
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAll retrieves recipients with optional filtering and pagination
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Recipient, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"email": 1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	recipients := make([]*models.Recipient, 0)
+	if err := cursor.All(ctx, &recipients); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return recipients, total, nil
+}
+
+// CreateIndexes creates the unique index on user and address
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,37 @@
+package recipient
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "recipients"
+
+var (
+	ErrRecipientNotFound = errors.New("recipient not found")
+)
+
+// RecipientRepository stores per-user recipient preferences. Recipients are
+// keyed by user and lower-cased email address.
+type RecipientRepository interface {
+	Upsert(ctx context.Context, recipient *models.Recipient) error
+	UpdateOptedOut(ctx context.Context, userID, email string, optedOut []string) (*models.Recipient, error)
+	FindByEmail(ctx context.Context, userID, email string) (*models.Recipient, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Recipient, int64, error)
+	Delete(ctx context.Context, userID, email string) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) RecipientRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
@@ -0,0 +1,74 @@
+package recipient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Upsert creates or replaces the preferences of a recipient
+func (m *mongoDB) Upsert(ctx context.Context, recipient *models.Recipient) error {
+	now := time.Now()
+	recipient.Email = strings.ToLower(recipient.Email)
+	recipient.UpdatedAt = now
+
+	filter := bson.M{"user_id": recipient.UserID, "email": recipient.Email}
+	update := bson.M{
+		"$set": bson.M{
+			"locale":     recipient.Locale,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(recipient)
+}
+
+// UpdateOptedOut replaces the topics a recipient opted out of, creating
+// the recipient when needed, and returns the updated recipient
+func (m *mongoDB) UpdateOptedOut(ctx context.Context, userID, email string, optedOut []string) (*models.Recipient, error) {
+	now := time.Now()
+	if optedOut == nil {
+		optedOut = []string{}
+	}
+
+	filter := bson.M{"user_id": userID, "email": strings.ToLower(email)}
+	update := bson.M{
+		"$set": bson.M{
+			"opted_out":  optedOut,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	recipient := &models.Recipient{}
+	if err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(recipient); err != nil {
+		return nil, err
+	}
+	return recipient, nil
+}
+
+// Delete removes the preferences of a recipient
+func (m *mongoDB) Delete(ctx context.Context, userID, email string) error {
+	result, err := m.collection.DeleteOne(ctx, bson.M{"user_id": userID, "email": strings.ToLower(email)})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecipientNotFound
+	}
+
+	return nil
+}
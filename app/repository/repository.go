@@ -1,14 +1,34 @@
 package repository
 
 import (
+	"GoMail/app/repository/attachment"
+	"GoMail/app/repository/dedup"
+	"GoMail/app/repository/downloadlink"
 	"GoMail/app/repository/email"
+	"GoMail/app/repository/emailevent"
 	"GoMail/app/repository/emaillog"
+	"GoMail/app/repository/emailtemplate"
+	"GoMail/app/repository/idempotency"
+	"GoMail/app/repository/inbound"
+	"GoMail/app/repository/mergejob"
+	"GoMail/app/repository/mergerow"
 	"GoMail/app/repository/models"
+	"GoMail/app/repository/recipient"
+	"GoMail/app/repository/schedule"
+	"GoMail/app/repository/schedulerun"
+	"GoMail/app/repository/suppression"
+	"GoMail/app/repository/templateversion"
 	"GoMail/app/repository/token"
 	"GoMail/app/repository/user"
+	"GoMail/app/repository/topic"
+	"GoMail/app/repository/webhook"
+	"GoMail/app/repository/webhookdelivery"
 	"context"
+	"io"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -19,12 +39,156 @@ type DB struct {
 type Repository interface {
 	SaveEmail(ctx context.Context, email *models.Email) error
 	FindEmails(ctx context.Context, filter interface{}, page, limit int) ([]*models.Email, int64, error)
-	DeleteEmail(ctx context.Context, id interface{}) error
+	DeleteEmail(ctx context.Context, id interface{}, conditions ...bson.M) error
+	FindEmailByID(ctx context.Context, id string) (*models.Email, error)
+	
+	// Queue methods
+	ClaimEmail(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.Email, error)
+	ReleaseEmail(ctx context.Context, email *models.Email, owner string) error
+	InitEmailIndexes(ctx context.Context) error
+	
+	// Scheduling methods
+	PromoteScheduledEmails(ctx context.Context, now time.Time) (int64, error)
+	RescheduleEmail(ctx context.Context, id, userID string, sendAt time.Time) (*models.Email, error)
+	
+	// Recurring schedule methods
+	SaveSchedule(ctx context.Context, schedule *models.Schedule) error
+	FindScheduleByID(ctx context.Context, id string) (*models.Schedule, error)
+	FindSchedules(ctx context.Context, filter interface{}, page, limit int) ([]*models.Schedule, int64, error)
+	DeleteSchedule(ctx context.Context, id string) error
+	FindDueSchedules(ctx context.Context, now time.Time, limit int) ([]*models.Schedule, error)
+	AdvanceSchedule(ctx context.Context, schedule *models.Schedule, previous time.Time) (bool, error)
+	SaveScheduleRun(ctx context.Context, run *models.ScheduleRun) error
+	FindScheduleRuns(ctx context.Context, filter interface{}, page, limit int) ([]*models.ScheduleRun, int64, error)
+	InitScheduleIndexes(ctx context.Context) error
+	
+	// Template methods
+	SaveTemplate(ctx context.Context, template *models.Template) error
+	FindTemplateByID(ctx context.Context, id string) (*models.Template, error)
+	FindTemplates(ctx context.Context, filter interface{}, page, limit int) ([]*models.Template, int64, error)
+	DeleteTemplate(ctx context.Context, id string) error
+	ReserveTemplateVersion(ctx context.Context, id string, latest int) error
+	InsertTemplateVersion(ctx context.Context, version *models.TemplateVersion) error
+	FindTemplateVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*models.TemplateVersion, error)
+	FindTemplateVersions(ctx context.Context, templateID primitive.ObjectID) ([]*models.TemplateVersion, error)
+	DeleteTemplateVersions(ctx context.Context, templateID primitive.ObjectID) error
+	InitTemplateIndexes(ctx context.Context) error
+	
+	// Recipient preference methods
+	UpsertRecipient(ctx context.Context, recipient *models.Recipient) error
+	UpdateRecipientOptedOut(ctx context.Context, userID, email string, optedOut []string) (*models.Recipient, error)
+	FindRecipient(ctx context.Context, userID, email string) (*models.Recipient, error)
+	FindRecipients(ctx context.Context, filter interface{}, page, limit int) ([]*models.Recipient, int64, error)
+	DeleteRecipient(ctx context.Context, userID, email string) error
+	InitRecipientIndexes(ctx context.Context) error
+
+	// Topic methods
+	SaveTopic(ctx context.Context, topic *models.Topic) error
+	UpdateTopic(ctx context.Context, topic *models.Topic) error
+	FindTopic(ctx context.Context, userID, name string) (*models.Topic, error)
+	FindUserTopics(ctx context.Context, userID string) ([]*models.Topic, error)
+	DeleteTopic(ctx context.Context, id primitive.ObjectID) error
+	InitTopicIndexes(ctx context.Context) error
+	
+	// Stored attachment methods
+	SaveAttachment(ctx context.Context, attachment *models.StoredAttachment) error
+	FindAttachmentByID(ctx context.Context, id string) (*models.StoredAttachment, error)
+	FindAttachmentByHash(ctx context.Context, userID, hash string) (*models.StoredAttachment, error)
+	FindAttachments(ctx context.Context, filter interface{}, page, limit int) ([]*models.StoredAttachment, int64, error)
+	FindExpiredAttachments(ctx context.Context, now time.Time, limit int) ([]*models.StoredAttachment, error)
+	SetAttachmentExpiry(ctx context.Context, id primitive.ObjectID, expiresAt *time.Time) error
+	DeleteAttachment(ctx context.Context, id primitive.ObjectID) error
+	UploadAttachmentFile(ctx context.Context, filename string, content io.Reader) (primitive.ObjectID, error)
+	OpenAttachmentFile(ctx context.Context, fileID primitive.ObjectID) (io.ReadCloser, error)
+	DeleteAttachmentFile(ctx context.Context, fileID primitive.ObjectID) error
+	InitAttachmentIndexes(ctx context.Context) error
+	
+	// Download link methods
+	SaveDownloadLink(ctx context.Context, link *models.DownloadLink) error
+	FindDownloadLinkByID(ctx context.Context, id string) (*models.DownloadLink, error)
+	FindDownloadLinks(ctx context.Context, filter interface{}, page, limit int) ([]*models.DownloadLink, int64, error)
+	RecordDownload(ctx context.Context, id primitive.ObjectID, now time.Time) (*models.DownloadLink, error)
+	RevokeDownloadLink(ctx context.Context, id primitive.ObjectID, now time.Time) error
+	InitDownloadLinkIndexes(ctx context.Context) error
+	
+	// Idempotency key methods
+	InsertIdempotencyRecord(ctx context.Context, record *models.IdempotencyRecord) error
+	FindIdempotencyRecord(ctx context.Context, userID, key string) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyRecord(ctx context.Context, userID, key string, response *models.StoredResponse, now time.Time) error
+	DeleteStaleIdempotencyRecord(ctx context.Context, userID, key string, now, lockedBefore time.Time) (bool, error)
+	ReleaseIdempotencyRecord(ctx context.Context, userID, key string) error
+	InitIdempotencyIndexes(ctx context.Context) error
+	
+	// Send dedup methods
+	ClaimSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint) (bool, error)
+	ReleaseSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint) error
+	ExtendSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint, expiresAt time.Time) error
+	InitDedupIndexes(ctx context.Context) error
+	
+	// Suppression methods
+	SaveSuppression(ctx context.Context, suppression *models.Suppression) error
+	UpsertSuppression(ctx context.Context, suppression *models.Suppression) (bool, error)
+	UpdateSuppression(ctx context.Context, suppression *models.Suppression) error
+	FindSuppressionByID(ctx context.Context, id string) (*models.Suppression, error)
+	FindSuppressions(ctx context.Context, filter interface{}, page, limit int) ([]*models.Suppression, int64, error)
+	FindActiveSuppression(ctx context.Context, userID, list string, values []string, now time.Time) (*models.Suppression, error)
+	DeleteSuppression(ctx context.Context, id primitive.ObjectID) error
+	InitSuppressionIndexes(ctx context.Context) error
+
+	// Inbound message methods
+	SaveInboundMessage(ctx context.Context, message *models.InboundMessage) error
+	ClaimInboundMessage(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.InboundMessage, error)
+	ReleaseInboundMessage(ctx context.Context, message *models.InboundMessage, owner string) error
+	InitInboundIndexes(ctx context.Context) error
 	
 	// Email Log methods
 	SaveEmailLog(ctx context.Context, emailLog *models.EmailLog) error
 	FindEmailLogs(ctx context.Context, filter interface{}, page, limit int) ([]*models.EmailLog, int64, error)
 	FindEmailLogByID(ctx context.Context, id string) (*models.EmailLog, error)
+	FindEmailLogByMessageID(ctx context.Context, messageID string) (*models.EmailLog, error)
+	AddEmailLogFeedback(ctx context.Context, id primitive.ObjectID, status models.EmailLogStatus, feedback models.DeliveryFeedback) error
+	InitEmailLogIndexes(ctx context.Context) error
+
+	// Email event methods
+	SaveEmailEvent(ctx context.Context, event *models.EmailEvent) error
+	FindEmailEvents(ctx context.Context, filter interface{}, page, limit int) ([]*models.EmailEvent, int64, error)
+	FindMessageEmailEvents(ctx context.Context, userID, messageID string) ([]*models.EmailEvent, error)
+	InitEmailEventIndexes(ctx context.Context) error
+
+	// Webhook methods
+	SaveWebhook(ctx context.Context, webhook *models.Webhook) error
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	FindWebhookByID(ctx context.Context, id string) (*models.Webhook, error)
+	FindUserWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error)
+	FindSubscribedWebhooks(ctx context.Context, userID string, eventType models.WebhookEventType) ([]*models.Webhook, error)
+	RecordWebhookResult(ctx context.Context, id primitive.ObjectID, delivered bool, disableAfter int, now time.Time) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id primitive.ObjectID) error
+	InitWebhookIndexes(ctx context.Context) error
+
+	// Webhook delivery methods
+	SaveWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	FindWebhookDeliveryByID(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	FindWebhookDeliveries(ctx context.Context, filter interface{}, page, limit int) ([]*models.WebhookDelivery, int64, error)
+	ClaimWebhookDelivery(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.WebhookDelivery, error)
+	ReleaseWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery, owner string) error
+	DeleteWebhookDeliveries(ctx context.Context, webhookID primitive.ObjectID) error
+	InitWebhookDeliveryIndexes(ctx context.Context) error
+
+	// Merge job methods
+	SaveMergeJob(ctx context.Context, job *models.MergeJob) error
+	FindMergeJobByID(ctx context.Context, id string) (*models.MergeJob, error)
+	FindMergeJobs(ctx context.Context, filter interface{}, page, limit int) ([]*models.MergeJob, int64, error)
+	ClaimMergeJob(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.MergeJob, error)
+	ReleaseMergeJob(ctx context.Context, job *models.MergeJob, owner string) error
+	CancelMergeJob(ctx context.Context, id primitive.ObjectID, now time.Time) error
+	InitMergeJobIndexes(ctx context.Context) error
+
+	// Merge row methods
+	SaveMergeRows(ctx context.Context, rows []*models.MergeRow) error
+	FindPendingMergeRows(ctx context.Context, jobID primitive.ObjectID, limit int) ([]*models.MergeRow, error)
+	FindMergeRows(ctx context.Context, filter interface{}, page, limit int) ([]*models.MergeRow, int64, error)
+	SaveMergeRowResults(ctx context.Context, rows []*models.MergeRow) error
+	InitMergeRowIndexes(ctx context.Context) error
 	
 	// User methods
 	SaveUser(ctx context.Context, user *models.User) error
@@ -40,18 +204,52 @@ type Repository interface {
 }
 
 type repoImpl struct {
-	email    email.EmailRepository
-	emailLog emaillog.EmailLogRepository
-	user     user.Repository
-	token    token.Repository
+	email       email.EmailRepository
+	emailLog    emaillog.EmailLogRepository
+	user        user.Repository
+	token       token.Repository
+	schedule    schedule.ScheduleRepository
+	scheduleRun schedulerun.ScheduleRunRepository
+	template    emailtemplate.TemplateRepository
+	version     templateversion.TemplateVersionRepository
+	recipient   recipient.RecipientRepository
+	topic       topic.TopicRepository
+	attachment  attachment.AttachmentRepository
+	link        downloadlink.DownloadLinkRepository
+	idempotency idempotency.IdempotencyRepository
+	dedup       dedup.DedupRepository
+	suppression suppression.SuppressionRepository
+	inbound     inbound.InboundRepository
+	emailEvent  emailevent.EmailEventRepository
+	webhook     webhook.WebhookRepository
+	delivery    webhookdelivery.WebhookDeliveryRepository
+	mergeJob    mergejob.MergeJobRepository
+	mergeRow    mergerow.MergeRowRepository
 }
 
 func New(db *DB) Repository {
 	return &repoImpl{
-		email:    email.New(db.MongoDB),
-		emailLog: emaillog.New(db.MongoDB),
-		user:     user.New(db.MongoDB),
-		token:    token.New(db.MongoDB),
+		email:       email.New(db.MongoDB),
+		emailLog:    emaillog.New(db.MongoDB),
+		user:        user.New(db.MongoDB),
+		token:       token.New(db.MongoDB),
+		schedule:    schedule.New(db.MongoDB),
+		scheduleRun: schedulerun.New(db.MongoDB),
+		template:    emailtemplate.New(db.MongoDB),
+		version:     templateversion.New(db.MongoDB),
+		recipient:   recipient.New(db.MongoDB),
+		topic:       topic.New(db.MongoDB),
+		attachment:  attachment.New(db.MongoDB),
+		link:        downloadlink.New(db.MongoDB),
+		idempotency: idempotency.New(db.MongoDB),
+		dedup:       dedup.New(db.MongoDB),
+		suppression: suppression.New(db.MongoDB),
+		inbound:     inbound.New(db.MongoDB),
+		emailEvent:  emailevent.New(db.MongoDB),
+		webhook:     webhook.New(db.MongoDB),
+		delivery:    webhookdelivery.New(db.MongoDB),
+		mergeJob:    mergejob.New(db.MongoDB),
+		mergeRow:    mergerow.New(db.MongoDB),
 	}
 }
 
@@ -63,8 +261,394 @@ func (r *repoImpl) FindEmails(ctx context.Context, filter interface{}, page, lim
 	return r.email.FindAll(ctx, filter, page, limit)
 }
 
-func (r *repoImpl) DeleteEmail(ctx context.Context, id interface{}) error {
-	return r.email.Delete(ctx, id)
+func (r *repoImpl) DeleteEmail(ctx context.Context, id interface{}, conditions ...bson.M) error {
+	return r.email.Delete(ctx, id, conditions...)
+}
+
+// FindEmailByID retrieves an email by ID
+func (r *repoImpl) FindEmailByID(ctx context.Context, id string) (*models.Email, error) {
+	return r.email.FindByID(ctx, id)
+}
+
+// ClaimEmail leases the next email that is due for delivery
+func (r *repoImpl) ClaimEmail(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.Email, error) {
+	return r.email.Claim(ctx, owner, now, lease)
+}
+
+// ReleaseEmail stores a delivery outcome and drops the lease held by owner
+func (r *repoImpl) ReleaseEmail(ctx context.Context, email *models.Email, owner string) error {
+	return r.email.Release(ctx, email, owner)
+}
+
+// InitEmailIndexes initializes indexes for the email queue
+func (r *repoImpl) InitEmailIndexes(ctx context.Context) error {
+	return r.email.CreateIndexes(ctx)
+}
+
+// PromoteScheduledEmails queues scheduled emails whose send time has passed
+func (r *repoImpl) PromoteScheduledEmails(ctx context.Context, now time.Time) (int64, error) {
+	return r.email.PromoteDue(ctx, now)
+}
+
+// RescheduleEmail changes the send time of a scheduled email
+func (r *repoImpl) RescheduleEmail(ctx context.Context, id, userID string, sendAt time.Time) (*models.Email, error) {
+	return r.email.Reschedule(ctx, id, userID, sendAt)
+}
+
+// SaveSchedule creates or updates a recurring schedule
+func (r *repoImpl) SaveSchedule(ctx context.Context, schedule *models.Schedule) error {
+	return r.schedule.Save(ctx, schedule)
+}
+
+// FindScheduleByID retrieves a recurring schedule by ID
+func (r *repoImpl) FindScheduleByID(ctx context.Context, id string) (*models.Schedule, error) {
+	return r.schedule.FindByID(ctx, id)
+}
+
+// FindSchedules retrieves recurring schedules from the database
+func (r *repoImpl) FindSchedules(ctx context.Context, filter interface{}, page, limit int) ([]*models.Schedule, int64, error) {
+	return r.schedule.FindAll(ctx, filter, page, limit)
+}
+
+// DeleteSchedule removes a recurring schedule
+func (r *repoImpl) DeleteSchedule(ctx context.Context, id string) error {
+	return r.schedule.Delete(ctx, id)
+}
+
+// FindDueSchedules retrieves active schedules whose next run time has passed
+func (r *repoImpl) FindDueSchedules(ctx context.Context, now time.Time, limit int) ([]*models.Schedule, error) {
+	return r.schedule.FindDue(ctx, now, limit)
+}
+
+// AdvanceSchedule moves a due schedule to its next run if no other instance did
+func (r *repoImpl) AdvanceSchedule(ctx context.Context, schedule *models.Schedule, previous time.Time) (bool, error) {
+	return r.schedule.Advance(ctx, schedule, previous)
+}
+
+// SaveScheduleRun records a firing of a recurring schedule
+func (r *repoImpl) SaveScheduleRun(ctx context.Context, run *models.ScheduleRun) error {
+	return r.scheduleRun.Save(ctx, run)
+}
+
+// FindScheduleRuns retrieves the run history of recurring schedules
+func (r *repoImpl) FindScheduleRuns(ctx context.Context, filter interface{}, page, limit int) ([]*models.ScheduleRun, int64, error) {
+	return r.scheduleRun.FindAll(ctx, filter, page, limit)
+}
+
+// InitScheduleIndexes initializes indexes for schedules and their runs
+func (r *repoImpl) InitScheduleIndexes(ctx context.Context) error {
+	if err := r.schedule.CreateIndexes(ctx); err != nil {
+		return err
+	}
+	return r.scheduleRun.CreateIndexes(ctx)
+}
+
+// SaveTemplate creates or updates a template
+func (r *repoImpl) SaveTemplate(ctx context.Context, template *models.Template) error {
+	return r.template.Save(ctx, template)
+}
+
+// FindTemplateByID retrieves a template by ID
+func (r *repoImpl) FindTemplateByID(ctx context.Context, id string) (*models.Template, error) {
+	return r.template.FindByID(ctx, id)
+}
+
+// FindTemplates retrieves templates from the database
+func (r *repoImpl) FindTemplates(ctx context.Context, filter interface{}, page, limit int) ([]*models.Template, int64, error) {
+	return r.template.FindAll(ctx, filter, page, limit)
+}
+
+// DeleteTemplate removes a template
+func (r *repoImpl) DeleteTemplate(ctx context.Context, id string) error {
+	return r.template.Delete(ctx, id)
+}
+
+// ReserveTemplateVersion atomically claims the version number after latest
+func (r *repoImpl) ReserveTemplateVersion(ctx context.Context, id string, latest int) error {
+	return r.template.NextVersion(ctx, id, latest)
+}
+
+// InsertTemplateVersion stores a new immutable template version
+func (r *repoImpl) InsertTemplateVersion(ctx context.Context, version *models.TemplateVersion) error {
+	return r.version.Insert(ctx, version)
+}
+
+// FindTemplateVersion retrieves a single version of a template
+func (r *repoImpl) FindTemplateVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*models.TemplateVersion, error) {
+	return r.version.Find(ctx, templateID, version)
+}
+
+// FindTemplateVersions retrieves every version of a template
+func (r *repoImpl) FindTemplateVersions(ctx context.Context, templateID primitive.ObjectID) ([]*models.TemplateVersion, error) {
+	return r.version.FindAll(ctx, templateID)
+}
+
+// DeleteTemplateVersions removes every version of a template
+func (r *repoImpl) DeleteTemplateVersions(ctx context.Context, templateID primitive.ObjectID) error {
+	return r.version.DeleteAll(ctx, templateID)
+}
+
+// InitTemplateIndexes initializes indexes for templates and their versions
+func (r *repoImpl) InitTemplateIndexes(ctx context.Context) error {
+	if err := r.template.CreateIndexes(ctx); err != nil {
+		return err
+	}
+	return r.version.CreateIndexes(ctx)
+}
+
+// UpsertRecipient creates or replaces the preferences of a recipient
+func (r *repoImpl) UpsertRecipient(ctx context.Context, recipient *models.Recipient) error {
+	return r.recipient.Upsert(ctx, recipient)
+}
+
+// UpdateRecipientOptedOut replaces the topics a recipient opted out of
+func (r *repoImpl) UpdateRecipientOptedOut(ctx context.Context, userID, email string, optedOut []string) (*models.Recipient, error) {
+	return r.recipient.UpdateOptedOut(ctx, userID, email, optedOut)
+}
+
+// FindRecipient retrieves the preferences of a recipient
+func (r *repoImpl) FindRecipient(ctx context.Context, userID, email string) (*models.Recipient, error) {
+	return r.recipient.FindByEmail(ctx, userID, email)
+}
+
+// FindRecipients retrieves recipients from the database
+func (r *repoImpl) FindRecipients(ctx context.Context, filter interface{}, page, limit int) ([]*models.Recipient, int64, error) {
+	return r.recipient.FindAll(ctx, filter, page, limit)
+}
+
+// DeleteRecipient removes the preferences of a recipient
+func (r *repoImpl) DeleteRecipient(ctx context.Context, userID, email string) error {
+	return r.recipient.Delete(ctx, userID, email)
+}
+
+// InitRecipientIndexes initializes indexes for recipient preferences
+func (r *repoImpl) InitRecipientIndexes(ctx context.Context) error {
+	return r.recipient.CreateIndexes(ctx)
+}
+
+// SaveTopic stores a new topic
+func (r *repoImpl) SaveTopic(ctx context.Context, topic *models.Topic) error {
+	return r.topic.Save(ctx, topic)
+}
+
+// UpdateTopic updates the label, description and kind of a topic
+func (r *repoImpl) UpdateTopic(ctx context.Context, topic *models.Topic) error {
+	return r.topic.Update(ctx, topic)
+}
+
+// FindTopic retrieves a topic of a user by name
+func (r *repoImpl) FindTopic(ctx context.Context, userID, name string) (*models.Topic, error) {
+	return r.topic.FindByName(ctx, userID, name)
+}
+
+// FindUserTopics retrieves all topics of a user
+func (r *repoImpl) FindUserTopics(ctx context.Context, userID string) ([]*models.Topic, error) {
+	return r.topic.FindByUser(ctx, userID)
+}
+
+// DeleteTopic removes a topic
+func (r *repoImpl) DeleteTopic(ctx context.Context, id primitive.ObjectID) error {
+	return r.topic.Delete(ctx, id)
+}
+
+// InitTopicIndexes initializes indexes for topics
+func (r *repoImpl) InitTopicIndexes(ctx context.Context) error {
+	return r.topic.CreateIndexes(ctx)
+}
+
+// SaveAttachment stores the metadata of a new attachment
+func (r *repoImpl) SaveAttachment(ctx context.Context, attachment *models.StoredAttachment) error {
+	return r.attachment.Save(ctx, attachment)
+}
+
+// FindAttachmentByID retrieves the metadata of an attachment
+func (r *repoImpl) FindAttachmentByID(ctx context.Context, id string) (*models.StoredAttachment, error) {
+	return r.attachment.FindByID(ctx, id)
+}
+
+// FindAttachmentByHash retrieves a user's attachment by content hash
+func (r *repoImpl) FindAttachmentByHash(ctx context.Context, userID, hash string) (*models.StoredAttachment, error) {
+	return r.attachment.FindByHash(ctx, userID, hash)
+}
+
+// FindAttachments retrieves attachments from the database
+func (r *repoImpl) FindAttachments(ctx context.Context, filter interface{}, page, limit int) ([]*models.StoredAttachment, int64, error) {
+	return r.attachment.FindAll(ctx, filter, page, limit)
+}
+
+// FindExpiredAttachments retrieves attachments whose expiry time has passed
+func (r *repoImpl) FindExpiredAttachments(ctx context.Context, now time.Time, limit int) ([]*models.StoredAttachment, error) {
+	return r.attachment.FindExpired(ctx, now, limit)
+}
+
+// SetAttachmentExpiry changes when an attachment expires
+func (r *repoImpl) SetAttachmentExpiry(ctx context.Context, id primitive.ObjectID, expiresAt *time.Time) error {
+	return r.attachment.SetExpiry(ctx, id, expiresAt)
+}
+
+// DeleteAttachment removes the metadata of an attachment
+func (r *repoImpl) DeleteAttachment(ctx context.Context, id primitive.ObjectID) error {
+	return r.attachment.Delete(ctx, id)
+}
+
+// UploadAttachmentFile streams attachment content into GridFS
+func (r *repoImpl) UploadAttachmentFile(ctx context.Context, filename string, content io.Reader) (primitive.ObjectID, error) {
+	return r.attachment.UploadFile(ctx, filename, content)
+}
+
+// OpenAttachmentFile opens attachment content stored in GridFS
+func (r *repoImpl) OpenAttachmentFile(ctx context.Context, fileID primitive.ObjectID) (io.ReadCloser, error) {
+	return r.attachment.OpenFile(ctx, fileID)
+}
+
+// DeleteAttachmentFile removes attachment content from GridFS
+func (r *repoImpl) DeleteAttachmentFile(ctx context.Context, fileID primitive.ObjectID) error {
+	return r.attachment.DeleteFile(ctx, fileID)
+}
+
+// InitAttachmentIndexes initializes indexes for stored attachments
+func (r *repoImpl) InitAttachmentIndexes(ctx context.Context) error {
+	return r.attachment.CreateIndexes(ctx)
+}
+
+// SaveDownloadLink stores a new download link
+func (r *repoImpl) SaveDownloadLink(ctx context.Context, link *models.DownloadLink) error {
+	return r.link.Save(ctx, link)
+}
+
+// FindDownloadLinkByID retrieves a download link
+func (r *repoImpl) FindDownloadLinkByID(ctx context.Context, id string) (*models.DownloadLink, error) {
+	return r.link.FindByID(ctx, id)
+}
+
+// FindDownloadLinks retrieves download links from the database
+func (r *repoImpl) FindDownloadLinks(ctx context.Context, filter interface{}, page, limit int) ([]*models.DownloadLink, int64, error) {
+	return r.link.FindAll(ctx, filter, page, limit)
+}
+
+// RecordDownload counts a download of a link that is still valid
+func (r *repoImpl) RecordDownload(ctx context.Context, id primitive.ObjectID, now time.Time) (*models.DownloadLink, error) {
+	return r.link.RecordDownload(ctx, id, now)
+}
+
+// RevokeDownloadLink disables a download link
+func (r *repoImpl) RevokeDownloadLink(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	return r.link.Revoke(ctx, id, now)
+}
+
+// InitDownloadLinkIndexes initializes indexes for download links
+func (r *repoImpl) InitDownloadLinkIndexes(ctx context.Context) error {
+	return r.link.CreateIndexes(ctx)
+}
+
+// InsertIdempotencyRecord reserves an idempotency key for a request
+func (r *repoImpl) InsertIdempotencyRecord(ctx context.Context, record *models.IdempotencyRecord) error {
+	return r.idempotency.Insert(ctx, record)
+}
+
+// FindIdempotencyRecord retrieves the record of an idempotency key
+func (r *repoImpl) FindIdempotencyRecord(ctx context.Context, userID, key string) (*models.IdempotencyRecord, error) {
+	return r.idempotency.Find(ctx, userID, key)
+}
+
+// CompleteIdempotencyRecord stores the response to a request
+func (r *repoImpl) CompleteIdempotencyRecord(ctx context.Context, userID, key string, response *models.StoredResponse, now time.Time) error {
+	return r.idempotency.Complete(ctx, userID, key, response, now)
+}
+
+// DeleteStaleIdempotencyRecord removes an expired or abandoned idempotency key
+func (r *repoImpl) DeleteStaleIdempotencyRecord(ctx context.Context, userID, key string, now, lockedBefore time.Time) (bool, error) {
+	return r.idempotency.DeleteStale(ctx, userID, key, now, lockedBefore)
+}
+
+// ReleaseIdempotencyRecord removes an idempotency key whose request is in progress
+func (r *repoImpl) ReleaseIdempotencyRecord(ctx context.Context, userID, key string) error {
+	return r.idempotency.Release(ctx, userID, key)
+}
+
+// InitIdempotencyIndexes initializes indexes for idempotency keys
+func (r *repoImpl) InitIdempotencyIndexes(ctx context.Context) error {
+	return r.idempotency.CreateIndexes(ctx)
+}
+
+// ClaimSendFingerprint claims the fingerprint of a send for the dedup window
+func (r *repoImpl) ClaimSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint) (bool, error) {
+	return r.dedup.Claim(ctx, fingerprint)
+}
+
+// ReleaseSendFingerprint gives up the claim of a send that failed
+func (r *repoImpl) ReleaseSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint) error {
+	return r.dedup.Release(ctx, fingerprint)
+}
+
+// ExtendSendFingerprint keeps the claim of a send until expiresAt
+func (r *repoImpl) ExtendSendFingerprint(ctx context.Context, fingerprint *models.SendFingerprint, expiresAt time.Time) error {
+	return r.dedup.Extend(ctx, fingerprint, expiresAt)
+}
+
+// InitDedupIndexes initializes indexes for send fingerprints
+func (r *repoImpl) InitDedupIndexes(ctx context.Context) error {
+	return r.dedup.CreateIndexes(ctx)
+}
+
+// SaveSuppression stores a new suppression
+func (r *repoImpl) SaveSuppression(ctx context.Context, suppression *models.Suppression) error {
+	return r.suppression.Save(ctx, suppression)
+}
+
+// UpsertSuppression creates or replaces a suppression of a value in a scope
+func (r *repoImpl) UpsertSuppression(ctx context.Context, suppression *models.Suppression) (bool, error) {
+	return r.suppression.Upsert(ctx, suppression)
+}
+
+// UpdateSuppression replaces the reason, note and expiry of a suppression
+func (r *repoImpl) UpdateSuppression(ctx context.Context, suppression *models.Suppression) error {
+	return r.suppression.Update(ctx, suppression)
+}
+
+// FindSuppressionByID retrieves a suppression
+func (r *repoImpl) FindSuppressionByID(ctx context.Context, id string) (*models.Suppression, error) {
+	return r.suppression.FindByID(ctx, id)
+}
+
+// FindSuppressions retrieves suppressions from the database
+func (r *repoImpl) FindSuppressions(ctx context.Context, filter interface{}, page, limit int) ([]*models.Suppression, int64, error) {
+	return r.suppression.FindAll(ctx, filter, page, limit)
+}
+
+// FindActiveSuppression retrieves a suppression that stops an email of a user
+func (r *repoImpl) FindActiveSuppression(ctx context.Context, userID, list string, values []string, now time.Time) (*models.Suppression, error) {
+	return r.suppression.FindActive(ctx, userID, list, values, now)
+}
+
+// DeleteSuppression removes a suppression
+func (r *repoImpl) DeleteSuppression(ctx context.Context, id primitive.ObjectID) error {
+	return r.suppression.Delete(ctx, id)
+}
+
+// InitSuppressionIndexes initializes indexes for suppressions
+func (r *repoImpl) InitSuppressionIndexes(ctx context.Context) error {
+	return r.suppression.CreateIndexes(ctx)
+}
+
+// SaveInboundMessage stores a received email
+func (r *repoImpl) SaveInboundMessage(ctx context.Context, message *models.InboundMessage) error {
+	return r.inbound.Save(ctx, message)
+}
+
+// ClaimInboundMessage leases the next received email whose webhooks are due
+func (r *repoImpl) ClaimInboundMessage(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.InboundMessage, error) {
+	return r.inbound.Claim(ctx, owner, now, lease)
+}
+
+// ReleaseInboundMessage stores a webhook dispatch outcome and drops the lease held by owner
+func (r *repoImpl) ReleaseInboundMessage(ctx context.Context, message *models.InboundMessage, owner string) error {
+	return r.inbound.Release(ctx, message, owner)
+}
+
+// InitInboundIndexes initializes indexes for received emails
+func (r *repoImpl) InitInboundIndexes(ctx context.Context) error {
+	return r.inbound.CreateIndexes(ctx)
 }
 
 // SaveEmailLog saves an email log to the database
@@ -82,6 +666,176 @@ func (r *repoImpl) FindEmailLogByID(ctx context.Context, id string) (*models.Ema
 	return r.emailLog.FindByID(ctx, id)
 }
 
+// FindEmailLogByMessageID retrieves the email log of a sent message
+func (r *repoImpl) FindEmailLogByMessageID(ctx context.Context, messageID string) (*models.EmailLog, error) {
+	return r.emailLog.FindByMessageID(ctx, messageID)
+}
+
+// AddEmailLogFeedback records a bounce or complaint about a sent email
+func (r *repoImpl) AddEmailLogFeedback(ctx context.Context, id primitive.ObjectID, status models.EmailLogStatus, feedback models.DeliveryFeedback) error {
+	return r.emailLog.AddFeedback(ctx, id, status, feedback)
+}
+
+// InitEmailLogIndexes initializes indexes for email logs
+func (r *repoImpl) InitEmailLogIndexes(ctx context.Context) error {
+	return r.emailLog.CreateIndexes(ctx)
+}
+
+// SaveEmailEvent stores an interaction of a recipient with a sent email
+func (r *repoImpl) SaveEmailEvent(ctx context.Context, event *models.EmailEvent) error {
+	return r.emailEvent.Save(ctx, event)
+}
+
+// FindEmailEvents retrieves email events from the database
+func (r *repoImpl) FindEmailEvents(ctx context.Context, filter interface{}, page, limit int) ([]*models.EmailEvent, int64, error) {
+	return r.emailEvent.FindAll(ctx, filter, page, limit)
+}
+
+// FindMessageEmailEvents retrieves all events of a sent message of a user
+func (r *repoImpl) FindMessageEmailEvents(ctx context.Context, userID, messageID string) ([]*models.EmailEvent, error) {
+	return r.emailEvent.FindByMessageID(ctx, userID, messageID)
+}
+
+// InitEmailEventIndexes initializes indexes for email events
+func (r *repoImpl) InitEmailEventIndexes(ctx context.Context) error {
+	return r.emailEvent.CreateIndexes(ctx)
+}
+
+// SaveWebhook stores a new webhook subscription
+func (r *repoImpl) SaveWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return r.webhook.Save(ctx, webhook)
+}
+
+// UpdateWebhook updates the settings of a webhook
+func (r *repoImpl) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return r.webhook.Update(ctx, webhook)
+}
+
+// FindWebhookByID retrieves a webhook by ID
+func (r *repoImpl) FindWebhookByID(ctx context.Context, id string) (*models.Webhook, error) {
+	return r.webhook.FindByID(ctx, id)
+}
+
+// FindUserWebhooks retrieves all webhooks of a user
+func (r *repoImpl) FindUserWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	return r.webhook.FindByUser(ctx, userID)
+}
+
+// FindSubscribedWebhooks retrieves the enabled webhooks of a user subscribed to an event
+func (r *repoImpl) FindSubscribedWebhooks(ctx context.Context, userID string, eventType models.WebhookEventType) ([]*models.Webhook, error) {
+	return r.webhook.FindSubscribed(ctx, userID, eventType)
+}
+
+// RecordWebhookResult counts a failed delivery attempt, disabling the webhook at the limit, or resets the count
+func (r *repoImpl) RecordWebhookResult(ctx context.Context, id primitive.ObjectID, delivered bool, disableAfter int, now time.Time) (*models.Webhook, error) {
+	return r.webhook.RecordResult(ctx, id, delivered, disableAfter, now)
+}
+
+// DeleteWebhook removes a webhook
+func (r *repoImpl) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	return r.webhook.Delete(ctx, id)
+}
+
+// InitWebhookIndexes initializes indexes for webhooks
+func (r *repoImpl) InitWebhookIndexes(ctx context.Context) error {
+	return r.webhook.CreateIndexes(ctx)
+}
+
+// SaveWebhookDelivery adds an event to the webhook outbox
+func (r *repoImpl) SaveWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.delivery.Save(ctx, delivery)
+}
+
+// FindWebhookDeliveryByID retrieves a webhook delivery by ID
+func (r *repoImpl) FindWebhookDeliveryByID(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	return r.delivery.FindByID(ctx, id)
+}
+
+// FindWebhookDeliveries retrieves webhook deliveries from the database
+func (r *repoImpl) FindWebhookDeliveries(ctx context.Context, filter interface{}, page, limit int) ([]*models.WebhookDelivery, int64, error) {
+	return r.delivery.FindAll(ctx, filter, page, limit)
+}
+
+// ClaimWebhookDelivery leases the next webhook delivery that is due
+func (r *repoImpl) ClaimWebhookDelivery(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	return r.delivery.Claim(ctx, owner, now, lease)
+}
+
+// ReleaseWebhookDelivery stores a delivery attempt outcome and drops the lease held by owner
+func (r *repoImpl) ReleaseWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery, owner string) error {
+	return r.delivery.Release(ctx, delivery, owner)
+}
+
+// DeleteWebhookDeliveries removes the deliveries of a webhook
+func (r *repoImpl) DeleteWebhookDeliveries(ctx context.Context, webhookID primitive.ObjectID) error {
+	return r.delivery.DeleteByWebhook(ctx, webhookID)
+}
+
+// InitWebhookDeliveryIndexes initializes indexes for webhook deliveries
+func (r *repoImpl) InitWebhookDeliveryIndexes(ctx context.Context) error {
+	return r.delivery.CreateIndexes(ctx)
+}
+
+// SaveMergeJob stores a new mail merge job
+func (r *repoImpl) SaveMergeJob(ctx context.Context, job *models.MergeJob) error {
+	return r.mergeJob.Save(ctx, job)
+}
+
+// FindMergeJobByID retrieves a mail merge job by ID
+func (r *repoImpl) FindMergeJobByID(ctx context.Context, id string) (*models.MergeJob, error) {
+	return r.mergeJob.FindByID(ctx, id)
+}
+
+// FindMergeJobs retrieves mail merge jobs from the database
+func (r *repoImpl) FindMergeJobs(ctx context.Context, filter interface{}, page, limit int) ([]*models.MergeJob, int64, error) {
+	return r.mergeJob.FindAll(ctx, filter, page, limit)
+}
+
+// ClaimMergeJob leases the next unfinished mail merge job
+func (r *repoImpl) ClaimMergeJob(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.MergeJob, error) {
+	return r.mergeJob.Claim(ctx, owner, now, lease)
+}
+
+// ReleaseMergeJob stores the progress of a mail merge job and drops the lease held by owner
+func (r *repoImpl) ReleaseMergeJob(ctx context.Context, job *models.MergeJob, owner string) error {
+	return r.mergeJob.Release(ctx, job, owner)
+}
+
+// CancelMergeJob stops a mail merge job that isn't finished yet
+func (r *repoImpl) CancelMergeJob(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	return r.mergeJob.Cancel(ctx, id, now)
+}
+
+// InitMergeJobIndexes initializes indexes for mail merge jobs
+func (r *repoImpl) InitMergeJobIndexes(ctx context.Context) error {
+	return r.mergeJob.CreateIndexes(ctx)
+}
+
+// SaveMergeRows stores the recipients of a mail merge job
+func (r *repoImpl) SaveMergeRows(ctx context.Context, rows []*models.MergeRow) error {
+	return r.mergeRow.SaveMany(ctx, rows)
+}
+
+// FindPendingMergeRows retrieves the next recipients of a mail merge job that weren't sent yet
+func (r *repoImpl) FindPendingMergeRows(ctx context.Context, jobID primitive.ObjectID, limit int) ([]*models.MergeRow, error) {
+	return r.mergeRow.FindPending(ctx, jobID, limit)
+}
+
+// FindMergeRows retrieves mail merge recipients from the database
+func (r *repoImpl) FindMergeRows(ctx context.Context, filter interface{}, page, limit int) ([]*models.MergeRow, int64, error) {
+	return r.mergeRow.FindAll(ctx, filter, page, limit)
+}
+
+// SaveMergeRowResults stores the outcome of a batch of mail merge recipients
+func (r *repoImpl) SaveMergeRowResults(ctx context.Context, rows []*models.MergeRow) error {
+	return r.mergeRow.SaveResults(ctx, rows)
+}
+
+// InitMergeRowIndexes initializes indexes for mail merge recipients
+func (r *repoImpl) InitMergeRowIndexes(ctx context.Context) error {
+	return r.mergeRow.CreateIndexes(ctx)
+}
+
 // SaveUser saves a user to the database
 func (r *repoImpl) SaveUser(ctx context.Context, user *models.User) error {
 	return r.user.Save(ctx, user)
@@ -0,0 +1,57 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Advance moves a due schedule to its next run. The update only applies
+// while the stored next run is still previous, so when several instances
+// evaluate the same schedule exactly one of them wins the run. It reports
+// whether the caller won.
+func (m *mongoDB) Advance(ctx context.Context, schedule *models.Schedule, previous time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":         schedule.ID,
+		"status":      models.ScheduleStatusActive,
+		"next_run_at": previous,
+	}
+
+	set := bson.M{
+		"status":      schedule.Status,
+		"last_run_at": schedule.LastRunAt,
+		"updated_at":  time.Now(),
+	}
+	update := bson.M{"$set": set}
+	if schedule.NextRunAt != nil {
+		set["next_run_at"] = schedule.NextRunAt
+	} else {
+		update["$unset"] = bson.M{"next_run_at": ""}
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// CreateIndexes creates the indexes used to find due schedules
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	_, err := m.collection.Indexes().CreateMany(ctx, indexModels)
+	return err
+}
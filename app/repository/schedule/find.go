@@ -0,0 +1,88 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByID retrieves a schedule by ID
+func (m *mongoDB) FindByID(ctx context.Context, id string) (*models.Schedule, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	schedule := &models.Schedule{}
+	err = m.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(schedule)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// FindAll retrieves schedules with optional filtering and pagination
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Schedule, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"created_at": -1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	schedules := make([]*models.Schedule, 0)
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return schedules, total, nil
+}
+
+// FindDue retrieves active schedules whose next run time has passed
+func (m *mongoDB) FindDue(ctx context.Context, now time.Time, limit int) ([]*models.Schedule, error) {
+	filter := bson.M{
+		"status":      models.ScheduleStatusActive,
+		"next_run_at": bson.M{"$lte": now},
+	}
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"next_run_at": 1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	schedules := make([]*models.Schedule, 0)
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,42 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Save creates or updates a schedule in the database
+func (m *mongoDB) Save(ctx context.Context, schedule *models.Schedule) error {
+	now := time.Now()
+	schedule.UpdatedAt = now
+
+	if schedule.ID.IsZero() {
+		schedule.CreatedAt = now
+
+		result, err := m.collection.InsertOne(ctx, schedule)
+		if err != nil {
+			return err
+		}
+
+		if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+			schedule.ID = oid
+		}
+		return nil
+	}
+
+	result, err := m.collection.ReplaceOne(ctx, bson.M{"_id": schedule.ID}, schedule)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrScheduleNotFound
+	}
+
+	return nil
+}
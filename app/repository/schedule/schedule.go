@@ -0,0 +1,38 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "schedules"
+
+var (
+	ErrInvalidID        = errors.New("invalid ID type")
+	ErrScheduleNotFound = errors.New("schedule not found")
+)
+
+type ScheduleRepository interface {
+	Save(ctx context.Context, schedule *models.Schedule) error
+	FindByID(ctx context.Context, id string) (*models.Schedule, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Schedule, int64, error)
+	Delete(ctx context.Context, id string) error
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*models.Schedule, error)
+	Advance(ctx context.Context, schedule *models.Schedule, previous time.Time) (bool, error)
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) ScheduleRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
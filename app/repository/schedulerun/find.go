@@ -0,0 +1,49 @@
+package schedulerun
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindAll retrieves schedule runs with optional filtering and pagination
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.ScheduleRun, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"scheduled_for": -1}) // Most recent run first
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	runs := make([]*models.ScheduleRun, 0)
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+// CreateIndexes creates the index used to list the runs of a schedule
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "schedule_id", Value: 1}, {Key: "scheduled_for", Value: -1}},
+	})
+	return err
+}
@@ -0,0 +1,21 @@
+package schedulerun
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Save inserts a schedule run into the database
+func (m *mongoDB) Save(ctx context.Context, run *models.ScheduleRun) error {
+	if run.ID.IsZero() {
+		run.ID = primitive.NewObjectID()
+		run.CreatedAt = time.Now()
+	}
+
+	_, err := m.collection.InsertOne(ctx, run)
+	return err
+}
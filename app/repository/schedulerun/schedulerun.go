@@ -0,0 +1,27 @@
+package schedulerun
+
+import (
+	"context"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "schedule_runs"
+
+type ScheduleRunRepository interface {
+	Save(ctx context.Context, run *models.ScheduleRun) error
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.ScheduleRun, int64, error)
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) ScheduleRunRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
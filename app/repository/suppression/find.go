@@ -0,0 +1,93 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByID retrieves a suppression by ID
+func (m *mongoDB) FindByID(ctx context.Context, id string) (*models.Suppression, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	suppression := &models.Suppression{}
+	if err := m.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(suppression); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSuppressionNotFound
+		}
+		return nil, err
+	}
+
+	return suppression, nil
+}
+
+// FindAll retrieves suppressions with optional filtering and pagination,
+// newest first
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Suppression, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"created_at": -1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	suppressions := make([]*models.Suppression, 0)
+	if err := cursor.All(ctx, &suppressions); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return suppressions, total, nil
+}
+
+// FindActive retrieves an unexpired suppression of one of the values that
+// applies to the emails of a user, either global or their own. Emails sent
+// to a list are also stopped by the suppressions of that list. Expired
+// suppressions are ignored before the TTL monitor removes them.
+func (m *mongoDB) FindActive(ctx context.Context, userID, list string, values []string, now time.Time) (*models.Suppression, error) {
+	lists := bson.A{nil}
+	if list != "" {
+		lists = append(lists, list)
+	}
+	filter := bson.M{
+		"value":   bson.M{"$in": values},
+		"user_id": bson.M{"$in": bson.A{"", userID}},
+		"list":    bson.M{"$in": lists},
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": now}},
+		},
+	}
+
+	suppression := &models.Suppression{}
+	if err := m.collection.FindOne(ctx, filter).Decode(suppression); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSuppressionNotFound
+		}
+		return nil, err
+	}
+
+	return suppression, nil
+}
@@ -0,0 +1,144 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Save stores a new suppression. It fails with ErrSuppressionExists when
+// the value is already suppressed in the same scope.
+func (m *mongoDB) Save(ctx context.Context, suppression *models.Suppression) error {
+	now := time.Now()
+	suppression.CreatedAt = now
+	suppression.UpdatedAt = now
+
+	result, err := m.collection.InsertOne(ctx, suppression)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrSuppressionExists
+		}
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		suppression.ID = oid
+	}
+	return nil
+}
+
+// Upsert creates a suppression or replaces the reason, note and expiry of
+// the one suppressing the same value in the same scope and list. It reports
+// whether the suppression was created.
+func (m *mongoDB) Upsert(ctx context.Context, suppression *models.Suppression) (bool, error) {
+	now := time.Now()
+	filter := bson.M{"user_id": suppression.UserID, "value": suppression.Value, "list": nil}
+	if suppression.List != "" {
+		filter["list"] = suppression.List
+	}
+	set := bson.M{
+		"reason":     suppression.Reason,
+		"note":       suppression.Note,
+		"updated_at": now,
+	}
+	update := bson.M{
+		"$set": set,
+		"$setOnInsert": bson.M{
+			"scope":      suppression.Scope,
+			"type":       suppression.Type,
+			"created_at": now,
+		},
+	}
+	if suppression.ExpiresAt != nil {
+		set["expires_at"] = suppression.ExpiresAt
+	} else {
+		update["$unset"] = bson.M{"expires_at": ""}
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	if err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(suppression); err != nil {
+		return false, err
+	}
+
+	return suppression.CreatedAt.Equal(suppression.UpdatedAt), nil
+}
+
+// Update replaces the reason, note and expiry of a suppression
+func (m *mongoDB) Update(ctx context.Context, suppression *models.Suppression) error {
+	suppression.UpdatedAt = time.Now()
+
+	set := bson.M{
+		"reason":     suppression.Reason,
+		"note":       suppression.Note,
+		"updated_at": suppression.UpdatedAt,
+	}
+	update := bson.M{"$set": set}
+	if suppression.ExpiresAt != nil {
+		set["expires_at"] = suppression.ExpiresAt
+	} else {
+		update["$unset"] = bson.M{"expires_at": ""}
+	}
+
+	result, err := m.collection.UpdateOne(ctx, bson.M{"_id": suppression.ID}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrSuppressionNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a suppression
+func (m *mongoDB) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := m.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrSuppressionNotFound
+	}
+
+	return nil
+}
+
+// CreateIndexes makes values unique per scope and list, serves the check
+// before every send and removes suppressions once they expire. The unique
+// index of values per scope that predates lists is dropped.
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	if _, err := m.collection.Indexes().DropOne(ctx, "value_1_user_id_1"); err != nil && !isIndexNotFound(err) {
+		return err
+	}
+
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "value", Value: 1}, {Key: "user_id", Value: 1}, {Key: "list", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+// isIndexNotFound reports whether an index couldn't be dropped because it
+// doesn't exist
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 27 || cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound")
+}
@@ -0,0 +1,43 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "suppressions"
+
+var (
+	ErrInvalidID           = errors.New("invalid ID type")
+	ErrSuppressionNotFound = errors.New("suppression not found")
+	ErrSuppressionExists   = errors.New("suppression already exists")
+)
+
+// SuppressionRepository stores the addresses and domains emails aren't
+// sent to
+type SuppressionRepository interface {
+	Save(ctx context.Context, suppression *models.Suppression) error
+	Upsert(ctx context.Context, suppression *models.Suppression) (bool, error)
+	Update(ctx context.Context, suppression *models.Suppression) error
+	FindByID(ctx context.Context, id string) (*models.Suppression, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.Suppression, int64, error)
+	FindActive(ctx context.Context, userID, list string, values []string, now time.Time) (*models.Suppression, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) SuppressionRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
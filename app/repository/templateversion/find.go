@@ -0,0 +1,45 @@
+package templateversion
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Find retrieves a single version of a template
+func (m *mongoDB) Find(ctx context.Context, templateID primitive.ObjectID, version int) (*models.TemplateVersion, error) {
+	result := &models.TemplateVersion{}
+	err := m.collection.FindOne(ctx, bson.M{"template_id": templateID, "version": version}).Decode(result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAll retrieves every version of a template, newest first
+func (m *mongoDB) FindAll(ctx context.Context, templateID primitive.ObjectID) ([]*models.TemplateVersion, error) {
+	opts := options.Find().SetSort(bson.M{"version": -1})
+
+	cursor, err := m.collection.Find(ctx, bson.M{"template_id": templateID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	versions := make([]*models.TemplateVersion, 0)
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
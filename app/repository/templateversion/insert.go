@@ -0,0 +1,39 @@
+package templateversion
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Insert stores a new template version
+func (m *mongoDB) Insert(ctx context.Context, version *models.TemplateVersion) error {
+	if version.ID.IsZero() {
+		version.ID = primitive.NewObjectID()
+	}
+	version.CreatedAt = time.Now()
+
+	_, err := m.collection.InsertOne(ctx, version)
+	return err
+}
+
+// DeleteAll removes every version of a template
+func (m *mongoDB) DeleteAll(ctx context.Context, templateID primitive.ObjectID) error {
+	_, err := m.collection.DeleteMany(ctx, bson.M{"template_id": templateID})
+	return err
+}
+
+// CreateIndexes creates the unique index that keeps version numbers distinct
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "template_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,37 @@
+package templateversion
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "template_versions"
+
+var (
+	ErrVersionNotFound = errors.New("template version not found")
+)
+
+// TemplateVersionRepository stores immutable template versions. There is
+// deliberately no update method.
+type TemplateVersionRepository interface {
+	Insert(ctx context.Context, version *models.TemplateVersion) error
+	Find(ctx context.Context, templateID primitive.ObjectID, version int) (*models.TemplateVersion, error)
+	FindAll(ctx context.Context, templateID primitive.ObjectID) ([]*models.TemplateVersion, error)
+	DeleteAll(ctx context.Context, templateID primitive.ObjectID) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) TemplateVersionRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
@@ -0,0 +1,43 @@
+package topic
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByName retrieves a topic of a user by name
+func (m *mongoDB) FindByName(ctx context.Context, userID, name string) (*models.Topic, error) {
+	topic := &models.Topic{}
+	if err := m.collection.FindOne(ctx, bson.M{"user_id": userID, "name": name}).Decode(topic); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTopicNotFound
+		}
+		return nil, err
+	}
+
+	return topic, nil
+}
+
+// FindByUser retrieves the topics of a user, sorted by name
+func (m *mongoDB) FindByUser(ctx context.Context, userID string) ([]*models.Topic, error) {
+	opts := options.Find().SetSort(bson.M{"name": 1})
+
+	cursor, err := m.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	topics := make([]*models.Topic, 0)
+	if err := cursor.All(ctx, &topics); err != nil {
+		return nil, err
+	}
+
+	return topics, nil
+}
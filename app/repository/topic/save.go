@@ -0,0 +1,80 @@
+package topic
+
+import (
+	"context"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Save stores a new topic. It fails with ErrTopicExists when the user has
+// a topic of the same name.
+func (m *mongoDB) Save(ctx context.Context, topic *models.Topic) error {
+	now := time.Now()
+	topic.CreatedAt = now
+	topic.UpdatedAt = now
+
+	result, err := m.collection.InsertOne(ctx, topic)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrTopicExists
+		}
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		topic.ID = oid
+	}
+	return nil
+}
+
+// Update replaces the label, description and kind of a topic
+func (m *mongoDB) Update(ctx context.Context, topic *models.Topic) error {
+	topic.UpdatedAt = time.Now()
+
+	result, err := m.collection.UpdateOne(ctx, bson.M{"_id": topic.ID}, bson.M{
+		"$set": bson.M{
+			"label":         topic.Label,
+			"description":   topic.Description,
+			"transactional": topic.Transactional,
+			"updated_at":    topic.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrTopicNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a topic
+func (m *mongoDB) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := m.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrTopicNotFound
+	}
+
+	return nil
+}
+
+// CreateIndexes makes topic names unique per user
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
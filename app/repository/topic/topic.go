@@ -0,0 +1,39 @@
+package topic
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "topics"
+
+var (
+	ErrTopicNotFound = errors.New("topic not found")
+	ErrTopicExists   = errors.New("topic already exists")
+)
+
+// TopicRepository stores the topics users tag their emails with. Topics
+// are keyed by user and name.
+type TopicRepository interface {
+	Save(ctx context.Context, topic *models.Topic) error
+	Update(ctx context.Context, topic *models.Topic) error
+	FindByName(ctx context.Context, userID, name string) (*models.Topic, error)
+	FindByUser(ctx context.Context, userID string) ([]*models.Topic, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) TopicRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
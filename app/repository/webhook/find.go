@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByID retrieves a webhook by ID
+func (m *mongoDB) FindByID(ctx context.Context, id string) (*models.Webhook, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	webhook := &models.Webhook{}
+	if err := m.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(webhook); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// FindByUser retrieves the webhooks of a user, oldest first
+func (m *mongoDB) FindByUser(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	return m.find(ctx, bson.M{"user_id": userID})
+}
+
+// FindSubscribed retrieves the enabled webhooks of a user that subscribe
+// to an event
+func (m *mongoDB) FindSubscribed(ctx context.Context, userID string, eventType models.WebhookEventType) ([]*models.Webhook, error) {
+	return m.find(ctx, bson.M{"user_id": userID, "events": eventType, "enabled": true})
+}
+
+// find retrieves the webhooks matching a filter, oldest first
+func (m *mongoDB) find(ctx context.Context, filter bson.M) ([]*models.Webhook, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := make([]*models.Webhook, 0)
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
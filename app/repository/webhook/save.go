@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Save stores a new webhook
+func (m *mongoDB) Save(ctx context.Context, webhook *models.Webhook) error {
+	now := time.Now()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	result, err := m.collection.InsertOne(ctx, webhook)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		webhook.ID = oid
+	}
+	return nil
+}
+
+// Update replaces the settings of a webhook. Enabling it clears the
+// failures that disabled it.
+func (m *mongoDB) Update(ctx context.Context, webhook *models.Webhook) error {
+	webhook.UpdatedAt = time.Now()
+
+	set := bson.M{
+		"url":                  webhook.URL,
+		"description":          webhook.Description,
+		"events":               webhook.Events,
+		"secret":               webhook.Secret,
+		"enabled":              webhook.Enabled,
+		"consecutive_failures": webhook.ConsecutiveFailures,
+		"updated_at":           webhook.UpdatedAt,
+	}
+	update := bson.M{"$set": set}
+	if webhook.DisabledAt != nil {
+		set["disabled_at"] = webhook.DisabledAt
+		set["disabled_reason"] = webhook.DisabledReason
+	} else {
+		update["$unset"] = bson.M{"disabled_at": "", "disabled_reason": ""}
+	}
+
+	result, err := m.collection.UpdateOne(ctx, bson.M{"_id": webhook.ID}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// RecordResult counts a failed attempt of a webhook, disabling it once
+// disableAfter attempts in a row failed, or resets the count after a
+// successful one. It returns the updated webhook.
+func (m *mongoDB) RecordResult(ctx context.Context, id primitive.ObjectID, delivered bool, disableAfter int, now time.Time) (*models.Webhook, error) {
+	update := bson.M{"$set": bson.M{"consecutive_failures": 0}}
+	if !delivered {
+		update = bson.M{"$inc": bson.M{"consecutive_failures": 1}}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	webhook := &models.Webhook{}
+	if err := m.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(webhook); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	if delivered || !webhook.Enabled || webhook.ConsecutiveFailures < disableAfter {
+		return webhook, nil
+	}
+
+	// Only the dispatcher whose failure reached the limit disables it
+	reason := fmt.Sprintf("%d delivery attempts failed in a row", webhook.ConsecutiveFailures)
+	result, err := m.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "enabled": true},
+		bson.M{"$set": bson.M{
+			"enabled":         false,
+			"disabled_at":     now,
+			"disabled_reason": reason,
+			"updated_at":      now,
+		}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.ModifiedCount > 0 {
+		webhook.Enabled = false
+		webhook.DisabledAt = &now
+		webhook.DisabledReason = reason
+		webhook.UpdatedAt = now
+	}
+
+	return webhook, nil
+}
+
+// Delete removes a webhook
+func (m *mongoDB) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := m.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// CreateIndexes creates the indexes for listing the webhooks of a user and
+// finding the ones subscribed to an event
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "events", Value: 1}, {Key: "enabled", Value: 1}},
+		},
+	})
+	return err
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "webhooks"
+
+var (
+	ErrInvalidID       = errors.New("invalid ID type")
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+// WebhookRepository stores the webhook subscriptions of users
+type WebhookRepository interface {
+	Save(ctx context.Context, webhook *models.Webhook) error
+	Update(ctx context.Context, webhook *models.Webhook) error
+	FindByID(ctx context.Context, id string) (*models.Webhook, error)
+	FindByUser(ctx context.Context, userID string) ([]*models.Webhook, error)
+	FindSubscribed(ctx context.Context, userID string, eventType models.WebhookEventType) ([]*models.Webhook, error)
+	RecordResult(ctx context.Context, id primitive.ObjectID, delivered bool, disableAfter int, now time.Time) (*models.Webhook, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) WebhookRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
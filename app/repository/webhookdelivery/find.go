@@ -0,0 +1,62 @@
+package webhookdelivery
+
+import (
+	"context"
+	"errors"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindByID retrieves a delivery by ID
+func (m *mongoDB) FindByID(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	delivery := &models.WebhookDelivery{}
+	if err := m.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(delivery); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// FindAll retrieves deliveries with optional filtering and pagination,
+// newest first
+func (m *mongoDB) FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.WebhookDelivery, int64, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"created_at": -1})
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
@@ -0,0 +1,123 @@
+package webhookdelivery
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Save adds a delivery to the outbox
+func (m *mongoDB) Save(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = now
+	}
+	delivery.UpdatedAt = now
+
+	_, err := m.collection.InsertOne(ctx, delivery)
+	return err
+}
+
+// Claim leases the next delivery that is due. Deliveries whose lease
+// expired, because their dispatcher stopped, are claimed again.
+func (m *mongoDB) Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{
+				"status":          models.WebhookDeliveryPending,
+				"next_attempt_at": bson.M{"$lte": now},
+			},
+			{
+				"status":       models.WebhookDeliveryProcessing,
+				"locked_until": bson.M{"$lt": now},
+			},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.WebhookDeliveryProcessing,
+			"locked_by":    owner,
+			"locked_until": now.Add(lease),
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	delivery := &models.WebhookDelivery{}
+	err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// Release stores the outcome of a delivery attempt and drops the lease
+func (m *mongoDB) Release(ctx context.Context, delivery *models.WebhookDelivery, owner string) error {
+	delivery.LockedBy = ""
+	delivery.LockedUntil = nil
+	delivery.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": delivery.ID, "locked_by": owner}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          delivery.Status,
+			"attempt_log":     delivery.AttemptLog,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      delivery.UpdatedAt,
+		},
+		"$unset": bson.M{"locked_by": "", "locked_until": ""},
+	}
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// DeleteByWebhook removes the deliveries of a deleted webhook
+func (m *mongoDB) DeleteByWebhook(ctx context.Context, webhookID primitive.ObjectID) error {
+	_, err := m.collection.DeleteMany(ctx, bson.M{"webhook_id": webhookID})
+	return err
+}
+
+// CreateIndexes creates the indexes used by the dispatchers and for
+// listing the deliveries of a webhook
+func (m *mongoDB) CreateIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "locked_until", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	return err
+}
@@ -0,0 +1,42 @@
+package webhookdelivery
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"GoMail/app/repository/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "webhook_deliveries"
+
+var (
+	ErrInvalidID        = errors.New("invalid ID type")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrLeaseLost        = errors.New("webhook delivery lease is held by another dispatcher")
+)
+
+// WebhookDeliveryRepository is the outbox of webhook events and the log of
+// their delivery attempts
+type WebhookDeliveryRepository interface {
+	Save(ctx context.Context, delivery *models.WebhookDelivery) error
+	FindByID(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	FindAll(ctx context.Context, filter interface{}, page, limit int) ([]*models.WebhookDelivery, int64, error)
+	Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*models.WebhookDelivery, error)
+	Release(ctx context.Context, delivery *models.WebhookDelivery, owner string) error
+	DeleteByWebhook(ctx context.Context, webhookID primitive.ObjectID) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type mongoDB struct {
+	collection *mongo.Collection
+}
+
+func New(database *mongo.Database) WebhookDeliveryRepository {
+	return &mongoDB{
+		collection: database.Collection(CollectionName),
+	}
+}
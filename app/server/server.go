@@ -8,8 +8,36 @@ import (
 
 	"GoMail/app/config"
 	"GoMail/app/handler"
+	"GoMail/app/handler/attachment"
+	"GoMail/app/handler/bounce"
+	"GoMail/app/handler/download"
 	"GoMail/app/handler/email"
+	"GoMail/app/handler/emailtemplate"
+	"GoMail/app/handler/merge"
+	"GoMail/app/handler/recipient"
+	"GoMail/app/handler/schedule"
+	"GoMail/app/handler/suppression"
+	"GoMail/app/handler/topic"
+	"GoMail/app/handler/tracking"
+	"GoMail/app/handler/unsubscribe"
+	"GoMail/app/handler/webhook"
+	attachmentLogic "GoMail/app/logic/attachment"
+	authLogic "GoMail/app/logic/auth"
+	bounceLogic "GoMail/app/logic/bounce"
+	downloadLogic "GoMail/app/logic/download"
 	emailLogic "GoMail/app/logic/email"
+	idempotencyLogic "GoMail/app/logic/idempotency"
+	inboundLogic "GoMail/app/logic/inbound"
+	mergeLogic "GoMail/app/logic/merge"
+	templateLogic "GoMail/app/logic/emailtemplate"
+	recipientLogic "GoMail/app/logic/recipient"
+	scheduleLogic "GoMail/app/logic/schedule"
+	submissionLogic "GoMail/app/logic/submission"
+	suppressionLogic "GoMail/app/logic/suppression"
+	topicLogic "GoMail/app/logic/topic"
+	trackingLogic "GoMail/app/logic/tracking"
+	unsubscribeLogic "GoMail/app/logic/unsubscribe"
+	webhookLogic "GoMail/app/logic/webhook"
 	"GoMail/app/middleware"
 	"GoMail/app/repository"
 
@@ -25,6 +53,16 @@ type Server struct {
 	config     *config.Config
 	db         *mongo.Database
 	repo       repository.Repository
+	workers    *emailLogic.WorkerPool
+	scheduler  *emailLogic.Scheduler
+	runner     *scheduleLogic.Runner
+	sweeper    *attachmentLogic.Sweeper
+	mailbox    *bounceLogic.Mailbox
+	inbound    *inboundLogic.Listener
+	dispatcher *inboundLogic.Dispatcher
+	submission *submissionLogic.Listener
+	webhooks   *webhookLogic.Dispatcher
+	merges     *mergeLogic.Runner
 }
 
 // New creates a new server instance
@@ -51,8 +89,59 @@ func New(cfg *config.Config) *Server {
 	// Initialize email service
 	emailService := emailLogic.NewEmailService(cfg, repo)
 	
-	// Create email handler
-	emailHandler := email.NewHandler(emailService)
+	// Initialize recurring schedule service
+	scheduleService := scheduleLogic.New(repo, emailService, cfg)
+
+	// Initialize stored template service
+	templateService := templateLogic.New(repo, cfg)
+
+	// Initialize recipient preference service
+	recipientService := recipientLogic.New(repo, cfg)
+
+	// Initialize attachment store service
+	attachmentService := attachmentLogic.New(repo, cfg)
+
+	// Initialize download link service
+	downloadService := downloadLogic.New(repo, cfg)
+
+	// Initialize suppression list service
+	suppressionService := suppressionLogic.New(repo, cfg)
+
+	// Initialize bounce and complaint processing
+	bounceService := bounceLogic.New(repo, cfg)
+
+	// Initialize open and click tracking
+	trackingService := trackingLogic.New(repo, cfg)
+
+	// Initialize one-click unsubscribe links
+	unsubscribeService := unsubscribeLogic.New(repo, cfg)
+
+	// Initialize webhook subscriptions to email events
+	webhookService := webhookLogic.New(repo, cfg)
+
+	// Initialize the topics recipients opt out of
+	topicService := topicLogic.New(repo, cfg)
+
+	// Initialize mail merges of uploaded recipient lists
+	mergeService := mergeLogic.New(repo, cfg)
+
+	// Initialize idempotency key service for retried sends
+	idempotencyService := idempotencyLogic.New(repo, cfg)
+
+	// Create handlers
+	emailHandler := email.NewHandler(emailService, cfg)
+	scheduleHandler := schedule.NewHandler(scheduleService)
+	templateHandler := emailtemplate.NewHandler(templateService)
+	recipientHandler := recipient.NewHandler(recipientService)
+	attachmentHandler := attachment.NewHandler(attachmentService, cfg)
+	downloadHandler := download.NewHandler(downloadService)
+	suppressionHandler := suppression.NewHandler(suppressionService)
+	bounceHandler := bounce.NewHandler(bounceService, cfg)
+	trackingHandler := tracking.NewHandler(trackingService)
+	unsubscribeHandler := unsubscribe.NewHandler(unsubscribeService)
+	webhookHandler := webhook.NewHandler(webhookService)
+	topicHandler := topic.NewHandler(topicService)
+	mergeHandler := merge.NewHandler(mergeService, cfg)
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -60,8 +149,8 @@ func New(cfg *config.Config) *Server {
 	})
 
 	// Setup routes with the emailHandler instance
-	handler.InitPublicRoutes(router, emailHandler, repo)
-	handler.InitProtectedRoutes(router, emailHandler)
+	handler.InitPublicRoutes(router, emailHandler, downloadHandler, bounceHandler, trackingHandler, unsubscribeHandler, recipientHandler, repo)
+	handler.InitProtectedRoutes(router, emailHandler, scheduleHandler, templateHandler, recipientHandler, attachmentHandler, downloadHandler, suppressionHandler, trackingHandler, webhookHandler, topicHandler, mergeHandler, idempotencyService, cfg)
 
 	// Initialize token indexes for token revocation support
 	if cfg.JWT.EnableTokenRevoking {
@@ -73,12 +162,146 @@ func New(cfg *config.Config) *Server {
 		}
 	}
 
+	// Initialize email queue indexes used by the workers
+	indexCtx, indexCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer indexCancel()
+	if err := repo.InitEmailIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize email queue indexes: %v", err)
+	}
+	if err := repo.InitScheduleIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize schedule indexes: %v", err)
+	}
+	if err := repo.InitTemplateIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize template indexes: %v", err)
+	}
+	if err := repo.InitRecipientIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize recipient indexes: %v", err)
+	}
+	if err := repo.InitTopicIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize topic indexes: %v", err)
+	}
+	if err := repo.InitAttachmentIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize attachment indexes: %v", err)
+	}
+	if err := repo.InitDownloadLinkIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize download link indexes: %v", err)
+	}
+	if err := repo.InitIdempotencyIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize idempotency key indexes: %v", err)
+	}
+	if err := repo.InitDedupIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize dedup indexes: %v", err)
+	}
+	if err := repo.InitSuppressionIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize suppression indexes: %v", err)
+	}
+	if err := repo.InitEmailLogIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize email log indexes: %v", err)
+	}
+	if err := repo.InitEmailEventIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize email event indexes: %v", err)
+	}
+	if err := repo.InitWebhookIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize webhook indexes: %v", err)
+	}
+	if err := repo.InitWebhookDeliveryIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize webhook delivery indexes: %v", err)
+	}
+	if err := repo.InitMergeJobIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize merge job indexes: %v", err)
+	}
+	if err := repo.InitMergeRowIndexes(indexCtx); err != nil {
+		log.Printf("WARNING: Failed to initialize merge row indexes: %v", err)
+	}
+
+	// Start the queue workers for async sending
+	var workers *emailLogic.WorkerPool
+	var scheduler *emailLogic.Scheduler
+	var runner *scheduleLogic.Runner
+	var merges *mergeLogic.Runner
+	if cfg.Queue.Workers > 0 {
+		workers = emailLogic.NewWorkerPool(cfg, emailService, repo)
+		workers.Start()
+
+		// Release scheduled emails to the workers once they are due
+		scheduler = emailLogic.NewScheduler(cfg, repo)
+		scheduler.Start()
+
+		// Queue the emails of recurring schedules when they fire
+		runner = scheduleLogic.NewRunner(cfg, scheduleService)
+		runner.Start()
+
+		// Queue the recipients of mail merges in batches
+		merges = mergeLogic.NewRunner(cfg, repo, emailService)
+		merges.Start()
+	} else {
+		log.Printf("WARNING: Queue workers disabled; async, scheduled and merge emails will not be delivered")
+	}
+
+	// Remove expired attachments from the store
+	sweeper := attachmentLogic.NewSweeper(attachmentService)
+	sweeper.Start()
+
+	// Process bounces and complaints delivered to the mailbox directory
+	var mailbox *bounceLogic.Mailbox
+	if cfg.Bounce.MailboxDir != "" {
+		mailbox = bounceLogic.NewMailbox(cfg, bounceService)
+		mailbox.Start()
+	}
+
+	// Receive emails over SMTP and post them to the webhooks of their recipients
+	var inbound *inboundLogic.Listener
+	var dispatcher *inboundLogic.Dispatcher
+	if cfg.Inbound.Enabled {
+		if err := repo.InitInboundIndexes(indexCtx); err != nil {
+			log.Printf("WARNING: Failed to initialize inbound message indexes: %v", err)
+		}
+
+		listener, err := inboundLogic.NewListener(cfg, inboundLogic.New(repo, cfg))
+		if err != nil {
+			log.Printf("WARNING: Inbound SMTP listener disabled: %v", err)
+		} else {
+			inbound = listener
+			inbound.Start()
+
+			dispatcher = inboundLogic.NewDispatcher(cfg, repo)
+			dispatcher.Start()
+		}
+	}
+
+	// Accept emails from applications that authenticate over SMTP
+	var submission *submissionLogic.Listener
+	if cfg.Submission.Enabled {
+		submissionService := submissionLogic.New(emailService, authLogic.New(repo, cfg), cfg)
+		listener, err := submissionLogic.NewListener(cfg, submissionService)
+		if err != nil {
+			log.Printf("WARNING: SMTP submission listener disabled: %v", err)
+		} else {
+			submission = listener
+			submission.Start()
+		}
+	}
+
+	// Post email events to the webhooks subscribed to them
+	webhooks := webhookLogic.NewDispatcher(cfg, repo)
+	webhooks.Start()
+
 	// Initialize the server
 	server := &Server{
-		router: router,
-		config: cfg,
-		db:     db,
-		repo:   repo,
+		router:    router,
+		config:    cfg,
+		db:        db,
+		repo:      repo,
+		workers:   workers,
+		scheduler: scheduler,
+		runner:    runner,
+		sweeper:   sweeper,
+		mailbox:   mailbox,
+		inbound:   inbound,
+		dispatcher: dispatcher,
+		submission: submission,
+		webhooks:   webhooks,
+		merges:     merges,
 		httpServer: &http.Server{
 			Addr:         ":" + cfg.Server.Port,
 			Handler:      router,
@@ -116,6 +339,7 @@ func configureCORS(cfg *config.Config) cors.Config {
 			"origin",
 			"Cache-Control",
 			"X-Requested-With",
+			middleware.IdempotencyKeyHeader,
 		)
 	}
 	
@@ -127,6 +351,8 @@ func configureCORS(cfg *config.Config) cors.Config {
 	
 	if len(cfg.Cors.ExposeHeaders) > 0 {
 		corsConfig.ExposeHeaders = cfg.Cors.ExposeHeaders
+	} else {
+		corsConfig.ExposeHeaders = []string{middleware.IdempotentReplayedHeader}
 	}
 	
 	if cfg.Cors.MaxAge > 0 {
@@ -143,5 +369,59 @@ func (s *Server) Run() error {
 
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if s.runner != nil {
+		if err := s.runner.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if s.scheduler != nil {
+		if err := s.scheduler.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if s.sweeper != nil {
+		if err := s.sweeper.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if s.mailbox != nil {
+		if err := s.mailbox.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if s.inbound != nil {
+		if err := s.inbound.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if s.dispatcher != nil {
+		if err := s.dispatcher.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if s.submission != nil {
+		if err := s.submission.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if s.webhooks != nil {
+		if err := s.webhooks.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if s.merges != nil {
+		if err := s.merges.Stop(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Let in-flight deliveries finish before exiting
+	if s.workers != nil {
+		return s.workers.Stop(ctx)
+	}
+	return nil
 }
\ No newline at end of file